@@ -1,11 +1,16 @@
 package main
 
 import (
+	"context"
 	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
 	"flag"
 	"fmt"
 	"github.com/burenotti/rtu-it-lab-recruit/handler"
+	"github.com/burenotti/rtu-it-lab-recruit/model"
 	"github.com/burenotti/rtu-it-lab-recruit/pkg/httpserver"
+	"github.com/burenotti/rtu-it-lab-recruit/pkg/scheduler"
 	"github.com/burenotti/rtu-it-lab-recruit/repositories"
 	"github.com/burenotti/rtu-it-lab-recruit/services"
 	"github.com/burenotti/rtu-it-lab-recruit/usecases"
@@ -16,10 +21,15 @@ import (
 	"github.com/spf13/viper"
 	"gopkg.in/gomail.v2"
 	"html/template"
+	"net/http"
+	"net/url"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
+	// Timezones of digest settings must be resolvable in images without system tzdata.
+	_ "time/tzdata"
 )
 
 type Config struct {
@@ -31,21 +41,79 @@ type Config struct {
 	SmtpPort                    int
 	SmtpUser                    string
 	SmtpPassword                string
-	ActivationEmailTemplatePath string
-	PassCodeEmailTemplatePath   string
+	MailFromAddress             string
+	MailFromName                string
+	MessageTemplatesDir         string
+	PublicURL                   string
+	ActivationPageTemplatePath  string
+	UnsubscribePageTemplatePath string
+	ActivationRedirectURL       string
 	LoginCodeTTL                time.Duration
 	AuthTokenTTL                time.Duration
+	ImpersonationTokenTTL       time.Duration
 	ActivationTokenTTL          time.Duration
+	ActivationResendCooldown    time.Duration
+	ActivationResendLimit       int
+	InactiveUserTTL             time.Duration
+	InactiveUserPurgeInterval   time.Duration
+	DataExportInterval          time.Duration
+	OutboxDispatchInterval      time.Duration
+	OutboxBatchSize             int
+	OutboxLease                 time.Duration
+	OutboxMaxAttempts           int
+	OutboxBaseBackoff           time.Duration
+	OutboxMaxBackoff            time.Duration
+	ReminderInterval            time.Duration
+	FanoutInterval              time.Duration
+	FanoutBatchSize             int
+	FanoutBatchesPerRun         int
+	DigestInterval              time.Duration
+	SearchAlertInterval         time.Duration
+	NotificationListenRetry     time.Duration
+	WebhookInterval             time.Duration
+	WebhookBatchSize            int
+	WebhookLease                time.Duration
+	WebhookMaxAttempts          int
+	WebhookBaseBackoff          time.Duration
+	WebhookMaxBackoff           time.Duration
+	WebhookDisableAfter         int
+	WebhookTimeout              time.Duration
+	RecurrenceHorizon           time.Duration
+	RecurrenceInterval          time.Duration
+	EventImportInterval         time.Duration
+	OrderReservationTTL         time.Duration
+	OrderExpiryInterval         time.Duration
+	AnalyticsRefreshInterval    time.Duration
+	ReminderOffsets             []time.Duration
+	AdminEmails                 []string
+	TelegramBotToken            string
+	TelegramBotName             string
+	TelegramAPIURL              string
+	TelegramWebhookSecret       string
+	TelegramLinkTTL             time.Duration
+	PhoneCodeTTL                time.Duration
+	PhoneCodeAttempts           int
+	PhoneCodeCooldown           time.Duration
+	PhoneCodeLimit              int
+	SMSGatewayURL               string
+	SMSGatewayAPIKey            string
+	SMSSender                   string
+	PaymentProvider             string
 	PrivateKey                  *rsa.PrivateKey
+	UnsubscribeSecret           []byte
 }
 
 func (c *Config) Addr() string {
 	return fmt.Sprintf("%s:%s", c.Host, c.Port)
 }
 
-func (c *Config) HandlerConfig() *handler.Config {
+func (c *Config) HandlerConfig(activationPage, unsubscribePage *template.Template) *handler.Config {
 	return &handler.Config{
-		Name: c.AppName,
+		Name:                  c.AppName,
+		ActivationRedirectURL: c.ActivationRedirectURL,
+		ActivationPage:        activationPage,
+		UnsubscribePage:       unsubscribePage,
+		TelegramWebhookSecret: c.TelegramWebhookSecret,
 	}
 }
 
@@ -53,8 +121,54 @@ func ParseConfig() *Config {
 	viper.AutomaticEnv()
 	viper.SetDefault("LOGIN_CODE_TTL", 2*time.Minute)
 	viper.SetDefault("AUTH_TOKEN_TTL", 24*time.Hour)
+	viper.SetDefault("IMPERSONATION_TOKEN_TTL", 15*time.Minute)
 	viper.SetDefault("ACTIVATION_TOKEN_TTL", 10*time.Minute)
+	viper.SetDefault("ACTIVATION_RESEND_COOLDOWN", time.Minute)
+	viper.SetDefault("ACTIVATION_RESEND_LIMIT", 5)
+	viper.SetDefault("INACTIVE_USER_TTL", 7*24*time.Hour)
+	viper.SetDefault("INACTIVE_USER_PURGE_INTERVAL", time.Hour)
+	viper.SetDefault("DATA_EXPORT_INTERVAL", 30*time.Second)
+	viper.SetDefault("OUTBOX_DISPATCH_INTERVAL", 5*time.Second)
+	viper.SetDefault("OUTBOX_BATCH_SIZE", 20)
+	viper.SetDefault("OUTBOX_LEASE", time.Minute)
+	viper.SetDefault("OUTBOX_MAX_ATTEMPTS", 8)
+	viper.SetDefault("OUTBOX_BASE_BACKOFF", 30*time.Second)
+	viper.SetDefault("OUTBOX_MAX_BACKOFF", time.Hour)
+	viper.SetDefault("REMINDER_INTERVAL", time.Minute)
+	viper.SetDefault("REMINDER_OFFSETS", "24h,1h")
+	viper.SetDefault("FANOUT_INTERVAL", time.Minute)
+	viper.SetDefault("FANOUT_BATCH_SIZE", 100)
+	viper.SetDefault("FANOUT_BATCHES_PER_RUN", 10)
+	viper.SetDefault("DIGEST_INTERVAL", 5*time.Minute)
+	viper.SetDefault("SEARCH_ALERT_INTERVAL", time.Minute)
+	viper.SetDefault("NOTIFICATION_LISTEN_RETRY", 5*time.Second)
+	viper.SetDefault("WEBHOOK_INTERVAL", 5*time.Second)
+	viper.SetDefault("WEBHOOK_BATCH_SIZE", 20)
+	viper.SetDefault("WEBHOOK_LEASE", time.Minute)
+	viper.SetDefault("WEBHOOK_MAX_ATTEMPTS", 8)
+	viper.SetDefault("WEBHOOK_BASE_BACKOFF", 30*time.Second)
+	viper.SetDefault("WEBHOOK_MAX_BACKOFF", time.Hour)
+	viper.SetDefault("WEBHOOK_DISABLE_AFTER", 20)
+	viper.SetDefault("WEBHOOK_TIMEOUT", 10*time.Second)
+	viper.SetDefault("RECURRENCE_HORIZON", 365*24*time.Hour)
+	viper.SetDefault("RECURRENCE_INTERVAL", time.Hour)
+	viper.SetDefault("EVENT_IMPORT_INTERVAL", 10*time.Second)
+	viper.SetDefault("ORDER_RESERVATION_TTL", 15*time.Minute)
+	viper.SetDefault("ORDER_EXPIRY_INTERVAL", time.Minute)
+	viper.SetDefault("ANALYTICS_REFRESH_INTERVAL", 5*time.Minute)
 	viper.SetDefault("PRIVATE_KEY_PATH", "private.pem")
+	viper.SetDefault("ACTIVATION_PAGE_TEMPLATE", "templates/activation_page.html")
+	viper.SetDefault("UNSUBSCRIBE_PAGE_TEMPLATE", "templates/unsubscribe_page.html")
+	viper.SetDefault("MESSAGE_TEMPLATES_DIR", "templates/messages")
+	viper.SetDefault("MAIL_FROM_ADDRESS", "burenotti@gmail.com")
+	viper.SetDefault("MAIL_FROM_NAME", "Contact")
+	viper.SetDefault("PUBLIC_URL", "http://localhost:8000")
+	viper.SetDefault("TELEGRAM_API_URL", services.DefaultTelegramAPIURL)
+	viper.SetDefault("TELEGRAM_LINK_TTL", 10*time.Minute)
+	viper.SetDefault("PHONE_CODE_TTL", 10*time.Minute)
+	viper.SetDefault("PHONE_CODE_ATTEMPTS", 5)
+	viper.SetDefault("PHONE_CODE_COOLDOWN", time.Minute)
+	viper.SetDefault("PHONE_CODE_LIMIT", 5)
 
 	privateKey := ReadPrivateKeyFromFile(viper.GetString("PRIVATE_KEY_PATH"))
 
@@ -63,14 +177,68 @@ func ParseConfig() *Config {
 		DbDsn:                       viper.GetString("DB_DSN"),
 		LoginCodeTTL:                viper.GetDuration("LOGIN_CODE_TTL"),
 		AuthTokenTTL:                viper.GetDuration("AUTH_TOKEN_TTL"),
+		ImpersonationTokenTTL:       viper.GetDuration("IMPERSONATION_TOKEN_TTL"),
 		ActivationTokenTTL:          viper.GetDuration("ACTIVATION_TOKEN_TTL"),
+		ActivationResendCooldown:    viper.GetDuration("ACTIVATION_RESEND_COOLDOWN"),
+		ActivationResendLimit:       viper.GetInt("ACTIVATION_RESEND_LIMIT"),
+		InactiveUserTTL:             viper.GetDuration("INACTIVE_USER_TTL"),
+		InactiveUserPurgeInterval:   viper.GetDuration("INACTIVE_USER_PURGE_INTERVAL"),
+		DataExportInterval:          viper.GetDuration("DATA_EXPORT_INTERVAL"),
+		OutboxDispatchInterval:      viper.GetDuration("OUTBOX_DISPATCH_INTERVAL"),
+		OutboxBatchSize:             viper.GetInt("OUTBOX_BATCH_SIZE"),
+		OutboxLease:                 viper.GetDuration("OUTBOX_LEASE"),
+		OutboxMaxAttempts:           viper.GetInt("OUTBOX_MAX_ATTEMPTS"),
+		OutboxBaseBackoff:           viper.GetDuration("OUTBOX_BASE_BACKOFF"),
+		OutboxMaxBackoff:            viper.GetDuration("OUTBOX_MAX_BACKOFF"),
+		ReminderInterval:            viper.GetDuration("REMINDER_INTERVAL"),
+		ReminderOffsets:             parseDurations(viper.GetString("REMINDER_OFFSETS")),
+		FanoutInterval:              viper.GetDuration("FANOUT_INTERVAL"),
+		FanoutBatchSize:             viper.GetInt("FANOUT_BATCH_SIZE"),
+		FanoutBatchesPerRun:         viper.GetInt("FANOUT_BATCHES_PER_RUN"),
+		DigestInterval:              viper.GetDuration("DIGEST_INTERVAL"),
+		SearchAlertInterval:         viper.GetDuration("SEARCH_ALERT_INTERVAL"),
+		NotificationListenRetry:     viper.GetDuration("NOTIFICATION_LISTEN_RETRY"),
+		WebhookInterval:             viper.GetDuration("WEBHOOK_INTERVAL"),
+		WebhookBatchSize:            viper.GetInt("WEBHOOK_BATCH_SIZE"),
+		WebhookLease:                viper.GetDuration("WEBHOOK_LEASE"),
+		WebhookMaxAttempts:          viper.GetInt("WEBHOOK_MAX_ATTEMPTS"),
+		WebhookBaseBackoff:          viper.GetDuration("WEBHOOK_BASE_BACKOFF"),
+		WebhookMaxBackoff:           viper.GetDuration("WEBHOOK_MAX_BACKOFF"),
+		WebhookDisableAfter:         viper.GetInt("WEBHOOK_DISABLE_AFTER"),
+		WebhookTimeout:              viper.GetDuration("WEBHOOK_TIMEOUT"),
+		RecurrenceHorizon:           viper.GetDuration("RECURRENCE_HORIZON"),
+		RecurrenceInterval:          viper.GetDuration("RECURRENCE_INTERVAL"),
+		EventImportInterval:         viper.GetDuration("EVENT_IMPORT_INTERVAL"),
+		OrderReservationTTL:         viper.GetDuration("ORDER_RESERVATION_TTL"),
+		OrderExpiryInterval:         viper.GetDuration("ORDER_EXPIRY_INTERVAL"),
+		AnalyticsRefreshInterval:    viper.GetDuration("ANALYTICS_REFRESH_INTERVAL"),
+		AdminEmails:                 parseList(viper.GetString("ADMIN_EMAILS")),
+		TelegramBotToken:            viper.GetString("TELEGRAM_BOT_TOKEN"),
+		TelegramBotName:             viper.GetString("TELEGRAM_BOT_NAME"),
+		TelegramAPIURL:              viper.GetString("TELEGRAM_API_URL"),
+		TelegramWebhookSecret:       viper.GetString("TELEGRAM_WEBHOOK_SECRET"),
+		TelegramLinkTTL:             viper.GetDuration("TELEGRAM_LINK_TTL"),
+		PhoneCodeTTL:                viper.GetDuration("PHONE_CODE_TTL"),
+		PhoneCodeAttempts:           viper.GetInt("PHONE_CODE_ATTEMPTS"),
+		PhoneCodeCooldown:           viper.GetDuration("PHONE_CODE_COOLDOWN"),
+		PhoneCodeLimit:              viper.GetInt("PHONE_CODE_LIMIT"),
+		SMSGatewayURL:               viper.GetString("SMS_GATEWAY_URL"),
+		SMSGatewayAPIKey:            viper.GetString("SMS_GATEWAY_API_KEY"),
+		SMSSender:                   viper.GetString("SMS_SENDER"),
+		PaymentProvider:             viper.GetString("PAYMENT_PROVIDER"),
 		SmtpHost:                    viper.GetString("SMTP_HOST"),
 		SmtpUser:                    viper.GetString("SMTP_USER"),
 		SmtpPort:                    viper.GetInt("SMTP_PORT"),
 		SmtpPassword:                viper.GetString("SMTP_PASSWORD"),
-		ActivationEmailTemplatePath: viper.GetString("ACTIVATION_EMAIL_TEMPLATE"),
-		PassCodeEmailTemplatePath:   viper.GetString("PASS_CODE_EMAIL_TEMPLATE"),
+		MailFromAddress:             viper.GetString("MAIL_FROM_ADDRESS"),
+		MailFromName:                viper.GetString("MAIL_FROM_NAME"),
+		MessageTemplatesDir:         viper.GetString("MESSAGE_TEMPLATES_DIR"),
+		PublicURL:                   viper.GetString("PUBLIC_URL"),
+		ActivationPageTemplatePath:  viper.GetString("ACTIVATION_PAGE_TEMPLATE"),
+		UnsubscribePageTemplatePath: viper.GetString("UNSUBSCRIBE_PAGE_TEMPLATE"),
+		ActivationRedirectURL:       viper.GetString("ACTIVATION_REDIRECT_URL"),
 		PrivateKey:                  privateKey,
+		UnsubscribeSecret:           unsubscribeSecret(viper.GetString("UNSUBSCRIBE_SECRET"), privateKey),
 	}
 	flag.StringVar(&cfg.Host, "host", "0.0.0.0", "Server host")
 	flag.StringVar(&cfg.Port, "port", "80", "Server port")
@@ -79,6 +247,85 @@ func ParseConfig() *Config {
 	return &cfg
 }
 
+func parseList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func parseDurations(s string) []time.Duration {
+	var durations []time.Duration
+	for _, item := range parseList(s) {
+		d, err := time.ParseDuration(item)
+		if err != nil {
+			logrus.WithError(err).Fatalf("can't parse duration %q", item)
+		}
+		durations = append(durations, d)
+	}
+	return durations
+}
+
+// getChannels returns deliveries of configured channels. Email is always available,
+// Telegram and SMS are enabled by their credentials.
+func getChannels(cfg *Config, mailing *services.MailingService) services.ChannelRouter {
+	channels := services.ChannelRouter{
+		model.ChannelEmail: mailing,
+	}
+	if cfg.TelegramBotToken != "" {
+		channels[model.ChannelTelegram] = &services.TelegramDelivery{
+			Client: &services.TelegramClient{
+				BaseURL:    cfg.TelegramAPIURL,
+				Token:      cfg.TelegramBotToken,
+				HTTPClient: &http.Client{Timeout: 10 * time.Second},
+			},
+		}
+	}
+	if cfg.SMSGatewayURL != "" {
+		channels[model.ChannelSMS] = &services.SMSDelivery{
+			Client: &services.SMSClient{
+				URL:        cfg.SMSGatewayURL,
+				APIKey:     cfg.SMSGatewayAPIKey,
+				Sender:     cfg.SMSSender,
+				HTTPClient: &http.Client{Timeout: 10 * time.Second},
+			},
+		}
+	}
+	return channels
+}
+
+// getPayments returns the payment provider chosen by PAYMENT_PROVIDER. Priced tickets are not sold
+// until it is set. The fake provider charges nobody, it must be chosen explicitly for development and tests.
+func getPayments(cfg *Config, logger *logrus.Logger) usecases.PaymentProvider {
+	switch cfg.PaymentProvider {
+	case "":
+		logger.Warn("Payment provider is not configured, priced tickets are not sold")
+		return nil
+	case "fake":
+		logger.Warn("Payments are not charged, fake payment provider is used")
+		return &services.FakePaymentProvider{
+			BaseURL:     strings.TrimSuffix(cfg.PublicURL, "/") + "/payments",
+			AutoConfirm: true,
+		}
+	default:
+		logger.Fatalf("unknown payment provider: %s", cfg.PaymentProvider)
+		return nil
+	}
+}
+
+// unsubscribeSecret returns the key unsubscribe links are signed with. If it is not configured, it is derived
+// from the private key, so links stay valid across restarts and replicas without extra configuration.
+func unsubscribeSecret(secret string, privateKey *rsa.PrivateKey) []byte {
+	if secret != "" {
+		return []byte(secret)
+	}
+	sum := sha256.Sum256(append([]byte("unsubscribe:"), x509.MarshalPKCS1PrivateKey(privateKey)...))
+	return sum[:]
+}
+
 func getLogger() *logrus.Logger {
 	logger := logrus.Logger{
 		Out: os.Stdout,
@@ -131,31 +378,56 @@ func main() {
 	mailingService := &services.MailingService{
 		Dialer: dialer,
 		Cfg: services.MailingConfig{
-			FromAddress: "burenotti@gmail.com",
-			FromName:    "Contact",
-			Subject:     "Не отвечайте на это сообщение",
-			ContentType: "text/plain",
+			FromAddress: cfg.MailFromAddress,
+			FromName:    cfg.MailFromName,
 		},
 	}
 
-	activationEmailTemplate, err := template.ParseFiles(cfg.ActivationEmailTemplatePath)
+	outboxRepo := repositories.NewOutboxRepository(db)
+	outboxDelivery := &services.OutboxDelivery{Queue: outboxRepo}
+
+	messageTemplates, err := services.LoadMessageTemplates(
+		cfg.MessageTemplatesDir,
+		model.MessageKinds,
+		model.SupportedLocales,
+		model.DefaultLocale,
+	)
 	if err != nil {
-		logger.WithError(err).Fatalf("can't parse activation email template")
+		logger.WithError(err).Fatalf("can't parse message templates: %v", err)
 	}
 
-	activationTokenDelivery := &services.ActivationTokenDelivery{
-		MailTemplate: activationEmailTemplate,
-		Delivery:     mailingService,
+	channels := getChannels(cfg, mailingService)
+	notificationRepo := repositories.NewNotificationRepository(db)
+	// In-app notifications are stored right away, messages to other channels are sent through the outbox.
+	notifierDelivery := services.ChannelRouter{
+		model.ChannelInApp: &services.InAppDelivery{Store: notificationRepo},
+	}
+	for _, channel := range channels.Channels() {
+		notifierDelivery[channel] = outboxDelivery
 	}
+	userChannelRepo := repositories.NewUserChannelRepository(db)
+	preferenceRepo := repositories.NewNotificationPreferenceRepository(db)
+	unsubscribeLinks := &services.UnsubscribeLinks{
+		Secret:  cfg.UnsubscribeSecret,
+		BaseURL: strings.TrimSuffix(cfg.PublicURL, "/") + "/unsubscribe",
+	}
+	notifier := &services.Notifier{
+		Templates:   messageTemplates,
+		Channels:    userChannelRepo,
+		Preferences: preferenceRepo,
+		Unsubscribe: unsubscribeLinks,
+		Delivery:    notifierDelivery,
+		Configured:  notifierDelivery.Channels(),
+	}
+	notificationHub := services.NewNotificationHub()
 
-	passCodeEmailTemplate, err := template.ParseFiles(cfg.PassCodeEmailTemplatePath)
-	if err != nil {
-		logger.WithError(err).Fatalf("can't parse pass code email template")
+	activationTokenDelivery := &services.ActivationTokenDelivery{
+		Notifier:      notifier,
+		ActivationURL: strings.TrimSuffix(cfg.PublicURL, "/") + "/auth/activate",
 	}
 
 	passCodeDelivery := &services.PassCodeDelivery{
-		MailTemplate: passCodeEmailTemplate,
-		Delivery:     mailingService,
+		Notifier: notifier,
 	}
 
 	auth := &services.AuthService{
@@ -164,11 +436,54 @@ func main() {
 	}
 
 	activationRepo := &repositories.UserActivationRepository{
+		Db:         db,
 		PrivateKey: cfg.PrivateKey,
 		TokenTTL:   cfg.ActivationTokenTTL,
 	}
 
+	activationRequestRepo := repositories.NewActivationRequestRepository(db)
+
+	authService := &services.AuthService{
+		TokenTTL:         cfg.AuthTokenTTL,
+		ImpersonationTTL: cfg.ImpersonationTokenTTL,
+		PrivateKey:       cfg.PrivateKey,
+	}
+
 	orgRepo := repositories.NewOrganizationRepository(db)
+	eventRepo := repositories.NewEventRepository(db)
+	dataExportRepo := repositories.NewDataExportRepository(db)
+	auditLogRepo := repositories.NewAuditLogRepository(db)
+
+	registrationRepo := repositories.NewRegistrationRepository(db)
+	occurrenceRepo := repositories.NewOccurrenceRepository(db)
+	reminders := &usecases.ReminderUseCase{
+		Transactioner: db,
+		Reminders:     repositories.NewReminderRepository(db),
+		Registrations: registrationRepo,
+		Events:        eventRepo,
+		Occurrences:   occurrenceRepo,
+		Notifier:      notifier,
+		Logger:        logger,
+		Offsets:       cfg.ReminderOffsets,
+	}
+
+	followRepo := repositories.NewFollowRepository(db)
+	digestRepo := repositories.NewDigestRepository(db)
+	searchRepo := repositories.NewSavedSearchRepository(db)
+	webhookRepo := repositories.NewWebhookRepository(db)
+	venueRepo := repositories.NewVenueRepository(db)
+	categoryRepo := repositories.NewCategoryRepository(db)
+	tagRepo := repositories.NewTagRepository(db)
+	calendarFeedRepo := repositories.NewCalendarFeedRepository(db)
+	eventImportRepo := repositories.NewEventImportRepository(db)
+	orderRepo := repositories.NewOrderRepository(db)
+	publicURL, err := url.Parse(cfg.PublicURL)
+	if err != nil {
+		logger.WithError(err).Fatalf("invalid public url: %v", err)
+	}
+	webhookEmitter := &services.WebhookEmitter{Queue: webhookRepo}
+	ticketRepo := repositories.NewTicketRepository(db)
+	payments := getPayments(cfg, logger)
 
 	ucase := handler.UseCases{
 		EmailSignInUseCase: usecases.EmailSignInUseCase{
@@ -179,25 +494,328 @@ func main() {
 			Auth:           auth,
 		},
 		SignUpUseCase: usecases.SignUpUseCase{
-			UserRepo:       userStore,
-			ActivationRepo: activationRepo,
-			Delivery:       activationTokenDelivery,
-			Logger:         logger,
+			UserRepo:        userStore,
+			ActivationRepo:  activationRepo,
+			RequestRepo:     activationRequestRepo,
+			Delivery:        activationTokenDelivery,
+			Transactioner:   db,
+			Logger:          logger,
+			ResendCooldown:  cfg.ActivationResendCooldown,
+			ResendLimit:     cfg.ActivationResendLimit,
+			InactiveUserTTL: cfg.InactiveUserTTL,
 		},
 		OrganizationUseCase: usecases.OrganizationUseCase{
 			Transactioner:       db,
 			OrganizationStorage: orgRepo,
 		},
-		AuthService: services.AuthService{
-			TokenTTL:   cfg.AuthTokenTTL,
-			PrivateKey: cfg.PrivateKey,
+		PersonalDataUseCase: usecases.PersonalDataUseCase{
+			Transactioner: db,
+			Users:         userStore,
+			Exports:       dataExportRepo,
+			Memberships:   orgRepo,
+			LoginHistory:  loginCodeStore,
+			Events:        eventRepo,
+			Registrations: registrationRepo,
+			Follows:       followRepo,
+			Digests:       digestRepo,
+			Searches:      searchRepo,
+			Feeds:         calendarFeedRepo,
+			Imports:       eventImportRepo,
+			Orders:        orderRepo,
+			Logger:        logger,
+		},
+		AdminUseCase: usecases.AdminUseCase{
+			Transactioner: db,
+			Users:         userStore,
+			Organizations: orgRepo,
+			Events:        eventRepo,
+			Audit:         auditLogRepo,
+			Tokens:        authService,
+			Webhooks:      webhookEmitter,
+			Logger:        logger,
+		},
+		OutboxUseCase: usecases.OutboxUseCase{
+			Transactioner: db,
+			Outbox:        outboxRepo,
+			Sender:        channels,
+			Audit:         auditLogRepo,
+			Logger:        logger,
+			BatchSize:     cfg.OutboxBatchSize,
+			Lease:         cfg.OutboxLease,
+			MaxAttempts:   cfg.OutboxMaxAttempts,
+			BaseBackoff:   cfg.OutboxBaseBackoff,
+			MaxBackoff:    cfg.OutboxMaxBackoff,
 		},
+		ChannelsUseCase: usecases.ChannelsUseCase{
+			Transactioner:     db,
+			Channels:          userChannelRepo,
+			Users:             userStore,
+			Notifier:          notifier,
+			Logger:            logger,
+			Configured:        channels.Channels(),
+			TelegramBotName:   cfg.TelegramBotName,
+			TelegramLinkTTL:   cfg.TelegramLinkTTL,
+			PhoneCodeTTL:      cfg.PhoneCodeTTL,
+			PhoneCodeAttempts: cfg.PhoneCodeAttempts,
+			PhoneCodeCooldown: cfg.PhoneCodeCooldown,
+			PhoneCodeLimit:    cfg.PhoneCodeLimit,
+		},
+		EventUseCase: usecases.EventUseCase{
+			Transactioner:     db,
+			Events:            eventRepo,
+			Members:           orgRepo,
+			Venues:            venueRepo,
+			Tags:              tagRepo,
+			Occurrences:       occurrenceRepo,
+			Registrations:     registrationRepo,
+			Users:             userStore,
+			Reminders:         reminders,
+			Notifier:          notifier,
+			Fanouts:           followRepo,
+			SearchAlerts:      searchRepo,
+			Webhooks:          webhookEmitter,
+			Imports:           eventImportRepo,
+			Tickets:           ticketRepo,
+			Logger:            logger,
+			RecurrenceHorizon: cfg.RecurrenceHorizon,
+		},
+		FollowUseCase: usecases.FollowUseCase{
+			Transactioner: db,
+			Follows:       followRepo,
+			Events:        eventRepo,
+			Notifier:      notifier,
+			Logger:        logger,
+			BatchSize:     cfg.FanoutBatchSize,
+			BatchesPerRun: cfg.FanoutBatchesPerRun,
+		},
+		DigestUseCase: usecases.DigestUseCase{
+			Transactioner: db,
+			Digests:       digestRepo,
+			Users:         userStore,
+			Events:        eventRepo,
+			Searches:      searchRepo,
+			Notifier:      notifier,
+			Logger:        logger,
+		},
+		SearchUseCase: usecases.SearchUseCase{
+			Transactioner:  db,
+			Searches:       searchRepo,
+			Alerts:         searchRepo,
+			Events:         eventRepo,
+			Users:          userStore,
+			Notifier:       notifier,
+			Logger:         logger,
+			UnsubscribeURL: strings.TrimSuffix(cfg.PublicURL, "/") + "/unsubscribe/search",
+			BatchSize:      cfg.FanoutBatchSize,
+			BatchesPerRun:  cfg.FanoutBatchesPerRun,
+		},
+		NotificationUseCase: usecases.NotificationUseCase{
+			Notifications: notificationRepo,
+			Subscriber:    notificationHub,
+		},
+		PreferencesUseCase: usecases.PreferencesUseCase{
+			Transactioner: db,
+			Preferences:   preferenceRepo,
+			Channels:      userChannelRepo,
+			Users:         userStore,
+			Tokens:        unsubscribeLinks,
+		},
+		WebhookUseCase: usecases.WebhookUseCase{
+			Transactioner: db,
+			Webhooks:      webhookRepo,
+			Deliveries:    webhookRepo,
+			Members:       orgRepo,
+			Sender: &services.WebhookClient{
+				HTTPClient: services.NewWebhookHTTPClient(cfg.WebhookTimeout),
+			},
+			Logger:       logger,
+			BatchSize:    cfg.WebhookBatchSize,
+			Lease:        cfg.WebhookLease,
+			MaxAttempts:  cfg.WebhookMaxAttempts,
+			BaseBackoff:  cfg.WebhookBaseBackoff,
+			MaxBackoff:   cfg.WebhookMaxBackoff,
+			DisableAfter: cfg.WebhookDisableAfter,
+		},
+		VenueUseCase: usecases.VenueUseCase{
+			Transactioner: db,
+			Venues:        venueRepo,
+			Members:       orgRepo,
+		},
+		CategoryUseCase: usecases.CategoryUseCase{
+			Transactioner: db,
+			Categories:    categoryRepo,
+			Tags:          tagRepo,
+			Audit:         auditLogRepo,
+		},
+		CalendarUseCase: usecases.CalendarUseCase{
+			Transactioner: db,
+			Feeds:         calendarFeedRepo,
+			Events:        eventRepo,
+			Occurrences:   occurrenceRepo,
+			Searches:      searchRepo,
+			Organizations: orgRepo,
+			Venues:        venueRepo,
+			Tags:          tagRepo,
+			FeedURL:       strings.TrimSuffix(cfg.PublicURL, "/") + "/feeds",
+			Domain:        publicURL.Hostname(),
+			ProdID:        "-//" + cfg.AppName + "//Events//EN",
+		},
+		TicketUseCase: usecases.TicketUseCase{
+			Transactioner:  db,
+			Tickets:        ticketRepo,
+			Orders:         orderRepo,
+			Events:         eventRepo,
+			Members:        orgRepo,
+			Registrations:  registrationRepo,
+			Users:          userStore,
+			Notifier:       notifier,
+			Webhooks:       webhookEmitter,
+			Payments:       payments,
+			Logger:         logger,
+			ReservationTTL: cfg.OrderReservationTTL,
+			ReturnURL:      strings.TrimSuffix(cfg.PublicURL, "/") + "/orders",
+		},
+		CheckInUseCase: usecases.CheckInUseCase{
+			CheckIns:    repositories.NewCheckInRepository(db),
+			Events:      eventRepo,
+			Occurrences: occurrenceRepo,
+			Members:     orgRepo,
+			Signer:      &services.TicketCodes{PrivateKey: cfg.PrivateKey},
+		},
+		AnalyticsUseCase: usecases.AnalyticsUseCase{
+			Analytics: repositories.NewAnalyticsRepository(db),
+			Events:    eventRepo,
+			Members:   orgRepo,
+		},
+		AuthService: *authService,
+	}
+	if len(cfg.AdminEmails) > 0 {
+		if err := ucase.AdminUseCase.PromoteAdmins(context.Background(), cfg.AdminEmails); err != nil {
+			logger.WithError(err).Fatalf("can't promote administrators: %v", err)
+		}
 	}
-	http := handler.New(logger, ucase, cfg.HandlerConfig())
+
+	purgeInactiveUsers := scheduler.New(
+		"purge_inactive_users",
+		cfg.InactiveUserPurgeInterval,
+		ucase.SignUpUseCase.PurgeInactiveUsers,
+		logger,
+	)
+	defer purgeInactiveUsers.Shutdown()
+
+	processDataExports := scheduler.New(
+		"process_data_exports",
+		cfg.DataExportInterval,
+		ucase.PersonalDataUseCase.ProcessPendingExports,
+		logger,
+	)
+	defer processDataExports.Shutdown()
+
+	dispatchOutbox := scheduler.New(
+		"dispatch_message_outbox",
+		cfg.OutboxDispatchInterval,
+		ucase.OutboxUseCase.DispatchPending,
+		logger,
+	)
+	defer dispatchOutbox.Shutdown()
+
+	sendReminders := scheduler.New(
+		"send_event_reminders",
+		cfg.ReminderInterval,
+		reminders.DispatchReminders,
+		logger,
+	)
+	defer sendReminders.Shutdown()
+
+	notifyFollowers := scheduler.New(
+		"notify_followers",
+		cfg.FanoutInterval,
+		ucase.FollowUseCase.DispatchFanouts,
+		logger,
+	)
+	defer notifyFollowers.Shutdown()
+
+	sendDigests := scheduler.New(
+		"send_weekly_digests",
+		cfg.DigestInterval,
+		ucase.DigestUseCase.DispatchDigests,
+		logger,
+	)
+	defer sendDigests.Shutdown()
+
+	sendSearchAlerts := scheduler.New(
+		"send_search_alerts",
+		cfg.SearchAlertInterval,
+		ucase.SearchUseCase.DispatchSearchAlerts,
+		logger,
+	)
+	defer sendSearchAlerts.Shutdown()
+
+	dispatchWebhooks := scheduler.New(
+		"dispatch_webhooks",
+		cfg.WebhookInterval,
+		ucase.WebhookUseCase.DispatchWebhooks,
+		logger,
+	)
+	defer dispatchWebhooks.Shutdown()
+
+	extendOccurrences := scheduler.New(
+		"extend_occurrences",
+		cfg.RecurrenceInterval,
+		ucase.EventUseCase.ExtendOccurrences,
+		logger,
+	)
+	defer extendOccurrences.Shutdown()
+
+	processEventImports := scheduler.New(
+		"process_event_imports",
+		cfg.EventImportInterval,
+		ucase.EventUseCase.ProcessEventImports,
+		logger,
+	)
+	defer processEventImports.Shutdown()
+
+	expireOrders := scheduler.New(
+		"expire_orders",
+		cfg.OrderExpiryInterval,
+		ucase.TicketUseCase.ExpireOrders,
+		logger,
+	)
+	defer expireOrders.Shutdown()
+
+	refreshAnalytics := scheduler.New(
+		"refresh_analytics",
+		cfg.AnalyticsRefreshInterval,
+		ucase.AnalyticsUseCase.RefreshAnalytics,
+		logger,
+	)
+	defer refreshAnalytics.Shutdown()
+
+	notificationListener := &repositories.NotificationListener{DSN: cfg.DbDsn}
+	listenNotifications := scheduler.New(
+		"listen_notifications",
+		cfg.NotificationListenRetry,
+		func(ctx context.Context) error {
+			return notificationListener.Listen(ctx, notificationHub.Notify)
+		},
+		logger,
+	)
+	defer listenNotifications.Shutdown()
+
+	activationPageTemplate, err := template.ParseFiles(cfg.ActivationPageTemplatePath)
+	if err != nil {
+		logger.WithError(err).Fatalf("can't parse activation page template")
+	}
+	unsubscribePageTemplate, err := template.ParseFiles(cfg.UnsubscribePageTemplatePath)
+	if err != nil {
+		logger.WithError(err).Fatalf("can't parse unsubscribe page template")
+	}
+
+	http := handler.New(logger, ucase, cfg.HandlerConfig(activationPageTemplate, unsubscribePageTemplate))
 	logger.Infof("Server run on %s", cfg.Addr())
 	srv := httpserver.New(cfg.Addr(), http.Handler(), logger)
 
-	interrupt := make(chan os.Signal)
+	interrupt := make(chan os.Signal, 1)
 	signal.Notify(interrupt, os.Interrupt, syscall.SIGTERM)
 
 	select {
@@ -209,6 +827,8 @@ func main() {
 		}
 	case s := <-interrupt:
 		logger.WithField("signal", s.String()).Infof("%s Signal caught. Shutdown", s.String())
+		// Streams are open until they are closed, so they are finished before waiting for connections.
+		notificationHub.Close()
 		if err := srv.Shutdown(); err != nil {
 			logger.WithError(err).Errorf("Server shutdowned with error: %v", err)
 		}
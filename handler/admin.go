@@ -0,0 +1,346 @@
+package handler
+
+import (
+	"github.com/burenotti/rtu-it-lab-recruit/handler/middlewares/auth"
+	"github.com/burenotti/rtu-it-lab-recruit/model"
+	"github.com/gofiber/fiber/v2"
+)
+
+// SearchUsers
+//
+//	@Summary		Searches users by name or email
+//	@Description	Available only for platform administrators.
+//	@Security		APIKey
+//	@Produce		json
+//	@Tags			Admin
+//	@Param			query	query		model.UserSearch	false	"Search parameters"
+//	@Success		200		{array}		model.User
+//	@Failure		403		{object}	HTTPError
+//	@Failure		422		{object}	ValidationError
+//	@Failure		500		{object}	HTTPError
+//	@Router			/admin/users [get]
+func (h *HTTPHandler) SearchUsers(ctx *fiber.Ctx) error {
+	search, jerr := QueryParseAndValidate[model.UserSearch](ctx, validate)
+	if jerr != nil {
+		return jerr.AsFiberError(422)
+	}
+
+	users, err := h.ucase.AdminUseCase.SearchUsers(ctx.Context(), search)
+	if err != nil {
+		return WrapError(err)
+	}
+	return ReturnJson(ctx, users)
+}
+
+// AdminActivateUser
+//
+//	@Summary	Activates user account
+//	@Security	APIKey
+//	@Produce	json
+//	@Tags		Admin
+//	@Param		user_id	path		int	true	"User id"
+//	@Success	200		{object}	model.User
+//	@Failure	400		{object}	HTTPError
+//	@Failure	403		{object}	HTTPError
+//	@Failure	500		{object}	HTTPError
+//	@Router		/admin/users/{user_id}/activate [post]
+func (h *HTTPHandler) AdminActivateUser(ctx *fiber.Ctx) error {
+	admin, _ := auth.GetAuth(ctx)
+	userId, err := getIdParam(ctx, "user_id")
+	if err != nil {
+		return err
+	}
+
+	user, err := h.ucase.AdminUseCase.ActivateUser(ctx.Context(), admin.UserID, userId)
+	if err != nil {
+		return WrapError(err)
+	}
+	return ReturnJson(ctx, user)
+}
+
+// AdminDeactivateUser
+//
+//	@Summary	Deactivates user account
+//	@Security	APIKey
+//	@Produce	json
+//	@Tags		Admin
+//	@Param		user_id	path		int	true	"User id"
+//	@Success	200		{object}	model.User
+//	@Failure	400		{object}	HTTPError
+//	@Failure	403		{object}	HTTPError
+//	@Failure	500		{object}	HTTPError
+//	@Router		/admin/users/{user_id}/deactivate [post]
+func (h *HTTPHandler) AdminDeactivateUser(ctx *fiber.Ctx) error {
+	admin, _ := auth.GetAuth(ctx)
+	userId, err := getIdParam(ctx, "user_id")
+	if err != nil {
+		return err
+	}
+
+	user, err := h.ucase.AdminUseCase.DeactivateUser(ctx.Context(), admin.UserID, userId)
+	if err != nil {
+		return WrapError(err)
+	}
+	return ReturnJson(ctx, user)
+}
+
+// ImpersonateUser
+//
+//	@Summary		Issues a short-lived token to act as the user
+//	@Description	Every request made with the token is recorded to audit log.
+//	@Description	Only reads are available with the token, except personal data exports and admin API.
+//	@Security		APIKey
+//	@Produce		json
+//	@Tags			Admin
+//	@Param			user_id	path		int	true	"User id"
+//	@Success		201		{object}	model.ImpersonationToken
+//	@Failure		400		{object}	HTTPError
+//	@Failure		403		{object}	HTTPError
+//	@Failure		500		{object}	HTTPError
+//	@Router			/admin/users/{user_id}/impersonate [post]
+func (h *HTTPHandler) ImpersonateUser(ctx *fiber.Ctx) error {
+	admin, _ := auth.GetAuth(ctx)
+	userId, err := getIdParam(ctx, "user_id")
+	if err != nil {
+		return err
+	}
+
+	token, err := h.ucase.AdminUseCase.Impersonate(ctx.Context(), admin.UserID, userId)
+	if err != nil {
+		return WrapError(err)
+	}
+	ctx.Status(fiber.StatusCreated)
+	return ReturnJson(ctx, token)
+}
+
+// BanUser
+//
+//	@Summary		Bans user
+//	@Description	Banned user can't sign in until unbanned.
+//	@Security		APIKey
+//	@Accept			json
+//	@Produce		json
+//	@Tags			Admin
+//	@Param			user_id	path		int					true	"User id"
+//	@Param			request	body		model.BanRequest	true	"Ban reason"
+//	@Success		200		{object}	model.User
+//	@Failure		400		{object}	HTTPError
+//	@Failure		403		{object}	HTTPError
+//	@Failure		422		{object}	ValidationError
+//	@Failure		500		{object}	HTTPError
+//	@Router			/admin/users/{user_id}/ban [post]
+func (h *HTTPHandler) BanUser(ctx *fiber.Ctx) error {
+	admin, _ := auth.GetAuth(ctx)
+	userId, err := getIdParam(ctx, "user_id")
+	if err != nil {
+		return err
+	}
+	req, jerr := JsonParseAndValidate[model.BanRequest](ctx, validate)
+	if jerr != nil {
+		return jerr.AsFiberError(422)
+	}
+
+	user, err := h.ucase.AdminUseCase.BanUser(ctx.Context(), admin.UserID, userId, req.Reason)
+	if err != nil {
+		return WrapError(err)
+	}
+	return ReturnJson(ctx, user)
+}
+
+// UnbanUser
+//
+//	@Summary	Unbans user
+//	@Security	APIKey
+//	@Produce	json
+//	@Tags		Admin
+//	@Param		user_id	path		int	true	"User id"
+//	@Success	200		{object}	model.User
+//	@Failure	400		{object}	HTTPError
+//	@Failure	403		{object}	HTTPError
+//	@Failure	500		{object}	HTTPError
+//	@Router		/admin/users/{user_id}/unban [post]
+func (h *HTTPHandler) UnbanUser(ctx *fiber.Ctx) error {
+	admin, _ := auth.GetAuth(ctx)
+	userId, err := getIdParam(ctx, "user_id")
+	if err != nil {
+		return err
+	}
+
+	user, err := h.ucase.AdminUseCase.UnbanUser(ctx.Context(), admin.UserID, userId)
+	if err != nil {
+		return WrapError(err)
+	}
+	return ReturnJson(ctx, user)
+}
+
+// TransferOwnership
+//
+//	@Summary		Forcibly transfers organization ownership
+//	@Description	The user becomes the only owner of the organization, previous owners stay members.
+//	@Security		APIKey
+//	@Accept			json
+//	@Produce		json
+//	@Tags			Admin
+//	@Param			organization_id	path	int						true	"Organization id"
+//	@Param			request			body	model.OwnershipTransfer	true	"New owner"
+//	@Success		204
+//	@Failure		400	{object}	HTTPError
+//	@Failure		403	{object}	HTTPError
+//	@Failure		404	{object}	HTTPError
+//	@Failure		422	{object}	ValidationError
+//	@Failure		500	{object}	HTTPError
+//	@Router			/admin/organizations/{organization_id}/transfer [post]
+func (h *HTTPHandler) TransferOwnership(ctx *fiber.Ctx) error {
+	admin, _ := auth.GetAuth(ctx)
+	orgId, err := getOrganizationId(ctx)
+	if err != nil {
+		return err
+	}
+	req, jerr := JsonParseAndValidate[model.OwnershipTransfer](ctx, validate)
+	if jerr != nil {
+		return jerr.AsFiberError(422)
+	}
+
+	if err := h.ucase.AdminUseCase.TransferOwnership(ctx.Context(), admin.UserID, orgId, req.UserID); err != nil {
+		return WrapError(err)
+	}
+	ctx.Status(fiber.StatusNoContent)
+	return nil
+}
+
+// HideEvent
+//
+//	@Summary		Hides event from public listings
+//	@Security		APIKey
+//	@Accept			json
+//	@Produce		json
+//	@Tags			Admin
+//	@Param			event_id	path	int				true	"Event id"
+//	@Param			request		body	model.EventHide	true	"Hide reason"
+//	@Success		204
+//	@Failure		403	{object}	HTTPError
+//	@Failure		404	{object}	HTTPError
+//	@Failure		422	{object}	ValidationError
+//	@Failure		500	{object}	HTTPError
+//	@Router			/admin/events/{event_id}/hide [post]
+func (h *HTTPHandler) HideEvent(ctx *fiber.Ctx) error {
+	admin, _ := auth.GetAuth(ctx)
+	eventId, err := getIdParam(ctx, "event_id")
+	if err != nil {
+		return err
+	}
+	req, jerr := JsonParseAndValidate[model.EventHide](ctx, validate)
+	if jerr != nil {
+		return jerr.AsFiberError(422)
+	}
+
+	if err := h.ucase.AdminUseCase.HideEvent(ctx.Context(), admin.UserID, eventId, req.Reason); err != nil {
+		return WrapError(err)
+	}
+	ctx.Status(fiber.StatusNoContent)
+	return nil
+}
+
+// UnhideEvent
+//
+//	@Summary	Returns hidden event to public listings
+//	@Security	APIKey
+//	@Produce	json
+//	@Tags		Admin
+//	@Param		event_id	path	int	true	"Event id"
+//	@Success	204
+//	@Failure	403	{object}	HTTPError
+//	@Failure	404	{object}	HTTPError
+//	@Failure	500	{object}	HTTPError
+//	@Router		/admin/events/{event_id}/unhide [post]
+func (h *HTTPHandler) UnhideEvent(ctx *fiber.Ctx) error {
+	admin, _ := auth.GetAuth(ctx)
+	eventId, err := getIdParam(ctx, "event_id")
+	if err != nil {
+		return err
+	}
+
+	if err := h.ucase.AdminUseCase.UnhideEvent(ctx.Context(), admin.UserID, eventId); err != nil {
+		return WrapError(err)
+	}
+	ctx.Status(fiber.StatusNoContent)
+	return nil
+}
+
+// ListAuditLog
+//
+//	@Summary	Returns administrator actions, newest first
+//	@Security	APIKey
+//	@Produce	json
+//	@Tags		Admin
+//	@Param		page	query		model.Pagination	false	"Pagination"
+//	@Success	200		{array}		model.AuditEntry
+//	@Failure	403		{object}	HTTPError
+//	@Failure	422		{object}	ValidationError
+//	@Failure	500		{object}	HTTPError
+//	@Router		/admin/audit [get]
+func (h *HTTPHandler) ListAuditLog(ctx *fiber.Ctx) error {
+	page, jerr := QueryParseAndValidate[model.Pagination](ctx, validate)
+	if jerr != nil {
+		return jerr.AsFiberError(422)
+	}
+
+	entries, err := h.ucase.AdminUseCase.ListAuditLog(ctx.Context(), page)
+	if err != nil {
+		return WrapError(err)
+	}
+	return ReturnJson(ctx, entries)
+}
+
+// ListOutboxMessages
+//
+//	@Summary		Returns outgoing messages of all channels, newest first
+//	@Description	Message bodies are not returned as they contain login codes and activation tokens.
+//	@Security		APIKey
+//	@Produce		json
+//	@Tags			Admin
+//	@Param			filter	query		model.OutboxFilter	false	"Filter"
+//	@Success		200		{array}		model.OutboxMessage
+//	@Failure		403		{object}	HTTPError
+//	@Failure		422		{object}	ValidationError
+//	@Failure		500		{object}	HTTPError
+//	@Router			/admin/outbox [get]
+func (h *HTTPHandler) ListOutboxMessages(ctx *fiber.Ctx) error {
+	filter, jerr := QueryParseAndValidate[model.OutboxFilter](ctx, validate)
+	if jerr != nil {
+		return jerr.AsFiberError(422)
+	}
+
+	messages, err := h.ucase.OutboxUseCase.ListMessages(ctx.Context(), filter)
+	if err != nil {
+		return WrapError(err)
+	}
+	return ReturnJson(ctx, messages)
+}
+
+// ReplayOutboxMessage
+//
+//	@Summary	Returns dead message to the delivery queue
+//	@Security	APIKey
+//	@Produce	json
+//	@Tags		Admin
+//	@Param		message_id	path	int	true	"Message id"
+//	@Success	204
+//	@Failure	400	{object}	HTTPError
+//	@Failure	403	{object}	HTTPError
+//	@Failure	404	{object}	HTTPError
+//	@Failure	500	{object}	HTTPError
+//	@Router		/admin/outbox/{message_id}/replay [post]
+func (h *HTTPHandler) ReplayOutboxMessage(ctx *fiber.Ctx) error {
+	admin, _ := auth.GetAuth(ctx)
+	messageId, err := getIdParam(ctx, "message_id")
+	if err != nil {
+		return err
+	}
+
+	if err := h.ucase.OutboxUseCase.ReplayMessage(ctx.Context(), admin.UserID, messageId); err != nil {
+		return WrapError(err)
+	}
+	ctx.Status(fiber.StatusNoContent)
+	return nil
+}
@@ -0,0 +1,144 @@
+package handler
+
+import (
+	"fmt"
+	"github.com/burenotti/rtu-it-lab-recruit/handler/middlewares/auth"
+	"github.com/burenotti/rtu-it-lab-recruit/model"
+	"github.com/gofiber/fiber/v2"
+)
+
+const csvContentType = "text/csv; charset=utf-8"
+
+// GetEventAnalytics
+//
+//	@Summary		Returns analytics of event
+//	@Description	Registered and attended users, no-shows, registrations and check-ins over time and by traffic sources.
+//	@Description	Available to organization members with rights to edit events. Analytics are refreshed periodically.
+//	@Security		APIKey
+//	@Produce		json
+//	@Tags			Analytics
+//	@Param			event_id	path		int						true	"Event id"
+//	@Param			query		query		model.AnalyticsQuery	false	"Period and interval"
+//	@Success		200			{object}	model.EventAnalytics
+//	@Failure		400			{object}	HTTPError
+//	@Failure		404			{object}	HTTPError
+//	@Failure		422			{object}	ValidationError
+//	@Failure		500			{object}	HTTPError
+//	@Router			/event/{event_id}/analytics [get]
+func (h *HTTPHandler) GetEventAnalytics(ctx *fiber.Ctx) error {
+	user, _ := auth.GetAuth(ctx)
+	eventId, err := getIdParam(ctx, "event_id")
+	if err != nil {
+		return err
+	}
+	query, jerr := QueryParseAndValidate[model.AnalyticsQuery](ctx, validate)
+	if jerr != nil {
+		return jerr.AsFiberError(422)
+	}
+
+	analytics, err := h.ucase.GetEventAnalytics(ctx.Context(), user.UserID, eventId, query)
+	if err != nil {
+		return WrapError(err)
+	}
+	return ReturnJson(ctx, analytics)
+}
+
+// ExportEventAnalytics
+//
+//	@Summary		Exports registrations and check-ins of event over time to CSV
+//	@Security		APIKey
+//	@Produce		text/csv
+//	@Tags			Analytics
+//	@Param			event_id	path	int						true	"Event id"
+//	@Param			query		query	model.AnalyticsQuery	false	"Period and interval"
+//	@Success		200
+//	@Failure		400	{object}	HTTPError
+//	@Failure		404	{object}	HTTPError
+//	@Failure		422	{object}	ValidationError
+//	@Failure		500	{object}	HTTPError
+//	@Router			/event/{event_id}/analytics.csv [get]
+func (h *HTTPHandler) ExportEventAnalytics(ctx *fiber.Ctx) error {
+	user, _ := auth.GetAuth(ctx)
+	eventId, err := getIdParam(ctx, "event_id")
+	if err != nil {
+		return err
+	}
+	query, jerr := QueryParseAndValidate[model.AnalyticsQuery](ctx, validate)
+	if jerr != nil {
+		return jerr.AsFiberError(422)
+	}
+
+	data, err := h.ucase.ExportEventAnalytics(ctx.Context(), user.UserID, eventId, query)
+	if err != nil {
+		return WrapError(err)
+	}
+	ctx.Attachment(fmt.Sprintf("event-%d-analytics.csv", eventId))
+	ctx.Set(fiber.HeaderContentType, csvContentType)
+	return ctx.Send(data)
+}
+
+// GetOrganizationAnalytics
+//
+//	@Summary		Returns analytics of organization's events
+//	@Description	Attendance in total and by events, registrations and check-ins over time and by traffic sources.
+//	@Description	Available to organization members with rights to edit events. Analytics are refreshed periodically.
+//	@Security		APIKey
+//	@Produce		json
+//	@Tags			Analytics
+//	@Param			organization_id	path		int						true	"Organization id"
+//	@Param			query			query		model.AnalyticsQuery	false	"Period and interval"
+//	@Success		200				{object}	model.OrganizationAnalytics
+//	@Failure		400				{object}	HTTPError
+//	@Failure		422				{object}	ValidationError
+//	@Failure		500				{object}	HTTPError
+//	@Router			/organization/{organization_id}/analytics [get]
+func (h *HTTPHandler) GetOrganizationAnalytics(ctx *fiber.Ctx) error {
+	user, _ := auth.GetAuth(ctx)
+	orgId, err := getIdParam(ctx, "organization_id")
+	if err != nil {
+		return err
+	}
+	query, jerr := QueryParseAndValidate[model.AnalyticsQuery](ctx, validate)
+	if jerr != nil {
+		return jerr.AsFiberError(422)
+	}
+
+	analytics, err := h.ucase.GetOrganizationAnalytics(ctx.Context(), user.UserID, orgId, query)
+	if err != nil {
+		return WrapError(err)
+	}
+	return ReturnJson(ctx, analytics)
+}
+
+// ExportOrganizationAnalytics
+//
+//	@Summary	Exports attendance of organization's events to CSV, an event per row
+//	@Security	APIKey
+//	@Produce	text/csv
+//	@Tags		Analytics
+//	@Param		organization_id	path	int						true	"Organization id"
+//	@Param		query			query	model.AnalyticsQuery	false	"Period"
+//	@Success	200
+//	@Failure	400	{object}	HTTPError
+//	@Failure	422	{object}	ValidationError
+//	@Failure	500	{object}	HTTPError
+//	@Router		/organization/{organization_id}/analytics.csv [get]
+func (h *HTTPHandler) ExportOrganizationAnalytics(ctx *fiber.Ctx) error {
+	user, _ := auth.GetAuth(ctx)
+	orgId, err := getIdParam(ctx, "organization_id")
+	if err != nil {
+		return err
+	}
+	query, jerr := QueryParseAndValidate[model.AnalyticsQuery](ctx, validate)
+	if jerr != nil {
+		return jerr.AsFiberError(422)
+	}
+
+	data, err := h.ucase.ExportOrganizationAnalytics(ctx.Context(), user.UserID, orgId, query)
+	if err != nil {
+		return WrapError(err)
+	}
+	ctx.Attachment(fmt.Sprintf("organization-%d-analytics.csv", orgId))
+	ctx.Set(fiber.HeaderContentType, csvContentType)
+	return ctx.Send(data)
+}
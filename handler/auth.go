@@ -1,9 +1,11 @@
 package handler
 
 import (
+	"bytes"
 	"github.com/burenotti/rtu-it-lab-recruit/model"
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
+	"net/url"
 )
 
 var validate = validator.New()
@@ -14,11 +16,12 @@ var validate = validator.New()
 //	@Summary	Creates new user that should be activated with email
 //	@Accept		json
 //	@Produce	json
-//	@Param		user	body		model.UserCreate	true	"User info"
-//	@Success	201		{object}	model.UserGet
-//	@Failure	422		{object}	HTTPError
-//	@Failure	500		{object}	HTTPError
-//	@Failure	400		{object}	HTTPError
+//	@Param		user			body		model.UserCreate	true	"User info"
+//	@Param		Accept-Language	header		string				false	"Default for preferred_locale"
+//	@Success	201				{object}	model.UserGet
+//	@Failure	422				{object}	HTTPError
+//	@Failure	500				{object}	HTTPError
+//	@Failure	400				{object}	HTTPError
 //	@Router		/auth/sign-up [post]
 func (h *HTTPHandler) SignUp(ctx *fiber.Ctx) error {
 
@@ -26,6 +29,9 @@ func (h *HTTPHandler) SignUp(ctx *fiber.Ctx) error {
 	if jerr != nil {
 		return jerr.AsFiberError(fiber.StatusUnprocessableEntity)
 	}
+	if u.PreferredLocale == "" {
+		u.PreferredLocale = ctx.AcceptsLanguages(model.SupportedLocales...)
+	}
 	user, err := h.ucase.SignUp(ctx.Context(), u)
 	if err != nil {
 		return WrapError(err)
@@ -34,10 +40,49 @@ func (h *HTTPHandler) SignUp(ctx *fiber.Ctx) error {
 	return ReturnJson(ctx, user)
 }
 
+type activationPageContext struct {
+	Action    string
+	Error     string
+	Activated bool
+}
+
+// ActivationLanding
+//
+//	@Tags		Auth
+//	@Summary	Landing page for activation link sent in email
+//	@Description	Does not activate user, so email link scanners can't use the token.
+//	@Description	Renders confirmation page or redirects to the frontend if it is configured.
+//	@Produce	html
+//
+//	@Param		token	path	string	true	"Activation token"
+//
+//	@Success	200
+//	@Success	303
+//	@Failure	400
+//	@Router		/auth/activate/{token} [get]
+func (h *HTTPHandler) ActivationLanding(ctx *fiber.Ctx) error {
+	token := ctx.Params("token")
+	if token == "" {
+		return NewHTTPError("token is required path parameter").AsFiberError(fiber.StatusBadRequest)
+	}
+	if h.cfg.ActivationRedirectURL != "" {
+		redirect := h.cfg.ActivationRedirectURL + "?token=" + url.QueryEscape(token)
+		return ctx.Redirect(redirect, fiber.StatusSeeOther)
+	}
+
+	page := activationPageContext{Action: "/auth/activate/" + url.PathEscape(token)}
+	if _, err := h.ucase.CheckActivationToken(ctx.Context(), token); err != nil {
+		page.Error = UnwrapAtomicError(err).Error()
+		ctx.Status(fiber.StatusBadRequest)
+	}
+	return h.renderActivationPage(ctx, page)
+}
+
 // ActivateWithToken
 //
 //	@Tags		Auth
 //	@Summary	Activates user with token sent in email
+//	@Description	Every token could be used only once.
 //	@Accept		json
 //	@Produce	json
 //
@@ -46,13 +91,60 @@ func (h *HTTPHandler) SignUp(ctx *fiber.Ctx) error {
 //	@Success	204
 //	@Failure	500	{object}	HTTPError
 //	@Failure	400	{object}	HTTPError
-//	@Router		/auth/activate/{token} [get]
+//	@Router		/auth/activate/{token} [post]
 func (h *HTTPHandler) ActivateWithToken(ctx *fiber.Ctx) error {
 	token := ctx.Params("token")
 	if token == "" {
 		return NewHTTPError("token is required path parameter").AsFiberError(fiber.StatusBadRequest)
 	}
-	if err := h.ucase.ActivateWithToken(ctx.Context(), token); err != nil {
+	err := h.ucase.ActivateWithToken(ctx.Context(), token)
+
+	// Confirmation form of the landing page expects html in response
+	if ctx.Accepts(fiber.MIMEApplicationJSON, fiber.MIMETextHTML) == fiber.MIMETextHTML {
+		page := activationPageContext{Activated: err == nil}
+		if err != nil {
+			page.Error = UnwrapAtomicError(err).Error()
+			ctx.Status(fiber.StatusBadRequest)
+		}
+		return h.renderActivationPage(ctx, page)
+	}
+
+	if err != nil {
+		return WrapError(err)
+	}
+	ctx.Status(fiber.StatusNoContent)
+	return nil
+}
+
+func (h *HTTPHandler) renderActivationPage(ctx *fiber.Ctx, page activationPageContext) error {
+	var buf bytes.Buffer
+	if err := h.cfg.ActivationPage.Execute(&buf, page); err != nil {
+		return NewHTTPError(err.Error()).AsFiberError(fiber.StatusInternalServerError)
+	}
+	ctx.Type("html", "utf-8")
+	return ctx.Send(buf.Bytes())
+}
+
+// ResendActivation
+//
+//	@Tags			Auth
+//	@Summary		Sends a new activation token to not yet activated user
+//	@Description	Responds the same whether the email is registered or not, and whether the email was sent.
+//	@Accept			json
+//	@Produce		json
+//
+//	@Param			request	body	model.ActivationResendRequest	true	"User email"
+//
+//	@Success		204
+//	@Failure		500	{object}	HTTPError
+//	@Failure		422	{object}	ValidationError
+//	@Router			/auth/activate/resend [post]
+func (h *HTTPHandler) ResendActivation(ctx *fiber.Ctx) error {
+	req, jerr := JsonParseAndValidate[model.ActivationResendRequest](ctx, validate)
+	if jerr != nil {
+		return jerr.AsFiberError(fiber.StatusUnprocessableEntity)
+	}
+	if err := h.ucase.ResendActivation(ctx.Context(), req.Email); err != nil {
 		return WrapError(err)
 	}
 	ctx.Status(fiber.StatusNoContent)
@@ -97,6 +189,7 @@ func (h *HTTPHandler) RequestEmailCode(ctx *fiber.Ctx) error {
 //	@Success	200			{object}	model.Token
 //	@Failure	500			{object}	HTTPError
 //	@Failure	422			{object}	HTTPError
+//	@Failure	403			{object}	HTTPError
 //	@Failure	401			{object}	HTTPError
 //	@Router		/auth/sign-in [post]
 func (h *HTTPHandler) SignIn(ctx *fiber.Ctx) error {
@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"fmt"
+	"github.com/burenotti/rtu-it-lab-recruit/handler/middlewares/auth"
+	"github.com/burenotti/rtu-it-lab-recruit/model"
+	"github.com/gofiber/fiber/v2"
+)
+
+const calendarContentType = "text/calendar; charset=utf-8"
+
+// ExportEvent
+//
+//	@Summary		Exports published event to iCalendar
+//	@Description	Recurring events are exported with RRULE, cancelled occurrences as EXDATE
+//	@Description	and changed occurrences as separate VEVENTs with RECURRENCE-ID.
+//	@Produce		text/calendar
+//	@Tags			Calendar
+//	@Param			event_id	path	int	true	"Event id"
+//	@Success		200
+//	@Failure		404	{object}	HTTPError
+//	@Failure		422	{object}	HTTPError
+//	@Failure		500	{object}	HTTPError
+//	@Router			/event/{event_id}.ics [get]
+func (h *HTTPHandler) ExportEvent(ctx *fiber.Ctx) error {
+	eventId, err := getIdParam(ctx, "event_id")
+	if err != nil {
+		return err
+	}
+
+	calendar, err := h.ucase.ExportEvent(ctx.Context(), eventId)
+	if err != nil {
+		return WrapError(err)
+	}
+	ctx.Attachment(fmt.Sprintf("event-%d.ics", eventId))
+	ctx.Set(fiber.HeaderContentType, calendarContentType)
+	return ctx.Send(calendar)
+}
+
+// ExportCalendarFeed
+//
+//	@Summary		Returns calendar feed
+//	@Description	The feed is accessed by the token from its URL, so calendar apps can subscribe to it.
+//	@Description	It contains events from a month ago up to two years ahead.
+//	@Produce		text/calendar
+//	@Tags			Calendar
+//	@Param			token	path	string	true	"Feed token"
+//	@Success		200
+//	@Failure		404	{object}	HTTPError
+//	@Failure		500	{object}	HTTPError
+//	@Router			/feeds/{token}.ics [get]
+func (h *HTTPHandler) ExportCalendarFeed(ctx *fiber.Ctx) error {
+	calendar, err := h.ucase.ExportCalendarFeed(ctx.Context(), ctx.Params("token"))
+	if err != nil {
+		return WrapError(err)
+	}
+	ctx.Set(fiber.HeaderContentType, calendarContentType)
+	return ctx.Send(calendar)
+}
+
+// CreateCalendarFeed
+//
+//	@Summary		Creates calendar feed
+//	@Description	Feed of kind "registrations" contains events the user has registered for,
+//	@Description	"organization" contains events of the organization, "search" contains events matching the saved search.
+//	@Security		APIKey
+//	@Accept			json
+//	@Produce		json
+//	@Tags			Calendar
+//	@Param			feed	body		model.CalendarFeedCreate	true	"Feed"
+//	@Success		201		{object}	model.CalendarFeed
+//	@Failure		400		{object}	HTTPError
+//	@Failure		404		{object}	HTTPError
+//	@Failure		422		{object}	ValidationError
+//	@Failure		500		{object}	HTTPError
+//	@Router			/me/feeds [post]
+func (h *HTTPHandler) CreateCalendarFeed(ctx *fiber.Ctx) error {
+	user, _ := auth.GetAuth(ctx)
+	create, jerr := JsonParseAndValidate[model.CalendarFeedCreate](ctx, validate)
+	if jerr != nil {
+		return jerr.AsFiberError(422)
+	}
+
+	feed, err := h.ucase.CreateCalendarFeed(ctx.Context(), user.UserID, create)
+	if err != nil {
+		return WrapError(err)
+	}
+	ctx.Status(fiber.StatusCreated)
+	return ReturnJson(ctx, feed)
+}
+
+// ListCalendarFeeds
+//
+//	@Summary	Returns calendar feeds of current user
+//	@Security	APIKey
+//	@Produce	json
+//	@Tags		Calendar
+//	@Success	200	{array}		model.CalendarFeed
+//	@Failure	401	{object}	HTTPError
+//	@Failure	500	{object}	HTTPError
+//	@Router		/me/feeds [get]
+func (h *HTTPHandler) ListCalendarFeeds(ctx *fiber.Ctx) error {
+	user, _ := auth.GetAuth(ctx)
+
+	feeds, err := h.ucase.ListCalendarFeeds(ctx.Context(), user.UserID)
+	if err != nil {
+		return WrapError(err)
+	}
+	return ReturnJson(ctx, feeds)
+}
+
+// DeleteCalendarFeed
+//
+//	@Summary	Revokes calendar feed
+//	@Security	APIKey
+//	@Tags		Calendar
+//	@Param		feed_id	path	int	true	"Feed id"
+//	@Success	204
+//	@Failure	404	{object}	HTTPError
+//	@Failure	422	{object}	HTTPError
+//	@Failure	500	{object}	HTTPError
+//	@Router		/me/feeds/{feed_id} [delete]
+func (h *HTTPHandler) DeleteCalendarFeed(ctx *fiber.Ctx) error {
+	user, _ := auth.GetAuth(ctx)
+	feedId, err := getIdParam(ctx, "feed_id")
+	if err != nil {
+		return err
+	}
+
+	if err = h.ucase.DeleteCalendarFeed(ctx.Context(), user.UserID, feedId); err != nil {
+		return WrapError(err)
+	}
+	ctx.Status(fiber.StatusNoContent)
+	return nil
+}
@@ -0,0 +1,160 @@
+package handler
+
+import (
+	"github.com/burenotti/rtu-it-lab-recruit/handler/middlewares/auth"
+	"github.com/burenotti/rtu-it-lab-recruit/model"
+	"github.com/gofiber/fiber/v2"
+)
+
+// ListCategories
+//
+//	@Summary		Returns event categories
+//	@Description	Categories form a tree, root categories have no parent_id.
+//	@Produce		json
+//	@Tags			Categories
+//	@Success		200	{array}		model.Category
+//	@Failure		500	{object}	HTTPError
+//	@Router			/categories [get]
+func (h *HTTPHandler) ListCategories(ctx *fiber.Ctx) error {
+	categories, err := h.ucase.ListCategories(ctx.Context())
+	if err != nil {
+		return WrapError(err)
+	}
+	return ReturnJson(ctx, categories)
+}
+
+// CountCategoryEvents
+//
+//	@Summary		Returns the number of published upcoming events in each category
+//	@Description	Total also counts events of all subcategories.
+//	@Produce		json
+//	@Tags			Categories
+//	@Success		200	{array}		model.CategoryCount
+//	@Failure		500	{object}	HTTPError
+//	@Router			/categories/counts [get]
+func (h *HTTPHandler) CountCategoryEvents(ctx *fiber.Ctx) error {
+	counts, err := h.ucase.CountCategoryEvents(ctx.Context())
+	if err != nil {
+		return WrapError(err)
+	}
+	return ReturnJson(ctx, counts)
+}
+
+// SuggestTags
+//
+//	@Summary		Autocompletes tags
+//	@Description	Returns the most used tags of published upcoming events starting with the prefix.
+//	@Produce		json
+//	@Tags			Categories
+//	@Param			query	query		model.TagQuery	true	"Prefix"
+//	@Success		200		{array}		model.TagSuggestion
+//	@Failure		422		{object}	ValidationError
+//	@Failure		500		{object}	HTTPError
+//	@Router			/tags [get]
+func (h *HTTPHandler) SuggestTags(ctx *fiber.Ctx) error {
+	query, jerr := QueryParseAndValidate[model.TagQuery](ctx, validate)
+	if jerr != nil {
+		return jerr.AsFiberError(422)
+	}
+
+	tags, err := h.ucase.SuggestTags(ctx.Context(), query)
+	if err != nil {
+		return WrapError(err)
+	}
+	return ReturnJson(ctx, tags)
+}
+
+// CreateCategory
+//
+//	@Summary		Creates event category
+//	@Description	Categories can be nested up to three levels.
+//	@Security		APIKey
+//	@Accept			json
+//	@Produce		json
+//	@Tags			Admin
+//	@Param			category	body		model.CategoryCreate	true	"Category"
+//	@Success		201			{object}	model.Category
+//	@Failure		400			{object}	HTTPError
+//	@Failure		403			{object}	HTTPError
+//	@Failure		404			{object}	HTTPError
+//	@Failure		409			{object}	HTTPError
+//	@Failure		422			{object}	ValidationError
+//	@Failure		500			{object}	HTTPError
+//	@Router			/admin/categories [post]
+func (h *HTTPHandler) CreateCategory(ctx *fiber.Ctx) error {
+	admin, _ := auth.GetAuth(ctx)
+	create, jerr := JsonParseAndValidate[model.CategoryCreate](ctx, validate)
+	if jerr != nil {
+		return jerr.AsFiberError(422)
+	}
+
+	category, err := h.ucase.CreateCategory(ctx.Context(), admin.UserID, create)
+	if err != nil {
+		return WrapError(err)
+	}
+	ctx.Status(fiber.StatusCreated)
+	return ReturnJson(ctx, category)
+}
+
+// UpdateCategory
+//
+//	@Summary		Updates event category
+//	@Description	Set parent_id to move the category with its subcategories, zero makes it a root category.
+//	@Security		APIKey
+//	@Accept			json
+//	@Produce		json
+//	@Tags			Admin
+//	@Param			category_id	path		int						true	"Category id"
+//	@Param			updates		body		model.CategoryUpdate	true	"Fields that will be updated"
+//	@Success		200			{object}	model.Category
+//	@Failure		400			{object}	HTTPError
+//	@Failure		403			{object}	HTTPError
+//	@Failure		404			{object}	HTTPError
+//	@Failure		409			{object}	HTTPError
+//	@Failure		422			{object}	ValidationError
+//	@Failure		500			{object}	HTTPError
+//	@Router			/admin/categories/{category_id} [patch]
+func (h *HTTPHandler) UpdateCategory(ctx *fiber.Ctx) error {
+	admin, _ := auth.GetAuth(ctx)
+	categoryId, err := getIdParam(ctx, "category_id")
+	if err != nil {
+		return err
+	}
+	update, jerr := JsonParseAndValidate[model.CategoryUpdate](ctx, validate)
+	if jerr != nil {
+		return jerr.AsFiberError(422)
+	}
+
+	category, err := h.ucase.UpdateCategory(ctx.Context(), admin.UserID, categoryId, update)
+	if err != nil {
+		return WrapError(err)
+	}
+	return ReturnJson(ctx, category)
+}
+
+// DeleteCategory
+//
+//	@Summary		Deletes event category
+//	@Description	Categories with subcategories or events can't be deleted.
+//	@Security		APIKey
+//	@Tags			Admin
+//	@Param			category_id	path	int	true	"Category id"
+//	@Success		204
+//	@Failure		403	{object}	HTTPError
+//	@Failure		404	{object}	HTTPError
+//	@Failure		409	{object}	HTTPError
+//	@Failure		500	{object}	HTTPError
+//	@Router			/admin/categories/{category_id} [delete]
+func (h *HTTPHandler) DeleteCategory(ctx *fiber.Ctx) error {
+	admin, _ := auth.GetAuth(ctx)
+	categoryId, err := getIdParam(ctx, "category_id")
+	if err != nil {
+		return err
+	}
+
+	if err := h.ucase.DeleteCategory(ctx.Context(), admin.UserID, categoryId); err != nil {
+		return WrapError(err)
+	}
+	ctx.Status(fiber.StatusNoContent)
+	return nil
+}
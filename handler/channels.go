@@ -0,0 +1,198 @@
+package handler
+
+import (
+	"crypto/subtle"
+	"github.com/burenotti/rtu-it-lab-recruit/handler/middlewares/auth"
+	"github.com/burenotti/rtu-it-lab-recruit/model"
+	"github.com/gofiber/fiber/v2"
+)
+
+const telegramSecretHeader = "X-Telegram-Bot-Api-Secret-Token"
+
+// ListChannels
+//
+//	@Summary		Returns notification channels of current user
+//	@Description	Email and in-app channels are always present. Login codes are sent to the enabled channel
+//	@Description	with the highest priority except in-app, event notifications are sent to all enabled channels.
+//	@Security		APIKey
+//	@Produce		json
+//	@Tags			Me
+//	@Success		200	{array}		model.UserChannel
+//	@Failure		401	{object}	HTTPError
+//	@Failure		500	{object}	HTTPError
+//	@Router			/me/channels [get]
+func (h *HTTPHandler) ListChannels(ctx *fiber.Ctx) error {
+	user, _ := auth.GetAuth(ctx)
+
+	channels, err := h.ucase.ListChannels(ctx.Context(), user.UserID)
+	if err != nil {
+		return WrapError(err)
+	}
+	return ReturnJson(ctx, channels)
+}
+
+// LinkPhone
+//
+//	@Summary		Starts linking of phone number to receive notifications by SMS
+//	@Description	Sends a confirmation code to the phone. The phone is linked once the code is confirmed.
+//	@Security		APIKey
+//	@Accept			json
+//	@Produce		json
+//	@Tags			Me
+//	@Param			phone	body		model.PhoneLink	true	"Phone in E.164 format"
+//	@Success		202		{object}	model.PhoneVerification
+//	@Failure		401		{object}	HTTPError
+//	@Failure		403		{object}	HTTPError
+//	@Failure		422		{object}	ValidationError
+//	@Failure		429		{object}	HTTPError
+//	@Failure		501		{object}	HTTPError
+//	@Failure		500		{object}	HTTPError
+//	@Router			/me/channels/sms [put]
+func (h *HTTPHandler) LinkPhone(ctx *fiber.Ctx) error {
+	user, _ := auth.GetAuth(ctx)
+	link, jerr := JsonParseAndValidate[model.PhoneLink](ctx, validate)
+	if jerr != nil {
+		return jerr.AsFiberError(422)
+	}
+
+	verification, err := h.ucase.LinkPhone(ctx.Context(), user.UserID, link)
+	if err != nil {
+		return WrapError(err)
+	}
+	ctx.Status(fiber.StatusAccepted)
+	return ReturnJson(ctx, verification)
+}
+
+// ConfirmPhone
+//
+//	@Summary	Links phone number confirmed by the code sent to it
+//	@Security	APIKey
+//	@Accept		json
+//	@Produce	json
+//	@Tags		Me
+//	@Param		code	body		model.PhoneConfirmation	true	"Code sent to the phone"
+//	@Success	200		{object}	model.UserChannel
+//	@Failure	401		{object}	HTTPError
+//	@Failure	403		{object}	HTTPError
+//	@Failure	404		{object}	HTTPError
+//	@Failure	422		{object}	ValidationError
+//	@Failure	501		{object}	HTTPError
+//	@Failure	500		{object}	HTTPError
+//	@Router		/me/channels/sms/confirm [post]
+func (h *HTTPHandler) ConfirmPhone(ctx *fiber.Ctx) error {
+	user, _ := auth.GetAuth(ctx)
+	confirm, jerr := JsonParseAndValidate[model.PhoneConfirmation](ctx, validate)
+	if jerr != nil {
+		return jerr.AsFiberError(422)
+	}
+
+	channel, err := h.ucase.ConfirmPhone(ctx.Context(), user.UserID, confirm)
+	if err != nil {
+		return WrapError(err)
+	}
+	return ReturnJson(ctx, channel)
+}
+
+// StartTelegramLink
+//
+//	@Summary		Starts linking of Telegram chat
+//	@Description	Returns a deep link to the bot. The chat is linked once the user opens the link and starts the bot.
+//	@Security		APIKey
+//	@Produce		json
+//	@Tags			Me
+//	@Success		201	{object}	model.TelegramLink
+//	@Failure		401	{object}	HTTPError
+//	@Failure		501	{object}	HTTPError
+//	@Failure		500	{object}	HTTPError
+//	@Router			/me/channels/telegram [post]
+func (h *HTTPHandler) StartTelegramLink(ctx *fiber.Ctx) error {
+	user, _ := auth.GetAuth(ctx)
+
+	link, err := h.ucase.StartTelegramLink(ctx.Context(), user.UserID)
+	if err != nil {
+		return WrapError(err)
+	}
+	ctx.Status(fiber.StatusCreated)
+	return ReturnJson(ctx, link)
+}
+
+// UpdateChannel
+//
+//	@Summary	Updates preferences of notification channel
+//	@Security	APIKey
+//	@Accept		json
+//	@Produce	json
+//	@Tags		Me
+//	@Param		channel	path		string					true	"Channel"	Enums(email, telegram, sms, inapp)
+//	@Param		updates	body		model.UserChannelUpdate	true	"Fields that will be updated"
+//	@Success	200		{object}	model.UserChannel
+//	@Failure	401		{object}	HTTPError
+//	@Failure	404		{object}	HTTPError
+//	@Failure	422		{object}	ValidationError
+//	@Failure	500		{object}	HTTPError
+//	@Router		/me/channels/{channel} [patch]
+func (h *HTTPHandler) UpdateChannel(ctx *fiber.Ctx) error {
+	user, _ := auth.GetAuth(ctx)
+	update, jerr := JsonParseAndValidate[model.UserChannelUpdate](ctx, validate)
+	if jerr != nil {
+		return jerr.AsFiberError(422)
+	}
+
+	channel, err := h.ucase.UpdateChannel(ctx.Context(), user.UserID, ctx.Params("channel"), update)
+	if err != nil {
+		return WrapError(err)
+	}
+	return ReturnJson(ctx, channel)
+}
+
+// UnlinkChannel
+//
+//	@Summary	Unlinks notification channel
+//	@Security	APIKey
+//	@Produce	json
+//	@Tags		Me
+//	@Param		channel	path	string	true	"Channel"	Enums(telegram, sms)
+//	@Success	204
+//	@Failure	400	{object}	HTTPError
+//	@Failure	401	{object}	HTTPError
+//	@Failure	404	{object}	HTTPError
+//	@Failure	500	{object}	HTTPError
+//	@Router		/me/channels/{channel} [delete]
+func (h *HTTPHandler) UnlinkChannel(ctx *fiber.Ctx) error {
+	user, _ := auth.GetAuth(ctx)
+
+	if err := h.ucase.UnlinkChannel(ctx.Context(), user.UserID, ctx.Params("channel")); err != nil {
+		return WrapError(err)
+	}
+	ctx.Status(fiber.StatusNoContent)
+	return nil
+}
+
+// TelegramWebhook
+//
+//	@Summary		Receives updates from Telegram Bot API
+//	@Description	Requests must contain the secret token the webhook was registered with.
+//	@Accept			json
+//	@Tags			Channels
+//	@Success		200
+//	@Failure		403	{object}	HTTPError
+//	@Failure		404	{object}	HTTPError
+//	@Router			/telegram/webhook [post]
+func (h *HTTPHandler) TelegramWebhook(ctx *fiber.Ctx) error {
+	if h.cfg.TelegramWebhookSecret == "" {
+		return fiber.ErrNotFound
+	}
+	secret := ctx.Get(telegramSecretHeader)
+	if subtle.ConstantTimeCompare([]byte(secret), []byte(h.cfg.TelegramWebhookSecret)) != 1 {
+		return NewHTTPError("invalid secret token").AsFiberError(fiber.StatusForbidden)
+	}
+
+	update := &model.TelegramUpdate{}
+	if err := ctx.BodyParser(update); err != nil {
+		return NewHTTPError("invalid update").AsFiberError(fiber.StatusBadRequest)
+	}
+	if err := h.ucase.HandleTelegramUpdate(ctx.Context(), update); err != nil {
+		return WrapError(err)
+	}
+	return ctx.SendStatus(fiber.StatusOK)
+}
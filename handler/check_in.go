@@ -0,0 +1,167 @@
+package handler
+
+import (
+	"fmt"
+	"github.com/burenotti/rtu-it-lab-recruit/handler/middlewares/auth"
+	"github.com/burenotti/rtu-it-lab-recruit/model"
+	"github.com/gofiber/fiber/v2"
+)
+
+// GetTicket
+//
+//	@Summary		Returns ticket of current user's registration
+//	@Description	The code of the ticket is signed, it is shown as a QR code and scanned at the entrance.
+//	@Security		APIKey
+//	@Produce		json
+//	@Tags			Check-in
+//	@Param			registration_id	path		int	true	"Registration id"
+//	@Success		200				{object}	model.Ticket
+//	@Failure		400				{object}	HTTPError
+//	@Failure		404				{object}	HTTPError
+//	@Failure		500				{object}	HTTPError
+//	@Router			/me/tickets/{registration_id} [get]
+func (h *HTTPHandler) GetTicket(ctx *fiber.Ctx) error {
+	user, _ := auth.GetAuth(ctx)
+	registrationId, err := getIdParam(ctx, "registration_id")
+	if err != nil {
+		return err
+	}
+
+	ticket, err := h.ucase.GetTicket(ctx.Context(), user.UserID, registrationId)
+	if err != nil {
+		return WrapError(err)
+	}
+	return ReturnJson(ctx, ticket)
+}
+
+// GetTicketQRCode
+//
+//	@Summary	Returns QR code of current user's ticket
+//	@Security	APIKey
+//	@Produce	png
+//	@Tags		Check-in
+//	@Param		registration_id	path	int	true	"Registration id"
+//	@Success	200
+//	@Failure	400	{object}	HTTPError
+//	@Failure	404	{object}	HTTPError
+//	@Failure	500	{object}	HTTPError
+//	@Router		/me/tickets/{registration_id}.png [get]
+func (h *HTTPHandler) GetTicketQRCode(ctx *fiber.Ctx) error {
+	user, _ := auth.GetAuth(ctx)
+	registrationId, err := getIdParam(ctx, "registration_id")
+	if err != nil {
+		return err
+	}
+
+	image, err := h.ucase.GetTicketQRCode(ctx.Context(), user.UserID, registrationId)
+	if err != nil {
+		return WrapError(err)
+	}
+	ctx.Set(fiber.HeaderContentType, "image/png")
+	ctx.Set(fiber.HeaderContentDisposition, fmt.Sprintf("inline; filename=\"ticket-%d.png\"", registrationId))
+	return ctx.Send(image)
+}
+
+// CheckIn
+//
+//	@Summary		Checks in ticket scanned at the entrance
+//	@Description	Available to organization members with rights to check in or edit events.
+//	@Description	Invalid tickets are rejected, tickets scanned again are duplicates with the time of the first scan.
+//	@Security		APIKey
+//	@Accept			json
+//	@Produce		json
+//	@Tags			Check-in
+//	@Param			event_id	path		int					true	"Event id"
+//	@Param			check_in	body		model.CheckInCreate	true	"Scanned ticket"
+//	@Success		200			{object}	model.CheckInResult
+//	@Failure		400			{object}	HTTPError
+//	@Failure		404			{object}	HTTPError
+//	@Failure		422			{object}	ValidationError
+//	@Failure		500			{object}	HTTPError
+//	@Router			/event/{event_id}/check-ins [post]
+func (h *HTTPHandler) CheckIn(ctx *fiber.Ctx) error {
+	user, _ := auth.GetAuth(ctx)
+	eventId, err := getIdParam(ctx, "event_id")
+	if err != nil {
+		return err
+	}
+	create, jerr := JsonParseAndValidate[model.CheckInCreate](ctx, validate)
+	if jerr != nil {
+		return jerr.AsFiberError(422)
+	}
+
+	result, err := h.ucase.CheckIn(ctx.Context(), user.UserID, eventId, create)
+	if err != nil {
+		return WrapError(err)
+	}
+	return ReturnJson(ctx, result)
+}
+
+// ListAttendees
+//
+//	@Summary		Returns attendees of event
+//	@Description	Scanners download the list to check in offline, codes of tickets are verified with the public key.
+//	@Description	Available to organization members with rights to check in or edit events.
+//	@Security		APIKey
+//	@Produce		json
+//	@Tags			Check-in
+//	@Param			event_id		path		int	true	"Event id"
+//	@Param			occurrence_id	query		int	false	"Occurrence id of recurring event"
+//	@Success		200				{object}	model.AttendeeList
+//	@Failure		400				{object}	HTTPError
+//	@Failure		404				{object}	HTTPError
+//	@Failure		422				{object}	ValidationError
+//	@Failure		500				{object}	HTTPError
+//	@Router			/event/{event_id}/attendees [get]
+func (h *HTTPHandler) ListAttendees(ctx *fiber.Ctx) error {
+	user, _ := auth.GetAuth(ctx)
+	eventId, err := getIdParam(ctx, "event_id")
+	if err != nil {
+		return err
+	}
+	query, jerr := QueryParseAndValidate[model.AttendeeListQuery](ctx, validate)
+	if jerr != nil {
+		return jerr.AsFiberError(422)
+	}
+
+	attendees, err := h.ucase.ListAttendees(ctx.Context(), user.UserID, eventId, query)
+	if err != nil {
+		return WrapError(err)
+	}
+	return ReturnJson(ctx, attendees)
+}
+
+// SyncCheckIns
+//
+//	@Summary		Uploads check-ins made offline
+//	@Description	Results are in the order of check-ins. If the ticket was checked in on several scanners,
+//	@Description	the earliest scan wins and the others are duplicates.
+//	@Security		APIKey
+//	@Accept			json
+//	@Produce		json
+//	@Tags			Check-in
+//	@Param			event_id	path		int					true	"Event id"
+//	@Param			check_ins	body		model.CheckInSync	true	"Check-ins"
+//	@Success		200			{array}		model.CheckInResult
+//	@Failure		400			{object}	HTTPError
+//	@Failure		404			{object}	HTTPError
+//	@Failure		422			{object}	ValidationError
+//	@Failure		500			{object}	HTTPError
+//	@Router			/event/{event_id}/check-ins/sync [post]
+func (h *HTTPHandler) SyncCheckIns(ctx *fiber.Ctx) error {
+	user, _ := auth.GetAuth(ctx)
+	eventId, err := getIdParam(ctx, "event_id")
+	if err != nil {
+		return err
+	}
+	sync, jerr := JsonParseAndValidate[model.CheckInSync](ctx, validate)
+	if jerr != nil {
+		return jerr.AsFiberError(422)
+	}
+
+	results, err := h.ucase.SyncCheckIns(ctx.Context(), user.UserID, eventId, sync)
+	if err != nil {
+		return WrapError(err)
+	}
+	return ReturnJson(ctx, results)
+}
@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"github.com/burenotti/rtu-it-lab-recruit/handler/middlewares/auth"
+	"github.com/burenotti/rtu-it-lab-recruit/model"
+	"github.com/gofiber/fiber/v2"
+)
+
+// GetDigestSettings
+//
+//	@Summary		Returns weekly digest settings of current user
+//	@Description	Digest of upcoming events of followed organizations is sent by email once a week.
+//	@Security		APIKey
+//	@Produce		json
+//	@Tags			Me
+//	@Success		200	{object}	model.DigestSettings
+//	@Failure		401	{object}	HTTPError
+//	@Failure		500	{object}	HTTPError
+//	@Router			/me/digest [get]
+func (h *HTTPHandler) GetDigestSettings(ctx *fiber.Ctx) error {
+	user, _ := auth.GetAuth(ctx)
+
+	settings, err := h.ucase.GetDigestSettings(ctx.Context(), user.UserID)
+	if err != nil {
+		return WrapError(err)
+	}
+	return ReturnJson(ctx, settings)
+}
+
+// UpdateDigestSettings
+//
+//	@Summary	Updates weekly digest settings of current user
+//	@Security	APIKey
+//	@Accept		json
+//	@Produce	json
+//	@Tags		Me
+//	@Param		updates	body		model.DigestSettingsUpdate	true	"Fields that will be updated"
+//	@Success	200		{object}	model.DigestSettings
+//	@Failure	400		{object}	HTTPError
+//	@Failure	401		{object}	HTTPError
+//	@Failure	422		{object}	ValidationError
+//	@Failure	500		{object}	HTTPError
+//	@Router		/me/digest [patch]
+func (h *HTTPHandler) UpdateDigestSettings(ctx *fiber.Ctx) error {
+	user, _ := auth.GetAuth(ctx)
+	update, jerr := JsonParseAndValidate[model.DigestSettingsUpdate](ctx, validate)
+	if jerr != nil {
+		return jerr.AsFiberError(422)
+	}
+
+	settings, err := h.ucase.UpdateDigestSettings(ctx.Context(), user.UserID, update)
+	if err != nil {
+		return WrapError(err)
+	}
+	return ReturnJson(ctx, settings)
+}
@@ -0,0 +1,197 @@
+package handler
+
+import (
+	"github.com/burenotti/rtu-it-lab-recruit/handler/middlewares/auth"
+	"github.com/burenotti/rtu-it-lab-recruit/model"
+	"github.com/gofiber/fiber/v2"
+)
+
+// UpdateEvent
+//
+//	@Summary		Updates the event
+//	@Description	Available to organization members with rights to edit events.
+//	@Description	If the event time is changed, reminders are sent according to the new time.
+//	@Security		APIKey
+//	@Accept			json
+//	@Produce		json
+//	@Tags			Events
+//	@Param			event_id	path		int					true	"Event id"
+//	@Param			updates		body		model.EventUpdate	true	"Fields that will be updated"
+//	@Success		200			{object}	model.Event
+//	@Failure		400			{object}	HTTPError
+//	@Failure		404			{object}	HTTPError
+//	@Failure		422			{object}	ValidationError
+//	@Failure		500			{object}	HTTPError
+//	@Router			/event/{event_id} [patch]
+func (h *HTTPHandler) UpdateEvent(ctx *fiber.Ctx) error {
+	user, _ := auth.GetAuth(ctx)
+	eventId, err := getIdParam(ctx, "event_id")
+	if err != nil {
+		return err
+	}
+	update, jerr := JsonParseAndValidate[model.EventUpdate](ctx, validate)
+	if jerr != nil {
+		return jerr.AsFiberError(422)
+	}
+
+	event, err := h.ucase.UpdateEvent(ctx.Context(), user.UserID, eventId, update)
+	if err != nil {
+		return WrapError(err)
+	}
+	return ReturnJson(ctx, event)
+}
+
+// PublishEvent
+//
+//	@Summary		Publishes the event
+//	@Description	Makes the event visible to everyone. Followers of the organization are notified about it.
+//	@Security		APIKey
+//	@Produce		json
+//	@Tags			Events
+//	@Param			event_id	path		int	true	"Event id"
+//	@Success		200			{object}	model.Event
+//	@Failure		400			{object}	HTTPError
+//	@Failure		404			{object}	HTTPError
+//	@Failure		500			{object}	HTTPError
+//	@Router			/event/{event_id}/publish [post]
+func (h *HTTPHandler) PublishEvent(ctx *fiber.Ctx) error {
+	user, _ := auth.GetAuth(ctx)
+	eventId, err := getIdParam(ctx, "event_id")
+	if err != nil {
+		return err
+	}
+
+	event, err := h.ucase.PublishEvent(ctx.Context(), user.UserID, eventId)
+	if err != nil {
+		return WrapError(err)
+	}
+	return ReturnJson(ctx, event)
+}
+
+// DeleteEvent
+//
+//	@Summary		Deletes the event
+//	@Description	Available to organization members with rights to edit events.
+//	@Description	If someone has registered for the event, it is cancelled instead and registrants are notified.
+//	@Security		APIKey
+//	@Produce		json
+//	@Tags			Events
+//	@Param			event_id	path		int		true	"Event id"
+//	@Param			reason		query		string	false	"Reason of cancellation shown to registrants"
+//	@Success		200			{object}	model.EventDeleted
+//	@Failure		400			{object}	HTTPError
+//	@Failure		404			{object}	HTTPError
+//	@Failure		422			{object}	ValidationError
+//	@Failure		500			{object}	HTTPError
+//	@Router			/event/{event_id} [delete]
+func (h *HTTPHandler) DeleteEvent(ctx *fiber.Ctx) error {
+	user, _ := auth.GetAuth(ctx)
+	eventId, err := getIdParam(ctx, "event_id")
+	if err != nil {
+		return err
+	}
+	cancel, jerr := QueryParseAndValidate[model.EventCancel](ctx, validate)
+	if jerr != nil {
+		return jerr.AsFiberError(422)
+	}
+
+	cancelled, err := h.ucase.DeleteEvent(ctx.Context(), user.UserID, eventId, cancel)
+	if err != nil {
+		return WrapError(err)
+	}
+	return ReturnJson(ctx, &model.EventDeleted{Cancelled: cancelled})
+}
+
+// RegisterForEvent
+//
+//	@Summary		Registers current user for the event
+//	@Description	Registered users are reminded about the event before it begins.
+//	@Description	Users register for occurrences of recurring events, occurrence_id is required for them.
+//	@Security		APIKey
+//	@Produce		json
+//	@Tags			Events
+//	@Param			event_id	path		int							true	"Event id"
+//	@Param			target		query		model.RegistrationTarget	false	"Occurrence and traffic source"
+//	@Success		201			{object}	model.Registration
+//	@Failure		400			{object}	HTTPError
+//	@Failure		404			{object}	HTTPError
+//	@Failure		422			{object}	ValidationError
+//	@Failure		500			{object}	HTTPError
+//	@Router			/event/{event_id}/registration [post]
+func (h *HTTPHandler) RegisterForEvent(ctx *fiber.Ctx) error {
+	user, _ := auth.GetAuth(ctx)
+	eventId, err := getIdParam(ctx, "event_id")
+	if err != nil {
+		return err
+	}
+	target, jerr := QueryParseAndValidate[model.RegistrationTarget](ctx, validate)
+	if jerr != nil {
+		return jerr.AsFiberError(422)
+	}
+
+	registration, err := h.ucase.RegisterForEvent(ctx.Context(), user.UserID, eventId, target)
+	if err != nil {
+		return WrapError(err)
+	}
+	ctx.Status(fiber.StatusCreated)
+	return ReturnJson(ctx, registration)
+}
+
+// CancelRegistration
+//
+//	@Summary	Cancels registration of current user for the event
+//	@Security	APIKey
+//	@Produce	json
+//	@Tags		Events
+//	@Param		event_id	path	int							true	"Event id"
+//	@Param		target		query	model.RegistrationTarget	false	"Occurrence"
+//	@Success	204
+//	@Failure	404	{object}	HTTPError
+//	@Failure	422	{object}	ValidationError
+//	@Failure	500	{object}	HTTPError
+//	@Router		/event/{event_id}/registration [delete]
+func (h *HTTPHandler) CancelRegistration(ctx *fiber.Ctx) error {
+	user, _ := auth.GetAuth(ctx)
+	eventId, err := getIdParam(ctx, "event_id")
+	if err != nil {
+		return err
+	}
+	target, jerr := QueryParseAndValidate[model.RegistrationTarget](ctx, validate)
+	if jerr != nil {
+		return jerr.AsFiberError(422)
+	}
+
+	if err := h.ucase.CancelRegistration(ctx.Context(), user.UserID, eventId, target.OccurrenceID); err != nil {
+		return WrapError(err)
+	}
+	ctx.Status(fiber.StatusNoContent)
+	return nil
+}
+
+// SearchEvents
+//
+//	@Summary		Searches published upcoming events
+//	@Description	Events beginning within a year are returned ordered by the beginning.
+//	@Description	lat, lon and radius in meters select events at venues within the radius from the point.
+//	@Description	min_lat, min_lon, max_lat and max_lon select events at venues within the bounding box.
+//	@Description	category_id selects events of the category and its subcategories, tags select events having all the tags.
+//	@Produce		json
+//	@Tags			Events
+//	@Param			query	query		model.EventQuery	false	"Search conditions"
+//	@Success		200		{array}		model.Event
+//	@Failure		400		{object}	HTTPError
+//	@Failure		422		{object}	ValidationError
+//	@Failure		500		{object}	HTTPError
+//	@Router			/events [get]
+func (h *HTTPHandler) SearchEvents(ctx *fiber.Ctx) error {
+	query, jerr := QueryParseAndValidate[model.EventQuery](ctx, validate)
+	if jerr != nil {
+		return jerr.AsFiberError(422)
+	}
+
+	events, err := h.ucase.SearchEvents(ctx.Context(), query)
+	if err != nil {
+		return WrapError(err)
+	}
+	return ReturnJson(ctx, events)
+}
@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"github.com/burenotti/rtu-it-lab-recruit/handler/middlewares/auth"
+	"github.com/gofiber/fiber/v2"
+)
+
+// FollowOrganization
+//
+//	@Summary		Follows the organization
+//	@Description	Followers are notified when the organization publishes an event.
+//	@Security		APIKey
+//	@Produce		json
+//	@Tags			Organizations
+//	@Param			organization_id	path		int	true	"Organization id"
+//	@Success		201				{object}	model.Follow
+//	@Failure		400				{object}	HTTPError
+//	@Failure		404				{object}	HTTPError
+//	@Failure		500				{object}	HTTPError
+//	@Router			/organization/{organization_id}/follow [post]
+func (h *HTTPHandler) FollowOrganization(ctx *fiber.Ctx) error {
+	user, _ := auth.GetAuth(ctx)
+	orgId, err := getOrganizationId(ctx)
+	if err != nil {
+		return err
+	}
+
+	follow, err := h.ucase.FollowOrganization(ctx.Context(), user.UserID, orgId)
+	if err != nil {
+		return WrapError(err)
+	}
+	ctx.Status(fiber.StatusCreated)
+	return ReturnJson(ctx, follow)
+}
+
+// UnfollowOrganization
+//
+//	@Summary	Unfollows the organization
+//	@Security	APIKey
+//	@Produce	json
+//	@Tags		Organizations
+//	@Param		organization_id	path	int	true	"Organization id"
+//	@Success	204
+//	@Failure	404	{object}	HTTPError
+//	@Failure	500	{object}	HTTPError
+//	@Router		/organization/{organization_id}/follow [delete]
+func (h *HTTPHandler) UnfollowOrganization(ctx *fiber.Ctx) error {
+	user, _ := auth.GetAuth(ctx)
+	orgId, err := getOrganizationId(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := h.ucase.UnfollowOrganization(ctx.Context(), user.UserID, orgId); err != nil {
+		return WrapError(err)
+	}
+	ctx.Status(fiber.StatusNoContent)
+	return nil
+}
@@ -2,7 +2,9 @@ package handler
 
 import (
 	_ "github.com/burenotti/rtu-it-lab-recruit/docs"
+	"github.com/burenotti/rtu-it-lab-recruit/handler/middlewares/admin"
 	"github.com/burenotti/rtu-it-lab-recruit/handler/middlewares/auth"
+	"github.com/burenotti/rtu-it-lab-recruit/handler/middlewares/impersonation"
 	"github.com/burenotti/rtu-it-lab-recruit/handler/middlewares/logging"
 	"github.com/burenotti/rtu-it-lab-recruit/services"
 	"github.com/burenotti/rtu-it-lab-recruit/usecases"
@@ -11,6 +13,7 @@ import (
 	"github.com/gofiber/swagger"
 	"github.com/sirupsen/logrus"
 	"github.com/valyala/fasthttp"
+	"html/template"
 )
 
 // HTTPHandler
@@ -31,10 +34,19 @@ import (
 type HTTPHandler struct {
 	app   *fiber.App
 	ucase UseCases
+	cfg   *Config
 }
 
 type Config struct {
 	Name string
+	// ActivationRedirectURL is the frontend page activation links are redirected to.
+	// If it is empty, the confirmation page is rendered with ActivationPage template.
+	ActivationRedirectURL string
+	ActivationPage        *template.Template
+	// UnsubscribePage is the confirmation page of unsubscribe links sent in notifications.
+	UnsubscribePage *template.Template
+	// TelegramWebhookSecret authenticates updates sent by Telegram. Webhook is disabled if it is empty.
+	TelegramWebhookSecret string
 }
 
 type UseCases struct {
@@ -42,47 +54,200 @@ type UseCases struct {
 	usecases.EmailSignInUseCase
 	usecases.SignUpUseCase
 	usecases.OrganizationUseCase
+	usecases.PersonalDataUseCase
+	usecases.AdminUseCase
+	usecases.OutboxUseCase
+	usecases.ChannelsUseCase
+	usecases.EventUseCase
+	usecases.FollowUseCase
+	usecases.DigestUseCase
+	usecases.SearchUseCase
+	usecases.NotificationUseCase
+	usecases.PreferencesUseCase
+	usecases.WebhookUseCase
+	usecases.VenueUseCase
+	usecases.CategoryUseCase
+	usecases.CalendarUseCase
+	usecases.TicketUseCase
+	usecases.CheckInUseCase
+	usecases.AnalyticsUseCase
 }
 
 func New(logger *logrus.Logger, ucase UseCases, config *Config) *HTTPHandler {
 	app := fiber.New(fiber.Config{
 		AppName: config.Name,
 	})
-	app.Use(logging.New(logging.Config{Logger: logger}))
+	app.Use(logging.New(logging.Config{
+		Logger:          logger,
+		SensitiveParams: []string{"token"},
+	}))
 	app.Use(recover.New(recover.Config{}))
 	handler := &HTTPHandler{
 		ucase: ucase,
 		app:   app,
+		cfg:   config,
 	}
 	handler.Mount()
 	return handler
 }
 
 func (h *HTTPHandler) Mount() {
-	authRequired := auth.New(&h.ucase.AuthService)
+	authRequired := auth.New(&h.ucase.AuthService, &h.ucase.EmailSignInUseCase, &h.ucase.AdminUseCase)
+	// Impersonation is read-only, auditImpersonation rejects requests which may change anything.
+	auditImpersonation := impersonation.New(&h.ucase.AdminUseCase)
+	denyImpersonation := impersonation.Deny()
+	adminRequired := admin.New(&h.ucase.AdminUseCase)
 	h.app.Get("/docs/*", swagger.HandlerDefault)
 	auth := h.app.Group("/auth")
 	{
 		auth.Post("/sign-up", h.SignUp)
-		auth.Get("/activate/:token", h.ActivateWithToken)
+		auth.Post("/activate/resend", h.ResendActivation)
+		auth.Get("/activate/:token", h.ActivationLanding)
+		auth.Post("/activate/:token", h.ActivateWithToken)
 		auth.Post("/request", h.RequestEmailCode)
 		auth.Post("/sign-in", h.SignIn)
 	}
 
-	organizations := h.app.Group("/organization", authRequired)
+	me := h.app.Group("/me", authRequired, auditImpersonation)
+	{
+		me.Get("/", h.GetMe)
+		me.Patch("/", h.UpdateMe)
+		me.Delete("/", h.EraseMe)
+		me.Post("/export", h.RequestDataExport)
+		me.Get("/export/:export_id", denyImpersonation, h.GetDataExport)
+		me.Get("/export/:export_id/archive", denyImpersonation, h.DownloadDataExport)
+		me.Get("/channels", h.ListChannels)
+		me.Put("/channels/sms", h.LinkPhone)
+		me.Post("/channels/sms/confirm", h.ConfirmPhone)
+		me.Post("/channels/telegram", h.StartTelegramLink)
+		me.Patch("/channels/:channel", h.UpdateChannel)
+		me.Delete("/channels/:channel", h.UnlinkChannel)
+		me.Get("/digest", h.GetDigestSettings)
+		me.Patch("/digest", h.UpdateDigestSettings)
+		me.Post("/searches", h.CreateSearch)
+		me.Get("/searches", h.ListSearches)
+		me.Patch("/searches/:search_id", h.UpdateSearch)
+		me.Delete("/searches/:search_id", h.DeleteSearch)
+		me.Get("/searches/:search_id/events", h.RunSearch)
+		me.Get("/preferences", h.ListPreferences)
+		me.Patch("/preferences", h.UpdatePreferences)
+		me.Get("/notifications", h.ListNotifications)
+		me.Get("/notifications/stream", h.StreamNotifications)
+		me.Post("/notifications/read", h.MarkAllNotificationsRead)
+		me.Post("/notifications/:notification_id/read", h.MarkNotificationRead)
+		me.Post("/feeds", h.CreateCalendarFeed)
+		me.Get("/feeds", h.ListCalendarFeeds)
+		me.Delete("/feeds/:feed_id", h.DeleteCalendarFeed)
+		me.Get("/orders", h.ListMyOrders)
+		me.Get("/tickets/:registration_id.png", h.GetTicketQRCode)
+		me.Get("/tickets/:registration_id", h.GetTicket)
+	}
+
+	unsubscribe := h.app.Group("/unsubscribe")
+	{
+		unsubscribe.Get("/search/:token", h.SearchUnsubscribeLanding)
+		unsubscribe.Post("/search/:token", h.UnsubscribeSearch)
+		unsubscribe.Get("/:token", h.UnsubscribeLanding)
+		unsubscribe.Post("/:token", h.UnsubscribeCategory)
+	}
+
+	h.app.Post("/telegram/webhook", h.TelegramWebhook)
+	h.app.Get("/events", h.SearchEvents)
+	h.app.Get("/categories", h.ListCategories)
+	h.app.Get("/categories/counts", h.CountCategoryEvents)
+	h.app.Get("/tags", h.SuggestTags)
+	h.app.Get("/feeds/:token.ics", h.ExportCalendarFeed)
+	// Registered before the /event group, which requires authentication.
+	h.app.Get("/event/:event_id.ics", h.ExportEvent)
+
+	admin := h.app.Group("/admin", authRequired, auditImpersonation, denyImpersonation, adminRequired)
+	{
+		admin.Get("/users", h.SearchUsers)
+		admin.Post("/users/:user_id/impersonate", h.ImpersonateUser)
+		admin.Post("/users/:user_id/activate", h.AdminActivateUser)
+		admin.Post("/users/:user_id/deactivate", h.AdminDeactivateUser)
+		admin.Post("/users/:user_id/ban", h.BanUser)
+		admin.Post("/users/:user_id/unban", h.UnbanUser)
+		admin.Post("/organizations/:organization_id/transfer", h.TransferOwnership)
+		admin.Post("/events/:event_id/hide", h.HideEvent)
+		admin.Post("/events/:event_id/unhide", h.UnhideEvent)
+		admin.Get("/audit", h.ListAuditLog)
+		admin.Get("/outbox", h.ListOutboxMessages)
+		admin.Post("/outbox/:message_id/replay", h.ReplayOutboxMessage)
+		admin.Post("/categories", h.CreateCategory)
+		admin.Patch("/categories/:category_id", h.UpdateCategory)
+		admin.Delete("/categories/:category_id", h.DeleteCategory)
+	}
+
+	organizations := h.app.Group("/organization", authRequired, auditImpersonation)
 	{
 		organizations.Post("/", h.CreateOrganization)
 		organizations.Get("/:organization_id", h.GetOrganization)
 		organizations.Patch("/:organization_id", h.UpdateOrganization)
 		organizations.Delete("/:organization_id", h.DeleteOrganization)
+		organizations.Post("/:organization_id/follow", h.FollowOrganization)
+		organizations.Delete("/:organization_id/follow", h.UnfollowOrganization)
+		organizations.Post("/:organization_id/venues", h.CreateVenue)
+		organizations.Get("/:organization_id/venues", h.ListVenues)
+		organizations.Post("/:organization_id/imports", h.ImportEvents)
+		organizations.Get("/:organization_id/imports/:import_id", h.GetEventImport)
+		organizations.Get("/:organization_id/analytics", h.GetOrganizationAnalytics)
+		organizations.Get("/:organization_id/analytics.csv", h.ExportOrganizationAnalytics)
 	}
-	invites := h.app.Group("/organization/:organization_id/invite", authRequired)
+	venues := h.app.Group("/venue", authRequired, auditImpersonation)
+	{
+		venues.Get("/:venue_id", h.GetVenue)
+		venues.Patch("/:venue_id", h.UpdateVenue)
+		venues.Delete("/:venue_id", h.DeleteVenue)
+	}
+	events := h.app.Group("/event", authRequired, auditImpersonation)
+	{
+		events.Patch("/:event_id", h.UpdateEvent)
+		events.Delete("/:event_id", h.DeleteEvent)
+		events.Post("/:event_id/publish", h.PublishEvent)
+		events.Post("/:event_id/registration", h.RegisterForEvent)
+		events.Delete("/:event_id/registration", h.CancelRegistration)
+		events.Get("/:event_id/occurrences", h.ListOccurrences)
+		events.Patch("/:event_id/occurrences/:occurrence_id", h.UpdateOccurrence)
+		events.Delete("/:event_id/occurrences/:occurrence_id", h.CancelOccurrence)
+		events.Post("/:event_id/ticket-types", h.CreateTicketType)
+		events.Get("/:event_id/ticket-types", h.ListTicketTypes)
+		events.Post("/:event_id/promo-codes", h.CreatePromoCode)
+		events.Get("/:event_id/promo-codes", h.ListPromoCodes)
+		events.Delete("/:event_id/promo-codes/:promo_code_id", h.DeletePromoCode)
+		events.Post("/:event_id/orders", h.CreateOrder)
+		events.Post("/:event_id/check-ins", h.CheckIn)
+		events.Post("/:event_id/check-ins/sync", h.SyncCheckIns)
+		events.Get("/:event_id/attendees", h.ListAttendees)
+		events.Get("/:event_id/analytics", h.GetEventAnalytics)
+		events.Get("/:event_id/analytics.csv", h.ExportEventAnalytics)
+	}
+	ticketTypes := h.app.Group("/ticket-type", authRequired, auditImpersonation)
+	{
+		ticketTypes.Patch("/:ticket_type_id", h.UpdateTicketType)
+		ticketTypes.Delete("/:ticket_type_id", h.DeleteTicketType)
+	}
+	orders := h.app.Group("/order", authRequired, auditImpersonation)
+	{
+		orders.Get("/:order_id", h.GetOrder)
+		orders.Post("/:order_id/confirm", h.ConfirmOrder)
+		orders.Post("/:order_id/cancel", h.CancelOrder)
+	}
+	invites := h.app.Group("/organization/:organization_id/invite", authRequired, auditImpersonation)
 	{
 		invites.Post("/", h.InviteToOrganization)
 		invites.Get("/", h.ListInvites)
 		invites.Post("/:invite_id/accept", h.AcceptInvite)
 		invites.Post("/:invite_id/reject", h.RejectInvite)
 	}
+	webhooks := h.app.Group("/organization/:organization_id/webhooks", authRequired, auditImpersonation)
+	{
+		webhooks.Post("/", h.CreateWebhook)
+		webhooks.Get("/", h.ListWebhooks)
+		webhooks.Patch("/:webhook_id", h.UpdateWebhook)
+		webhooks.Delete("/:webhook_id", h.DeleteWebhook)
+		webhooks.Get("/:webhook_id/deliveries", h.ListWebhookDeliveries)
+	}
 }
 
 func (h *HTTPHandler) Handler() fasthttp.RequestHandler {
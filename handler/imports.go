@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"github.com/burenotti/rtu-it-lab-recruit/handler/middlewares/auth"
+	"github.com/burenotti/rtu-it-lab-recruit/model"
+	"github.com/gofiber/fiber/v2"
+)
+
+// ImportEvents
+//
+//	@Summary		Imports events from iCalendar or CSV file
+//	@Description	The file is the request body. Events are matched by UIDs: new ones are created unpublished,
+//	@Description	existing ones are updated and events cancelled in the file are cancelled, so files can be imported again.
+//	@Description	CSV must have the header with columns uid, name, begins_at and ends_at, optional columns are
+//	@Description	description, timezone, online_url, recurrence_rule, tags (separated by commas) and status.
+//	@Description	Times are in RFC 3339 or "2006-01-02 15:04" in the timezone of the row.
+//	@Description	With dry_run the file is validated and the report of what would be done with each row is returned.
+//	@Description	Otherwise the import runs in background, its progress is returned by the import endpoint.
+//	@Security		APIKey
+//	@Accept			text/calendar,text/csv
+//	@Produce		json
+//	@Tags			Events
+//	@Param			organization_id	path		int						true	"Organization id"
+//	@Param			query			query		model.EventImportQuery	true	"File format and options"
+//	@Success		200				{object}	model.EventImportReport
+//	@Success		202				{object}	model.EventImport
+//	@Failure		400				{object}	HTTPError
+//	@Failure		422				{object}	ValidationError
+//	@Failure		500				{object}	HTTPError
+//	@Router			/organization/{organization_id}/imports [post]
+func (h *HTTPHandler) ImportEvents(ctx *fiber.Ctx) error {
+	user, _ := auth.GetAuth(ctx)
+	orgId, err := getIdParam(ctx, "organization_id")
+	if err != nil {
+		return err
+	}
+	query, jerr := QueryParseAndValidate[model.EventImportQuery](ctx, validate)
+	if jerr != nil {
+		return jerr.AsFiberError(422)
+	}
+
+	if query.DryRun {
+		report, err := h.ucase.CheckImport(ctx.Context(), user.UserID, orgId, query, ctx.Body())
+		if err != nil {
+			return WrapError(err)
+		}
+		return ReturnJson(ctx, report)
+	}
+	i, err := h.ucase.ImportEvents(ctx.Context(), user.UserID, orgId, query, ctx.Body())
+	if err != nil {
+		return WrapError(err)
+	}
+	ctx.Status(fiber.StatusAccepted)
+	return ReturnJson(ctx, i)
+}
+
+// GetEventImport
+//
+//	@Summary		Returns import of events
+//	@Description	Running imports have the progress, completed ones have the report.
+//	@Security		APIKey
+//	@Produce		json
+//	@Tags			Events
+//	@Param			organization_id	path		int	true	"Organization id"
+//	@Param			import_id		path		int	true	"Import id"
+//	@Success		200				{object}	model.EventImport
+//	@Failure		400				{object}	HTTPError
+//	@Failure		404				{object}	HTTPError
+//	@Failure		500				{object}	HTTPError
+//	@Router			/organization/{organization_id}/imports/{import_id} [get]
+func (h *HTTPHandler) GetEventImport(ctx *fiber.Ctx) error {
+	user, _ := auth.GetAuth(ctx)
+	orgId, err := getIdParam(ctx, "organization_id")
+	if err != nil {
+		return err
+	}
+	importId, err := getIdParam(ctx, "import_id")
+	if err != nil {
+		return err
+	}
+
+	i, err := h.ucase.GetEventImport(ctx.Context(), user.UserID, orgId, importId)
+	if err != nil {
+		return WrapError(err)
+	}
+	return ReturnJson(ctx, i)
+}
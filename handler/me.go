@@ -0,0 +1,183 @@
+package handler
+
+import (
+	"fmt"
+	"github.com/burenotti/rtu-it-lab-recruit/handler/middlewares/auth"
+	"github.com/burenotti/rtu-it-lab-recruit/model"
+	"github.com/gofiber/fiber/v2"
+)
+
+// GetMe
+//
+//	@Summary		Returns current user
+//	@Description	If the request is made with impersonation token, impersonated is true
+//	@Description	and impersonated_by contains the administrator acting as the user.
+//	@Security		APIKey
+//	@Produce		json
+//	@Tags			Me
+//	@Success		200	{object}	model.Me
+//	@Failure		401	{object}	HTTPError
+//	@Failure		500	{object}	HTTPError
+//	@Router			/me [get]
+func (h *HTTPHandler) GetMe(ctx *fiber.Ctx) error {
+	payload, _ := auth.GetAuth(ctx)
+
+	user, err := h.ucase.GetProfile(ctx.Context(), payload.UserID)
+	if err != nil {
+		return WrapError(err)
+	}
+	return ReturnJson(ctx, &model.Me{
+		UserGet:        *userGet(user),
+		Impersonated:   payload.IsImpersonated(),
+		ImpersonatedBy: payload.Act,
+	})
+}
+
+// UpdateMe
+//
+//	@Summary	Updates settings of current user
+//	@Security	APIKey
+//	@Accept		json
+//	@Produce	json
+//	@Tags		Me
+//	@Param		updates	body		model.MeUpdate	true	"Fields that will be updated"
+//	@Success	200		{object}	model.UserGet
+//	@Failure	401		{object}	HTTPError
+//	@Failure	422		{object}	ValidationError
+//	@Failure	500		{object}	HTTPError
+//	@Router		/me [patch]
+func (h *HTTPHandler) UpdateMe(ctx *fiber.Ctx) error {
+	payload, _ := auth.GetAuth(ctx)
+	update, jerr := JsonParseAndValidate[model.MeUpdate](ctx, validate)
+	if jerr != nil {
+		return jerr.AsFiberError(422)
+	}
+
+	user, err := h.ucase.UpdateProfile(ctx.Context(), payload.UserID, update)
+	if err != nil {
+		return WrapError(err)
+	}
+	return ReturnJson(ctx, userGet(user))
+}
+
+// RequestDataExport
+//
+//	@Summary		Requests an archive with all personal data of current user
+//	@Description	Archive is generated asynchronously. Poll the export until its status is ready.
+//	@Security		APIKey
+//	@Produce		json
+//	@Tags			Me
+//	@Success		202	{object}	model.DataExportGet
+//	@Failure		401	{object}	HTTPError
+//	@Failure		500	{object}	HTTPError
+//	@Router			/me/export [post]
+func (h *HTTPHandler) RequestDataExport(ctx *fiber.Ctx) error {
+	user, _ := auth.GetAuth(ctx)
+
+	export, err := h.ucase.RequestExport(ctx.Context(), user.UserID)
+	if err != nil {
+		return WrapError(err)
+	}
+	ctx.Status(fiber.StatusAccepted)
+	return ReturnJson(ctx, dataExportGet(export))
+}
+
+// GetDataExport
+//
+//	@Summary	Returns status of personal data export
+//	@Security	APIKey
+//	@Produce	json
+//	@Tags		Me
+//	@Param		export_id	path		int	true	"Export id"
+//	@Success	200			{object}	model.DataExportGet
+//	@Failure	404			{object}	HTTPError
+//	@Failure	422			{object}	HTTPError
+//	@Failure	500			{object}	HTTPError
+//	@Router		/me/export/{export_id} [get]
+func (h *HTTPHandler) GetDataExport(ctx *fiber.Ctx) error {
+	user, _ := auth.GetAuth(ctx)
+	exportId, err := getIdParam(ctx, "export_id")
+	if err != nil {
+		return err
+	}
+
+	export, err := h.ucase.GetExport(ctx.Context(), user.UserID, exportId)
+	if err != nil {
+		return WrapError(err)
+	}
+	return ReturnJson(ctx, dataExportGet(export))
+}
+
+// DownloadDataExport
+//
+//	@Summary	Downloads zip archive with personal data
+//	@Security	APIKey
+//	@Produce	application/zip
+//	@Tags		Me
+//	@Param		export_id	path	int	true	"Export id"
+//	@Success	200
+//	@Failure	404	{object}	HTTPError
+//	@Failure	422	{object}	HTTPError
+//	@Failure	500	{object}	HTTPError
+//	@Router		/me/export/{export_id}/archive [get]
+func (h *HTTPHandler) DownloadDataExport(ctx *fiber.Ctx) error {
+	user, _ := auth.GetAuth(ctx)
+	exportId, err := getIdParam(ctx, "export_id")
+	if err != nil {
+		return err
+	}
+
+	archive, err := h.ucase.DownloadExport(ctx.Context(), user.UserID, exportId)
+	if err != nil {
+		return WrapError(err)
+	}
+	ctx.Attachment(fmt.Sprintf("personal-data-%d.zip", exportId))
+	ctx.Type("zip")
+	return ctx.Send(archive)
+}
+
+// EraseMe
+//
+//	@Summary		Erases personal data of current user
+//	@Description	User is anonymized, so events created by the user are kept.
+//	@Description	Owners must transfer or delete their organizations first.
+//	@Description	Not available during impersonation.
+//	@Security		APIKey
+//	@Produce		json
+//	@Tags			Me
+//	@Success		204
+//	@Failure		400	{object}	HTTPError
+//	@Failure		403	{object}	HTTPError
+//	@Failure		500	{object}	HTTPError
+//	@Router			/me [delete]
+func (h *HTTPHandler) EraseMe(ctx *fiber.Ctx) error {
+	user, _ := auth.GetAuth(ctx)
+
+	if err := h.ucase.EraseUser(ctx.Context(), user.UserID); err != nil {
+		return WrapError(err)
+	}
+	ctx.Status(fiber.StatusNoContent)
+	return nil
+}
+
+func userGet(u *model.User) *model.UserGet {
+	return &model.UserGet{
+		UserID:          u.UserID,
+		FirstName:       u.FirstName,
+		LastName:        u.LastName,
+		MiddleName:      u.MiddleName,
+		Email:           u.Email,
+		IsActive:        u.IsActive,
+		PreferredLocale: u.PreferredLocale,
+	}
+}
+
+func dataExportGet(e *model.DataExport) *model.DataExportGet {
+	return &model.DataExportGet{
+		ExportID:   e.ExportID,
+		Status:     e.Status,
+		Error:      e.Error,
+		CreatedAt:  e.CreatedAt,
+		FinishedAt: e.FinishedAt,
+	}
+}
@@ -0,0 +1,37 @@
+package admin
+
+import (
+	"context"
+	"github.com/burenotti/rtu-it-lab-recruit/handler/middlewares/auth"
+	"github.com/gofiber/fiber/v2"
+)
+
+type Checker interface {
+	IsAdmin(ctx context.Context, userId int64) (bool, error)
+}
+
+// Middleware rejects requests of users without platform administrator role.
+// It must be mounted after auth middleware.
+type Middleware struct {
+	checker Checker
+}
+
+func New(checker Checker) fiber.Handler {
+	m := Middleware{checker: checker}
+	return m.Call
+}
+
+func (m *Middleware) Call(ctx *fiber.Ctx) error {
+	user, ok := auth.GetAuth(ctx)
+	if !ok {
+		return fiber.NewError(fiber.StatusUnauthorized, "Not authenticated")
+	}
+	isAdmin, err := m.checker.IsAdmin(ctx.Context(), user.UserID)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+	if !isAdmin {
+		return fiber.NewError(fiber.StatusForbidden, "Platform administrator role required")
+	}
+	return ctx.Next()
+}
@@ -15,12 +15,24 @@ var (
 
 const UserCtxKey = "user_payload"
 
+// Checker tells whether the user is still allowed in, e.g. is not banned or erased since the token was issued.
+type Checker interface {
+	CanSignIn(ctx context.Context, userId int64) (bool, error)
+}
+
+// ActorChecker tells whether the administrator is still allowed to act as other users.
+type ActorChecker interface {
+	IsAdmin(ctx context.Context, userId int64) (bool, error)
+}
+
 type Middleware struct {
 	authService *services.AuthService
+	checker     Checker
+	actors      ActorChecker
 }
 
-func New(auth *services.AuthService) fiber.Handler {
-	m := Middleware{authService: auth}
+func New(auth *services.AuthService, checker Checker, actors ActorChecker) fiber.Handler {
+	m := Middleware{authService: auth, checker: checker, actors: actors}
 	return m.Call
 }
 
@@ -35,10 +47,36 @@ func (m *Middleware) Call(ctx *fiber.Ctx) error {
 		ctx.Set("WWW-Authenticate", "Bearer")
 		return fiber.NewError(fiber.StatusUnauthorized, err.Error())
 	}
-	ctx.SetUserContext(context.WithValue(ctx.Context(), UserCtxKey, payload))
+	allowed, err := m.checker.CanSignIn(ctx.Context(), payload.UserID)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+	if !allowed {
+		ctx.Set("WWW-Authenticate", "Bearer")
+		return fiber.NewError(fiber.StatusUnauthorized, "Account is disabled")
+	}
+	if payload.IsImpersonated() {
+		// Impersonation token is revoked as soon as the administrator is banned, erased or demoted.
+		if allowed, err = m.actorAllowed(ctx.Context(), payload.Act.UserID); err != nil {
+			return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+		}
+		if !allowed {
+			ctx.Set("WWW-Authenticate", "Bearer")
+			return fiber.NewError(fiber.StatusUnauthorized, "Impersonation is revoked")
+		}
+	}
+	ctx.Locals(UserCtxKey, payload)
 	return ctx.Next()
 }
 
+func (m *Middleware) actorAllowed(ctx context.Context, actorId int64) (bool, error) {
+	allowed, err := m.checker.CanSignIn(ctx, actorId)
+	if err != nil || !allowed {
+		return false, err
+	}
+	return m.actors.IsAdmin(ctx, actorId)
+}
+
 func GetToken(ctx *fiber.Ctx) (scheme string, token string, err error) {
 	header := ctx.Get("Authorization")
 
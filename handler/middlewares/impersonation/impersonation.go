@@ -0,0 +1,55 @@
+package impersonation
+
+import (
+	"context"
+	"github.com/burenotti/rtu-it-lab-recruit/handler/middlewares/auth"
+	"github.com/gofiber/fiber/v2"
+)
+
+type Recorder interface {
+	RecordImpersonatedRequest(ctx context.Context, actorId, userId int64, method, path string) error
+}
+
+// Middleware records every request made with impersonation token to audit log.
+// Request is rejected if it can't be recorded. Impersonation is read-only, so requests
+// which may change anything are rejected after they are recorded. It must be mounted after auth middleware.
+type Middleware struct {
+	recorder Recorder
+}
+
+func New(recorder Recorder) fiber.Handler {
+	m := Middleware{recorder: recorder}
+	return m.Call
+}
+
+func (m *Middleware) Call(ctx *fiber.Ctx) error {
+	user, ok := auth.GetAuth(ctx)
+	if !ok || !user.IsImpersonated() {
+		return ctx.Next()
+	}
+	err := m.recorder.RecordImpersonatedRequest(ctx.Context(), user.Act.UserID, user.UserID, ctx.Method(), ctx.Path())
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "Can't record impersonated request")
+	}
+	if !isReadOnly(ctx.Method()) {
+		return errDenied
+	}
+	return ctx.Next()
+}
+
+var errDenied = fiber.NewError(fiber.StatusForbidden, "Action is not available during impersonation")
+
+func isReadOnly(method string) bool {
+	return method == fiber.MethodGet || method == fiber.MethodHead || method == fiber.MethodOptions
+}
+
+// Deny rejects requests made with impersonation token. It protects reads which must not be available
+// to administrators, e.g. downloads of personal data, other requests are rejected by the middleware anyway.
+func Deny() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		if user, ok := auth.GetAuth(ctx); ok && user.IsImpersonated() {
+			return errDenied
+		}
+		return ctx.Next()
+	}
+}
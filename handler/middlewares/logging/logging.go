@@ -8,21 +8,26 @@ import (
 
 type Config struct {
 	Logger *logrus.Logger
+	// SensitiveParams are route parameters which must not get to logs, like activation tokens.
+	// Requests to routes with such parameters are logged with route pattern instead of path.
+	SensitiveParams []string
 }
 
 type Middleware struct {
-	Logger *logrus.Logger
+	Logger          *logrus.Logger
+	SensitiveParams []string
 }
 
 func (m *Middleware) Call(ctx *fiber.Ctx) error {
+	start := time.Now().UTC()
+	err := ctx.Next()
+
 	log := m.Logger.
-		WithField("time", time.Now().UTC()).
+		WithField("time", start).
 		WithField("method", ctx.Method()).
-		WithField("path", ctx.Path())
-
-	err := ctx.Next()
+		WithField("path", m.path(ctx))
 	if err != nil {
-		log = m.Logger.WithError(err)
+		log = log.WithError(err)
 	}
 	log.WithField("status", ctx.Response().StatusCode()).
 		WithField("execution_time", time.Now().UTC()).
@@ -31,9 +36,22 @@ func (m *Middleware) Call(ctx *fiber.Ctx) error {
 	return err
 }
 
+func (m *Middleware) path(ctx *fiber.Ctx) string {
+	route := ctx.Route()
+	for _, param := range route.Params {
+		for _, sensitive := range m.SensitiveParams {
+			if param == sensitive {
+				return route.Path
+			}
+		}
+	}
+	return ctx.Path()
+}
+
 func New(cfg Config) fiber.Handler {
 	m := Middleware{
-		Logger: cfg.Logger,
+		Logger:          cfg.Logger,
+		SensitiveParams: cfg.SensitiveParams,
 	}
 	return m.Call
 }
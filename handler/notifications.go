@@ -0,0 +1,193 @@
+package handler
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/burenotti/rtu-it-lab-recruit/handler/middlewares/auth"
+	"github.com/burenotti/rtu-it-lab-recruit/model"
+	"github.com/gofiber/fiber/v2"
+	"strconv"
+	"time"
+)
+
+const (
+	// notificationKeepAlive is the interval of comments sent to idle streams, so proxies don't close them.
+	// Streams also check for new notifications on keep-alive, in case a signal was lost.
+	notificationKeepAlive = 20 * time.Second
+	// notificationStreamLifetime limits the stream duration, clients reconnect with Last-Event-ID afterwards.
+	// It must be less than the write timeout of streaming requests.
+	notificationStreamLifetime = 30 * time.Minute
+	// notificationRetry is the reconnection delay suggested to clients, in milliseconds.
+	notificationRetry = 3000
+)
+
+// ListNotifications
+//
+//	@Summary		Returns notifications of current user
+//	@Description	Notifications are ordered from the newest. Unread is the number of all unread notifications.
+//	@Security		APIKey
+//	@Produce		json
+//	@Tags			Notifications
+//	@Param			filter	query		model.NotificationFilter	false	"Filter"
+//	@Success		200		{object}	model.NotificationList
+//	@Failure		401		{object}	HTTPError
+//	@Failure		422		{object}	ValidationError
+//	@Failure		500		{object}	HTTPError
+//	@Router			/me/notifications [get]
+func (h *HTTPHandler) ListNotifications(ctx *fiber.Ctx) error {
+	user, _ := auth.GetAuth(ctx)
+	filter, jerr := QueryParseAndValidate[model.NotificationFilter](ctx, validate)
+	if jerr != nil {
+		return jerr.AsFiberError(422)
+	}
+
+	notifications, err := h.ucase.ListNotifications(ctx.Context(), user.UserID, filter)
+	if err != nil {
+		return WrapError(err)
+	}
+	return ReturnJson(ctx, notifications)
+}
+
+// MarkNotificationRead
+//
+//	@Summary	Marks notification as read
+//	@Security	APIKey
+//	@Produce	json
+//	@Tags		Notifications
+//	@Param		notification_id	path		int	true	"Notification id"
+//	@Success	200				{object}	model.Notification
+//	@Failure	404				{object}	HTTPError
+//	@Failure	500				{object}	HTTPError
+//	@Router		/me/notifications/{notification_id}/read [post]
+func (h *HTTPHandler) MarkNotificationRead(ctx *fiber.Ctx) error {
+	user, _ := auth.GetAuth(ctx)
+	notificationId, err := getIdParam(ctx, "notification_id")
+	if err != nil {
+		return err
+	}
+
+	notification, err := h.ucase.MarkNotificationRead(ctx.Context(), user.UserID, notificationId)
+	if err != nil {
+		return WrapError(err)
+	}
+	return ReturnJson(ctx, notification)
+}
+
+// MarkAllNotificationsRead
+//
+//	@Summary	Marks all notifications of current user as read
+//	@Security	APIKey
+//	@Produce	json
+//	@Tags		Notifications
+//	@Success	200	{object}	model.NotificationsRead
+//	@Failure	401	{object}	HTTPError
+//	@Failure	500	{object}	HTTPError
+//	@Router		/me/notifications/read [post]
+func (h *HTTPHandler) MarkAllNotificationsRead(ctx *fiber.Ctx) error {
+	user, _ := auth.GetAuth(ctx)
+
+	read, err := h.ucase.MarkAllNotificationsRead(ctx.Context(), user.UserID)
+	if err != nil {
+		return WrapError(err)
+	}
+	return ReturnJson(ctx, read)
+}
+
+// StreamNotifications
+//
+//	@Summary		Streams new notifications of current user
+//	@Description	Server-Sent Events stream, each event is a notification with its id as the event id.
+//	@Description	Clients reconnecting with Last-Event-ID header receive notifications they have missed.
+//	@Description	Without the header only notifications created after connecting are sent.
+//	@Description	Requests must accept text/event-stream, as EventSource does, otherwise the stream is cut by write timeout.
+//	@Security		APIKey
+//	@Produce		text/event-stream
+//	@Tags			Notifications
+//	@Param			Last-Event-ID	header		int	false	"Id of the last received notification"
+//	@Success		200				{object}	model.Notification
+//	@Failure		400				{object}	HTTPError
+//	@Failure		401				{object}	HTTPError
+//	@Failure		500				{object}	HTTPError
+//	@Router			/me/notifications/stream [get]
+func (h *HTTPHandler) StreamNotifications(ctx *fiber.Ctx) error {
+	user, _ := auth.GetAuth(ctx)
+	userId := user.UserID
+
+	var lastId int64
+	if header := ctx.Get("Last-Event-ID"); header != "" {
+		id, err := strconv.ParseInt(header, 10, 64)
+		if err != nil || id < 0 {
+			return NewHTTPError("Last-Event-ID must be a notification id").AsFiberError(fiber.StatusBadRequest)
+		}
+		lastId = id
+	} else {
+		id, err := h.ucase.LatestNotificationID(ctx.Context(), userId)
+		if err != nil {
+			return WrapError(err)
+		}
+		lastId = id
+	}
+
+	ctx.Set(fiber.HeaderContentType, "text/event-stream")
+	ctx.Set(fiber.HeaderCacheControl, "no-cache")
+	ctx.Set(fiber.HeaderConnection, "keep-alive")
+	ctx.Set("X-Accel-Buffering", "no")
+	ctx.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		// The stream subscribes before fetching notifications, so ones created in between are not missed.
+		signals, unsubscribe := h.ucase.SubscribeNotifications(userId)
+		defer unsubscribe()
+		keepAlive := time.NewTicker(notificationKeepAlive)
+		defer keepAlive.Stop()
+		lifetime := time.NewTimer(notificationStreamLifetime)
+		defer lifetime.Stop()
+
+		_, _ = fmt.Fprintf(w, "retry: %d\n\n", notificationRetry)
+		for {
+			if err := h.writeNotificationsAfter(w, userId, &lastId); err != nil {
+				return
+			}
+
+			select {
+			case _, ok := <-signals:
+				if !ok {
+					return
+				}
+			case <-keepAlive.C:
+				_, _ = w.WriteString(": keep-alive\n\n")
+			case <-lifetime.C:
+				return
+			}
+		}
+	})
+	return nil
+}
+
+// writeNotificationsAfter sends all notifications created after lastId and advances it.
+// Failed fetches are not fatal, the stream retries them on the next signal or keep-alive.
+func (h *HTTPHandler) writeNotificationsAfter(w *bufio.Writer, userId int64, lastId *int64) error {
+	for {
+		// The request context is released once the handler returns, so the stream uses its own.
+		notifications, err := h.ucase.NotificationsAfter(context.Background(), userId, *lastId)
+		if err != nil || len(notifications) == 0 {
+			break
+		}
+		for i := range notifications {
+			if err = writeNotificationEvent(w, &notifications[i]); err != nil {
+				return err
+			}
+			*lastId = notifications[i].NotificationID
+		}
+	}
+	return w.Flush()
+}
+
+func writeNotificationEvent(w *bufio.Writer, n *model.Notification) error {
+	data, err := json.Marshal(n)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: notification\ndata: %s\n\n", n.NotificationID, data)
+	return err
+}
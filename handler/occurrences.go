@@ -0,0 +1,120 @@
+package handler
+
+import (
+	"github.com/burenotti/rtu-it-lab-recruit/handler/middlewares/auth"
+	"github.com/burenotti/rtu-it-lab-recruit/model"
+	"github.com/gofiber/fiber/v2"
+)
+
+// ListOccurrences
+//
+//	@Summary		Returns occurrences of recurring event
+//	@Description	Occurrences are materialized for a limited period ahead. By default, upcoming occurrences
+//	@Description	within 90 days are returned. Cancelled occurrences are returned with cancelled_at set.
+//	@Security		APIKey
+//	@Produce		json
+//	@Tags			Events
+//	@Param			event_id	path		int						true	"Event id"
+//	@Param			query		query		model.OccurrenceQuery	false	"Window"
+//	@Success		200			{array}		model.Occurrence
+//	@Failure		400			{object}	HTTPError
+//	@Failure		404			{object}	HTTPError
+//	@Failure		422			{object}	ValidationError
+//	@Failure		500			{object}	HTTPError
+//	@Router			/event/{event_id}/occurrences [get]
+func (h *HTTPHandler) ListOccurrences(ctx *fiber.Ctx) error {
+	user, _ := auth.GetAuth(ctx)
+	eventId, err := getIdParam(ctx, "event_id")
+	if err != nil {
+		return err
+	}
+	query, jerr := QueryParseAndValidate[model.OccurrenceQuery](ctx, validate)
+	if jerr != nil {
+		return jerr.AsFiberError(422)
+	}
+
+	occurrences, err := h.ucase.ListOccurrences(ctx.Context(), user.UserID, eventId, query)
+	if err != nil {
+		return WrapError(err)
+	}
+	return ReturnJson(ctx, occurrences)
+}
+
+// UpdateOccurrence
+//
+//	@Summary		Updates occurrence of recurring event
+//	@Description	Scope "this" (default) changes only the occurrence. Scope "following" splits the series,
+//	@Description	the occurrence begins the new series with all following occurrences and their registrations.
+//	@Description	Scope "all" changes the whole series. Time changes of following occurrences or the series
+//	@Description	shift them by the difference with the occurrence's time.
+//	@Security		APIKey
+//	@Accept			json
+//	@Produce		json
+//	@Tags			Events
+//	@Param			event_id		path		int							true	"Event id"
+//	@Param			occurrence_id	path		int							true	"Occurrence id"
+//	@Param			scope			query		model.OccurrenceScopeQuery	false	"Scope"
+//	@Param			updates			body		model.OccurrenceUpdate		true	"Fields that will be updated"
+//	@Success		200				{object}	model.Occurrence
+//	@Failure		400				{object}	HTTPError
+//	@Failure		404				{object}	HTTPError
+//	@Failure		422				{object}	ValidationError
+//	@Failure		500				{object}	HTTPError
+//	@Router			/event/{event_id}/occurrences/{occurrence_id} [patch]
+func (h *HTTPHandler) UpdateOccurrence(ctx *fiber.Ctx) error {
+	user, _ := auth.GetAuth(ctx)
+	eventId, err := getIdParam(ctx, "event_id")
+	if err != nil {
+		return err
+	}
+	occurrenceId, err := getIdParam(ctx, "occurrence_id")
+	if err != nil {
+		return err
+	}
+	scope, jerr := QueryParseAndValidate[model.OccurrenceScopeQuery](ctx, validate)
+	if jerr != nil {
+		return jerr.AsFiberError(422)
+	}
+	update, jerr := JsonParseAndValidate[model.OccurrenceUpdate](ctx, validate)
+	if jerr != nil {
+		return jerr.AsFiberError(422)
+	}
+
+	occurrence, err := h.ucase.UpdateOccurrence(ctx.Context(), user.UserID, eventId, occurrenceId, scope.Scope, update)
+	if err != nil {
+		return WrapError(err)
+	}
+	return ReturnJson(ctx, occurrence)
+}
+
+// CancelOccurrence
+//
+//	@Summary		Cancels occurrence of recurring event
+//	@Description	The occurrence is excluded from the series, its registrants are notified.
+//	@Security		APIKey
+//	@Produce		json
+//	@Tags			Events
+//	@Param			event_id		path		int	true	"Event id"
+//	@Param			occurrence_id	path		int	true	"Occurrence id"
+//	@Success		200				{object}	model.Occurrence
+//	@Failure		400				{object}	HTTPError
+//	@Failure		404				{object}	HTTPError
+//	@Failure		500				{object}	HTTPError
+//	@Router			/event/{event_id}/occurrences/{occurrence_id} [delete]
+func (h *HTTPHandler) CancelOccurrence(ctx *fiber.Ctx) error {
+	user, _ := auth.GetAuth(ctx)
+	eventId, err := getIdParam(ctx, "event_id")
+	if err != nil {
+		return err
+	}
+	occurrenceId, err := getIdParam(ctx, "occurrence_id")
+	if err != nil {
+		return err
+	}
+
+	occurrence, err := h.ucase.CancelOccurrence(ctx.Context(), user.UserID, eventId, occurrenceId)
+	if err != nil {
+		return WrapError(err)
+	}
+	return ReturnJson(ctx, occurrence)
+}
@@ -92,6 +92,7 @@ func (h *HTTPHandler) UpdateOrganization(ctx *fiber.Ctx) error {
 //	@Tags		Organizations
 //	@Param		organization_id	path		int	true	"Organization id"
 //	@Failure	400				{object}	HTTPError
+//	@Failure	403				{object}	HTTPError
 //	@Failure	500				{object}	HTTPError
 //	@Router		/organization/{organization_id} [delete]
 func (h *HTTPHandler) DeleteOrganization(ctx *fiber.Ctx) error {
@@ -109,14 +110,18 @@ func (h *HTTPHandler) DeleteOrganization(ctx *fiber.Ctx) error {
 }
 
 func getOrganizationId(ctx *fiber.Ctx) (int64, error) {
-	orgIdRaw := ctx.Params("organization_id")
-	if orgIdRaw == "" {
-		return 0, NewHTTPError("organization_id is required path parameter").
+	return getIdParam(ctx, "organization_id")
+}
+
+func getIdParam(ctx *fiber.Ctx, name string) (int64, error) {
+	raw := ctx.Params(name)
+	if raw == "" {
+		return 0, NewHTTPError(fmt.Sprintf("%s is required path parameter", name)).
 			AsFiberError(422)
 	}
-	var orgId int64
-	if _, err := fmt.Sscanf(orgIdRaw, "%d", &orgId); err != nil {
-		return 0, NewHTTPError("organization_id must be a number").AsFiberError(422)
+	var id int64
+	if _, err := fmt.Sscanf(raw, "%d", &id); err != nil {
+		return 0, NewHTTPError(fmt.Sprintf("%s must be a number", name)).AsFiberError(422)
 	}
-	return orgId, nil
+	return id, nil
 }
@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"github.com/burenotti/rtu-it-lab-recruit/handler/middlewares/auth"
+	"github.com/burenotti/rtu-it-lab-recruit/model"
+	"github.com/gofiber/fiber/v2"
+	"net/url"
+)
+
+// categoryTitles are names of categories shown on the unsubscribe page.
+var categoryTitles = map[string]string{
+	model.CategoryReminders: "напоминания о мероприятиях",
+	model.CategoryDigests:   "еженедельные подборки",
+	model.CategoryFollows:   "анонсы организаций",
+	model.CategoryInvites:   "приглашения в организации",
+	model.CategorySearches:  "уведомления по сохранённым поискам",
+}
+
+// ListPreferences
+//
+//	@Summary		Returns notification preferences of current user
+//	@Description	Preferences of every category in every channel of the user. Categories are enabled by default.
+//	@Description	Transactional messages, like login codes, have no category and can't be disabled.
+//	@Security		APIKey
+//	@Produce		json
+//	@Tags			Me
+//	@Success		200	{array}		model.NotificationPreference
+//	@Failure		401	{object}	HTTPError
+//	@Failure		500	{object}	HTTPError
+//	@Router			/me/preferences [get]
+func (h *HTTPHandler) ListPreferences(ctx *fiber.Ctx) error {
+	user, _ := auth.GetAuth(ctx)
+
+	preferences, err := h.ucase.ListPreferences(ctx.Context(), user.UserID)
+	if err != nil {
+		return WrapError(err)
+	}
+	return ReturnJson(ctx, preferences)
+}
+
+// UpdatePreferences
+//
+//	@Summary	Enables or disables categories of notifications in channels
+//	@Security	APIKey
+//	@Accept		json
+//	@Produce	json
+//	@Tags		Me
+//	@Param		updates	body		model.NotificationPreferencesUpdate	true	"Preferences that will be updated"
+//	@Success	200		{array}		model.NotificationPreference
+//	@Failure	401		{object}	HTTPError
+//	@Failure	422		{object}	ValidationError
+//	@Failure	500		{object}	HTTPError
+//	@Router		/me/preferences [patch]
+func (h *HTTPHandler) UpdatePreferences(ctx *fiber.Ctx) error {
+	user, _ := auth.GetAuth(ctx)
+	update, jerr := JsonParseAndValidate[model.NotificationPreferencesUpdate](ctx, validate)
+	if jerr != nil {
+		return jerr.AsFiberError(422)
+	}
+
+	preferences, err := h.ucase.UpdatePreferences(ctx.Context(), user.UserID, update)
+	if err != nil {
+		return WrapError(err)
+	}
+	return ReturnJson(ctx, preferences)
+}
+
+// UnsubscribeLanding
+//
+//	@Tags			Me
+//	@Summary		Landing page for unsubscribe link sent in non-transactional emails
+//	@Description	Does not unsubscribe, so email link scanners can't opt users out.
+//	@Produce		html
+//	@Param			token	path	string	true	"Signed unsubscribe token"
+//	@Success		200
+//	@Router			/unsubscribe/{token} [get]
+func (h *HTTPHandler) UnsubscribeLanding(ctx *fiber.Ctx) error {
+	token := ctx.Params("token")
+	return h.renderUnsubscribePage(ctx, unsubscribePageContext{
+		Action: "/unsubscribe/" + url.PathEscape(token),
+	})
+}
+
+// UnsubscribeCategory
+//
+//	@Tags			Me
+//	@Summary		Opts the user out of the category of notifications in the channel
+//	@Description	Used by the confirmation form of the landing page and RFC 8058 one-click unsubscribe of mail clients,
+//	@Description	which send List-Unsubscribe=One-Click form. Sign in is not required, the token is signed.
+//	@Param			token	path	string	true	"Signed unsubscribe token"
+//	@Success		204
+//	@Failure		404	{object}	HTTPError
+//	@Failure		500	{object}	HTTPError
+//	@Router			/unsubscribe/{token} [post]
+func (h *HTTPHandler) UnsubscribeCategory(ctx *fiber.Ctx) error {
+	preference, err := h.ucase.UnsubscribeByToken(ctx.Context(), ctx.Params("token"))
+
+	// Confirmation form of the landing page expects html in response
+	if ctx.Accepts(fiber.MIMEApplicationJSON, fiber.MIMETextHTML) == fiber.MIMETextHTML {
+		page := unsubscribePageContext{Unsubscribed: err == nil}
+		if err != nil {
+			page.Error = UnwrapAtomicError(err).Error()
+			ctx.Status(fiber.StatusNotFound)
+		} else {
+			page.Subject = categoryTitles[preference.Category]
+		}
+		return h.renderUnsubscribePage(ctx, page)
+	}
+
+	if err != nil {
+		return WrapError(err)
+	}
+	ctx.Status(fiber.StatusNoContent)
+	return nil
+}
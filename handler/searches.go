@@ -0,0 +1,204 @@
+package handler
+
+import (
+	"bytes"
+	"github.com/burenotti/rtu-it-lab-recruit/handler/middlewares/auth"
+	"github.com/burenotti/rtu-it-lab-recruit/model"
+	"github.com/gofiber/fiber/v2"
+	"net/url"
+)
+
+// CreateSearch
+//
+//	@Summary		Saves event search
+//	@Description	Filter is a tree of conditions combined with and/or. Each node must have exactly one field set.
+//	@Description	If alerts are enabled, the user is notified about published events matching the search.
+//	@Security		APIKey
+//	@Accept			json
+//	@Produce		json
+//	@Tags			Searches
+//	@Param			search	body		model.SavedSearchCreate	true	"Search"
+//	@Success		201		{object}	model.SavedSearch
+//	@Failure		400		{object}	HTTPError
+//	@Failure		422		{object}	ValidationError
+//	@Failure		500		{object}	HTTPError
+//	@Router			/me/searches [post]
+func (h *HTTPHandler) CreateSearch(ctx *fiber.Ctx) error {
+	user, _ := auth.GetAuth(ctx)
+	create, jerr := JsonParseAndValidate[model.SavedSearchCreate](ctx, validate)
+	if jerr != nil {
+		return jerr.AsFiberError(422)
+	}
+
+	search, err := h.ucase.CreateSearch(ctx.Context(), user.UserID, create)
+	if err != nil {
+		return WrapError(err)
+	}
+	ctx.Status(fiber.StatusCreated)
+	return ReturnJson(ctx, search)
+}
+
+// ListSearches
+//
+//	@Summary	Returns saved searches of current user
+//	@Security	APIKey
+//	@Produce	json
+//	@Tags		Searches
+//	@Success	200	{array}		model.SavedSearch
+//	@Failure	401	{object}	HTTPError
+//	@Failure	500	{object}	HTTPError
+//	@Router		/me/searches [get]
+func (h *HTTPHandler) ListSearches(ctx *fiber.Ctx) error {
+	user, _ := auth.GetAuth(ctx)
+
+	searches, err := h.ucase.ListSearches(ctx.Context(), user.UserID)
+	if err != nil {
+		return WrapError(err)
+	}
+	return ReturnJson(ctx, searches)
+}
+
+// UpdateSearch
+//
+//	@Summary	Renames saved search or toggles its alerts
+//	@Security	APIKey
+//	@Accept		json
+//	@Produce	json
+//	@Tags		Searches
+//	@Param		search_id	path		int						true	"Search id"
+//	@Param		updates		body		model.SavedSearchUpdate	true	"Fields that will be updated"
+//	@Success	200			{object}	model.SavedSearch
+//	@Failure	404			{object}	HTTPError
+//	@Failure	422			{object}	ValidationError
+//	@Failure	500			{object}	HTTPError
+//	@Router		/me/searches/{search_id} [patch]
+func (h *HTTPHandler) UpdateSearch(ctx *fiber.Ctx) error {
+	user, _ := auth.GetAuth(ctx)
+	searchId, err := getIdParam(ctx, "search_id")
+	if err != nil {
+		return err
+	}
+	update, jerr := JsonParseAndValidate[model.SavedSearchUpdate](ctx, validate)
+	if jerr != nil {
+		return jerr.AsFiberError(422)
+	}
+
+	search, err := h.ucase.UpdateSearch(ctx.Context(), user.UserID, searchId, update)
+	if err != nil {
+		return WrapError(err)
+	}
+	return ReturnJson(ctx, search)
+}
+
+// DeleteSearch
+//
+//	@Summary	Deletes saved search
+//	@Security	APIKey
+//	@Tags		Searches
+//	@Param		search_id	path	int	true	"Search id"
+//	@Success	204
+//	@Failure	404	{object}	HTTPError
+//	@Failure	500	{object}	HTTPError
+//	@Router		/me/searches/{search_id} [delete]
+func (h *HTTPHandler) DeleteSearch(ctx *fiber.Ctx) error {
+	user, _ := auth.GetAuth(ctx)
+	searchId, err := getIdParam(ctx, "search_id")
+	if err != nil {
+		return err
+	}
+
+	if err := h.ucase.DeleteSearch(ctx.Context(), user.UserID, searchId); err != nil {
+		return WrapError(err)
+	}
+	ctx.Status(fiber.StatusNoContent)
+	return nil
+}
+
+// RunSearch
+//
+//	@Summary	Returns upcoming published events matching saved search
+//	@Security	APIKey
+//	@Produce	json
+//	@Tags		Searches
+//	@Param		search_id	path		int	true	"Search id"
+//	@Success	200			{array}		model.Event
+//	@Failure	404			{object}	HTTPError
+//	@Failure	500			{object}	HTTPError
+//	@Router		/me/searches/{search_id}/events [get]
+func (h *HTTPHandler) RunSearch(ctx *fiber.Ctx) error {
+	user, _ := auth.GetAuth(ctx)
+	searchId, err := getIdParam(ctx, "search_id")
+	if err != nil {
+		return err
+	}
+
+	events, err := h.ucase.RunSearch(ctx.Context(), user.UserID, searchId)
+	if err != nil {
+		return WrapError(err)
+	}
+	return ReturnJson(ctx, events)
+}
+
+type unsubscribePageContext struct {
+	Action       string
+	Error        string
+	Subject      string
+	Unsubscribed bool
+}
+
+// SearchUnsubscribeLanding
+//
+//	@Tags			Searches
+//	@Summary		Landing page for unsubscribe link sent in search alerts
+//	@Description	Does not unsubscribe, so email link scanners can't disable alerts.
+//	@Produce		html
+//	@Param			token	path	string	true	"Unsubscribe token"
+//	@Success		200
+//	@Router			/unsubscribe/search/{token} [get]
+func (h *HTTPHandler) SearchUnsubscribeLanding(ctx *fiber.Ctx) error {
+	token := ctx.Params("token")
+	return h.renderUnsubscribePage(ctx, unsubscribePageContext{
+		Action: "/unsubscribe/search/" + url.PathEscape(token),
+	})
+}
+
+// UnsubscribeSearch
+//
+//	@Tags			Searches
+//	@Summary		Disables alerts of saved search
+//	@Description	Used by the confirmation form of the landing page and one-click unsubscribe of mail clients.
+//	@Param			token	path	string	true	"Unsubscribe token"
+//	@Success		204
+//	@Failure		404	{object}	HTTPError
+//	@Failure		500	{object}	HTTPError
+//	@Router			/unsubscribe/search/{token} [post]
+func (h *HTTPHandler) UnsubscribeSearch(ctx *fiber.Ctx) error {
+	search, err := h.ucase.Unsubscribe(ctx.Context(), ctx.Params("token"))
+
+	// Confirmation form of the landing page expects html in response
+	if ctx.Accepts(fiber.MIMEApplicationJSON, fiber.MIMETextHTML) == fiber.MIMETextHTML {
+		page := unsubscribePageContext{Unsubscribed: err == nil}
+		if err != nil {
+			page.Error = UnwrapAtomicError(err).Error()
+			ctx.Status(fiber.StatusNotFound)
+		} else {
+			page.Subject = search.Name
+		}
+		return h.renderUnsubscribePage(ctx, page)
+	}
+
+	if err != nil {
+		return WrapError(err)
+	}
+	ctx.Status(fiber.StatusNoContent)
+	return nil
+}
+
+func (h *HTTPHandler) renderUnsubscribePage(ctx *fiber.Ctx, page unsubscribePageContext) error {
+	var buf bytes.Buffer
+	if err := h.cfg.UnsubscribePage.Execute(&buf, page); err != nil {
+		return NewHTTPError(err.Error()).AsFiberError(fiber.StatusInternalServerError)
+	}
+	ctx.Type("html", "utf-8")
+	return ctx.Send(buf.Bytes())
+}
@@ -0,0 +1,363 @@
+package handler
+
+import (
+	"github.com/burenotti/rtu-it-lab-recruit/handler/middlewares/auth"
+	"github.com/burenotti/rtu-it-lab-recruit/model"
+	"github.com/gofiber/fiber/v2"
+)
+
+// CreateTicketType
+//
+//	@Summary		Creates ticket type of event
+//	@Description	Available to organization members with rights to edit events. Prices are in minor units of the currency.
+//	@Description	Once the event has ticket types, users register for it by ordering tickets. Tickets are not sold for recurring events.
+//	@Security		APIKey
+//	@Accept			json
+//	@Produce		json
+//	@Tags			Tickets
+//	@Param			event_id	path		int						true	"Event id"
+//	@Param			ticket_type	body		model.TicketTypeCreate	true	"Ticket type"
+//	@Success		201			{object}	model.TicketType
+//	@Failure		400			{object}	HTTPError
+//	@Failure		404			{object}	HTTPError
+//	@Failure		422			{object}	ValidationError
+//	@Failure		500			{object}	HTTPError
+//	@Router			/event/{event_id}/ticket-types [post]
+func (h *HTTPHandler) CreateTicketType(ctx *fiber.Ctx) error {
+	user, _ := auth.GetAuth(ctx)
+	eventId, err := getIdParam(ctx, "event_id")
+	if err != nil {
+		return err
+	}
+	create, jerr := JsonParseAndValidate[model.TicketTypeCreate](ctx, validate)
+	if jerr != nil {
+		return jerr.AsFiberError(422)
+	}
+
+	ticketType, err := h.ucase.CreateTicketType(ctx.Context(), user.UserID, eventId, create)
+	if err != nil {
+		return WrapError(err)
+	}
+	ctx.Status(fiber.StatusCreated)
+	return ReturnJson(ctx, ticketType)
+}
+
+// ListTicketTypes
+//
+//	@Summary		Returns ticket types of event
+//	@Description	Ticket types have numbers of tickets sold and available.
+//	@Security		APIKey
+//	@Produce		json
+//	@Tags			Tickets
+//	@Param			event_id	path		int	true	"Event id"
+//	@Success		200			{array}		model.TicketType
+//	@Failure		400			{object}	HTTPError
+//	@Failure		404			{object}	HTTPError
+//	@Failure		500			{object}	HTTPError
+//	@Router			/event/{event_id}/ticket-types [get]
+func (h *HTTPHandler) ListTicketTypes(ctx *fiber.Ctx) error {
+	user, _ := auth.GetAuth(ctx)
+	eventId, err := getIdParam(ctx, "event_id")
+	if err != nil {
+		return err
+	}
+
+	ticketTypes, err := h.ucase.ListTicketTypes(ctx.Context(), user.UserID, eventId)
+	if err != nil {
+		return WrapError(err)
+	}
+	return ReturnJson(ctx, ticketTypes)
+}
+
+// UpdateTicketType
+//
+//	@Summary		Updates ticket type
+//	@Description	Quantity can't be less than the number of sold and reserved tickets.
+//	@Security		APIKey
+//	@Accept			json
+//	@Produce		json
+//	@Tags			Tickets
+//	@Param			ticket_type_id	path		int						true	"Ticket type id"
+//	@Param			ticket_type		body		model.TicketTypeUpdate	true	"Fields to update"
+//	@Success		200				{object}	model.TicketType
+//	@Failure		400				{object}	HTTPError
+//	@Failure		404				{object}	HTTPError
+//	@Failure		422				{object}	ValidationError
+//	@Failure		500				{object}	HTTPError
+//	@Router			/ticket-type/{ticket_type_id} [patch]
+func (h *HTTPHandler) UpdateTicketType(ctx *fiber.Ctx) error {
+	user, _ := auth.GetAuth(ctx)
+	ticketTypeId, err := getIdParam(ctx, "ticket_type_id")
+	if err != nil {
+		return err
+	}
+	update, jerr := JsonParseAndValidate[model.TicketTypeUpdate](ctx, validate)
+	if jerr != nil {
+		return jerr.AsFiberError(422)
+	}
+
+	ticketType, err := h.ucase.UpdateTicketType(ctx.Context(), user.UserID, ticketTypeId, update)
+	if err != nil {
+		return WrapError(err)
+	}
+	return ReturnJson(ctx, ticketType)
+}
+
+// DeleteTicketType
+//
+//	@Summary		Deletes ticket type
+//	@Description	Ticket types with orders can't be deleted.
+//	@Security		APIKey
+//	@Tags			Tickets
+//	@Param			ticket_type_id	path	int	true	"Ticket type id"
+//	@Success		204
+//	@Failure		400	{object}	HTTPError
+//	@Failure		404	{object}	HTTPError
+//	@Failure		409	{object}	HTTPError
+//	@Failure		500	{object}	HTTPError
+//	@Router			/ticket-type/{ticket_type_id} [delete]
+func (h *HTTPHandler) DeleteTicketType(ctx *fiber.Ctx) error {
+	user, _ := auth.GetAuth(ctx)
+	ticketTypeId, err := getIdParam(ctx, "ticket_type_id")
+	if err != nil {
+		return err
+	}
+
+	if err := h.ucase.DeleteTicketType(ctx.Context(), user.UserID, ticketTypeId); err != nil {
+		return WrapError(err)
+	}
+	ctx.Status(fiber.StatusNoContent)
+	return nil
+}
+
+// CreatePromoCode
+//
+//	@Summary		Creates promo code of event
+//	@Description	Available to organization members with rights to edit events. Codes are case-insensitive.
+//	@Description	Percent codes take amount percents off, fixed codes take amount minor units of their currency off.
+//	@Security		APIKey
+//	@Accept			json
+//	@Produce		json
+//	@Tags			Tickets
+//	@Param			event_id	path		int						true	"Event id"
+//	@Param			promo_code	body		model.PromoCodeCreate	true	"Promo code"
+//	@Success		201			{object}	model.PromoCode
+//	@Failure		400			{object}	HTTPError
+//	@Failure		404			{object}	HTTPError
+//	@Failure		409			{object}	HTTPError
+//	@Failure		422			{object}	ValidationError
+//	@Failure		500			{object}	HTTPError
+//	@Router			/event/{event_id}/promo-codes [post]
+func (h *HTTPHandler) CreatePromoCode(ctx *fiber.Ctx) error {
+	user, _ := auth.GetAuth(ctx)
+	eventId, err := getIdParam(ctx, "event_id")
+	if err != nil {
+		return err
+	}
+	create, jerr := JsonParseAndValidate[model.PromoCodeCreate](ctx, validate)
+	if jerr != nil {
+		return jerr.AsFiberError(422)
+	}
+
+	promoCode, err := h.ucase.CreatePromoCode(ctx.Context(), user.UserID, eventId, create)
+	if err != nil {
+		return WrapError(err)
+	}
+	ctx.Status(fiber.StatusCreated)
+	return ReturnJson(ctx, promoCode)
+}
+
+// ListPromoCodes
+//
+//	@Summary		Returns promo codes of event
+//	@Description	Available to organization members with rights to edit events.
+//	@Security		APIKey
+//	@Produce		json
+//	@Tags			Tickets
+//	@Param			event_id	path		int	true	"Event id"
+//	@Success		200			{array}		model.PromoCode
+//	@Failure		400			{object}	HTTPError
+//	@Failure		404			{object}	HTTPError
+//	@Failure		500			{object}	HTTPError
+//	@Router			/event/{event_id}/promo-codes [get]
+func (h *HTTPHandler) ListPromoCodes(ctx *fiber.Ctx) error {
+	user, _ := auth.GetAuth(ctx)
+	eventId, err := getIdParam(ctx, "event_id")
+	if err != nil {
+		return err
+	}
+
+	promoCodes, err := h.ucase.ListPromoCodes(ctx.Context(), user.UserID, eventId)
+	if err != nil {
+		return WrapError(err)
+	}
+	return ReturnJson(ctx, promoCodes)
+}
+
+// DeletePromoCode
+//
+//	@Summary		Deletes promo code of event
+//	@Description	Orders with the code keep their discounts.
+//	@Security		APIKey
+//	@Tags			Tickets
+//	@Param			event_id		path	int	true	"Event id"
+//	@Param			promo_code_id	path	int	true	"Promo code id"
+//	@Success		204
+//	@Failure		400	{object}	HTTPError
+//	@Failure		404	{object}	HTTPError
+//	@Failure		500	{object}	HTTPError
+//	@Router			/event/{event_id}/promo-codes/{promo_code_id} [delete]
+func (h *HTTPHandler) DeletePromoCode(ctx *fiber.Ctx) error {
+	user, _ := auth.GetAuth(ctx)
+	eventId, err := getIdParam(ctx, "event_id")
+	if err != nil {
+		return err
+	}
+	promoCodeId, err := getIdParam(ctx, "promo_code_id")
+	if err != nil {
+		return err
+	}
+
+	if err := h.ucase.DeletePromoCode(ctx.Context(), user.UserID, eventId, promoCodeId); err != nil {
+		return WrapError(err)
+	}
+	ctx.Status(fiber.StatusNoContent)
+	return nil
+}
+
+// CreateOrder
+//
+//	@Summary		Orders ticket for event
+//	@Description	The ticket is reserved for a few minutes, the order must be paid by payment_url and confirmed before it expires.
+//	@Description	Free orders are paid at once and the user is registered for the event.
+//	@Description	One ticket is ordered, the user can't have another reserved or paid order for the event.
+//	@Security		APIKey
+//	@Accept			json
+//	@Produce		json
+//	@Tags			Tickets
+//	@Param			event_id	path		int					true	"Event id"
+//	@Param			order		body		model.OrderCreate	true	"Order"
+//	@Success		201			{object}	model.Order
+//	@Failure		400			{object}	HTTPError
+//	@Failure		404			{object}	HTTPError
+//	@Failure		422			{object}	ValidationError
+//	@Failure		500			{object}	HTTPError
+//	@Router			/event/{event_id}/orders [post]
+func (h *HTTPHandler) CreateOrder(ctx *fiber.Ctx) error {
+	user, _ := auth.GetAuth(ctx)
+	eventId, err := getIdParam(ctx, "event_id")
+	if err != nil {
+		return err
+	}
+	create, jerr := JsonParseAndValidate[model.OrderCreate](ctx, validate)
+	if jerr != nil {
+		return jerr.AsFiberError(422)
+	}
+
+	order, err := h.ucase.CreateOrder(ctx.Context(), user.UserID, eventId, create)
+	if err != nil {
+		return WrapError(err)
+	}
+	ctx.Status(fiber.StatusCreated)
+	return ReturnJson(ctx, order)
+}
+
+// GetOrder
+//
+//	@Summary	Returns order of current user
+//	@Security	APIKey
+//	@Produce	json
+//	@Tags		Tickets
+//	@Param		order_id	path		int	true	"Order id"
+//	@Success	200			{object}	model.Order
+//	@Failure	400			{object}	HTTPError
+//	@Failure	404			{object}	HTTPError
+//	@Failure	500			{object}	HTTPError
+//	@Router		/order/{order_id} [get]
+func (h *HTTPHandler) GetOrder(ctx *fiber.Ctx) error {
+	user, _ := auth.GetAuth(ctx)
+	orderId, err := getIdParam(ctx, "order_id")
+	if err != nil {
+		return err
+	}
+
+	order, err := h.ucase.GetOrder(ctx.Context(), user.UserID, orderId)
+	if err != nil {
+		return WrapError(err)
+	}
+	return ReturnJson(ctx, order)
+}
+
+// ListMyOrders
+//
+//	@Summary	Returns orders of current user
+//	@Security	APIKey
+//	@Produce	json
+//	@Tags		Tickets
+//	@Success	200	{array}		model.Order
+//	@Failure	500	{object}	HTTPError
+//	@Router		/me/orders [get]
+func (h *HTTPHandler) ListMyOrders(ctx *fiber.Ctx) error {
+	user, _ := auth.GetAuth(ctx)
+
+	orders, err := h.ucase.ListUserOrders(ctx.Context(), user.UserID)
+	if err != nil {
+		return WrapError(err)
+	}
+	return ReturnJson(ctx, orders)
+}
+
+// ConfirmOrder
+//
+//	@Summary		Confirms payment of order
+//	@Description	If the payment has succeeded, the order is paid and the user is registered for the event.
+//	@Description	If the payment has been cancelled, the order is cancelled.
+//	@Security		APIKey
+//	@Produce		json
+//	@Tags			Tickets
+//	@Param			order_id	path		int	true	"Order id"
+//	@Success		200			{object}	model.Order
+//	@Failure		400			{object}	HTTPError
+//	@Failure		404			{object}	HTTPError
+//	@Failure		500			{object}	HTTPError
+//	@Router			/order/{order_id}/confirm [post]
+func (h *HTTPHandler) ConfirmOrder(ctx *fiber.Ctx) error {
+	user, _ := auth.GetAuth(ctx)
+	orderId, err := getIdParam(ctx, "order_id")
+	if err != nil {
+		return err
+	}
+
+	order, err := h.ucase.ConfirmOrder(ctx.Context(), user.UserID, orderId)
+	if err != nil {
+		return WrapError(err)
+	}
+	return ReturnJson(ctx, order)
+}
+
+// CancelOrder
+//
+//	@Summary		Cancels reserved order
+//	@Description	The payment is cancelled and reserved tickets are released.
+//	@Security		APIKey
+//	@Produce		json
+//	@Tags			Tickets
+//	@Param			order_id	path		int	true	"Order id"
+//	@Success		200			{object}	model.Order
+//	@Failure		400			{object}	HTTPError
+//	@Failure		404			{object}	HTTPError
+//	@Failure		500			{object}	HTTPError
+//	@Router			/order/{order_id}/cancel [post]
+func (h *HTTPHandler) CancelOrder(ctx *fiber.Ctx) error {
+	user, _ := auth.GetAuth(ctx)
+	orderId, err := getIdParam(ctx, "order_id")
+	if err != nil {
+		return err
+	}
+
+	order, err := h.ucase.CancelOrder(ctx.Context(), user.UserID, orderId)
+	if err != nil {
+		return WrapError(err)
+	}
+	return ReturnJson(ctx, order)
+}
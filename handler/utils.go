@@ -3,6 +3,8 @@ package handler
 import (
 	"errors"
 	"github.com/burenotti/rtu-it-lab-recruit/repositories"
+	"github.com/burenotti/rtu-it-lab-recruit/services"
+	"github.com/burenotti/rtu-it-lab-recruit/usecases"
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
 	"reflect"
@@ -14,6 +16,18 @@ func JsonParseAndValidate[T any](ctx *fiber.Ctx, validate *validator.Validate) (
 	if err := ctx.BodyParser(obj); err != nil {
 		return nil, &HTTPError{Details: err.Error()}
 	}
+	return validateStruct(obj, validate, "json")
+}
+
+func QueryParseAndValidate[T any](ctx *fiber.Ctx, validate *validator.Validate) (*T, JsonError) {
+	obj := new(T)
+	if err := ctx.QueryParser(obj); err != nil {
+		return nil, &HTTPError{Details: err.Error()}
+	}
+	return validateStruct(obj, validate, "query")
+}
+
+func validateStruct[T any](obj *T, validate *validator.Validate, tag string) (*T, JsonError) {
 	err := validate.Struct(obj)
 	if err != nil {
 		verr := err.(validator.ValidationErrors)
@@ -22,7 +36,7 @@ func JsonParseAndValidate[T any](ctx *fiber.Ctx, validate *validator.Validate) (
 		}
 		for _, e := range verr {
 			field, _ := reflect.TypeOf(*obj).FieldByName(e.StructField())
-			fieldName := strings.Split(field.Tag.Get("json"), ",")[0]
+			fieldName := strings.Split(field.Tag.Get(tag), ",")[0]
 			fieldErr := FieldValidationError{
 				Name:  fieldName,
 				Error: e.Error(),
@@ -81,6 +95,88 @@ func WrapError(err error) error {
 		return httpError.AsFiberError(fiber.StatusForbidden)
 	} else if errors.Is(err, repositories.ErrCodeInvalid) {
 		return httpError.AsFiberError(fiber.StatusBadRequest)
+	} else if errors.Is(err, repositories.ErrDataExportNotFound) {
+		return httpError.AsFiberError(fiber.StatusNotFound)
+	} else if errors.Is(err, usecases.ErrBusinessLogicViolation) {
+		return httpError.AsFiberError(fiber.StatusBadRequest)
+	} else if errors.Is(err, repositories.ErrTokenAlreadyUsed) {
+		return httpError.AsFiberError(fiber.StatusBadRequest)
+	} else if errors.Is(err, repositories.ErrTokenExpired) {
+		return httpError.AsFiberError(fiber.StatusBadRequest)
+	} else if errors.Is(err, usecases.ErrUserNotActivated) {
+		return httpError.AsFiberError(fiber.StatusForbidden)
+	} else if errors.Is(err, usecases.ErrTooManyRequests) {
+		return httpError.AsFiberError(fiber.StatusTooManyRequests)
+	} else if errors.Is(err, usecases.ErrUserBanned) {
+		return httpError.AsFiberError(fiber.StatusForbidden)
+	} else if errors.Is(err, usecases.ErrUserDeactivated) {
+		return httpError.AsFiberError(fiber.StatusForbidden)
+	} else if errors.Is(err, usecases.ErrNotAdmin) {
+		return httpError.AsFiberError(fiber.StatusForbidden)
+	} else if errors.Is(err, usecases.ErrSelfAction) {
+		return httpError.AsFiberError(fiber.StatusBadRequest)
+	} else if errors.Is(err, repositories.ErrOrganizationNotFound) {
+		return httpError.AsFiberError(fiber.StatusNotFound)
+	} else if errors.Is(err, repositories.ErrEventNotFount) {
+		return httpError.AsFiberError(fiber.StatusNotFound)
+	} else if errors.Is(err, repositories.ErrOutboxMessageNotFound) {
+		return httpError.AsFiberError(fiber.StatusNotFound)
+	} else if errors.Is(err, usecases.ErrMessageNotDead) {
+		return httpError.AsFiberError(fiber.StatusBadRequest)
+	} else if errors.Is(err, repositories.ErrChannelNotLinked) {
+		return httpError.AsFiberError(fiber.StatusNotFound)
+	} else if errors.Is(err, usecases.ErrChannelNotConfigured) {
+		return httpError.AsFiberError(fiber.StatusNotImplemented)
+	} else if errors.Is(err, usecases.ErrPhoneCodeInvalid) {
+		return httpError.AsFiberError(fiber.StatusForbidden)
+	} else if errors.Is(err, repositories.ErrPhoneVerificationNotFound) {
+		return httpError.AsFiberError(fiber.StatusNotFound)
+	} else if errors.Is(err, repositories.ErrUpdatesValidationError) {
+		return httpError.AsFiberError(fiber.StatusBadRequest)
+	} else if errors.Is(err, repositories.ErrAlreadyRegistered) {
+		return httpError.AsFiberError(fiber.StatusBadRequest)
+	} else if errors.Is(err, repositories.ErrRegistrationNotFound) {
+		return httpError.AsFiberError(fiber.StatusNotFound)
+	} else if errors.Is(err, repositories.ErrAlreadyFollowing) {
+		return httpError.AsFiberError(fiber.StatusBadRequest)
+	} else if errors.Is(err, repositories.ErrNotFollowing) {
+		return httpError.AsFiberError(fiber.StatusNotFound)
+	} else if errors.Is(err, repositories.ErrSavedSearchNotFound) {
+		return httpError.AsFiberError(fiber.StatusNotFound)
+	} else if errors.Is(err, usecases.ErrInvalidSearchFilter) {
+		return httpError.AsFiberError(422)
+	} else if errors.Is(err, repositories.ErrNotificationNotFound) {
+		return httpError.AsFiberError(fiber.StatusNotFound)
+	} else if errors.Is(err, services.ErrInvalidUnsubscribeToken) {
+		return httpError.AsFiberError(fiber.StatusNotFound)
+	} else if errors.Is(err, repositories.ErrWebhookNotFound) {
+		return httpError.AsFiberError(fiber.StatusNotFound)
+	} else if errors.Is(err, repositories.ErrVenueNotFound) {
+		return httpError.AsFiberError(fiber.StatusNotFound)
+	} else if errors.Is(err, repositories.ErrVenueInUse) {
+		return httpError.AsFiberError(fiber.StatusConflict)
+	} else if errors.Is(err, repositories.ErrCategoryNotFound) {
+		return httpError.AsFiberError(fiber.StatusNotFound)
+	} else if errors.Is(err, repositories.ErrCategoryExists) {
+		return httpError.AsFiberError(fiber.StatusConflict)
+	} else if errors.Is(err, repositories.ErrCategoryInUse) {
+		return httpError.AsFiberError(fiber.StatusConflict)
+	} else if errors.Is(err, repositories.ErrOccurrenceNotFound) {
+		return httpError.AsFiberError(fiber.StatusNotFound)
+	} else if errors.Is(err, repositories.ErrCalendarFeedNotFound) {
+		return httpError.AsFiberError(fiber.StatusNotFound)
+	} else if errors.Is(err, repositories.ErrEventImportNotFound) {
+		return httpError.AsFiberError(fiber.StatusNotFound)
+	} else if errors.Is(err, repositories.ErrTicketTypeNotFound) {
+		return httpError.AsFiberError(fiber.StatusNotFound)
+	} else if errors.Is(err, repositories.ErrTicketTypeInUse) {
+		return httpError.AsFiberError(fiber.StatusConflict)
+	} else if errors.Is(err, repositories.ErrPromoCodeNotFound) {
+		return httpError.AsFiberError(fiber.StatusNotFound)
+	} else if errors.Is(err, repositories.ErrPromoCodeExists) {
+		return httpError.AsFiberError(fiber.StatusConflict)
+	} else if errors.Is(err, repositories.ErrOrderNotFound) {
+		return httpError.AsFiberError(fiber.StatusNotFound)
 	} else {
 		return httpError.AsFiberError(fiber.StatusInternalServerError)
 	}
@@ -0,0 +1,151 @@
+package handler
+
+import (
+	"github.com/burenotti/rtu-it-lab-recruit/handler/middlewares/auth"
+	"github.com/burenotti/rtu-it-lab-recruit/model"
+	"github.com/gofiber/fiber/v2"
+)
+
+// CreateVenue
+//
+//	@Summary		Creates venue of organization
+//	@Description	Available to organization members with rights to edit events.
+//	@Security		APIKey
+//	@Accept			json
+//	@Produce		json
+//	@Tags			Venues
+//	@Param			organization_id	path		int					true	"Organization id"
+//	@Param			venue			body		model.VenueCreate	true	"Venue"
+//	@Success		201				{object}	model.Venue
+//	@Failure		400				{object}	HTTPError
+//	@Failure		404				{object}	HTTPError
+//	@Failure		422				{object}	ValidationError
+//	@Failure		500				{object}	HTTPError
+//	@Router			/organization/{organization_id}/venues [post]
+func (h *HTTPHandler) CreateVenue(ctx *fiber.Ctx) error {
+	user, _ := auth.GetAuth(ctx)
+	orgId, err := getIdParam(ctx, "organization_id")
+	if err != nil {
+		return err
+	}
+	create, jerr := JsonParseAndValidate[model.VenueCreate](ctx, validate)
+	if jerr != nil {
+		return jerr.AsFiberError(422)
+	}
+
+	venue, err := h.ucase.CreateVenue(ctx.Context(), user.UserID, orgId, create)
+	if err != nil {
+		return WrapError(err)
+	}
+	ctx.Status(fiber.StatusCreated)
+	return ReturnJson(ctx, venue)
+}
+
+// ListVenues
+//
+//	@Summary	Returns venues of organization
+//	@Security	APIKey
+//	@Produce	json
+//	@Tags		Venues
+//	@Param		organization_id	path		int	true	"Organization id"
+//	@Success	200				{array}		model.Venue
+//	@Failure	400				{object}	HTTPError
+//	@Failure	500				{object}	HTTPError
+//	@Router		/organization/{organization_id}/venues [get]
+func (h *HTTPHandler) ListVenues(ctx *fiber.Ctx) error {
+	orgId, err := getIdParam(ctx, "organization_id")
+	if err != nil {
+		return err
+	}
+
+	venues, err := h.ucase.ListVenues(ctx.Context(), orgId)
+	if err != nil {
+		return WrapError(err)
+	}
+	return ReturnJson(ctx, venues)
+}
+
+// GetVenue
+//
+//	@Summary	Returns venue
+//	@Security	APIKey
+//	@Produce	json
+//	@Tags		Venues
+//	@Param		venue_id	path		int	true	"Venue id"
+//	@Success	200			{object}	model.Venue
+//	@Failure	404			{object}	HTTPError
+//	@Failure	500			{object}	HTTPError
+//	@Router		/venue/{venue_id} [get]
+func (h *HTTPHandler) GetVenue(ctx *fiber.Ctx) error {
+	venueId, err := getIdParam(ctx, "venue_id")
+	if err != nil {
+		return err
+	}
+
+	venue, err := h.ucase.GetVenue(ctx.Context(), venueId)
+	if err != nil {
+		return WrapError(err)
+	}
+	return ReturnJson(ctx, venue)
+}
+
+// UpdateVenue
+//
+//	@Summary		Updates venue
+//	@Description	Available to members of the venue's organization with rights to edit events.
+//	@Security		APIKey
+//	@Accept			json
+//	@Produce		json
+//	@Tags			Venues
+//	@Param			venue_id	path		int					true	"Venue id"
+//	@Param			updates		body		model.VenueUpdate	true	"Fields that will be updated"
+//	@Success		200			{object}	model.Venue
+//	@Failure		400			{object}	HTTPError
+//	@Failure		404			{object}	HTTPError
+//	@Failure		422			{object}	ValidationError
+//	@Failure		500			{object}	HTTPError
+//	@Router			/venue/{venue_id} [patch]
+func (h *HTTPHandler) UpdateVenue(ctx *fiber.Ctx) error {
+	user, _ := auth.GetAuth(ctx)
+	venueId, err := getIdParam(ctx, "venue_id")
+	if err != nil {
+		return err
+	}
+	update, jerr := JsonParseAndValidate[model.VenueUpdate](ctx, validate)
+	if jerr != nil {
+		return jerr.AsFiberError(422)
+	}
+
+	venue, err := h.ucase.UpdateVenue(ctx.Context(), user.UserID, venueId, update)
+	if err != nil {
+		return WrapError(err)
+	}
+	return ReturnJson(ctx, venue)
+}
+
+// DeleteVenue
+//
+//	@Summary		Deletes venue
+//	@Description	Venues linked to events can't be deleted.
+//	@Security		APIKey
+//	@Tags			Venues
+//	@Param			venue_id	path	int	true	"Venue id"
+//	@Success		204
+//	@Failure		400	{object}	HTTPError
+//	@Failure		404	{object}	HTTPError
+//	@Failure		409	{object}	HTTPError
+//	@Failure		500	{object}	HTTPError
+//	@Router			/venue/{venue_id} [delete]
+func (h *HTTPHandler) DeleteVenue(ctx *fiber.Ctx) error {
+	user, _ := auth.GetAuth(ctx)
+	venueId, err := getIdParam(ctx, "venue_id")
+	if err != nil {
+		return err
+	}
+
+	if err := h.ucase.DeleteVenue(ctx.Context(), user.UserID, venueId); err != nil {
+		return WrapError(err)
+	}
+	ctx.Status(fiber.StatusNoContent)
+	return nil
+}
@@ -0,0 +1,176 @@
+package handler
+
+import (
+	"github.com/burenotti/rtu-it-lab-recruit/handler/middlewares/auth"
+	"github.com/burenotti/rtu-it-lab-recruit/model"
+	"github.com/gofiber/fiber/v2"
+)
+
+// CreateWebhook
+//
+//	@Summary		Subscribes endpoint to events of organization
+//	@Description	Events are sent as POST requests with JSON body. Each request has X-Webhook-Timestamp header
+//	@Description	and X-Webhook-Signature header with "sha256=" prefixed hex encoded HMAC-SHA256 of the timestamp
+//	@Description	and the body joined with a dot, the key is the secret of the webhook.
+//	@Description	The secret is returned only in this response. Only owners can manage webhooks.
+//	@Security		APIKey
+//	@Accept			json
+//	@Produce		json
+//	@Tags			Webhooks
+//	@Param			organization_id	path		int					true	"Organization id"
+//	@Param			webhook			body		model.WebhookCreate	true	"Webhook"
+//	@Success		201				{object}	model.Webhook
+//	@Failure		400				{object}	HTTPError
+//	@Failure		422				{object}	ValidationError
+//	@Failure		500				{object}	HTTPError
+//	@Router			/organization/{organization_id}/webhooks [post]
+func (h *HTTPHandler) CreateWebhook(ctx *fiber.Ctx) error {
+	user, _ := auth.GetAuth(ctx)
+	orgId, err := getIdParam(ctx, "organization_id")
+	if err != nil {
+		return err
+	}
+	create, jerr := JsonParseAndValidate[model.WebhookCreate](ctx, validate)
+	if jerr != nil {
+		return jerr.AsFiberError(422)
+	}
+
+	webhook, err := h.ucase.CreateWebhook(ctx.Context(), user.UserID, orgId, create)
+	if err != nil {
+		return WrapError(err)
+	}
+	ctx.Status(fiber.StatusCreated)
+	return ReturnJson(ctx, webhook)
+}
+
+// ListWebhooks
+//
+//	@Summary	Returns webhooks of organization
+//	@Security	APIKey
+//	@Produce	json
+//	@Tags		Webhooks
+//	@Param		organization_id	path		int	true	"Organization id"
+//	@Success	200				{array}		model.Webhook
+//	@Failure	400				{object}	HTTPError
+//	@Failure	500				{object}	HTTPError
+//	@Router		/organization/{organization_id}/webhooks [get]
+func (h *HTTPHandler) ListWebhooks(ctx *fiber.Ctx) error {
+	user, _ := auth.GetAuth(ctx)
+	orgId, err := getIdParam(ctx, "organization_id")
+	if err != nil {
+		return err
+	}
+
+	webhooks, err := h.ucase.ListWebhooks(ctx.Context(), user.UserID, orgId)
+	if err != nil {
+		return WrapError(err)
+	}
+	return ReturnJson(ctx, webhooks)
+}
+
+// UpdateWebhook
+//
+//	@Summary		Updates webhook
+//	@Description	Webhooks failing too many times in a row are disabled, enable them to resume deliveries.
+//	@Security		APIKey
+//	@Accept			json
+//	@Produce		json
+//	@Tags			Webhooks
+//	@Param			organization_id	path		int					true	"Organization id"
+//	@Param			webhook_id		path		int					true	"Webhook id"
+//	@Param			updates			body		model.WebhookUpdate	true	"Fields that will be updated"
+//	@Success		200				{object}	model.Webhook
+//	@Failure		400				{object}	HTTPError
+//	@Failure		404				{object}	HTTPError
+//	@Failure		422				{object}	ValidationError
+//	@Failure		500				{object}	HTTPError
+//	@Router			/organization/{organization_id}/webhooks/{webhook_id} [patch]
+func (h *HTTPHandler) UpdateWebhook(ctx *fiber.Ctx) error {
+	user, _ := auth.GetAuth(ctx)
+	orgId, err := getIdParam(ctx, "organization_id")
+	if err != nil {
+		return err
+	}
+	webhookId, err := getIdParam(ctx, "webhook_id")
+	if err != nil {
+		return err
+	}
+	update, jerr := JsonParseAndValidate[model.WebhookUpdate](ctx, validate)
+	if jerr != nil {
+		return jerr.AsFiberError(422)
+	}
+
+	webhook, err := h.ucase.UpdateWebhook(ctx.Context(), user.UserID, orgId, webhookId, update)
+	if err != nil {
+		return WrapError(err)
+	}
+	return ReturnJson(ctx, webhook)
+}
+
+// DeleteWebhook
+//
+//	@Summary	Deletes webhook with its delivery log
+//	@Security	APIKey
+//	@Tags		Webhooks
+//	@Param		organization_id	path	int	true	"Organization id"
+//	@Param		webhook_id		path	int	true	"Webhook id"
+//	@Success	204
+//	@Failure	400	{object}	HTTPError
+//	@Failure	404	{object}	HTTPError
+//	@Failure	500	{object}	HTTPError
+//	@Router		/organization/{organization_id}/webhooks/{webhook_id} [delete]
+func (h *HTTPHandler) DeleteWebhook(ctx *fiber.Ctx) error {
+	user, _ := auth.GetAuth(ctx)
+	orgId, err := getIdParam(ctx, "organization_id")
+	if err != nil {
+		return err
+	}
+	webhookId, err := getIdParam(ctx, "webhook_id")
+	if err != nil {
+		return err
+	}
+
+	if err := h.ucase.DeleteWebhook(ctx.Context(), user.UserID, orgId, webhookId); err != nil {
+		return WrapError(err)
+	}
+	ctx.Status(fiber.StatusNoContent)
+	return nil
+}
+
+// ListWebhookDeliveries
+//
+//	@Summary		Returns delivery log of webhook
+//	@Description	Deliveries are ordered from the newest. Pending deliveries are retried with exponential backoff.
+//	@Security		APIKey
+//	@Produce		json
+//	@Tags			Webhooks
+//	@Param			organization_id	path		int					true	"Organization id"
+//	@Param			webhook_id		path		int					true	"Webhook id"
+//	@Param			page			query		model.Pagination	false	"Pagination"
+//	@Success		200				{array}		model.WebhookDelivery
+//	@Failure		400				{object}	HTTPError
+//	@Failure		404				{object}	HTTPError
+//	@Failure		422				{object}	ValidationError
+//	@Failure		500				{object}	HTTPError
+//	@Router			/organization/{organization_id}/webhooks/{webhook_id}/deliveries [get]
+func (h *HTTPHandler) ListWebhookDeliveries(ctx *fiber.Ctx) error {
+	user, _ := auth.GetAuth(ctx)
+	orgId, err := getIdParam(ctx, "organization_id")
+	if err != nil {
+		return err
+	}
+	webhookId, err := getIdParam(ctx, "webhook_id")
+	if err != nil {
+		return err
+	}
+	page, jerr := QueryParseAndValidate[model.Pagination](ctx, validate)
+	if jerr != nil {
+		return jerr.AsFiberError(422)
+	}
+
+	deliveries, err := h.ucase.ListWebhookDeliveries(ctx.Context(), user.UserID, orgId, webhookId, page)
+	if err != nil {
+		return WrapError(err)
+	}
+	return ReturnJson(ctx, deliveries)
+}
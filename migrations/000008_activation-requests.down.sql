@@ -0,0 +1,11 @@
+BEGIN;
+
+DROP INDEX idx_users_inactive;
+
+ALTER TABLE login_code
+    DROP CONSTRAINT login_code_user_id_fkey,
+    ADD CONSTRAINT login_code_user_id_fkey FOREIGN KEY (user_id) REFERENCES users;
+
+DROP TABLE activation_requests;
+
+COMMIT;
@@ -0,0 +1,17 @@
+BEGIN;
+
+CREATE TABLE activation_requests
+(
+    user_id      int8                     NOT NULL REFERENCES users ON DELETE CASCADE,
+    requested_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_activation_requests ON activation_requests (user_id, requested_at);
+
+ALTER TABLE login_code
+    DROP CONSTRAINT login_code_user_id_fkey,
+    ADD CONSTRAINT login_code_user_id_fkey FOREIGN KEY (user_id) REFERENCES users ON DELETE CASCADE;
+
+CREATE INDEX idx_users_inactive ON users (created_at) WHERE is_active = FALSE;
+
+COMMIT;
@@ -0,0 +1,14 @@
+BEGIN;
+
+CREATE TABLE activation_tokens
+(
+    token_id   varchar(64)              NOT NULL PRIMARY KEY,
+    user_id    int8                     NOT NULL REFERENCES users ON DELETE CASCADE,
+    issued_at  TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    used_at    TIMESTAMP WITH TIME ZONE NULL     DEFAULT NULL
+);
+
+CREATE INDEX idx_activation_tokens_user ON activation_tokens (user_id);
+
+COMMIT;
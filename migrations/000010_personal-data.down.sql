@@ -0,0 +1,12 @@
+BEGIN;
+
+DROP TABLE data_exports;
+
+ALTER TABLE login_code
+    DROP COLUMN created_at,
+    DROP COLUMN used_at;
+
+ALTER TABLE users
+    DROP COLUMN erased_at;
+
+COMMIT;
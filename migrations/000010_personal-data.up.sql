@@ -0,0 +1,24 @@
+BEGIN;
+
+ALTER TABLE users
+    ADD COLUMN erased_at TIMESTAMP WITH TIME ZONE NULL DEFAULT NULL;
+
+ALTER TABLE login_code
+    ADD COLUMN created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    ADD COLUMN used_at    TIMESTAMP WITH TIME ZONE NULL     DEFAULT NULL;
+
+CREATE TABLE data_exports
+(
+    export_id   int8                     NOT NULL PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+    user_id     int8                     NOT NULL REFERENCES users ON DELETE CASCADE,
+    status      varchar(16)              NOT NULL DEFAULT 'pending',
+    archive     bytea                    NULL     DEFAULT NULL,
+    error       TEXT                     NULL     DEFAULT NULL,
+    created_at  TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    finished_at TIMESTAMP WITH TIME ZONE NULL     DEFAULT NULL
+);
+
+CREATE INDEX idx_data_exports_user ON data_exports (user_id);
+CREATE INDEX idx_data_exports_pending ON data_exports (created_at) WHERE status = 'pending';
+
+COMMIT;
@@ -0,0 +1,14 @@
+BEGIN;
+
+DROP TABLE audit_log;
+
+ALTER TABLE events
+    DROP COLUMN hidden_at,
+    DROP COLUMN hidden_reason;
+
+ALTER TABLE users
+    DROP COLUMN is_admin,
+    DROP COLUMN banned_at,
+    DROP COLUMN ban_reason;
+
+COMMIT;
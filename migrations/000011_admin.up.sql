@@ -0,0 +1,26 @@
+BEGIN;
+
+ALTER TABLE users
+    ADD COLUMN is_admin   bool                     NOT NULL DEFAULT FALSE,
+    ADD COLUMN banned_at  TIMESTAMP WITH TIME ZONE NULL     DEFAULT NULL,
+    ADD COLUMN ban_reason TEXT                     NULL     DEFAULT NULL;
+
+ALTER TABLE events
+    ADD COLUMN hidden_at     TIMESTAMP WITH TIME ZONE NULL DEFAULT NULL,
+    ADD COLUMN hidden_reason TEXT                     NULL DEFAULT NULL;
+
+CREATE TABLE audit_log
+(
+    audit_id    int8                     NOT NULL PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+    actor_id    int8                     NULL REFERENCES users ON DELETE SET NULL,
+    action      varchar(64)              NOT NULL,
+    target_type varchar(32)              NOT NULL,
+    target_id   int8                     NOT NULL,
+    details     jsonb                    NOT NULL DEFAULT '{}',
+    created_at  TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_audit_log_target ON audit_log (target_type, target_id);
+CREATE INDEX idx_audit_log_created_at ON audit_log (created_at);
+
+COMMIT;
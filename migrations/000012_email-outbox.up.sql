@@ -0,0 +1,22 @@
+BEGIN;
+
+CREATE TABLE email_outbox
+(
+    message_id      int8                     NOT NULL PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+    user_id         int8                     NULL REFERENCES users ON DELETE CASCADE,
+    kind            varchar(32)              NOT NULL,
+    to_address      varchar(64)              NOT NULL,
+    to_name         varchar(128)             NOT NULL DEFAULT '',
+    body            TEXT                     NOT NULL,
+    status          varchar(16)              NOT NULL DEFAULT 'pending',
+    attempts        int4                     NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    last_error      TEXT                     NULL     DEFAULT NULL,
+    created_at      TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    sent_at         TIMESTAMP WITH TIME ZONE NULL     DEFAULT NULL
+);
+
+CREATE INDEX idx_email_outbox_due ON email_outbox (next_attempt_at) WHERE status = 'pending';
+CREATE INDEX idx_email_outbox_status ON email_outbox (status, message_id);
+
+COMMIT;
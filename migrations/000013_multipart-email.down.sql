@@ -0,0 +1,10 @@
+BEGIN;
+
+ALTER TABLE email_outbox
+    DROP COLUMN subject,
+    DROP COLUMN html_body;
+
+ALTER TABLE email_outbox
+    RENAME COLUMN text_body TO body;
+
+COMMIT;
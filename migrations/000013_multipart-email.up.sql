@@ -0,0 +1,10 @@
+BEGIN;
+
+ALTER TABLE email_outbox
+    RENAME COLUMN body TO text_body;
+
+ALTER TABLE email_outbox
+    ADD COLUMN subject   varchar(256) NOT NULL DEFAULT '',
+    ADD COLUMN html_body TEXT         NOT NULL DEFAULT '';
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE users
+    ADD COLUMN preferred_locale varchar(16) NOT NULL DEFAULT 'ru';
+
+COMMIT;
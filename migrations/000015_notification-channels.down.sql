@@ -0,0 +1,14 @@
+BEGIN;
+
+DROP TABLE telegram_link_tokens;
+DROP TABLE user_channels;
+
+ALTER TABLE message_outbox
+    DROP COLUMN channel;
+
+ALTER INDEX idx_message_outbox_due RENAME TO idx_email_outbox_due;
+ALTER INDEX idx_message_outbox_status RENAME TO idx_email_outbox_status;
+ALTER TABLE message_outbox
+    RENAME TO email_outbox;
+
+COMMIT;
@@ -0,0 +1,31 @@
+BEGIN;
+
+ALTER TABLE email_outbox
+    RENAME TO message_outbox;
+ALTER INDEX idx_email_outbox_due RENAME TO idx_message_outbox_due;
+ALTER INDEX idx_email_outbox_status RENAME TO idx_message_outbox_status;
+
+ALTER TABLE message_outbox
+    ADD COLUMN channel varchar(16) NOT NULL DEFAULT 'email';
+
+CREATE TABLE user_channels
+(
+    user_id   int8                     NOT NULL REFERENCES users ON DELETE CASCADE,
+    channel   varchar(16)              NOT NULL,
+    address   varchar(64)              NOT NULL,
+    enabled   bool                     NOT NULL DEFAULT TRUE,
+    priority  int4                     NOT NULL DEFAULT 0,
+    linked_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    PRIMARY KEY (user_id, channel)
+);
+
+CREATE UNIQUE INDEX unique_user_channels_telegram ON user_channels (address) WHERE channel = 'telegram';
+
+CREATE TABLE telegram_link_tokens
+(
+    token      varchar(64)              NOT NULL PRIMARY KEY,
+    user_id    int8                     NOT NULL REFERENCES users ON DELETE CASCADE,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP TABLE event_reminders;
+DROP TABLE event_registrations;
+
+COMMIT;
@@ -0,0 +1,25 @@
+BEGIN;
+
+CREATE TABLE event_registrations
+(
+    event_id   int8                     NOT NULL REFERENCES events ON DELETE CASCADE,
+    user_id    int8                     NOT NULL REFERENCES users ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    PRIMARY KEY (event_id, user_id)
+);
+
+CREATE INDEX idx_event_registrations_user ON event_registrations (user_id);
+
+CREATE TABLE event_reminders
+(
+    reminder_id    int8                     NOT NULL PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+    event_id       int8                     NOT NULL REFERENCES events ON DELETE CASCADE,
+    offset_seconds int8                     NOT NULL,
+    remind_at      TIMESTAMP WITH TIME ZONE NOT NULL,
+    sent_at        TIMESTAMP WITH TIME ZONE NULL DEFAULT NULL,
+    CONSTRAINT unique_event_reminders_offset UNIQUE (event_id, offset_seconds)
+);
+
+CREATE INDEX idx_event_reminders_due ON event_reminders (remind_at) WHERE sent_at IS NULL;
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE events
+    DROP COLUMN cancelled_at,
+    DROP COLUMN cancel_reason;
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE events
+    ADD COLUMN cancelled_at  TIMESTAMP WITH TIME ZONE NULL DEFAULT NULL,
+    ADD COLUMN cancel_reason TEXT                     NULL DEFAULT NULL;
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP TABLE event_fanouts;
+DROP TABLE organization_follows;
+
+COMMIT;
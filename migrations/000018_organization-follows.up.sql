@@ -0,0 +1,27 @@
+BEGIN;
+
+CREATE TABLE organization_follows
+(
+    organization_id int8                     NOT NULL REFERENCES organizations ON DELETE CASCADE,
+    user_id         int8                     NOT NULL REFERENCES users ON DELETE CASCADE,
+    created_at      TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    PRIMARY KEY (organization_id, user_id)
+);
+
+CREATE INDEX idx_organization_follows_user ON organization_follows (user_id);
+
+-- Notifications of followers about published events. Followers are notified in batches ordered by user id,
+-- last_user_id is the last notified follower.
+CREATE TABLE event_fanouts
+(
+    fanout_id    int8                     NOT NULL PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+    event_id     int8                     NOT NULL REFERENCES events ON DELETE CASCADE,
+    last_user_id int8                     NOT NULL DEFAULT 0,
+    created_at   TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    done_at      TIMESTAMP WITH TIME ZONE NULL     DEFAULT NULL,
+    CONSTRAINT unique_event_fanouts_event UNIQUE (event_id)
+);
+
+CREATE INDEX idx_event_fanouts_pending ON event_fanouts (fanout_id) WHERE done_at IS NULL;
+
+COMMIT;
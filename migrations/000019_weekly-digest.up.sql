@@ -0,0 +1,18 @@
+BEGIN;
+
+-- Weekly digest is sent on weekday (0 is Sunday) at send_time in the timezone of the user.
+-- next_send_at is the start of the next period, the digest is sent once it is passed.
+CREATE TABLE digest_settings
+(
+    user_id      int8                     NOT NULL PRIMARY KEY REFERENCES users ON DELETE CASCADE,
+    enabled      bool                     NOT NULL DEFAULT false,
+    weekday      int2                     NOT NULL DEFAULT 1 CHECK (weekday BETWEEN 0 AND 6),
+    send_time    TIME                     NOT NULL DEFAULT '09:00',
+    timezone     TEXT                     NOT NULL DEFAULT 'UTC',
+    next_send_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    last_sent_at TIMESTAMP WITH TIME ZONE NULL     DEFAULT NULL
+);
+
+CREATE INDEX idx_digest_settings_due ON digest_settings (next_send_at) WHERE enabled;
+
+COMMIT;
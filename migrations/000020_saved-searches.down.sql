@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP TABLE search_alert_jobs;
+DROP TABLE saved_searches;
+
+COMMIT;
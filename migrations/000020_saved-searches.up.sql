@@ -0,0 +1,32 @@
+BEGIN;
+
+CREATE TABLE saved_searches
+(
+    search_id         int8                     NOT NULL PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+    user_id           int8                     NOT NULL REFERENCES users ON DELETE CASCADE,
+    name              TEXT                     NOT NULL,
+    filter            JSONB                    NOT NULL,
+    alerts_enabled    bool                     NOT NULL DEFAULT true,
+    unsubscribe_token TEXT                     NOT NULL,
+    created_at        TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    CONSTRAINT unique_saved_searches_unsubscribe_token UNIQUE (unsubscribe_token)
+);
+
+CREATE INDEX idx_saved_searches_user ON saved_searches (user_id);
+CREATE INDEX idx_saved_searches_alerts ON saved_searches (search_id) WHERE alerts_enabled;
+
+-- Matching of published events against saved searches. Searches are scanned in batches ordered by id,
+-- last_search_id is the last checked search.
+CREATE TABLE search_alert_jobs
+(
+    job_id         int8                     NOT NULL PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+    event_id       int8                     NOT NULL REFERENCES events ON DELETE CASCADE,
+    last_search_id int8                     NOT NULL DEFAULT 0,
+    created_at     TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    done_at        TIMESTAMP WITH TIME ZONE NULL     DEFAULT NULL,
+    CONSTRAINT unique_search_alert_jobs_event UNIQUE (event_id)
+);
+
+CREATE INDEX idx_search_alert_jobs_pending ON search_alert_jobs (job_id) WHERE done_at IS NULL;
+
+COMMIT;
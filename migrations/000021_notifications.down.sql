@@ -0,0 +1,6 @@
+BEGIN;
+
+DELETE FROM user_channels WHERE channel = 'inapp';
+DROP TABLE notifications;
+
+COMMIT;
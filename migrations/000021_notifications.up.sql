@@ -0,0 +1,17 @@
+BEGIN;
+
+CREATE TABLE notifications
+(
+    notification_id int8                     NOT NULL PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+    user_id         int8                     NOT NULL REFERENCES users ON DELETE CASCADE,
+    kind            varchar(64)              NOT NULL,
+    title           TEXT                     NOT NULL,
+    body            TEXT                     NOT NULL,
+    created_at      TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    read_at         TIMESTAMP WITH TIME ZONE NULL     DEFAULT NULL
+);
+
+CREATE INDEX idx_notifications_user ON notifications (user_id, notification_id DESC);
+CREATE INDEX idx_notifications_unread ON notifications (user_id) WHERE read_at IS NULL;
+
+COMMIT;
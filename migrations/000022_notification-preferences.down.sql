@@ -0,0 +1,8 @@
+BEGIN;
+
+ALTER TABLE message_outbox
+    DROP COLUMN unsubscribe_url;
+
+DROP TABLE notification_preferences;
+
+COMMIT;
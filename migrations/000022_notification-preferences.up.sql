@@ -0,0 +1,15 @@
+BEGIN;
+
+CREATE TABLE notification_preferences
+(
+    user_id  int8        NOT NULL REFERENCES users ON DELETE CASCADE,
+    channel  varchar(16) NOT NULL,
+    category varchar(16) NOT NULL,
+    enabled  bool        NOT NULL,
+    PRIMARY KEY (user_id, channel, category)
+);
+
+ALTER TABLE message_outbox
+    ADD COLUMN unsubscribe_url TEXT NOT NULL DEFAULT '';
+
+COMMIT;
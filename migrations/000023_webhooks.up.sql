@@ -0,0 +1,36 @@
+BEGIN;
+
+CREATE TABLE webhooks
+(
+    webhook_id           int8                     NOT NULL PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+    organization_id      int8                     NOT NULL REFERENCES organizations ON DELETE CASCADE,
+    url                  TEXT                     NOT NULL,
+    secret               varchar(64)              NOT NULL,
+    event_types          JSONB                    NOT NULL,
+    enabled              bool                     NOT NULL DEFAULT true,
+    consecutive_failures int4                     NOT NULL DEFAULT 0,
+    disabled_at          TIMESTAMP WITH TIME ZONE NULL     DEFAULT NULL,
+    created_at           TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_webhooks_organization ON webhooks (organization_id);
+
+CREATE TABLE webhook_deliveries
+(
+    delivery_id      int8                     NOT NULL PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+    webhook_id       int8                     NOT NULL REFERENCES webhooks ON DELETE CASCADE,
+    event_type       varchar(32)              NOT NULL,
+    payload          TEXT                     NOT NULL,
+    status           varchar(16)              NOT NULL DEFAULT 'pending',
+    attempts         int4                     NOT NULL DEFAULT 0,
+    next_attempt_at  TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    last_status_code int4                     NULL     DEFAULT NULL,
+    last_error       TEXT                     NULL     DEFAULT NULL,
+    created_at       TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    delivered_at     TIMESTAMP WITH TIME ZONE NULL     DEFAULT NULL
+);
+
+CREATE INDEX idx_webhook_deliveries_due ON webhook_deliveries (next_attempt_at) WHERE status = 'pending';
+CREATE INDEX idx_webhook_deliveries_webhook ON webhook_deliveries (webhook_id, delivery_id DESC);
+
+COMMIT;
@@ -0,0 +1,9 @@
+BEGIN;
+
+ALTER TABLE events
+    DROP COLUMN venue_id,
+    DROP COLUMN online_url;
+
+DROP TABLE venues;
+
+COMMIT;
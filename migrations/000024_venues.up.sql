@@ -0,0 +1,28 @@
+BEGIN;
+
+CREATE TABLE venues
+(
+    venue_id        int8                     NOT NULL PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+    organization_id int8                     NOT NULL REFERENCES organizations ON DELETE CASCADE,
+    name            varchar(256)             NOT NULL,
+    address         varchar(512)             NOT NULL,
+    latitude        float8                   NOT NULL CHECK (latitude BETWEEN -90 AND 90),
+    longitude       float8                   NOT NULL CHECK (longitude BETWEEN -180 AND 180),
+    accessibility   TEXT                     NOT NULL DEFAULT '',
+    capacity        int4                     NULL     DEFAULT NULL CHECK (capacity > 0),
+    created_at      TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_venues_organization ON venues (organization_id);
+-- Geographic filters narrow venues down by the bounding box before computing distances.
+CREATE INDEX idx_venues_location ON venues (latitude, longitude);
+
+-- Venues linked to events can't be deleted, so events don't lose their location silently.
+-- The check is not RESTRICT, so deletion of the organization cascades to both events and venues.
+ALTER TABLE events
+    ADD COLUMN venue_id   int8 NULL DEFAULT NULL REFERENCES venues,
+    ADD COLUMN online_url TEXT NULL DEFAULT NULL;
+
+CREATE INDEX idx_events_venue ON events (venue_id);
+
+COMMIT;
@@ -0,0 +1,10 @@
+BEGIN;
+
+DROP TABLE event_tags;
+
+ALTER TABLE events
+    DROP COLUMN category_id;
+
+DROP TABLE categories;
+
+COMMIT;
@@ -0,0 +1,33 @@
+BEGIN;
+
+-- Categories form a tree managed by platform administrators. Categories with subcategories or events
+-- can't be deleted, so the browse UI doesn't lose events silently.
+CREATE TABLE categories
+(
+    category_id int8                     NOT NULL PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+    parent_id   int8                     NULL     DEFAULT NULL REFERENCES categories,
+    slug        varchar(64)              NOT NULL UNIQUE,
+    name        varchar(128)             NOT NULL,
+    created_at  TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    CHECK (parent_id <> category_id)
+);
+
+CREATE INDEX idx_categories_parent ON categories (parent_id);
+
+ALTER TABLE events
+    ADD COLUMN category_id int8 NULL DEFAULT NULL REFERENCES categories;
+
+CREATE INDEX idx_events_category ON events (category_id);
+
+-- Tags are lowercase free-form labels assigned by organizers.
+CREATE TABLE event_tags
+(
+    event_id int8        NOT NULL REFERENCES events ON DELETE CASCADE,
+    tag      varchar(32) NOT NULL,
+    PRIMARY KEY (event_id, tag)
+);
+
+-- Serves both tag filters and prefix autocomplete.
+CREATE INDEX idx_event_tags_tag ON event_tags (tag varchar_pattern_ops);
+
+COMMIT;
@@ -0,0 +1,16 @@
+BEGIN;
+
+DELETE FROM event_registrations WHERE occurrence_id IS NOT NULL;
+
+ALTER TABLE event_registrations
+    DROP COLUMN occurrence_id,
+    DROP COLUMN registration_id,
+    ADD PRIMARY KEY (event_id, user_id);
+
+DROP TABLE event_occurrences;
+
+ALTER TABLE events
+    DROP COLUMN recurrence_rule,
+    DROP COLUMN timezone;
+
+COMMIT;
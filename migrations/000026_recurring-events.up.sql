@@ -0,0 +1,34 @@
+BEGIN;
+
+ALTER TABLE events
+    ADD COLUMN recurrence_rule TEXT        NULL     DEFAULT NULL,
+    ADD COLUMN timezone        varchar(64) NOT NULL DEFAULT 'UTC';
+
+-- Occurrences of recurring events are materialized for a limited period ahead, so they can be
+-- queried and registered for. RECURRENCE-ID identifies the occurrence in the series even if it is moved.
+CREATE TABLE event_occurrences
+(
+    occurrence_id int8                     NOT NULL PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+    event_id      int8                     NOT NULL REFERENCES events ON DELETE CASCADE,
+    recurrence_id TIMESTAMP WITH TIME ZONE NOT NULL,
+    begins_at     TIMESTAMP WITH TIME ZONE NOT NULL,
+    ends_at       TIMESTAMP WITH TIME ZONE NOT NULL,
+    name          varchar(256)             NULL     DEFAULT NULL,
+    description   TEXT                     NULL     DEFAULT NULL,
+    overridden    bool                     NOT NULL DEFAULT false,
+    cancelled_at  TIMESTAMP WITH TIME ZONE NULL     DEFAULT NULL,
+    CONSTRAINT unique_event_occurrences_recurrence UNIQUE (event_id, recurrence_id)
+);
+
+CREATE INDEX idx_event_occurrences_begins ON event_occurrences (begins_at) WHERE cancelled_at IS NULL;
+
+-- Users register for each occurrence of recurring events separately.
+ALTER TABLE event_registrations
+    DROP CONSTRAINT event_registrations_pkey,
+    ADD COLUMN registration_id int8 NOT NULL PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+    ADD COLUMN occurrence_id   int8 NULL DEFAULT NULL REFERENCES event_occurrences ON DELETE CASCADE;
+
+CREATE UNIQUE INDEX unique_event_registrations ON event_registrations (event_id, user_id, COALESCE(occurrence_id, 0));
+CREATE INDEX idx_event_registrations_occurrence ON event_registrations (occurrence_id);
+
+COMMIT;
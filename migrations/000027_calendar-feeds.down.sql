@@ -0,0 +1,9 @@
+BEGIN;
+
+DROP TABLE calendar_feeds;
+
+ALTER TABLE events
+    DROP COLUMN sequence,
+    DROP COLUMN updated_at;
+
+COMMIT;
@@ -0,0 +1,24 @@
+BEGIN;
+
+-- SEQUENCE of iCalendar is incremented on every change of the event or its occurrences,
+-- so calendar apps replace their copies.
+ALTER TABLE events
+    ADD COLUMN sequence   int4                     NOT NULL DEFAULT 0,
+    ADD COLUMN updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now();
+
+-- Subscribable iCalendar feeds. Anyone knowing the token can read the feed, so feeds are revoked by deletion.
+CREATE TABLE calendar_feeds
+(
+    feed_id         int8                     NOT NULL PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+    user_id         int8                     NOT NULL REFERENCES users ON DELETE CASCADE,
+    kind            varchar(16)              NOT NULL CHECK (kind IN ('registrations', 'organization', 'search')),
+    organization_id int8                     NULL     DEFAULT NULL REFERENCES organizations ON DELETE CASCADE,
+    search_id       int8                     NULL     DEFAULT NULL REFERENCES saved_searches ON DELETE CASCADE,
+    token           TEXT                     NOT NULL,
+    created_at      TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    CONSTRAINT unique_calendar_feeds_token UNIQUE (token)
+);
+
+CREATE INDEX idx_calendar_feeds_user ON calendar_feeds (user_id);
+
+COMMIT;
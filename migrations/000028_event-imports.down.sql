@@ -0,0 +1,10 @@
+BEGIN;
+
+DROP TABLE event_imports;
+
+DROP INDEX unique_events_external_uid;
+
+ALTER TABLE events
+    DROP COLUMN external_uid;
+
+COMMIT;
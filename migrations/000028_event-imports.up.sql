@@ -0,0 +1,32 @@
+BEGIN;
+
+-- External UID is the identifier of imported event in the calendar it comes from,
+-- so re-imports update events instead of duplicating them.
+ALTER TABLE events
+    ADD COLUMN external_uid TEXT NULL DEFAULT NULL;
+
+CREATE UNIQUE INDEX unique_events_external_uid ON events (organization_id, external_uid);
+
+CREATE TABLE event_imports
+(
+    import_id       int8                     NOT NULL PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+    organization_id int8                     NOT NULL REFERENCES organizations ON DELETE CASCADE,
+    user_id         int8                     NOT NULL REFERENCES users ON DELETE CASCADE,
+    format          varchar(8)               NOT NULL CHECK (format IN ('ics', 'csv')),
+    timezone        TEXT                     NOT NULL,
+    data            bytea                    NOT NULL,
+    status          varchar(16)              NOT NULL DEFAULT 'pending',
+    total           int4                     NOT NULL DEFAULT 0,
+    processed       int4                     NOT NULL DEFAULT 0,
+    report          jsonb                    NULL     DEFAULT NULL,
+    error           TEXT                     NULL     DEFAULT NULL,
+    created_at      TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    -- updated_at is bumped with the progress, running imports which stopped progressing are claimed again.
+    updated_at      TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    finished_at     TIMESTAMP WITH TIME ZONE NULL     DEFAULT NULL
+);
+
+CREATE INDEX idx_event_imports_organization ON event_imports (organization_id);
+CREATE INDEX idx_event_imports_unfinished ON event_imports (created_at) WHERE status IN ('pending', 'running');
+
+COMMIT;
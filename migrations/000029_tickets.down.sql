@@ -0,0 +1,7 @@
+BEGIN;
+
+DROP TABLE orders;
+DROP TABLE promo_codes;
+DROP TABLE ticket_types;
+
+COMMIT;
@@ -0,0 +1,61 @@
+BEGIN;
+
+-- Prices and amounts are in minor units of the currency, such as kopecks.
+CREATE TABLE ticket_types
+(
+    ticket_type_id int8                     NOT NULL PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+    event_id       int8                     NOT NULL REFERENCES events ON DELETE CASCADE,
+    name           varchar(128)             NOT NULL,
+    price          int8                     NOT NULL CHECK (price >= 0),
+    currency       char(3)                  NOT NULL,
+    -- quantity is NULL for tickets which are not limited.
+    quantity       int4                     NULL     DEFAULT NULL CHECK (quantity > 0),
+    sales_begin    TIMESTAMP WITH TIME ZONE NULL     DEFAULT NULL,
+    sales_end      TIMESTAMP WITH TIME ZONE NULL     DEFAULT NULL,
+    created_at     TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_ticket_types_event ON ticket_types (event_id);
+
+CREATE TABLE promo_codes
+(
+    promo_code_id int8                     NOT NULL PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+    event_id      int8                     NOT NULL REFERENCES events ON DELETE CASCADE,
+    code          varchar(32)              NOT NULL,
+    kind          varchar(16)              NOT NULL CHECK (kind IN ('percent', 'fixed')),
+    -- amount is percents for percent codes and minor units of the currency for fixed ones.
+    amount        int8                     NOT NULL CHECK (amount > 0),
+    currency      char(3)                  NULL     DEFAULT NULL,
+    max_uses      int4                     NULL     DEFAULT NULL CHECK (max_uses > 0),
+    expires_at    TIMESTAMP WITH TIME ZONE NULL     DEFAULT NULL,
+    created_at    TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    CONSTRAINT unique_promo_codes_code UNIQUE (event_id, code)
+);
+
+-- Reserved orders hold tickets until they expire, paid orders hold them for good.
+CREATE TABLE orders
+(
+    order_id       int8                     NOT NULL PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+    event_id       int8                     NOT NULL REFERENCES events ON DELETE CASCADE,
+    user_id        int8                     NOT NULL REFERENCES users ON DELETE CASCADE,
+    ticket_type_id int8                     NOT NULL REFERENCES ticket_types ON DELETE RESTRICT,
+    quantity       int4                     NOT NULL CHECK (quantity > 0),
+    promo_code_id  int8                     NULL     DEFAULT NULL REFERENCES promo_codes ON DELETE SET NULL,
+    currency       char(3)                  NOT NULL,
+    subtotal       int8                     NOT NULL,
+    discount       int8                     NOT NULL DEFAULT 0,
+    total          int8                     NOT NULL,
+    status         varchar(16)              NOT NULL CHECK (status IN ('reserved', 'paid', 'expired', 'cancelled')),
+    payment_id     TEXT                     NULL     DEFAULT NULL,
+    payment_url    TEXT                     NULL     DEFAULT NULL,
+    expires_at     TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at     TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    paid_at        TIMESTAMP WITH TIME ZONE NULL     DEFAULT NULL
+);
+
+CREATE INDEX idx_orders_user ON orders (user_id);
+CREATE INDEX idx_orders_ticket_type ON orders (ticket_type_id) WHERE status IN ('reserved', 'paid');
+CREATE INDEX idx_orders_promo_code ON orders (promo_code_id) WHERE status IN ('reserved', 'paid');
+CREATE INDEX idx_orders_reserved ON orders (expires_at) WHERE status = 'reserved';
+
+COMMIT;
@@ -0,0 +1,8 @@
+BEGIN;
+
+DROP TABLE check_ins;
+
+ALTER TABLE organization_members
+    DROP COLUMN can_check_in;
+
+COMMIT;
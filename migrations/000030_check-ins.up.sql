@@ -0,0 +1,20 @@
+BEGIN;
+
+ALTER TABLE organization_members
+    ADD COLUMN can_check_in bool NOT NULL DEFAULT FALSE;
+
+-- Registration is checked in once. Scanners sync check-ins made offline later,
+-- checked_in_at is the time of the scan and created_at is the time it reached the server.
+CREATE TABLE check_ins
+(
+    registration_id int8                     NOT NULL PRIMARY KEY REFERENCES event_registrations ON DELETE CASCADE,
+    event_id        int8                     NOT NULL REFERENCES events ON DELETE CASCADE,
+    checked_in_by   int8                     NULL     DEFAULT NULL REFERENCES users ON DELETE SET NULL,
+    device_id       varchar(64)              NULL     DEFAULT NULL,
+    checked_in_at   TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at      TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_check_ins_event ON check_ins (event_id);
+
+COMMIT;
@@ -0,0 +1,11 @@
+BEGIN;
+
+DROP MATERIALIZED VIEW event_activity_stats;
+
+ALTER TABLE orders
+    DROP COLUMN source;
+
+ALTER TABLE event_registrations
+    DROP COLUMN source;
+
+COMMIT;
@@ -0,0 +1,42 @@
+BEGIN;
+
+-- Source is where the user came from to register, such as a campaign or a partner site.
+ALTER TABLE event_registrations
+    ADD COLUMN source varchar(64) NULL DEFAULT NULL;
+
+ALTER TABLE orders
+    ADD COLUMN source varchar(64) NULL DEFAULT NULL;
+
+-- Registrations and check-ins of events by hour and traffic source. The view is refreshed periodically,
+-- analytics roll hours up to longer intervals. Empty source stands for registrations without source.
+CREATE MATERIALIZED VIEW event_activity_stats AS
+SELECT e.event_id,
+       e.organization_id,
+       a.bucket,
+       a.source,
+       sum(a.registrations)::int8 AS registrations,
+       sum(a.check_ins)::int8     AS check_ins
+FROM (SELECT r.event_id,
+             date_trunc('hour', r.created_at AT TIME ZONE 'UTC') AS bucket,
+             COALESCE(r.source, '')                             AS source,
+             count(*)                                           AS registrations,
+             0                                                  AS check_ins
+      FROM event_registrations r
+      GROUP BY 1, 2, 3
+      UNION ALL
+      SELECT c.event_id,
+             date_trunc('hour', c.checked_in_at AT TIME ZONE 'UTC'),
+             COALESCE(r.source, ''),
+             0,
+             count(*)
+      FROM check_ins c
+               JOIN event_registrations r ON r.registration_id = c.registration_id
+      GROUP BY 1, 2, 3) a
+         JOIN events e ON e.event_id = a.event_id
+GROUP BY e.event_id, e.organization_id, a.bucket, a.source;
+
+-- Unique index is required to refresh the view concurrently.
+CREATE UNIQUE INDEX unique_event_activity_stats ON event_activity_stats (event_id, bucket, source);
+CREATE INDEX idx_event_activity_stats_organization ON event_activity_stats (organization_id, bucket);
+
+COMMIT;
@@ -0,0 +1,19 @@
+BEGIN;
+
+-- Accounts deactivated by administrators are told apart from never activated ones,
+-- so they are neither purged nor activated again by the user.
+ALTER TABLE users
+    ADD COLUMN deactivated_at TIMESTAMP WITH TIME ZONE NULL DEFAULT NULL;
+
+UPDATE users u
+SET deactivated_at = a.created_at
+FROM (SELECT target_id, max(created_at) AS created_at
+      FROM audit_log
+      WHERE action = 'user.deactivate'
+        AND target_type = 'user'
+      GROUP BY target_id) a
+WHERE u.user_id = a.target_id
+  AND u.is_active = false
+  AND u.erased_at IS NULL;
+
+COMMIT;
@@ -0,0 +1,19 @@
+BEGIN;
+
+-- Phone is linked only after the user confirms the code sent to it,
+-- otherwise login codes could be routed to a number of somebody else.
+CREATE TABLE phone_verifications
+(
+    user_id    int8                     NOT NULL PRIMARY KEY REFERENCES users ON DELETE CASCADE,
+    phone      varchar(64)              NOT NULL,
+    code       varchar(8)               NOT NULL,
+    attempts   int4                     NOT NULL DEFAULT 0,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+-- Numbers linked before were never verified
+DELETE
+FROM user_channels
+WHERE channel = 'sms';
+
+COMMIT;
@@ -0,0 +1,12 @@
+BEGIN;
+
+DELETE FROM event_reminders WHERE occurrence_id IS NOT NULL;
+
+DROP INDEX unique_occurrence_reminders_offset;
+DROP INDEX unique_event_reminders_offset;
+
+ALTER TABLE event_reminders
+    DROP COLUMN occurrence_id,
+    ADD CONSTRAINT unique_event_reminders_offset UNIQUE (event_id, offset_seconds);
+
+COMMIT;
@@ -0,0 +1,17 @@
+BEGIN;
+
+-- Recurring events are reminded about every occurrence, single events keep reminders of the event itself.
+ALTER TABLE event_reminders
+    DROP CONSTRAINT unique_event_reminders_offset,
+    ADD COLUMN occurrence_id int8 NULL DEFAULT NULL REFERENCES event_occurrences ON DELETE CASCADE;
+
+CREATE UNIQUE INDEX unique_event_reminders_offset ON event_reminders (event_id, offset_seconds)
+    WHERE occurrence_id IS NULL;
+CREATE UNIQUE INDEX unique_occurrence_reminders_offset ON event_reminders (occurrence_id, offset_seconds)
+    WHERE occurrence_id IS NOT NULL;
+
+-- Pending reminders of recurring events are about their first occurrence only, they are planned again per occurrence.
+DELETE FROM event_reminders r USING events e
+WHERE e.event_id = r.event_id AND e.recurrence_rule IS NOT NULL AND r.sent_at IS NULL;
+
+COMMIT;
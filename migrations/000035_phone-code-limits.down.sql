@@ -0,0 +1,8 @@
+BEGIN;
+
+ALTER TABLE phone_verifications
+    DROP COLUMN window_started_at;
+
+DROP TABLE phone_verification_requests;
+
+COMMIT;
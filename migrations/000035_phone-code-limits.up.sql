@@ -0,0 +1,16 @@
+BEGIN;
+
+-- Codes sent to phones are rate limited like activation emails, SMS cost money.
+CREATE TABLE phone_verification_requests
+(
+    user_id      int8                     NOT NULL REFERENCES users ON DELETE CASCADE,
+    requested_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_phone_verification_requests ON phone_verification_requests (user_id, requested_at);
+
+-- Attempts to enter codes are counted since the start of the window, so resending a code gives no extra guesses.
+ALTER TABLE phone_verifications
+    ADD COLUMN window_started_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now();
+
+COMMIT;
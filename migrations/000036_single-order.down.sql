@@ -0,0 +1,5 @@
+BEGIN;
+
+DROP INDEX orders_event_id_user_id_key;
+
+COMMIT;
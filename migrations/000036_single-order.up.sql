@@ -0,0 +1,6 @@
+BEGIN;
+
+-- Registrations are per user, so the user holds one order of the event's tickets at a time.
+CREATE UNIQUE INDEX orders_event_id_user_id_key ON orders (event_id, user_id) WHERE status IN ('reserved', 'paid');
+
+COMMIT;
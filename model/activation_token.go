@@ -6,6 +6,7 @@ import (
 )
 
 type ActivationToken struct {
+	TokenID   string
 	Token     string
 	UserId    int64
 	ExpiresAt time.Time
@@ -14,3 +15,7 @@ type ActivationToken struct {
 type ActivationTokenClaims struct {
 	jwt.RegisteredClaims
 }
+
+type ActivationResendRequest struct {
+	Email string `json:"email" validate:"required,email" example:"johndoe@example.com"`
+}
@@ -0,0 +1,77 @@
+package model
+
+import "time"
+
+// Intervals of analytics series.
+const (
+	IntervalHour  = "hour"
+	IntervalDay   = "day"
+	IntervalWeek  = "week"
+	IntervalMonth = "month"
+)
+
+// SourceDirect stands for registrations without traffic source.
+const SourceDirect = "direct"
+
+// AnalyticsQuery selects registrations and check-ins made within the period, dates are in UTC.
+type AnalyticsQuery struct {
+	From     string `query:"from" validate:"omitempty,datetime=2006-01-02" example:"2023-05-01"`
+	To       string `query:"to" validate:"omitempty,datetime=2006-01-02" example:"2023-05-31"`
+	Interval string `query:"interval" validate:"omitempty,oneof=hour day week month" example:"day"`
+}
+
+// AnalyticsFilter selects activity of the event or of all events of the organization.
+// Times are bounds of the period, To is excluded.
+type AnalyticsFilter struct {
+	EventID        *int64
+	OrganizationID *int64
+	From           *time.Time
+	To             *time.Time
+	Interval       string
+}
+
+// AttendanceStats compares registered users with checked in ones. Registered users who were not checked in
+// are no-shows, so the numbers are final once the event is over.
+type AttendanceStats struct {
+	Registered int64   `json:"registered" example:"120"`
+	Attended   int64   `json:"attended" example:"90"`
+	NoShows    int64   `json:"no_shows" example:"30"`
+	NoShowRate float64 `json:"no_show_rate" example:"0.25"`
+}
+
+// AnalyticsPoint is the number of registrations and check-ins within the interval beginning at Bucket.
+type AnalyticsPoint struct {
+	Bucket        time.Time `json:"bucket"`
+	Registrations int64     `json:"registrations" example:"12"`
+	CheckIns      int64     `json:"check_ins" example:"9"`
+}
+
+type TrafficSource struct {
+	Source        string `json:"source" example:"telegram"`
+	Registrations int64  `json:"registrations" example:"40"`
+	CheckIns      int64  `json:"check_ins" example:"31"`
+}
+
+type EventAttendance struct {
+	EventID  int64     `json:"event_id" example:"1"`
+	Name     string    `json:"name" example:"Concert"`
+	BeginsAt time.Time `json:"begins_at"`
+	AttendanceStats
+}
+
+type EventAnalytics struct {
+	EventID  int64  `json:"event_id" example:"1"`
+	Interval string `json:"interval" example:"day"`
+	AttendanceStats
+	Series  []AnalyticsPoint `json:"series"`
+	Sources []TrafficSource  `json:"sources"`
+}
+
+type OrganizationAnalytics struct {
+	OrganizationID int64  `json:"organization_id" example:"1"`
+	Interval       string `json:"interval" example:"day"`
+	AttendanceStats
+	Series  []AnalyticsPoint  `json:"series"`
+	Sources []TrafficSource   `json:"sources"`
+	Events  []EventAttendance `json:"events"`
+}
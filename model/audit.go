@@ -0,0 +1,54 @@
+package model
+
+import "time"
+
+const (
+	AuditUserActivate         = "user.activate"
+	AuditUserDeactivate       = "user.deactivate"
+	AuditUserBan              = "user.ban"
+	AuditUserUnban            = "user.unban"
+	AuditUserImpersonate      = "user.impersonate"
+	AuditImpersonatedRequest  = "user.impersonated_request"
+	AuditOrganizationTransfer = "organization.transfer_ownership"
+	AuditEventHide            = "event.hide"
+	AuditEventUnhide          = "event.unhide"
+	AuditOutboxReplay         = "email.replay"
+	AuditCategoryCreate       = "category.create"
+	AuditCategoryUpdate       = "category.update"
+	AuditCategoryDelete       = "category.delete"
+)
+
+const (
+	AuditTargetUser         = "user"
+	AuditTargetOrganization = "organization"
+	AuditTargetEvent        = "event"
+	AuditTargetEmail        = "email"
+	AuditTargetCategory     = "category"
+)
+
+type AuditEntryCreate struct {
+	ActorID    int64
+	Action     string
+	TargetType string
+	TargetID   int64
+	Details    map[string]interface{}
+}
+
+type AuditEntry struct {
+	AuditID    int64                  `json:"audit_id" example:"1"`
+	ActorID    *int64                 `json:"actor_id,omitempty" example:"1"`
+	Action     string                 `json:"action" example:"user.ban"`
+	TargetType string                 `json:"target_type" enums:"user,organization,event,email,category"`
+	TargetID   int64                  `json:"target_id" example:"2"`
+	Details    map[string]interface{} `json:"details"`
+	CreatedAt  time.Time              `json:"created_at"`
+}
+
+type Pagination struct {
+	Limit  int `query:"limit" validate:"omitempty,min=1,max=100" example:"20"`
+	Offset int `query:"offset" validate:"omitempty,min=0" example:"0"`
+}
+
+type OwnershipTransfer struct {
+	UserID int64 `json:"user_id" validate:"required,min=1" example:"2"`
+}
@@ -1,6 +1,9 @@
 package model
 
-import "github.com/golang-jwt/jwt/v5"
+import (
+	"github.com/golang-jwt/jwt/v5"
+	"time"
+)
 
 type AuthCredentials struct {
 	Email string `json:"username" form:"username" validate:"required,email,gte=1" example:"johndoe@example.com"`
@@ -13,6 +16,15 @@ type AuthTokenClaims struct {
 	Email     string `json:"email"`
 	FirstName string `json:"first_name"`
 	LastName  string `json:"last_name"`
+	Act       *Actor `json:"act,omitempty"`
+}
+
+// Actor is the party acting on behalf of the token subject (RFC 8693 "act" claim).
+// It is set only in impersonation tokens.
+type Actor struct {
+	Subject string `json:"sub" example:"1"`
+	UserID  int64  `json:"user_id" example:"1"`
+	Email   string `json:"email" example:"admin@example.com"`
 }
 
 type AuthPayload struct {
@@ -20,6 +32,11 @@ type AuthPayload struct {
 	Email     string `json:"email"`
 	FirstName string `json:"first_name"`
 	LastName  string `json:"last_name"`
+	Act       *Actor `json:"act,omitempty"`
+}
+
+func (p *AuthPayload) IsImpersonated() bool {
+	return p.Act != nil
 }
 
 type CodeRequest struct {
@@ -31,6 +48,11 @@ type Token struct {
 	AccessToken string `json:"access_token"`
 }
 
+type ImpersonationToken struct {
+	Token
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
 func NewAccessToken(token string) *Token {
 	return &Token{
 		Type:        "Bearer",
@@ -0,0 +1,31 @@
+package model
+
+import "time"
+
+// Kinds of calendar feeds.
+const (
+	CalendarFeedRegistrations = "registrations"
+	CalendarFeedOrganization  = "organization"
+	CalendarFeedSearch        = "search"
+)
+
+// CalendarFeed is a subscribable iCalendar feed. Anyone knowing its URL can read it, so feeds are revoked by deletion.
+type CalendarFeed struct {
+	FeedID int64 `json:"feed_id" example:"1"`
+	UserID int64 `json:"-"`
+	// Kind is what the feed contains: events the user has registered for, events of the organization
+	// or events matching the saved search.
+	Kind           string `json:"kind" enums:"registrations,organization,search" example:"organization"`
+	OrganizationID *int64 `json:"organization_id,omitempty" example:"1"`
+	SearchID       *int64 `json:"search_id,omitempty" example:"2"`
+	Token          string `json:"-"`
+	// URL is the address calendar apps subscribe to. Personal data exports omit it, since it grants access to the feed.
+	URL       string    `json:"url,omitempty" example:"https://events.example.com/feeds/0f3c9a.ics"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type CalendarFeedCreate struct {
+	Kind           string `json:"kind" validate:"required,oneof=registrations organization search" example:"organization"`
+	OrganizationID *int64 `json:"organization_id" validate:"required_if=Kind organization,omitempty,min=1" example:"1"`
+	SearchID       *int64 `json:"search_id" validate:"required_if=Kind search,omitempty,min=1" example:"2"`
+}
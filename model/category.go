@@ -0,0 +1,49 @@
+package model
+
+import "time"
+
+// Category is a node of the event taxonomy managed by platform administrators, e.g. music → concert.
+type Category struct {
+	CategoryID int64     `json:"category_id" example:"2"`
+	ParentID   *int64    `json:"parent_id,omitempty" example:"1"`
+	Slug       string    `json:"slug" example:"concert"`
+	Name       string    `json:"name" example:"Концерты"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type CategoryCreate struct {
+	ParentID *int64 `json:"parent_id" validate:"omitempty,min=1" example:"1"`
+	// Slug consists of lowercase latin letters and digits separated by hyphens.
+	Slug string `json:"slug" validate:"required,max=64" example:"concert"`
+	Name string `json:"name" validate:"required,max=128" example:"Концерты"`
+}
+
+type CategoryUpdate struct {
+	// ParentID moves the category, zero makes it a root category.
+	ParentID *int64  `json:"parent_id" validate:"omitempty,min=0" example:"1"`
+	Slug     *string `json:"slug" validate:"omitempty,min=1,max=64" example:"concert"`
+	Name     *string `json:"name" validate:"omitempty,min=1,max=128" example:"Концерты"`
+}
+
+// CategoryCount is the number of published upcoming events in the category.
+type CategoryCount struct {
+	CategoryID int64  `json:"category_id" example:"2"`
+	ParentID   *int64 `json:"parent_id,omitempty" example:"1"`
+	Slug       string `json:"slug" example:"concert"`
+	Name       string `json:"name" example:"Концерты"`
+	// Events is the number of events in the category itself.
+	Events int `json:"events" example:"3"`
+	// Total also counts events of all subcategories.
+	Total int `json:"total" example:"5"`
+}
+
+// TagSuggestion is a tag used by published upcoming events and the number of such events.
+type TagSuggestion struct {
+	Tag    string `json:"tag" example:"free"`
+	Events int    `json:"events" example:"12"`
+}
+
+type TagQuery struct {
+	Prefix string `query:"prefix" validate:"required,max=32" example:"fr"`
+	Limit  int    `query:"limit" validate:"omitempty,min=1,max=50" example:"10"`
+}
@@ -0,0 +1,64 @@
+package model
+
+import "time"
+
+const (
+	ChannelEmail    = "email"
+	ChannelTelegram = "telegram"
+	ChannelSMS      = "sms"
+	ChannelInApp    = "inapp"
+)
+
+// UserChannel is a channel the user receives notifications by.
+// Email channel is always linked to the user's email and in-app channel is always available,
+// other channels must be linked explicitly.
+type UserChannel struct {
+	UserID  int64  `json:"-"`
+	Channel string `json:"channel" enums:"email,telegram,sms,inapp"`
+	Address string `json:"address" example:"johndoe@example.com"`
+	Enabled bool   `json:"enabled" example:"true"`
+	// Channels with higher priority are preferred for messages sent to a single channel, like login codes.
+	Priority int       `json:"priority" example:"0"`
+	LinkedAt time.Time `json:"linked_at"`
+}
+
+type UserChannelUpdate struct {
+	Enabled  *bool `json:"enabled" example:"true"`
+	Priority *int  `json:"priority" validate:"omitempty,min=0,max=100" example:"10"`
+}
+
+type PhoneLink struct {
+	Phone string `json:"phone" validate:"required,e164" example:"+79991234567"`
+}
+
+// PhoneVerification is a pending link of the phone. The phone is linked once the code sent to it is confirmed.
+type PhoneVerification struct {
+	Phone     string    `json:"phone" example:"+79991234567"`
+	Code      string    `json:"-"`
+	Attempts  int       `json:"-"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type PhoneConfirmation struct {
+	Code string `json:"code" validate:"required,len=6,numeric" example:"123456"`
+}
+
+type TelegramLink struct {
+	URL       string    `json:"url" example:"https://t.me/events_bot?start=4f1c2a"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// TelegramUpdate is a part of Telegram Bot API update sent to the webhook.
+type TelegramUpdate struct {
+	UpdateID int64            `json:"update_id"`
+	Message  *TelegramMessage `json:"message"`
+}
+
+type TelegramMessage struct {
+	Text string       `json:"text"`
+	Chat TelegramChat `json:"chat"`
+}
+
+type TelegramChat struct {
+	ID int64 `json:"id"`
+}
@@ -0,0 +1,87 @@
+package model
+
+import "time"
+
+// Results of scanning tickets.
+const (
+	CheckInAccepted  = "checked_in"
+	CheckInDuplicate = "duplicate"
+	CheckInRejected  = "rejected"
+)
+
+// TicketClaims are signed into codes of tickets.
+type TicketClaims struct {
+	RegistrationID int64
+	EventID        int64
+}
+
+// Ticket admits the user registered for the event. Code is shown as a QR code and scanned at the entrance.
+type Ticket struct {
+	RegistrationID int64 `json:"registration_id" example:"1"`
+	EventID        int64 `json:"event_id" example:"1"`
+	// OccurrenceID is set for tickets for an occurrence of recurring event.
+	OccurrenceID *int64     `json:"occurrence_id,omitempty" example:"3"`
+	Code         string     `json:"code" example:"NzoxMg.c2lnbmF0dXJl"`
+	CheckedInAt  *time.Time `json:"checked_in_at,omitempty"`
+}
+
+type CheckIn struct {
+	RegistrationID int64  `json:"registration_id" example:"1"`
+	EventID        int64  `json:"event_id" example:"1"`
+	CheckedInBy    *int64 `json:"checked_in_by,omitempty" example:"2"`
+	// DeviceID identifies the scanner which checked in offline.
+	DeviceID *string `json:"device_id,omitempty" example:"scanner-1"`
+	// CheckedInAt is the time of the scan, CreatedAt is the time the check-in reached the server.
+	CheckedInAt time.Time `json:"checked_in_at"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type CheckInCreate struct {
+	Code string `json:"code" validate:"required,max=1024" example:"NzoxMg.c2lnbmF0dXJl"`
+	// OccurrenceID is the occurrence of recurring event checked in for, tickets for other occurrences are rejected.
+	// It is required for recurring events.
+	OccurrenceID *int64 `json:"occurrence_id" example:"3"`
+}
+
+// CheckInResult is the result of scanning a ticket. Duplicate results hold the earlier check-in.
+type CheckInResult struct {
+	Status   string    `json:"status" example:"checked_in"`
+	Reason   string    `json:"reason,omitempty" example:"ticket is for another event"`
+	Attendee *Attendee `json:"attendee,omitempty"`
+	CheckIn  *CheckIn  `json:"check_in,omitempty"`
+}
+
+type Attendee struct {
+	RegistrationID int64      `json:"registration_id" example:"1"`
+	EventID        int64      `json:"event_id" example:"1"`
+	OccurrenceID   *int64     `json:"occurrence_id,omitempty" example:"3"`
+	UserID         int64      `json:"user_id" example:"1"`
+	FirstName      string     `json:"first_name" example:"John"`
+	LastName       string     `json:"last_name" example:"Doe"`
+	CheckedInAt    *time.Time `json:"checked_in_at,omitempty"`
+}
+
+// AttendeeList is downloaded by scanners to check in offline. Codes of tickets are verified with PublicKey.
+type AttendeeList struct {
+	EventID     int64      `json:"event_id" example:"1"`
+	PublicKey   string     `json:"public_key"`
+	GeneratedAt time.Time  `json:"generated_at"`
+	Attendees   []Attendee `json:"attendees"`
+}
+
+type AttendeeListQuery struct {
+	OccurrenceID *int64 `query:"occurrence_id" validate:"omitempty,min=1"`
+}
+
+// CheckInSync uploads check-ins made by the scanner offline. The earliest scan of a ticket wins.
+type CheckInSync struct {
+	DeviceID string `json:"device_id" validate:"required,max=64" example:"scanner-1"`
+	// OccurrenceID is required for recurring events, as in CheckInCreate.
+	OccurrenceID *int64           `json:"occurrence_id" example:"3"`
+	CheckIns     []OfflineCheckIn `json:"check_ins" validate:"required,max=1000,dive"`
+}
+
+type OfflineCheckIn struct {
+	Code        string    `json:"code" validate:"required,max=1024" example:"NzoxMg.c2lnbmF0dXJl"`
+	CheckedInAt time.Time `json:"checked_in_at" validate:"required"`
+}
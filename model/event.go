@@ -12,23 +12,177 @@ type EventCreate struct {
 	RegistrationNeeded bool
 	RegistrationBegin  *time.Time
 	RegistrationEnd    *time.Time
+	VenueID            *int64
+	OnlineURL          *string
+	CategoryID         *int64
+	RecurrenceRule     *string
+	// Timezone defaults to UTC.
+	Timezone    string
+	ExternalUID *string
 }
 
 type Event struct {
-	EventID            int64      `db:"event_id"`
-	Name               string     `db:"name"`
-	OrganizationID     int64      `db:"organization_id"`
-	CreatorID          int64      `db:"creator_id"`
-	Description        string     `db:"description"`
-	RegistrationBegin  *time.Time `db:"registration_begin"`
-	RegistrationEnd    *time.Time `db:"registration_end"`
-	BeginsAt           time.Time  `db:"begins_at"`
-	EndsAt             time.Time  `db:"ends_at"`
-	RegistrationNeeded bool       `db:"registration_needed"`
-	CreatedAt          time.Time  `db:"created_at"`
-	PublishedAt        *time.Time `db:"published_at"`
+	EventID            int64      `json:"event_id" db:"event_id"`
+	Name               string     `json:"name" db:"name"`
+	OrganizationID     int64      `json:"organization_id" db:"organization_id"`
+	CreatorID          int64      `json:"creator_id" db:"creator_id"`
+	Description        string     `json:"description" db:"description"`
+	RegistrationBegin  *time.Time `json:"registration_begin,omitempty" db:"registration_begin"`
+	RegistrationEnd    *time.Time `json:"registration_end,omitempty" db:"registration_end"`
+	BeginsAt           time.Time  `json:"begins_at" db:"begins_at"`
+	EndsAt             time.Time  `json:"ends_at" db:"ends_at"`
+	RegistrationNeeded bool       `json:"registration_needed" db:"registration_needed"`
+	CreatedAt          time.Time  `json:"created_at" db:"created_at"`
+	PublishedAt        *time.Time `json:"published_at,omitempty" db:"published_at"`
+	HiddenAt           *time.Time `json:"hidden_at,omitempty" db:"hidden_at"`
+	CancelledAt        *time.Time `json:"cancelled_at,omitempty" db:"cancelled_at"`
+	CancelReason       *string    `json:"cancel_reason,omitempty" db:"cancel_reason"`
+	VenueID            *int64     `json:"venue_id,omitempty" db:"venue_id"`
+	// OnlineURL is the link to join online events. Events may have both venue and online URL.
+	OnlineURL  *string `json:"online_url,omitempty" db:"online_url"`
+	CategoryID *int64  `json:"category_id,omitempty" db:"category_id"`
+	// RecurrenceRule is RFC 5545 RRULE of recurring events. BeginsAt and EndsAt are the first occurrence then.
+	RecurrenceRule *string `json:"recurrence_rule,omitempty" db:"recurrence_rule" example:"FREQ=WEEKLY;BYDAY=TU"`
+	// Timezone is the timezone occurrences of recurring events keep their wall clock time in.
+	Timezone string `json:"timezone" db:"timezone" example:"Europe/Moscow"`
+	// Sequence is SEQUENCE of iCalendar, it is incremented on every change of the event or its occurrences.
+	Sequence  int       `json:"-" db:"sequence"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+	// ExternalUID is the UID of imported event in the calendar it comes from.
+	ExternalUID *string `json:"external_uid,omitempty" db:"external_uid"`
+	// Venue is loaded only by event search.
+	Venue *Venue `json:"venue,omitempty" db:"-"`
+	// Tags are loaded by event search and returned when they are updated.
+	Tags []string `json:"tags,omitempty" db:"-"`
 }
 
 func (e *Event) IsPublished() bool {
 	return e.PublishedAt != nil
 }
+
+func (e *Event) IsHidden() bool {
+	return e.HiddenAt != nil
+}
+
+func (e *Event) IsCancelled() bool {
+	return e.CancelledAt != nil
+}
+
+func (e *Event) IsRecurring() bool {
+	return e.RecurrenceRule != nil
+}
+
+type EventUpdate struct {
+	Name        *string    `json:"name" validate:"omitempty,min=3,max=256" example:"Открытая лекция"`
+	Description *string    `json:"description" validate:"omitempty,max=4096" example:"Лекция о городской среде"`
+	BeginsAt    *time.Time `json:"begins_at"`
+	EndsAt      *time.Time `json:"ends_at"`
+	// VenueID links the event to a venue of its organization, zero unlinks the venue.
+	VenueID *int64 `json:"venue_id" validate:"omitempty,min=0" example:"1"`
+	// OnlineURL is the link to join the event online, empty string removes it.
+	OnlineURL *string `json:"online_url" validate:"omitempty,max=2048,http_url|len=0" example:"https://meet.example.com/lecture"`
+	// CategoryID sets the category of the event, zero removes it.
+	CategoryID *int64 `json:"category_id" validate:"omitempty,min=0" example:"2"`
+	// Tags replace tags of the event. Tags are lowercased, empty list removes all tags.
+	Tags []string `json:"tags" validate:"omitempty,max=10,dive,min=1,max=32" example:"free,kids"`
+	// RecurrenceRule makes the event recurring, empty string makes it a single event.
+	// Changing the rule or the time of recurring event applies to all its upcoming occurrences.
+	RecurrenceRule *string `json:"recurrence_rule" validate:"omitempty,max=512" example:"FREQ=WEEKLY;BYDAY=TU;COUNT=10"`
+	Timezone       *string `json:"timezone" validate:"omitempty,timezone" example:"Europe/Moscow"`
+}
+
+// EventQuery is the search of published upcoming events. Geographic conditions select events at venues
+// either within radius meters from the point or within the bounding box. Bounding boxes with min_lon greater
+// than max_lon cross the antimeridian.
+type EventQuery struct {
+	Text           string `query:"text" validate:"max=256" example:"concert"`
+	OrganizationID int64  `query:"organization_id" validate:"omitempty,min=1" example:"1"`
+	// CategoryID selects events of the category and its subcategories.
+	CategoryID int64 `query:"category_id" validate:"omitempty,min=1" example:"2"`
+	// Tags selects events having all the tags.
+	Tags         []string `query:"tags" validate:"omitempty,max=10,dive,min=1,max=32" example:"free,kids"`
+	Latitude     *float64 `query:"lat" validate:"required_with=Longitude Radius,omitempty,min=-90,max=90" example:"55.751"`
+	Longitude    *float64 `query:"lon" validate:"required_with=Latitude Radius,omitempty,min=-180,max=180" example:"37.618"`
+	Radius       *float64 `query:"radius" validate:"required_with=Latitude Longitude,omitempty,gt=0,max=100000" example:"2000"`
+	MinLatitude  *float64 `query:"min_lat" validate:"required_with=MinLongitude MaxLatitude MaxLongitude,omitempty,min=-90,max=90"`
+	MinLongitude *float64 `query:"min_lon" validate:"required_with=MinLatitude MaxLatitude MaxLongitude,omitempty,min=-180,max=180"`
+	MaxLatitude  *float64 `query:"max_lat" validate:"required_with=MinLatitude MinLongitude MaxLongitude,omitempty,min=-90,max=90"`
+	MaxLongitude *float64 `query:"max_lon" validate:"required_with=MinLatitude MinLongitude MaxLatitude,omitempty,min=-180,max=180"`
+	Limit        int      `query:"limit" validate:"omitempty,min=1,max=100" example:"20"`
+}
+
+type EventCancel struct {
+	Reason string `query:"reason" validate:"max=1024" example:"The lecturer is ill"`
+}
+
+type EventDeleted struct {
+	// Cancelled is true if the event had registrants, so it was cancelled instead of deletion.
+	Cancelled bool `json:"cancelled" example:"true"`
+}
+
+// EventChange is a material change of the event registrants are notified about.
+// Values are formatted to be shown to users as is.
+type EventChange struct {
+	Field string `json:"field" enums:"begins_at,ends_at,venue_id,online_url"`
+	Old   string `json:"old"`
+	New   string `json:"new"`
+}
+
+type Registration struct {
+	RegistrationID int64 `json:"registration_id" example:"1"`
+	EventID        int64 `json:"event_id" example:"1"`
+	// OccurrenceID is set for registrations for an occurrence of recurring event.
+	OccurrenceID *int64 `json:"occurrence_id,omitempty" example:"3"`
+	UserID       int64  `json:"user_id" example:"1"`
+	// Source is where the user came from to register, such as a campaign or a partner site.
+	Source    *string   `json:"source,omitempty" example:"telegram"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type RegistrationTarget struct {
+	// OccurrenceID is required for recurring events.
+	OccurrenceID int64 `query:"occurrence_id" validate:"omitempty,min=1" example:"3"`
+	// Source is where the user came from, it is counted in traffic sources of the event analytics.
+	Source string `query:"source" validate:"omitempty,max=64" example:"telegram"`
+}
+
+// EventReminder is a reminder about the event sent to registered users at Offset before the event begins.
+// Reminders of recurring events are about the occurrence with OccurrenceID.
+type EventReminder struct {
+	ReminderID   int64
+	EventID      int64
+	OccurrenceID *int64
+	Offset       time.Duration
+	RemindAt     time.Time
+	SentAt       *time.Time
+}
+
+// EventFanout is notification of organization followers about published event.
+type EventFanout struct {
+	FanoutID   int64
+	EventID    int64
+	LastUserID int64
+}
+
+type EventHide struct {
+	Reason string `json:"reason" validate:"required,max=1024" example:"Violates terms of use"`
+}
+
+// DigestSettings are preferences of the weekly digest of upcoming events.
+type DigestSettings struct {
+	UserID  int64 `json:"-"`
+	Enabled bool  `json:"enabled" example:"true"`
+	// Weekday is the day the digest is sent on, 0 is Sunday.
+	Weekday    int        `json:"weekday" example:"1"`
+	Time       string     `json:"time" example:"09:00"`
+	Timezone   string     `json:"timezone" example:"Europe/Moscow"`
+	NextSendAt time.Time  `json:"next_send_at"`
+	LastSentAt *time.Time `json:"last_sent_at,omitempty"`
+}
+
+type DigestSettingsUpdate struct {
+	Enabled  *bool   `json:"enabled" example:"true"`
+	Weekday  *int    `json:"weekday" validate:"omitempty,min=0,max=6" example:"1"`
+	Time     *string `json:"time" validate:"omitempty,datetime=15:04" example:"09:00"`
+	Timezone *string `json:"timezone" validate:"omitempty,timezone" example:"Europe/Moscow"`
+}
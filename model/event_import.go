@@ -0,0 +1,88 @@
+package model
+
+import "time"
+
+// Formats of imported files.
+const (
+	EventImportICS = "ics"
+	EventImportCSV = "csv"
+)
+
+const (
+	EventImportPending   = "pending"
+	EventImportRunning   = "running"
+	EventImportCompleted = "completed"
+	EventImportFailed    = "failed"
+)
+
+// Actions taken for rows of imports.
+const (
+	ImportRowCreate    = "create"
+	ImportRowUpdate    = "update"
+	ImportRowUnchanged = "unchanged"
+	ImportRowCancel    = "cancel"
+	ImportRowSkip      = "skip"
+	ImportRowInvalid   = "invalid"
+)
+
+// EventImportQuery describes the imported file. CSV files must have the header with columns:
+//
+//   - uid (required) identifies the event, re-imports update events with the same uid;
+//   - name (required);
+//   - description;
+//   - begins_at, ends_at (required) in RFC 3339 or as "2006-01-02 15:04" in the timezone of the row;
+//   - timezone, IANA name, defaults to the timezone of the import;
+//   - online_url;
+//   - recurrence_rule, RRULE of RFC 5545;
+//   - tags, separated by commas;
+//   - status, "cancelled" cancels the event.
+//
+// Columns may go in any order, unknown columns are ignored.
+type EventImportQuery struct {
+	Format string `query:"format" validate:"required,oneof=ics csv" example:"ics"`
+	// Timezone is used for floating times of iCalendar and rows of CSV without timezone. Defaults to UTC.
+	Timezone string `query:"timezone" validate:"omitempty,timezone" example:"Europe/Moscow"`
+	// DryRun validates the file and returns the report without importing.
+	DryRun bool `query:"dry_run" example:"true"`
+}
+
+// EventImport is an import running in background. Imported events are created unpublished.
+type EventImport struct {
+	ImportID       int64  `json:"import_id" example:"1"`
+	OrganizationID int64  `json:"organization_id" example:"1"`
+	UserID         int64  `json:"user_id" example:"1"`
+	Format         string `json:"format" enums:"ics,csv" example:"ics"`
+	Timezone       string `json:"timezone" example:"Europe/Moscow"`
+	Status         string `json:"status" enums:"pending,running,completed,failed"`
+	// Total and Processed are numbers of rows, they show the progress of running import.
+	Total     int `json:"total" example:"120"`
+	Processed int `json:"processed" example:"40"`
+	// Report is set when the import is completed.
+	Report     *EventImportReport `json:"report,omitempty"`
+	Error      *string            `json:"error,omitempty"`
+	CreatedAt  time.Time          `json:"created_at"`
+	FinishedAt *time.Time         `json:"finished_at,omitempty"`
+}
+
+type EventImportReport struct {
+	Total     int         `json:"total" example:"120"`
+	Created   int         `json:"created" example:"100"`
+	Updated   int         `json:"updated" example:"10"`
+	Unchanged int         `json:"unchanged" example:"5"`
+	Cancelled int         `json:"cancelled" example:"1"`
+	Skipped   int         `json:"skipped" example:"1"`
+	Invalid   int         `json:"invalid" example:"3"`
+	Rows      []ImportRow `json:"rows"`
+}
+
+// ImportRow is the result of importing a row of CSV or an event of iCalendar.
+type ImportRow struct {
+	// Line is the line of CSV row or the line VEVENT begins at.
+	Line    int    `json:"line" example:"2"`
+	UID     string `json:"uid,omitempty" example:"lecture-1@example.com"`
+	Action  string `json:"action" enums:"create,update,unchanged,cancel,skip,invalid"`
+	EventID *int64 `json:"event_id,omitempty" example:"1"`
+	// Errors are why the row is invalid, warnings are about data which is not imported.
+	Errors   []string `json:"errors,omitempty"`
+	Warnings []string `json:"warnings,omitempty"`
+}
@@ -0,0 +1,11 @@
+package model
+
+const (
+	LocaleRu = "ru"
+	LocaleEn = "en"
+
+	DefaultLocale = LocaleRu
+)
+
+// SupportedLocales are locales which have translations of all notification templates.
+var SupportedLocales = []string{LocaleRu, LocaleEn}
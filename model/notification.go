@@ -0,0 +1,37 @@
+package model
+
+import "time"
+
+// Notification is a message delivered to the in-app inbox of the user.
+type Notification struct {
+	NotificationID int64      `json:"notification_id" example:"1"`
+	UserID         int64      `json:"-"`
+	Kind           string     `json:"kind" example:"event_changed"`
+	Title          string     `json:"title" example:"Event changed: Open lecture"`
+	Body           string     `json:"body"`
+	CreatedAt      time.Time  `json:"created_at"`
+	ReadAt         *time.Time `json:"read_at,omitempty"`
+}
+
+type NotificationList struct {
+	Notifications []Notification `json:"notifications"`
+	// Unread is the number of all unread notifications of the user, not only of the page.
+	Unread int `json:"unread" example:"3"`
+}
+
+type NotificationFilter struct {
+	Unread bool `query:"unread" example:"true"`
+	Limit  int  `query:"limit" validate:"omitempty,min=1,max=100" example:"20"`
+	Offset int  `query:"offset" validate:"omitempty,min=0" example:"0"`
+}
+
+type NotificationsRead struct {
+	Count int64 `json:"count" example:"3"`
+}
+
+// NotificationSignal is published with Postgres NOTIFY when a notification is created,
+// so every replica could push it to streams of the user.
+type NotificationSignal struct {
+	NotificationID int64 `json:"notification_id"`
+	UserID         int64 `json:"user_id"`
+}
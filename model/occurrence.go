@@ -0,0 +1,65 @@
+package model
+
+import "time"
+
+// Scopes of changes of recurring events.
+const (
+	OccurrenceScopeThis      = "this"
+	OccurrenceScopeFollowing = "following"
+	OccurrenceScopeAll       = "all"
+)
+
+// Occurrence is a materialized occurrence of recurring event. Occurrences are materialized
+// for a limited period ahead and extended by a background job.
+type Occurrence struct {
+	OccurrenceID int64 `json:"occurrence_id" example:"3"`
+	EventID      int64 `json:"event_id" example:"1"`
+	// RecurrenceID is the beginning of the occurrence according to the rule, as RECURRENCE-ID of RFC 5545.
+	RecurrenceID time.Time `json:"recurrence_id"`
+	BeginsAt     time.Time `json:"begins_at"`
+	EndsAt       time.Time `json:"ends_at"`
+	// Name and Description override ones of the event.
+	Name        *string `json:"name,omitempty" example:"Встреча клуба: спецвыпуск"`
+	Description *string `json:"description,omitempty"`
+	// Overridden occurrences are changed individually, changes of the series don't apply to them.
+	Overridden bool `json:"overridden" example:"false"`
+	// CancelledAt is set for occurrences excluded from the series, as EXDATE of RFC 5545.
+	CancelledAt *time.Time `json:"cancelled_at,omitempty"`
+}
+
+func (o *Occurrence) IsCancelled() bool {
+	return o.CancelledAt != nil
+}
+
+// OccurrenceQuery selects occurrences beginning in [from, to). By default, upcoming occurrences within 90 days.
+type OccurrenceQuery struct {
+	From string `query:"from" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00" example:"2024-01-01T00:00:00Z"`
+	To   string `query:"to" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00" example:"2024-04-01T00:00:00Z"`
+}
+
+// Window returns the bounds of the query. Missing bounds are resolved against now.
+func (q *OccurrenceQuery) Window(now time.Time) (from, to time.Time) {
+	from, to = now, now.AddDate(0, 0, 90)
+	if q.From != "" {
+		from, _ = time.Parse(time.RFC3339, q.From)
+	}
+	if q.To != "" {
+		to, _ = time.Parse(time.RFC3339, q.To)
+	} else if q.From != "" {
+		to = from.AddDate(0, 0, 90)
+	}
+	return from, to
+}
+
+type OccurrenceScopeQuery struct {
+	Scope string `query:"scope" validate:"omitempty,oneof=this following all" example:"this"`
+}
+
+// OccurrenceUpdate changes the occurrence, the following occurrences or the whole series depending on the scope.
+// Time changes of following occurrences or the series shift them by the difference with the occurrence's time.
+type OccurrenceUpdate struct {
+	Name        *string    `json:"name" validate:"omitempty,min=3,max=256" example:"Встреча клуба"`
+	Description *string    `json:"description" validate:"omitempty,max=4096" example:"Обсуждаем книгу месяца"`
+	BeginsAt    *time.Time `json:"begins_at" example:"2024-01-02T19:00:00Z"`
+	EndsAt      *time.Time `json:"ends_at" example:"2024-01-02T21:00:00Z"`
+}
@@ -1,5 +1,7 @@
 package model
 
+import "time"
+
 type OrganizationCreate struct {
 	Name         string  `json:"name" validate:"required,min=3,max=256" example:"Российский технологический университет МИРЭА"`
 	Address      *string `json:"address,omitempty" validate:"omitempty,min=3,max=256" example:"Г. Москва, Пр-т. Вернадского 78"`
@@ -13,6 +15,7 @@ type Organization struct {
 	Address        *string `json:"address,omitempty"`
 	ContactEmail   *string `json:"contact_email,omitempty"`
 	ContactPhone   *string `json:"contact_phone,omitempty"`
+	FollowersCount int64   `json:"followers_count"`
 }
 
 type OrganizationGet struct {
@@ -21,6 +24,7 @@ type OrganizationGet struct {
 	Address        *string `json:"address,omitempty" example:"Г. Москва, Пр-т. Вернадского 78"`
 	ContactEmail   *string `json:"contact_email,omitempty" example:"contact@mirea.ru"`
 	ContactPhone   *string `json:"contact_phone,omitempty" example:"74992156565"`
+	FollowersCount int64   `json:"followers_count" example:"42"`
 }
 
 type OrganizationUpdate struct {
@@ -45,4 +49,11 @@ type OrganizationMember struct {
 type MemberRights struct {
 	EditEvents    bool `json:"edit_events"`
 	ManageMembers bool `json:"manage_members"`
+	CheckIn       bool `json:"check_in"`
+}
+
+type Follow struct {
+	OrganizationID int64     `json:"organization_id" example:"1"`
+	UserID         int64     `json:"user_id" example:"1"`
+	CreatedAt      time.Time `json:"created_at"`
 }
@@ -0,0 +1,61 @@
+package model
+
+import "time"
+
+const (
+	OutboxPending = "pending"
+	OutboxSent    = "sent"
+	OutboxDead    = "dead"
+)
+
+const (
+	MessageActivation            = "activation"
+	MessagePassCode              = "pass_code"
+	MessageChannelLinked         = "channel_linked"
+	MessageEventReminder         = "event_reminder"
+	MessageEventChanged          = "event_changed"
+	MessageEventCancelled        = "event_cancelled"
+	MessageEventPublished        = "event_published"
+	MessageWeeklyDigest          = "weekly_digest"
+	MessageSearchAlert           = "search_alert"
+	MessageRegistrationConfirmed = "registration_confirmed"
+	MessagePhoneCode             = "phone_code"
+)
+
+// MessageKinds are kinds of messages, each of them has its own template.
+var MessageKinds = []string{MessageActivation, MessagePassCode, MessageChannelLinked,
+	MessageEventReminder, MessageEventChanged, MessageEventCancelled, MessageEventPublished,
+	MessageWeeklyDigest, MessageSearchAlert, MessageRegistrationConfirmed, MessagePhoneCode}
+
+// Message is a rendered message ready to be sent to the channel.
+type Message struct {
+	UserID    int64  `json:"user_id" example:"1"`
+	Kind      string `json:"kind" example:"activation"`
+	Channel   string `json:"channel" enums:"email,telegram,sms"`
+	ToAddress string `json:"to_address" example:"johndoe@example.com"`
+	ToName    string `json:"to_name" example:"John Doe"`
+	Subject   string `json:"subject" example:"Код для входа"`
+	// Bodies may contain secrets like login codes, so they are never exposed in API or logs.
+	TextBody string `json:"-"`
+	HTMLBody string `json:"-"`
+	// UnsubscribeURL opts the user out of the message category. It is set for non-transactional emails only.
+	UnsubscribeURL string `json:"-"`
+}
+
+type OutboxMessage struct {
+	Message
+	MessageID     int64      `json:"message_id" example:"1"`
+	Status        string     `json:"status" enums:"pending,sent,dead"`
+	Attempts      int        `json:"attempts" example:"1"`
+	NextAttemptAt time.Time  `json:"next_attempt_at"`
+	LastError     *string    `json:"last_error,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	SentAt        *time.Time `json:"sent_at,omitempty"`
+}
+
+type OutboxFilter struct {
+	Status  string `query:"status" validate:"omitempty,oneof=pending sent dead" example:"dead"`
+	Channel string `query:"channel" validate:"omitempty,oneof=email telegram sms" example:"email"`
+	Limit   int    `query:"limit" validate:"omitempty,min=1,max=100" example:"20"`
+	Offset  int    `query:"offset" validate:"omitempty,min=0" example:"0"`
+}
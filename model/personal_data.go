@@ -0,0 +1,56 @@
+package model
+
+import "time"
+
+const (
+	DataExportPending = "pending"
+	DataExportReady   = "ready"
+	DataExportFailed  = "failed"
+)
+
+type DataExport struct {
+	ExportID   int64      `json:"export_id" example:"1"`
+	UserID     int64      `json:"user_id" example:"1"`
+	Status     string     `json:"status" enums:"pending,ready,failed"`
+	Error      *string    `json:"error,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+type DataExportGet struct {
+	ExportID   int64      `json:"export_id" example:"1"`
+	Status     string     `json:"status" enums:"pending,ready,failed"`
+	Error      *string    `json:"error,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+type LoginRecord struct {
+	CreatedAt time.Time  `json:"requested_at"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+	ExpiresAt time.Time  `json:"expires_at"`
+}
+
+type Membership struct {
+	OrganizationID int64        `json:"organization_id"`
+	Name           string       `json:"name"`
+	IsOwner        bool         `json:"is_owner"`
+	Can            MemberRights `json:"privileges"`
+}
+
+// PersonalData is everything stored about the user. Each field is exported as a separate file of the archive.
+type PersonalData struct {
+	Profile       *User          `json:"profile"`
+	Memberships   []Membership   `json:"memberships"`
+	LoginHistory  []LoginRecord  `json:"login_history"`
+	CreatedEvents []Event        `json:"created_events"`
+	Registrations []Registration `json:"registrations"`
+	Follows       []Follow       `json:"follows"`
+	// DigestSettings is nil if the user has never changed them.
+	DigestSettings *DigestSettings `json:"digest_settings"`
+	SavedSearches  []SavedSearch   `json:"saved_searches"`
+	CalendarFeeds  []CalendarFeed  `json:"calendar_feeds"`
+	// EventImports are imports started by the user, without uploaded files.
+	EventImports []EventImport `json:"event_imports"`
+	Orders       []Order       `json:"orders"`
+}
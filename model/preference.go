@@ -0,0 +1,50 @@
+package model
+
+const (
+	CategoryReminders = "reminders"
+	CategoryDigests   = "digests"
+	CategoryFollows   = "follows"
+	CategoryInvites   = "invites"
+	CategorySearches  = "searches"
+)
+
+// NotificationCategories are categories of non-transactional messages users may opt out of.
+var NotificationCategories = []string{CategoryReminders, CategoryDigests, CategoryFollows, CategoryInvites, CategorySearches}
+
+var messageCategories = map[string]string{
+	MessageEventReminder:  CategoryReminders,
+	MessageWeeklyDigest:   CategoryDigests,
+	MessageEventPublished: CategoryFollows,
+	MessageSearchAlert:    CategorySearches,
+}
+
+// MessageCategory returns the category of the message kind. Transactional messages, like login codes
+// or changes of events the user is registered for, have no category and are always sent.
+func MessageCategory(kind string) string {
+	return messageCategories[kind]
+}
+
+// NotificationPreference tells whether messages of the category are sent to the channel.
+// Categories are enabled unless the user opts out of them.
+type NotificationPreference struct {
+	Channel  string `json:"channel" enums:"email,telegram,sms,inapp"`
+	Category string `json:"category" enums:"reminders,digests,follows,invites,searches"`
+	Enabled  bool   `json:"enabled" example:"true"`
+}
+
+type NotificationPreferenceUpdate struct {
+	Channel  string `json:"channel" validate:"required,oneof=email telegram sms inapp" example:"email"`
+	Category string `json:"category" validate:"required,oneof=reminders digests follows invites searches" example:"digests"`
+	Enabled  *bool  `json:"enabled" validate:"required" example:"false"`
+}
+
+type NotificationPreferencesUpdate struct {
+	Preferences []NotificationPreferenceUpdate `json:"preferences" validate:"required,min=1,max=50,dive"`
+}
+
+// UnsubscribeClaims are signed into unsubscribe links of non-transactional messages.
+type UnsubscribeClaims struct {
+	UserID   int64
+	Channel  string
+	Category string
+}
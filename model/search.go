@@ -0,0 +1,70 @@
+package model
+
+import "time"
+
+// SearchFilter is a node of the event search. A node is either a combination of child nodes
+// with and/or, or a single condition. Exactly one field of the node must be set.
+// Saved searches store it as is and event search queries are converted to it, so both are matched by the same SQL.
+type SearchFilter struct {
+	And []SearchFilter `json:"and,omitempty"`
+	Or  []SearchFilter `json:"or,omitempty"`
+	// Text is searched in the name and the description of the event, case-insensitive.
+	Text           *string `json:"text,omitempty" example:"concert"`
+	OrganizationID *int64  `json:"organization_id,omitempty" example:"1"`
+	// CategoryID matches events of the category and its subcategories.
+	CategoryID   *int64     `json:"category_id,omitempty" example:"2"`
+	Tag          *string    `json:"tag,omitempty" example:"free"`
+	Near         *GeoCircle `json:"near,omitempty"`
+	InBounds     *GeoBounds `json:"in_bounds,omitempty"`
+	BeginsAfter  *time.Time `json:"begins_after,omitempty"`
+	BeginsBefore *time.Time `json:"begins_before,omitempty"`
+	// BeginsWithinDays matches events beginning in the given number of days from now,
+	// so the search stays relevant over time unlike fixed dates.
+	BeginsWithinDays *int `json:"begins_within_days,omitempty" example:"30"`
+}
+
+// GeoCircle matches events at venues within Radius meters from the point.
+type GeoCircle struct {
+	Latitude  float64 `json:"lat" example:"55.751"`
+	Longitude float64 `json:"lon" example:"37.618"`
+	Radius    float64 `json:"radius" example:"2000"`
+}
+
+// GeoBounds matches events at venues within the box. If MinLongitude is greater than MaxLongitude,
+// the box crosses the antimeridian.
+type GeoBounds struct {
+	MinLatitude  float64 `json:"min_lat" example:"55.5"`
+	MinLongitude float64 `json:"min_lon" example:"37.3"`
+	MaxLatitude  float64 `json:"max_lat" example:"56"`
+	MaxLongitude float64 `json:"max_lon" example:"37.9"`
+}
+
+type SavedSearch struct {
+	SearchID      int64        `json:"search_id" example:"1"`
+	UserID        int64        `json:"-"`
+	Name          string       `json:"name" example:"Concerts this month"`
+	Filter        SearchFilter `json:"filter"`
+	AlertsEnabled bool         `json:"alerts_enabled" example:"true"`
+	// UnsubscribeToken disables alerts of the search without signing in. It is only sent in alerts.
+	UnsubscribeToken string    `json:"-"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+type SavedSearchCreate struct {
+	Name          string       `json:"name" validate:"required,max=128" example:"Concerts this month"`
+	Filter        SearchFilter `json:"filter"`
+	AlertsEnabled *bool        `json:"alerts_enabled" example:"true"`
+}
+
+type SavedSearchUpdate struct {
+	Name          *string `json:"name" validate:"omitempty,min=1,max=128" example:"Concerts"`
+	AlertsEnabled *bool   `json:"alerts_enabled" example:"false"`
+}
+
+// SearchAlertJob is matching of published event against saved searches. Searches are scanned in batches
+// ordered by id, LastSearchID is the last checked search.
+type SearchAlertJob struct {
+	JobID        int64
+	EventID      int64
+	LastSearchID int64
+}
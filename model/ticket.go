@@ -0,0 +1,132 @@
+package model
+
+import "time"
+
+// Kinds of promo codes.
+const (
+	PromoCodePercent = "percent"
+	PromoCodeFixed   = "fixed"
+)
+
+const (
+	OrderReserved  = "reserved"
+	OrderPaid      = "paid"
+	OrderExpired   = "expired"
+	OrderCancelled = "cancelled"
+)
+
+const (
+	PaymentPending   = "pending"
+	PaymentSucceeded = "succeeded"
+	PaymentCancelled = "cancelled"
+)
+
+// TicketType is a tier of tickets of the event. Prices are in minor units of the currency, such as kopecks.
+type TicketType struct {
+	TicketTypeID int64  `json:"ticket_type_id" example:"1"`
+	EventID      int64  `json:"event_id" example:"1"`
+	Name         string `json:"name" example:"Standard"`
+	Price        int64  `json:"price" example:"150000"`
+	Currency     string `json:"currency" example:"RUB"`
+	// Quantity is the number of tickets for sale, tickets are not limited if it is not set.
+	Quantity *int `json:"quantity,omitempty" example:"100"`
+	// Sold is the number of tickets in paid orders and reserved by orders which have not expired yet.
+	Sold int `json:"sold" example:"58"`
+	// Available is the number of tickets neither sold nor reserved, it is not set for unlimited tickets.
+	Available  *int       `json:"available,omitempty" example:"42"`
+	SalesBegin *time.Time `json:"sales_begin,omitempty"`
+	SalesEnd   *time.Time `json:"sales_end,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+type TicketTypeCreate struct {
+	Name       string     `json:"name" validate:"required,max=128" example:"Standard"`
+	Price      int64      `json:"price" validate:"min=0" example:"150000"`
+	Currency   string     `json:"currency" validate:"required,iso4217" example:"RUB"`
+	Quantity   *int       `json:"quantity" validate:"omitempty,min=1" example:"100"`
+	SalesBegin *time.Time `json:"sales_begin"`
+	SalesEnd   *time.Time `json:"sales_end"`
+}
+
+type TicketTypeUpdate struct {
+	Name       *string    `json:"name" validate:"omitempty,min=1,max=128" example:"Standard"`
+	Price      *int64     `json:"price" validate:"omitempty,min=0" example:"150000"`
+	Quantity   *int       `json:"quantity" validate:"omitempty,min=1" example:"100"`
+	SalesBegin *time.Time `json:"sales_begin"`
+	SalesEnd   *time.Time `json:"sales_end"`
+}
+
+// PromoCode discounts orders of the event's tickets. Percent codes take Amount percents off,
+// fixed codes take Amount minor units of the currency off.
+type PromoCode struct {
+	PromoCodeID int64   `json:"promo_code_id" example:"1"`
+	EventID     int64   `json:"event_id" example:"1"`
+	Code        string  `json:"code" example:"EARLYBIRD"`
+	Kind        string  `json:"kind" enums:"percent,fixed" example:"percent"`
+	Amount      int64   `json:"amount" example:"20"`
+	Currency    *string `json:"currency,omitempty" example:"RUB"`
+	// MaxUses limits orders with the code, the code is not limited if it is not set.
+	MaxUses *int `json:"max_uses,omitempty" example:"50"`
+	// Used is the number of paid and reserved orders with the code.
+	Used      int        `json:"used" example:"3"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+type PromoCodeCreate struct {
+	// Code is case-insensitive, it is stored uppercased.
+	Code      string     `json:"code" validate:"required,min=3,max=32,alphanum" example:"EARLYBIRD"`
+	Kind      string     `json:"kind" validate:"required,oneof=percent fixed" example:"percent"`
+	Amount    int64      `json:"amount" validate:"required,min=1" example:"20"`
+	Currency  *string    `json:"currency" validate:"required_if=Kind fixed,omitempty,iso4217" example:"RUB"`
+	MaxUses   *int       `json:"max_uses" validate:"omitempty,min=1" example:"50"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// Order is an order of tickets. Tickets are reserved until ExpiresAt, if the order is not paid by then, it expires.
+type Order struct {
+	OrderID      int64   `json:"order_id" example:"1"`
+	EventID      int64   `json:"event_id" example:"1"`
+	UserID       int64   `json:"user_id" example:"1"`
+	TicketTypeID int64   `json:"ticket_type_id" example:"1"`
+	Quantity     int     `json:"quantity" example:"1"`
+	PromoCodeID  *int64  `json:"promo_code_id,omitempty" example:"1"`
+	Currency     string  `json:"currency" example:"RUB"`
+	Subtotal     int64   `json:"subtotal" example:"150000"`
+	Discount     int64   `json:"discount" example:"30000"`
+	Total        int64   `json:"total" example:"120000"`
+	Status       string  `json:"status" enums:"reserved,paid,expired,cancelled"`
+	PaymentID    *string `json:"-"`
+	// PaymentURL is where the user pays for the order.
+	PaymentURL *string    `json:"payment_url,omitempty" example:"https://pay.example.com/p/1"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+	PaidAt     *time.Time `json:"paid_at,omitempty"`
+	// Source is where the user came from, the registration for the paid order gets it.
+	Source *string `json:"source,omitempty" example:"telegram"`
+}
+
+// OrderCreate orders one ticket, the paid order registers the user who places it.
+type OrderCreate struct {
+	TicketTypeID int64  `json:"ticket_type_id" validate:"required,min=1" example:"1"`
+	Quantity     int    `json:"quantity" validate:"required,min=1,max=1" example:"1"`
+	PromoCode    string `json:"promo_code" validate:"omitempty,max=32" example:"EARLYBIRD"`
+	Source       string `json:"source" validate:"omitempty,max=64" example:"telegram"`
+}
+
+// PaymentRequest is a request to the payment provider to charge the user for the order.
+type PaymentRequest struct {
+	OrderID     int64
+	Amount      int64
+	Currency    string
+	Description string
+	// ReturnURL is where the provider redirects the user after the payment.
+	ReturnURL string
+}
+
+type Payment struct {
+	PaymentID string
+	Status    string
+	// ConfirmationURL is where the user pays.
+	ConfirmationURL string
+}
@@ -1,26 +1,65 @@
 package model
 
+import "time"
+
 type UserCreate struct {
-	FirstName  string `json:"first_name" validate:"gte=2,lte=32" example:"John"`
-	LastName   string `json:"last_name" validate:"gte=2,lte=32" example:"Doe"`
-	MiddleName string `json:"middle_name" validate:"gte=2,lte=32" example:"Jr."`
-	Email      string `json:"email" validate:"email,gte=2,lte=64" example:"johndoe@example.com"`
+	FirstName       string `json:"first_name" validate:"gte=2,lte=32" example:"John"`
+	LastName        string `json:"last_name" validate:"gte=2,lte=32" example:"Doe"`
+	MiddleName      string `json:"middle_name" validate:"gte=2,lte=32" example:"Jr."`
+	Email           string `json:"email" validate:"email,gte=2,lte=64" example:"johndoe@example.com"`
+	PreferredLocale string `json:"preferred_locale" validate:"omitempty,oneof=ru en" example:"ru"`
 }
 
 type UserGet struct {
-	UserID     int64  `json:"user_id" example:"1"`
-	FirstName  string `json:"first_name" example:"John"`
-	LastName   string `json:"last_name" example:"Doe"`
-	MiddleName string `json:"middle_name" example:"Jr."`
-	Email      string `json:"email" example:"johndoe@example.com"`
-	IsActive   bool   `json:"is_active" example:"true"`
+	UserID          int64  `json:"user_id" example:"1"`
+	FirstName       string `json:"first_name" example:"John"`
+	LastName        string `json:"last_name" example:"Doe"`
+	MiddleName      string `json:"middle_name" example:"Jr."`
+	Email           string `json:"email" example:"johndoe@example.com"`
+	IsActive        bool   `json:"is_active" example:"true"`
+	PreferredLocale string `json:"preferred_locale" example:"ru"`
+}
+
+// Me is the current user. During impersonation ImpersonatedBy is the administrator acting as the user.
+type Me struct {
+	UserGet
+	Impersonated   bool   `json:"impersonated" example:"false"`
+	ImpersonatedBy *Actor `json:"impersonated_by,omitempty"`
 }
 
 type User struct {
-	UserID     int64  `json:"user_id" faker:"-"`
-	FirstName  string `json:"first_name" faker:"first_name"`
-	LastName   string `json:"last_name" faker:"last_name"`
-	MiddleName string `json:"middle_name" faker:"-"`
-	Email      string `json:"email" faker:"email"`
-	IsActive   bool   `json:"is_active" faker:"-"`
+	UserID          int64      `json:"user_id" faker:"-"`
+	FirstName       string     `json:"first_name" faker:"first_name"`
+	LastName        string     `json:"last_name" faker:"last_name"`
+	MiddleName      string     `json:"middle_name" faker:"-"`
+	Email           string     `json:"email" faker:"email"`
+	IsActive        bool       `json:"is_active" faker:"-"`
+	IsAdmin         bool       `json:"is_admin" faker:"-"`
+	PreferredLocale string     `json:"preferred_locale" faker:"-"`
+	BannedAt        *time.Time `json:"banned_at,omitempty" faker:"-"`
+	BanReason       *string    `json:"ban_reason,omitempty" faker:"-"`
+	// DeactivatedAt is set for accounts deactivated by administrators, unlike never activated ones.
+	DeactivatedAt *time.Time `json:"deactivated_at,omitempty" faker:"-"`
+}
+
+func (u *User) IsBanned() bool {
+	return u.BannedAt != nil
+}
+
+func (u *User) IsDeactivated() bool {
+	return u.DeactivatedAt != nil
+}
+
+type MeUpdate struct {
+	PreferredLocale *string `json:"preferred_locale" validate:"omitempty,oneof=ru en" example:"en"`
+}
+
+type UserSearch struct {
+	Query  string `query:"query" example:"john"`
+	Limit  int    `query:"limit" validate:"omitempty,min=1,max=100" example:"20"`
+	Offset int    `query:"offset" validate:"omitempty,min=0" example:"0"`
+}
+
+type BanRequest struct {
+	Reason string `json:"reason" validate:"required,max=1024" example:"Spam"`
 }
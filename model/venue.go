@@ -0,0 +1,35 @@
+package model
+
+import "time"
+
+// Venue is a place of organization's events. Venues are reused across events of the organization.
+type Venue struct {
+	VenueID        int64   `json:"venue_id" example:"1"`
+	OrganizationID int64   `json:"organization_id" example:"1"`
+	Name           string  `json:"name" example:"Городской концертный зал"`
+	Address        string  `json:"address" example:"Москва, пр. Вернадского, 78"`
+	Latitude       float64 `json:"latitude" example:"55.670"`
+	Longitude      float64 `json:"longitude" example:"37.480"`
+	// Accessibility describes how visitors with disabilities can get in, e.g. step-free access or an elevator.
+	Accessibility string    `json:"accessibility" example:"Step-free entrance from the yard"`
+	Capacity      *int      `json:"capacity,omitempty" example:"300"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+type VenueCreate struct {
+	Name          string   `json:"name" validate:"required,max=256" example:"Городской концертный зал"`
+	Address       string   `json:"address" validate:"required,max=512" example:"Москва, пр. Вернадского, 78"`
+	Latitude      *float64 `json:"latitude" validate:"required,min=-90,max=90" example:"55.670"`
+	Longitude     *float64 `json:"longitude" validate:"required,min=-180,max=180" example:"37.480"`
+	Accessibility string   `json:"accessibility" validate:"max=2048" example:"Step-free entrance from the yard"`
+	Capacity      *int     `json:"capacity" validate:"omitempty,min=1" example:"300"`
+}
+
+type VenueUpdate struct {
+	Name          *string  `json:"name" validate:"omitempty,min=1,max=256" example:"Городской концертный зал"`
+	Address       *string  `json:"address" validate:"omitempty,min=1,max=512" example:"Москва, пр. Вернадского, 78"`
+	Latitude      *float64 `json:"latitude" validate:"omitempty,min=-90,max=90" example:"55.670"`
+	Longitude     *float64 `json:"longitude" validate:"omitempty,min=-180,max=180" example:"37.480"`
+	Accessibility *string  `json:"accessibility" validate:"omitempty,max=2048" example:"Step-free entrance from the yard"`
+	Capacity      *int     `json:"capacity" validate:"omitempty,min=1" example:"300"`
+}
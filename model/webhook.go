@@ -0,0 +1,91 @@
+package model
+
+import "time"
+
+const (
+	WebhookEventPublished      = "event.published"
+	WebhookEventUpdated        = "event.updated"
+	WebhookRegistrationCreated = "registration.created"
+	WebhookMemberJoined        = "member.joined"
+	WebhookOrderPaid           = "order.paid"
+)
+
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliverySucceeded = "succeeded"
+	WebhookDeliveryFailed    = "failed"
+)
+
+// Webhook is a subscription of organization's endpoint to event types.
+// Endpoints failing too many times in a row are disabled until enabled again.
+type Webhook struct {
+	WebhookID      int64    `json:"webhook_id" example:"1"`
+	OrganizationID int64    `json:"organization_id" example:"1"`
+	URL            string   `json:"url" example:"https://example.com/hooks/events"`
+	EventTypes     []string `json:"event_types" example:"event.published,event.updated"`
+	// Secret signs payloads. It is returned only when the webhook is created.
+	Secret              string     `json:"secret,omitempty" example:"9f86d081884c7d659a2feaa0c55ad015"`
+	Enabled             bool       `json:"enabled" example:"true"`
+	ConsecutiveFailures int        `json:"consecutive_failures" example:"0"`
+	DisabledAt          *time.Time `json:"disabled_at,omitempty"`
+	CreatedAt           time.Time  `json:"created_at"`
+}
+
+type WebhookCreate struct {
+	URL        string   `json:"url" validate:"required,url,startswith=http,max=2048" example:"https://example.com/hooks/events"`
+	EventTypes []string `json:"event_types" validate:"required,min=1,dive,oneof=event.published event.updated registration.created member.joined order.paid"`
+}
+
+type WebhookUpdate struct {
+	URL        *string  `json:"url" validate:"omitempty,url,startswith=http,max=2048" example:"https://example.com/hooks/events"`
+	EventTypes []string `json:"event_types" validate:"omitempty,min=1,dive,oneof=event.published event.updated registration.created member.joined order.paid"`
+	// Enabling the webhook resets its failures counter.
+	Enabled *bool `json:"enabled" example:"true"`
+}
+
+// WebhookDelivery is an attempt log of sending the event to the webhook.
+type WebhookDelivery struct {
+	DeliveryID     int64      `json:"delivery_id" example:"1"`
+	WebhookID      int64      `json:"webhook_id" example:"1"`
+	EventType      string     `json:"event_type" example:"event.published"`
+	Payload        string     `json:"payload" example:"{\"type\":\"event.published\",\"data\":{}}"`
+	Status         string     `json:"status" enums:"pending,succeeded,failed"`
+	Attempts       int        `json:"attempts" example:"1"`
+	NextAttemptAt  time.Time  `json:"next_attempt_at"`
+	LastStatusCode *int       `json:"last_status_code,omitempty" example:"500"`
+	LastError      *string    `json:"last_error,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	DeliveredAt    *time.Time `json:"delivered_at,omitempty"`
+	// URL and Secret of the webhook, they are needed to send the delivery.
+	URL    string `json:"-"`
+	Secret string `json:"-"`
+}
+
+// WebhookPayload is the body of webhook requests.
+type WebhookPayload struct {
+	Type      string      `json:"type" example:"event.published"`
+	CreatedAt time.Time   `json:"created_at"`
+	Data      interface{} `json:"data"`
+}
+
+type WebhookEventUpdatedData struct {
+	Event   *Event        `json:"event"`
+	Changes []EventChange `json:"changes"`
+}
+
+type WebhookRegistrationData struct {
+	Registration *Registration `json:"registration"`
+	FirstName    string        `json:"first_name"`
+	LastName     string        `json:"last_name"`
+}
+
+type WebhookOrderData struct {
+	Order     *Order `json:"order"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+}
+
+type WebhookMemberData struct {
+	OrganizationID int64               `json:"organization_id"`
+	Member         *OrganizationMember `json:"member"`
+}
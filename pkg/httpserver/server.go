@@ -3,12 +3,16 @@ package httpserver
 import (
 	"github.com/sirupsen/logrus"
 	"github.com/valyala/fasthttp"
+	"strings"
 	"time"
 )
 
 const (
 	_defaultReadTimeout  = 5 * time.Second
 	_defaultWriteTimeout = 5 * time.Second
+	// _streamWriteTimeout is the write timeout of Server-Sent Events streams, which stay open much longer
+	// than regular responses. Streams must be finished by the handler before it expires.
+	_streamWriteTimeout = time.Hour
 )
 
 type Server struct {
@@ -24,6 +28,12 @@ func New(addr string, handler fasthttp.RequestHandler, logger *logrus.Logger) Se
 			ReadTimeout:  _defaultReadTimeout,
 			WriteTimeout: _defaultWriteTimeout,
 			Logger:       logger,
+			HeaderReceived: func(header *fasthttp.RequestHeader) fasthttp.RequestConfig {
+				if strings.Contains(string(header.Peek(fasthttp.HeaderAccept)), "text/event-stream") {
+					return fasthttp.RequestConfig{WriteTimeout: _streamWriteTimeout}
+				}
+				return fasthttp.RequestConfig{}
+			},
 		},
 		addr:   addr,
 		notify: make(chan error),
@@ -0,0 +1,314 @@
+package ical
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const dateLayout = "20060102"
+
+var ErrInvalidCalendar = errors.New("invalid iCalendar")
+
+// DecodedEvent is VEVENT read from a calendar. Line is the line where the event begins,
+// Err is set if the event can't be read.
+type DecodedEvent struct {
+	Event
+	Line int
+	Err  error
+}
+
+// Decode reads events of the calendar. Floating times and dates are read in loc.
+// Malformed calendars fail as a whole, while events with malformed properties are returned with Err set.
+func Decode(r io.Reader, loc *time.Location) ([]DecodedEvent, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(lines) == 0 || !strings.EqualFold(lines[0].text, "BEGIN:VCALENDAR") {
+		return nil, fmt.Errorf("%w: calendar must begin with BEGIN:VCALENDAR", ErrInvalidCalendar)
+	}
+
+	var events []DecodedEvent
+	var stack []string
+	var event *DecodedEvent
+	var props []property
+	for _, l := range lines {
+		p, err := parseProperty(l.text)
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: %v", ErrInvalidCalendar, l.number, err)
+		}
+		switch p.name {
+		case "BEGIN":
+			component := strings.ToUpper(p.value)
+			stack = append(stack, component)
+			if component == "VEVENT" && len(stack) == 2 {
+				event, props = &DecodedEvent{Line: l.number}, nil
+			}
+		case "END":
+			component := strings.ToUpper(p.value)
+			if len(stack) == 0 || stack[len(stack)-1] != component {
+				return nil, fmt.Errorf("%w: line %d: unexpected END:%s", ErrInvalidCalendar, l.number, p.value)
+			}
+			stack = stack[:len(stack)-1]
+			if component == "VEVENT" && len(stack) == 1 {
+				event.Event, event.Err = decodeEvent(props, loc)
+				events = append(events, *event)
+				event = nil
+			}
+		default:
+			// Properties of nested components, such as alarms of events, are skipped.
+			if event != nil && len(stack) == 2 {
+				props = append(props, p)
+			}
+		}
+	}
+	if len(stack) != 0 {
+		return nil, fmt.Errorf("%w: %s is not closed", ErrInvalidCalendar, stack[len(stack)-1])
+	}
+	return events, nil
+}
+
+type contentLine struct {
+	number int
+	text   string
+}
+
+// unfold reads content lines joining continuation lines. Lines may end with either CRLF or LF.
+func unfold(r io.Reader) ([]contentLine, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	var lines []contentLine
+	number := 0
+	for scanner.Scan() {
+		number++
+		text := strings.TrimSuffix(scanner.Text(), "\r")
+		if number == 1 {
+			text = strings.TrimPrefix(text, "\ufeff")
+		}
+		if text == "" {
+			continue
+		}
+		if (text[0] == ' ' || text[0] == '\t') && len(lines) > 0 {
+			lines[len(lines)-1].text += text[1:]
+			continue
+		}
+		lines = append(lines, contentLine{number: number, text: text})
+	}
+	return lines, scanner.Err()
+}
+
+type property struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+// parseProperty parses content line "NAME;PARAM=value;PARAM="quoted value":value".
+// Names of properties and parameters are uppercased.
+func parseProperty(line string) (property, error) {
+	p := property{params: map[string]string{}}
+	i := strings.IndexAny(line, ";:")
+	if i <= 0 {
+		return p, fmt.Errorf("malformed content line %q", line)
+	}
+	p.name = strings.ToUpper(line[:i])
+	for line[i] == ';' {
+		line = line[i+1:]
+		eq := strings.IndexByte(line, '=')
+		if eq <= 0 {
+			return p, fmt.Errorf("malformed parameter of %s", p.name)
+		}
+		name := strings.ToUpper(line[:eq])
+		line = line[eq+1:]
+		var value string
+		if strings.HasPrefix(line, `"`) {
+			end := strings.IndexByte(line[1:], '"')
+			if end < 0 {
+				return p, fmt.Errorf("unterminated quoted parameter of %s", p.name)
+			}
+			value, line = line[1:end+1], line[end+2:]
+			i = 0
+		} else {
+			i = strings.IndexAny(line, ";:")
+			if i < 0 {
+				return p, fmt.Errorf("malformed parameter of %s", p.name)
+			}
+			value = line[:i]
+		}
+		p.params[name] = value
+		if i >= len(line) {
+			return p, fmt.Errorf("value of %s is missing", p.name)
+		}
+	}
+	if line[i] != ':' {
+		return p, fmt.Errorf("malformed content line of %s", p.name)
+	}
+	p.value = line[i+1:]
+	return p, nil
+}
+
+func decodeEvent(props []property, loc *time.Location) (Event, error) {
+	e := Event{}
+	var hasStart, hasEnd, isDate bool
+	var duration time.Duration
+	var err error
+	for _, p := range props {
+		switch p.name {
+		case "UID":
+			e.UID = p.value
+		case "SEQUENCE":
+			e.Sequence, _ = strconv.Atoi(p.value)
+		case "DTSTAMP":
+			e.Stamp, _ = parseTime(p, loc)
+		case "SUMMARY":
+			e.Summary = unescape(p.value)
+		case "DESCRIPTION":
+			e.Description = unescape(p.value)
+		case "LOCATION":
+			e.Location = unescape(p.value)
+		case "URL":
+			e.URL = p.value
+		case "STATUS":
+			e.Status = strings.ToUpper(p.value)
+		case "RRULE":
+			e.RRule = p.value
+		case "CATEGORIES":
+			for _, category := range splitList(p.value) {
+				if category = strings.TrimSpace(unescape(category)); category != "" {
+					e.Categories = append(e.Categories, category)
+				}
+			}
+		case "DTSTART":
+			if e.Start, err = parseTime(p, loc); err != nil {
+				return e, fmt.Errorf("invalid DTSTART: %w", err)
+			}
+			hasStart, isDate = true, isDateValue(p)
+		case "DTEND":
+			if e.End, err = parseTime(p, loc); err != nil {
+				return e, fmt.Errorf("invalid DTEND: %w", err)
+			}
+			hasEnd = true
+		case "DURATION":
+			if duration, err = parseDuration(p.value); err != nil {
+				return e, fmt.Errorf("invalid DURATION: %w", err)
+			}
+		case "RECURRENCE-ID":
+			if e.RecurrenceID, err = parseTime(p, loc); err != nil {
+				return e, fmt.Errorf("invalid RECURRENCE-ID: %w", err)
+			}
+		case "EXDATE":
+			for _, value := range strings.Split(p.value, ",") {
+				exDate, err := parseTime(property{params: p.params, value: value}, loc)
+				if err != nil {
+					return e, fmt.Errorf("invalid EXDATE: %w", err)
+				}
+				e.ExDates = append(e.ExDates, exDate)
+			}
+		}
+	}
+	if e.UID == "" {
+		return e, errors.New("UID is missing")
+	}
+	if !hasStart {
+		return e, errors.New("DTSTART is missing")
+	}
+	// Without DTEND and DURATION events on dates last for the day, events at date-times take no time.
+	if !hasEnd {
+		if duration == 0 && isDate {
+			e.End = e.Start.AddDate(0, 0, 1)
+		} else {
+			e.End = e.Start.Add(duration)
+		}
+	}
+	return e, nil
+}
+
+func isDateValue(p property) bool {
+	return strings.EqualFold(p.params["VALUE"], "DATE") || len(p.value) == len(dateLayout)
+}
+
+// parseTime parses DATE or DATE-TIME value. UTC times are returned in UTC, times with TZID in their zone,
+// floating times and dates in loc.
+func parseTime(p property, loc *time.Location) (time.Time, error) {
+	if isDateValue(p) {
+		return time.ParseInLocation(dateLayout, p.value, loc)
+	}
+	if strings.HasSuffix(p.value, "Z") {
+		return time.Parse(utcLayout, p.value)
+	}
+	if tzid := p.params["TZID"]; tzid != "" {
+		tz, err := time.LoadLocation(strings.TrimPrefix(tzid, "/"))
+		if err != nil {
+			return time.Time{}, fmt.Errorf("unknown timezone %q", tzid)
+		}
+		loc = tz
+	}
+	return time.ParseInLocation(localLayout, p.value, loc)
+}
+
+// parseDuration parses RFC 5545 duration, such as "PT1H30M" or "P1D".
+func parseDuration(value string) (time.Duration, error) {
+	sign := time.Duration(1)
+	if strings.HasPrefix(value, "-") {
+		sign, value = -1, value[1:]
+	}
+	value = strings.TrimPrefix(value, "+")
+	if !strings.HasPrefix(value, "P") || len(value) < 3 {
+		return 0, fmt.Errorf("malformed duration %q", value)
+	}
+	units := map[byte]time.Duration{
+		'W': 7 * 24 * time.Hour,
+		'D': 24 * time.Hour,
+		'H': time.Hour,
+		'M': time.Minute,
+		'S': time.Second,
+	}
+	var d time.Duration
+	number := ""
+	for i := 1; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case c >= '0' && c <= '9':
+			number += string(c)
+		case c == 'T' && number == "":
+		default:
+			unit, ok := units[c]
+			n, err := strconv.Atoi(number)
+			if !ok || err != nil {
+				return 0, fmt.Errorf("malformed duration %q", value)
+			}
+			d += time.Duration(n) * unit
+			number = ""
+		}
+	}
+	if number != "" {
+		return 0, fmt.Errorf("malformed duration %q", value)
+	}
+	return sign * d, nil
+}
+
+// splitList splits the value by commas which are not escaped.
+func splitList(value string) []string {
+	var items []string
+	start := 0
+	for i := 0; i < len(value); i++ {
+		if value[i] == '\\' {
+			i++
+		} else if value[i] == ',' {
+			items = append(items, value[start:i])
+			start = i + 1
+		}
+	}
+	return append(items, value[start:])
+}
+
+var textUnescaper = strings.NewReplacer(`\\`, `\`, `\;`, ";", `\,`, ",", `\n`, "\n", `\N`, "\n")
+
+func unescape(text string) string {
+	return textUnescaper.Replace(text)
+}
@@ -0,0 +1,135 @@
+package ical
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+	"time"
+)
+
+func decode(t *testing.T, lines ...string) []DecodedEvent {
+	events, err := Decode(strings.NewReader(strings.Join(lines, "\r\n")+"\r\n"), time.UTC)
+	require.NoError(t, err)
+	return events
+}
+
+func TestDecode(t *testing.T) {
+	events := decode(t,
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"BEGIN:VTIMEZONE",
+		"TZID:Europe/Berlin",
+		"END:VTIMEZONE",
+		"BEGIN:VEVENT",
+		"UID:lecture-1@example.com",
+		"DTSTART;TZID=Europe/Berlin:20240305T190000",
+		"DURATION:PT1H30M",
+		"SUMMARY:Lecture\\; part 1\\, intro",
+		"DESCRIPTION:Line one\\nLine two that is long enough to be folded by the calendar",
+		"  app",
+		"CATEGORIES:free,kids\\,teens",
+		"RRULE:FREQ=WEEKLY;COUNT=4",
+		"EXDATE;TZID=Europe/Berlin:20240312T190000,20240319T190000",
+		"BEGIN:VALARM",
+		"DESCRIPTION:Reminder",
+		"END:VALARM",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:holiday@example.com",
+		"DTSTART;VALUE=DATE:20240501",
+		"STATUS:CANCELLED",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"SUMMARY:No UID",
+		"DTSTART:20240501T100000Z",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	)
+	require.Len(t, events, 3)
+
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	lecture := events[0]
+	require.NoError(t, lecture.Err)
+	assert.Equal(t, 6, lecture.Line)
+	assert.Equal(t, "lecture-1@example.com", lecture.UID)
+	assert.Equal(t, time.Date(2024, 3, 5, 19, 0, 0, 0, berlin), lecture.Start)
+	assert.Equal(t, "Europe/Berlin", lecture.Start.Location().String())
+	assert.Equal(t, lecture.Start.Add(90*time.Minute), lecture.End)
+	assert.Equal(t, "Lecture; part 1, intro", lecture.Summary)
+	assert.Equal(t, "Line one\nLine two that is long enough to be folded by the calendar app", lecture.Description)
+	assert.Equal(t, []string{"free", "kids,teens"}, lecture.Categories)
+	assert.Equal(t, "FREQ=WEEKLY;COUNT=4", lecture.RRule)
+	assert.Len(t, lecture.ExDates, 2)
+
+	holiday := events[1]
+	require.NoError(t, holiday.Err)
+	assert.Equal(t, time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), holiday.Start)
+	assert.Equal(t, time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC), holiday.End)
+	assert.Equal(t, StatusCancelled, holiday.Status)
+
+	assert.EqualError(t, events[2].Err, "UID is missing")
+}
+
+func TestDecode_Malformed(t *testing.T) {
+	_, err := Decode(strings.NewReader("BEGIN:VEVENT\r\nEND:VEVENT\r\n"), time.UTC)
+	assert.ErrorIs(t, err, ErrInvalidCalendar)
+
+	_, err = Decode(strings.NewReader("BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nEND:VCALENDAR\r\n"), time.UTC)
+	assert.ErrorIs(t, err, ErrInvalidCalendar)
+}
+
+func TestDecode_UnknownTimezone(t *testing.T) {
+	events := decode(t,
+		"BEGIN:VCALENDAR",
+		"BEGIN:VEVENT",
+		"UID:1",
+		"DTSTART;TZID=\"Russian Standard Time\":20240305T190000",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	)
+	require.Len(t, events, 1)
+	assert.ErrorContains(t, events[0].Err, `unknown timezone "Russian Standard Time"`)
+}
+
+func TestDecode_RoundTrip(t *testing.T) {
+	moscow, err := time.LoadLocation("Europe/Moscow")
+	require.NoError(t, err)
+	start := time.Date(2024, 5, 10, 19, 0, 0, 0, moscow)
+	var buf bytes.Buffer
+	require.NoError(t, (&Calendar{ProdID: "-//Test//EN", Events: []Event{{
+		UID:        "event-1@example.com",
+		Start:      start,
+		End:        start.Add(time.Hour),
+		Summary:    strings.Repeat("Лекция, ", 20),
+		Categories: []string{"free"},
+	}}}).Encode(&buf))
+
+	events, err := Decode(&buf, time.UTC)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	require.NoError(t, events[0].Err)
+	assert.Equal(t, strings.Repeat("Лекция, ", 20), events[0].Summary)
+	assert.True(t, start.Equal(events[0].Start))
+	assert.Equal(t, "Europe/Moscow", events[0].Start.Location().String())
+}
+
+func TestParseDuration(t *testing.T) {
+	for value, expected := range map[string]time.Duration{
+		"PT1H30M": 90 * time.Minute,
+		"P1D":     24 * time.Hour,
+		"P1W":     7 * 24 * time.Hour,
+		"-PT15M":  -15 * time.Minute,
+		"P1DT2H":  26 * time.Hour,
+	} {
+		d, err := parseDuration(value)
+		assert.NoError(t, err, value)
+		assert.Equal(t, expected, d, value)
+	}
+	_, err := parseDuration("PT")
+	assert.Error(t, err)
+	_, err = parseDuration("1H")
+	assert.Error(t, err)
+}
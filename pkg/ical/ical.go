@@ -0,0 +1,271 @@
+// Package ical writes iCalendar (RFC 5545) calendars of events. Times of events in zones other than UTC
+// are written with TZID, the calendar includes VTIMEZONE for each such zone built from the tz database.
+package ical
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	StatusConfirmed = "CONFIRMED"
+	StatusCancelled = "CANCELLED"
+)
+
+const (
+	utcLayout   = "20060102T150405Z"
+	localLayout = "20060102T150405"
+	// maxLineLength is the limit of content lines in octets, longer lines are folded.
+	maxLineLength = 75
+	// timezoneYearsAhead is how many years after the last event VTIMEZONE covers,
+	// so calendar apps convert occurrences of recurring events correctly.
+	timezoneYearsAhead = 2
+)
+
+type Calendar struct {
+	ProdID string
+	// Name is shown by calendar apps as the name of subscribed calendar.
+	Name   string
+	Events []Event
+}
+
+// Event is VEVENT. Start and End are written in the location of Start.
+// Overridden occurrences of recurring event are separate events with the same UID and RecurrenceID set.
+type Event struct {
+	UID         string
+	Sequence    int
+	Stamp       time.Time
+	Start       time.Time
+	End         time.Time
+	Summary     string
+	Description string
+	Location    string
+	Geo         *Geo
+	URL         string
+	Categories  []string
+	Status      string
+	RRule       string
+	ExDates     []time.Time
+	// RecurrenceID is the beginning of the occurrence the event overrides according to the rule.
+	RecurrenceID time.Time
+}
+
+type Geo struct {
+	Latitude  float64
+	Longitude float64
+}
+
+// Encode writes the calendar with CRLF line endings and lines folded at 75 octets.
+func (c *Calendar) Encode(w io.Writer) error {
+	e := &encoder{}
+	e.line("BEGIN", "VCALENDAR")
+	e.line("VERSION", "2.0")
+	e.line("PRODID", c.ProdID)
+	e.line("CALSCALE", "GREGORIAN")
+	if c.Name != "" {
+		e.line("X-WR-CALNAME", escape(c.Name))
+	}
+	for _, tz := range timezones(c.Events) {
+		e.timezone(tz.loc, tz.from, tz.to)
+	}
+	for i := range c.Events {
+		e.event(&c.Events[i])
+	}
+	e.line("END", "VCALENDAR")
+	_, err := w.Write(e.buf.Bytes())
+	return err
+}
+
+type encoder struct {
+	buf bytes.Buffer
+}
+
+func (e *encoder) event(ev *Event) {
+	e.line("BEGIN", "VEVENT")
+	e.line("UID", ev.UID)
+	e.line("SEQUENCE", fmt.Sprint(ev.Sequence))
+	e.line("DTSTAMP", ev.Stamp.UTC().Format(utcLayout))
+	if !ev.RecurrenceID.IsZero() {
+		e.time("RECURRENCE-ID", ev.RecurrenceID.In(ev.Start.Location()))
+	}
+	e.time("DTSTART", ev.Start)
+	e.time("DTEND", ev.End.In(ev.Start.Location()))
+	if ev.RRule != "" {
+		e.line("RRULE", ev.RRule)
+	}
+	for _, exDate := range ev.ExDates {
+		e.time("EXDATE", exDate.In(ev.Start.Location()))
+	}
+	e.line("SUMMARY", escape(ev.Summary))
+	if ev.Description != "" {
+		e.line("DESCRIPTION", escape(ev.Description))
+	}
+	if ev.Location != "" {
+		e.line("LOCATION", escape(ev.Location))
+	}
+	if ev.Geo != nil {
+		e.line("GEO", fmt.Sprintf("%.6f;%.6f", ev.Geo.Latitude, ev.Geo.Longitude))
+	}
+	if ev.URL != "" {
+		e.line("URL", ev.URL)
+	}
+	if len(ev.Categories) > 0 {
+		categories := make([]string, len(ev.Categories))
+		for i, category := range ev.Categories {
+			categories[i] = escape(category)
+		}
+		e.line("CATEGORIES", strings.Join(categories, ","))
+	}
+	if ev.Status != "" {
+		e.line("STATUS", ev.Status)
+	}
+	e.line("END", "VEVENT")
+}
+
+// time writes date-time property in UTC form for UTC times, with TZID otherwise.
+func (e *encoder) time(name string, t time.Time) {
+	if t.Location() == time.UTC {
+		e.line(name, t.Format(utcLayout))
+		return
+	}
+	e.line(name+";TZID="+t.Location().String(), t.Format(localLayout))
+}
+
+// timezone writes VTIMEZONE with an observance for each transition of the zone within [from, to).
+// Observances have no RRULE, so the definition is exact for any rules the zone has had.
+func (e *encoder) timezone(loc *time.Location, from, to time.Time) {
+	e.line("BEGIN", "VTIMEZONE")
+	e.line("TZID", loc.String())
+	t := from
+	name, offset := t.Zone()
+	e.observance(t, offset, offset, name)
+	for t.Before(to) {
+		next := t.Add(24 * time.Hour)
+		if nextName, nextOffset := next.Zone(); nextName != name || nextOffset != offset {
+			transition := findTransition(t, next)
+			nextName, nextOffset = transition.Zone()
+			e.observance(transition, offset, nextOffset, nextName)
+			name, offset = nextName, nextOffset
+		}
+		t = next
+	}
+	e.line("END", "VTIMEZONE")
+}
+
+// observance writes STANDARD or DAYLIGHT component. Its DTSTART is the local time of the onset
+// in terms of the offset in effect before it.
+func (e *encoder) observance(onset time.Time, offsetFrom, offsetTo int, name string) {
+	component := "STANDARD"
+	if onset.IsDST() {
+		component = "DAYLIGHT"
+	}
+	e.line("BEGIN", component)
+	e.line("DTSTART", onset.UTC().Add(time.Duration(offsetFrom)*time.Second).Format(localLayout))
+	e.line("TZOFFSETFROM", formatOffset(offsetFrom))
+	e.line("TZOFFSETTO", formatOffset(offsetTo))
+	if name != "" {
+		e.line("TZNAME", escape(name))
+	}
+	e.line("END", component)
+}
+
+// line writes the content line folding it, so no line is longer than 75 octets.
+// Lines are split at character boundaries, continuation lines begin with a space.
+func (e *encoder) line(name, value string) {
+	line := name + ":" + value
+	limit := maxLineLength
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		e.buf.WriteString(line[:cut])
+		e.buf.WriteString("\r\n ")
+		line = line[cut:]
+		// The leading space of continuation lines counts towards the limit.
+		limit = maxLineLength - 1
+	}
+	e.buf.WriteString(line)
+	e.buf.WriteString("\r\n")
+}
+
+type timezoneRange struct {
+	loc      *time.Location
+	from, to time.Time
+}
+
+// timezones returns zones of the events with the years the events span, the zones are sorted by name.
+func timezones(events []Event) []timezoneRange {
+	ranges := map[string]*timezoneRange{}
+	for i := range events {
+		loc := events[i].Start.Location()
+		if loc == time.UTC {
+			continue
+		}
+		start, end := events[i].Start, events[i].End.In(loc)
+		if !events[i].RecurrenceID.IsZero() && events[i].RecurrenceID.Before(start) {
+			start = events[i].RecurrenceID.In(loc)
+		}
+		from := time.Date(start.Year(), time.January, 1, 0, 0, 0, 0, loc)
+		to := time.Date(end.Year()+timezoneYearsAhead, time.January, 1, 0, 0, 0, 0, loc)
+		if now := time.Now().In(loc); events[i].RRule != "" && now.After(end) {
+			to = time.Date(now.Year()+timezoneYearsAhead, time.January, 1, 0, 0, 0, 0, loc)
+		}
+		r, ok := ranges[loc.String()]
+		if !ok {
+			ranges[loc.String()] = &timezoneRange{loc: loc, from: from, to: to}
+			continue
+		}
+		if from.Before(r.from) {
+			r.from = from
+		}
+		if to.After(r.to) {
+			r.to = to
+		}
+	}
+	result := make([]timezoneRange, 0, len(ranges))
+	for _, r := range ranges {
+		result = append(result, *r)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].loc.String() < result[j].loc.String()
+	})
+	return result
+}
+
+// findTransition returns the first second in (before, after] with the zone of after.
+func findTransition(before, after time.Time) time.Time {
+	name, offset := before.Zone()
+	for after.Sub(before) > time.Second {
+		mid := before.Add(after.Sub(before) / 2).Truncate(time.Second)
+		if midName, midOffset := mid.Zone(); midName == name && midOffset == offset {
+			before = mid
+		} else {
+			after = mid
+		}
+	}
+	return after
+}
+
+func formatOffset(offset int) string {
+	sign := "+"
+	if offset < 0 {
+		sign, offset = "-", -offset
+	}
+	hours, minutes, seconds := offset/3600, offset%3600/60, offset%60
+	if seconds != 0 {
+		return fmt.Sprintf("%s%02d%02d%02d", sign, hours, minutes, seconds)
+	}
+	return fmt.Sprintf("%s%02d%02d", sign, hours, minutes)
+}
+
+var textEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", "")
+
+func escape(text string) string {
+	return textEscaper.Replace(text)
+}
@@ -0,0 +1,89 @@
+package ical
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func encode(t *testing.T, c *Calendar) string {
+	var buf bytes.Buffer
+	require.NoError(t, c.Encode(&buf))
+	return buf.String()
+}
+
+func TestCalendar_Encode_UTC(t *testing.T) {
+	start := time.Date(2024, 5, 10, 16, 0, 0, 0, time.UTC)
+	out := encode(t, &Calendar{ProdID: "-//Test//EN", Events: []Event{{
+		UID:         "event-1@example.com",
+		Sequence:    3,
+		Stamp:       start,
+		Start:       start,
+		End:         start.Add(2 * time.Hour),
+		Summary:     "Lecture; part 1, intro",
+		Description: "Line one\nLine two",
+		Status:      StatusCancelled,
+	}}})
+
+	assert.True(t, strings.HasPrefix(out, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n"))
+	assert.True(t, strings.HasSuffix(out, "END:VCALENDAR\r\n"))
+	assert.NotContains(t, out, "VTIMEZONE")
+	assert.Contains(t, out, "\r\nDTSTART:20240510T160000Z\r\nDTEND:20240510T180000Z\r\n")
+	assert.Contains(t, out, "\r\nSEQUENCE:3\r\n")
+	assert.Contains(t, out, `SUMMARY:Lecture\; part 1\, intro`)
+	assert.Contains(t, out, `DESCRIPTION:Line one\nLine two`)
+	assert.Contains(t, out, "\r\nSTATUS:CANCELLED\r\n")
+}
+
+func TestCalendar_Encode_FoldsLongLines(t *testing.T) {
+	start := time.Date(2024, 5, 10, 16, 0, 0, 0, time.UTC)
+	out := encode(t, &Calendar{ProdID: "-//Test//EN", Events: []Event{{
+		UID:         "event-1@example.com",
+		Start:       start,
+		End:         start,
+		Description: strings.Repeat("Лекция ", 40),
+	}}})
+
+	for _, line := range strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n") {
+		assert.LessOrEqual(t, len(line), maxLineLength)
+		assert.True(t, utf8.ValidString(line), "lines should be folded at character boundaries")
+	}
+	unfolded := strings.ReplaceAll(out, "\r\n ", "")
+	assert.Contains(t, unfolded, "DESCRIPTION:"+strings.Repeat("Лекция ", 40)+"\r\n")
+}
+
+func TestCalendar_Encode_Timezone(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	start := time.Date(2024, 3, 5, 19, 0, 0, 0, berlin)
+	out := encode(t, &Calendar{ProdID: "-//Test//EN", Events: []Event{{
+		UID:     "event-1@example.com",
+		Start:   start,
+		End:     start.Add(time.Hour),
+		RRule:   "FREQ=WEEKLY;COUNT=4",
+		ExDates: []time.Time{start.AddDate(0, 0, 7).UTC()},
+	}, {
+		UID:          "event-1@example.com",
+		RecurrenceID: start.AddDate(0, 0, 28),
+		Start:        start.AddDate(0, 0, 28).Add(time.Hour),
+		End:          start.AddDate(0, 0, 28).Add(2 * time.Hour),
+	}}})
+
+	assert.Contains(t, out, "\r\nDTSTART;TZID=Europe/Berlin:20240305T190000\r\n")
+	assert.Contains(t, out, "\r\nEXDATE;TZID=Europe/Berlin:20240312T190000\r\n")
+	assert.Contains(t, out, "\r\nRECURRENCE-ID;TZID=Europe/Berlin:20240402T190000\r\n")
+	assert.Equal(t, 1, strings.Count(out, "BEGIN:VTIMEZONE"))
+	assert.Contains(t, out, "BEGIN:DAYLIGHT\r\nDTSTART:20240331T020000\r\nTZOFFSETFROM:+0100\r\nTZOFFSETTO:+0200\r\nTZNAME:CEST\r\nEND:DAYLIGHT")
+	assert.Contains(t, out, "BEGIN:STANDARD\r\nDTSTART:20241027T030000\r\nTZOFFSETFROM:+0200\r\nTZOFFSETTO:+0100\r\nTZNAME:CET\r\nEND:STANDARD")
+	assert.Less(t, strings.Index(out, "END:VTIMEZONE"), strings.Index(out, "BEGIN:VEVENT"))
+}
+
+func TestFormatOffset(t *testing.T) {
+	assert.Equal(t, "+0300", formatOffset(3*3600))
+	assert.Equal(t, "-0930", formatOffset(-(9*3600 + 30*60)))
+	assert.Equal(t, "+023017", formatOffset(2*3600+30*60+17))
+}
@@ -0,0 +1,476 @@
+// Package qr encodes data to QR codes (ISO/IEC 18004) in byte mode and renders them to images.
+// The smallest version fitting the data is used, the mask is chosen by the penalty score.
+package qr
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+)
+
+// Level is the error correction level, higher levels recover more damage and hold less data.
+type Level int
+
+const (
+	L Level = iota // recovers about 7% of codewords
+	M              // recovers about 15% of codewords
+	Q              // recovers about 25% of codewords
+	H              // recovers about 30% of codewords
+)
+
+const (
+	minVersion = 1
+	maxVersion = 40
+	// quietZone is the width of the light border around the code in modules.
+	quietZone = 4
+)
+
+var ErrDataTooLong = errors.New("data is too long for QR code")
+
+// formatBits are the error correction bits of format information, they are not in the order of levels.
+var formatBits = [...]int{L: 1, M: 0, Q: 3, H: 2}
+
+// eccCodewordsPerBlock and numEccBlocks are indexed by level and version.
+var eccCodewordsPerBlock = [4][41]int{
+	{-1, 7, 10, 15, 20, 26, 18, 20, 24, 30, 18, 20, 24, 26, 30, 22, 24, 28, 30, 28, 28, 28, 28, 30, 30, 26, 28, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+	{-1, 10, 16, 26, 18, 24, 16, 18, 22, 22, 26, 30, 22, 22, 24, 24, 28, 28, 26, 26, 26, 26, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28},
+	{-1, 13, 22, 18, 26, 18, 24, 18, 22, 20, 24, 28, 26, 24, 20, 30, 24, 28, 28, 26, 30, 28, 30, 30, 30, 30, 28, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+	{-1, 17, 28, 22, 16, 22, 28, 26, 26, 24, 28, 24, 28, 22, 24, 24, 30, 28, 28, 26, 28, 30, 24, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+}
+
+var numEccBlocks = [4][41]int{
+	{-1, 1, 1, 1, 1, 1, 2, 2, 2, 2, 4, 4, 4, 4, 4, 6, 6, 6, 6, 7, 8, 8, 9, 9, 10, 12, 12, 12, 13, 14, 15, 16, 17, 18, 19, 19, 20, 21, 22, 24, 25},
+	{-1, 1, 1, 1, 2, 2, 4, 4, 4, 5, 5, 5, 8, 9, 9, 10, 10, 11, 13, 14, 16, 17, 17, 18, 20, 21, 23, 25, 26, 28, 29, 31, 33, 35, 37, 38, 40, 43, 45, 47, 49},
+	{-1, 1, 1, 2, 2, 4, 4, 6, 6, 8, 8, 8, 10, 12, 16, 12, 17, 16, 18, 21, 20, 23, 23, 25, 27, 29, 34, 34, 35, 38, 40, 43, 45, 48, 51, 53, 56, 59, 62, 65, 68},
+	{-1, 1, 1, 2, 4, 4, 4, 5, 6, 8, 8, 11, 11, 16, 16, 18, 16, 19, 21, 25, 25, 25, 34, 30, 32, 35, 37, 40, 42, 45, 48, 51, 54, 57, 60, 63, 66, 70, 74, 77, 81},
+}
+
+// Code is a matrix of dark and light modules.
+type Code struct {
+	Size     int
+	modules  []bool
+	function []bool
+}
+
+// Encode encodes the data in byte mode with the smallest version which fits it at the level.
+func Encode(data []byte, level Level) (*Code, error) {
+	version := minVersion
+	for ; version <= maxVersion; version++ {
+		if 4+charCountBits(version)+len(data)*8 <= numDataCodewords(version, level)*8 {
+			break
+		}
+	}
+	if version > maxVersion {
+		return nil, ErrDataTooLong
+	}
+
+	var bits bitBuffer
+	bits.append(0b0100, 4)
+	bits.append(len(data), charCountBits(version))
+	for _, b := range data {
+		bits.append(int(b), 8)
+	}
+	capacity := numDataCodewords(version, level) * 8
+	bits.append(0, min(4, capacity-bits.len()))
+	bits.append(0, (8-bits.len()%8)%8)
+	for pad := 0xEC; bits.len() < capacity; pad ^= 0xEC ^ 0x11 {
+		bits.append(pad, 8)
+	}
+
+	size := version*4 + 17
+	c := &Code{Size: size, modules: make([]bool, size*size), function: make([]bool, size*size)}
+	c.drawFunctionPatterns(version, level)
+	c.drawCodewords(interleave(bits.bytes(), version, level))
+
+	bestMask, bestPenalty := 0, -1
+	for mask := 0; mask < 8; mask++ {
+		c.applyMask(mask)
+		c.drawFormatBits(level, mask)
+		if penalty := c.penalty(); bestPenalty < 0 || penalty < bestPenalty {
+			bestMask, bestPenalty = mask, penalty
+		}
+		c.applyMask(mask)
+	}
+	c.applyMask(bestMask)
+	c.drawFormatBits(level, bestMask)
+	return c, nil
+}
+
+// Dark reports whether the module at column x and row y is dark.
+func (c *Code) Dark(x, y int) bool {
+	return c.modules[y*c.Size+x]
+}
+
+// Image renders the code with the quiet zone, each module is scale pixels wide.
+func (c *Code) Image(scale int) image.Image {
+	width := (c.Size + quietZone*2) * scale
+	img := image.NewPaletted(image.Rect(0, 0, width, width), color.Palette{color.White, color.Black})
+	for y := 0; y < c.Size; y++ {
+		for x := 0; x < c.Size; x++ {
+			if !c.Dark(x, y) {
+				continue
+			}
+			for dy := 0; dy < scale; dy++ {
+				for dx := 0; dx < scale; dx++ {
+					img.SetColorIndex((x+quietZone)*scale+dx, (y+quietZone)*scale+dy, 1)
+				}
+			}
+		}
+	}
+	return img
+}
+
+// PNG renders the code to PNG image, each module is scale pixels wide.
+func (c *Code) PNG(scale int) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, c.Image(scale)); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (c *Code) set(x, y int, dark bool) {
+	c.modules[y*c.Size+x] = dark
+	c.function[y*c.Size+x] = true
+}
+
+func (c *Code) drawFunctionPatterns(version int, level Level) {
+	for i := 0; i < c.Size; i++ {
+		c.set(6, i, i%2 == 0)
+		c.set(i, 6, i%2 == 0)
+	}
+	c.drawFinderPattern(3, 3)
+	c.drawFinderPattern(c.Size-4, 3)
+	c.drawFinderPattern(3, c.Size-4)
+
+	positions := alignmentPositions(version)
+	last := len(positions) - 1
+	for i, x := range positions {
+		for j, y := range positions {
+			// Alignment patterns don't overlap finder patterns.
+			if (i == 0 && j == 0) || (i == 0 && j == last) || (i == last && j == 0) {
+				continue
+			}
+			for dy := -2; dy <= 2; dy++ {
+				for dx := -2; dx <= 2; dx++ {
+					c.set(x+dx, y+dy, max(abs(dx), abs(dy)) != 1)
+				}
+			}
+		}
+	}
+
+	// Format bits are reserved now and drawn after the mask is chosen.
+	c.drawFormatBits(level, 0)
+	c.drawVersionBits(version)
+}
+
+// drawFinderPattern draws the finder pattern with its separator centered at x, y.
+func (c *Code) drawFinderPattern(x, y int) {
+	for dy := -4; dy <= 4; dy++ {
+		for dx := -4; dx <= 4; dx++ {
+			xx, yy := x+dx, y+dy
+			if xx < 0 || xx >= c.Size || yy < 0 || yy >= c.Size {
+				continue
+			}
+			dist := max(abs(dx), abs(dy))
+			c.set(xx, yy, dist != 2 && dist != 4)
+		}
+	}
+}
+
+func (c *Code) drawFormatBits(level Level, mask int) {
+	data := formatBits[level]<<3 | mask
+	rem := data
+	for i := 0; i < 10; i++ {
+		rem = rem<<1 ^ (rem>>9)*0x537
+	}
+	bits := (data<<10 | rem) ^ 0x5412
+	bit := func(i int) bool { return bits>>i&1 != 0 }
+
+	for i := 0; i <= 5; i++ {
+		c.set(8, i, bit(i))
+	}
+	c.set(8, 7, bit(6))
+	c.set(8, 8, bit(7))
+	c.set(7, 8, bit(8))
+	for i := 9; i < 15; i++ {
+		c.set(14-i, 8, bit(i))
+	}
+
+	for i := 0; i < 8; i++ {
+		c.set(c.Size-1-i, 8, bit(i))
+	}
+	for i := 8; i < 15; i++ {
+		c.set(8, c.Size-15+i, bit(i))
+	}
+	// The dark module is always dark.
+	c.set(8, c.Size-8, true)
+}
+
+func (c *Code) drawVersionBits(version int) {
+	if version < 7 {
+		return
+	}
+	rem := version
+	for i := 0; i < 12; i++ {
+		rem = rem<<1 ^ (rem>>11)*0x1F25
+	}
+	bits := version<<12 | rem
+	for i := 0; i < 18; i++ {
+		dark := bits>>i&1 != 0
+		a, b := c.Size-11+i%3, i/3
+		c.set(a, b, dark)
+		c.set(b, a, dark)
+	}
+}
+
+// drawCodewords places codewords in two-module columns zigzagging from the bottom right corner.
+// Modules left after the codewords are the remainder bits, they are light.
+func (c *Code) drawCodewords(data []byte) {
+	i := 0
+	for right := c.Size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			// The vertical timing pattern is skipped.
+			right = 5
+		}
+		for vert := 0; vert < c.Size; vert++ {
+			for j := 0; j < 2; j++ {
+				x := right - j
+				y := vert
+				if (right+1)&2 == 0 {
+					y = c.Size - 1 - vert
+				}
+				if !c.function[y*c.Size+x] && i < len(data)*8 {
+					c.modules[y*c.Size+x] = data[i>>3]>>(7-i&7)&1 != 0
+					i++
+				}
+			}
+		}
+	}
+}
+
+// applyMask inverts data modules by the mask pattern, applying it twice removes it.
+func (c *Code) applyMask(mask int) {
+	for y := 0; y < c.Size; y++ {
+		for x := 0; x < c.Size; x++ {
+			var invert bool
+			switch mask {
+			case 0:
+				invert = (x+y)%2 == 0
+			case 1:
+				invert = y%2 == 0
+			case 2:
+				invert = x%3 == 0
+			case 3:
+				invert = (x+y)%3 == 0
+			case 4:
+				invert = (x/3+y/2)%2 == 0
+			case 5:
+				invert = x*y%2+x*y%3 == 0
+			case 6:
+				invert = (x*y%2+x*y%3)%2 == 0
+			case 7:
+				invert = ((x+y)%2+x*y%3)%2 == 0
+			}
+			if invert && !c.function[y*c.Size+x] {
+				c.modules[y*c.Size+x] = !c.modules[y*c.Size+x]
+			}
+		}
+	}
+}
+
+// penalty scores patterns which make the code harder to scan: long runs of the same color,
+// 2x2 blocks of the same color, finder-like patterns and imbalance of dark and light modules.
+func (c *Code) penalty() int {
+	penalty, dark := 0, 0
+	line := make([]bool, c.Size)
+	for _, vertical := range []bool{false, true} {
+		for i := 0; i < c.Size; i++ {
+			for j := 0; j < c.Size; j++ {
+				if vertical {
+					line[j] = c.Dark(i, j)
+				} else {
+					line[j] = c.Dark(j, i)
+				}
+			}
+			penalty += linePenalty(line)
+		}
+	}
+	for y := 0; y < c.Size; y++ {
+		for x := 0; x < c.Size; x++ {
+			if c.Dark(x, y) {
+				dark++
+			}
+			if x < c.Size-1 && y < c.Size-1 {
+				module := c.Dark(x, y)
+				if c.Dark(x+1, y) == module && c.Dark(x, y+1) == module && c.Dark(x+1, y+1) == module {
+					penalty += 3
+				}
+			}
+		}
+	}
+	total := c.Size * c.Size
+	penalty += abs(dark*20-total*10) / total * 10
+	return penalty
+}
+
+var finderLike = [2][11]bool{
+	{true, false, true, true, true, false, true, false, false, false, false},
+	{false, false, false, false, true, false, true, true, true, false, true},
+}
+
+func linePenalty(line []bool) int {
+	penalty, run := 0, 1
+	for i := 1; i <= len(line); i++ {
+		if i < len(line) && line[i] == line[i-1] {
+			run++
+			continue
+		}
+		if run >= 5 {
+			penalty += run - 2
+		}
+		run = 1
+	}
+	for i := 0; i+11 <= len(line); i++ {
+		for _, pattern := range finderLike {
+			matches := true
+			for j, dark := range pattern {
+				if line[i+j] != dark {
+					matches = false
+					break
+				}
+			}
+			if matches {
+				penalty += 40
+			}
+		}
+	}
+	return penalty
+}
+
+// interleave splits data codewords to blocks, appends error correction codewords to each block
+// and interleaves the blocks.
+func interleave(data []byte, version int, level Level) []byte {
+	numBlocks := numEccBlocks[level][version]
+	eccLen := eccCodewordsPerBlock[level][version]
+	rawCodewords := numRawDataModules(version) / 8
+	numShortBlocks := numBlocks - rawCodewords%numBlocks
+	shortBlockLen := rawCodewords / numBlocks
+
+	blocks := make([][]byte, numBlocks)
+	generator := rsGenerator(eccLen)
+	for i, k := 0, 0; i < numBlocks; i++ {
+		dataLen := shortBlockLen - eccLen
+		if i >= numShortBlocks {
+			dataLen++
+		}
+		block := append([]byte{}, data[k:k+dataLen]...)
+		k += dataLen
+		ecc := rsRemainder(block, generator)
+		if i < numShortBlocks {
+			// Short blocks are padded to align codewords of all blocks, the padding is skipped.
+			block = append(block, 0)
+		}
+		blocks[i] = append(block, ecc...)
+	}
+
+	result := make([]byte, 0, rawCodewords)
+	for i := range blocks[0] {
+		for j, block := range blocks {
+			if i != shortBlockLen-eccLen || j >= numShortBlocks {
+				result = append(result, block[i])
+			}
+		}
+	}
+	return result
+}
+
+// alignmentPositions returns coordinates of centers of alignment patterns, used both for rows and columns.
+func alignmentPositions(version int) []int {
+	if version == 1 {
+		return nil
+	}
+	num := version/7 + 2
+	step := (version*4 + num*2 + 1) / (num*2 - 2) * 2
+	if version == 32 {
+		step = 26
+	}
+	positions := make([]int, num)
+	positions[0] = 6
+	for i, pos := num-1, version*4+17-7; i >= 1; i, pos = i-1, pos-step {
+		positions[i] = pos
+	}
+	return positions
+}
+
+// numRawDataModules returns the number of modules left for data and error correction codewords
+// after function patterns, including remainder bits.
+func numRawDataModules(version int) int {
+	result := (16*version+128)*version + 64
+	if version >= 2 {
+		num := version/7 + 2
+		result -= (25*num-10)*num - 55
+		if version >= 7 {
+			result -= 36
+		}
+	}
+	return result
+}
+
+func numDataCodewords(version int, level Level) int {
+	return numRawDataModules(version)/8 - eccCodewordsPerBlock[level][version]*numEccBlocks[level][version]
+}
+
+func charCountBits(version int) int {
+	if version <= 9 {
+		return 8
+	}
+	return 16
+}
+
+type bitBuffer struct {
+	bits []bool
+}
+
+func (b *bitBuffer) append(value, length int) {
+	for i := length - 1; i >= 0; i-- {
+		b.bits = append(b.bits, value>>i&1 != 0)
+	}
+}
+
+func (b *bitBuffer) len() int {
+	return len(b.bits)
+}
+
+func (b *bitBuffer) bytes() []byte {
+	result := make([]byte, len(b.bits)/8)
+	for i, bit := range b.bits {
+		if bit {
+			result[i/8] |= 1 << (7 - i%8)
+		}
+	}
+	return result
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
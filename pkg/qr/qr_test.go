@@ -0,0 +1,142 @@
+package qr
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func version(c *Code) int {
+	return (c.Size - 17) / 4
+}
+
+func TestEncode_Capacity(t *testing.T) {
+	for _, tc := range []struct {
+		level   Level
+		length  int
+		version int
+	}{
+		{L, 17, 1},
+		{L, 18, 2},
+		{M, 14, 1},
+		{H, 7, 1},
+		{L, 271, 10},
+		{L, 272, 11},
+		{M, 331, 13},
+		{L, 2953, 40},
+	} {
+		c, err := Encode(bytes.Repeat([]byte("a"), tc.length), tc.level)
+		require.NoError(t, err)
+		assert.Equal(t, tc.version, version(c), "%d bytes at level %d", tc.length, tc.level)
+	}
+
+	_, err := Encode(bytes.Repeat([]byte("a"), 2954), L)
+	assert.ErrorIs(t, err, ErrDataTooLong)
+}
+
+func TestEncode_FunctionPatterns(t *testing.T) {
+	c, err := Encode(bytes.Repeat([]byte("ticket"), 20), M)
+	require.NoError(t, err)
+	require.Equal(t, 7, version(c))
+
+	// Finder patterns are in three corners, their centers are dark and rings alternate.
+	for _, corner := range [][2]int{{3, 3}, {c.Size - 4, 3}, {3, c.Size - 4}} {
+		x, y := corner[0], corner[1]
+		assert.True(t, c.Dark(x, y))
+		assert.False(t, c.Dark(x+2, y))
+		assert.True(t, c.Dark(x+3, y))
+	}
+	for i := 8; i < c.Size-8; i++ {
+		assert.Equal(t, i%2 == 0, c.Dark(i, 6), "horizontal timing pattern")
+		assert.Equal(t, i%2 == 0, c.Dark(6, i), "vertical timing pattern")
+	}
+	assert.True(t, c.Dark(8, c.Size-8), "dark module")
+
+	var versionBits int
+	for i := 0; i < 18; i++ {
+		if c.Dark(c.Size-11+i%3, i/3) {
+			versionBits |= 1 << i
+		}
+	}
+	assert.Equal(t, 0x07C94, versionBits)
+
+	var format int
+	for i := 0; i <= 5; i++ {
+		if c.Dark(8, i) {
+			format |= 1 << i
+		}
+	}
+	for i, module := range [][2]int{{8, 7}, {8, 8}, {7, 8}} {
+		if c.Dark(module[0], module[1]) {
+			format |= 1 << (6 + i)
+		}
+	}
+	for i := 9; i < 15; i++ {
+		if c.Dark(14-i, 8) {
+			format |= 1 << i
+		}
+	}
+	// Format information of level M with masks 0 to 7.
+	assert.Contains(t, []int{0x5412, 0x5125, 0x5E7C, 0x5B4B, 0x45F9, 0x40CE, 0x4F97, 0x4AA0}, format)
+}
+
+func TestCode_PNG(t *testing.T) {
+	c, err := Encode([]byte("https://example.com"), M)
+	require.NoError(t, err)
+
+	data, err := c.PNG(4)
+	require.NoError(t, err)
+	img, err := png.Decode(bytes.NewReader(data))
+	require.NoError(t, err)
+	width := (c.Size + quietZone*2) * 4
+	assert.Equal(t, width, img.Bounds().Dx())
+
+	r, _, _, _ := img.At(0, 0).RGBA()
+	assert.Equal(t, uint32(0xFFFF), r, "quiet zone should be light")
+	r, _, _, _ = img.At(quietZone*4, quietZone*4).RGBA()
+	assert.Equal(t, uint32(0), r, "corner of finder pattern should be dark")
+}
+
+// Golden matrices in testdata were produced by an independent encoder, '#' is a dark module.
+// They cover short and long blocks, version information and 16-bit character count.
+func TestEncode_KnownAnswers(t *testing.T) {
+	for _, tc := range []struct {
+		golden string
+		data   string
+		level  Level
+	}{
+		{"v1-L", "HELLO", L},
+		{"v1-H", "HELLO", H},
+		{"v2-M", "https://example.com", M},
+		{"v5-Q", "https://example.com/events/42/tickets/7f3c9a1e-5b2d", Q},
+		{"v7-Q", "https://example.com/events/42/check-in?ticket=7f3c9a1e-5b2d-4e8f-a6c0-1d2e3f4a5b6c", Q},
+		{"v10-Q", "https://example.com/events/42/check-in?ticket=7f3c9a1e-5b2d-4e8f-a6c0-1d2e3f4a5b6c" +
+			"&occurrence=1337&signature=3q2-7wAbCdEfGhIjKlMnOpQrStUvWxYz0123456789", Q},
+	} {
+		golden, err := os.ReadFile(filepath.Join("testdata", tc.golden+".golden"))
+		require.NoError(t, err)
+		expected := strings.Fields(string(golden))
+
+		c, err := Encode([]byte(tc.data), tc.level)
+		require.NoError(t, err)
+		require.Equal(t, len(expected), c.Size, tc.golden)
+		var actual []string
+		for y := 0; y < c.Size; y++ {
+			var row strings.Builder
+			for x := 0; x < c.Size; x++ {
+				if c.Dark(x, y) {
+					row.WriteByte('#')
+				} else {
+					row.WriteByte('.')
+				}
+			}
+			actual = append(actual, row.String())
+		}
+		assert.Equal(t, expected, actual, tc.golden)
+	}
+}
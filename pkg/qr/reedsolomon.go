@@ -0,0 +1,54 @@
+package qr
+
+// Reed-Solomon error correction over GF(256) with the primitive polynomial x^8 + x^4 + x^3 + x^2 + 1.
+var gfExp, gfLog [256]byte
+
+func init() {
+	x := 1
+	for i := 0; i < 255; i++ {
+		gfExp[i] = byte(x)
+		gfLog[x] = byte(i)
+		x <<= 1
+		if x&0x100 != 0 {
+			x ^= 0x11D
+		}
+	}
+	gfExp[255] = gfExp[0]
+}
+
+func gfMul(a, b byte) byte {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	return gfExp[(int(gfLog[a])+int(gfLog[b]))%255]
+}
+
+// rsGenerator returns coefficients of the generator polynomial of the degree, the highest first.
+func rsGenerator(degree int) []byte {
+	generator := []byte{1}
+	for i := 0; i < degree; i++ {
+		next := make([]byte, len(generator)+1)
+		for j, coef := range generator {
+			next[j] ^= coef
+			next[j+1] ^= gfMul(coef, gfExp[i])
+		}
+		generator = next
+	}
+	return generator
+}
+
+// rsRemainder returns error correction codewords of the data, the remainder of its division by the generator.
+func rsRemainder(data, generator []byte) []byte {
+	remainder := make([]byte, len(data)+len(generator)-1)
+	copy(remainder, data)
+	for i := range data {
+		coef := remainder[i]
+		if coef == 0 {
+			continue
+		}
+		for j := 1; j < len(generator); j++ {
+			remainder[i+j] ^= gfMul(generator[j], coef)
+		}
+	}
+	return remainder[len(data):]
+}
@@ -0,0 +1,458 @@
+// Package rrule implements the subset of RFC 5545 recurrence rules used by events:
+// FREQ of DAILY, WEEKLY, MONTHLY and YEARLY with INTERVAL, COUNT, UNTIL, BYDAY, BYMONTHDAY, BYMONTH and WKST.
+package rrule
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Frequency string
+
+const (
+	Daily   Frequency = "DAILY"
+	Weekly  Frequency = "WEEKLY"
+	Monthly Frequency = "MONTHLY"
+	Yearly  Frequency = "YEARLY"
+)
+
+const (
+	untilLayout     = "20060102T150405Z"
+	untilDateLayout = "20060102"
+	// maxPeriods stops expansion of rules that never match, e.g. BYMONTH=2;BYMONTHDAY=30.
+	maxPeriods = 100000
+)
+
+var ErrInvalidRule = errors.New("invalid recurrence rule")
+
+var weekdays = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+var allMonths = []time.Month{
+	time.January, time.February, time.March, time.April, time.May, time.June,
+	time.July, time.August, time.September, time.October, time.November, time.December,
+}
+
+var weekdayNames = [...]string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+// Weekday is a BYDAY value. N is the ordinal of the weekday within the month or the year,
+// negative ordinals count from the end, zero means every such weekday.
+type Weekday struct {
+	N   int
+	Day time.Weekday
+}
+
+func (w Weekday) String() string {
+	if w.N == 0 {
+		return weekdayNames[w.Day]
+	}
+	return strconv.Itoa(w.N) + weekdayNames[w.Day]
+}
+
+// Rule is a parsed recurrence rule. Zero Count and zero Until mean the rule repeats forever.
+type Rule struct {
+	Freq       Frequency
+	Interval   int
+	Count      int
+	Until      time.Time
+	ByDay      []Weekday
+	ByMonthDay []int
+	ByMonth    []time.Month
+	WeekStart  time.Weekday
+	// untilDate is set if UNTIL is a date, the rule then includes the whole day.
+	untilDate bool
+}
+
+// Parse parses the value of RRULE property, e.g. "FREQ=WEEKLY;BYDAY=TU,TH;COUNT=10".
+// The "RRULE:" prefix is optional.
+func Parse(value string) (*Rule, error) {
+	value = strings.TrimPrefix(strings.TrimSpace(value), "RRULE:")
+	r := &Rule{Interval: 1, WeekStart: time.Monday}
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(value, ";") {
+		name, val, ok := strings.Cut(part, "=")
+		name = strings.ToUpper(name)
+		if !ok || val == "" {
+			return nil, fmt.Errorf("%w: malformed part '%s'", ErrInvalidRule, part)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("%w: %s is repeated", ErrInvalidRule, name)
+		}
+		seen[name] = true
+		if err := r.parsePart(name, strings.ToUpper(val)); err != nil {
+			return nil, err
+		}
+	}
+	if err := r.validate(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *Rule) parsePart(name, val string) (err error) {
+	switch name {
+	case "FREQ":
+		r.Freq = Frequency(val)
+	case "INTERVAL":
+		r.Interval, err = parsePositive(name, val)
+	case "COUNT":
+		r.Count, err = parsePositive(name, val)
+	case "UNTIL":
+		if r.Until, err = time.Parse(untilLayout, val); err == nil {
+			return nil
+		}
+		date, dateErr := time.Parse(untilDateLayout, val)
+		if dateErr != nil {
+			return fmt.Errorf("%w: UNTIL must be a date or UTC date-time", ErrInvalidRule)
+		}
+		r.Until, r.untilDate = date, true
+		return nil
+	case "BYDAY":
+		for _, v := range strings.Split(val, ",") {
+			day, err := parseWeekday(v)
+			if err != nil {
+				return err
+			}
+			r.ByDay = append(r.ByDay, day)
+		}
+	case "BYMONTHDAY":
+		for _, v := range strings.Split(val, ",") {
+			day, err := strconv.Atoi(v)
+			if err != nil || day == 0 || day < -31 || day > 31 {
+				return fmt.Errorf("%w: invalid BYMONTHDAY '%s'", ErrInvalidRule, v)
+			}
+			r.ByMonthDay = append(r.ByMonthDay, day)
+		}
+	case "BYMONTH":
+		for _, v := range strings.Split(val, ",") {
+			month, err := strconv.Atoi(v)
+			if err != nil || month < 1 || month > 12 {
+				return fmt.Errorf("%w: invalid BYMONTH '%s'", ErrInvalidRule, v)
+			}
+			r.ByMonth = append(r.ByMonth, time.Month(month))
+		}
+	case "WKST":
+		day, ok := weekdays[val]
+		if !ok {
+			return fmt.Errorf("%w: invalid WKST '%s'", ErrInvalidRule, val)
+		}
+		r.WeekStart = day
+	default:
+		return fmt.Errorf("%w: %s is not supported", ErrInvalidRule, name)
+	}
+	return err
+}
+
+func (r *Rule) validate() error {
+	switch r.Freq {
+	case Daily, Weekly, Monthly, Yearly:
+	case "":
+		return fmt.Errorf("%w: FREQ is required", ErrInvalidRule)
+	default:
+		return fmt.Errorf("%w: FREQ=%s is not supported", ErrInvalidRule, r.Freq)
+	}
+	if r.Count != 0 && !r.Until.IsZero() {
+		return fmt.Errorf("%w: COUNT and UNTIL can't be used together", ErrInvalidRule)
+	}
+	if r.Freq == Weekly && len(r.ByMonthDay) > 0 {
+		return fmt.Errorf("%w: BYMONTHDAY can't be used with FREQ=WEEKLY", ErrInvalidRule)
+	}
+	for _, day := range r.ByDay {
+		if day.N != 0 && r.Freq != Monthly && r.Freq != Yearly {
+			return fmt.Errorf("%w: BYDAY ordinals can be used only with FREQ=MONTHLY or FREQ=YEARLY", ErrInvalidRule)
+		}
+		withinMonth := r.Freq == Monthly || len(r.ByMonth) > 0
+		if day.N < -53 || day.N > 53 || (withinMonth && (day.N < -5 || day.N > 5)) {
+			return fmt.Errorf("%w: BYDAY ordinal %d is out of range", ErrInvalidRule, day.N)
+		}
+	}
+	return nil
+}
+
+// String formats the rule as the value of RRULE property.
+func (r *Rule) String() string {
+	parts := []string{"FREQ=" + string(r.Freq)}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if !r.Until.IsZero() {
+		if r.untilDate {
+			parts = append(parts, "UNTIL="+r.Until.Format(untilDateLayout))
+		} else {
+			parts = append(parts, "UNTIL="+r.Until.UTC().Format(untilLayout))
+		}
+	}
+	if len(r.ByMonth) > 0 {
+		parts = append(parts, "BYMONTH="+joinInts(len(r.ByMonth), func(i int) int { return int(r.ByMonth[i]) }))
+	}
+	if len(r.ByMonthDay) > 0 {
+		parts = append(parts, "BYMONTHDAY="+joinInts(len(r.ByMonthDay), func(i int) int { return r.ByMonthDay[i] }))
+	}
+	if len(r.ByDay) > 0 {
+		days := make([]string, 0, len(r.ByDay))
+		for _, day := range r.ByDay {
+			days = append(days, day.String())
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if r.WeekStart != time.Monday {
+		parts = append(parts, "WKST="+weekdayNames[r.WeekStart])
+	}
+	return strings.Join(parts, ";")
+}
+
+// SetUntil limits the rule to occurrences beginning at or before until. COUNT is removed.
+func (r *Rule) SetUntil(until time.Time) {
+	r.Count = 0
+	r.Until = until.UTC().Truncate(time.Second)
+	r.untilDate = false
+}
+
+// WithDateTimeUntil returns the rule with date UNTIL replaced by the end of the day in UTC.
+// RFC 5545 requires UNTIL to be date-time in UTC if DTSTART has time with TZID.
+func (r *Rule) WithDateTimeUntil() *Rule {
+	rule := *r
+	if rule.untilDate {
+		rule.Until = rule.until().Truncate(time.Second)
+		rule.untilDate = false
+	}
+	return &rule
+}
+
+// Between returns beginnings of occurrences in [from, to) of the series beginning at start, at most limit.
+// The start is always the first occurrence, later occurrences have the wall clock time of the start
+// in its location, so they don't shift across daylight saving time changes.
+func (r *Rule) Between(start, from, to time.Time, limit int) []time.Time {
+	var occurrences []time.Time
+	emitted := 0
+	emit := func(t time.Time) bool {
+		if r.Count > 0 && emitted >= r.Count || !r.Until.IsZero() && t.After(r.until()) || !t.Before(to) {
+			return false
+		}
+		emitted++
+		if !t.Before(from) {
+			occurrences = append(occurrences, t)
+		}
+		return len(occurrences) < limit
+	}
+	if limit <= 0 || !emit(start) {
+		return occurrences
+	}
+
+	loc := start.Location()
+	hour, min, sec := start.Clock()
+	first := date(start.Year(), start.Month(), start.Day())
+	for period := 0; period < maxPeriods; period++ {
+		periodStart, days := r.period(first, period)
+		if t := inLocation(periodStart, hour, min, sec, loc); t.After(r.until()) || !t.Before(to) {
+			return occurrences
+		}
+		for _, day := range days {
+			t := inLocation(day, hour, min, sec, loc)
+			if !t.After(start) {
+				continue
+			}
+			if !emit(t) {
+				return occurrences
+			}
+		}
+	}
+	return occurrences
+}
+
+func (r *Rule) until() time.Time {
+	if r.Until.IsZero() {
+		return time.Date(9999, 12, 31, 0, 0, 0, 0, time.UTC)
+	}
+	if r.untilDate {
+		return r.Until.Add(24*time.Hour - time.Nanosecond)
+	}
+	return r.Until
+}
+
+// period returns the first day of the n-th period of the rule and its days matching the rule, in order.
+func (r *Rule) period(first time.Time, n int) (time.Time, []time.Time) {
+	step := n * r.Interval
+	var days []time.Time
+	switch r.Freq {
+	case Daily:
+		day := first.AddDate(0, 0, step)
+		if r.matchesMonth(day) && r.matchesWeekday(day) && r.matchesMonthDay(day) {
+			days = append(days, day)
+		}
+		return day, days
+	case Weekly:
+		weekStart := first.AddDate(0, 0, -((int(first.Weekday())-int(r.WeekStart)+7)%7)+7*step)
+		byDay := r.ByDay
+		if len(byDay) == 0 {
+			byDay = []Weekday{{Day: first.Weekday()}}
+		}
+		for i := 0; i < 7; i++ {
+			day := weekStart.AddDate(0, 0, i)
+			if r.matchesMonth(day) && containsWeekday(byDay, day.Weekday()) {
+				days = append(days, day)
+			}
+		}
+		return weekStart, days
+	case Monthly:
+		month := date(first.Year(), first.Month()+time.Month(step), 1)
+		if r.matchesMonth(month) {
+			days = r.daysInSpan(month, month.AddDate(0, 1, 0), first.Day())
+		}
+		return month, days
+	default:
+		year := date(first.Year()+step, time.January, 1)
+		if len(r.ByMonth) == 0 && len(r.ByDay) > 0 && len(r.ByMonthDay) == 0 {
+			return year, r.daysInSpan(year, year.AddDate(1, 0, 0), 0)
+		}
+		months := r.ByMonth
+		if len(months) == 0 && len(r.ByMonthDay) > 0 {
+			months = allMonths
+		} else if len(months) == 0 {
+			months = []time.Month{first.Month()}
+		}
+		for _, m := range sortedMonths(months) {
+			month := date(year.Year(), m, 1)
+			days = append(days, r.daysInSpan(month, month.AddDate(0, 1, 0), first.Day())...)
+		}
+		return year, days
+	}
+}
+
+// daysInSpan returns days in [from, to) matching BYMONTHDAY and BYDAY. Ordinals of BYDAY are counted within the span.
+// Without both, the span includes only the day of month of the start, if the month has it.
+func (r *Rule) daysInSpan(from, to time.Time, startDay int) []time.Time {
+	var days []time.Time
+	for day := from; day.Before(to); day = day.AddDate(0, 0, 1) {
+		switch {
+		case len(r.ByMonthDay) == 0 && len(r.ByDay) == 0:
+			if day.Day() == startDay {
+				days = append(days, day)
+			}
+		case len(r.ByDay) == 0 || r.matchesOrdinalWeekday(day, from, to):
+			if r.matchesMonthDay(day) {
+				days = append(days, day)
+			}
+		}
+	}
+	return days
+}
+
+func (r *Rule) matchesOrdinalWeekday(day, from, to time.Time) bool {
+	for _, w := range r.ByDay {
+		if w.Day != day.Weekday() {
+			continue
+		}
+		if w.N == 0 {
+			return true
+		}
+		if w.N > 0 && int(day.Sub(from).Hours()/24)/7+1 == w.N {
+			return true
+		}
+		if w.N < 0 && int(to.Sub(day).Hours()/24-1)/7+1 == -w.N {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Rule) matchesMonth(day time.Time) bool {
+	if len(r.ByMonth) == 0 {
+		return true
+	}
+	for _, m := range r.ByMonth {
+		if m == day.Month() {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Rule) matchesWeekday(day time.Time) bool {
+	return len(r.ByDay) == 0 || containsWeekday(r.ByDay, day.Weekday())
+}
+
+func (r *Rule) matchesMonthDay(day time.Time) bool {
+	if len(r.ByMonthDay) == 0 {
+		return true
+	}
+	daysInMonth := date(day.Year(), day.Month()+1, 0).Day()
+	for _, d := range r.ByMonthDay {
+		if d == day.Day() || d < 0 && daysInMonth+d+1 == day.Day() {
+			return true
+		}
+	}
+	return false
+}
+
+func parseWeekday(value string) (Weekday, error) {
+	if len(value) < 2 {
+		return Weekday{}, fmt.Errorf("%w: invalid BYDAY '%s'", ErrInvalidRule, value)
+	}
+	day, ok := weekdays[value[len(value)-2:]]
+	if !ok {
+		return Weekday{}, fmt.Errorf("%w: invalid BYDAY '%s'", ErrInvalidRule, value)
+	}
+	w := Weekday{Day: day}
+	if ordinal := value[:len(value)-2]; ordinal != "" {
+		n, err := strconv.Atoi(ordinal)
+		if err != nil || n == 0 {
+			return Weekday{}, fmt.Errorf("%w: invalid BYDAY '%s'", ErrInvalidRule, value)
+		}
+		w.N = n
+	}
+	return w, nil
+}
+
+func parsePositive(name, value string) (int, error) {
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("%w: %s must be a positive integer", ErrInvalidRule, name)
+	}
+	return n, nil
+}
+
+func containsWeekday(days []Weekday, day time.Weekday) bool {
+	for _, d := range days {
+		if d.Day == day {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedMonths(months []time.Month) []time.Month {
+	sorted := append([]time.Month(nil), months...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	return sorted
+}
+
+func joinInts(n int, value func(i int) int) string {
+	values := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		values = append(values, strconv.Itoa(value(i)))
+	}
+	return strings.Join(values, ",")
+}
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func inLocation(day time.Time, hour, min, sec int, loc *time.Location) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day(), hour, min, sec, 0, loc)
+}
@@ -0,0 +1,135 @@
+package rrule
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func mustParse(t *testing.T, value string) *Rule {
+	r, err := Parse(value)
+	require.NoError(t, err)
+	return r
+}
+
+func days(times []time.Time) []string {
+	formatted := make([]string, 0, len(times))
+	for _, t := range times {
+		formatted = append(formatted, t.Format("2006-01-02 15:04"))
+	}
+	return formatted
+}
+
+var farFuture = time.Date(2100, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func TestParse_RoundTrip(t *testing.T) {
+	for _, value := range []string{
+		"FREQ=WEEKLY;COUNT=10;BYDAY=TU,TH",
+		"FREQ=MONTHLY;INTERVAL=2;UNTIL=20240101T000000Z;BYDAY=-1FR",
+		"FREQ=YEARLY;UNTIL=20301231;BYMONTH=3,9;BYMONTHDAY=1",
+		"FREQ=WEEKLY;INTERVAL=2;BYDAY=MO;WKST=SU",
+	} {
+		assert.Equal(t, value, mustParse(t, value).String())
+	}
+	assert.Equal(t, "FREQ=DAILY", mustParse(t, "RRULE:freq=daily").String())
+}
+
+func TestRule_WithDateTimeUntil(t *testing.T) {
+	r := mustParse(t, "FREQ=DAILY;UNTIL=20240110")
+	assert.Equal(t, "FREQ=DAILY;UNTIL=20240110T235959Z", r.WithDateTimeUntil().String())
+	assert.Equal(t, "FREQ=DAILY;UNTIL=20240110", r.String(), "the rule should not be changed")
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for _, value := range []string{
+		"",
+		"COUNT=3",
+		"FREQ=HOURLY",
+		"FREQ=DAILY;COUNT=0",
+		"FREQ=DAILY;COUNT=3;UNTIL=20240101",
+		"FREQ=DAILY;COUNT=3;COUNT=4",
+		"FREQ=DAILY;BYDAY=1MO",
+		"FREQ=WEEKLY;BYMONTHDAY=3",
+		"FREQ=MONTHLY;BYDAY=6MO",
+		"FREQ=MONTHLY;BYSETPOS=1",
+		"FREQ=DAILY;UNTIL=2024-01-01",
+	} {
+		_, err := Parse(value)
+		assert.ErrorIs(t, err, ErrInvalidRule, value)
+	}
+}
+
+func TestRule_Between_Weekly(t *testing.T) {
+	start := time.Date(2024, 1, 2, 19, 0, 0, 0, time.UTC) // Tuesday
+	r := mustParse(t, "FREQ=WEEKLY;BYDAY=TU,TH;COUNT=5")
+	assert.Equal(t, []string{
+		"2024-01-02 19:00", "2024-01-04 19:00", "2024-01-09 19:00", "2024-01-11 19:00", "2024-01-16 19:00",
+	}, days(r.Between(start, start, farFuture, 100)))
+
+	from := time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, []string{"2024-01-09 19:00", "2024-01-11 19:00", "2024-01-16 19:00"},
+		days(r.Between(start, from, farFuture, 100)), "COUNT should include occurrences before the window")
+	assert.Len(t, r.Between(start, start, farFuture, 2), 2)
+}
+
+func TestRule_Between_BiweeklyWithWeekStart(t *testing.T) {
+	start := time.Date(2024, 1, 7, 10, 0, 0, 0, time.UTC) // Sunday
+	r := mustParse(t, "FREQ=WEEKLY;INTERVAL=2;BYDAY=SU,MO;COUNT=4;WKST=SU")
+	assert.Equal(t, []string{"2024-01-07 10:00", "2024-01-08 10:00", "2024-01-21 10:00", "2024-01-22 10:00"},
+		days(r.Between(start, start, farFuture, 100)))
+}
+
+func TestRule_Between_MonthlyOrdinals(t *testing.T) {
+	start := time.Date(2024, 1, 26, 18, 0, 0, 0, time.UTC)
+	r := mustParse(t, "FREQ=MONTHLY;BYDAY=-1FR;COUNT=3")
+	assert.Equal(t, []string{"2024-01-26 18:00", "2024-02-23 18:00", "2024-03-29 18:00"},
+		days(r.Between(start, start, farFuture, 100)))
+
+	start = time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	r = mustParse(t, "FREQ=MONTHLY;BYDAY=2MO;UNTIL=20240331")
+	assert.Equal(t, []string{"2024-01-01 09:00", "2024-01-08 09:00", "2024-02-12 09:00", "2024-03-11 09:00"},
+		days(r.Between(start, start, farFuture, 100)), "the start is always the first occurrence")
+}
+
+func TestRule_Between_MonthlySkipsShortMonths(t *testing.T) {
+	start := time.Date(2024, 1, 31, 12, 0, 0, 0, time.UTC)
+	r := mustParse(t, "FREQ=MONTHLY;COUNT=3")
+	assert.Equal(t, []string{"2024-01-31 12:00", "2024-03-31 12:00", "2024-05-31 12:00"},
+		days(r.Between(start, start, farFuture, 100)))
+
+	r = mustParse(t, "FREQ=MONTHLY;BYMONTHDAY=-1;COUNT=3")
+	assert.Equal(t, []string{"2024-01-31 12:00", "2024-02-29 12:00", "2024-03-31 12:00"},
+		days(r.Between(start, start, farFuture, 100)))
+}
+
+func TestRule_Between_Yearly(t *testing.T) {
+	start := time.Date(2024, 3, 8, 12, 0, 0, 0, time.UTC)
+	r := mustParse(t, "FREQ=YEARLY;COUNT=3")
+	assert.Equal(t, []string{"2024-03-08 12:00", "2025-03-08 12:00", "2026-03-08 12:00"},
+		days(r.Between(start, start, farFuture, 100)))
+
+	r = mustParse(t, "FREQ=YEARLY;BYMONTH=2;BYMONTHDAY=30")
+	assert.Len(t, r.Between(start, start, farFuture, 100), 1, "rules that never match should stop")
+}
+
+func TestRule_Between_KeepsWallClockAcrossDST(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	start := time.Date(2024, 3, 30, 19, 0, 0, 0, berlin)
+	r := mustParse(t, "FREQ=DAILY;COUNT=3")
+	occurrences := r.Between(start, start, farFuture, 100)
+	require.Len(t, occurrences, 3)
+	for _, o := range occurrences {
+		assert.Equal(t, 19, o.Hour())
+	}
+	assert.Equal(t, 23*time.Hour, occurrences[1].Sub(occurrences[0]))
+}
+
+func TestRule_SetUntil(t *testing.T) {
+	start := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	r := mustParse(t, "FREQ=DAILY;COUNT=10")
+	r.SetUntil(time.Date(2024, 1, 3, 10, 0, 0, 0, time.UTC))
+	assert.Equal(t, "FREQ=DAILY;UNTIL=20240103T100000Z", r.String())
+	assert.Len(t, r.Between(start, start, farFuture, 100), 3)
+}
@@ -0,0 +1,59 @@
+package scheduler
+
+import (
+	"context"
+	"github.com/sirupsen/logrus"
+	"time"
+)
+
+type Job func(ctx context.Context) error
+
+// Scheduler runs a job periodically in background until it is shut down.
+type Scheduler struct {
+	name     string
+	interval time.Duration
+	job      Job
+	logger   *logrus.Logger
+	cancel   context.CancelFunc
+	done     chan struct{}
+}
+
+func New(name string, interval time.Duration, job Job, logger *logrus.Logger) *Scheduler {
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &Scheduler{
+		name:     name,
+		interval: interval,
+		job:      job,
+		logger:   logger,
+		cancel:   cancel,
+		done:     make(chan struct{}),
+	}
+	go s.run(ctx)
+	return s
+}
+
+func (s *Scheduler) run(ctx context.Context) {
+	defer close(s.done)
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.job(ctx); err != nil && ctx.Err() == nil {
+			s.logger.
+				WithField("job", s.name).
+				WithError(err).
+				Errorf("Job %s failed: %v", s.name, err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Shutdown stops the scheduler and waits until the running job is finished.
+func (s *Scheduler) Shutdown() {
+	s.cancel()
+	<-s.done
+}
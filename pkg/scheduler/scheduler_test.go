@@ -0,0 +1,47 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestScheduler_RunsJobPeriodically(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	var calls int32
+	s := New("test", 10*time.Millisecond, func(ctx context.Context) error {
+		atomic.AddInt32(&calls, 1)
+		return errors.New("job errors should not stop scheduler")
+	}, logger)
+
+	assert.Eventually(t, func() bool {
+		return atomic.LoadInt32(&calls) >= 3
+	}, time.Second, 5*time.Millisecond, "job should be called several times")
+
+	s.Shutdown()
+	stopped := atomic.LoadInt32(&calls)
+	time.Sleep(30 * time.Millisecond)
+	assert.Equal(t, stopped, atomic.LoadInt32(&calls), "job should not be called after shutdown")
+}
+
+func TestScheduler_ShutdownCancelsContext(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	started := make(chan struct{})
+	s := New("test", time.Hour, func(ctx context.Context) error {
+		close(started)
+		<-ctx.Done()
+		return ctx.Err()
+	}, logger)
+
+	<-started
+	s.Shutdown()
+}
@@ -0,0 +1,53 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/leporo/sqlf"
+	"github.com/pkg/errors"
+	"time"
+)
+
+type ActivationRequestRepository struct {
+	db DatabaseWrapper
+}
+
+func NewActivationRequestRepository(db DatabaseWrapper) *ActivationRequestRepository {
+	return &ActivationRequestRepository{db: db}
+}
+
+func (r *ActivationRequestRepository) CreateActivationRequest(ctx context.Context, userId int64) error {
+	_, err := sqlf.InsertInto("activation_requests").
+		Set("user_id", userId).
+		Set("requested_at", time.Now().UTC()).
+		ExecAndClose(ctx, r.db)
+
+	return err
+}
+
+func (r *ActivationRequestRepository) CountActivationRequests(ctx context.Context, userId int64, since time.Time) (int, error) {
+	count := 0
+	err := sqlf.From("activation_requests").
+		Select("count(1)").To(&count).
+		Where("user_id = ?", userId).
+		Where("requested_at >= ?", since).
+		QueryRowAndClose(ctx, r.db)
+
+	return count, err
+}
+
+// LockActivationRequests locks the user until the end of transaction, so concurrent resends
+// can't both pass the rate limit before either of them is recorded.
+func (r *ActivationRequestRepository) LockActivationRequests(ctx context.Context, userId int64) error {
+	var id int64
+	err := sqlf.From("users").
+		Select("user_id").To(&id).
+		Where("user_id = ?", userId).
+		Clause("FOR UPDATE").
+		QueryRowAndClose(ctx, r.db)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("%w: user with provided id does not exist", ErrUserNotFound)
+	}
+	return err
+}
@@ -0,0 +1,97 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"github.com/burenotti/rtu-it-lab-recruit/model"
+	"github.com/leporo/sqlf"
+)
+
+// AnalyticsRepository aggregates activity of events from event_activity_stats materialized view,
+// numbers lag behind until the view is refreshed.
+type AnalyticsRepository struct {
+	db DatabaseWrapper
+}
+
+func NewAnalyticsRepository(db DatabaseWrapper) *AnalyticsRepository {
+	return &AnalyticsRepository{db: db}
+}
+
+// RefreshActivityStats recomputes the view. It is refreshed concurrently, so analytics are read meanwhile.
+func (r *AnalyticsRepository) RefreshActivityStats(ctx context.Context) error {
+	_, err := r.db.ExecContext(ctx, "REFRESH MATERIALIZED VIEW CONCURRENTLY event_activity_stats")
+	return err
+}
+
+// ActivitySeries returns registrations and check-ins by intervals. Intervals without activity are skipped.
+func (r *AnalyticsRepository) ActivitySeries(ctx context.Context, filter *model.AnalyticsFilter) ([]model.AnalyticsPoint, error) {
+	points := make([]model.AnalyticsPoint, 0)
+	p := model.AnalyticsPoint{}
+	query := filterActivity(sqlf.From("event_activity_stats s"), filter).
+		Select("date_trunc(?, s.bucket)", filter.Interval).To(&p.Bucket).
+		Select("sum(s.registrations)::int8, sum(s.check_ins)::int8").To(&p.Registrations, &p.CheckIns).
+		GroupBy("1").
+		OrderBy("1")
+	err := query.QueryAndClose(ctx, r.db, func(rows *sql.Rows) {
+		points = append(points, p)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return points, nil
+}
+
+// TrafficSources returns registrations and check-ins by traffic sources, the most popular first.
+func (r *AnalyticsRepository) TrafficSources(ctx context.Context, filter *model.AnalyticsFilter) ([]model.TrafficSource, error) {
+	sources := make([]model.TrafficSource, 0)
+	s := model.TrafficSource{}
+	query := filterActivity(sqlf.From("event_activity_stats s"), filter).
+		Select("COALESCE(NULLIF(s.source, ''), ?)", model.SourceDirect).To(&s.Source).
+		Select("sum(s.registrations)::int8, sum(s.check_ins)::int8").To(&s.Registrations, &s.CheckIns).
+		GroupBy("1").
+		OrderBy("2 DESC, 1")
+	err := query.QueryAndClose(ctx, r.db, func(rows *sql.Rows) {
+		sources = append(sources, s)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sources, nil
+}
+
+// EventAttendance returns registrations and check-ins by events, the latest events first.
+// Numbers of no-shows are not set.
+func (r *AnalyticsRepository) EventAttendance(ctx context.Context, filter *model.AnalyticsFilter) ([]model.EventAttendance, error) {
+	events := make([]model.EventAttendance, 0)
+	e := model.EventAttendance{}
+	query := filterActivity(sqlf.From("event_activity_stats s"), filter).
+		Join("events e", "e.event_id = s.event_id").
+		Select("e.event_id, e.name, e.begins_at").To(&e.EventID, &e.Name, &e.BeginsAt).
+		Select("sum(s.registrations)::int8, sum(s.check_ins)::int8").To(&e.Registered, &e.Attended).
+		GroupBy("e.event_id, e.name, e.begins_at").
+		OrderBy("e.begins_at DESC, e.event_id DESC")
+	err := query.QueryAndClose(ctx, r.db, func(rows *sql.Rows) {
+		events = append(events, e)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+func filterActivity(query *sqlf.Stmt, filter *model.AnalyticsFilter) *sqlf.Stmt {
+	if filter.EventID != nil {
+		query = query.Where("s.event_id = ?", *filter.EventID)
+	}
+	if filter.OrganizationID != nil {
+		query = query.Where("s.organization_id = ?", *filter.OrganizationID)
+	}
+	// Buckets are in UTC without time zone.
+	if filter.From != nil {
+		query = query.Where("s.bucket >= ?", filter.From.UTC())
+	}
+	if filter.To != nil {
+		query = query.Where("s.bucket < ?", filter.To.UTC())
+	}
+	return query
+}
@@ -0,0 +1,97 @@
+package repositories
+
+import (
+	"context"
+	"github.com/burenotti/rtu-it-lab-recruit/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+)
+
+type AnalyticsRepositoryTestSuite struct {
+	DBTestSuite
+}
+
+func TestAnalyticsRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, &AnalyticsRepositoryTestSuite{
+		*DBTestSuiteFromEnv(),
+	})
+}
+
+// register registers a new user for the event and checks them in at the time unless it is zero.
+func (s *AnalyticsRepositoryTestSuite) register(ctx context.Context, eventId int64, source *string, checkedInAt time.Time) {
+	db := NewDatabase(s.db)
+	user := CreateRandomUser(ctx, db, s.T())
+	registration, err := NewRegistrationRepository(db).Register(ctx, eventId, user.UserID, nil, source)
+	require.NoError(s.T(), err, "should register without errors")
+	if checkedInAt.IsZero() {
+		return
+	}
+	_, _, err = NewCheckInRepository(db).CheckIn(ctx, &model.CheckIn{
+		RegistrationID: registration.RegistrationID,
+		EventID:        eventId,
+		CheckedInAt:    checkedInAt,
+	})
+	require.NoError(s.T(), err, "should check in without errors")
+}
+
+func (s *AnalyticsRepositoryTestSuite) TestActivityStats() {
+	ctx := context.Background()
+	db := NewDatabase(s.db)
+	repo := NewAnalyticsRepository(db)
+	user := CreateRandomUser(ctx, db, s.T())
+	event := CreateRandomEvent(ctx, db, s.T(), user.UserID, time.Now().Add(72*time.Hour))
+	other := CreateRandomEvent(ctx, db, s.T(), user.UserID, time.Now().Add(72*time.Hour))
+	telegram := "telegram"
+	checkInHour := time.Now().UTC().Truncate(time.Hour).Add(3 * time.Hour)
+
+	s.register(ctx, event.EventID, &telegram, checkInHour.Add(10*time.Minute))
+	s.register(ctx, event.EventID, &telegram, time.Time{})
+	s.register(ctx, event.EventID, nil, checkInHour.Add(20*time.Minute))
+	s.register(ctx, other.EventID, &telegram, checkInHour)
+
+	filter := &model.AnalyticsFilter{EventID: &event.EventID, Interval: "hour"}
+	series, err := repo.ActivitySeries(ctx, filter)
+	require.NoError(s.T(), err)
+	assert.Empty(s.T(), series, "activity should not be counted until the view is refreshed")
+
+	require.NoError(s.T(), repo.RefreshActivityStats(ctx), "should refresh view without errors")
+
+	series, err = repo.ActivitySeries(ctx, filter)
+	require.NoError(s.T(), err)
+	require.NotEmpty(s.T(), series)
+	var registrations, checkIns int64
+	for _, point := range series {
+		registrations += point.Registrations
+		checkIns += point.CheckIns
+	}
+	assert.Equal(s.T(), int64(3), registrations)
+	assert.Equal(s.T(), int64(2), checkIns)
+	last := series[len(series)-1]
+	assert.True(s.T(), checkInHour.Equal(last.Bucket), "check-ins should be counted in the hour of the scan")
+	assert.Equal(s.T(), int64(0), last.Registrations)
+	assert.Equal(s.T(), int64(2), last.CheckIns)
+
+	sources, err := repo.TrafficSources(ctx, filter)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), []model.TrafficSource{
+		{Source: telegram, Registrations: 2, CheckIns: 1},
+		{Source: model.SourceDirect, Registrations: 1, CheckIns: 1},
+	}, sources)
+
+	attendance, err := repo.EventAttendance(ctx, &model.AnalyticsFilter{OrganizationID: &event.OrganizationID})
+	require.NoError(s.T(), err)
+	require.Len(s.T(), attendance, 1, "events of other organizations should not be counted")
+	assert.Equal(s.T(), event.EventID, attendance[0].EventID)
+	assert.Equal(s.T(), int64(3), attendance[0].Registered)
+	assert.Equal(s.T(), int64(2), attendance[0].Attended)
+
+	s.register(ctx, event.EventID, nil, time.Time{})
+	require.NoError(s.T(), repo.RefreshActivityStats(ctx), "view should be refreshed again")
+	attendance, err = repo.EventAttendance(ctx, &model.AnalyticsFilter{OrganizationID: &event.OrganizationID})
+	require.NoError(s.T(), err)
+	require.Len(s.T(), attendance, 1)
+	assert.Equal(s.T(), int64(4), attendance[0].Registered, "refresh should count new registrations")
+}
@@ -0,0 +1,65 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"github.com/burenotti/rtu-it-lab-recruit/model"
+	"github.com/leporo/sqlf"
+)
+
+type AuditLogRepository struct {
+	db DatabaseWrapper
+}
+
+func NewAuditLogRepository(db DatabaseWrapper) *AuditLogRepository {
+	return &AuditLogRepository{db: db}
+}
+
+func (r *AuditLogRepository) Record(ctx context.Context, entry *model.AuditEntryCreate) error {
+	details := entry.Details
+	if details == nil {
+		details = map[string]interface{}{}
+	}
+	data, err := json.Marshal(details)
+	if err != nil {
+		return err
+	}
+
+	_, err = sqlf.InsertInto("audit_log").
+		Set("actor_id", entry.ActorID).
+		Set("action", entry.Action).
+		Set("target_type", entry.TargetType).
+		Set("target_id", entry.TargetID).
+		Set("details", string(data)).
+		ExecAndClose(ctx, r.db)
+	return err
+}
+
+func (r *AuditLogRepository) List(ctx context.Context, limit, offset int) ([]model.AuditEntry, error) {
+	var entries []model.AuditEntry
+	var scanErr error
+	err := sqlf.From("audit_log").
+		Select("audit_id, actor_id, action, target_type, target_id, details, created_at").
+		OrderBy("audit_id DESC").
+		Limit(limit).
+		Offset(offset).
+		QueryAndClose(ctx, r.db, func(rows *sql.Rows) {
+			e := model.AuditEntry{}
+			var details []byte
+			err := rows.Scan(&e.AuditID, &e.ActorID, &e.Action, &e.TargetType, &e.TargetID, &details, &e.CreatedAt)
+			if err == nil {
+				err = json.Unmarshal(details, &e.Details)
+			}
+			if err != nil {
+				scanErr = err
+				return
+			}
+			entries = append(entries, e)
+		})
+
+	if err != nil {
+		return nil, err
+	}
+	return entries, scanErr
+}
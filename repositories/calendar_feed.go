@@ -0,0 +1,96 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/burenotti/rtu-it-lab-recruit/model"
+	"github.com/leporo/sqlf"
+)
+
+const calendarFeedColumns = "feed_id, user_id, kind, organization_id, search_id, token, created_at"
+
+var ErrCalendarFeedNotFound = errors.New("calendar feed does not exist")
+
+type CalendarFeedRepository struct {
+	db DatabaseWrapper
+}
+
+func NewCalendarFeedRepository(db DatabaseWrapper) *CalendarFeedRepository {
+	return &CalendarFeedRepository{db: db}
+}
+
+func (r *CalendarFeedRepository) CreateFeed(ctx context.Context, feed *model.CalendarFeed) (*model.CalendarFeed, error) {
+	f := &model.CalendarFeed{}
+	err := returningCalendarFeed(sqlf.InsertInto("calendar_feeds").
+		Set("user_id", feed.UserID).
+		Set("kind", feed.Kind).
+		Set("organization_id", feed.OrganizationID).
+		Set("search_id", feed.SearchID).
+		Set("token", feed.Token), f).
+		QueryRowAndClose(ctx, r.db)
+	if getViolatedConstraint(err) == "calendar_feeds_organization_id_fkey" {
+		return nil, fmt.Errorf("%w: organization with provided id does not exist", ErrOrganizationNotFound)
+	} else if getViolatedConstraint(err) == "calendar_feeds_search_id_fkey" {
+		return nil, fmt.Errorf("%w: search with provided id does not exist", ErrSavedSearchNotFound)
+	} else if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (r *CalendarFeedRepository) ListFeeds(ctx context.Context, userId int64) ([]model.CalendarFeed, error) {
+	f := model.CalendarFeed{}
+	feeds := make([]model.CalendarFeed, 0)
+	err := selectCalendarFeed(&f).
+		Where("user_id = ?", userId).
+		OrderBy("feed_id").
+		QueryAndClose(ctx, r.db, func(rows *sql.Rows) {
+			feeds = append(feeds, f)
+		})
+	if err != nil {
+		return nil, err
+	}
+	return feeds, nil
+}
+
+func (r *CalendarFeedRepository) GetFeedByToken(ctx context.Context, token string) (*model.CalendarFeed, error) {
+	f := &model.CalendarFeed{}
+	err := selectCalendarFeed(f).
+		Where("token = ?", token).
+		QueryRowAndClose(ctx, r.db)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: calendar feed with provided token does not exist", ErrCalendarFeedNotFound)
+	} else if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (r *CalendarFeedRepository) DeleteFeed(ctx context.Context, userId, feedId int64) error {
+	res, err := sqlf.DeleteFrom("calendar_feeds").
+		Where("feed_id = ?", feedId).
+		Where("user_id = ?", userId).
+		ExecAndClose(ctx, r.db)
+	if err != nil {
+		return err
+	}
+	count, _ := res.RowsAffected()
+	if count == 0 {
+		return fmt.Errorf("%w: calendar feed with provided id does not exist", ErrCalendarFeedNotFound)
+	}
+	return nil
+}
+
+func selectCalendarFeed(f *model.CalendarFeed) *sqlf.Stmt {
+	return sqlf.From("calendar_feeds").
+		Select(calendarFeedColumns).
+		To(&f.FeedID, &f.UserID, &f.Kind, &f.OrganizationID, &f.SearchID, &f.Token, &f.CreatedAt)
+}
+
+func returningCalendarFeed(query *sqlf.Stmt, f *model.CalendarFeed) *sqlf.Stmt {
+	return query.
+		Returning(calendarFeedColumns).
+		To(&f.FeedID, &f.UserID, &f.Kind, &f.OrganizationID, &f.SearchID, &f.Token, &f.CreatedAt)
+}
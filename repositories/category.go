@@ -0,0 +1,173 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/burenotti/rtu-it-lab-recruit/model"
+	"github.com/leporo/sqlf"
+	"time"
+)
+
+const (
+	CategoriesSlugUniqueName = "categories_slug_key"
+	CategoriesParentFkeyName = "categories_parent_id_fkey"
+	categoryColumns          = "category_id, parent_id, slug, name, created_at"
+)
+
+// categoryCountsQuery counts published events beginning after the argument in each category,
+// the total also counts events of subcategories of any depth.
+const categoryCountsQuery = `
+WITH RECURSIVE tree AS (
+    SELECT category_id, category_id AS root_id FROM categories
+    UNION ALL
+    SELECT c.category_id, tree.root_id FROM categories c JOIN tree ON c.parent_id = tree.category_id
+), counts AS (
+    SELECT category_id, count(*) AS events
+    FROM events
+    WHERE category_id IS NOT NULL
+      AND published_at IS NOT NULL AND hidden_at IS NULL AND cancelled_at IS NULL
+      AND begins_at >= ?
+    GROUP BY category_id
+)
+SELECT c.category_id, c.parent_id, c.slug, c.name,
+       COALESCE((SELECT counts.events FROM counts WHERE counts.category_id = c.category_id), 0),
+       COALESCE((SELECT sum(counts.events)::int8 FROM tree JOIN counts ON counts.category_id = tree.category_id
+                 WHERE tree.root_id = c.category_id), 0)
+FROM categories c
+ORDER BY c.name, c.category_id`
+
+var (
+	ErrCategoryNotFound = errors.New("category does not exist")
+	ErrCategoryExists   = errors.New("category already exists")
+	ErrCategoryInUse    = errors.New("category is used")
+)
+
+var categoryUpdatesValidator = NewUpdatesValidator([]string{"parent_id", "slug", "name"})
+
+type CategoryRepository struct {
+	db DatabaseWrapper
+}
+
+func NewCategoryRepository(db DatabaseWrapper) *CategoryRepository {
+	return &CategoryRepository{db: db}
+}
+
+func (r *CategoryRepository) CreateCategory(ctx context.Context, create *model.CategoryCreate) (*model.Category, error) {
+	c := &model.Category{}
+	err := returningCategory(sqlf.InsertInto("categories").
+		Set("parent_id", create.ParentID).
+		Set("slug", create.Slug).
+		Set("name", create.Name), c).
+		QueryRowAndClose(ctx, r.db)
+	if err = categoryError(err, create.Slug); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (r *CategoryRepository) GetCategory(ctx context.Context, categoryId int64) (*model.Category, error) {
+	c := &model.Category{}
+	err := selectCategory(c).
+		Where("category_id = ?", categoryId).
+		QueryRowAndClose(ctx, r.db)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: category with provided id does not exist", ErrCategoryNotFound)
+	} else if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// ListCategories returns the whole taxonomy ordered by name.
+func (r *CategoryRepository) ListCategories(ctx context.Context) ([]model.Category, error) {
+	c := model.Category{}
+	categories := make([]model.Category, 0)
+	err := selectCategory(&c).
+		OrderBy("name, category_id").
+		QueryAndClose(ctx, r.db, func(rows *sql.Rows) {
+			categories = append(categories, c)
+		})
+	if err != nil {
+		return nil, err
+	}
+	return categories, nil
+}
+
+// CountEvents returns the number of published events beginning after since for each category.
+func (r *CategoryRepository) CountEvents(ctx context.Context, since time.Time) ([]model.CategoryCount, error) {
+	c := model.CategoryCount{}
+	counts := make([]model.CategoryCount, 0)
+	err := sqlf.New(categoryCountsQuery, since).
+		To(&c.CategoryID, &c.ParentID, &c.Slug, &c.Name, &c.Events, &c.Total).
+		QueryAndClose(ctx, r.db, func(rows *sql.Rows) {
+			counts = append(counts, c)
+		})
+	if err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
+
+func (r *CategoryRepository) UpdateCategory(ctx context.Context, categoryId int64, updates map[string]interface{}) (*model.Category, error) {
+	if err := categoryUpdatesValidator.Validate(updates); err != nil {
+		return nil, err
+	}
+	c := &model.Category{}
+	query := sqlf.Update("categories").
+		Where("category_id = ?", categoryId)
+	for field, value := range updates {
+		query = query.Set(field, value)
+	}
+	err := returningCategory(query, c).QueryRowAndClose(ctx, r.db)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: category with provided id does not exist", ErrCategoryNotFound)
+	}
+	slug, _ := updates["slug"].(string)
+	if err = categoryError(err, slug); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// DeleteCategory deletes the category. Categories with subcategories or events can't be deleted.
+func (r *CategoryRepository) DeleteCategory(ctx context.Context, categoryId int64) error {
+	res, err := sqlf.DeleteFrom("categories").
+		Where("category_id = ?", categoryId).
+		ExecAndClose(ctx, r.db)
+	if constraint := getViolatedConstraint(err); constraint == CategoriesParentFkeyName {
+		return fmt.Errorf("%w: delete or move its subcategories first", ErrCategoryInUse)
+	} else if constraint == EventsCategoryIdFkeyName {
+		return fmt.Errorf("%w: move its events to other categories first", ErrCategoryInUse)
+	} else if err != nil {
+		return err
+	}
+	if affected, err := res.RowsAffected(); err != nil {
+		return err
+	} else if affected == 0 {
+		return fmt.Errorf("%w: category with provided id does not exist", ErrCategoryNotFound)
+	}
+	return nil
+}
+
+func categoryError(err error, slug string) error {
+	if constraint := getViolatedConstraint(err); constraint == CategoriesSlugUniqueName {
+		return fmt.Errorf("%w: category with slug '%s' already exists", ErrCategoryExists, slug)
+	} else if constraint == CategoriesParentFkeyName {
+		return fmt.Errorf("%w: parent category with provided id does not exist", ErrCategoryNotFound)
+	}
+	return err
+}
+
+func selectCategory(c *model.Category) *sqlf.Stmt {
+	return sqlf.From("categories").
+		Select(categoryColumns).
+		To(&c.CategoryID, &c.ParentID, &c.Slug, &c.Name, &c.CreatedAt)
+}
+
+func returningCategory(query *sqlf.Stmt, c *model.Category) *sqlf.Stmt {
+	return query.
+		Returning(categoryColumns).
+		To(&c.CategoryID, &c.ParentID, &c.Slug, &c.Name, &c.CreatedAt)
+}
@@ -0,0 +1,111 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/burenotti/rtu-it-lab-recruit/model"
+	"github.com/leporo/sqlf"
+)
+
+const checkInColumns = "registration_id, event_id, checked_in_by, device_id, checked_in_at, created_at"
+
+type CheckInRepository struct {
+	db DatabaseWrapper
+}
+
+func NewCheckInRepository(db DatabaseWrapper) *CheckInRepository {
+	return &CheckInRepository{db: db}
+}
+
+// CheckIn saves the check-in unless the registration is already checked in. Earlier check-in replaces
+// the saved one, since check-ins made offline may reach the server in any order. Created is false if
+// the saved check-in is kept, it is returned then.
+func (r *CheckInRepository) CheckIn(ctx context.Context, checkIn *model.CheckIn) (c *model.CheckIn, created bool, err error) {
+	c = &model.CheckIn{}
+	err = returningCheckIn(sqlf.InsertInto("check_ins").
+		Set("registration_id", checkIn.RegistrationID).
+		Set("event_id", checkIn.EventID).
+		Set("checked_in_by", checkIn.CheckedInBy).
+		Set("device_id", checkIn.DeviceID).
+		Set("checked_in_at", checkIn.CheckedInAt).
+		Clause(`ON CONFLICT (registration_id) DO UPDATE SET checked_in_by = EXCLUDED.checked_in_by,
+			device_id = EXCLUDED.device_id, checked_in_at = EXCLUDED.checked_in_at, created_at = EXCLUDED.created_at
+			WHERE EXCLUDED.checked_in_at < check_ins.checked_in_at`), c).
+		QueryRowAndClose(ctx, r.db)
+	if getViolatedConstraint(err) == "check_ins_registration_id_fkey" {
+		return nil, false, fmt.Errorf("%w: registration with provided id does not exist", ErrRegistrationNotFound)
+	} else if errors.Is(err, sql.ErrNoRows) {
+		c, err = r.getCheckIn(ctx, checkIn.RegistrationID)
+		return c, false, err
+	} else if err != nil {
+		return nil, false, err
+	}
+	return c, true, nil
+}
+
+func (r *CheckInRepository) getCheckIn(ctx context.Context, registrationId int64) (*model.CheckIn, error) {
+	c := &model.CheckIn{}
+	err := sqlf.From("check_ins").
+		Select(checkInColumns).
+		To(&c.RegistrationID, &c.EventID, &c.CheckedInBy, &c.DeviceID, &c.CheckedInAt, &c.CreatedAt).
+		Where("registration_id = ?", registrationId).
+		QueryRowAndClose(ctx, r.db)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: registration is not checked in", ErrRegistrationNotFound)
+	} else if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// GetAttendee returns the registration with the name of the user and the time of check-in.
+func (r *CheckInRepository) GetAttendee(ctx context.Context, registrationId int64) (*model.Attendee, error) {
+	a := &model.Attendee{}
+	err := selectAttendee(a).
+		Where("r.registration_id = ?", registrationId).
+		QueryRowAndClose(ctx, r.db)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: registration with provided id does not exist", ErrRegistrationNotFound)
+	} else if err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// ListAttendees returns users registered for the event, or for the occurrence if occurrenceId is set.
+// Banned and erased users are skipped.
+func (r *CheckInRepository) ListAttendees(ctx context.Context, eventId int64, occurrenceId *int64) ([]model.Attendee, error) {
+	attendees := make([]model.Attendee, 0)
+	a := model.Attendee{}
+	query := selectAttendee(&a).
+		Where("r.event_id = ?", eventId).
+		Where("u.banned_at IS NULL").
+		Where("u.erased_at IS NULL").
+		OrderBy("u.last_name, u.first_name, r.registration_id")
+	if occurrenceId != nil {
+		query = query.Where("r.occurrence_id = ?", *occurrenceId)
+	}
+	err := query.QueryAndClose(ctx, r.db, func(rows *sql.Rows) {
+		attendees = append(attendees, a)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return attendees, nil
+}
+
+func selectAttendee(a *model.Attendee) *sqlf.Stmt {
+	return sqlf.From("event_registrations r").
+		Join("users u", "u.user_id = r.user_id").
+		LeftJoin("check_ins c", "c.registration_id = r.registration_id").
+		Select("r.registration_id, r.event_id, r.occurrence_id, r.user_id, u.first_name, u.last_name, c.checked_in_at").
+		To(&a.RegistrationID, &a.EventID, &a.OccurrenceID, &a.UserID, &a.FirstName, &a.LastName, &a.CheckedInAt)
+}
+
+func returningCheckIn(query *sqlf.Stmt, c *model.CheckIn) *sqlf.Stmt {
+	return query.
+		Returning(checkInColumns).
+		To(&c.RegistrationID, &c.EventID, &c.CheckedInBy, &c.DeviceID, &c.CheckedInAt, &c.CreatedAt)
+}
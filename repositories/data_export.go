@@ -0,0 +1,131 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/burenotti/rtu-it-lab-recruit/model"
+	"github.com/leporo/sqlf"
+	"time"
+)
+
+var (
+	ErrDataExportNotFound = errors.New("data export not found")
+)
+
+type DataExportRepository struct {
+	db DatabaseWrapper
+}
+
+func NewDataExportRepository(db DatabaseWrapper) *DataExportRepository {
+	return &DataExportRepository{db: db}
+}
+
+func (r *DataExportRepository) Create(ctx context.Context, userId int64) (*model.DataExport, error) {
+	e := &model.DataExport{}
+	err := sqlf.InsertInto("data_exports").
+		Set("user_id", userId).
+		Set("status", model.DataExportPending).
+		Returning("export_id, user_id, status, created_at").
+		To(&e.ExportID, &e.UserID, &e.Status, &e.CreatedAt).
+		QueryRowAndClose(ctx, r.db)
+
+	if err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+func (r *DataExportRepository) GetById(ctx context.Context, exportId int64) (*model.DataExport, error) {
+	e := &model.DataExport{}
+	err := r.selectExport(e).
+		Where("export_id = ?", exportId).
+		QueryRowAndClose(ctx, r.db)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: export with provided id does not exist", ErrDataExportNotFound)
+	} else if err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// GetPending returns not yet processed export of the user.
+func (r *DataExportRepository) GetPending(ctx context.Context, userId int64) (*model.DataExport, error) {
+	e := &model.DataExport{}
+	err := r.selectExport(e).
+		Where("user_id = ?", userId).
+		Where("status = ?", model.DataExportPending).
+		OrderBy("created_at").
+		Limit(1).
+		QueryRowAndClose(ctx, r.db)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrDataExportNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// ClaimPending locks the oldest pending export, so other replicas will skip it.
+// Must be called inside a transaction.
+func (r *DataExportRepository) ClaimPending(ctx context.Context) (*model.DataExport, error) {
+	e := &model.DataExport{}
+	err := r.selectExport(e).
+		Where("status = ?", model.DataExportPending).
+		OrderBy("created_at").
+		Limit(1).
+		Clause("FOR UPDATE SKIP LOCKED").
+		QueryRowAndClose(ctx, r.db)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrDataExportNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+func (r *DataExportRepository) Complete(ctx context.Context, exportId int64, archive []byte) error {
+	_, err := sqlf.Update("data_exports").
+		Set("status", model.DataExportReady).
+		Set("archive", archive).
+		Set("finished_at", time.Now().UTC()).
+		Where("export_id = ?", exportId).
+		ExecAndClose(ctx, r.db)
+	return err
+}
+
+// Fail marks the export failed unless it is already processed, it may be called without the claim lock.
+func (r *DataExportRepository) Fail(ctx context.Context, exportId int64, reason string) error {
+	_, err := sqlf.Update("data_exports").
+		Set("status", model.DataExportFailed).
+		Set("error", reason).
+		Set("finished_at", time.Now().UTC()).
+		Where("export_id = ?", exportId).
+		Where("status = ?", model.DataExportPending).
+		ExecAndClose(ctx, r.db)
+	return err
+}
+
+func (r *DataExportRepository) GetArchive(ctx context.Context, exportId int64) ([]byte, error) {
+	var archive []byte
+	err := sqlf.From("data_exports").
+		Select("archive").To(&archive).
+		Where("export_id = ?", exportId).
+		Where("status = ?", model.DataExportReady).
+		QueryRowAndClose(ctx, r.db)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: export is not ready", ErrDataExportNotFound)
+	}
+	return archive, err
+}
+
+func (r *DataExportRepository) selectExport(e *model.DataExport) *sqlf.Stmt {
+	return sqlf.From("data_exports").
+		Select("export_id, user_id, status, error, created_at, finished_at").
+		To(&e.ExportID, &e.UserID, &e.Status, &e.Error, &e.CreatedAt, &e.FinishedAt)
+}
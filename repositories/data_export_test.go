@@ -0,0 +1,78 @@
+package repositories
+
+import (
+	"context"
+	"github.com/burenotti/rtu-it-lab-recruit/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type DataExportRepositoryTestSuite struct {
+	DBTestSuite
+}
+
+func TestDataExportRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, &DataExportRepositoryTestSuite{
+		*DBTestSuiteFromEnv(),
+	})
+}
+
+func (s *DataExportRepositoryTestSuite) TestExportLifecycle() {
+	ctx := context.Background()
+	db := NewDatabase(s.db)
+	repo := NewDataExportRepository(db)
+	user := CreateRandomUser(ctx, db, s.T())
+
+	export, err := repo.Create(ctx, user.UserID)
+	require.NoError(s.T(), err, "should create export without errors")
+	assert.Equal(s.T(), model.DataExportPending, export.Status)
+
+	pending, err := repo.GetPending(ctx, user.UserID)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), export.ExportID, pending.ExportID)
+
+	_, err = repo.GetArchive(ctx, export.ExportID)
+	assert.ErrorIs(s.T(), err, ErrDataExportNotFound, "archive of pending export should not be available")
+
+	err = db.Atomic(ctx, func(ctx context.Context) error {
+		claimed, err := repo.ClaimPending(ctx)
+		if err != nil {
+			return err
+		}
+		return repo.Complete(ctx, claimed.ExportID, []byte("archive"))
+	})
+	require.NoError(s.T(), err, "should claim and complete export")
+
+	archive, err := repo.GetArchive(ctx, export.ExportID)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), []byte("archive"), archive)
+
+	_, err = repo.GetPending(ctx, user.UserID)
+	assert.ErrorIs(s.T(), err, ErrDataExportNotFound, "completed export should not be pending")
+}
+
+func (s *DataExportRepositoryTestSuite) TestFail() {
+	ctx := context.Background()
+	db := NewDatabase(s.db)
+	repo := NewDataExportRepository(db)
+	user := CreateRandomUser(ctx, db, s.T())
+
+	failed, err := repo.Create(ctx, user.UserID)
+	require.NoError(s.T(), err)
+	require.NoError(s.T(), repo.Fail(ctx, failed.ExportID, "connection reset"))
+	export, err := repo.GetById(ctx, failed.ExportID)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), model.DataExportFailed, export.Status)
+	_, err = repo.GetPending(ctx, user.UserID)
+	assert.ErrorIs(s.T(), err, ErrDataExportNotFound, "failed export should not be claimed again")
+
+	completed, err := repo.Create(ctx, user.UserID)
+	require.NoError(s.T(), err)
+	require.NoError(s.T(), repo.Complete(ctx, completed.ExportID, []byte("archive")))
+	require.NoError(s.T(), repo.Fail(ctx, completed.ExportID, "connection reset"))
+	export, err = repo.GetById(ctx, completed.ExportID)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), model.DataExportReady, export.Status, "completed export should not be failed afterwards")
+}
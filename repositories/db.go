@@ -21,6 +21,10 @@ func (e *AtomicError) Error() string {
 	return fmt.Sprintf("transaction error: %v\n inner error: %v", e.TransactionError, e.InnerError)
 }
 
+func (e *AtomicError) Unwrap() error {
+	return e.InnerError
+}
+
 type AtomicFunc func(ctx context.Context) error
 
 type DbContext interface {
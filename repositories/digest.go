@@ -0,0 +1,95 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/burenotti/rtu-it-lab-recruit/model"
+	"github.com/leporo/sqlf"
+	"time"
+)
+
+var (
+	ErrDigestSettingsNotFound = errors.New("digest settings not found")
+)
+
+type DigestRepository struct {
+	db DatabaseWrapper
+}
+
+func NewDigestRepository(db DatabaseWrapper) *DigestRepository {
+	return &DigestRepository{db: db}
+}
+
+func (r *DigestRepository) GetDigestSettings(ctx context.Context, userId int64) (*model.DigestSettings, error) {
+	s := &model.DigestSettings{}
+	err := selectDigestSettings(s).
+		Where("user_id = ?", userId).
+		QueryRowAndClose(ctx, r.db)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrDigestSettingsNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// SaveDigestSettings creates or replaces digest settings of the user. Time of the last digest is kept.
+func (r *DigestRepository) SaveDigestSettings(ctx context.Context, settings *model.DigestSettings) (*model.DigestSettings, error) {
+	s := &model.DigestSettings{}
+	err := sqlf.InsertInto("digest_settings").
+		Set("user_id", settings.UserID).
+		Set("enabled", settings.Enabled).
+		Set("weekday", settings.Weekday).
+		Set("send_time", settings.Time).
+		Set("timezone", settings.Timezone).
+		Set("next_send_at", settings.NextSendAt).
+		Clause(`ON CONFLICT (user_id) DO UPDATE SET enabled = EXCLUDED.enabled, weekday = EXCLUDED.weekday,
+			send_time = EXCLUDED.send_time, timezone = EXCLUDED.timezone, next_send_at = EXCLUDED.next_send_at`).
+		Returning("user_id, enabled, weekday, to_char(send_time, 'HH24:MI'), timezone, next_send_at, last_sent_at").
+		To(&s.UserID, &s.Enabled, &s.Weekday, &s.Time, &s.Timezone, &s.NextSendAt, &s.LastSentAt).
+		QueryRowAndClose(ctx, r.db)
+	if getViolatedConstraint(err) == "digest_settings_user_id_fkey" {
+		return nil, ErrUserNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// ClaimDueDigest locks settings of a user whose digest is due. Locked settings are skipped by other replicas,
+// so the digest is sent once per period. Banned and erased users are skipped. It must be called in transaction.
+func (r *DigestRepository) ClaimDueDigest(ctx context.Context) (*model.DigestSettings, error) {
+	s := &model.DigestSettings{}
+	err := selectDigestSettings(s).
+		Where("enabled").
+		Where("next_send_at <= ?", time.Now().UTC()).
+		Where("user_id IN (SELECT user_id FROM users WHERE banned_at IS NULL AND erased_at IS NULL)").
+		OrderBy("next_send_at").
+		Limit(1).
+		Clause("FOR UPDATE SKIP LOCKED").
+		QueryRowAndClose(ctx, r.db)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrDigestSettingsNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// MarkDigestSent moves the digest of the user to the next period.
+func (r *DigestRepository) MarkDigestSent(ctx context.Context, userId int64, nextSendAt time.Time) error {
+	_, err := sqlf.Update("digest_settings").
+		Set("last_sent_at", time.Now().UTC()).
+		Set("next_send_at", nextSendAt).
+		Where("user_id = ?", userId).
+		ExecAndClose(ctx, r.db)
+	return err
+}
+
+func selectDigestSettings(s *model.DigestSettings) *sqlf.Stmt {
+	return sqlf.From("digest_settings").
+		Select("user_id, enabled, weekday").To(&s.UserID, &s.Enabled, &s.Weekday).
+		Select("to_char(send_time, 'HH24:MI')").To(&s.Time).
+		Select("timezone, next_send_at, last_sent_at").To(&s.Timezone, &s.NextSendAt, &s.LastSentAt)
+}
@@ -7,29 +7,44 @@ import (
 	"github.com/burenotti/rtu-it-lab-recruit/model"
 	"github.com/jmoiron/sqlx"
 	"github.com/leporo/sqlf"
+	"time"
 )
 
 const (
-	EventsOrgIdFkeyName     = "events_organization_id_fkey"
-	EventsCreatorIdFkeyName = "events_creator_id_fkey"
-	EventsPkeyName          = "events_pkey"
+	EventsOrgIdFkeyName         = "events_organization_id_fkey"
+	EventsCreatorIdFkeyName     = "events_creator_id_fkey"
+	EventsPkeyName              = "events_pkey"
+	EventsVenueIdFkeyName       = "events_venue_id_fkey"
+	EventsCategoryIdFkeyName    = "events_category_id_fkey"
+	EventsExternalUIDUniqueName = "unique_events_external_uid"
 )
 
 var (
 	ErrEventNotFount = errors.New("event does not exist")
+	// ErrExternalUIDTaken is returned when the organization already has an event with the external UID.
+	ErrExternalUIDTaken = errors.New("event with the external uid already exists")
 )
 
 var EventUpdatesValidator = NewUpdatesValidator([]string{
 	"name", "description", "begins_at", "ends_at",
 	"registration_needed", "registration_begin", "registration_end",
+	"venue_id", "online_url", "category_id", "recurrence_rule", "timezone",
 })
 
 type EventRepository struct {
 	db DatabaseWrapper
 }
 
+func NewEventRepository(db DatabaseWrapper) *EventRepository {
+	return &EventRepository{db: db}
+}
+
 func (r *EventRepository) Create(ctx context.Context, create *model.EventCreate) (*model.Event, error) {
 	e := &model.Event{}
+	timezone := create.Timezone
+	if timezone == "" {
+		timezone = "UTC"
+	}
 	err := sqlf.InsertInto("events").
 		Set("organization_id", create.OrganizationID).
 		Set("creator_id", create.CreatorID).
@@ -40,18 +55,34 @@ func (r *EventRepository) Create(ctx context.Context, create *model.EventCreate)
 		Set("registration_end", create.RegistrationEnd).
 		Set("begins_at", create.BeginsAt).
 		Set("ends_at", create.EndsAt).
+		Set("venue_id", create.VenueID).
+		Set("online_url", create.OnlineURL).
+		Set("category_id", create.CategoryID).
+		Set("recurrence_rule", create.RecurrenceRule).
+		Set("timezone", timezone).
+		Set("external_uid", create.ExternalUID).
 		Returning("event_id").To(&e.EventID).
 		Returning("organization_id, creator_id, name, description").
 		To(&e.OrganizationID, &e.CreatorID, &e.Name, &e.Description).
 		Returning("registration_needed, registration_begin, registration_end").
 		To(&e.RegistrationNeeded, &e.RegistrationBegin, &e.RegistrationEnd).
 		Returning("begins_at, ends_at").To(&e.BeginsAt, &e.EndsAt).
+		Returning("venue_id, online_url, category_id").To(&e.VenueID, &e.OnlineURL, &e.CategoryID).
+		Returning("recurrence_rule, timezone").To(&e.RecurrenceRule, &e.Timezone).
+		Returning("sequence, created_at, updated_at").To(&e.Sequence, &e.CreatedAt, &e.UpdatedAt).
+		Returning("external_uid").To(&e.ExternalUID).
 		QueryRowAndClose(ctx, r.db)
 
 	if getViolatedConstraint(err) == EventsOrgIdFkeyName {
 		return nil, ErrOrganizationNotFound
 	} else if getViolatedConstraint(err) == EventsCreatorIdFkeyName {
 		return nil, ErrUserNotFound
+	} else if getViolatedConstraint(err) == EventsVenueIdFkeyName {
+		return nil, ErrVenueNotFound
+	} else if getViolatedConstraint(err) == EventsCategoryIdFkeyName {
+		return nil, ErrCategoryNotFound
+	} else if getViolatedConstraint(err) == EventsExternalUIDUniqueName {
+		return nil, ErrExternalUIDTaken
 	} else if err != nil {
 		return nil, err
 	}
@@ -70,9 +101,14 @@ func (r *EventRepository) GetById(ctx context.Context, eventId int64) (*model.Ev
 		Select("registration_needed, registration_begin, registration_end").
 		To(&e.RegistrationNeeded, &e.RegistrationBegin, &e.RegistrationEnd).
 		Select("begins_at, ends_at").To(&e.BeginsAt, &e.EndsAt).
+		Select("created_at, published_at, hidden_at").To(&e.CreatedAt, &e.PublishedAt, &e.HiddenAt).
+		Select("cancelled_at, cancel_reason").To(&e.CancelledAt, &e.CancelReason).
+		Select("venue_id, online_url, category_id").To(&e.VenueID, &e.OnlineURL, &e.CategoryID).
+		Select("recurrence_rule, timezone").To(&e.RecurrenceRule, &e.Timezone).
+		Select("sequence, updated_at, external_uid").To(&e.Sequence, &e.UpdatedAt, &e.ExternalUID).
 		QueryRowAndClose(ctx, r.db)
 
-	if getViolatedConstraint(err) == EventsPkeyName {
+	if errors.Is(err, sql.ErrNoRows) {
 		return nil, ErrEventNotFount
 	} else if err != nil {
 		return nil, err
@@ -89,8 +125,13 @@ func (r *EventRepository) SelectBy(ctx context.Context, filter EventFilter) ([]m
 	builder := sqlf.From("events").
 		Select("event_id, organization_id, creator_id, name, description").
 		Select("registration_needed, registration_begin, registration_end").
-		Select("begins_at, ends_at").
-		Where(where, args...)
+		Select("begins_at, ends_at, created_at, published_at, hidden_at, cancelled_at, cancel_reason").
+		Select("venue_id, online_url, category_id, recurrence_rule, timezone").
+		Select("sequence, updated_at, external_uid")
+
+	if where != "" {
+		builder = builder.Where(where, args...)
+	}
 
 	if orderBy != "" {
 		builder = builder.OrderBy(orderBy)
@@ -100,20 +141,12 @@ func (r *EventRepository) SelectBy(ctx context.Context, filter EventFilter) ([]m
 		builder = builder.Limit(limit)
 	}
 	var events []model.Event
-	var scanErr error
-	err := builder.QueryAndClose(ctx, r.db, func(rows *sql.Rows) {
-		e := model.Event{}
-		scanErr = sqlx.StructScan(rows, &e)
-		events = append(events, e)
-	})
-	if err != nil {
-		return nil, err
-	}
-	if errors.Is(scanErr, sql.ErrNoRows) {
+	err := sqlx.SelectContext(ctx, r.db, &events, builder.String(), builder.Args()...)
+	builder.Close()
+	if errors.Is(err, sql.ErrNoRows) {
 		return nil, nil
-	} else {
-		return events, scanErr
 	}
+	return events, err
 }
 
 func (r *EventRepository) UpdateEvent(ctx context.Context, eventId int64, updates map[string]interface{}) (*model.Event, error) {
@@ -128,20 +161,34 @@ func (r *EventRepository) UpdateEvent(ctx context.Context, eventId int64, update
 		To(&e.OrganizationID, &e.CreatorID, &e.Name, &e.Description).
 		Returning("registration_needed, registration_begin, registration_end").
 		To(&e.RegistrationNeeded, &e.RegistrationBegin, &e.RegistrationEnd).
-		Returning("begins_at, ends_at").To(&e.BeginsAt, &e.EndsAt)
+		Returning("begins_at, ends_at").To(&e.BeginsAt, &e.EndsAt).
+		Returning("created_at, published_at, hidden_at").To(&e.CreatedAt, &e.PublishedAt, &e.HiddenAt).
+		Returning("cancelled_at, cancel_reason").To(&e.CancelledAt, &e.CancelReason).
+		Returning("venue_id, online_url, category_id").To(&e.VenueID, &e.OnlineURL, &e.CategoryID).
+		Returning("recurrence_rule, timezone").To(&e.RecurrenceRule, &e.Timezone).
+		Returning("sequence, updated_at, external_uid").To(&e.Sequence, &e.UpdatedAt, &e.ExternalUID)
 
 	for field, val := range updates {
 		builder = builder.Set(field, val)
 	}
+	builder = touchEvent(builder)
 	err := builder.QueryRow(ctx, r.db)
-	if getViolatedConstraint(err) == EventsPkeyName {
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrEventNotFount
+	} else if getViolatedConstraint(err) == EventsPkeyName {
 		return nil, ErrEventNotFount
 	} else if getViolatedConstraint(err) == EventsOrgIdFkeyName {
 		return nil, ErrOrganizationNotFound
 	} else if getViolatedConstraint(err) == EventsCreatorIdFkeyName {
 		return nil, ErrUserNotFound
+	} else if getViolatedConstraint(err) == EventsVenueIdFkeyName {
+		return nil, ErrVenueNotFound
+	} else if getViolatedConstraint(err) == EventsCategoryIdFkeyName {
+		return nil, ErrCategoryNotFound
+	} else if err != nil {
+		return nil, err
 	}
-	return &e, err
+	return &e, nil
 }
 
 func (r *EventRepository) DeleteEvent(ctx context.Context, eventId int64) error {
@@ -162,3 +209,89 @@ func (r *EventRepository) DeleteEvent(ctx context.Context, eventId int64) error
 	}
 	return nil
 }
+
+// Publish makes the event visible to everyone. Already published events are not changed.
+func (r *EventRepository) Publish(ctx context.Context, eventId int64) error {
+	res, err := sqlf.Update("events").
+		Set("published_at", time.Now().UTC()).
+		Where("event_id = ?", eventId).
+		Where("published_at IS NULL").
+		ExecAndClose(ctx, r.db)
+
+	if err != nil {
+		return err
+	}
+	count, _ := res.RowsAffected()
+	if count == 0 {
+		return ErrEventNotFount
+	}
+	return nil
+}
+
+// Cancel marks the event as cancelled. Cancelled events are kept, so registrants could see what happened.
+func (r *EventRepository) Cancel(ctx context.Context, eventId int64, reason string) error {
+	res, err := touchEvent(sqlf.Update("events")).
+		Set("cancelled_at", time.Now().UTC()).
+		Set("cancel_reason", reason).
+		Where("event_id = ?", eventId).
+		Where("cancelled_at IS NULL").
+		ExecAndClose(ctx, r.db)
+
+	if err != nil {
+		return err
+	}
+	count, _ := res.RowsAffected()
+	if count == 0 {
+		return ErrEventNotFount
+	}
+	return nil
+}
+
+// Touch increments SEQUENCE of the event. It is used when occurrences of the event are changed.
+func (r *EventRepository) Touch(ctx context.Context, eventId int64) error {
+	res, err := touchEvent(sqlf.Update("events")).
+		Where("event_id = ?", eventId).
+		ExecAndClose(ctx, r.db)
+
+	if err != nil {
+		return err
+	}
+	count, _ := res.RowsAffected()
+	if count == 0 {
+		return ErrEventNotFount
+	}
+	return nil
+}
+
+// Hide hides event from everyone except organization members. It is used by platform administrators.
+func (r *EventRepository) Hide(ctx context.Context, eventId int64, reason string) error {
+	return r.setHidden(ctx, eventId, time.Now().UTC(), &reason)
+}
+
+func (r *EventRepository) Unhide(ctx context.Context, eventId int64) error {
+	return r.setHidden(ctx, eventId, nil, nil)
+}
+
+func (r *EventRepository) setHidden(ctx context.Context, eventId int64, hiddenAt interface{}, reason *string) error {
+	res, err := sqlf.Update("events").
+		Set("hidden_at", hiddenAt).
+		Set("hidden_reason", reason).
+		Where("event_id = ?", eventId).
+		ExecAndClose(ctx, r.db)
+
+	if err != nil {
+		return err
+	}
+	count, _ := res.RowsAffected()
+	if count == 0 {
+		return ErrEventNotFount
+	}
+	return nil
+}
+
+// touchEvent marks the event as changed for calendar apps.
+func touchEvent(query *sqlf.Stmt) *sqlf.Stmt {
+	return query.
+		SetExpr("sequence", "sequence + 1").
+		Set("updated_at", time.Now().UTC())
+}
@@ -2,6 +2,8 @@ package repositories
 
 import (
 	"fmt"
+	"github.com/burenotti/rtu-it-lab-recruit/model"
+	"math"
 	"strings"
 	"time"
 )
@@ -33,9 +35,11 @@ func (f *EventJoinerFilter) whereClause() (string, []interface{}) {
 }
 
 func (f *EventJoinerFilter) orderByClause() string {
-	orderBy := make([]string, len(f.filters))
-	for i, filter := range f.filters {
-		orderBy[i] = filter.orderByClause()
+	orderBy := make([]string, 0, len(f.filters))
+	for _, filter := range f.filters {
+		if clause := filter.orderByClause(); clause != "" {
+			orderBy = append(orderBy, clause)
+		}
 	}
 	return strings.Join(orderBy, ", ")
 }
@@ -100,6 +104,45 @@ func NewEventSinceFilter(since time.Time) *EventSinceFilter {
 	}
 }
 
+type EventCreatorFilter struct {
+	BaseWhereFilter
+}
+
+func NewEventCreatorFilter(creatorId int64) *EventCreatorFilter {
+	return &EventCreatorFilter{
+		BaseWhereFilter{
+			query: "(creator_id = ?)",
+			args:  []interface{}{creatorId},
+		},
+	}
+}
+
+type EventIdFilter struct {
+	BaseWhereFilter
+}
+
+func NewEventIdFilter(eventId int64) *EventIdFilter {
+	return &EventIdFilter{
+		BaseWhereFilter{
+			query: "(event_id = ?)",
+			args:  []interface{}{eventId},
+		},
+	}
+}
+
+type EventVisibleFilter struct {
+	BaseWhereFilter
+}
+
+// NewEventVisibleFilter excludes events hidden by platform administrators.
+func NewEventVisibleFilter() *EventVisibleFilter {
+	return &EventVisibleFilter{
+		BaseWhereFilter{
+			query: "(hidden_at IS NULL)",
+		},
+	}
+}
+
 type NameLikeFilter struct {
 	BaseWhereFilter
 }
@@ -130,6 +173,321 @@ func (f *LimitFilter) orderByClause() string {
 	return f.child.orderByClause()
 }
 
-func (f *LimitFilter) limit() int64 {
-	return f.limit_
+func (f *LimitFilter) limit() int {
+	return int(f.limit_)
+}
+
+type EventPublishedFilter struct {
+	BaseWhereFilter
+}
+
+// NewEventPublishedFilter selects events visible to everyone: published, not hidden and not cancelled.
+func NewEventPublishedFilter() *EventPublishedFilter {
+	return &EventPublishedFilter{
+		BaseWhereFilter{
+			query: "(published_at IS NOT NULL AND hidden_at IS NULL AND cancelled_at IS NULL)",
+		},
+	}
+}
+
+type EventListedFilter struct {
+	BaseWhereFilter
+}
+
+// NewEventListedFilter selects published events which are not hidden, including cancelled ones,
+// so calendars learn about cancellations.
+func NewEventListedFilter() *EventListedFilter {
+	return &EventListedFilter{
+		BaseWhereFilter{
+			query: "(published_at IS NOT NULL AND hidden_at IS NULL)",
+		},
+	}
+}
+
+type EventRegisteredFilter struct {
+	BaseWhereFilter
+}
+
+// NewEventRegisteredFilter selects events the user has registered for or for any of their occurrences.
+func NewEventRegisteredFilter(userId int64) *EventRegisteredFilter {
+	return &EventRegisteredFilter{
+		BaseWhereFilter{
+			query: "event_id IN (SELECT event_id FROM event_registrations WHERE user_id = ?)",
+			args:  []interface{}{userId},
+		},
+	}
+}
+
+type EventExternalUIDFilter struct {
+	BaseWhereFilter
+}
+
+// NewEventExternalUIDFilter selects imported events of the organization with the external UIDs.
+func NewEventExternalUIDFilter(orgId int64, uids []string) *EventExternalUIDFilter {
+	return &EventExternalUIDFilter{
+		BaseWhereFilter{
+			query: "(organization_id = ? AND external_uid = ANY(?))",
+			args:  []interface{}{orgId, uids},
+		},
+	}
+}
+
+type EventBeginsBetweenFilter struct {
+	BaseWhereFilter
+}
+
+// NewEventBeginsBetweenFilter selects events beginning in [from, to) ordered by the beginning.
+func NewEventBeginsBetweenFilter(from, to time.Time) *EventBeginsBetweenFilter {
+	return &EventBeginsBetweenFilter{
+		BaseWhereFilter{
+			query: "(begins_at >= ? AND begins_at < ?)",
+			args:  []interface{}{from, to},
+		},
+	}
+}
+
+func (f *EventBeginsBetweenFilter) orderByClause() string {
+	return "begins_at"
+}
+
+type EventFollowedFilter struct {
+	BaseWhereFilter
+}
+
+// NewEventFollowedFilter selects events of organizations followed by the user.
+func NewEventFollowedFilter(userId int64) *EventFollowedFilter {
+	return &EventFollowedFilter{
+		BaseWhereFilter{
+			query: "(organization_id IN (SELECT organization_id FROM organization_follows WHERE user_id = ?))",
+			args:  []interface{}{userId},
+		},
+	}
+}
+
+type EventTextFilter struct {
+	BaseWhereFilter
+}
+
+// NewEventTextFilter selects events containing the text in the name or the description, case-insensitive.
+func NewEventTextFilter(text string) *EventTextFilter {
+	pattern := "%" + likeEscaper.Replace(text) + "%"
+	return &EventTextFilter{
+		BaseWhereFilter{
+			query: "(name ILIKE ? OR description ILIKE ?)",
+			args:  []interface{}{pattern, pattern},
+		},
+	}
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+type EventOrganizationFilter struct {
+	BaseWhereFilter
+}
+
+func NewEventOrganizationFilter(orgId int64) *EventOrganizationFilter {
+	return &EventOrganizationFilter{
+		BaseWhereFilter{
+			query: "(organization_id = ?)",
+			args:  []interface{}{orgId},
+		},
+	}
+}
+
+type EventBeginsAfterFilter struct {
+	BaseWhereFilter
+}
+
+func NewEventBeginsAfterFilter(after time.Time) *EventBeginsAfterFilter {
+	return &EventBeginsAfterFilter{
+		BaseWhereFilter{
+			query: "(begins_at >= ?)",
+			args:  []interface{}{after},
+		},
+	}
+}
+
+type EventBeginsBeforeFilter struct {
+	BaseWhereFilter
+}
+
+func NewEventBeginsBeforeFilter(before time.Time) *EventBeginsBeforeFilter {
+	return &EventBeginsBeforeFilter{
+		BaseWhereFilter{
+			query: "(begins_at < ?)",
+			args:  []interface{}{before},
+		},
+	}
+}
+
+type EventOccursBetweenFilter struct {
+	BaseWhereFilter
+	from time.Time
+}
+
+// NewEventOccursBetweenFilter selects single events beginning in [from, to) and recurring events
+// having occurrences there. Events are ordered by their first occurrence in the window.
+func NewEventOccursBetweenFilter(from, to time.Time) *EventOccursBetweenFilter {
+	return &EventOccursBetweenFilter{
+		BaseWhereFilter: BaseWhereFilter{
+			query: "((recurrence_rule IS NULL AND begins_at >= ? AND begins_at < ?) OR " +
+				"(recurrence_rule IS NOT NULL AND event_id IN (SELECT event_id FROM event_occurrences " +
+				"WHERE cancelled_at IS NULL AND begins_at >= ? AND begins_at < ?)))",
+			args: []interface{}{from, to, from, to},
+		},
+		from: from,
+	}
+}
+
+func (f *EventOccursBetweenFilter) orderByClause() string {
+	// ORDER BY clauses have no arguments. The timestamp is formatted from time.Time, so it is safe to inline.
+	return fmt.Sprintf("COALESCE((SELECT min(o.begins_at) FROM event_occurrences o "+
+		"WHERE o.event_id = events.event_id AND o.cancelled_at IS NULL AND o.begins_at >= '%s'), begins_at)",
+		f.from.UTC().Format(time.RFC3339Nano))
+}
+
+type EventRecurringFilter struct {
+	BaseWhereFilter
+}
+
+// NewEventRecurringFilter selects recurring events which are not cancelled.
+func NewEventRecurringFilter() *EventRecurringFilter {
+	return &EventRecurringFilter{
+		BaseWhereFilter{
+			query: "(recurrence_rule IS NOT NULL AND cancelled_at IS NULL)",
+		},
+	}
+}
+
+// earthRadius is the mean radius of the Earth in meters.
+const earthRadius = 6371008.8
+
+// venueDistanceSQL is the haversine distance in meters from the venue to the point. Its args are the latitude
+// of the point twice and its longitude. It runs on vanilla Postgres without earthdistance or PostGIS.
+const venueDistanceSQL = "2 * %f * asin(least(1, sqrt(" +
+	"power(sin(radians(latitude - ?) / 2), 2) + " +
+	"cos(radians(?)) * cos(radians(latitude)) * power(sin(radians(longitude - ?) / 2), 2))))"
+
+type EventNearFilter struct {
+	BaseWhereFilter
+}
+
+// NewEventNearFilter selects events at venues within radius meters from the point.
+// Venues are narrowed down by the bounding box of the circle first, so the index on venue location is used.
+func NewEventNearFilter(latitude, longitude, radius float64) *EventNearFilter {
+	latDelta := radius / earthRadius * 180 / math.Pi
+	conditions := []string{"latitude BETWEEN ? AND ?"}
+	args := []interface{}{latitude - latDelta, latitude + latDelta}
+
+	// Longitude degrees shrink towards the poles. Near the poles and across the antimeridian
+	// the box is not a range of longitudes, so only latitude narrows venues down there.
+	if math.Abs(latitude)+latDelta < 89 {
+		lonDelta := latDelta / math.Cos(latitude*math.Pi/180)
+		if longitude-lonDelta >= -180 && longitude+lonDelta <= 180 {
+			conditions = append(conditions, "longitude BETWEEN ? AND ?")
+			args = append(args, longitude-lonDelta, longitude+lonDelta)
+		}
+	}
+	conditions = append(conditions, fmt.Sprintf(venueDistanceSQL, earthRadius)+" <= ?")
+	args = append(args, latitude, latitude, longitude, radius)
+
+	return &EventNearFilter{
+		BaseWhereFilter{
+			query: fmt.Sprintf("(venue_id IN (SELECT venue_id FROM venues WHERE %s))", strings.Join(conditions, " AND ")),
+			args:  args,
+		},
+	}
+}
+
+type EventInBoundsFilter struct {
+	BaseWhereFilter
+}
+
+// NewEventInBoundsFilter selects events at venues within the bounding box.
+// If minLongitude is greater than maxLongitude, the box crosses the antimeridian.
+func NewEventInBoundsFilter(minLatitude, minLongitude, maxLatitude, maxLongitude float64) *EventInBoundsFilter {
+	longitude := "longitude BETWEEN ? AND ?"
+	if minLongitude > maxLongitude {
+		longitude = "(longitude >= ? OR longitude <= ?)"
+	}
+	return &EventInBoundsFilter{
+		BaseWhereFilter{
+			query: "(venue_id IN (SELECT venue_id FROM venues WHERE latitude BETWEEN ? AND ? AND " + longitude + "))",
+			args:  []interface{}{minLatitude, maxLatitude, minLongitude, maxLongitude},
+		},
+	}
+}
+
+type EventCategoryFilter struct {
+	BaseWhereFilter
+}
+
+// NewEventCategoryFilter selects events of the category and all its subcategories.
+func NewEventCategoryFilter(categoryId int64) *EventCategoryFilter {
+	return &EventCategoryFilter{
+		BaseWhereFilter{
+			query: "(category_id IN (WITH RECURSIVE tree AS (" +
+				"SELECT category_id FROM categories WHERE category_id = ? " +
+				"UNION ALL " +
+				"SELECT c.category_id FROM categories c JOIN tree ON c.parent_id = tree.category_id" +
+				") SELECT category_id FROM tree))",
+			args: []interface{}{categoryId},
+		},
+	}
+}
+
+type EventTagFilter struct {
+	BaseWhereFilter
+}
+
+// NewEventTagFilter selects events having the tag. The tag must be normalized.
+func NewEventTagFilter(tag string) *EventTagFilter {
+	return &EventTagFilter{
+		BaseWhereFilter{
+			query: "(event_id IN (SELECT event_id FROM event_tags WHERE tag = ?))",
+			args:  []interface{}{tag},
+		},
+	}
+}
+
+// NewEventSearchFilter builds the filter of the event search. Relative conditions are resolved against now.
+// The search must be validated before.
+func NewEventSearchFilter(search *model.SearchFilter, now time.Time) EventFilter {
+	switch {
+	case search.And != nil:
+		return NewEventAndFilter(newEventSearchFilters(search.And, now)...)
+	case search.Or != nil:
+		return NewEventOrFilter(newEventSearchFilters(search.Or, now)...)
+	case search.Text != nil:
+		return NewEventTextFilter(*search.Text)
+	case search.OrganizationID != nil:
+		return NewEventOrganizationFilter(*search.OrganizationID)
+	case search.CategoryID != nil:
+		return NewEventCategoryFilter(*search.CategoryID)
+	case search.Tag != nil:
+		return NewEventTagFilter(*search.Tag)
+	case search.Near != nil:
+		return NewEventNearFilter(search.Near.Latitude, search.Near.Longitude, search.Near.Radius)
+	case search.InBounds != nil:
+		b := search.InBounds
+		return NewEventInBoundsFilter(b.MinLatitude, b.MinLongitude, b.MaxLatitude, b.MaxLongitude)
+	case search.BeginsAfter != nil:
+		return NewEventBeginsAfterFilter(*search.BeginsAfter)
+	case search.BeginsBefore != nil:
+		return NewEventBeginsBeforeFilter(*search.BeginsBefore)
+	case search.BeginsWithinDays != nil:
+		return NewEventAndFilter(
+			NewEventBeginsAfterFilter(now),
+			NewEventBeginsBeforeFilter(now.AddDate(0, 0, *search.BeginsWithinDays)),
+		)
+	}
+	return &BaseWhereFilter{query: "(false)"}
+}
+
+func newEventSearchFilters(searches []model.SearchFilter, now time.Time) []EventFilter {
+	filters := make([]EventFilter, 0, len(searches))
+	for i := range searches {
+		filters = append(filters, NewEventSearchFilter(&searches[i], now))
+	}
+	return filters
 }
@@ -1,7 +1,9 @@
 package repositories
 
 import (
+	"github.com/burenotti/rtu-it-lab-recruit/model"
 	"github.com/stretchr/testify/assert"
+	"strings"
 	"testing"
 	"time"
 )
@@ -75,3 +77,129 @@ func TestLimitFilter(t *testing.T) {
 	assert.Equal(t, []interface{}{since, "John", "Doe"}, args)
 	assert.Equal(t, int64(5), withLimit.limit_)
 }
+
+func TestEventCreatorFilter(t *testing.T) {
+	f := NewEventCreatorFilter(5)
+	query, args := f.whereClause()
+	assert.Equal(t, "(creator_id = ?)", query)
+	assert.Equal(t, []interface{}{int64(5)}, args)
+	assert.Equal(t, "", f.orderByClause())
+}
+
+func TestEventJoinerFilter_SkipsEmptyOrderBy(t *testing.T) {
+	and := NewEventAndFilter(
+		NewEventCreatorFilter(5),
+		NewNameLikeFilter("Test"),
+	)
+	assert.Equal(t, "", and.orderByClause())
+}
+
+func TestEventVisibleFilter(t *testing.T) {
+	f := NewEventVisibleFilter()
+	query, args := f.whereClause()
+	assert.Equal(t, "(hidden_at IS NULL)", query)
+	assert.Empty(t, args)
+}
+
+func TestEventBeginsBetweenFilter(t *testing.T) {
+	from := time.Now()
+	to := from.Add(time.Hour)
+	and := NewEventAndFilter(
+		NewEventPublishedFilter(),
+		NewEventBeginsBetweenFilter(from, to),
+	)
+	query, args := and.whereClause()
+	assert.Equal(t, "((published_at IS NOT NULL AND hidden_at IS NULL AND cancelled_at IS NULL) AND "+
+		"(begins_at >= ? AND begins_at < ?))", query)
+	assert.Equal(t, []interface{}{from, to}, args)
+	assert.Equal(t, "begins_at", and.orderByClause())
+}
+
+func TestEventSearchFilter(t *testing.T) {
+	now := time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC)
+	text := "50%_off"
+	orgId := int64(3)
+	days := 30
+	f := NewEventSearchFilter(&model.SearchFilter{
+		And: []model.SearchFilter{
+			{Text: &text},
+			{Or: []model.SearchFilter{
+				{OrganizationID: &orgId},
+				{BeginsWithinDays: &days},
+			}},
+		},
+	}, now)
+	query, args := f.whereClause()
+	assert.Equal(t, "((name ILIKE ? OR description ILIKE ?) AND "+
+		"((organization_id = ?) OR ((begins_at >= ?) AND (begins_at < ?))))", query)
+	assert.Equal(t, []interface{}{`%50\%\_off%`, `%50\%\_off%`, orgId, now, now.AddDate(0, 0, days)}, args)
+}
+
+func TestEventSearchFilter_Location(t *testing.T) {
+	categoryId := int64(2)
+	tag := "free"
+	f := NewEventSearchFilter(&model.SearchFilter{
+		And: []model.SearchFilter{
+			{CategoryID: &categoryId},
+			{Tag: &tag},
+			{Near: &model.GeoCircle{Latitude: 55.75, Longitude: 37.61, Radius: 2000}},
+		},
+	}, time.Now())
+	query, args := f.whereClause()
+	assert.Contains(t, query, "(category_id IN (WITH RECURSIVE tree AS (")
+	assert.Contains(t, query, "(event_id IN (SELECT event_id FROM event_tags WHERE tag = ?))")
+	assert.Contains(t, query, "(venue_id IN (SELECT venue_id FROM venues WHERE latitude BETWEEN ? AND ?")
+	assert.Equal(t, strings.Count(query, "?"), len(args))
+	assert.Equal(t, categoryId, args[0])
+	assert.Equal(t, tag, args[1])
+}
+
+func TestEventNearFilter(t *testing.T) {
+	f := NewEventNearFilter(55.75, 37.61, 2000)
+	query, args := f.whereClause()
+	assert.Contains(t, query, "latitude BETWEEN ? AND ? AND longitude BETWEEN ? AND ?")
+	assert.Contains(t, query, "asin(")
+	assert.Equal(t, strings.Count(query, "?"), len(args))
+	assert.InDelta(t, 55.75-0.018, args[0], 0.001)
+	assert.InDelta(t, 37.61+0.032, args[3], 0.001, "longitude delta should grow with latitude")
+	assert.Equal(t, 2000.0, args[len(args)-1])
+
+	query, args = NewEventNearFilter(0, 179.99, 5000).whereClause()
+	assert.NotContains(t, query, "longitude BETWEEN", "box crossing the antimeridian should not filter longitudes")
+	assert.Equal(t, strings.Count(query, "?"), len(args))
+}
+
+func TestEventInBoundsFilter(t *testing.T) {
+	query, args := NewEventInBoundsFilter(55, 37, 56, 38).whereClause()
+	assert.Equal(t, "(venue_id IN (SELECT venue_id FROM venues WHERE latitude BETWEEN ? AND ? AND longitude BETWEEN ? AND ?))", query)
+	assert.Equal(t, []interface{}{55.0, 56.0, 37.0, 38.0}, args)
+
+	query, _ = NewEventInBoundsFilter(-10, 170, 10, -170).whereClause()
+	assert.Contains(t, query, "(longitude >= ? OR longitude <= ?)")
+}
+
+func TestEventCategoryFilter(t *testing.T) {
+	query, args := NewEventCategoryFilter(2).whereClause()
+	assert.True(t, strings.HasPrefix(query, "(category_id IN (WITH RECURSIVE tree AS ("))
+	assert.Contains(t, query, "JOIN tree ON c.parent_id = tree.category_id")
+	assert.Equal(t, []interface{}{int64(2)}, args)
+}
+
+func TestEventTagFilter(t *testing.T) {
+	f := NewEventAndFilter(NewEventTagFilter("free"), NewEventTagFilter("kids"))
+	query, args := f.whereClause()
+	assert.Equal(t, "((event_id IN (SELECT event_id FROM event_tags WHERE tag = ?)) AND "+
+		"(event_id IN (SELECT event_id FROM event_tags WHERE tag = ?)))", query)
+	assert.Equal(t, []interface{}{"free", "kids"}, args)
+}
+
+func TestEventOccursBetweenFilter(t *testing.T) {
+	from := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+	f := NewEventOccursBetweenFilter(from, to)
+	query, args := f.whereClause()
+	assert.Contains(t, query, "recurrence_rule IS NULL AND begins_at >= ? AND begins_at < ?")
+	assert.Contains(t, query, "FROM event_occurrences WHERE cancelled_at IS NULL")
+	assert.Equal(t, []interface{}{from, to, from, to}, args)
+	assert.Contains(t, f.orderByClause(), "o.begins_at >= '2024-01-02T03:04:05Z'")
+}
@@ -0,0 +1,183 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/burenotti/rtu-it-lab-recruit/model"
+	"github.com/leporo/sqlf"
+	"time"
+)
+
+var (
+	ErrEventImportNotFound = errors.New("event import does not exist")
+)
+
+type EventImportRepository struct {
+	db DatabaseWrapper
+}
+
+func NewEventImportRepository(db DatabaseWrapper) *EventImportRepository {
+	return &EventImportRepository{db: db}
+}
+
+const eventImportColumns = "import_id, organization_id, user_id, format, timezone, status, " +
+	"total, processed, report, error, created_at, finished_at"
+
+// eventImportRow is an import with the report not decoded yet.
+type eventImportRow struct {
+	model.EventImport
+	report []byte
+}
+
+func (row *eventImportRow) dest() []interface{} {
+	i := &row.EventImport
+	return []interface{}{
+		&i.ImportID, &i.OrganizationID, &i.UserID, &i.Format, &i.Timezone, &i.Status,
+		&i.Total, &i.Processed, &row.report, &i.Error, &i.CreatedAt, &i.FinishedAt,
+	}
+}
+
+func (row *eventImportRow) decode() (*model.EventImport, error) {
+	i := row.EventImport
+	if row.report != nil {
+		i.Report = &model.EventImportReport{}
+		if err := json.Unmarshal(row.report, i.Report); err != nil {
+			return nil, err
+		}
+	}
+	return &i, nil
+}
+
+func (r *EventImportRepository) CreateImport(ctx context.Context, i *model.EventImport, data []byte) (*model.EventImport, error) {
+	row := &eventImportRow{}
+	err := sqlf.InsertInto("event_imports").
+		Set("organization_id", i.OrganizationID).
+		Set("user_id", i.UserID).
+		Set("format", i.Format).
+		Set("timezone", i.Timezone).
+		Set("data", data).
+		Set("status", model.EventImportPending).
+		Returning(eventImportColumns).To(row.dest()...).
+		QueryRowAndClose(ctx, r.db)
+
+	if getViolatedConstraint(err) == "event_imports_organization_id_fkey" {
+		return nil, fmt.Errorf("%w: organization with provided id does not exist", ErrOrganizationNotFound)
+	} else if err != nil {
+		return nil, err
+	}
+	return row.decode()
+}
+
+func (r *EventImportRepository) GetImport(ctx context.Context, importId int64) (*model.EventImport, error) {
+	row := &eventImportRow{}
+	err := sqlf.From("event_imports").
+		Select(eventImportColumns).To(row.dest()...).
+		Where("import_id = ?", importId).
+		QueryRowAndClose(ctx, r.db)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: import with provided id does not exist", ErrEventImportNotFound)
+	} else if err != nil {
+		return nil, err
+	}
+	return row.decode()
+}
+
+// ListUserImports returns imports started by the user, newest first.
+func (r *EventImportRepository) ListUserImports(ctx context.Context, userId int64) ([]model.EventImport, error) {
+	row := &eventImportRow{}
+	imports := make([]model.EventImport, 0)
+	var decodeErr error
+	err := sqlf.From("event_imports").
+		Select(eventImportColumns).To(row.dest()...).
+		Where("user_id = ?", userId).
+		OrderBy("import_id DESC").
+		QueryAndClose(ctx, r.db, func(rows *sql.Rows) {
+			i, err := row.decode()
+			if err != nil {
+				decodeErr = err
+				return
+			}
+			imports = append(imports, *i)
+		})
+	if err != nil {
+		return nil, err
+	}
+	if decodeErr != nil {
+		return nil, decodeErr
+	}
+	return imports, nil
+}
+
+// ClaimImport marks the oldest pending import running and returns it with its file.
+// Running imports which have not progressed since staleBefore are claimed again,
+// so imports interrupted by restarts are resumed. Imports update events by UIDs, so it is safe to repeat them.
+func (r *EventImportRepository) ClaimImport(ctx context.Context, staleBefore time.Time) (*model.EventImport, []byte, error) {
+	row := &eventImportRow{}
+	var data []byte
+	err := sqlf.Update("event_imports").
+		Set("status", model.EventImportRunning).
+		Set("updated_at", time.Now().UTC()).
+		Where("import_id = (SELECT import_id FROM event_imports "+
+			"WHERE status = ? OR (status = ? AND updated_at < ?) "+
+			"ORDER BY created_at LIMIT 1 FOR UPDATE SKIP LOCKED)",
+			model.EventImportPending, model.EventImportRunning, staleBefore).
+		Returning(eventImportColumns).To(row.dest()...).
+		Returning("data").To(&data).
+		QueryRowAndClose(ctx, r.db)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil, ErrEventImportNotFound
+	} else if err != nil {
+		return nil, nil, err
+	}
+	i, err := row.decode()
+	return i, data, err
+}
+
+// UpdateImportProgress sets numbers of the rows of running import.
+func (r *EventImportRepository) UpdateImportProgress(ctx context.Context, importId int64, total, processed int) error {
+	_, err := sqlf.Update("event_imports").
+		Set("total", total).
+		Set("processed", processed).
+		Set("updated_at", time.Now().UTC()).
+		Where("import_id = ?", importId).
+		ExecAndClose(ctx, r.db)
+	return err
+}
+
+// CompleteImport saves the report and drops the file.
+func (r *EventImportRepository) CompleteImport(ctx context.Context, importId int64, report *model.EventImportReport) error {
+	data, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	_, err = sqlf.Update("event_imports").
+		Set("status", model.EventImportCompleted).
+		Set("total", report.Total).
+		Set("processed", report.Total).
+		Set("report", string(data)).
+		Set("data", []byte{}).
+		Set("updated_at", now).
+		Set("finished_at", now).
+		Where("import_id = ?", importId).
+		ExecAndClose(ctx, r.db)
+	return err
+}
+
+func (r *EventImportRepository) FailImport(ctx context.Context, importId int64, reason string) error {
+	now := time.Now().UTC()
+	_, err := sqlf.Update("event_imports").
+		Set("status", model.EventImportFailed).
+		Set("error", reason).
+		Set("data", []byte{}).
+		Set("updated_at", now).
+		Set("finished_at", now).
+		Where("import_id = ?", importId).
+		ExecAndClose(ctx, r.db)
+	return err
+}
@@ -0,0 +1,151 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/burenotti/rtu-it-lab-recruit/model"
+	"github.com/leporo/sqlf"
+	"time"
+)
+
+const (
+	FollowsPkeyName    = "organization_follows_pkey"
+	FollowsOrgFkeyName = "organization_follows_organization_id_fkey"
+)
+
+var (
+	ErrAlreadyFollowing = errors.New("user already follows organization")
+	ErrNotFollowing     = errors.New("user does not follow organization")
+	ErrFanoutNotFound   = errors.New("fanout not found")
+)
+
+type FollowRepository struct {
+	db DatabaseWrapper
+}
+
+func NewFollowRepository(db DatabaseWrapper) *FollowRepository {
+	return &FollowRepository{db: db}
+}
+
+func (r *FollowRepository) Follow(ctx context.Context, orgId, userId int64) (*model.Follow, error) {
+	f := &model.Follow{}
+	err := sqlf.InsertInto("organization_follows").
+		Set("organization_id", orgId).
+		Set("user_id", userId).
+		Returning("organization_id, user_id, created_at").
+		To(&f.OrganizationID, &f.UserID, &f.CreatedAt).
+		QueryRowAndClose(ctx, r.db)
+
+	switch getViolatedConstraint(err) {
+	case "":
+	case FollowsPkeyName:
+		return nil, fmt.Errorf("%w: organization %d is already followed", ErrAlreadyFollowing, orgId)
+	case FollowsOrgFkeyName:
+		return nil, fmt.Errorf("%w: organization with provided id does not exist", ErrOrganizationNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (r *FollowRepository) Unfollow(ctx context.Context, orgId, userId int64) error {
+	res, err := sqlf.DeleteFrom("organization_follows").
+		Where("organization_id = ?", orgId).
+		Where("user_id = ?", userId).
+		ExecAndClose(ctx, r.db)
+	if err != nil {
+		return err
+	}
+	count, _ := res.RowsAffected()
+	if count == 0 {
+		return fmt.Errorf("%w: organization %d is not followed", ErrNotFollowing, orgId)
+	}
+	return nil
+}
+
+// ListUserFollows returns organizations followed by the user, the newest first.
+func (r *FollowRepository) ListUserFollows(ctx context.Context, userId int64) ([]model.Follow, error) {
+	follows := make([]model.Follow, 0)
+	f := model.Follow{}
+	err := sqlf.From("organization_follows").
+		Select("organization_id, user_id, created_at").
+		To(&f.OrganizationID, &f.UserID, &f.CreatedAt).
+		Where("user_id = ?", userId).
+		OrderBy("created_at DESC").
+		QueryAndClose(ctx, r.db, func(rows *sql.Rows) {
+			follows = append(follows, f)
+		})
+	if err != nil {
+		return nil, err
+	}
+	return follows, nil
+}
+
+// ListFollowers returns followers of the organization with id greater than afterUserId ordered by id.
+// Banned and erased users are skipped.
+func (r *FollowRepository) ListFollowers(ctx context.Context, orgId, afterUserId int64, limit int) ([]model.User, error) {
+	var users []model.User
+	u := model.User{}
+	err := selectUser(&u).
+		Where("user_id IN (SELECT user_id FROM organization_follows WHERE organization_id = ?)", orgId).
+		Where("user_id > ?", afterUserId).
+		Where("banned_at IS NULL").
+		Where("erased_at IS NULL").
+		OrderBy("user_id").
+		Limit(limit).
+		QueryAndClose(ctx, r.db, func(rows *sql.Rows) {
+			users = append(users, u)
+		})
+	if err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// ScheduleFanout schedules notification of followers about the event. The event is notified about once.
+func (r *FollowRepository) ScheduleFanout(ctx context.Context, eventId int64) error {
+	_, err := sqlf.InsertInto("event_fanouts").
+		Set("event_id", eventId).
+		Clause("ON CONFLICT (event_id) DO NOTHING").
+		ExecAndClose(ctx, r.db)
+	return err
+}
+
+// ClaimFanout locks pending fanout, so other replicas skip it. It must be called in transaction.
+func (r *FollowRepository) ClaimFanout(ctx context.Context) (*model.EventFanout, error) {
+	f := &model.EventFanout{}
+	err := sqlf.From("event_fanouts").
+		Select("fanout_id, event_id, last_user_id").
+		To(&f.FanoutID, &f.EventID, &f.LastUserID).
+		Where("done_at IS NULL").
+		OrderBy("fanout_id").
+		Limit(1).
+		Clause("FOR UPDATE SKIP LOCKED").
+		QueryRowAndClose(ctx, r.db)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrFanoutNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (r *FollowRepository) AdvanceFanout(ctx context.Context, fanoutId, lastUserId int64) error {
+	_, err := sqlf.Update("event_fanouts").
+		Set("last_user_id", lastUserId).
+		Where("fanout_id = ?", fanoutId).
+		ExecAndClose(ctx, r.db)
+	return err
+}
+
+func (r *FollowRepository) CompleteFanout(ctx context.Context, fanoutId int64) error {
+	_, err := sqlf.Update("event_fanouts").
+		Set("done_at", time.Now().UTC()).
+		Where("fanout_id = ?", fanoutId).
+		ExecAndClose(ctx, r.db)
+	return err
+}
@@ -2,8 +2,10 @@ package repositories
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
+	"github.com/burenotti/rtu-it-lab-recruit/model"
 	"github.com/leporo/sqlf"
 	"strconv"
 	"time"
@@ -44,6 +46,7 @@ func (r *LoginCodeRepository) MarkCodeUsed(ctx context.Context, userId int64, co
 		Where("is_used = false").
 		Where("now() < expires_at").
 		Set("is_used", true).
+		Set("used_at", time.Now().UTC()).
 		ExecAndClose(ctx, r.Db)
 
 	if err != nil {
@@ -56,3 +59,26 @@ func (r *LoginCodeRepository) MarkCodeUsed(ctx context.Context, userId int64, co
 	}
 	return nil
 }
+
+// ListLoginHistory returns all login codes requested by user, the newest first.
+func (r *LoginCodeRepository) ListLoginHistory(ctx context.Context, userId int64) ([]model.LoginRecord, error) {
+	var history []model.LoginRecord
+	var scanErr error
+	err := sqlf.From("login_code").
+		Select("created_at, used_at, expires_at").
+		Where("user_id = ?", userId).
+		OrderBy("created_at DESC").
+		QueryAndClose(ctx, r.Db, func(rows *sql.Rows) {
+			rec := model.LoginRecord{}
+			if err := rows.Scan(&rec.CreatedAt, &rec.UsedAt, &rec.ExpiresAt); err != nil {
+				scanErr = err
+				return
+			}
+			history = append(history, rec)
+		})
+
+	if err != nil {
+		return nil, err
+	}
+	return history, scanErr
+}
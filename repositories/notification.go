@@ -0,0 +1,199 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/burenotti/rtu-it-lab-recruit/model"
+	"github.com/jackc/pgx/v4"
+	"github.com/leporo/sqlf"
+	"time"
+)
+
+// NotificationsChannel is the Postgres channel signals about created notifications are published to.
+const NotificationsChannel = "notifications"
+
+var (
+	ErrNotificationNotFound = errors.New("notification does not exist")
+)
+
+type NotificationRepository struct {
+	db DatabaseWrapper
+}
+
+func NewNotificationRepository(db DatabaseWrapper) *NotificationRepository {
+	return &NotificationRepository{db: db}
+}
+
+// Create stores the notification and publishes the signal about it. NOTIFY is delivered once the transaction
+// is committed, so listeners never see notifications which are rolled back.
+func (r *NotificationRepository) Create(ctx context.Context, n *model.Notification) (*model.Notification, error) {
+	created := &model.Notification{}
+	err := returningNotification(sqlf.InsertInto("notifications").
+		Set("user_id", n.UserID).
+		Set("kind", n.Kind).
+		Set("title", n.Title).
+		Set("body", n.Body), created).
+		QueryRowAndClose(ctx, r.db)
+	if getViolatedConstraint(err) == "notifications_user_id_fkey" {
+		return nil, ErrUserNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	signal, err := json.Marshal(model.NotificationSignal{
+		NotificationID: created.NotificationID,
+		UserID:         created.UserID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	_, err = sqlf.New("SELECT pg_notify(?, ?)", NotificationsChannel, string(signal)).ExecAndClose(ctx, r.db)
+	if err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+func (r *NotificationRepository) GetNotification(ctx context.Context, userId, notificationId int64) (*model.Notification, error) {
+	n := &model.Notification{}
+	err := selectNotification(n).
+		Where("notification_id = ?", notificationId).
+		Where("user_id = ?", userId).
+		QueryRowAndClose(ctx, r.db)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: notification with provided id does not exist", ErrNotificationNotFound)
+	} else if err != nil {
+		return nil, err
+	}
+	return n, nil
+}
+
+// ListNotifications returns notifications of the user starting from the newest.
+func (r *NotificationRepository) ListNotifications(
+	ctx context.Context,
+	userId int64,
+	unreadOnly bool,
+	limit, offset int,
+) ([]model.Notification, error) {
+	n := model.Notification{}
+	query := selectNotification(&n).
+		Where("user_id = ?", userId).
+		OrderBy("notification_id DESC").
+		Limit(limit).
+		Offset(offset)
+	if unreadOnly {
+		query = query.Where("read_at IS NULL")
+	}
+	return r.list(ctx, &n, query)
+}
+
+// ListNotificationsAfter returns notifications of the user created after the provided one, starting from the oldest.
+// Streams use it to catch up with notifications created while the client was disconnected.
+func (r *NotificationRepository) ListNotificationsAfter(
+	ctx context.Context,
+	userId, afterId int64,
+	limit int,
+) ([]model.Notification, error) {
+	n := model.Notification{}
+	return r.list(ctx, &n, selectNotification(&n).
+		Where("user_id = ?", userId).
+		Where("notification_id > ?", afterId).
+		OrderBy("notification_id").
+		Limit(limit))
+}
+
+func (r *NotificationRepository) CountUnread(ctx context.Context, userId int64) (int, error) {
+	count := 0
+	err := sqlf.From("notifications").
+		Select("count(1)").To(&count).
+		Where("user_id = ?", userId).
+		Where("read_at IS NULL").
+		QueryRowAndClose(ctx, r.db)
+	return count, err
+}
+
+// MarkRead marks the notification as read. Marking already read notification is not an error.
+func (r *NotificationRepository) MarkRead(ctx context.Context, userId, notificationId int64) (*model.Notification, error) {
+	n := &model.Notification{}
+	err := returningNotification(sqlf.Update("notifications").
+		SetExpr("read_at", "coalesce(read_at, ?)", time.Now().UTC()).
+		Where("notification_id = ?", notificationId).
+		Where("user_id = ?", userId), n).
+		QueryRowAndClose(ctx, r.db)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: notification with provided id does not exist", ErrNotificationNotFound)
+	} else if err != nil {
+		return nil, err
+	}
+	return n, nil
+}
+
+// MarkAllRead marks all unread notifications of the user as read and returns their number.
+func (r *NotificationRepository) MarkAllRead(ctx context.Context, userId int64) (int64, error) {
+	res, err := sqlf.Update("notifications").
+		Set("read_at", time.Now().UTC()).
+		Where("user_id = ?", userId).
+		Where("read_at IS NULL").
+		ExecAndClose(ctx, r.db)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+func (r *NotificationRepository) list(ctx context.Context, n *model.Notification, query *sqlf.Stmt) ([]model.Notification, error) {
+	notifications := make([]model.Notification, 0)
+	err := query.QueryAndClose(ctx, r.db, func(rows *sql.Rows) {
+		notifications = append(notifications, *n)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return notifications, nil
+}
+
+func selectNotification(n *model.Notification) *sqlf.Stmt {
+	return sqlf.From("notifications").
+		Select("notification_id, user_id, kind, title, body, created_at, read_at").
+		To(&n.NotificationID, &n.UserID, &n.Kind, &n.Title, &n.Body, &n.CreatedAt, &n.ReadAt)
+}
+
+func returningNotification(query *sqlf.Stmt, n *model.Notification) *sqlf.Stmt {
+	return query.
+		Returning("notification_id, user_id, kind, title, body, created_at, read_at").
+		To(&n.NotificationID, &n.UserID, &n.Kind, &n.Title, &n.Body, &n.CreatedAt, &n.ReadAt)
+}
+
+// NotificationListener receives signals about created notifications with Postgres LISTEN.
+// It uses a dedicated connection, because connections of the pool can't be held by a listener.
+type NotificationListener struct {
+	DSN string
+}
+
+// Listen calls handle for every signal until the context is done or the connection fails.
+// Signals published while the listener is disconnected are lost.
+func (l *NotificationListener) Listen(ctx context.Context, handle func(signal model.NotificationSignal)) error {
+	conn, err := pgx.Connect(ctx, l.DSN)
+	if err != nil {
+		return err
+	}
+	defer conn.Close(context.Background())
+
+	if _, err = conn.Exec(ctx, "LISTEN "+NotificationsChannel); err != nil {
+		return err
+	}
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+		signal := model.NotificationSignal{}
+		if err = json.Unmarshal([]byte(notification.Payload), &signal); err != nil {
+			continue
+		}
+		handle(signal)
+	}
+}
@@ -0,0 +1,68 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/burenotti/rtu-it-lab-recruit/model"
+	"github.com/leporo/sqlf"
+)
+
+type NotificationPreferenceRepository struct {
+	db DatabaseWrapper
+}
+
+func NewNotificationPreferenceRepository(db DatabaseWrapper) *NotificationPreferenceRepository {
+	return &NotificationPreferenceRepository{db: db}
+}
+
+// ListPreferences returns stored preferences of the user. Categories without stored preference are enabled.
+func (r *NotificationPreferenceRepository) ListPreferences(ctx context.Context, userId int64) ([]model.NotificationPreference, error) {
+	var preferences []model.NotificationPreference
+	p := model.NotificationPreference{}
+	err := sqlf.From("notification_preferences").
+		Select("channel, category, enabled").To(&p.Channel, &p.Category, &p.Enabled).
+		Where("user_id = ?", userId).
+		OrderBy("channel, category").
+		QueryAndClose(ctx, r.db, func(rows *sql.Rows) {
+			preferences = append(preferences, p)
+		})
+	if err != nil {
+		return nil, err
+	}
+	return preferences, nil
+}
+
+func (r *NotificationPreferenceRepository) SetPreference(
+	ctx context.Context,
+	userId int64,
+	channel, category string,
+	enabled bool,
+) error {
+	_, err := sqlf.InsertInto("notification_preferences").
+		Set("user_id", userId).
+		Set("channel", channel).
+		Set("category", category).
+		Set("enabled", enabled).
+		Clause("ON CONFLICT (user_id, channel, category) DO UPDATE SET enabled = EXCLUDED.enabled").
+		ExecAndClose(ctx, r.db)
+	if getViolatedConstraint(err) == "notification_preferences_user_id_fkey" {
+		return ErrUserNotFound
+	}
+	return err
+}
+
+// IsEnabled tells whether messages of the category are sent to the channel of the user.
+func (r *NotificationPreferenceRepository) IsEnabled(ctx context.Context, userId int64, channel, category string) (bool, error) {
+	enabled := true
+	err := sqlf.From("notification_preferences").
+		Select("enabled").To(&enabled).
+		Where("user_id = ?", userId).
+		Where("channel = ?", channel).
+		Where("category = ?", category).
+		QueryRowAndClose(ctx, r.db)
+	if errors.Is(err, sql.ErrNoRows) {
+		return true, nil
+	}
+	return enabled, err
+}
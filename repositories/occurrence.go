@@ -0,0 +1,196 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/burenotti/rtu-it-lab-recruit/model"
+	"github.com/leporo/sqlf"
+	"time"
+)
+
+const occurrenceColumns = "occurrence_id, event_id, recurrence_id, begins_at, ends_at, " +
+	"name, description, overridden, cancelled_at"
+
+var ErrOccurrenceNotFound = errors.New("occurrence does not exist")
+
+var occurrenceUpdatesValidator = NewUpdatesValidator([]string{
+	"recurrence_id", "begins_at", "ends_at", "name", "description", "overridden", "cancelled_at",
+})
+
+type OccurrenceRepository struct {
+	db DatabaseWrapper
+}
+
+func NewOccurrenceRepository(db DatabaseWrapper) *OccurrenceRepository {
+	return &OccurrenceRepository{db: db}
+}
+
+// CreateOccurrences materializes occurrences of the event.
+func (r *OccurrenceRepository) CreateOccurrences(ctx context.Context, occurrences []model.Occurrence) error {
+	for i := range occurrences {
+		o := &occurrences[i]
+		err := sqlf.InsertInto("event_occurrences").
+			Set("event_id", o.EventID).
+			Set("recurrence_id", o.RecurrenceID).
+			Set("begins_at", o.BeginsAt).
+			Set("ends_at", o.EndsAt).
+			Returning("occurrence_id").To(&o.OccurrenceID).
+			QueryRowAndClose(ctx, r.db)
+		if getViolatedConstraint(err) == "event_occurrences_event_id_fkey" {
+			return ErrEventNotFount
+		} else if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *OccurrenceRepository) GetOccurrence(ctx context.Context, eventId, occurrenceId int64) (*model.Occurrence, error) {
+	o := &model.Occurrence{}
+	err := selectOccurrence(o).
+		Where("event_id = ?", eventId).
+		Where("occurrence_id = ?", occurrenceId).
+		QueryRowAndClose(ctx, r.db)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: occurrence with provided id does not exist", ErrOccurrenceNotFound)
+	} else if err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+// ListOccurrences returns occurrences of the event beginning in [from, to), including cancelled ones.
+func (r *OccurrenceRepository) ListOccurrences(ctx context.Context, eventId int64, from, to time.Time) ([]model.Occurrence, error) {
+	o := model.Occurrence{}
+	return r.list(ctx, &o, selectOccurrence(&o).
+		Where("event_id = ?", eventId).
+		Where("begins_at >= ? AND begins_at < ?", from, to).
+		OrderBy("begins_at, occurrence_id"))
+}
+
+// ListOccurrencesSince returns occurrences of the event the rule places at or after since, ordered by RECURRENCE-ID.
+func (r *OccurrenceRepository) ListOccurrencesSince(ctx context.Context, eventId int64, since time.Time) ([]model.Occurrence, error) {
+	o := model.Occurrence{}
+	return r.list(ctx, &o, selectOccurrence(&o).
+		Where("event_id = ?", eventId).
+		Where("recurrence_id >= ?", since).
+		OrderBy("recurrence_id"))
+}
+
+// ListOccurrenceChanges returns overridden and cancelled occurrences of the events, the rest of occurrences
+// follow the rule of their series.
+func (r *OccurrenceRepository) ListOccurrenceChanges(ctx context.Context, eventIds []int64) ([]model.Occurrence, error) {
+	o := model.Occurrence{}
+	return r.list(ctx, &o, selectOccurrence(&o).
+		Where("event_id = ANY(?)", eventIds).
+		Where("(overridden OR cancelled_at IS NOT NULL)").
+		OrderBy("event_id, recurrence_id"))
+}
+
+// ListRegisteredOccurrences returns occurrences the user has registered for, which end after since.
+func (r *OccurrenceRepository) ListRegisteredOccurrences(ctx context.Context, userId int64, since time.Time) ([]model.Occurrence, error) {
+	o := model.Occurrence{}
+	return r.list(ctx, &o, selectOccurrence(&o).
+		Where("occurrence_id IN (SELECT occurrence_id FROM event_registrations WHERE user_id = ?)", userId).
+		Where("ends_at >= ?", since).
+		OrderBy("begins_at, occurrence_id"))
+}
+
+func (r *OccurrenceRepository) UpdateOccurrence(
+	ctx context.Context,
+	occurrenceId int64,
+	updates map[string]interface{},
+) (*model.Occurrence, error) {
+	if err := occurrenceUpdatesValidator.Validate(updates); err != nil {
+		return nil, err
+	}
+	o := &model.Occurrence{}
+	query := sqlf.Update("event_occurrences").
+		Where("occurrence_id = ?", occurrenceId)
+	for field, value := range updates {
+		query = query.Set(field, value)
+	}
+	err := returningOccurrence(query, o).QueryRowAndClose(ctx, r.db)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: occurrence with provided id does not exist", ErrOccurrenceNotFound)
+	} else if err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+// RemoveOccurrences removes occurrences no longer matching the rule. Occurrences with registrations
+// are cancelled instead, so registrants don't lose them silently. It returns ids of cancelled occurrences.
+func (r *OccurrenceRepository) RemoveOccurrences(ctx context.Context, occurrenceIds []int64) ([]int64, error) {
+	cancelled := make([]int64, 0)
+	if len(occurrenceIds) == 0 {
+		return cancelled, nil
+	}
+	var occurrenceId int64
+	err := sqlf.Update("event_occurrences").
+		Set("cancelled_at", time.Now().UTC()).
+		Where("occurrence_id = ANY(?)", occurrenceIds).
+		Where("cancelled_at IS NULL").
+		Where("occurrence_id IN (SELECT occurrence_id FROM event_registrations WHERE occurrence_id IS NOT NULL)").
+		Returning("occurrence_id").To(&occurrenceId).
+		QueryAndClose(ctx, r.db, func(rows *sql.Rows) {
+			cancelled = append(cancelled, occurrenceId)
+		})
+	if err != nil {
+		return nil, err
+	}
+	_, err = sqlf.DeleteFrom("event_occurrences").
+		Where("occurrence_id = ANY(?)", occurrenceIds).
+		Where("occurrence_id NOT IN (SELECT occurrence_id FROM event_registrations WHERE occurrence_id IS NOT NULL)").
+		ExecAndClose(ctx, r.db)
+	if err != nil {
+		return nil, err
+	}
+	return cancelled, nil
+}
+
+// MoveOccurrences moves occurrences the rule places at or after since with their registrations to another event.
+// It is used to split series.
+func (r *OccurrenceRepository) MoveOccurrences(ctx context.Context, fromEventId, toEventId int64, since time.Time) error {
+	_, err := sqlf.Update("event_registrations").
+		Set("event_id", toEventId).
+		Where("occurrence_id IN (SELECT occurrence_id FROM event_occurrences WHERE event_id = ? AND recurrence_id >= ?)",
+			fromEventId, since).
+		ExecAndClose(ctx, r.db)
+	if err != nil {
+		return err
+	}
+	_, err = sqlf.Update("event_occurrences").
+		Set("event_id", toEventId).
+		Where("event_id = ?", fromEventId).
+		Where("recurrence_id >= ?", since).
+		ExecAndClose(ctx, r.db)
+	return err
+}
+
+func (r *OccurrenceRepository) list(ctx context.Context, o *model.Occurrence, query *sqlf.Stmt) ([]model.Occurrence, error) {
+	occurrences := make([]model.Occurrence, 0)
+	err := query.QueryAndClose(ctx, r.db, func(rows *sql.Rows) {
+		occurrences = append(occurrences, *o)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return occurrences, nil
+}
+
+func selectOccurrence(o *model.Occurrence) *sqlf.Stmt {
+	return sqlf.From("event_occurrences").
+		Select(occurrenceColumns).
+		To(&o.OccurrenceID, &o.EventID, &o.RecurrenceID, &o.BeginsAt, &o.EndsAt,
+			&o.Name, &o.Description, &o.Overridden, &o.CancelledAt)
+}
+
+func returningOccurrence(query *sqlf.Stmt, o *model.Occurrence) *sqlf.Stmt {
+	return query.
+		Returning(occurrenceColumns).
+		To(&o.OccurrenceID, &o.EventID, &o.RecurrenceID, &o.BeginsAt, &o.EndsAt,
+			&o.Name, &o.Description, &o.Overridden, &o.CancelledAt)
+}
@@ -0,0 +1,163 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/burenotti/rtu-it-lab-recruit/model"
+	"github.com/leporo/sqlf"
+	"time"
+)
+
+var ErrOrderNotFound = errors.New("order does not exist")
+
+const OrdersEventUserUniqueName = "orders_event_id_user_id_key"
+
+const orderColumns = "order_id, event_id, user_id, ticket_type_id, quantity, promo_code_id, currency, " +
+	"subtotal, discount, total, status, payment_id, payment_url, expires_at, created_at, paid_at, source"
+
+type OrderRepository struct {
+	db DatabaseWrapper
+}
+
+func NewOrderRepository(db DatabaseWrapper) *OrderRepository {
+	return &OrderRepository{db: db}
+}
+
+// CreateOrder creates the order with status set in it, reserved orders hold tickets until they expire.
+// The user can't have more than one reserved or paid order for the event.
+func (r *OrderRepository) CreateOrder(ctx context.Context, order *model.Order) (*model.Order, error) {
+	o := &model.Order{}
+	err := returningOrder(sqlf.InsertInto("orders").
+		Set("event_id", order.EventID).
+		Set("user_id", order.UserID).
+		Set("ticket_type_id", order.TicketTypeID).
+		Set("quantity", order.Quantity).
+		Set("promo_code_id", order.PromoCodeID).
+		Set("currency", order.Currency).
+		Set("subtotal", order.Subtotal).
+		Set("discount", order.Discount).
+		Set("total", order.Total).
+		Set("status", order.Status).
+		Set("expires_at", order.ExpiresAt).
+		Set("paid_at", order.PaidAt).
+		Set("source", order.Source), o).
+		QueryRowAndClose(ctx, r.db)
+	if constraint := getViolatedConstraint(err); constraint == "orders_ticket_type_id_fkey" {
+		return nil, fmt.Errorf("%w: ticket type with provided id does not exist", ErrTicketTypeNotFound)
+	} else if constraint == OrdersEventUserUniqueName {
+		return nil, fmt.Errorf("%w: user has already ordered tickets for event %d", ErrAlreadyRegistered, order.EventID)
+	} else if err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+func (r *OrderRepository) GetOrder(ctx context.Context, orderId int64) (*model.Order, error) {
+	o := &model.Order{}
+	err := selectOrder(o).
+		Where("order_id = ?", orderId).
+		QueryRowAndClose(ctx, r.db)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: order with provided id does not exist", ErrOrderNotFound)
+	} else if err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+// LockOrder returns the order locked until the end of transaction, so it is not paid and expired at once.
+func (r *OrderRepository) LockOrder(ctx context.Context, orderId int64) (*model.Order, error) {
+	o := &model.Order{}
+	err := selectOrder(o).
+		Where("order_id = ?", orderId).
+		Clause("FOR UPDATE").
+		QueryRowAndClose(ctx, r.db)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: order with provided id does not exist", ErrOrderNotFound)
+	} else if err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+// ListUserOrders returns orders of the user, the newest first.
+func (r *OrderRepository) ListUserOrders(ctx context.Context, userId int64) ([]model.Order, error) {
+	o := &model.Order{}
+	return r.listOrders(ctx, o, selectOrder(o).
+		Where("user_id = ?", userId).
+		OrderBy("created_at DESC, order_id DESC"))
+}
+
+// ListExpiredOrders returns reserved orders which expire before the time.
+func (r *OrderRepository) ListExpiredOrders(ctx context.Context, before time.Time, limit int) ([]model.Order, error) {
+	o := &model.Order{}
+	return r.listOrders(ctx, o, selectOrder(o).
+		Where("status = ?", model.OrderReserved).
+		Where("expires_at <= ?", before).
+		OrderBy("expires_at").
+		Limit(limit))
+}
+
+func (r *OrderRepository) SetOrderPayment(ctx context.Context, orderId int64, paymentId, paymentURL string) error {
+	res, err := sqlf.Update("orders").
+		Set("payment_id", paymentId).
+		Set("payment_url", paymentURL).
+		Where("order_id = ?", orderId).
+		ExecAndClose(ctx, r.db)
+	if err != nil {
+		return err
+	}
+	if affected, err := res.RowsAffected(); err != nil {
+		return err
+	} else if affected == 0 {
+		return fmt.Errorf("%w: order with provided id does not exist", ErrOrderNotFound)
+	}
+	return nil
+}
+
+// UpdateOrderStatus sets status of the order, paid orders get the time of payment.
+func (r *OrderRepository) UpdateOrderStatus(ctx context.Context, orderId int64, status string) (*model.Order, error) {
+	o := &model.Order{}
+	query := sqlf.Update("orders").
+		Set("status", status).
+		Where("order_id = ?", orderId)
+	if status == model.OrderPaid {
+		query = query.SetExpr("paid_at", "now()")
+	}
+	err := returningOrder(query, o).QueryRowAndClose(ctx, r.db)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: order with provided id does not exist", ErrOrderNotFound)
+	} else if err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+func (r *OrderRepository) listOrders(ctx context.Context, o *model.Order, query *sqlf.Stmt) ([]model.Order, error) {
+	orders := make([]model.Order, 0)
+	err := query.QueryAndClose(ctx, r.db, func(rows *sql.Rows) {
+		orders = append(orders, *o)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+func selectOrder(o *model.Order) *sqlf.Stmt {
+	return sqlf.From("orders").
+		Select(orderColumns).
+		To(&o.OrderID, &o.EventID, &o.UserID, &o.TicketTypeID, &o.Quantity, &o.PromoCodeID, &o.Currency,
+			&o.Subtotal, &o.Discount, &o.Total, &o.Status, &o.PaymentID, &o.PaymentURL, &o.ExpiresAt,
+			&o.CreatedAt, &o.PaidAt, &o.Source)
+}
+
+func returningOrder(query *sqlf.Stmt, o *model.Order) *sqlf.Stmt {
+	return query.
+		Returning(orderColumns).
+		To(&o.OrderID, &o.EventID, &o.UserID, &o.TicketTypeID, &o.Quantity, &o.PromoCodeID, &o.Currency,
+			&o.Subtotal, &o.Discount, &o.Total, &o.Status, &o.PaymentID, &o.PaymentURL, &o.ExpiresAt,
+			&o.CreatedAt, &o.PaidAt, &o.Source)
+}
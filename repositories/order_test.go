@@ -0,0 +1,116 @@
+package repositories
+
+import (
+	"context"
+	"github.com/burenotti/rtu-it-lab-recruit/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+)
+
+type OrderRepositoryTestSuite struct {
+	DBTestSuite
+}
+
+func TestOrderRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, &OrderRepositoryTestSuite{
+		*DBTestSuiteFromEnv(),
+	})
+}
+
+func (s *OrderRepositoryTestSuite) createTicketType(ctx context.Context, quantity *int) *model.TicketType {
+	db := NewDatabase(s.db)
+	user := CreateRandomUser(ctx, db, s.T())
+	event := CreateRandomEvent(ctx, db, s.T(), user.UserID, time.Now().Add(48*time.Hour))
+	ticketType, err := NewTicketRepository(db).CreateTicketType(ctx, event.EventID, &model.TicketTypeCreate{
+		Name: "Standard", Price: 1000, Currency: "RUB", Quantity: quantity,
+	})
+	require.NoError(s.T(), err, "should create ticket type without errors")
+	return ticketType
+}
+
+func (s *OrderRepositoryTestSuite) TestExpiryReleasesTickets() {
+	ctx := context.Background()
+	db := NewDatabase(s.db)
+	repo := NewOrderRepository(db)
+	tickets := NewTicketRepository(db)
+	quantity := 1
+	ticketType := s.createTicketType(ctx, &quantity)
+	order := CreateRandomOrder(ctx, db, s.T(), ticketType, nil, model.OrderReserved)
+
+	expired, err := repo.ListExpiredOrders(ctx, order.ExpiresAt.Add(-time.Minute), 10)
+	require.NoError(s.T(), err)
+	assert.Empty(s.T(), expired, "order should not expire before its time")
+
+	expired, err = repo.ListExpiredOrders(ctx, order.ExpiresAt.Add(time.Minute), 10)
+	require.NoError(s.T(), err)
+	require.Len(s.T(), expired, 1)
+	assert.Equal(s.T(), order.OrderID, expired[0].OrderID)
+
+	got, err := tickets.GetTicketType(ctx, ticketType.TicketTypeID)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), 0, *got.Available, "overdue order should hold ticket until it is expired")
+
+	err = db.Atomic(ctx, func(ctx context.Context) error {
+		locked, err := repo.LockOrder(ctx, order.OrderID)
+		if err != nil {
+			return err
+		}
+		assert.Equal(s.T(), model.OrderReserved, locked.Status)
+		_, err = repo.UpdateOrderStatus(ctx, order.OrderID, model.OrderExpired)
+		return err
+	})
+	require.NoError(s.T(), err)
+
+	got, err = tickets.GetTicketType(ctx, ticketType.TicketTypeID)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), 1, *got.Available, "expired order should release ticket")
+
+	expired, err = repo.ListExpiredOrders(ctx, order.ExpiresAt.Add(time.Minute), 10)
+	require.NoError(s.T(), err)
+	assert.Empty(s.T(), expired, "expired order should not be listed again")
+}
+
+func (s *OrderRepositoryTestSuite) TestOneOrderPerEvent() {
+	ctx := context.Background()
+	db := NewDatabase(s.db)
+	repo := NewOrderRepository(db)
+	ticketType := s.createTicketType(ctx, nil)
+	order := CreateRandomOrder(ctx, db, s.T(), ticketType, nil, model.OrderReserved)
+
+	second := *order
+	_, err := repo.CreateOrder(ctx, &second)
+	assert.ErrorIs(s.T(), err, ErrAlreadyRegistered, "user should not hold two orders for the event")
+
+	paid, err := repo.UpdateOrderStatus(ctx, order.OrderID, model.OrderPaid)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), model.OrderPaid, paid.Status)
+	assert.NotNil(s.T(), paid.PaidAt, "paid order should get the time of payment")
+	_, err = repo.CreateOrder(ctx, &second)
+	assert.ErrorIs(s.T(), err, ErrAlreadyRegistered, "user should not order tickets again after paying")
+
+	_, err = repo.UpdateOrderStatus(ctx, order.OrderID, model.OrderCancelled)
+	require.NoError(s.T(), err)
+	_, err = repo.CreateOrder(ctx, &second)
+	assert.NoError(s.T(), err, "user should order tickets again after cancelling the order")
+}
+
+func (s *OrderRepositoryTestSuite) TestSetOrderPayment() {
+	ctx := context.Background()
+	db := NewDatabase(s.db)
+	repo := NewOrderRepository(db)
+	ticketType := s.createTicketType(ctx, nil)
+	order := CreateRandomOrder(ctx, db, s.T(), ticketType, nil, model.OrderReserved)
+
+	err := repo.SetOrderPayment(ctx, order.OrderID, "payment-1", "https://pay.example.com/payment-1")
+	require.NoError(s.T(), err)
+	got, err := repo.GetOrder(ctx, order.OrderID)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), "payment-1", *got.PaymentID)
+	assert.Equal(s.T(), "https://pay.example.com/payment-1", *got.PaymentURL)
+
+	err = repo.SetOrderPayment(ctx, order.OrderID+1000, "payment-2", "https://pay.example.com/payment-2")
+	assert.ErrorIs(s.T(), err, ErrOrderNotFound)
+}
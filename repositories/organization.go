@@ -21,6 +21,9 @@ var (
 	ErrUserAlreadyMember    = errors.New("user is already member of organization")
 )
 
+const followersCountColumn = "(SELECT count(*) FROM organization_follows f " +
+	"WHERE f.organization_id = organizations.organization_id)"
+
 type OrganizationRepository struct {
 	db DatabaseWrapper
 }
@@ -60,6 +63,7 @@ func (r *OrganizationRepository) GetById(ctx context.Context, orgId int64) (*mod
 		Select("address").To(&res.Address).
 		Select("contact_phone").To(&res.ContactPhone).
 		Select("contact_email").To(&res.ContactEmail).
+		Select(followersCountColumn).To(&res.FollowersCount).
 		Where("organization_id = ?", orgId).
 		QueryRow(ctx, r.db)
 
@@ -82,7 +86,8 @@ func (r *OrganizationRepository) Update(ctx context.Context, orgId int64, update
 		Returning("name").To(&o.Name).
 		Returning("address").To(&o.Address).
 		Returning("contact_phone").To(&o.ContactPhone).
-		Returning("contact_email").To(&o.ContactEmail)
+		Returning("contact_email").To(&o.ContactEmail).
+		Returning(followersCountColumn).To(&o.FollowersCount)
 
 	for key, upd := range updates {
 		if _, ok := fields[key]; !ok {
@@ -124,6 +129,7 @@ func (r *OrganizationRepository) AddMember(ctx context.Context, orgId int64, mem
 		Set("user_id", mem.UserID).
 		Set("can_manage_members", mem.Rights.ManageMembers).
 		Set("can_edit_events", mem.Rights.EditEvents).
+		Set("can_check_in", mem.Rights.CheckIn).
 		Set("is_owner", mem.IsOwner).
 		ExecAndClose(ctx, r.db)
 
@@ -149,10 +155,10 @@ func (r *OrganizationRepository) ListMembers(ctx context.Context, orgId int64) (
 	var scanErr error
 	err := sqlf.From("organization_members").
 		Where("organization_id = ?", orgId).
-		Select("user_id, can_manage_members, can_edit_events, is_owner").
+		Select("user_id, can_manage_members, can_edit_events, can_check_in, is_owner").
 		QueryAndClose(ctx, r.db, func(rows *sql.Rows) {
 			m := model.OrganizationMember{}
-			err := rows.Scan(&m.UserID, &m.Can.ManageMembers, &m.Can.EditEvents, &m.IsOwner)
+			err := rows.Scan(&m.UserID, &m.Can.ManageMembers, &m.Can.EditEvents, &m.Can.CheckIn, &m.IsOwner)
 			if errors.Is(err, sql.ErrNoRows) {
 				scanErr = ErrOrganizationNotFound
 			} else if err != nil {
@@ -173,9 +179,10 @@ func (r *OrganizationRepository) ListMembers(ctx context.Context, orgId int64) (
 
 func (r *OrganizationRepository) SetMemberRights(ctx context.Context, orgId, userId int64, newRights model.MemberRights) (*model.OrganizationMember, error) {
 	res, err := sqlf.Update("organization_members").
-		Where("organizationId = ? AND user_id = ?", orgId, userId).
+		Where("organization_id = ? AND user_id = ?", orgId, userId).
 		Set("can_edit_events", newRights.EditEvents).
 		Set("can_manage_members", newRights.ManageMembers).
+		Set("can_check_in", newRights.CheckIn).
 		Exec(ctx, r.db)
 
 	if err != nil {
@@ -198,12 +205,12 @@ func (r *OrganizationRepository) SetMemberRights(ctx context.Context, orgId, use
 func (r *OrganizationRepository) GetMember(ctx context.Context, orgId, userId int64) (*model.OrganizationMember, error) {
 	mem := &model.OrganizationMember{}
 	err := sqlf.From("organization_members").
-		Where("organizationId = ? AND user_id = ?", orgId, userId).
-		Select("user_id, can_manage_members, can_edit_events, is_owner").
+		Where("organization_id = ? AND user_id = ?", orgId, userId).
 		Select("user_id").To(&mem.UserID).
 		Select("is_owner").To(&mem.IsOwner).
 		Select("can_edit_events").To(&mem.Can.EditEvents).
 		Select("can_manage_members").To(&mem.Can.ManageMembers).
+		Select("can_check_in").To(&mem.Can.CheckIn).
 		QueryRowAndClose(ctx, r.db)
 
 	if errors.Is(err, sql.ErrNoRows) {
@@ -230,3 +237,58 @@ func (r *OrganizationRepository) DeleteMember(ctx context.Context, orgId, userId
 	}
 	return nil
 }
+
+// ListUserMemberships returns all organizations the user is member of.
+func (r *OrganizationRepository) ListUserMemberships(ctx context.Context, userId int64) ([]model.Membership, error) {
+	var memberships []model.Membership
+	var scanErr error
+	err := sqlf.From("organization_members m").
+		Join("organizations o", "o.organization_id = m.organization_id").
+		Select("o.organization_id, o.name, m.is_owner, m.can_edit_events, m.can_manage_members, m.can_check_in").
+		Where("m.user_id = ?", userId).
+		OrderBy("o.organization_id").
+		QueryAndClose(ctx, r.db, func(rows *sql.Rows) {
+			m := model.Membership{}
+			err := rows.Scan(&m.OrganizationID, &m.Name, &m.IsOwner, &m.Can.EditEvents, &m.Can.ManageMembers, &m.Can.CheckIn)
+			if err != nil {
+				scanErr = err
+				return
+			}
+			memberships = append(memberships, m)
+		})
+
+	if err != nil {
+		return nil, err
+	}
+	return memberships, scanErr
+}
+
+// TransferOwnership makes the user the only owner of organization.
+// If the user is not a member yet, they are added with full rights.
+func (r *OrganizationRepository) TransferOwnership(ctx context.Context, orgId, userId int64) error {
+	_, err := sqlf.Update("organization_members").
+		Set("is_owner", false).
+		Where("organization_id = ? AND user_id <> ?", orgId, userId).
+		ExecAndClose(ctx, r.db)
+	if err != nil {
+		return err
+	}
+
+	_, err = sqlf.InsertInto("organization_members").
+		Set("organization_id", orgId).
+		Set("user_id", userId).
+		Set("is_owner", true).
+		Set("can_edit_events", true).
+		Set("can_manage_members", true).
+		Set("can_check_in", true).
+		Clause("ON CONFLICT (organization_id, user_id) DO UPDATE SET").
+		Clause("is_owner = true, can_edit_events = true, can_manage_members = true, can_check_in = true").
+		ExecAndClose(ctx, r.db)
+
+	if getViolatedConstraint(err) == MembersUserFkeyName {
+		return ErrUserNotFound
+	} else if getViolatedConstraint(err) == MembersOrgFkeyName {
+		return ErrOrganizationNotFound
+	}
+	return err
+}
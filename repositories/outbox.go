@@ -0,0 +1,174 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/burenotti/rtu-it-lab-recruit/model"
+	"github.com/leporo/sqlf"
+	"time"
+)
+
+var (
+	ErrOutboxMessageNotFound = errors.New("outbox message not found")
+)
+
+const outboxColumns = "message_id, user_id, kind, channel, to_address, to_name, subject, text_body, html_body, unsubscribe_url, " +
+	"status, attempts, next_attempt_at, last_error, created_at, sent_at"
+
+type OutboxRepository struct {
+	db DatabaseWrapper
+}
+
+func NewOutboxRepository(db DatabaseWrapper) *OutboxRepository {
+	return &OutboxRepository{db: db}
+}
+
+// Enqueue stores the message to be sent by dispatcher.
+// Call it in the same transaction with the changes the message is about.
+func (r *OutboxRepository) Enqueue(ctx context.Context, msg *model.Message) error {
+	var userId interface{}
+	if msg.UserID != 0 {
+		userId = msg.UserID
+	}
+	_, err := sqlf.InsertInto("message_outbox").
+		Set("user_id", userId).
+		Set("kind", msg.Kind).
+		Set("channel", msg.Channel).
+		Set("to_address", msg.ToAddress).
+		Set("to_name", msg.ToName).
+		Set("subject", msg.Subject).
+		Set("text_body", msg.TextBody).
+		Set("html_body", msg.HTMLBody).
+		Set("unsubscribe_url", msg.UnsubscribeURL).
+		ExecAndClose(ctx, r.db)
+	return err
+}
+
+// ClaimDue returns pending messages which are due to be sent. Claimed messages are leased:
+// their next attempt is postponed by lease, so other replicas won't pick them up
+// and messages of a crashed dispatcher are retried after the lease expires.
+func (r *OutboxRepository) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]model.OutboxMessage, error) {
+	now := time.Now().UTC()
+	stmt := sqlf.Update("message_outbox").
+		Set("next_attempt_at", now.Add(lease)).
+		SetExpr("attempts", "attempts + 1").
+		Where("message_id IN (SELECT message_id FROM message_outbox "+
+			"WHERE status = ? AND next_attempt_at <= ? "+
+			"ORDER BY next_attempt_at LIMIT ? FOR UPDATE SKIP LOCKED)", model.OutboxPending, now, limit).
+		Returning(outboxColumns)
+	return r.queryMessages(ctx, stmt)
+}
+
+// ExtendLease postpones the next attempt of the claimed message by lease, so the message is not picked up
+// by another replica while the batch is being sent. Attempts identify the claim: if the lease has already
+// expired and the message was claimed again, nothing is updated and false is returned.
+func (r *OutboxRepository) ExtendLease(ctx context.Context, messageId int64, attempts int, lease time.Duration) (bool, error) {
+	res, err := sqlf.Update("message_outbox").
+		Set("next_attempt_at", time.Now().UTC().Add(lease)).
+		Where("message_id = ?", messageId).
+		Where("status = ?", model.OutboxPending).
+		Where("attempts = ?", attempts).
+		ExecAndClose(ctx, r.db)
+	if err != nil {
+		return false, err
+	}
+	count, err := res.RowsAffected()
+	return count > 0, err
+}
+
+func (r *OutboxRepository) MarkSent(ctx context.Context, messageId int64) error {
+	return r.update(ctx, messageId, sqlf.Update("message_outbox").
+		Set("status", model.OutboxSent).
+		Set("sent_at", time.Now().UTC()).
+		Set("last_error", nil))
+}
+
+// MarkFailed records the failed attempt and schedules the next one.
+func (r *OutboxRepository) MarkFailed(ctx context.Context, messageId int64, reason string, nextAttemptAt time.Time) error {
+	return r.update(ctx, messageId, sqlf.Update("message_outbox").
+		Set("last_error", reason).
+		Set("next_attempt_at", nextAttemptAt))
+}
+
+// MarkDead moves the message to dead letters. It won't be retried until replayed.
+func (r *OutboxRepository) MarkDead(ctx context.Context, messageId int64, reason string) error {
+	return r.update(ctx, messageId, sqlf.Update("message_outbox").
+		Set("status", model.OutboxDead).
+		Set("last_error", reason))
+}
+
+// Replay returns dead message to the queue with reset attempts counter.
+func (r *OutboxRepository) Replay(ctx context.Context, messageId int64) error {
+	return r.update(ctx, messageId, sqlf.Update("message_outbox").
+		Set("status", model.OutboxPending).
+		Set("attempts", 0).
+		Set("next_attempt_at", time.Now().UTC()).
+		Where("status = ?", model.OutboxDead))
+}
+
+func (r *OutboxRepository) GetById(ctx context.Context, messageId int64) (*model.OutboxMessage, error) {
+	messages, err := r.queryMessages(ctx, sqlf.From("message_outbox").
+		Select(outboxColumns).
+		Where("message_id = ?", messageId))
+	if err != nil {
+		return nil, err
+	}
+	if len(messages) == 0 {
+		return nil, fmt.Errorf("%w: message with provided id does not exist", ErrOutboxMessageNotFound)
+	}
+	return &messages[0], nil
+}
+
+// List returns messages matching the filter, newest first. Empty filter fields match all messages.
+func (r *OutboxRepository) List(ctx context.Context, status, channel string, limit, offset int) ([]model.OutboxMessage, error) {
+	stmt := sqlf.From("message_outbox").
+		Select(outboxColumns)
+	if status != "" {
+		stmt.Where("status = ?", status)
+	}
+	if channel != "" {
+		stmt.Where("channel = ?", channel)
+	}
+	stmt.OrderBy("message_id DESC").
+		Limit(limit).
+		Offset(offset)
+	return r.queryMessages(ctx, stmt)
+}
+
+func (r *OutboxRepository) update(ctx context.Context, messageId int64, stmt *sqlf.Stmt) error {
+	res, err := stmt.
+		Where("message_id = ?", messageId).
+		ExecAndClose(ctx, r.db)
+	if err != nil {
+		return err
+	}
+	count, _ := res.RowsAffected()
+	if count == 0 {
+		return ErrOutboxMessageNotFound
+	}
+	return nil
+}
+
+func (r *OutboxRepository) queryMessages(ctx context.Context, stmt *sqlf.Stmt) ([]model.OutboxMessage, error) {
+	var messages []model.OutboxMessage
+	var scanErr error
+	err := stmt.QueryAndClose(ctx, r.db, func(rows *sql.Rows) {
+		m := model.OutboxMessage{}
+		var userId sql.NullInt64
+		err := rows.Scan(&m.MessageID, &userId, &m.Kind, &m.Channel, &m.ToAddress, &m.ToName, &m.Subject, &m.TextBody, &m.HTMLBody,
+			&m.UnsubscribeURL, &m.Status, &m.Attempts, &m.NextAttemptAt, &m.LastError, &m.CreatedAt, &m.SentAt)
+		if err != nil {
+			scanErr = err
+			return
+		}
+		m.UserID = userId.Int64
+		messages = append(messages, m)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return messages, scanErr
+}
@@ -0,0 +1,113 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/burenotti/rtu-it-lab-recruit/model"
+	"github.com/leporo/sqlf"
+)
+
+const (
+	RegistrationsUniqueName         = "unique_event_registrations"
+	RegistrationsOccurrenceFkeyName = "event_registrations_occurrence_id_fkey"
+)
+
+var (
+	ErrAlreadyRegistered    = errors.New("user is already registered")
+	ErrRegistrationNotFound = errors.New("registration not found")
+)
+
+type RegistrationRepository struct {
+	db DatabaseWrapper
+}
+
+func NewRegistrationRepository(db DatabaseWrapper) *RegistrationRepository {
+	return &RegistrationRepository{db: db}
+}
+
+// Register registers the user for the event. OccurrenceId is set for occurrences of recurring events,
+// source is where the user came from.
+func (r *RegistrationRepository) Register(ctx context.Context, eventId, userId int64, occurrenceId *int64, source *string) (*model.Registration, error) {
+	reg := &model.Registration{}
+	err := sqlf.InsertInto("event_registrations").
+		Set("event_id", eventId).
+		Set("user_id", userId).
+		Set("occurrence_id", occurrenceId).
+		Set("source", source).
+		Returning("registration_id, event_id, occurrence_id, user_id, source, created_at").
+		To(&reg.RegistrationID, &reg.EventID, &reg.OccurrenceID, &reg.UserID, &reg.Source, &reg.CreatedAt).
+		QueryRowAndClose(ctx, r.db)
+
+	if getViolatedConstraint(err) == RegistrationsUniqueName {
+		return nil, fmt.Errorf("%w: user is already registered for event %d", ErrAlreadyRegistered, eventId)
+	} else if getViolatedConstraint(err) == RegistrationsOccurrenceFkeyName {
+		return nil, fmt.Errorf("%w: occurrence with provided id does not exist", ErrOccurrenceNotFound)
+	} else if err != nil {
+		return nil, err
+	}
+	return reg, nil
+}
+
+func (r *RegistrationRepository) Unregister(ctx context.Context, eventId, userId int64, occurrenceId *int64) error {
+	res, err := sqlf.DeleteFrom("event_registrations").
+		Where("event_id = ?", eventId).
+		Where("user_id = ?", userId).
+		Where("occurrence_id IS NOT DISTINCT FROM ?", occurrenceId).
+		ExecAndClose(ctx, r.db)
+	if err != nil {
+		return err
+	}
+	count, _ := res.RowsAffected()
+	if count == 0 {
+		return fmt.Errorf("%w: user is not registered for event %d", ErrRegistrationNotFound, eventId)
+	}
+	return nil
+}
+
+// ListUserRegistrations returns all registrations of the user, the newest first.
+func (r *RegistrationRepository) ListUserRegistrations(ctx context.Context, userId int64) ([]model.Registration, error) {
+	registrations := make([]model.Registration, 0)
+	reg := model.Registration{}
+	err := sqlf.From("event_registrations").
+		Select("registration_id, event_id, occurrence_id, user_id, source, created_at").
+		To(&reg.RegistrationID, &reg.EventID, &reg.OccurrenceID, &reg.UserID, &reg.Source, &reg.CreatedAt).
+		Where("user_id = ?", userId).
+		OrderBy("created_at DESC").
+		QueryAndClose(ctx, r.db, func(rows *sql.Rows) {
+			registrations = append(registrations, reg)
+		})
+	if err != nil {
+		return nil, err
+	}
+	return registrations, nil
+}
+
+// ListRegistrants returns users registered for the event or any of its occurrences.
+// Banned and erased users are skipped.
+func (r *RegistrationRepository) ListRegistrants(ctx context.Context, eventId int64) ([]model.User, error) {
+	return r.listUsers(ctx, "user_id IN (SELECT user_id FROM event_registrations WHERE event_id = ?)", eventId)
+}
+
+// ListOccurrenceRegistrants returns users registered for the occurrence. Banned and erased users are skipped.
+func (r *RegistrationRepository) ListOccurrenceRegistrants(ctx context.Context, occurrenceId int64) ([]model.User, error) {
+	return r.listUsers(ctx, "user_id IN (SELECT user_id FROM event_registrations WHERE occurrence_id = ?)", occurrenceId)
+}
+
+func (r *RegistrationRepository) listUsers(ctx context.Context, where string, args ...interface{}) ([]model.User, error) {
+	var users []model.User
+	u := model.User{}
+	err := selectUser(&u).
+		Where(where, args...).
+		Where("banned_at IS NULL").
+		Where("erased_at IS NULL").
+		OrderBy("user_id").
+		QueryAndClose(ctx, r.db, func(rows *sql.Rows) {
+			users = append(users, u)
+		})
+	if err != nil {
+		return nil, err
+	}
+	return users, nil
+}
@@ -0,0 +1,122 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/burenotti/rtu-it-lab-recruit/model"
+	"github.com/leporo/sqlf"
+	"time"
+)
+
+var (
+	ErrReminderNotFound = errors.New("reminder not found")
+)
+
+type ReminderRepository struct {
+	db DatabaseWrapper
+}
+
+func NewReminderRepository(db DatabaseWrapper) *ReminderRepository {
+	return &ReminderRepository{db: db}
+}
+
+// PlanReminders creates reminders of published events for every offset. Recurring events get reminders
+// for every upcoming occurrence instead. Existing reminders are kept as is, so it is safe to call it repeatedly.
+// Reminders whose time has already passed are not created.
+func (r *ReminderRepository) PlanReminders(ctx context.Context, offsets []time.Duration) error {
+	for _, offset := range offsets {
+		if err := r.plan(ctx, offset, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReplanEventReminders drops reminders of the event and its occurrences, including sent ones, and plans them again.
+// Use it when the event time is changed, so registrants are reminded about the new time.
+// Reminders of occurrences moved from another series are dropped as well.
+func (r *ReminderRepository) ReplanEventReminders(ctx context.Context, eventId int64, offsets []time.Duration) error {
+	_, err := sqlf.DeleteFrom("event_reminders").
+		Where("(event_id = ? OR occurrence_id IN (SELECT occurrence_id FROM event_occurrences WHERE event_id = ?))",
+			eventId, eventId).
+		ExecAndClose(ctx, r.db)
+	if err != nil {
+		return err
+	}
+	for _, offset := range offsets {
+		if err := r.plan(ctx, offset, &eventId); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *ReminderRepository) plan(ctx context.Context, offset time.Duration, eventId *int64) error {
+	seconds := int64(offset.Seconds())
+	now := time.Now().UTC()
+	stmt := sqlf.New("INSERT INTO event_reminders (event_id, offset_seconds, remind_at)").
+		Expr("SELECT event_id, ?::int8, begins_at - make_interval(secs => ?) FROM events", seconds, seconds).
+		Where("published_at IS NOT NULL").
+		Where("hidden_at IS NULL").
+		Where("cancelled_at IS NULL").
+		Where("recurrence_rule IS NULL").
+		Where("begins_at - make_interval(secs => ?) > ?", seconds, now)
+	if eventId != nil {
+		stmt = stmt.Where("event_id = ?", *eventId)
+	}
+	_, err := stmt.
+		Clause("ON CONFLICT (event_id, offset_seconds) WHERE occurrence_id IS NULL DO NOTHING").
+		ExecAndClose(ctx, r.db)
+	if err != nil {
+		return err
+	}
+
+	stmt = sqlf.New("INSERT INTO event_reminders (event_id, occurrence_id, offset_seconds, remind_at)").
+		Expr("SELECT o.event_id, o.occurrence_id, ?::int8, o.begins_at - make_interval(secs => ?) "+
+			"FROM event_occurrences o JOIN events e ON e.event_id = o.event_id", seconds, seconds).
+		Where("e.published_at IS NOT NULL").
+		Where("e.hidden_at IS NULL").
+		Where("e.cancelled_at IS NULL").
+		Where("o.cancelled_at IS NULL").
+		Where("o.begins_at - make_interval(secs => ?) > ?", seconds, now)
+	if eventId != nil {
+		stmt = stmt.Where("o.event_id = ?", *eventId)
+	}
+	_, err = stmt.
+		Clause("ON CONFLICT (occurrence_id, offset_seconds) WHERE occurrence_id IS NOT NULL DO NOTHING").
+		ExecAndClose(ctx, r.db)
+	return err
+}
+
+// ClaimDueReminder locks a reminder which is due to be sent. Locked reminders are skipped by other replicas,
+// so the reminder is sent once. It must be called in transaction.
+func (r *ReminderRepository) ClaimDueReminder(ctx context.Context) (*model.EventReminder, error) {
+	reminder := &model.EventReminder{}
+	var offsetSeconds int64
+	err := sqlf.From("event_reminders").
+		Select("reminder_id, event_id, occurrence_id, offset_seconds, remind_at, sent_at").
+		To(&reminder.ReminderID, &reminder.EventID, &reminder.OccurrenceID, &offsetSeconds, &reminder.RemindAt, &reminder.SentAt).
+		Where("sent_at IS NULL").
+		Where("remind_at <= ?", time.Now().UTC()).
+		OrderBy("remind_at").
+		Limit(1).
+		Clause("FOR UPDATE SKIP LOCKED").
+		QueryRowAndClose(ctx, r.db)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrReminderNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	reminder.Offset = time.Duration(offsetSeconds) * time.Second
+	return reminder, nil
+}
+
+func (r *ReminderRepository) MarkReminderSent(ctx context.Context, reminderId int64) error {
+	_, err := sqlf.Update("event_reminders").
+		Set("sent_at", time.Now().UTC()).
+		Where("reminder_id = ?", reminderId).
+		ExecAndClose(ctx, r.db)
+	return err
+}
@@ -0,0 +1,233 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/burenotti/rtu-it-lab-recruit/model"
+	"github.com/leporo/sqlf"
+	"time"
+)
+
+var (
+	ErrSavedSearchNotFound    = errors.New("saved search does not exist")
+	ErrSearchAlertJobNotFound = errors.New("search alert job not found")
+)
+
+var savedSearchUpdatesValidator = NewUpdatesValidator([]string{"name", "alerts_enabled"})
+
+type SavedSearchRepository struct {
+	db DatabaseWrapper
+}
+
+func NewSavedSearchRepository(db DatabaseWrapper) *SavedSearchRepository {
+	return &SavedSearchRepository{db: db}
+}
+
+// savedSearchRow is a saved search with the filter not decoded yet.
+type savedSearchRow struct {
+	model.SavedSearch
+	filter []byte
+}
+
+func (row *savedSearchRow) decode() (model.SavedSearch, error) {
+	search := row.SavedSearch
+	search.Filter = model.SearchFilter{}
+	err := json.Unmarshal(row.filter, &search.Filter)
+	return search, err
+}
+
+func (r *SavedSearchRepository) CreateSearch(ctx context.Context, search *model.SavedSearch) (*model.SavedSearch, error) {
+	filter, err := json.Marshal(search.Filter)
+	if err != nil {
+		return nil, err
+	}
+	row := &savedSearchRow{}
+	err = returningSavedSearch(sqlf.InsertInto("saved_searches").
+		Set("user_id", search.UserID).
+		Set("name", search.Name).
+		Set("filter", string(filter)).
+		Set("alerts_enabled", search.AlertsEnabled).
+		Set("unsubscribe_token", search.UnsubscribeToken), row).
+		QueryRowAndClose(ctx, r.db)
+	if err != nil {
+		return nil, err
+	}
+	return decodeSavedSearch(row)
+}
+
+func (r *SavedSearchRepository) ListSearches(ctx context.Context, userId int64) ([]model.SavedSearch, error) {
+	row := &savedSearchRow{}
+	return r.list(ctx, row, selectSavedSearch(row).
+		Where("user_id = ?", userId).
+		OrderBy("search_id"))
+}
+
+func (r *SavedSearchRepository) GetSearch(ctx context.Context, userId, searchId int64) (*model.SavedSearch, error) {
+	row := &savedSearchRow{}
+	err := selectSavedSearch(row).
+		Where("search_id = ?", searchId).
+		Where("user_id = ?", userId).
+		QueryRowAndClose(ctx, r.db)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: search with provided id does not exist", ErrSavedSearchNotFound)
+	} else if err != nil {
+		return nil, err
+	}
+	return decodeSavedSearch(row)
+}
+
+func (r *SavedSearchRepository) UpdateSearch(
+	ctx context.Context,
+	userId, searchId int64,
+	updates map[string]interface{},
+) (*model.SavedSearch, error) {
+	if err := savedSearchUpdatesValidator.Validate(updates); err != nil {
+		return nil, err
+	}
+	row := &savedSearchRow{}
+	query := sqlf.Update("saved_searches").
+		Where("search_id = ?", searchId).
+		Where("user_id = ?", userId)
+	for field, value := range updates {
+		query = query.Set(field, value)
+	}
+	err := returningSavedSearch(query, row).QueryRowAndClose(ctx, r.db)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: search with provided id does not exist", ErrSavedSearchNotFound)
+	} else if err != nil {
+		return nil, err
+	}
+	return decodeSavedSearch(row)
+}
+
+func (r *SavedSearchRepository) DeleteSearch(ctx context.Context, userId, searchId int64) error {
+	res, err := sqlf.DeleteFrom("saved_searches").
+		Where("search_id = ?", searchId).
+		Where("user_id = ?", userId).
+		ExecAndClose(ctx, r.db)
+	if err != nil {
+		return err
+	}
+	if affected, err := res.RowsAffected(); err != nil {
+		return err
+	} else if affected == 0 {
+		return fmt.Errorf("%w: search with provided id does not exist", ErrSavedSearchNotFound)
+	}
+	return nil
+}
+
+// DisableAlertsByToken turns off alerts of the search the unsubscribe token was issued for.
+// Disabling alerts again is not an error, so the unsubscribe link may be followed several times.
+func (r *SavedSearchRepository) DisableAlertsByToken(ctx context.Context, token string) (*model.SavedSearch, error) {
+	row := &savedSearchRow{}
+	err := returningSavedSearch(sqlf.Update("saved_searches").
+		Set("alerts_enabled", false).
+		Where("unsubscribe_token = ?", token), row).
+		QueryRowAndClose(ctx, r.db)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: unsubscribe link is invalid", ErrSavedSearchNotFound)
+	} else if err != nil {
+		return nil, err
+	}
+	return decodeSavedSearch(row)
+}
+
+// ListAlertSearches returns searches with enabled alerts with id greater than afterSearchId ordered by id.
+// Searches of banned and erased users are skipped.
+func (r *SavedSearchRepository) ListAlertSearches(ctx context.Context, afterSearchId int64, limit int) ([]model.SavedSearch, error) {
+	row := &savedSearchRow{}
+	return r.list(ctx, row, selectSavedSearch(row).
+		Where("alerts_enabled").
+		Where("search_id > ?", afterSearchId).
+		Where("user_id IN (SELECT user_id FROM users WHERE banned_at IS NULL AND erased_at IS NULL)").
+		OrderBy("search_id").
+		Limit(limit))
+}
+
+// ScheduleSearchAlerts schedules matching of the event against saved searches. The event is matched once.
+func (r *SavedSearchRepository) ScheduleSearchAlerts(ctx context.Context, eventId int64) error {
+	_, err := sqlf.InsertInto("search_alert_jobs").
+		Set("event_id", eventId).
+		Clause("ON CONFLICT (event_id) DO NOTHING").
+		ExecAndClose(ctx, r.db)
+	return err
+}
+
+// ClaimSearchAlertJob locks pending job, so other replicas skip it. It must be called in transaction.
+func (r *SavedSearchRepository) ClaimSearchAlertJob(ctx context.Context) (*model.SearchAlertJob, error) {
+	j := &model.SearchAlertJob{}
+	err := sqlf.From("search_alert_jobs").
+		Select("job_id, event_id, last_search_id").
+		To(&j.JobID, &j.EventID, &j.LastSearchID).
+		Where("done_at IS NULL").
+		OrderBy("job_id").
+		Limit(1).
+		Clause("FOR UPDATE SKIP LOCKED").
+		QueryRowAndClose(ctx, r.db)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrSearchAlertJobNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	return j, nil
+}
+
+func (r *SavedSearchRepository) AdvanceSearchAlertJob(ctx context.Context, jobId, lastSearchId int64) error {
+	_, err := sqlf.Update("search_alert_jobs").
+		Set("last_search_id", lastSearchId).
+		Where("job_id = ?", jobId).
+		ExecAndClose(ctx, r.db)
+	return err
+}
+
+func (r *SavedSearchRepository) CompleteSearchAlertJob(ctx context.Context, jobId int64) error {
+	_, err := sqlf.Update("search_alert_jobs").
+		Set("done_at", time.Now().UTC()).
+		Where("job_id = ?", jobId).
+		ExecAndClose(ctx, r.db)
+	return err
+}
+
+// list runs the query selecting saved searches into the row.
+func (r *SavedSearchRepository) list(ctx context.Context, row *savedSearchRow, query *sqlf.Stmt) ([]model.SavedSearch, error) {
+	var searches []model.SavedSearch
+	var decodeErr error
+	err := query.QueryAndClose(ctx, r.db, func(rows *sql.Rows) {
+		search, err := row.decode()
+		if err != nil {
+			decodeErr = err
+			return
+		}
+		searches = append(searches, search)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return searches, decodeErr
+}
+
+func decodeSavedSearch(row *savedSearchRow) (*model.SavedSearch, error) {
+	search, err := row.decode()
+	if err != nil {
+		return nil, err
+	}
+	return &search, nil
+}
+
+func selectSavedSearch(row *savedSearchRow) *sqlf.Stmt {
+	return sqlf.From("saved_searches").
+		Select("search_id, user_id, name, filter").To(&row.SearchID, &row.UserID, &row.Name, &row.filter).
+		Select("alerts_enabled, unsubscribe_token, created_at").
+		To(&row.AlertsEnabled, &row.UnsubscribeToken, &row.CreatedAt)
+}
+
+func returningSavedSearch(query *sqlf.Stmt, row *savedSearchRow) *sqlf.Stmt {
+	return query.
+		Returning("search_id, user_id, name, filter").To(&row.SearchID, &row.UserID, &row.Name, &row.filter).
+		Returning("alerts_enabled, unsubscribe_token, created_at").
+		To(&row.AlertsEnabled, &row.UnsubscribeToken, &row.CreatedAt)
+}
@@ -0,0 +1,106 @@
+package repositories
+
+import (
+	"context"
+	"github.com/burenotti/rtu-it-lab-recruit/model"
+	"github.com/go-faker/faker/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+)
+
+type SavedSearchRepositoryTestSuite struct {
+	DBTestSuite
+}
+
+func TestSavedSearchRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, &SavedSearchRepositoryTestSuite{
+		*DBTestSuiteFromEnv(),
+	})
+}
+
+func (s *SavedSearchRepositoryTestSuite) createSearch(ctx context.Context, repo *SavedSearchRepository, userId int64) *model.SavedSearch {
+	search, err := repo.CreateSearch(ctx, &model.SavedSearch{
+		UserID:           userId,
+		Name:             faker.Name(),
+		AlertsEnabled:    true,
+		UnsubscribeToken: faker.UUIDDigit(),
+	})
+	require.NoError(s.T(), err, "should create search without errors")
+	return search
+}
+
+func (s *SavedSearchRepositoryTestSuite) TestSearchAlertJobFlow() {
+	ctx := context.Background()
+	db := NewDatabase(s.db)
+	repo := NewSavedSearchRepository(db)
+	user := CreateRandomUser(ctx, db, s.T())
+	event := CreateRandomEvent(ctx, db, s.T(), user.UserID, time.Now().Add(24*time.Hour))
+
+	require.NoError(s.T(), repo.ScheduleSearchAlerts(ctx, event.EventID))
+	require.NoError(s.T(), repo.ScheduleSearchAlerts(ctx, event.EventID), "scheduling event again should be ignored")
+
+	var jobId int64
+	err := db.Atomic(ctx, func(ctx context.Context) error {
+		job, err := repo.ClaimSearchAlertJob(ctx)
+		if err != nil {
+			return err
+		}
+		assert.Equal(s.T(), event.EventID, job.EventID)
+		assert.Zero(s.T(), job.LastSearchID, "new job should start from the first search")
+		jobId = job.JobID
+		return repo.AdvanceSearchAlertJob(ctx, job.JobID, 42)
+	})
+	require.NoError(s.T(), err, "should claim and advance job")
+
+	err = db.Atomic(ctx, func(ctx context.Context) error {
+		job, err := repo.ClaimSearchAlertJob(ctx)
+		if err != nil {
+			return err
+		}
+		assert.Equal(s.T(), jobId, job.JobID, "unfinished job should be claimed again")
+		assert.Equal(s.T(), int64(42), job.LastSearchID, "job should resume after the last matched search")
+		return repo.CompleteSearchAlertJob(ctx, job.JobID)
+	})
+	require.NoError(s.T(), err, "should claim and complete job")
+
+	err = db.Atomic(ctx, func(ctx context.Context) error {
+		_, err := repo.ClaimSearchAlertJob(ctx)
+		return err
+	})
+	assert.ErrorIs(s.T(), err, ErrSearchAlertJobNotFound, "completed job should not be claimed")
+
+	require.NoError(s.T(), repo.ScheduleSearchAlerts(ctx, event.EventID))
+	err = db.Atomic(ctx, func(ctx context.Context) error {
+		_, err := repo.ClaimSearchAlertJob(ctx)
+		return err
+	})
+	assert.ErrorIs(s.T(), err, ErrSearchAlertJobNotFound, "matched event should not be scheduled again")
+}
+
+func (s *SavedSearchRepositoryTestSuite) TestDisableAlertsByToken() {
+	ctx := context.Background()
+	db := NewDatabase(s.db)
+	repo := NewSavedSearchRepository(db)
+	user := CreateRandomUser(ctx, db, s.T())
+	search := s.createSearch(ctx, repo, user.UserID)
+	other := s.createSearch(ctx, repo, user.UserID)
+
+	disabled, err := repo.DisableAlertsByToken(ctx, search.UnsubscribeToken)
+	require.NoError(s.T(), err, "should disable alerts without errors")
+	assert.Equal(s.T(), search.SearchID, disabled.SearchID)
+	assert.False(s.T(), disabled.AlertsEnabled)
+
+	_, err = repo.DisableAlertsByToken(ctx, search.UnsubscribeToken)
+	assert.NoError(s.T(), err, "following unsubscribe link again should not fail")
+
+	_, err = repo.DisableAlertsByToken(ctx, faker.UUIDDigit())
+	assert.ErrorIs(s.T(), err, ErrSavedSearchNotFound, "unknown token should be rejected")
+
+	searches, err := repo.ListAlertSearches(ctx, search.SearchID-1, 2)
+	require.NoError(s.T(), err)
+	require.Len(s.T(), searches, 1, "only search with enabled alerts should be listed")
+	assert.Equal(s.T(), other.SearchID, searches[0].SearchID)
+}
@@ -0,0 +1,79 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"github.com/burenotti/rtu-it-lab-recruit/model"
+	"github.com/leporo/sqlf"
+	"time"
+)
+
+type TagRepository struct {
+	db DatabaseWrapper
+}
+
+func NewTagRepository(db DatabaseWrapper) *TagRepository {
+	return &TagRepository{db: db}
+}
+
+// SetTags replaces tags of the event. Tags must be normalized and unique.
+func (r *TagRepository) SetTags(ctx context.Context, eventId int64, tags []string) error {
+	_, err := sqlf.DeleteFrom("event_tags").
+		Where("event_id = ?", eventId).
+		ExecAndClose(ctx, r.db)
+	if err != nil {
+		return err
+	}
+	if len(tags) == 0 {
+		return nil
+	}
+	_, err = sqlf.New("INSERT INTO event_tags (event_id, tag) SELECT ?, unnest(?::text[])", eventId, tags).
+		ExecAndClose(ctx, r.db)
+	if getViolatedConstraint(err) == "event_tags_event_id_fkey" {
+		return ErrEventNotFount
+	}
+	return err
+}
+
+// ListTags returns tags of the events ordered alphabetically. Events without tags are missing in the result.
+func (r *TagRepository) ListTags(ctx context.Context, eventIds []int64) (map[int64][]string, error) {
+	tags := make(map[int64][]string, len(eventIds))
+	if len(eventIds) == 0 {
+		return tags, nil
+	}
+	var eventId int64
+	var tag string
+	err := sqlf.From("event_tags").
+		Select("event_id, tag").To(&eventId, &tag).
+		Where("event_id = ANY(?)", eventIds).
+		OrderBy("event_id, tag").
+		QueryAndClose(ctx, r.db, func(rows *sql.Rows) {
+			tags[eventId] = append(tags[eventId], tag)
+		})
+	if err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+// SuggestTags returns the most used tags starting with the prefix among published events beginning after since.
+func (r *TagRepository) SuggestTags(ctx context.Context, prefix string, since time.Time, limit int) ([]model.TagSuggestion, error) {
+	s := model.TagSuggestion{}
+	suggestions := make([]model.TagSuggestion, 0)
+	err := sqlf.From("event_tags t").
+		Select("t.tag, count(*)").To(&s.Tag, &s.Events).
+		Join("events e", "e.event_id = t.event_id").
+		Where("t.tag LIKE ?", likeEscaper.Replace(prefix)+"%").
+		Where("e.published_at IS NOT NULL AND e.hidden_at IS NULL AND e.cancelled_at IS NULL").
+		Where("e.begins_at >= ?", since).
+		GroupBy("t.tag").
+		OrderBy("count(*) DESC, t.tag").
+		Limit(limit).
+		QueryAndClose(ctx, r.db, func(rows *sql.Rows) {
+			suggestions = append(suggestions, s)
+		})
+	if err != nil {
+		return nil, err
+	}
+	return suggestions, nil
+}
@@ -0,0 +1,254 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/burenotti/rtu-it-lab-recruit/model"
+	"github.com/leporo/sqlf"
+)
+
+var (
+	ErrTicketTypeNotFound = errors.New("ticket type does not exist")
+	ErrTicketTypeInUse    = errors.New("ticket type has orders")
+	ErrPromoCodeNotFound  = errors.New("promo code does not exist")
+	ErrPromoCodeExists    = errors.New("promo code already exists")
+)
+
+// Paid and reserved orders hold tickets and uses of promo codes. Reserved orders hold them until they are
+// actually expired rather than until ExpiresAt, so a payment made just before expiration never oversells tickets.
+const (
+	heldTicketsColumn = "COALESCE((SELECT SUM(o.quantity) FROM orders o " +
+		"WHERE o.ticket_type_id = ticket_types.ticket_type_id AND o.status IN ('paid', 'reserved')), 0)"
+	promoCodeUsesColumn = "(SELECT COUNT(*) FROM orders o " +
+		"WHERE o.promo_code_id = promo_codes.promo_code_id AND o.status IN ('paid', 'reserved'))"
+)
+
+const ticketTypeColumns = "ticket_type_id, event_id, name, price, currency, quantity, " +
+	heldTicketsColumn + ", quantity - " + heldTicketsColumn + ", sales_begin, sales_end, created_at"
+
+const promoCodeColumns = "promo_code_id, event_id, code, kind, amount, currency, max_uses, " +
+	promoCodeUsesColumn + ", expires_at, created_at"
+
+var ticketTypeUpdatesValidator = NewUpdatesValidator([]string{
+	"name", "price", "quantity", "sales_begin", "sales_end",
+})
+
+type TicketRepository struct {
+	db DatabaseWrapper
+}
+
+func NewTicketRepository(db DatabaseWrapper) *TicketRepository {
+	return &TicketRepository{db: db}
+}
+
+func (r *TicketRepository) CreateTicketType(ctx context.Context, eventId int64, create *model.TicketTypeCreate) (*model.TicketType, error) {
+	t := &model.TicketType{}
+	err := returningTicketType(sqlf.InsertInto("ticket_types").
+		Set("event_id", eventId).
+		Set("name", create.Name).
+		Set("price", create.Price).
+		Set("currency", create.Currency).
+		Set("quantity", create.Quantity).
+		Set("sales_begin", create.SalesBegin).
+		Set("sales_end", create.SalesEnd), t).
+		QueryRowAndClose(ctx, r.db)
+	if getViolatedConstraint(err) == "ticket_types_event_id_fkey" {
+		return nil, fmt.Errorf("%w: event with provided id does not exist", ErrEventNotFount)
+	} else if err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func (r *TicketRepository) GetTicketType(ctx context.Context, ticketTypeId int64) (*model.TicketType, error) {
+	t := &model.TicketType{}
+	err := selectTicketType(t).
+		Where("ticket_type_id = ?", ticketTypeId).
+		QueryRowAndClose(ctx, r.db)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: ticket type with provided id does not exist", ErrTicketTypeNotFound)
+	} else if err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// LockTicketType locks the ticket type until the end of transaction, so tickets are not oversold
+// by concurrent orders. Tickets available must be read after locking, by a separate query.
+func (r *TicketRepository) LockTicketType(ctx context.Context, ticketTypeId int64) error {
+	var id int64
+	err := sqlf.From("ticket_types").
+		Select("ticket_type_id").To(&id).
+		Where("ticket_type_id = ?", ticketTypeId).
+		Clause("FOR UPDATE").
+		QueryRowAndClose(ctx, r.db)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("%w: ticket type with provided id does not exist", ErrTicketTypeNotFound)
+	}
+	return err
+}
+
+func (r *TicketRepository) ListTicketTypes(ctx context.Context, eventId int64) ([]model.TicketType, error) {
+	t := model.TicketType{}
+	types := make([]model.TicketType, 0)
+	err := selectTicketType(&t).
+		Where("event_id = ?", eventId).
+		OrderBy("price, ticket_type_id").
+		QueryAndClose(ctx, r.db, func(rows *sql.Rows) {
+			types = append(types, t)
+		})
+	if err != nil {
+		return nil, err
+	}
+	return types, nil
+}
+
+func (r *TicketRepository) UpdateTicketType(ctx context.Context, ticketTypeId int64, updates map[string]interface{}) (*model.TicketType, error) {
+	if err := ticketTypeUpdatesValidator.Validate(updates); err != nil {
+		return nil, err
+	}
+	t := &model.TicketType{}
+	query := sqlf.Update("ticket_types").
+		Where("ticket_type_id = ?", ticketTypeId)
+	for field, value := range updates {
+		query = query.Set(field, value)
+	}
+	err := returningTicketType(query, t).QueryRowAndClose(ctx, r.db)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: ticket type with provided id does not exist", ErrTicketTypeNotFound)
+	} else if err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func (r *TicketRepository) DeleteTicketType(ctx context.Context, ticketTypeId int64) error {
+	res, err := sqlf.DeleteFrom("ticket_types").
+		Where("ticket_type_id = ?", ticketTypeId).
+		ExecAndClose(ctx, r.db)
+	if getViolatedConstraint(err) == "orders_ticket_type_id_fkey" {
+		return fmt.Errorf("%w: tickets of the type are ordered, it can't be deleted", ErrTicketTypeInUse)
+	} else if err != nil {
+		return err
+	}
+	if affected, err := res.RowsAffected(); err != nil {
+		return err
+	} else if affected == 0 {
+		return fmt.Errorf("%w: ticket type with provided id does not exist", ErrTicketTypeNotFound)
+	}
+	return nil
+}
+
+func (r *TicketRepository) CreatePromoCode(ctx context.Context, eventId int64, create *model.PromoCodeCreate) (*model.PromoCode, error) {
+	p := &model.PromoCode{}
+	err := returningPromoCode(sqlf.InsertInto("promo_codes").
+		Set("event_id", eventId).
+		Set("code", create.Code).
+		Set("kind", create.Kind).
+		Set("amount", create.Amount).
+		Set("currency", create.Currency).
+		Set("max_uses", create.MaxUses).
+		Set("expires_at", create.ExpiresAt), p).
+		QueryRowAndClose(ctx, r.db)
+	if getViolatedConstraint(err) == "unique_promo_codes_code" {
+		return nil, fmt.Errorf("%w: event already has promo code %s", ErrPromoCodeExists, create.Code)
+	} else if getViolatedConstraint(err) == "promo_codes_event_id_fkey" {
+		return nil, fmt.Errorf("%w: event with provided id does not exist", ErrEventNotFount)
+	} else if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (r *TicketRepository) GetPromoCode(ctx context.Context, promoCodeId int64) (*model.PromoCode, error) {
+	p := &model.PromoCode{}
+	err := selectPromoCode(p).
+		Where("promo_code_id = ?", promoCodeId).
+		QueryRowAndClose(ctx, r.db)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: promo code with provided id does not exist", ErrPromoCodeNotFound)
+	} else if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// LockPromoCode locks the promo code of the event until the end of transaction and returns its id,
+// so its uses are not exceeded by concurrent orders. Uses must be read after locking, by a separate query.
+func (r *TicketRepository) LockPromoCode(ctx context.Context, eventId int64, code string) (int64, error) {
+	var id int64
+	err := sqlf.From("promo_codes").
+		Select("promo_code_id").To(&id).
+		Where("event_id = ?", eventId).
+		Where("code = ?", code).
+		Clause("FOR UPDATE").
+		QueryRowAndClose(ctx, r.db)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, fmt.Errorf("%w: promo code %s does not exist", ErrPromoCodeNotFound, code)
+	} else if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+func (r *TicketRepository) ListPromoCodes(ctx context.Context, eventId int64) ([]model.PromoCode, error) {
+	p := model.PromoCode{}
+	codes := make([]model.PromoCode, 0)
+	err := selectPromoCode(&p).
+		Where("event_id = ?", eventId).
+		OrderBy("promo_code_id").
+		QueryAndClose(ctx, r.db, func(rows *sql.Rows) {
+			codes = append(codes, p)
+		})
+	if err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// DeletePromoCode deletes the promo code, orders with it keep their discounts.
+func (r *TicketRepository) DeletePromoCode(ctx context.Context, eventId, promoCodeId int64) error {
+	res, err := sqlf.DeleteFrom("promo_codes").
+		Where("promo_code_id = ?", promoCodeId).
+		Where("event_id = ?", eventId).
+		ExecAndClose(ctx, r.db)
+	if err != nil {
+		return err
+	}
+	if affected, err := res.RowsAffected(); err != nil {
+		return err
+	} else if affected == 0 {
+		return fmt.Errorf("%w: promo code with provided id does not exist", ErrPromoCodeNotFound)
+	}
+	return nil
+}
+
+func selectTicketType(t *model.TicketType) *sqlf.Stmt {
+	return sqlf.From("ticket_types").
+		Select(ticketTypeColumns).
+		To(&t.TicketTypeID, &t.EventID, &t.Name, &t.Price, &t.Currency, &t.Quantity, &t.Sold, &t.Available,
+			&t.SalesBegin, &t.SalesEnd, &t.CreatedAt)
+}
+
+func returningTicketType(query *sqlf.Stmt, t *model.TicketType) *sqlf.Stmt {
+	return query.
+		Returning(ticketTypeColumns).
+		To(&t.TicketTypeID, &t.EventID, &t.Name, &t.Price, &t.Currency, &t.Quantity, &t.Sold, &t.Available,
+			&t.SalesBegin, &t.SalesEnd, &t.CreatedAt)
+}
+
+func selectPromoCode(p *model.PromoCode) *sqlf.Stmt {
+	return sqlf.From("promo_codes").
+		Select(promoCodeColumns).
+		To(&p.PromoCodeID, &p.EventID, &p.Code, &p.Kind, &p.Amount, &p.Currency, &p.MaxUses, &p.Used,
+			&p.ExpiresAt, &p.CreatedAt)
+}
+
+func returningPromoCode(query *sqlf.Stmt, p *model.PromoCode) *sqlf.Stmt {
+	return query.
+		Returning(promoCodeColumns).
+		To(&p.PromoCodeID, &p.EventID, &p.Code, &p.Kind, &p.Amount, &p.Currency, &p.MaxUses, &p.Used,
+			&p.ExpiresAt, &p.CreatedAt)
+}
@@ -0,0 +1,146 @@
+package repositories
+
+import (
+	"context"
+	"github.com/burenotti/rtu-it-lab-recruit/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+)
+
+type TicketRepositoryTestSuite struct {
+	DBTestSuite
+}
+
+func TestTicketRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, &TicketRepositoryTestSuite{
+		*DBTestSuiteFromEnv(),
+	})
+}
+
+func (s *TicketRepositoryTestSuite) createTicketType(ctx context.Context, repo *TicketRepository, quantity *int) *model.TicketType {
+	db := NewDatabase(s.db)
+	user := CreateRandomUser(ctx, db, s.T())
+	event := CreateRandomEvent(ctx, db, s.T(), user.UserID, time.Now().Add(48*time.Hour))
+	ticketType, err := repo.CreateTicketType(ctx, event.EventID, &model.TicketTypeCreate{
+		Name: "Standard", Price: 1000, Currency: "RUB", Quantity: quantity,
+	})
+	require.NoError(s.T(), err, "should create ticket type without errors")
+	return ticketType
+}
+
+func (s *TicketRepositoryTestSuite) TestLockTicketType() {
+	ctx := context.Background()
+	db := NewDatabase(s.db)
+	repo := NewTicketRepository(db)
+	ticketType := s.createTicketType(ctx, repo, nil)
+
+	err := db.Atomic(ctx, func(ctx context.Context) error {
+		if err := repo.LockTicketType(ctx, ticketType.TicketTypeID); err != nil {
+			return err
+		}
+		// Concurrent orders lock the ticket type from other connections.
+		_, err := s.db.ExecContext(context.Background(),
+			"SELECT 1 FROM ticket_types WHERE ticket_type_id = $1 FOR UPDATE NOWAIT", ticketType.TicketTypeID)
+		assert.Error(s.T(), err, "locked ticket type should not be locked by other transaction")
+		return nil
+	})
+	require.NoError(s.T(), err)
+
+	_, err = s.db.ExecContext(ctx,
+		"SELECT 1 FROM ticket_types WHERE ticket_type_id = $1 FOR UPDATE NOWAIT", ticketType.TicketTypeID)
+	assert.NoError(s.T(), err, "ticket type should be unlocked after transaction")
+
+	err = db.Atomic(ctx, func(ctx context.Context) error {
+		return repo.LockTicketType(ctx, ticketType.TicketTypeID+1000)
+	})
+	assert.ErrorIs(s.T(), err, ErrTicketTypeNotFound)
+}
+
+func (s *TicketRepositoryTestSuite) TestHeldTickets() {
+	ctx := context.Background()
+	db := NewDatabase(s.db)
+	repo := NewTicketRepository(db)
+	quantity := 10
+	ticketType := s.createTicketType(ctx, repo, &quantity)
+	unlimited := s.createTicketType(ctx, repo, nil)
+
+	CreateRandomOrder(ctx, db, s.T(), ticketType, nil, model.OrderReserved)
+	CreateRandomOrder(ctx, db, s.T(), ticketType, nil, model.OrderPaid)
+	CreateRandomOrder(ctx, db, s.T(), ticketType, nil, model.OrderExpired)
+	CreateRandomOrder(ctx, db, s.T(), ticketType, nil, model.OrderCancelled)
+	CreateRandomOrder(ctx, db, s.T(), unlimited, nil, model.OrderPaid)
+
+	got, err := repo.GetTicketType(ctx, ticketType.TicketTypeID)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), 2, got.Sold, "only paid and reserved orders should hold tickets")
+	require.NotNil(s.T(), got.Available)
+	assert.Equal(s.T(), 8, *got.Available)
+
+	got, err = repo.GetTicketType(ctx, unlimited.TicketTypeID)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), 1, got.Sold)
+	assert.Nil(s.T(), got.Available, "unlimited tickets should not have availability")
+}
+
+func (s *TicketRepositoryTestSuite) TestPromoCodeUses() {
+	ctx := context.Background()
+	db := NewDatabase(s.db)
+	repo := NewTicketRepository(db)
+	ticketType := s.createTicketType(ctx, repo, nil)
+	maxUses := 5
+	promoCode, err := repo.CreatePromoCode(ctx, ticketType.EventID, &model.PromoCodeCreate{
+		Code: "EARLY", Kind: model.PromoCodePercent, Amount: 20, MaxUses: &maxUses,
+	})
+	require.NoError(s.T(), err, "should create promo code without errors")
+
+	_, err = repo.CreatePromoCode(ctx, ticketType.EventID, &model.PromoCodeCreate{
+		Code: "EARLY", Kind: model.PromoCodePercent, Amount: 10,
+	})
+	assert.ErrorIs(s.T(), err, ErrPromoCodeExists)
+
+	CreateRandomOrder(ctx, db, s.T(), ticketType, &promoCode.PromoCodeID, model.OrderReserved)
+	CreateRandomOrder(ctx, db, s.T(), ticketType, &promoCode.PromoCodeID, model.OrderPaid)
+	CreateRandomOrder(ctx, db, s.T(), ticketType, &promoCode.PromoCodeID, model.OrderExpired)
+	CreateRandomOrder(ctx, db, s.T(), ticketType, nil, model.OrderPaid)
+
+	err = db.Atomic(ctx, func(ctx context.Context) error {
+		promoCodeId, err := repo.LockPromoCode(ctx, ticketType.EventID, "EARLY")
+		if err != nil {
+			return err
+		}
+		assert.Equal(s.T(), promoCode.PromoCodeID, promoCodeId)
+		got, err := repo.GetPromoCode(ctx, promoCodeId)
+		if err != nil {
+			return err
+		}
+		assert.Equal(s.T(), 2, got.Used, "only paid and reserved orders should use promo code")
+		return nil
+	})
+	require.NoError(s.T(), err)
+
+	err = db.Atomic(ctx, func(ctx context.Context) error {
+		_, err := repo.LockPromoCode(ctx, ticketType.EventID, "LATE")
+		return err
+	})
+	assert.ErrorIs(s.T(), err, ErrPromoCodeNotFound)
+}
+
+func (s *TicketRepositoryTestSuite) TestDeleteTicketType() {
+	ctx := context.Background()
+	db := NewDatabase(s.db)
+	repo := NewTicketRepository(db)
+	ordered := s.createTicketType(ctx, repo, nil)
+	unused := s.createTicketType(ctx, repo, nil)
+	CreateRandomOrder(ctx, db, s.T(), ordered, nil, model.OrderCancelled)
+
+	err := repo.DeleteTicketType(ctx, ordered.TicketTypeID)
+	assert.ErrorIs(s.T(), err, ErrTicketTypeInUse, "ticket type with orders should not be deleted")
+
+	err = repo.DeleteTicketType(ctx, unused.TicketTypeID)
+	assert.NoError(s.T(), err)
+	_, err = repo.GetTicketType(ctx, unused.TicketTypeID)
+	assert.ErrorIs(s.T(), err, ErrTicketTypeNotFound)
+}
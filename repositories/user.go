@@ -7,6 +7,7 @@ import (
 	"github.com/burenotti/rtu-it-lab-recruit/model"
 	"github.com/leporo/sqlf"
 	"github.com/pkg/errors"
+	"time"
 )
 
 const (
@@ -32,12 +33,17 @@ func NewUserRepository(db DatabaseWrapper) *UserRepository {
 
 func (r *UserRepository) Create(ctx context.Context, u *model.UserCreate) (*model.User, error) {
 	var userId int64 = 0
+	locale := u.PreferredLocale
+	if locale == "" {
+		locale = model.DefaultLocale
+	}
 	err := sqlf.InsertInto("users").
 		Set("first_name", u.FirstName).
 		Set("last_name", u.LastName).
 		Set("middle_name", u.MiddleName).
 		Set("email", u.Email).
 		Set("is_active", false).
+		Set("preferred_locale", locale).
 		Returning("user_id").To(&userId).
 		QueryRow(ctx, r.db)
 
@@ -48,25 +54,20 @@ func (r *UserRepository) Create(ctx context.Context, u *model.UserCreate) (*mode
 	}
 
 	user := &model.User{
-		UserID:     userId,
-		FirstName:  u.FirstName,
-		LastName:   u.LastName,
-		MiddleName: u.MiddleName,
-		Email:      u.Email,
-		IsActive:   false,
+		UserID:          userId,
+		FirstName:       u.FirstName,
+		LastName:        u.LastName,
+		MiddleName:      u.MiddleName,
+		Email:           u.Email,
+		IsActive:        false,
+		PreferredLocale: locale,
 	}
 	return user, nil
 }
 
 func (r *UserRepository) GetById(ctx context.Context, userId int64) (*model.User, error) {
 	u := &model.User{}
-	err := sqlf.From("users").
-		Select("user_id").To(&u.UserID).
-		Select("first_name").To(&u.FirstName).
-		Select("last_name").To(&u.LastName).
-		Select("middle_name").To(&u.MiddleName).
-		Select("is_active").To(&u.IsActive).
-		Select("email").To(&u.Email).
+	err := selectUser(u).
 		Where("user_id = ?", userId).
 		QueryRow(ctx, r.db)
 
@@ -81,13 +82,7 @@ func (r *UserRepository) GetById(ctx context.Context, userId int64) (*model.User
 
 func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*model.User, error) {
 	u := &model.User{}
-	err := sqlf.From("users").
-		Select("user_id").To(&u.UserID).
-		Select("first_name").To(&u.FirstName).
-		Select("last_name").To(&u.LastName).
-		Select("middle_name").To(&u.MiddleName).
-		Select("is_active").To(&u.IsActive).
-		Select("email").To(&u.Email).
+	err := selectUser(u).
 		Where("email = ?", email).
 		QueryRow(ctx, r.db)
 
@@ -105,7 +100,9 @@ func (r *UserRepository) Update(ctx context.Context, userId int64, update map[st
 	// Set of columns, which could be updated
 	fields := map[string]struct{}{
 		"first_name": {}, "last_name": {}, "middle_name": {},
-		"email": {}, "is_active": {},
+		"email": {}, "is_active": {}, "is_admin": {},
+		"banned_at": {}, "ban_reason": {}, "preferred_locale": {},
+		"deactivated_at": {},
 	}
 
 	u := &model.User{}
@@ -117,7 +114,9 @@ func (r *UserRepository) Update(ctx context.Context, userId int64, update map[st
 		Returning("last_name").To(&u.LastName).
 		Returning("middle_name").To(&u.MiddleName).
 		Returning("email").To(&u.Email).
-		Returning("is_active").To(&u.IsActive)
+		Returning("is_active").To(&u.IsActive).
+		Returning("is_admin, banned_at, ban_reason, preferred_locale, deactivated_at").
+		To(&u.IsAdmin, &u.BannedAt, &u.BanReason, &u.PreferredLocale, &u.DeactivatedAt)
 
 	for key, upd := range update {
 		if _, ok := fields[key]; !ok {
@@ -154,3 +153,160 @@ func (r *UserRepository) Delete(ctx context.Context, userId int64) error {
 	}
 	return nil
 }
+
+// DeleteInactiveBefore removes users that have never been activated and were created before the given moment.
+// Accounts deactivated by administrators are kept. Returns count of deleted users.
+func (r *UserRepository) DeleteInactiveBefore(ctx context.Context, before time.Time) (int64, error) {
+	res, err := sqlf.DeleteFrom("users").
+		Where("is_active = false").
+		Where("deactivated_at IS NULL").
+		Where("erased_at IS NULL").
+		Where("created_at < ?", before).
+		ExecAndClose(ctx, r.db)
+
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// Anonymize erases personal data of the user. The row itself is kept,
+// so historical data (e.g. created events) still references it.
+func (r *UserRepository) Anonymize(ctx context.Context, userId int64) error {
+	res, err := sqlf.Update("users").
+		Set("first_name", "Deleted").
+		Set("last_name", "User").
+		Set("middle_name", "").
+		Set("email", fmt.Sprintf("deleted-%d@erased.invalid", userId)).
+		Set("is_active", false).
+		Set("erased_at", time.Now().UTC()).
+		Where("user_id = ?", userId).
+		Where("erased_at IS NULL").
+		ExecAndClose(ctx, r.db)
+
+	if err != nil {
+		return err
+	}
+	if count, _ := res.RowsAffected(); count == 0 {
+		return fmt.Errorf("%w: user with provided id does not exist", ErrUserNotFound)
+	}
+
+	// Tables that contain nothing but personal data of the user
+	personalTables := []string{
+		"login_code", "activation_requests", "activation_tokens",
+		"organization_members", "data_exports", "message_outbox",
+		"user_channels", "telegram_link_tokens", "notifications",
+		"notification_preferences", "phone_verifications", "phone_verification_requests",
+		"organization_follows", "digest_settings", "calendar_feeds", "saved_searches",
+	}
+	for _, table := range personalTables {
+		_, err = sqlf.DeleteFrom(table).
+			Where("user_id = ?", userId).
+			ExecAndClose(ctx, r.db)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Registrations are kept, so attendance and analytics of organizers don't change, they point to the anonymized
+	// user only. Registrations for events which have not begun and are not checked in are cancelled to free seats.
+	now := time.Now().UTC()
+	_, err = sqlf.DeleteFrom("event_registrations r").
+		Where("r.user_id = ?", userId).
+		Where("NOT EXISTS (SELECT 1 FROM check_ins c WHERE c.registration_id = r.registration_id)").
+		Where("COALESCE("+
+			"(SELECT o.begins_at FROM event_occurrences o WHERE o.occurrence_id = r.occurrence_id), "+
+			"(SELECT e.begins_at FROM events e WHERE e.event_id = r.event_id)) > ?", now).
+		ExecAndClose(ctx, r.db)
+	if err != nil {
+		return err
+	}
+
+	// Imports are kept in the history of the organization, files uploaded by the user are dropped.
+	// Unfinished imports can't go on without the file.
+	_, err = sqlf.Update("event_imports").
+		Set("status", model.EventImportFailed).
+		Set("error", "file was deleted with the account of the user who uploaded it").
+		Set("updated_at", now).
+		Set("finished_at", now).
+		Where("user_id = ?", userId).
+		Where("status IN (?, ?)", model.EventImportPending, model.EventImportRunning).
+		ExecAndClose(ctx, r.db)
+	if err != nil {
+		return err
+	}
+	_, err = sqlf.Update("event_imports").
+		Set("data", []byte{}).
+		Where("user_id = ?", userId).
+		Where("length(data) > 0").
+		ExecAndClose(ctx, r.db)
+	if err != nil {
+		return err
+	}
+
+	// Orders are kept for the accounting of organizations, they point to the anonymized user only.
+	// Reserved ones are cancelled, so their tickets are released, and payment links are dropped.
+	_, err = sqlf.Update("orders").
+		SetExpr("status", "CASE WHEN status = ? THEN ? ELSE status END", model.OrderReserved, model.OrderCancelled).
+		Set("payment_url", nil).
+		Where("user_id = ?", userId).
+		ExecAndClose(ctx, r.db)
+	return err
+}
+
+// Search looks for users with email or name containing the query.
+func (r *UserRepository) Search(ctx context.Context, query string, limit, offset int) ([]model.User, error) {
+	var users []model.User
+	var scanErr error
+	pattern := "%" + query + "%"
+	err := sqlf.From("users").
+		Select("user_id, first_name, last_name, middle_name, email, is_active, is_admin, banned_at, ban_reason, preferred_locale, deactivated_at").
+		Where("(email ILIKE ? OR first_name ILIKE ? OR last_name ILIKE ?)", pattern, pattern, pattern).
+		OrderBy("user_id").
+		Limit(limit).
+		Offset(offset).
+		QueryAndClose(ctx, r.db, func(rows *sql.Rows) {
+			u := model.User{}
+			err := rows.Scan(&u.UserID, &u.FirstName, &u.LastName, &u.MiddleName,
+				&u.Email, &u.IsActive, &u.IsAdmin, &u.BannedAt, &u.BanReason, &u.PreferredLocale, &u.DeactivatedAt)
+			if err != nil {
+				scanErr = err
+				return
+			}
+			users = append(users, u)
+		})
+
+	if err != nil {
+		return nil, err
+	}
+	return users, scanErr
+}
+
+// PromoteAdmins grants platform administrator role to users with provided emails.
+func (r *UserRepository) PromoteAdmins(ctx context.Context, emails []string) (int64, error) {
+	if len(emails) == 0 {
+		return 0, nil
+	}
+	res, err := sqlf.Update("users").
+		Set("is_admin", true).
+		Where("is_admin = false").
+		Where("email = ANY(?)", emails).
+		ExecAndClose(ctx, r.db)
+
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+func selectUser(u *model.User) *sqlf.Stmt {
+	return sqlf.From("users").
+		Select("user_id").To(&u.UserID).
+		Select("first_name").To(&u.FirstName).
+		Select("last_name").To(&u.LastName).
+		Select("middle_name").To(&u.MiddleName).
+		Select("is_active").To(&u.IsActive).
+		Select("email").To(&u.Email).
+		Select("is_admin, banned_at, ban_reason, preferred_locale, deactivated_at").
+		To(&u.IsAdmin, &u.BannedAt, &u.BanReason, &u.PreferredLocale, &u.DeactivatedAt)
+}
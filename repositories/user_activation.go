@@ -2,25 +2,33 @@ package repositories
 
 import (
 	"context"
+	"crypto/rand"
 	"crypto/rsa"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"github.com/burenotti/rtu-it-lab-recruit/model"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/leporo/sqlf"
 	"os"
 	"time"
 )
 
 const (
-	DefaultIssuer = "RTUITLab"
+	DefaultIssuer                = "RTUITLab"
+	ActivationTokensUserFkeyName = "activation_tokens_user_id_fkey"
 )
 
 var (
-	ErrTokenExpired = errors.New("token expired")
-	ErrInvalidToken = errors.New("invalid token")
+	ErrTokenExpired     = errors.New("token expired")
+	ErrInvalidToken     = errors.New("invalid token")
+	ErrTokenAlreadyUsed = errors.New("token has already been used")
 )
 
+// UserActivationRepository issues activation tokens as signed jwt.
+// Every issued token is recorded by its jti, so it could be used only once.
 type UserActivationRepository struct {
+	Db         DatabaseWrapper
 	PrivateKey *rsa.PrivateKey
 	TokenTTL   time.Duration
 }
@@ -44,9 +52,13 @@ func NewUserActivationRepositoryFromFile(privateKeyPath string) (*UserActivation
 	return NewUserActivationRepositoryFromPem(data)
 }
 
-func (r *UserActivationRepository) CreateActivationToken(_ context.Context, userId int64) (*model.ActivationToken, error) {
+func (r *UserActivationRepository) CreateActivationToken(ctx context.Context, userId int64) (*model.ActivationToken, error) {
 	now := time.Now().UTC()
 	expiresAt := now.Add(r.TokenTTL)
+	tokenId, err := newTokenId()
+	if err != nil {
+		return nil, err
+	}
 
 	claims := model.ActivationTokenClaims{
 		RegisteredClaims: jwt.RegisteredClaims{
@@ -55,19 +67,64 @@ func (r *UserActivationRepository) CreateActivationToken(_ context.Context, user
 			ExpiresAt: jwt.NewNumericDate(expiresAt),
 			NotBefore: jwt.NewNumericDate(now),
 			IssuedAt:  jwt.NewNumericDate(now),
+			ID:        tokenId,
 		},
 	}
 	tokenString, err := jwt.NewWithClaims(jwt.SigningMethodRS256, claims).SignedString(r.PrivateKey)
 	if err != nil {
 		return nil, err
 	}
+
+	_, err = sqlf.InsertInto("activation_tokens").
+		Set("token_id", tokenId).
+		Set("user_id", userId).
+		Set("issued_at", now).
+		Set("expires_at", expiresAt).
+		ExecAndClose(ctx, r.Db)
+
+	if getViolatedConstraint(err) == ActivationTokensUserFkeyName {
+		return nil, ErrUserNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
 	return &model.ActivationToken{
+		TokenID:   tokenId,
 		Token:     tokenString,
 		UserId:    userId,
 		ExpiresAt: expiresAt,
 	}, nil
 }
 
+// UseActivationToken validates token and marks it as used.
+// Each token could be used only once, so the following calls return ErrTokenAlreadyUsed.
+func (r *UserActivationRepository) UseActivationToken(ctx context.Context, token string) (*model.ActivationToken, error) {
+	t, err := r.ValidateActivationToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if t.TokenID == "" {
+		return nil, fmt.Errorf("%w: token has no id", ErrInvalidToken)
+	}
+
+	res, err := sqlf.Update("activation_tokens").
+		Set("used_at", time.Now().UTC()).
+		Where("token_id = ?", t.TokenID).
+		Where("user_id = ?", t.UserId).
+		Where("used_at IS NULL").
+		ExecAndClose(ctx, r.Db)
+
+	if err != nil {
+		return nil, err
+	}
+
+	count, _ := res.RowsAffected()
+	if count == 0 {
+		return nil, ErrTokenAlreadyUsed
+	}
+	return t, nil
+}
+
 func (r *UserActivationRepository) ValidateActivationToken(_ context.Context, token string) (*model.ActivationToken, error) {
 	jwtToken, err := jwt.Parse(token, r.selectKey)
 	if errors.Is(err, ErrInvalidToken) {
@@ -83,12 +140,14 @@ func (r *UserActivationRepository) ValidateActivationToken(_ context.Context, to
 
 	exp, _ := jwtToken.Claims.GetExpirationTime()
 	sub, _ := jwtToken.Claims.GetSubject()
+	jti, _ := jwtToken.Claims.(jwt.MapClaims)["jti"].(string)
 	var userId int64
 	_, err = fmt.Sscanf(sub, "%d", &userId)
 	if err != nil {
 		return nil, fmt.Errorf("%w: invalid token subject: %s", ErrInvalidToken, sub)
 	}
 	return &model.ActivationToken{
+		TokenID:   jti,
 		Token:     token,
 		UserId:    userId,
 		ExpiresAt: exp.Time.UTC(),
@@ -102,3 +161,11 @@ func (r *UserActivationRepository) selectKey(token *jwt.Token) (interface{}, err
 
 	return &r.PrivateKey.PublicKey, nil
 }
+
+func newTokenId() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
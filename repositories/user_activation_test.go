@@ -6,9 +6,11 @@ import (
 	"crypto/rsa"
 	"crypto/x509"
 	"encoding/pem"
+	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/burenotti/rtu-it-lab-recruit/model"
 	"github.com/docker/docker/pkg/ioutils"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/jmoiron/sqlx"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"os"
@@ -54,13 +56,22 @@ func TestUserActivationRepository_CreateActivationToken(t *testing.T) {
 	privateKey, err := rsa.GenerateKey(rand.Reader, 3072)
 	require.NoError(t, err, "should generate key without errors")
 
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err, "could not create sql mock")
+
 	repo := UserActivationRepository{
+		Db:         NewDatabase(sqlx.NewDb(db, "postgres")),
 		PrivateKey: privateKey,
 		TokenTTL:   24 * time.Hour,
 	}
 	var userId int64 = 1
+	mock.ExpectExec("INSERT INTO activation_tokens").
+		WithArgs(sqlmock.AnyArg(), userId, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
 	token, err := repo.CreateActivationToken(ctx, userId)
 	assert.NoError(t, err, "should correctly create activation token")
+	assert.NoError(t, mock.ExpectationsWereMet(), "token should be recorded")
+	assert.NotEmpty(t, token.TokenID, "token should have an id")
 	expectedExpiration := time.Now().Add(repo.TokenTTL).UTC().Unix()
 	expiration := token.ExpiresAt.UTC().Unix()
 	// Since CreateActivationToken uses time.Now() inside,
@@ -72,6 +83,38 @@ func TestUserActivationRepository_CreateActivationToken(t *testing.T) {
 	assert.NoError(t, err, "token should be parsed correctly")
 	sub, _ := jwtToken.Claims.GetSubject()
 	assert.Equal(t, "1", sub, "user id should be marshaled to jwt as subject")
+	assert.Equal(t, token.TokenID, jwtToken.Claims.(jwt.MapClaims)["jti"], "token id should be marshaled to jwt as jti")
+}
+
+func TestUserActivationRepository_UseActivationToken(t *testing.T) {
+	ctx := context.Background()
+	privateKey, err := rsa.GenerateKey(rand.Reader, 3072)
+	require.NoError(t, err, "should generate key without errors")
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err, "could not create sql mock")
+
+	repo := UserActivationRepository{
+		Db:         NewDatabase(sqlx.NewDb(db, "postgres")),
+		PrivateKey: privateKey,
+		TokenTTL:   24 * time.Hour,
+	}
+	mock.ExpectExec("INSERT INTO activation_tokens").WillReturnResult(sqlmock.NewResult(0, 1))
+	token, err := repo.CreateActivationToken(ctx, 1)
+	require.NoError(t, err, "should correctly create activation token")
+
+	mock.ExpectExec("UPDATE activation_tokens").
+		WithArgs(sqlmock.AnyArg(), token.TokenID, int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	used, err := repo.UseActivationToken(ctx, token.Token)
+	assert.NoError(t, err, "should use token for the first time")
+	assert.Equal(t, int64(1), used.UserId)
+
+	mock.ExpectExec("UPDATE activation_tokens").
+		WithArgs(sqlmock.AnyArg(), token.TokenID, int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	_, err = repo.UseActivationToken(ctx, token.Token)
+	assert.ErrorIs(t, err, ErrTokenAlreadyUsed, "should not use token twice")
+	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
 func TestUserActivationRepository_ValidateActivationToken(t *testing.T) {
@@ -0,0 +1,240 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/burenotti/rtu-it-lab-recruit/model"
+	"github.com/leporo/sqlf"
+	"time"
+)
+
+var (
+	ErrChannelNotLinked          = errors.New("channel is not linked")
+	ErrTelegramLinkTokenNotFound = errors.New("telegram link token not found")
+	ErrPhoneVerificationNotFound = errors.New("phone verification not found")
+)
+
+var channelUpdatesValidator = NewUpdatesValidator([]string{"enabled", "priority"})
+
+type UserChannelRepository struct {
+	db DatabaseWrapper
+}
+
+func NewUserChannelRepository(db DatabaseWrapper) *UserChannelRepository {
+	return &UserChannelRepository{db: db}
+}
+
+func (r *UserChannelRepository) ListChannels(ctx context.Context, userId int64) ([]model.UserChannel, error) {
+	var channels []model.UserChannel
+	var c model.UserChannel
+	err := selectChannel(&c).
+		Where("user_id = ?", userId).
+		OrderBy("priority DESC", "channel").
+		QueryAndClose(ctx, r.db, func(rows *sql.Rows) {
+			channels = append(channels, c)
+		})
+	if err != nil {
+		return nil, err
+	}
+	return channels, nil
+}
+
+// LinkChannel links the address to the channel of the user. If the channel is already linked, its address is replaced
+// and preferences are kept.
+func (r *UserChannelRepository) LinkChannel(ctx context.Context, userId int64, channel, address string) (*model.UserChannel, error) {
+	c := &model.UserChannel{}
+	err := sqlf.InsertInto("user_channels").
+		Set("user_id", userId).
+		Set("channel", channel).
+		Set("address", address).
+		Clause("ON CONFLICT (user_id, channel) DO UPDATE SET address = EXCLUDED.address, linked_at = now()").
+		Returning("user_id, channel, address, enabled, priority, linked_at").
+		To(&c.UserID, &c.Channel, &c.Address, &c.Enabled, &c.Priority, &c.LinkedAt).
+		QueryRowAndClose(ctx, r.db)
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// ReleaseAddress unlinks the address of the channel from all users. Telegram chat may be linked to a single user only,
+// so it is released before linking to a new one.
+func (r *UserChannelRepository) ReleaseAddress(ctx context.Context, channel, address string) error {
+	_, err := sqlf.DeleteFrom("user_channels").
+		Where("channel = ?", channel).
+		Where("address = ?", address).
+		ExecAndClose(ctx, r.db)
+	return err
+}
+
+func (r *UserChannelRepository) UpdateChannel(
+	ctx context.Context,
+	userId int64,
+	channel string,
+	updates map[string]interface{},
+) (*model.UserChannel, error) {
+	if err := channelUpdatesValidator.Validate(updates); err != nil {
+		return nil, err
+	}
+
+	c := &model.UserChannel{}
+	query := sqlf.Update("user_channels").
+		Where("user_id = ?", userId).
+		Where("channel = ?", channel).
+		Returning("user_id, channel, address, enabled, priority, linked_at").
+		To(&c.UserID, &c.Channel, &c.Address, &c.Enabled, &c.Priority, &c.LinkedAt)
+	for field, value := range updates {
+		query = query.Set(field, value)
+	}
+
+	err := query.QueryRowAndClose(ctx, r.db)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: channel %s is not linked", ErrChannelNotLinked, channel)
+	} else if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (r *UserChannelRepository) UnlinkChannel(ctx context.Context, userId int64, channel string) error {
+	res, err := sqlf.DeleteFrom("user_channels").
+		Where("user_id = ?", userId).
+		Where("channel = ?", channel).
+		ExecAndClose(ctx, r.db)
+	if err != nil {
+		return err
+	}
+	if affected, err := res.RowsAffected(); err != nil {
+		return err
+	} else if affected == 0 {
+		return fmt.Errorf("%w: channel %s is not linked", ErrChannelNotLinked, channel)
+	}
+	return nil
+}
+
+func (r *UserChannelRepository) CreateTelegramLinkToken(ctx context.Context, userId int64, token string, expiresAt time.Time) error {
+	_, err := sqlf.InsertInto("telegram_link_tokens").
+		Set("token", token).
+		Set("user_id", userId).
+		Set("expires_at", expiresAt).
+		ExecAndClose(ctx, r.db)
+	return err
+}
+
+// UseTelegramLinkToken deletes the token and returns id of the user it was issued for.
+func (r *UserChannelRepository) UseTelegramLinkToken(ctx context.Context, token string) (int64, error) {
+	var userId int64
+	err := sqlf.DeleteFrom("telegram_link_tokens").
+		Where("token = ?", token).
+		Where("expires_at > ?", time.Now().UTC()).
+		Returning("user_id").To(&userId).
+		QueryRowAndClose(ctx, r.db)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, fmt.Errorf("%w: token is invalid or expired", ErrTelegramLinkTokenNotFound)
+	} else if err != nil {
+		return 0, err
+	}
+	return userId, nil
+}
+
+// CreatePhoneVerification stores the code sent to the phone. A new verification replaces the pending one,
+// attempts made since windowStart are kept, so resending the code gives no extra guesses.
+// It returns the number of attempts already made.
+func (r *UserChannelRepository) CreatePhoneVerification(ctx context.Context, userId int64, phone, code string, expiresAt, windowStart time.Time) (int, error) {
+	attempts := 0
+	err := sqlf.InsertInto("phone_verifications").
+		Set("user_id", userId).
+		Set("phone", phone).
+		Set("code", code).
+		Set("expires_at", expiresAt).
+		Set("window_started_at", time.Now().UTC()).
+		Clause("ON CONFLICT (user_id) DO UPDATE SET phone = EXCLUDED.phone, code = EXCLUDED.code, "+
+			"expires_at = EXCLUDED.expires_at, "+
+			"attempts = CASE WHEN phone_verifications.window_started_at > ? "+
+			"THEN phone_verifications.attempts ELSE 0 END, "+
+			"window_started_at = CASE WHEN phone_verifications.window_started_at > ? "+
+			"THEN phone_verifications.window_started_at ELSE EXCLUDED.window_started_at END", windowStart, windowStart).
+		Returning("attempts").To(&attempts).
+		QueryRowAndClose(ctx, r.db)
+	return attempts, err
+}
+
+// LockPhoneVerificationRequests locks the user until the end of transaction, so concurrent requests
+// can't both pass the rate limit before either of them is recorded.
+func (r *UserChannelRepository) LockPhoneVerificationRequests(ctx context.Context, userId int64) error {
+	var id int64
+	err := sqlf.From("users").
+		Select("user_id").To(&id).
+		Where("user_id = ?", userId).
+		Clause("FOR UPDATE").
+		QueryRowAndClose(ctx, r.db)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("%w: user with provided id does not exist", ErrUserNotFound)
+	}
+	return err
+}
+
+func (r *UserChannelRepository) CreatePhoneVerificationRequest(ctx context.Context, userId int64) error {
+	_, err := sqlf.InsertInto("phone_verification_requests").
+		Set("user_id", userId).
+		Set("requested_at", time.Now().UTC()).
+		ExecAndClose(ctx, r.db)
+	return err
+}
+
+func (r *UserChannelRepository) CountPhoneVerificationRequests(ctx context.Context, userId int64, since time.Time) (int, error) {
+	count := 0
+	err := sqlf.From("phone_verification_requests").
+		Select("count(1)").To(&count).
+		Where("user_id = ?", userId).
+		Where("requested_at >= ?", since).
+		QueryRowAndClose(ctx, r.db)
+	return count, err
+}
+
+// UsePhoneVerificationAttempt counts an attempt to confirm the pending verification and returns it.
+// Attempts are counted before the code is checked, so concurrent guesses can't exceed maxAttempts.
+func (r *UserChannelRepository) UsePhoneVerificationAttempt(ctx context.Context, userId int64, maxAttempts int) (*model.PhoneVerification, error) {
+	v := &model.PhoneVerification{}
+	err := sqlf.Update("phone_verifications").
+		SetExpr("attempts", "attempts + 1").
+		Where("user_id = ?", userId).
+		Where("attempts < ?", maxAttempts).
+		Where("expires_at > ?", time.Now().UTC()).
+		Returning("phone, code, attempts, expires_at").
+		To(&v.Phone, &v.Code, &v.Attempts, &v.ExpiresAt).
+		QueryRowAndClose(ctx, r.db)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: request a new code", ErrPhoneVerificationNotFound)
+	} else if err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func (r *UserChannelRepository) DeletePhoneVerification(ctx context.Context, userId int64) error {
+	res, err := sqlf.DeleteFrom("phone_verifications").
+		Where("user_id = ?", userId).
+		ExecAndClose(ctx, r.db)
+	if err != nil {
+		return err
+	}
+	if affected, err := res.RowsAffected(); err != nil {
+		return err
+	} else if affected == 0 {
+		return fmt.Errorf("%w: request a new code", ErrPhoneVerificationNotFound)
+	}
+	return nil
+}
+
+func selectChannel(c *model.UserChannel) *sqlf.Stmt {
+	return sqlf.From("user_channels").
+		Select("user_id").To(&c.UserID).
+		Select("channel").To(&c.Channel).
+		Select("address").To(&c.Address).
+		Select("enabled").To(&c.Enabled).
+		Select("priority").To(&c.Priority).
+		Select("linked_at").To(&c.LinkedAt)
+}
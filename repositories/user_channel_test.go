@@ -0,0 +1,60 @@
+package repositories
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+)
+
+type UserChannelRepositoryTestSuite struct {
+	DBTestSuite
+}
+
+func TestUserChannelRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, &UserChannelRepositoryTestSuite{
+		*DBTestSuiteFromEnv(),
+	})
+}
+
+func (s *UserChannelRepositoryTestSuite) TestCreatePhoneVerification_KeepsAttempts() {
+	ctx := context.Background()
+	db := NewDatabase(s.db)
+	repo := NewUserChannelRepository(db)
+	user := CreateRandomUser(ctx, db, s.T())
+	expiresAt := time.Now().Add(10 * time.Minute)
+	windowStart := time.Now().Add(-24 * time.Hour)
+
+	attempts, err := repo.CreatePhoneVerification(ctx, user.UserID, "+79991234567", "123456", expiresAt, windowStart)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), 0, attempts)
+	for i := 0; i < 2; i++ {
+		_, err = repo.UsePhoneVerificationAttempt(ctx, user.UserID, 5)
+		require.NoError(s.T(), err)
+	}
+
+	attempts, err = repo.CreatePhoneVerification(ctx, user.UserID, "+79991234567", "654321", expiresAt, windowStart)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), 2, attempts, "resending code should keep attempts")
+
+	attempts, err = repo.CreatePhoneVerification(ctx, user.UserID, "+79991234567", "111111", expiresAt, time.Now().Add(time.Minute))
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), 0, attempts, "attempts should be reset once the window is over")
+}
+
+func (s *UserChannelRepositoryTestSuite) TestCountPhoneVerificationRequests() {
+	ctx := context.Background()
+	db := NewDatabase(s.db)
+	repo := NewUserChannelRepository(db)
+	user := CreateRandomUser(ctx, db, s.T())
+
+	require.NoError(s.T(), repo.CreatePhoneVerificationRequest(ctx, user.UserID))
+	count, err := repo.CountPhoneVerificationRequests(ctx, user.UserID, time.Now().Add(-time.Minute))
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), 1, count)
+	count, err = repo.CountPhoneVerificationRequests(ctx, user.UserID, time.Now().Add(time.Minute))
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), 0, count)
+}
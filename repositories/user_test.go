@@ -9,6 +9,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 	"testing"
+	"time"
 )
 
 func TestNewUserRepository(t *testing.T) {
@@ -34,10 +35,10 @@ func (s *UserRepositoryTestSuite) TestCreate() {
 	repo := NewUserRepository(NewDatabase(s.db))
 
 	users := []model.UserCreate{
-		{"John", "Doe", "Jr.", "johndoe@example.com"},
-		{"Michel", "Smith", "", "smith@example.com"},
-		{"John", "Doe", "Jr.", "johndoe@example.com"},
-		{"'; DELETE * FROM users; --", "", "", ""},
+		{FirstName: "John", LastName: "Doe", MiddleName: "Jr.", Email: "johndoe@example.com"},
+		{FirstName: "Michel", LastName: "Smith", MiddleName: "", Email: "smith@example.com"},
+		{FirstName: "John", LastName: "Doe", MiddleName: "Jr.", Email: "johndoe@example.com"},
+		{FirstName: "'; DELETE * FROM users; --", LastName: "", MiddleName: "", Email: ""},
 	}
 
 	for _, u := range users {
@@ -60,10 +61,10 @@ func (s *UserRepositoryTestSuite) TestGetById() {
 	repo := NewUserRepository(NewDatabase(s.db))
 
 	users := []model.UserCreate{
-		{"John", "Doe", "Jr.", "johndoe@example.com"},
-		{"Michel", "Smith", "", "smith@example.com"},
-		{"John", "Doe", "Jr.", "johndoe@example.com"},
-		{"'; DELETE * FROM users; --", "", "", ""},
+		{FirstName: "John", LastName: "Doe", MiddleName: "Jr.", Email: "johndoe@example.com"},
+		{FirstName: "Michel", LastName: "Smith", MiddleName: "", Email: "smith@example.com"},
+		{FirstName: "John", LastName: "Doe", MiddleName: "Jr.", Email: "johndoe@example.com"},
+		{FirstName: "'; DELETE * FROM users; --", LastName: "", MiddleName: "", Email: ""},
 	}
 
 	q := `INSERT INTO users (first_name, last_name, middle_name, email) VALUES ($1, $2, $3, $4) RETURNING user_id`
@@ -157,3 +158,31 @@ func (s *UserRepositoryTestSuite) TestGetByEmail() {
 		})
 	}
 }
+
+func (s *UserRepositoryTestSuite) TestAnonymize_KeepsAttendance() {
+	ctx := context.Background()
+	db := NewDatabase(s.db)
+	repo := NewUserRepository(db)
+	registrations := NewRegistrationRepository(db)
+	user := CreateRandomUser(ctx, db, s.T())
+	past := CreateRandomEvent(ctx, db, s.T(), user.UserID, time.Now().Add(-24*time.Hour))
+	upcoming := CreateRandomEvent(ctx, db, s.T(), user.UserID, time.Now().Add(24*time.Hour))
+
+	attended, err := registrations.Register(ctx, past.EventID, user.UserID, nil, nil)
+	require.NoError(s.T(), err)
+	_, err = s.db.Exec(`INSERT INTO check_ins (registration_id, event_id, checked_in_at) VALUES ($1, $2, now())`,
+		attended.RegistrationID, past.EventID)
+	require.NoError(s.T(), err)
+	_, err = registrations.Register(ctx, upcoming.EventID, user.UserID, nil, nil)
+	require.NoError(s.T(), err)
+
+	require.NoError(s.T(), repo.Anonymize(ctx, user.UserID))
+
+	kept, err := registrations.ListUserRegistrations(ctx, user.UserID)
+	require.NoError(s.T(), err)
+	require.Len(s.T(), kept, 1, "registration for upcoming event should be cancelled")
+	assert.Equal(s.T(), attended.RegistrationID, kept[0].RegistrationID, "attendance should be kept")
+	checkIns := 0
+	require.NoError(s.T(), s.db.Get(&checkIns, `SELECT count(*) FROM check_ins WHERE event_id = $1`, past.EventID))
+	assert.Equal(s.T(), 1, checkIns, "check-in should be kept")
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/jackc/pgconn"
 	"github.com/stretchr/testify/require"
 	"testing"
+	"time"
 )
 
 var (
@@ -63,3 +64,39 @@ func CreateRandomUser(ctx context.Context, db DatabaseWrapper, t *testing.T) *mo
 	require.NoError(t, err, "should create user without errors")
 	return &u
 }
+
+// CreateRandomEvent creates a published event of a new organization.
+func CreateRandomEvent(ctx context.Context, db DatabaseWrapper, t *testing.T, creatorId int64, beginsAt time.Time) *model.Event {
+	e := model.Event{Name: faker.Name(), CreatorID: creatorId, BeginsAt: beginsAt}
+	row := db.QueryRowContext(ctx, `INSERT INTO organizations (name) VALUES ($1) RETURNING organization_id`, e.Name)
+	err := row.Scan(&e.OrganizationID)
+	require.NoError(t, err, "should create organization without errors")
+	query := `
+		INSERT INTO
+			events (name, description, organization_id, creator_id, begins_at, published_at)
+		VALUES ($1, '', $2, $3, $4, now()) RETURNING event_id
+	`
+	row = db.QueryRowContext(ctx, query, e.Name, e.OrganizationID, creatorId, beginsAt)
+	err = row.Scan(&e.EventID)
+	require.NoError(t, err, "should create event without errors")
+	return &e
+}
+
+// CreateRandomOrder creates the order of a new user for one ticket of the type.
+func CreateRandomOrder(ctx context.Context, db DatabaseWrapper, t *testing.T, ticketType *model.TicketType, promoCodeId *int64, status string) *model.Order {
+	user := CreateRandomUser(ctx, db, t)
+	order, err := NewOrderRepository(db).CreateOrder(ctx, &model.Order{
+		EventID:      ticketType.EventID,
+		UserID:       user.UserID,
+		TicketTypeID: ticketType.TicketTypeID,
+		Quantity:     1,
+		PromoCodeID:  promoCodeId,
+		Currency:     ticketType.Currency,
+		Subtotal:     ticketType.Price,
+		Total:        ticketType.Price,
+		Status:       status,
+		ExpiresAt:    time.Now().Add(15 * time.Minute),
+	})
+	require.NoError(t, err, "should create order without errors")
+	return order
+}
@@ -0,0 +1,139 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/burenotti/rtu-it-lab-recruit/model"
+	"github.com/leporo/sqlf"
+)
+
+var (
+	ErrVenueNotFound = errors.New("venue does not exist")
+	ErrVenueInUse    = errors.New("venue is used by events")
+)
+
+const venueColumns = "venue_id, organization_id, name, address, latitude, longitude, accessibility, capacity, created_at"
+
+var venueUpdatesValidator = NewUpdatesValidator([]string{
+	"name", "address", "latitude", "longitude", "accessibility", "capacity",
+})
+
+type VenueRepository struct {
+	db DatabaseWrapper
+}
+
+func NewVenueRepository(db DatabaseWrapper) *VenueRepository {
+	return &VenueRepository{db: db}
+}
+
+func (r *VenueRepository) CreateVenue(ctx context.Context, orgId int64, create *model.VenueCreate) (*model.Venue, error) {
+	v := &model.Venue{}
+	err := returningVenue(sqlf.InsertInto("venues").
+		Set("organization_id", orgId).
+		Set("name", create.Name).
+		Set("address", create.Address).
+		Set("latitude", *create.Latitude).
+		Set("longitude", *create.Longitude).
+		Set("accessibility", create.Accessibility).
+		Set("capacity", create.Capacity), v).
+		QueryRowAndClose(ctx, r.db)
+	if getViolatedConstraint(err) == "venues_organization_id_fkey" {
+		return nil, fmt.Errorf("%w: organization with provided id does not exist", ErrOrganizationNotFound)
+	} else if err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func (r *VenueRepository) GetVenue(ctx context.Context, venueId int64) (*model.Venue, error) {
+	v := &model.Venue{}
+	err := selectVenue(v).
+		Where("venue_id = ?", venueId).
+		QueryRowAndClose(ctx, r.db)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: venue with provided id does not exist", ErrVenueNotFound)
+	} else if err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func (r *VenueRepository) ListVenues(ctx context.Context, orgId int64) ([]model.Venue, error) {
+	v := model.Venue{}
+	return r.list(ctx, &v, selectVenue(&v).
+		Where("organization_id = ?", orgId).
+		OrderBy("name, venue_id"))
+}
+
+// ListVenuesByIds returns venues with provided ids, missing ones are skipped.
+func (r *VenueRepository) ListVenuesByIds(ctx context.Context, venueIds []int64) ([]model.Venue, error) {
+	if len(venueIds) == 0 {
+		return []model.Venue{}, nil
+	}
+	v := model.Venue{}
+	return r.list(ctx, &v, selectVenue(&v).
+		Where("venue_id = ANY(?)", venueIds))
+}
+
+func (r *VenueRepository) UpdateVenue(ctx context.Context, venueId int64, updates map[string]interface{}) (*model.Venue, error) {
+	if err := venueUpdatesValidator.Validate(updates); err != nil {
+		return nil, err
+	}
+	v := &model.Venue{}
+	query := sqlf.Update("venues").
+		Where("venue_id = ?", venueId)
+	for field, value := range updates {
+		query = query.Set(field, value)
+	}
+	err := returningVenue(query, v).QueryRowAndClose(ctx, r.db)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: venue with provided id does not exist", ErrVenueNotFound)
+	} else if err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func (r *VenueRepository) DeleteVenue(ctx context.Context, venueId int64) error {
+	res, err := sqlf.DeleteFrom("venues").
+		Where("venue_id = ?", venueId).
+		ExecAndClose(ctx, r.db)
+	if getViolatedConstraint(err) == EventsVenueIdFkeyName {
+		return fmt.Errorf("%w: unlink the venue from its events first", ErrVenueInUse)
+	} else if err != nil {
+		return err
+	}
+	if affected, err := res.RowsAffected(); err != nil {
+		return err
+	} else if affected == 0 {
+		return fmt.Errorf("%w: venue with provided id does not exist", ErrVenueNotFound)
+	}
+	return nil
+}
+
+func (r *VenueRepository) list(ctx context.Context, v *model.Venue, query *sqlf.Stmt) ([]model.Venue, error) {
+	venues := make([]model.Venue, 0)
+	err := query.QueryAndClose(ctx, r.db, func(rows *sql.Rows) {
+		venues = append(venues, *v)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return venues, nil
+}
+
+func selectVenue(v *model.Venue) *sqlf.Stmt {
+	return sqlf.From("venues").
+		Select(venueColumns).
+		To(&v.VenueID, &v.OrganizationID, &v.Name, &v.Address, &v.Latitude, &v.Longitude,
+			&v.Accessibility, &v.Capacity, &v.CreatedAt)
+}
+
+func returningVenue(query *sqlf.Stmt, v *model.Venue) *sqlf.Stmt {
+	return query.
+		Returning(venueColumns).
+		To(&v.VenueID, &v.OrganizationID, &v.Name, &v.Address, &v.Latitude, &v.Longitude,
+			&v.Accessibility, &v.Capacity, &v.CreatedAt)
+}
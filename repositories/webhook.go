@@ -0,0 +1,333 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/burenotti/rtu-it-lab-recruit/model"
+	"github.com/leporo/sqlf"
+	"time"
+)
+
+var (
+	ErrWebhookNotFound = errors.New("webhook does not exist")
+)
+
+var webhookUpdatesValidator = NewUpdatesValidator([]string{"url", "event_types", "enabled"})
+
+const webhookDeliveryColumns = "d.delivery_id, d.webhook_id, d.event_type, d.payload, d.status, d.attempts, d.next_attempt_at, " +
+	"d.last_status_code, d.last_error, d.created_at, d.delivered_at"
+
+type WebhookRepository struct {
+	db DatabaseWrapper
+}
+
+func NewWebhookRepository(db DatabaseWrapper) *WebhookRepository {
+	return &WebhookRepository{db: db}
+}
+
+// webhookRow is a webhook with event types not decoded yet.
+type webhookRow struct {
+	model.Webhook
+	eventTypes []byte
+}
+
+func (row *webhookRow) decode() (model.Webhook, error) {
+	webhook := row.Webhook
+	webhook.EventTypes = nil
+	err := json.Unmarshal(row.eventTypes, &webhook.EventTypes)
+	return webhook, err
+}
+
+func (r *WebhookRepository) CreateWebhook(ctx context.Context, webhook *model.Webhook) (*model.Webhook, error) {
+	eventTypes, err := json.Marshal(webhook.EventTypes)
+	if err != nil {
+		return nil, err
+	}
+	row := &webhookRow{}
+	err = returningWebhook(sqlf.InsertInto("webhooks").
+		Set("organization_id", webhook.OrganizationID).
+		Set("url", webhook.URL).
+		Set("secret", webhook.Secret).
+		Set("event_types", string(eventTypes)), row).
+		QueryRowAndClose(ctx, r.db)
+	if getViolatedConstraint(err) == "webhooks_organization_id_fkey" {
+		return nil, fmt.Errorf("%w: organization with provided id does not exist", ErrOrganizationNotFound)
+	} else if err != nil {
+		return nil, err
+	}
+	row.Secret = webhook.Secret
+	return decodeWebhook(row)
+}
+
+func (r *WebhookRepository) ListWebhooks(ctx context.Context, orgId int64) ([]model.Webhook, error) {
+	row := &webhookRow{}
+	webhooks := make([]model.Webhook, 0)
+	var decodeErr error
+	err := selectWebhook(row).
+		Where("organization_id = ?", orgId).
+		OrderBy("webhook_id").
+		QueryAndClose(ctx, r.db, func(rows *sql.Rows) {
+			webhook, err := row.decode()
+			if err != nil {
+				decodeErr = err
+				return
+			}
+			webhooks = append(webhooks, webhook)
+		})
+	if err != nil {
+		return nil, err
+	}
+	return webhooks, decodeErr
+}
+
+func (r *WebhookRepository) GetWebhook(ctx context.Context, orgId, webhookId int64) (*model.Webhook, error) {
+	row := &webhookRow{}
+	err := selectWebhook(row).
+		Where("webhook_id = ?", webhookId).
+		Where("organization_id = ?", orgId).
+		QueryRowAndClose(ctx, r.db)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: webhook with provided id does not exist", ErrWebhookNotFound)
+	} else if err != nil {
+		return nil, err
+	}
+	return decodeWebhook(row)
+}
+
+// UpdateWebhook updates the webhook. Enabling the webhook resets its failures, so it isn't disabled right away.
+func (r *WebhookRepository) UpdateWebhook(
+	ctx context.Context,
+	orgId, webhookId int64,
+	updates map[string]interface{},
+) (*model.Webhook, error) {
+	if err := webhookUpdatesValidator.Validate(updates); err != nil {
+		return nil, err
+	}
+	query := sqlf.Update("webhooks").
+		Where("webhook_id = ?", webhookId).
+		Where("organization_id = ?", orgId)
+	for field, value := range updates {
+		switch field {
+		case "event_types":
+			eventTypes, err := json.Marshal(value)
+			if err != nil {
+				return nil, err
+			}
+			query = query.Set(field, string(eventTypes))
+		case "enabled":
+			query = query.Set(field, value)
+			if enabled, _ := value.(bool); enabled {
+				query = query.Set("consecutive_failures", 0).Set("disabled_at", nil)
+			}
+		default:
+			query = query.Set(field, value)
+		}
+	}
+	row := &webhookRow{}
+	err := returningWebhook(query, row).QueryRowAndClose(ctx, r.db)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: webhook with provided id does not exist", ErrWebhookNotFound)
+	} else if err != nil {
+		return nil, err
+	}
+	return decodeWebhook(row)
+}
+
+func (r *WebhookRepository) DeleteWebhook(ctx context.Context, orgId, webhookId int64) error {
+	res, err := sqlf.DeleteFrom("webhooks").
+		Where("webhook_id = ?", webhookId).
+		Where("organization_id = ?", orgId).
+		ExecAndClose(ctx, r.db)
+	if err != nil {
+		return err
+	}
+	if affected, err := res.RowsAffected(); err != nil {
+		return err
+	} else if affected == 0 {
+		return fmt.Errorf("%w: webhook with provided id does not exist", ErrWebhookNotFound)
+	}
+	return nil
+}
+
+// EnqueueDeliveries schedules delivery of the payload to enabled webhooks of the organization subscribed to the event type.
+// Call it in the same transaction with the changes the event is about.
+func (r *WebhookRepository) EnqueueDeliveries(ctx context.Context, orgId int64, eventType, payload string) error {
+	_, err := sqlf.New("INSERT INTO webhook_deliveries (webhook_id, event_type, payload) "+
+		"SELECT webhook_id, ?, ? FROM webhooks "+
+		"WHERE organization_id = ? AND enabled AND event_types @> jsonb_build_array(?::text)",
+		eventType, payload, orgId, eventType).
+		ExecAndClose(ctx, r.db)
+	return err
+}
+
+// ClaimDueDeliveries returns pending deliveries of enabled webhooks which are due to be sent.
+// Claimed deliveries are leased the same way outbox messages are.
+func (r *WebhookRepository) ClaimDueDeliveries(ctx context.Context, limit int, lease time.Duration) ([]model.WebhookDelivery, error) {
+	now := time.Now().UTC()
+	stmt := sqlf.New("UPDATE webhook_deliveries d SET next_attempt_at = ?, attempts = d.attempts + 1 "+
+		"FROM webhooks w WHERE w.webhook_id = d.webhook_id AND d.delivery_id IN ("+
+		"SELECT pd.delivery_id FROM webhook_deliveries pd JOIN webhooks pw ON pw.webhook_id = pd.webhook_id "+
+		"WHERE pd.status = ? AND pd.next_attempt_at <= ? AND pw.enabled "+
+		"ORDER BY pd.next_attempt_at LIMIT ? FOR UPDATE OF pd SKIP LOCKED)",
+		now.Add(lease), model.WebhookDeliveryPending, now, limit).
+		Returning(webhookDeliveryColumns + ", w.url, w.secret")
+	return r.queryDeliveries(ctx, stmt, true)
+}
+
+// ExtendDeliveryLease postpones the next attempt of the claimed delivery by lease, so the delivery is not picked up
+// by another replica while the batch is being sent. Attempts identify the claim: if the lease has already
+// expired and the delivery was claimed again, nothing is updated and false is returned.
+func (r *WebhookRepository) ExtendDeliveryLease(ctx context.Context, deliveryId int64, attempts int, lease time.Duration) (bool, error) {
+	res, err := sqlf.Update("webhook_deliveries").
+		Set("next_attempt_at", time.Now().UTC().Add(lease)).
+		Where("delivery_id = ?", deliveryId).
+		Where("status = ?", model.WebhookDeliveryPending).
+		Where("attempts = ?", attempts).
+		ExecAndClose(ctx, r.db)
+	if err != nil {
+		return false, err
+	}
+	count, err := res.RowsAffected()
+	return count > 0, err
+}
+
+// MarkDelivered records the successful attempt.
+func (r *WebhookRepository) MarkDelivered(ctx context.Context, deliveryId int64, statusCode int) error {
+	return r.updateDelivery(ctx, deliveryId, sqlf.Update("webhook_deliveries").
+		Set("status", model.WebhookDeliverySucceeded).
+		Set("last_status_code", statusCode).
+		Set("last_error", nil).
+		Set("delivered_at", time.Now().UTC()))
+}
+
+// MarkDeliveryFailed records the failed attempt and schedules the next one.
+// Status code is zero if the endpoint did not respond.
+func (r *WebhookRepository) MarkDeliveryFailed(
+	ctx context.Context,
+	deliveryId int64,
+	statusCode int,
+	reason string,
+	nextAttemptAt time.Time,
+) error {
+	return r.updateDelivery(ctx, deliveryId, sqlf.Update("webhook_deliveries").
+		Set("last_status_code", nullStatusCode(statusCode)).
+		Set("last_error", reason).
+		Set("next_attempt_at", nextAttemptAt))
+}
+
+// MarkDeliveryDead records the last failed attempt. The delivery won't be retried.
+func (r *WebhookRepository) MarkDeliveryDead(ctx context.Context, deliveryId int64, statusCode int, reason string) error {
+	return r.updateDelivery(ctx, deliveryId, sqlf.Update("webhook_deliveries").
+		Set("status", model.WebhookDeliveryFailed).
+		Set("last_status_code", nullStatusCode(statusCode)).
+		Set("last_error", reason))
+}
+
+// ResetFailures clears consecutive failures of the webhook after a successful delivery.
+func (r *WebhookRepository) ResetFailures(ctx context.Context, webhookId int64) error {
+	_, err := sqlf.Update("webhooks").
+		Set("consecutive_failures", 0).
+		Where("webhook_id = ?", webhookId).
+		Where("consecutive_failures > 0").
+		ExecAndClose(ctx, r.db)
+	return err
+}
+
+// RecordFailure increments consecutive failures of the webhook and returns their number.
+func (r *WebhookRepository) RecordFailure(ctx context.Context, webhookId int64) (int, error) {
+	failures := 0
+	err := sqlf.Update("webhooks").
+		SetExpr("consecutive_failures", "consecutive_failures + 1").
+		Where("webhook_id = ?", webhookId).
+		Returning("consecutive_failures").To(&failures).
+		QueryRowAndClose(ctx, r.db)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, fmt.Errorf("%w: webhook with provided id does not exist", ErrWebhookNotFound)
+	}
+	return failures, err
+}
+
+// DisableWebhook disables the webhook, its pending deliveries are kept until it is enabled again.
+func (r *WebhookRepository) DisableWebhook(ctx context.Context, webhookId int64) error {
+	_, err := sqlf.Update("webhooks").
+		Set("enabled", false).
+		Set("disabled_at", time.Now().UTC()).
+		Where("webhook_id = ?", webhookId).
+		Where("enabled").
+		ExecAndClose(ctx, r.db)
+	return err
+}
+
+// ListDeliveries returns deliveries of the webhook, newest first.
+func (r *WebhookRepository) ListDeliveries(ctx context.Context, webhookId int64, limit, offset int) ([]model.WebhookDelivery, error) {
+	stmt := sqlf.From("webhook_deliveries d").
+		Select(webhookDeliveryColumns).
+		Where("d.webhook_id = ?", webhookId).
+		OrderBy("d.delivery_id DESC").
+		Limit(limit).
+		Offset(offset)
+	return r.queryDeliveries(ctx, stmt, false)
+}
+
+func (r *WebhookRepository) updateDelivery(ctx context.Context, deliveryId int64, stmt *sqlf.Stmt) error {
+	_, err := stmt.
+		Where("delivery_id = ?", deliveryId).
+		ExecAndClose(ctx, r.db)
+	return err
+}
+
+func (r *WebhookRepository) queryDeliveries(ctx context.Context, stmt *sqlf.Stmt, withWebhook bool) ([]model.WebhookDelivery, error) {
+	deliveries := make([]model.WebhookDelivery, 0)
+	var scanErr error
+	err := stmt.QueryAndClose(ctx, r.db, func(rows *sql.Rows) {
+		d := model.WebhookDelivery{}
+		dest := []interface{}{&d.DeliveryID, &d.WebhookID, &d.EventType, &d.Payload, &d.Status, &d.Attempts,
+			&d.NextAttemptAt, &d.LastStatusCode, &d.LastError, &d.CreatedAt, &d.DeliveredAt}
+		if withWebhook {
+			dest = append(dest, &d.URL, &d.Secret)
+		}
+		if err := rows.Scan(dest...); err != nil {
+			scanErr = err
+			return
+		}
+		deliveries = append(deliveries, d)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return deliveries, scanErr
+}
+
+func nullStatusCode(statusCode int) interface{} {
+	if statusCode == 0 {
+		return nil
+	}
+	return statusCode
+}
+
+func decodeWebhook(row *webhookRow) (*model.Webhook, error) {
+	webhook, err := row.decode()
+	if err != nil {
+		return nil, err
+	}
+	return &webhook, nil
+}
+
+func selectWebhook(row *webhookRow) *sqlf.Stmt {
+	return sqlf.From("webhooks").
+		Select("webhook_id, organization_id, url, event_types").
+		To(&row.WebhookID, &row.OrganizationID, &row.URL, &row.eventTypes).
+		Select("enabled, consecutive_failures, disabled_at, created_at").
+		To(&row.Enabled, &row.ConsecutiveFailures, &row.DisabledAt, &row.CreatedAt)
+}
+
+func returningWebhook(query *sqlf.Stmt, row *webhookRow) *sqlf.Stmt {
+	return query.
+		Returning("webhook_id, organization_id, url, event_types").
+		To(&row.WebhookID, &row.OrganizationID, &row.URL, &row.eventTypes).
+		Returning("enabled, consecutive_failures, disabled_at, created_at").
+		To(&row.Enabled, &row.ConsecutiveFailures, &row.DisabledAt, &row.CreatedAt)
+}
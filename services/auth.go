@@ -16,14 +16,39 @@ var (
 )
 
 type AuthService struct {
-	TokenTTL   time.Duration
-	PrivateKey *rsa.PrivateKey
+	TokenTTL time.Duration
+	// ImpersonationTTL is lifetime of tokens issued to administrators acting as another user.
+	ImpersonationTTL time.Duration
+	PrivateKey       *rsa.PrivateKey
 }
 
 func (s *AuthService) CreateToken(_ context.Context, user *model.User) (string, error) {
+	claims := s.newClaims(user, s.TokenTTL)
+	return s.sign(claims)
+}
+
+// CreateImpersonationToken issues a token of the user with the actor recorded in "act" claim.
+func (s *AuthService) CreateImpersonationToken(_ context.Context, user, actor *model.User) (*model.ImpersonationToken, error) {
+	claims := s.newClaims(user, s.ImpersonationTTL)
+	claims.Act = &model.Actor{
+		Subject: fmt.Sprintf("%d", actor.UserID),
+		UserID:  actor.UserID,
+		Email:   actor.Email,
+	}
+	token, err := s.sign(claims)
+	if err != nil {
+		return nil, err
+	}
+	return &model.ImpersonationToken{
+		Token:     *model.NewAccessToken(token),
+		ExpiresAt: claims.ExpiresAt.Time,
+	}, nil
+}
+
+func (s *AuthService) newClaims(user *model.User, ttl time.Duration) model.AuthTokenClaims {
 	now := jwt.NewNumericDate(time.Now().UTC())
-	expiresAt := jwt.NewNumericDate(now.Add(s.TokenTTL))
-	claims := model.AuthTokenClaims{
+	expiresAt := jwt.NewNumericDate(now.Add(ttl))
+	return model.AuthTokenClaims{
 		RegisteredClaims: jwt.RegisteredClaims{
 			Issuer:    "",
 			Subject:   fmt.Sprintf("%d", user.UserID),
@@ -36,14 +61,19 @@ func (s *AuthService) CreateToken(_ context.Context, user *model.User) (string,
 		FirstName: user.FirstName,
 		LastName:  user.LastName,
 	}
-	token, err := jwt.NewWithClaims(jwt.SigningMethodRS256, claims).SignedString(s.PrivateKey)
-	return token, err
+}
+
+func (s *AuthService) sign(claims model.AuthTokenClaims) (string, error) {
+	return jwt.NewWithClaims(jwt.SigningMethodRS256, claims).SignedString(s.PrivateKey)
 }
 
 func (s *AuthService) ValidateToken(_ context.Context, tokenString string) (*model.AuthPayload, error) {
 	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
 		return &s.PrivateKey.PublicKey, nil
-	})
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Alg()}))
+	if err != nil || !token.Valid {
+		return nil, ErrInvalidToken
+	}
 	payload := &model.AuthPayload{}
 	claims, _ := token.Claims.(jwt.MapClaims)
 	data, _ := json.Marshal(claims)
@@ -70,3 +70,49 @@ func TestAuthService_ValidateToken(t *testing.T) {
 		})
 	}
 }
+
+func TestAuthService_ValidateToken_ForeignKey(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	foreignKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	s := AuthService{TokenTTL: time.Hour, PrivateKey: key}
+	foreign := AuthService{TokenTTL: time.Hour, PrivateKey: foreignKey}
+	ctx := context.Background()
+
+	token, err := foreign.CreateToken(ctx, &model.User{UserID: 1})
+	require.NoError(t, err)
+	_, err = s.ValidateToken(ctx, token)
+	assert.ErrorIs(t, err, ErrInvalidToken, "token signed with another key should be rejected")
+
+	expired := AuthService{TokenTTL: -time.Hour, PrivateKey: key}
+	token, err = expired.CreateToken(ctx, &model.User{UserID: 1})
+	require.NoError(t, err)
+	_, err = s.ValidateToken(ctx, token)
+	assert.ErrorIs(t, err, ErrInvalidToken, "expired token should be rejected")
+}
+
+func TestAuthService_CreateImpersonationToken(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	s := AuthService{
+		TokenTTL:         24 * time.Hour,
+		ImpersonationTTL: 15 * time.Minute,
+		PrivateKey:       key,
+	}
+	ctx := context.Background()
+	user := &model.User{UserID: 2, Email: "johndoe@example.com"}
+	actor := &model.User{UserID: 1, Email: "admin@example.com"}
+
+	token, err := s.CreateImpersonationToken(ctx, user, actor)
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(s.ImpersonationTTL), token.ExpiresAt, time.Minute)
+
+	p, err := s.ValidateToken(ctx, token.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, user.UserID, p.UserID)
+	assert.True(t, p.IsImpersonated())
+	assert.Equal(t, &model.Actor{Subject: "1", UserID: actor.UserID, Email: actor.Email}, p.Act)
+}
@@ -0,0 +1,188 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/burenotti/rtu-it-lab-recruit/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestTelegramClient_SendMessage(t *testing.T) {
+	var received map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/botsecret/sendMessage", r.URL.Path)
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		_, _ = w.Write([]byte(`{"ok": true, "result": {}}`))
+	}))
+	defer server.Close()
+
+	d := &TelegramDelivery{Client: &TelegramClient{BaseURL: server.URL, Token: "secret"}}
+	err := d.Send(context.Background(), &model.Message{ToAddress: "42", Subject: "Subject", TextBody: "Body\n"})
+	require.NoError(t, err)
+
+	assert.Equal(t, float64(42), received["chat_id"])
+	assert.Equal(t, "Subject\n\nBody", received["text"])
+}
+
+func TestTelegramClient_SendMessage_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`{"ok": false, "description": "Forbidden: bot was blocked by the user"}`))
+	}))
+	defer server.Close()
+
+	client := &TelegramClient{BaseURL: server.URL, Token: "secret"}
+	err := client.SendMessage(context.Background(), 42, "text")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "bot was blocked")
+
+	server.Close()
+	err = client.SendMessage(context.Background(), 42, "text")
+	require.Error(t, err)
+	assert.NotContains(t, err.Error(), "secret", "bot token should not leak to errors")
+}
+
+func TestSMSClient_Send(t *testing.T) {
+	var received smsRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer key", r.Header.Get("Authorization"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	d := &SMSDelivery{Client: &SMSClient{URL: server.URL, APIKey: "key", Sender: "Events"}}
+	err := d.Send(context.Background(), &model.Message{ToAddress: "+79991234567", TextBody: "1234"})
+	require.NoError(t, err)
+	assert.Equal(t, smsRequest{To: "+79991234567", From: "Events", Text: "1234"}, received)
+}
+
+func TestSMSClient_Send_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	client := &SMSClient{URL: server.URL}
+	assert.Error(t, client.Send(context.Background(), "+79991234567", "text"))
+}
+
+type channelStorageStub []model.UserChannel
+
+func (s channelStorageStub) ListChannels(context.Context, int64) ([]model.UserChannel, error) {
+	return s, nil
+}
+
+// preferenceStorageStub stores disabled categories as "channel:category".
+type preferenceStorageStub map[string]bool
+
+func (s preferenceStorageStub) IsEnabled(_ context.Context, _ int64, channel, category string) (bool, error) {
+	return !s[channel+":"+category], nil
+}
+
+type deliveryStub []*model.Message
+
+func (d *deliveryStub) Send(_ context.Context, msg *model.Message) error {
+	*d = append(*d, msg)
+	return nil
+}
+
+func newTestNotifier(t *testing.T, channels []model.UserChannel, configured ...string) (*Notifier, *deliveryStub) {
+	templates, err := LoadMessageTemplates(testTemplatesDir, model.MessageKinds, model.SupportedLocales, model.DefaultLocale)
+	require.NoError(t, err)
+	delivery := &deliveryStub{}
+	return &Notifier{
+		Templates:   templates,
+		Channels:    channelStorageStub(channels),
+		Preferences: preferenceStorageStub{},
+		Unsubscribe: &UnsubscribeLinks{Secret: []byte("secret"), BaseURL: "https://example.com/unsubscribe"},
+		Delivery:    delivery,
+		Configured:  configured,
+	}, delivery
+}
+
+func TestNotifier_SendToPreferred(t *testing.T) {
+	user := &model.User{UserID: 1, Email: "johndoe@example.com"}
+	data := passwordDeliveryContext{User: user, Code: "1234"}
+	telegram := model.UserChannel{Channel: model.ChannelTelegram, Address: "42", Enabled: true, Priority: 10}
+	sms := model.UserChannel{Channel: model.ChannelSMS, Address: "+79991234567", Enabled: true, Priority: 5}
+
+	cases := []struct {
+		name       string
+		channels   []model.UserChannel
+		configured []string
+		channel    string
+		address    string
+	}{
+		{"no channels", nil, []string{"email", "telegram"}, model.ChannelEmail, user.Email},
+		{"highest priority", []model.UserChannel{sms, telegram}, []string{"email", "telegram", "sms"}, model.ChannelTelegram, "42"},
+		{"not configured", []model.UserChannel{sms, telegram}, []string{"email", "sms"}, model.ChannelSMS, "+79991234567"},
+		{"all disabled", []model.UserChannel{{Channel: model.ChannelEmail, Enabled: false}}, []string{"email"}, model.ChannelEmail, user.Email},
+		{"in-app skipped", []model.UserChannel{sms, {Channel: model.ChannelInApp, Enabled: true, Priority: 50}}, []string{"email", "sms", "inapp"}, model.ChannelSMS, "+79991234567"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			n, delivery := newTestNotifier(t, c.channels, c.configured...)
+			require.NoError(t, n.SendToPreferred(context.Background(), user, model.MessagePassCode, data))
+			require.Len(t, *delivery, 1)
+			assert.Equal(t, c.channel, (*delivery)[0].Channel)
+			assert.Equal(t, c.address, (*delivery)[0].ToAddress)
+		})
+	}
+}
+
+func TestNotifier_SendToAll(t *testing.T) {
+	user := &model.User{UserID: 1, Email: "johndoe@example.com"}
+	n, delivery := newTestNotifier(t, []model.UserChannel{
+		{Channel: model.ChannelEmail, Address: "old@example.com", Enabled: true},
+		{Channel: model.ChannelTelegram, Address: "42", Enabled: true, Priority: 1},
+		{Channel: model.ChannelSMS, Address: "+79991234567", Enabled: false},
+	}, model.ChannelEmail, model.ChannelTelegram, model.ChannelSMS)
+
+	err := n.SendToAll(context.Background(), user, model.MessagePassCode, passwordDeliveryContext{User: user})
+	require.NoError(t, err)
+	require.Len(t, *delivery, 2, "disabled channels should be skipped")
+	assert.Equal(t, model.ChannelTelegram, (*delivery)[0].Channel)
+	assert.Equal(t, user.Email, (*delivery)[1].ToAddress, "email should be sent to the current address of the user")
+}
+
+func TestNotifier_SendToAll_InApp(t *testing.T) {
+	user := &model.User{UserID: 1, Email: "johndoe@example.com"}
+	n, delivery := newTestNotifier(t, nil, model.ChannelEmail, model.ChannelInApp)
+
+	err := n.SendToAll(context.Background(), user, model.MessagePassCode, passwordDeliveryContext{User: user})
+	require.NoError(t, err)
+	require.Len(t, *delivery, 2, "in-app channel should be enabled by default")
+	assert.Equal(t, model.ChannelInApp, (*delivery)[1].Channel)
+	assert.Equal(t, user.UserID, (*delivery)[1].UserID)
+}
+
+func TestNotifier_SendToAll_Preferences(t *testing.T) {
+	user := &model.User{UserID: 1, Email: "johndoe@example.com"}
+	n, delivery := newTestNotifier(t, []model.UserChannel{
+		{Channel: model.ChannelTelegram, Address: "42", Enabled: true},
+		{Channel: model.ChannelSMS, Address: "+79991234567", Enabled: true},
+	}, model.ChannelEmail, model.ChannelTelegram, model.ChannelSMS)
+	n.Preferences = preferenceStorageStub{"sms:reminders": true}
+	data := map[string]interface{}{"User": user, "Event": &model.Event{Name: "Lecture"}}
+
+	require.NoError(t, n.SendToAll(context.Background(), user, model.MessageEventReminder, data))
+	require.Len(t, *delivery, 2, "opted out channels should be skipped")
+	email, telegram := (*delivery)[0], (*delivery)[1]
+	assert.Equal(t, model.ChannelEmail, email.Channel)
+	assert.True(t, strings.HasPrefix(email.UnsubscribeURL, "https://example.com/unsubscribe/"))
+	assert.Contains(t, email.TextBody, email.UnsubscribeURL, "unsubscribe link should be embedded in the email")
+	assert.Equal(t, model.ChannelTelegram, telegram.Channel)
+	assert.Empty(t, telegram.UnsubscribeURL, "unsubscribe links are sent by email only")
+
+	*delivery = nil
+	n.Preferences = preferenceStorageStub{"email:reminders": true, "telegram:reminders": true, "sms:reminders": true}
+	require.NoError(t, n.SendToAll(context.Background(), user, model.MessagePassCode, passwordDeliveryContext{User: user}))
+	require.Len(t, *delivery, 3, "transactional messages should ignore preferences")
+	assert.Empty(t, (*delivery)[0].UnsubscribeURL)
+}
@@ -6,24 +6,18 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
+// ConsoleDelivery writes messages to the log instead of sending them. Use it for local development only.
 type ConsoleDelivery struct {
 	Logger *logrus.Logger
 }
 
-func (c *ConsoleDelivery) SendCode(_ context.Context, user *model.User, code string) error {
+func (c *ConsoleDelivery) Send(_ context.Context, msg *model.Message) error {
 	c.Logger.
-		WithField("email", user.Email).
-		WithField("code", code).
-		Infof("ConsoleDelivery new login code")
-
-	return nil
-}
-
-func (c *ConsoleDelivery) SendActivationToken(_ context.Context, user *model.User, token string) error {
-	c.Logger.
-		WithField("email", user.Email).
-		WithField("token", token).
-		Infof("ConsoleDelivery new activation token")
+		WithField("channel", msg.Channel).
+		WithField("kind", msg.Kind).
+		WithField("to", msg.ToAddress).
+		WithField("body", msg.TextBody).
+		Infof("ConsoleDelivery new %s message", msg.Kind)
 
 	return nil
 }
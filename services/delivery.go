@@ -0,0 +1,83 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"github.com/burenotti/rtu-it-lab-recruit/model"
+	"net/url"
+	"strings"
+)
+
+type Delivery interface {
+	Send(ctx context.Context, msg *model.Message) error
+}
+
+type MessageQueue interface {
+	Enqueue(ctx context.Context, msg *model.Message) error
+}
+
+// OutboxDelivery puts messages to the outbox instead of sending them right away.
+// They are sent by dispatcher once the transaction is committed.
+type OutboxDelivery struct {
+	Queue MessageQueue
+}
+
+func (d *OutboxDelivery) Send(ctx context.Context, msg *model.Message) error {
+	return d.Queue.Enqueue(ctx, msg)
+}
+
+// ChannelRouter sends messages with the delivery of message's channel.
+type ChannelRouter map[string]Delivery
+
+func (r ChannelRouter) Send(ctx context.Context, msg *model.Message) error {
+	delivery, ok := r[msg.Channel]
+	if !ok {
+		return fmt.Errorf("channel %s is not configured", msg.Channel)
+	}
+	return delivery.Send(ctx, msg)
+}
+
+// Channels returns names of configured channels.
+func (r ChannelRouter) Channels() []string {
+	channels := make([]string, 0, len(r))
+	for channel := range r {
+		channels = append(channels, channel)
+	}
+	return channels
+}
+
+type activationTemplateContext struct {
+	User          *model.User
+	ActivationURL string
+}
+
+// ActivationTokenDelivery always sends activation links by email, as they confirm the email address.
+type ActivationTokenDelivery struct {
+	Notifier *Notifier
+	// ActivationURL is the address of activation endpoint, token is appended to it.
+	ActivationURL string
+}
+
+func (d *ActivationTokenDelivery) SendActivationToken(ctx context.Context, user *model.User, token string) error {
+	return d.Notifier.SendTo(ctx, user, EmailChannel(user), model.MessageActivation, activationTemplateContext{
+		User:          user,
+		ActivationURL: strings.TrimSuffix(d.ActivationURL, "/") + "/" + url.PathEscape(token),
+	})
+}
+
+// PassCodeDelivery sends login codes to the preferred channel of the user.
+type PassCodeDelivery struct {
+	Notifier *Notifier
+}
+
+func (d *PassCodeDelivery) SendCode(ctx context.Context, user *model.User, code string) error {
+	return d.Notifier.SendToPreferred(ctx, user, model.MessagePassCode, passwordDeliveryContext{
+		User: user,
+		Code: code,
+	})
+}
+
+type passwordDeliveryContext struct {
+	User *model.User
+	Code string
+}
@@ -0,0 +1,79 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/burenotti/rtu-it-lab-recruit/model"
+	"strconv"
+	"sync"
+)
+
+var ErrPaymentNotFound = errors.New("payment does not exist")
+
+// FakePaymentProvider keeps payments in memory and charges nobody. Use it for local development and tests only.
+// Payments are pending until they are completed with Succeed or Cancel, with AutoConfirm they succeed at once.
+type FakePaymentProvider struct {
+	// BaseURL is the prefix of confirmation URLs of payments.
+	BaseURL     string
+	AutoConfirm bool
+
+	mu       sync.Mutex
+	lastId   int64
+	payments map[string]*model.Payment
+}
+
+func (p *FakePaymentProvider) CreatePayment(_ context.Context, request *model.PaymentRequest) (*model.Payment, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.payments == nil {
+		p.payments = map[string]*model.Payment{}
+	}
+	p.lastId++
+	id := "fake-" + strconv.FormatInt(p.lastId, 10)
+	payment := &model.Payment{
+		PaymentID:       id,
+		Status:          model.PaymentPending,
+		ConfirmationURL: fmt.Sprintf("%s/%s?order_id=%d", p.BaseURL, id, request.OrderID),
+	}
+	if p.AutoConfirm {
+		payment.Status = model.PaymentSucceeded
+	}
+	p.payments[id] = payment
+	copied := *payment
+	return &copied, nil
+}
+
+func (p *FakePaymentProvider) GetPayment(_ context.Context, paymentId string) (*model.Payment, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	payment, ok := p.payments[paymentId]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrPaymentNotFound, paymentId)
+	}
+	copied := *payment
+	return &copied, nil
+}
+
+// CancelPayment cancels pending payment, completed payments are left as they are.
+func (p *FakePaymentProvider) CancelPayment(_ context.Context, paymentId string) error {
+	return p.complete(paymentId, model.PaymentCancelled)
+}
+
+// Succeed completes pending payment as if the user paid.
+func (p *FakePaymentProvider) Succeed(paymentId string) error {
+	return p.complete(paymentId, model.PaymentSucceeded)
+}
+
+func (p *FakePaymentProvider) complete(paymentId, status string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	payment, ok := p.payments[paymentId]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrPaymentNotFound, paymentId)
+	}
+	if payment.Status == model.PaymentPending {
+		payment.Status = status
+	}
+	return nil
+}
@@ -1,18 +1,15 @@
 package services
 
 import (
-	"fmt"
+	"context"
 	"github.com/burenotti/rtu-it-lab-recruit/model"
 	"gopkg.in/gomail.v2"
-	"os"
 	"time"
 )
 
 type MailingConfig struct {
 	FromAddress string
 	FromName    string
-	Subject     string
-	ContentType string
 }
 
 type MailingService struct {
@@ -29,15 +26,26 @@ func NewMailingService(dialer *gomail.Dialer, cfg MailingConfig) *MailingService
 	}
 }
 
-func (s *MailingService) Send(user *model.User, text string) error {
+// Send sends multipart/alternative message with plain text and HTML parts.
+func (s *MailingService) Send(_ context.Context, email *model.Message) error {
+	msg := NewMessage(s.Cfg, email)
+	return s.Dialer.DialAndSend(msg)
+}
+
+func NewMessage(cfg MailingConfig, email *model.Message) *gomail.Message {
 	msg := gomail.NewMessage()
-	name := fmt.Sprintf("%s %s", user.FirstName, user.LastName)
-	msg.SetAddressHeader("To", user.Email, name)
-	msg.SetAddressHeader("From", s.Cfg.FromAddress, s.Cfg.FromName)
-	msg.SetHeader("Subject", s.Cfg.Subject)
-	msg.SetDateHeader("X-Date", time.Now())
-	msg.SetBody(s.Cfg.ContentType, text)
-	msg.WriteTo(os.Stdout)
-	err := s.Dialer.DialAndSend(msg)
-	return err
+	msg.SetAddressHeader("To", email.ToAddress, email.ToName)
+	msg.SetAddressHeader("From", cfg.FromAddress, cfg.FromName)
+	msg.SetHeader("Subject", email.Subject)
+	msg.SetDateHeader("Date", time.Now())
+	if email.UnsubscribeURL != "" {
+		// RFC 8058 one-click unsubscribe: mail clients POST to the link without opening it in browser
+		msg.SetHeader("List-Unsubscribe", "<"+email.UnsubscribeURL+">")
+		msg.SetHeader("List-Unsubscribe-Post", "List-Unsubscribe=One-Click")
+	}
+	msg.SetBody("text/plain", email.TextBody)
+	if email.HTMLBody != "" {
+		msg.AddAlternative("text/html", email.HTMLBody)
+	}
+	return msg
 }
@@ -0,0 +1,136 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"github.com/burenotti/rtu-it-lab-recruit/model"
+	htmltemplate "html/template"
+	"io/fs"
+	"path/filepath"
+	"strings"
+	texttemplate "text/template"
+)
+
+const (
+	subjectTemplateName = "subject"
+	// unsubscribeFuncName is the template function returning the unsubscribe link of the message.
+	// It returns empty string for transactional messages and channels without links.
+	unsubscribeFuncName = "unsubscribeURL"
+)
+
+// MessageTemplate is a pair of templates rendered to HTML and plain text parts of the message.
+// The subject is defined in the plain text template as {{ define "subject" }}...{{ end }}.
+// Templates are never executed directly, they are cloned to bind the unsubscribe link of the message.
+type MessageTemplate struct {
+	Kind string
+	HTML *htmltemplate.Template
+	Text *texttemplate.Template
+}
+
+// LoadMessageTemplate parses <kind>.html and <kind>.txt templates from the directory.
+func LoadMessageTemplate(dir, kind string) (*MessageTemplate, error) {
+	html, err := htmltemplate.New(kind + ".html").
+		Funcs(htmltemplate.FuncMap{unsubscribeFuncName: unsubscribeFunc("")}).
+		ParseFiles(filepath.Join(dir, kind+".html"))
+	if err != nil {
+		return nil, err
+	}
+	text, err := texttemplate.New(kind + ".txt").
+		Funcs(texttemplate.FuncMap{unsubscribeFuncName: unsubscribeFunc("")}).
+		ParseFiles(filepath.Join(dir, kind+".txt"))
+	if err != nil {
+		return nil, err
+	}
+	if text.Lookup(subjectTemplateName) == nil {
+		return nil, fmt.Errorf("template %s.txt does not define subject", kind)
+	}
+	return &MessageTemplate{Kind: kind, HTML: html, Text: text}, nil
+}
+
+// Render renders the message to the user. Recipient address is set by the caller according to the channel.
+// Unsubscribe link is available to templates as {{ unsubscribeURL }}, it may be empty.
+func (t *MessageTemplate) Render(user *model.User, data interface{}, unsubscribeURL string) (*model.Message, error) {
+	textTemplate, err := t.Text.Clone()
+	if err != nil {
+		return nil, err
+	}
+	textTemplate.Funcs(texttemplate.FuncMap{unsubscribeFuncName: unsubscribeFunc(unsubscribeURL)})
+	htmlTemplate, err := t.HTML.Clone()
+	if err != nil {
+		return nil, err
+	}
+	htmlTemplate.Funcs(htmltemplate.FuncMap{unsubscribeFuncName: unsubscribeFunc(unsubscribeURL)})
+
+	var subject, html, text bytes.Buffer
+	if err := textTemplate.ExecuteTemplate(&subject, subjectTemplateName, data); err != nil {
+		return nil, err
+	}
+	if err := textTemplate.Execute(&text, data); err != nil {
+		return nil, err
+	}
+	if err := htmlTemplate.Execute(&html, data); err != nil {
+		return nil, err
+	}
+	return &model.Message{
+		UserID:         user.UserID,
+		Kind:           t.Kind,
+		ToName:         strings.TrimSpace(fmt.Sprintf("%s %s", user.FirstName, user.LastName)),
+		Subject:        strings.TrimSpace(subject.String()),
+		TextBody:       text.String(),
+		HTMLBody:       html.String(),
+		UnsubscribeURL: unsubscribeURL,
+	}, nil
+}
+
+func unsubscribeFunc(url string) func() string {
+	return func() string {
+		return url
+	}
+}
+
+// MessageTemplates is a registry of message templates keyed by message kind and locale.
+type MessageTemplates struct {
+	defaultLocale string
+	// templates maps message kind to its translations keyed by locale
+	templates map[string]map[string]*MessageTemplate
+}
+
+// LoadMessageTemplates loads templates of all kinds from <dir>/<locale>/ directories.
+// Every template must be translated to default locale, translations to other locales are optional.
+func LoadMessageTemplates(dir string, kinds, locales []string, defaultLocale string) (*MessageTemplates, error) {
+	registry := &MessageTemplates{
+		defaultLocale: defaultLocale,
+		templates:     make(map[string]map[string]*MessageTemplate),
+	}
+	for _, kind := range kinds {
+		registry.templates[kind] = make(map[string]*MessageTemplate)
+		for _, locale := range locales {
+			t, err := LoadMessageTemplate(filepath.Join(dir, locale), kind)
+			if errors.Is(err, fs.ErrNotExist) && locale != defaultLocale {
+				continue
+			} else if err != nil {
+				return nil, fmt.Errorf("can't load %s template of %s locale: %w", kind, locale, err)
+			}
+			registry.templates[kind][locale] = t
+		}
+	}
+	return registry, nil
+}
+
+// Get returns template of the kind in the locale. If there is no such translation,
+// it falls back to the base language (en-US -> en) and then to default locale.
+func (r *MessageTemplates) Get(kind, locale string) (*MessageTemplate, error) {
+	translations, ok := r.templates[kind]
+	if !ok {
+		return nil, fmt.Errorf("unknown email kind %s", kind)
+	}
+	locale = strings.ToLower(strings.ReplaceAll(locale, "_", "-"))
+	base, _, _ := strings.Cut(locale, "-")
+	for _, candidate := range []string{locale, base, r.defaultLocale} {
+		if t, ok := translations[candidate]; ok {
+			return t, nil
+		}
+	}
+	return nil, fmt.Errorf("there is no %s template in default locale %s", kind, r.defaultLocale)
+}
@@ -0,0 +1,153 @@
+package services
+
+import (
+	"bytes"
+	"github.com/burenotti/rtu-it-lab-recruit/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+const testTemplatesDir = "../templates/messages"
+
+func TestLoadMessageTemplate_Render(t *testing.T) {
+	tmpl, err := LoadMessageTemplate(testTemplatesDir+"/ru", model.MessagePassCode)
+	require.NoError(t, err)
+
+	user := &model.User{UserID: 1, FirstName: "<John>", LastName: "Doe", Email: "johndoe@example.com"}
+	msg, err := tmpl.Render(user, passwordDeliveryContext{User: user, Code: "1234"}, "")
+	require.NoError(t, err)
+
+	assert.Equal(t, model.MessagePassCode, msg.Kind)
+	assert.Equal(t, "Код для входа", msg.Subject)
+	assert.Equal(t, "<John> Doe", msg.ToName)
+	assert.Contains(t, msg.TextBody, "1234")
+	assert.Contains(t, msg.TextBody, "<John>", "plain text part should not be escaped")
+	assert.Contains(t, msg.HTMLBody, "<strong>1234</strong>")
+	assert.Contains(t, msg.HTMLBody, "&lt;John&gt;", "HTML part should be escaped")
+}
+
+func TestLoadMessageTemplate_NotFound(t *testing.T) {
+	_, err := LoadMessageTemplate(testTemplatesDir+"/ru", "unknown")
+	assert.Error(t, err)
+}
+
+func TestMessageTemplates_Get(t *testing.T) {
+	templates, err := LoadMessageTemplates(testTemplatesDir, model.MessageKinds, model.SupportedLocales, model.DefaultLocale)
+	require.NoError(t, err)
+	user := &model.User{FirstName: "John"}
+
+	cases := []struct {
+		locale  string
+		subject string
+	}{
+		{"en", "Sign-in code"},
+		{"en-US", "Sign-in code"},
+		{"en_GB", "Sign-in code"},
+		{"ru", "Код для входа"},
+		{"de", "Код для входа"},
+		{"", "Код для входа"},
+	}
+	for _, c := range cases {
+		t.Run(c.locale, func(t *testing.T) {
+			tmpl, err := templates.Get(model.MessagePassCode, c.locale)
+			require.NoError(t, err)
+			msg, err := tmpl.Render(user, passwordDeliveryContext{User: user, Code: "1234"}, "")
+			require.NoError(t, err)
+			assert.Equal(t, c.subject, msg.Subject)
+		})
+	}
+
+	_, err = templates.Get("unknown", model.LocaleEn)
+	assert.Error(t, err)
+}
+
+func TestLoadMessageTemplate_EventChanged(t *testing.T) {
+	tmpl, err := LoadMessageTemplate(testTemplatesDir+"/en", model.MessageEventChanged)
+	require.NoError(t, err)
+
+	user := &model.User{FirstName: "John"}
+	msg, err := tmpl.Render(user, map[string]interface{}{
+		"User":  user,
+		"Event": &model.Event{Name: "Lecture"},
+		"Changes": []model.EventChange{
+			{Field: "begins_at", Old: "01.05.2023 10:00 UTC", New: "01.05.2023 11:00 UTC"},
+		},
+	}, "")
+	require.NoError(t, err)
+
+	assert.Equal(t, "Event changed: Lecture", msg.Subject)
+	assert.Contains(t, msg.TextBody, "Begins: 01.05.2023 10:00 UTC → 01.05.2023 11:00 UTC")
+}
+
+func TestNewMessage(t *testing.T) {
+	msg := NewMessage(MailingConfig{FromAddress: "noreply@example.com", FromName: "Events"}, &model.Message{
+		ToAddress: "johndoe@example.com",
+		ToName:    "John Doe",
+		Subject:   "Subject",
+		TextBody:  "text",
+		HTMLBody:  "<p>html</p>",
+	})
+	var buf bytes.Buffer
+	_, err := msg.WriteTo(&buf)
+	require.NoError(t, err)
+
+	raw := buf.String()
+	assert.Contains(t, raw, "multipart/alternative")
+	assert.Contains(t, raw, "Content-Type: text/plain")
+	assert.Contains(t, raw, "Content-Type: text/html")
+	assert.Contains(t, raw, "From: \"Events\" <noreply@example.com>")
+	assert.Contains(t, raw, "Subject: Subject")
+}
+
+func TestNewMessage_Unsubscribe(t *testing.T) {
+	msg := NewMessage(MailingConfig{FromAddress: "noreply@example.com"}, &model.Message{
+		ToAddress:      "johndoe@example.com",
+		Subject:        "Subject",
+		TextBody:       "text",
+		UnsubscribeURL: "https://example.com/unsubscribe/token",
+	})
+	assert.Equal(t, []string{"<https://example.com/unsubscribe/token>"}, msg.GetHeader("List-Unsubscribe"))
+	assert.Equal(t, []string{"List-Unsubscribe=One-Click"}, msg.GetHeader("List-Unsubscribe-Post"))
+
+	transactional := NewMessage(MailingConfig{}, &model.Message{ToAddress: "johndoe@example.com"})
+	assert.Empty(t, transactional.GetHeader("List-Unsubscribe"))
+}
+
+func TestLoadMessageTemplate_WeeklyDigest(t *testing.T) {
+	tmpl, err := LoadMessageTemplate(testTemplatesDir+"/en", model.MessageWeeklyDigest)
+	require.NoError(t, err)
+
+	user := &model.User{FirstName: "John"}
+	msg, err := tmpl.Render(user, map[string]interface{}{
+		"User": user,
+		"Followed": []model.Event{
+			{Name: "Lecture", BeginsAt: time.Date(2023, 5, 1, 10, 0, 0, 0, time.UTC)},
+			{Name: "Hackathon", BeginsAt: time.Date(2023, 5, 3, 12, 30, 0, 0, time.UTC)},
+		},
+	}, "")
+	require.NoError(t, err)
+
+	assert.Equal(t, "Upcoming events of the week", msg.Subject)
+	assert.Contains(t, msg.TextBody, "- Lecture, Mon 01.05 at 10:00 UTC\n- Hackathon, Wed 03.05 at 12:30 UTC")
+	assert.Contains(t, msg.HTMLBody, "<strong>Hackathon</strong>")
+}
+
+func TestLoadMessageTemplate_WeeklyDigestSearches(t *testing.T) {
+	tmpl, err := LoadMessageTemplate(testTemplatesDir+"/en", model.MessageWeeklyDigest)
+	require.NoError(t, err)
+
+	user := &model.User{FirstName: "John"}
+	msg, err := tmpl.Render(user, map[string]interface{}{
+		"User": user,
+		"Searches": []map[string]interface{}{{
+			"Search": &model.SavedSearch{Name: "Concerts"},
+			"Events": []model.Event{{Name: "Jazz", BeginsAt: time.Date(2023, 5, 1, 19, 0, 0, 0, time.UTC)}},
+		}},
+	}, "")
+	require.NoError(t, err)
+
+	assert.Equal(t, "Hello, John!\n\nUpcoming events matching your search \"Concerts\":\n- Jazz, Mon 01.05 at 19:00 UTC\n", msg.TextBody)
+	assert.NotContains(t, msg.HTMLBody, "organizations you follow")
+}
@@ -0,0 +1,98 @@
+package services
+
+import (
+	"context"
+	"github.com/burenotti/rtu-it-lab-recruit/model"
+	"strings"
+	"sync"
+)
+
+type NotificationStorage interface {
+	Create(ctx context.Context, n *model.Notification) (*model.Notification, error)
+}
+
+// InAppDelivery puts messages to the inbox of the user. Notifications are stored in the same transaction
+// the message is sent in, so they don't need the outbox.
+type InAppDelivery struct {
+	Store NotificationStorage
+}
+
+func (d *InAppDelivery) Send(ctx context.Context, msg *model.Message) error {
+	_, err := d.Store.Create(ctx, &model.Notification{
+		UserID: msg.UserID,
+		Kind:   msg.Kind,
+		Title:  msg.Subject,
+		Body:   strings.TrimSpace(msg.TextBody),
+	})
+	return err
+}
+
+// NotificationHub wakes up notification streams of users when new notifications are created.
+// Signals only tell the stream to fetch new notifications from the storage, so a lost or coalesced signal
+// delays the notification instead of losing it.
+type NotificationHub struct {
+	mu          sync.Mutex
+	subscribers map[int64]map[chan struct{}]struct{}
+	closed      bool
+}
+
+func NewNotificationHub() *NotificationHub {
+	return &NotificationHub{
+		subscribers: make(map[int64]map[chan struct{}]struct{}),
+	}
+}
+
+// Subscribe returns the channel signals for the user are sent to and the function cancelling the subscription.
+// The channel is closed when the hub is closed.
+func (h *NotificationHub) Subscribe(userId int64) (<-chan struct{}, func()) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	ch := make(chan struct{}, 1)
+	if h.closed {
+		close(ch)
+		return ch, func() {}
+	}
+	if h.subscribers[userId] == nil {
+		h.subscribers[userId] = make(map[chan struct{}]struct{})
+	}
+	h.subscribers[userId][ch] = struct{}{}
+
+	return ch, func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		if _, ok := h.subscribers[userId][ch]; !ok {
+			return
+		}
+		delete(h.subscribers[userId], ch)
+		if len(h.subscribers[userId]) == 0 {
+			delete(h.subscribers, userId)
+		}
+		close(ch)
+	}
+}
+
+// Notify signals streams of the user. It never blocks: if a subscriber has a pending signal, the new one is dropped.
+func (h *NotificationHub) Notify(signal model.NotificationSignal) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for ch := range h.subscribers[signal.UserID] {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Close closes channels of all subscribers, so streams are finished before the server is shut down.
+func (h *NotificationHub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.closed = true
+	for userId, subscribers := range h.subscribers {
+		for ch := range subscribers {
+			close(ch)
+		}
+		delete(h.subscribers, userId)
+	}
+}
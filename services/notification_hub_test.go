@@ -0,0 +1,68 @@
+package services
+
+import (
+	"context"
+	"github.com/burenotti/rtu-it-lab-recruit/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+type notificationStorageStub []*model.Notification
+
+func (s *notificationStorageStub) Create(_ context.Context, n *model.Notification) (*model.Notification, error) {
+	*s = append(*s, n)
+	return n, nil
+}
+
+func TestInAppDelivery_Send(t *testing.T) {
+	store := &notificationStorageStub{}
+	d := &InAppDelivery{Store: store}
+	err := d.Send(context.Background(), &model.Message{
+		UserID:   1,
+		Kind:     model.MessageEventChanged,
+		Channel:  model.ChannelInApp,
+		Subject:  "Event changed",
+		TextBody: "Hello!\n\nThe event is moved.\n",
+		HTMLBody: "<p>Hello!</p>",
+	})
+	require.NoError(t, err)
+	require.Len(t, *store, 1)
+	assert.Equal(t, &model.Notification{
+		UserID: 1,
+		Kind:   model.MessageEventChanged,
+		Title:  "Event changed",
+		Body:   "Hello!\n\nThe event is moved.",
+	}, (*store)[0])
+}
+
+func TestNotificationHub(t *testing.T) {
+	hub := NewNotificationHub()
+	first, unsubscribeFirst := hub.Subscribe(1)
+	second, unsubscribeSecond := hub.Subscribe(1)
+	other, unsubscribeOther := hub.Subscribe(2)
+	defer unsubscribeOther()
+
+	hub.Notify(model.NotificationSignal{NotificationID: 1, UserID: 1})
+	hub.Notify(model.NotificationSignal{NotificationID: 2, UserID: 1})
+	assert.Len(t, first, 1, "pending signals should be coalesced")
+	assert.Len(t, second, 1)
+	assert.Len(t, other, 0, "streams of other users should not be signalled")
+
+	unsubscribeFirst()
+	unsubscribeFirst()
+	_, ok := <-first
+	assert.True(t, ok, "pending signal should be received before the channel is closed")
+	_, ok = <-first
+	assert.False(t, ok, "channel should be closed on unsubscribe")
+
+	hub.Close()
+	<-second
+	_, ok = <-second
+	assert.False(t, ok, "channels should be closed with the hub")
+	unsubscribeSecond()
+
+	closed, _ := hub.Subscribe(1)
+	_, ok = <-closed
+	assert.False(t, ok, "subscriptions to closed hub should be closed right away")
+}
@@ -0,0 +1,160 @@
+package services
+
+import (
+	"context"
+	"github.com/burenotti/rtu-it-lab-recruit/model"
+	"sort"
+)
+
+type ChannelStorage interface {
+	ListChannels(ctx context.Context, userId int64) ([]model.UserChannel, error)
+}
+
+type PreferenceStorage interface {
+	IsEnabled(ctx context.Context, userId int64, channel, category string) (bool, error)
+}
+
+type UnsubscribeURLIssuer interface {
+	URL(claims model.UnsubscribeClaims) string
+}
+
+// Notifier renders messages in the user's locale and routes them to the user's channels.
+type Notifier struct {
+	Templates   *MessageTemplates
+	Channels    ChannelStorage
+	Preferences PreferenceStorage
+	// Unsubscribe issues links embedded in non-transactional emails.
+	Unsubscribe UnsubscribeURLIssuer
+	Delivery    Delivery
+	// Configured are channels messages could be delivered by. Channels of users which are not configured are skipped.
+	Configured []string
+}
+
+// SendTo sends the message to the specified channel of the user.
+// Non-transactional messages are skipped if the user has opted out of their category in the channel.
+func (n *Notifier) SendTo(ctx context.Context, user *model.User, channel *model.UserChannel, kind string, data interface{}) error {
+	unsubscribeURL := ""
+	if category := model.MessageCategory(kind); category != "" {
+		enabled, err := n.Preferences.IsEnabled(ctx, user.UserID, channel.Channel, category)
+		if err != nil || !enabled {
+			return err
+		}
+		if channel.Channel == model.ChannelEmail {
+			unsubscribeURL = n.Unsubscribe.URL(model.UnsubscribeClaims{
+				UserID:   user.UserID,
+				Channel:  channel.Channel,
+				Category: category,
+			})
+		}
+	}
+
+	template, err := n.Templates.Get(kind, user.PreferredLocale)
+	if err != nil {
+		return err
+	}
+	msg, err := template.Render(user, data, unsubscribeURL)
+	if err != nil {
+		return err
+	}
+	msg.Channel = channel.Channel
+	msg.ToAddress = channel.Address
+	return n.Delivery.Send(ctx, msg)
+}
+
+// SendToPreferred sends the message to the enabled channel with the highest priority.
+// In-app channel is skipped, as the user may not be signed in to read it.
+// If the user has disabled all channels, the message is sent by email, so the user can still sign in.
+func (n *Notifier) SendToPreferred(ctx context.Context, user *model.User, kind string, data interface{}) error {
+	channels, err := n.UserChannels(ctx, user)
+	if err != nil {
+		return err
+	}
+	channel := EmailChannel(user)
+	for i := range channels {
+		if channels[i].Channel != model.ChannelInApp {
+			channel = &channels[i]
+			break
+		}
+	}
+	return n.SendTo(ctx, user, channel, kind, data)
+}
+
+// SendToAll sends the message to every enabled channel of the user.
+func (n *Notifier) SendToAll(ctx context.Context, user *model.User, kind string, data interface{}) error {
+	channels, err := n.UserChannels(ctx, user)
+	if err != nil {
+		return err
+	}
+	for i := range channels {
+		if err = n.SendTo(ctx, user, &channels[i], kind, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// UserChannels returns enabled and configured channels of the user ordered by priority.
+func (n *Notifier) UserChannels(ctx context.Context, user *model.User) ([]model.UserChannel, error) {
+	linked, err := n.Channels.ListChannels(ctx, user.UserID)
+	if err != nil {
+		return nil, err
+	}
+	linked = WithDefaultChannels(user, linked)
+
+	channels := make([]model.UserChannel, 0, len(linked))
+	for _, channel := range linked {
+		if channel.Enabled && n.isConfigured(channel.Channel) {
+			channels = append(channels, channel)
+		}
+	}
+	sort.SliceStable(channels, func(i, j int) bool {
+		return channels[i].Priority > channels[j].Priority
+	})
+	return channels, nil
+}
+
+func (n *Notifier) isConfigured(channel string) bool {
+	for _, c := range n.Configured {
+		if c == channel {
+			return true
+		}
+	}
+	return false
+}
+
+// EmailChannel is the default email channel of the user.
+func EmailChannel(user *model.User) *model.UserChannel {
+	return &model.UserChannel{
+		UserID:  user.UserID,
+		Channel: model.ChannelEmail,
+		Address: user.Email,
+		Enabled: true,
+	}
+}
+
+// InAppChannel is the default in-app channel of the user. Messages sent by it are put to the user's inbox.
+func InAppChannel(user *model.User) *model.UserChannel {
+	return &model.UserChannel{
+		UserID:  user.UserID,
+		Channel: model.ChannelInApp,
+		Enabled: true,
+	}
+}
+
+// WithDefaultChannels puts email and in-app channels first. Their preferences may be stored, but the email address
+// is always the current email of the user. If there are no stored preferences, default channels are used.
+func WithDefaultChannels(user *model.User, linked []model.UserChannel) []model.UserChannel {
+	channels := []model.UserChannel{*EmailChannel(user), *InAppChannel(user)}
+	for _, channel := range linked {
+		switch channel.Channel {
+		case model.ChannelEmail:
+			channel.Address = user.Email
+			channels[0] = channel
+		case model.ChannelInApp:
+			channels[1] = channel
+		default:
+			channels = append(channels, channel)
+		}
+	}
+	return channels
+}
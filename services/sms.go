@@ -0,0 +1,63 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/burenotti/rtu-it-lab-recruit/model"
+	"net/http"
+)
+
+// SMSClient sends SMS through HTTP gateway. The gateway accepts JSON with
+// recipient phone, sender name and text, and authenticates requests by bearer API key.
+type SMSClient struct {
+	URL        string
+	APIKey     string
+	Sender     string
+	HTTPClient *http.Client
+}
+
+type smsRequest struct {
+	To   string `json:"to"`
+	From string `json:"from,omitempty"`
+	Text string `json:"text"`
+}
+
+func (c *SMSClient) Send(ctx context.Context, phone, text string) error {
+	body, err := json.Marshal(smsRequest{To: phone, From: c.Sender, Text: text})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+c.APIKey)
+
+	client := c.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("sms gateway request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("sms gateway responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// SMSDelivery sends messages by SMS. Address of the message is the phone number in E.164 format.
+type SMSDelivery struct {
+	Client *SMSClient
+}
+
+func (d *SMSDelivery) Send(ctx context.Context, msg *model.Message) error {
+	return d.Client.Send(ctx, msg.ToAddress, messageText(msg))
+}
@@ -0,0 +1,95 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/burenotti/rtu-it-lab-recruit/model"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const DefaultTelegramAPIURL = "https://api.telegram.org"
+
+// TelegramClient is a minimal client of Telegram Bot API.
+type TelegramClient struct {
+	BaseURL    string
+	Token      string
+	HTTPClient *http.Client
+}
+
+type telegramResponse struct {
+	Ok          bool   `json:"ok"`
+	Description string `json:"description"`
+}
+
+// SendMessage sends plain text message to the chat.
+func (c *TelegramClient) SendMessage(ctx context.Context, chatId int64, text string) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"chat_id": chatId,
+		"text":    text,
+	})
+	if err != nil {
+		return err
+	}
+
+	endpoint := fmt.Sprintf("%s/bot%s/sendMessage", strings.TrimSuffix(c.BaseURL, "/"), c.Token)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient().Do(req)
+	if err != nil {
+		// Error contains request url with bot token, so only the cause is returned.
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("telegram request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var result telegramResponse
+	if err = json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("telegram responded with status %d", resp.StatusCode)
+	}
+	if !result.Ok {
+		return fmt.Errorf("telegram responded with status %d: %s", resp.StatusCode, result.Description)
+	}
+	return nil
+}
+
+func (c *TelegramClient) httpClient() *http.Client {
+	if c.HTTPClient != nil {
+		return c.HTTPClient
+	}
+	return http.DefaultClient
+}
+
+// TelegramDelivery sends messages to Telegram chats. Address of the message is the chat id.
+type TelegramDelivery struct {
+	Client *TelegramClient
+}
+
+func (d *TelegramDelivery) Send(ctx context.Context, msg *model.Message) error {
+	chatId, err := strconv.ParseInt(msg.ToAddress, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid telegram chat id %q", msg.ToAddress)
+	}
+	return d.Client.SendMessage(ctx, chatId, messageText(msg))
+}
+
+// messageText joins subject and plain text body for channels without subjects.
+func messageText(msg *model.Message) string {
+	text := strings.TrimSpace(msg.TextBody)
+	if msg.Subject == "" {
+		return text
+	}
+	return msg.Subject + "\n\n" + text
+}
@@ -0,0 +1,75 @@
+package services
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"github.com/burenotti/rtu-it-lab-recruit/model"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrInvalidTicketCode = errors.New("ticket code is invalid")
+)
+
+// TicketCodes signs codes of tickets shown as QR codes. Codes are signed with the RSA key of the service,
+// so scanners can verify them offline with the public key.
+type TicketCodes struct {
+	PrivateKey *rsa.PrivateKey
+}
+
+func (c *TicketCodes) Code(claims model.TicketClaims) (string, error) {
+	payload := fmt.Sprintf("%d:%d", claims.RegistrationID, claims.EventID)
+	hash := sha256.Sum256([]byte(payload))
+	signature, err := rsa.SignPKCS1v15(nil, c.PrivateKey, crypto.SHA256, hash[:])
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString([]byte(payload)) + "." +
+		base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// Verify checks the signature of the code and returns its claims.
+func (c *TicketCodes) Verify(code string) (*model.TicketClaims, error) {
+	encodedPayload, encodedSignature, ok := strings.Cut(code, ".")
+	if !ok {
+		return nil, ErrInvalidTicketCode
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+	if err != nil {
+		return nil, ErrInvalidTicketCode
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(encodedSignature)
+	if err != nil {
+		return nil, ErrInvalidTicketCode
+	}
+	hash := sha256.Sum256(payload)
+	if rsa.VerifyPKCS1v15(&c.PrivateKey.PublicKey, crypto.SHA256, hash[:], signature) != nil {
+		return nil, ErrInvalidTicketCode
+	}
+
+	registrationId, eventId, ok := strings.Cut(string(payload), ":")
+	if !ok {
+		return nil, ErrInvalidTicketCode
+	}
+	claims := &model.TicketClaims{}
+	if claims.RegistrationID, err = strconv.ParseInt(registrationId, 10, 64); err != nil {
+		return nil, ErrInvalidTicketCode
+	}
+	if claims.EventID, err = strconv.ParseInt(eventId, 10, 64); err != nil {
+		return nil, ErrInvalidTicketCode
+	}
+	return claims, nil
+}
+
+// PublicKey returns PEM encoded public key codes are verified with.
+func (c *TicketCodes) PublicKey() string {
+	der, _ := x509.MarshalPKIXPublicKey(&c.PrivateKey.PublicKey)
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+}
@@ -0,0 +1,51 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"github.com/burenotti/rtu-it-lab-recruit/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func TestTicketCodes(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	foreignKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	codes := &TicketCodes{PrivateKey: key}
+	claims := model.TicketClaims{RegistrationID: 7, EventID: 42}
+
+	code, err := codes.Code(claims)
+	require.NoError(t, err)
+	verified, err := codes.Verify(code)
+	require.NoError(t, err)
+	assert.Equal(t, &claims, verified)
+
+	payload, signature, _ := strings.Cut(code, ".")
+	forged, err := codes.Code(model.TicketClaims{RegistrationID: 8, EventID: 42})
+	require.NoError(t, err)
+	forgedPayload, _, _ := strings.Cut(forged, ".")
+	foreign, err := (&TicketCodes{PrivateKey: foreignKey}).Code(claims)
+	require.NoError(t, err)
+
+	for name, code := range map[string]string{
+		"empty":           "",
+		"no signature":    payload,
+		"foreign payload": forgedPayload + "." + signature,
+		"foreign key":     foreign,
+	} {
+		_, err = codes.Verify(code)
+		assert.ErrorIs(t, err, ErrInvalidTicketCode, name)
+	}
+
+	block, _ := pem.Decode([]byte(codes.PublicKey()))
+	require.NotNil(t, block)
+	publicKey, err := x509.ParsePKIXPublicKey(block.Bytes)
+	require.NoError(t, err)
+	assert.True(t, key.PublicKey.Equal(publicKey))
+}
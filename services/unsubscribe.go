@@ -0,0 +1,67 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"github.com/burenotti/rtu-it-lab-recruit/model"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrInvalidUnsubscribeToken = errors.New("unsubscribe token is invalid")
+)
+
+// UnsubscribeLinks issues links opting users out of message categories without signing in.
+// Tokens are signed with HMAC, so they don't need to be stored and never expire.
+type UnsubscribeLinks struct {
+	Secret []byte
+	// BaseURL is the address of unsubscribe endpoint, token is appended to it.
+	BaseURL string
+}
+
+func (l *UnsubscribeLinks) URL(claims model.UnsubscribeClaims) string {
+	return strings.TrimSuffix(l.BaseURL, "/") + "/" + url.PathEscape(l.Token(claims))
+}
+
+func (l *UnsubscribeLinks) Token(claims model.UnsubscribeClaims) string {
+	payload := fmt.Sprintf("%d:%s:%s", claims.UserID, claims.Channel, claims.Category)
+	return base64.RawURLEncoding.EncodeToString([]byte(payload)) + "." +
+		base64.RawURLEncoding.EncodeToString(l.sign(payload))
+}
+
+// Verify checks the signature of the token and returns its claims.
+func (l *UnsubscribeLinks) Verify(token string) (*model.UnsubscribeClaims, error) {
+	encodedPayload, encodedSignature, ok := strings.Cut(token, ".")
+	if !ok {
+		return nil, ErrInvalidUnsubscribeToken
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+	if err != nil {
+		return nil, ErrInvalidUnsubscribeToken
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(encodedSignature)
+	if err != nil || !hmac.Equal(signature, l.sign(string(payload))) {
+		return nil, ErrInvalidUnsubscribeToken
+	}
+
+	parts := strings.Split(string(payload), ":")
+	if len(parts) != 3 {
+		return nil, ErrInvalidUnsubscribeToken
+	}
+	userId, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return nil, ErrInvalidUnsubscribeToken
+	}
+	return &model.UnsubscribeClaims{UserID: userId, Channel: parts[1], Category: parts[2]}, nil
+}
+
+func (l *UnsubscribeLinks) sign(payload string) []byte {
+	mac := hmac.New(sha256.New, l.Secret)
+	mac.Write([]byte(payload))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,35 @@
+package services
+
+import (
+	"github.com/burenotti/rtu-it-lab-recruit/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func TestUnsubscribeLinks(t *testing.T) {
+	links := &UnsubscribeLinks{Secret: []byte("secret"), BaseURL: "https://example.com/unsubscribe/"}
+	claims := model.UnsubscribeClaims{UserID: 42, Channel: model.ChannelEmail, Category: model.CategoryDigests}
+
+	token := links.Token(claims)
+	assert.Equal(t, "https://example.com/unsubscribe/"+token, links.URL(claims))
+	verified, err := links.Verify(token)
+	require.NoError(t, err)
+	assert.Equal(t, &claims, verified)
+
+	payload, signature, _ := strings.Cut(token, ".")
+	forged := links.Token(model.UnsubscribeClaims{UserID: 43, Channel: model.ChannelEmail, Category: model.CategoryDigests})
+	forgedPayload, _, _ := strings.Cut(forged, ".")
+	other := &UnsubscribeLinks{Secret: []byte("other")}
+
+	for name, token := range map[string]string{
+		"empty":           "",
+		"no signature":    payload,
+		"foreign payload": forgedPayload + "." + signature,
+		"other secret":    other.Token(claims),
+	} {
+		_, err = links.Verify(token)
+		assert.ErrorIs(t, err, ErrInvalidUnsubscribeToken, name)
+	}
+}
@@ -0,0 +1,159 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/burenotti/rtu-it-lab-recruit/model"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+var (
+	ErrWebhookAddressForbidden = errors.New("endpoint address is not allowed")
+	ErrWebhookUnreachable      = errors.New("endpoint could not be reached")
+)
+
+// carrierGradeNAT is the shared address space (RFC 6598), it is not reachable from the internet either.
+var carrierGradeNAT = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+const (
+	WebhookIDHeader        = "X-Webhook-Id"
+	WebhookEventHeader     = "X-Webhook-Event"
+	WebhookTimestampHeader = "X-Webhook-Timestamp"
+	WebhookSignatureHeader = "X-Webhook-Signature"
+)
+
+type WebhookQueue interface {
+	EnqueueDeliveries(ctx context.Context, orgId int64, eventType, payload string) error
+}
+
+// WebhookEmitter schedules delivery of organization's events to its webhooks.
+type WebhookEmitter struct {
+	Queue WebhookQueue
+}
+
+// Emit must be called in the same transaction with the changes the event is about.
+func (e *WebhookEmitter) Emit(ctx context.Context, orgId int64, eventType string, data interface{}) error {
+	payload, err := json.Marshal(model.WebhookPayload{
+		Type:      eventType,
+		CreatedAt: time.Now().UTC(),
+		Data:      data,
+	})
+	if err != nil {
+		return err
+	}
+	return e.Queue.EnqueueDeliveries(ctx, orgId, eventType, string(payload))
+}
+
+// WebhookStatusError is returned when the endpoint responded with non-2xx status.
+type WebhookStatusError struct {
+	StatusCode int
+}
+
+func (e *WebhookStatusError) Error() string {
+	return fmt.Sprintf("endpoint responded with status %d", e.StatusCode)
+}
+
+// NewWebhookHTTPClient returns the client which only connects to public addresses, so webhooks
+// can't be used to reach internal services or cloud metadata. The address is checked when connecting,
+// after the host is resolved, and redirects are not followed.
+func NewWebhookHTTPClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !IsPublicIP(ip) {
+				return ErrWebhookAddressForbidden
+			}
+			return nil
+		},
+	}
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			// Proxy is not used, otherwise the address of the proxy would be checked instead of the endpoint.
+			Proxy:                 nil,
+			DialContext:           dialer.DialContext,
+			ForceAttemptHTTP2:     true,
+			MaxIdleConns:          100,
+			IdleConnTimeout:       90 * time.Second,
+			TLSHandshakeTimeout:   10 * time.Second,
+			ExpectContinueTimeout: time.Second,
+		},
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// IsPublicIP tells whether the address is routable in the internet. Private, loopback, link-local
+// (including the metadata address 169.254.169.254), multicast and unspecified addresses are not.
+func IsPublicIP(ip net.IP) bool {
+	return !ip.IsPrivate() &&
+		!ip.IsLoopback() &&
+		!ip.IsLinkLocalUnicast() &&
+		!ip.IsLinkLocalMulticast() &&
+		!ip.IsInterfaceLocalMulticast() &&
+		!ip.IsMulticast() &&
+		!ip.IsUnspecified() &&
+		!carrierGradeNAT.Contains(ip)
+}
+
+// WebhookClient sends deliveries to endpoints. Requests are signed with the secret of the webhook:
+// the signature is hex encoded HMAC-SHA256 of the timestamp and the body joined with a dot.
+// Receivers should reject requests with old timestamps to prevent replays.
+type WebhookClient struct {
+	HTTPClient *http.Client
+}
+
+// Send returns the status code of the response, or zero if the endpoint did not respond.
+// Errors are safe to show to the owner of the webhook, they don't reveal details of the network.
+func (c *WebhookClient) Send(ctx context.Context, d *model.WebhookDelivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.URL, bytes.NewBufferString(d.Payload))
+	if err != nil {
+		return 0, err
+	}
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "rtu-it-lab-recruit-webhooks")
+	req.Header.Set(WebhookIDHeader, strconv.FormatInt(d.DeliveryID, 10))
+	req.Header.Set(WebhookEventHeader, d.EventType)
+	req.Header.Set(WebhookTimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(WebhookSignatureHeader, "sha256="+SignWebhookPayload(d.Secret, timestamp, []byte(d.Payload)))
+
+	resp, err := c.HTTPClient.Do(req)
+	if errors.Is(err, ErrWebhookAddressForbidden) {
+		return 0, ErrWebhookAddressForbidden
+	} else if err != nil {
+		return 0, ErrWebhookUnreachable
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, &WebhookStatusError{StatusCode: resp.StatusCode}
+	}
+	return resp.StatusCode, nil
+}
+
+// SignWebhookPayload returns the signature of the payload sent at the timestamp.
+func SignWebhookPayload(secret string, timestamp int64, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,101 @@
+package services
+
+import (
+	"context"
+	"github.com/burenotti/rtu-it-lab-recruit/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestWebhookClient_Send(t *testing.T) {
+	delivery := &model.WebhookDelivery{
+		DeliveryID: 7,
+		EventType:  model.WebhookEventPublished,
+		Payload:    `{"type":"event.published","data":{}}`,
+		Secret:     "secret",
+	}
+	received := make(chan *http.Request, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		timestamp, err := strconv.ParseInt(r.Header.Get(WebhookTimestampHeader), 10, 64)
+		if err != nil || r.Header.Get(WebhookSignatureHeader) != "sha256="+SignWebhookPayload("secret", timestamp, body) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		received <- r
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+	delivery.URL = server.URL
+
+	client := &WebhookClient{HTTPClient: server.Client()}
+	status, err := client.Send(context.Background(), delivery)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, status)
+
+	r := <-received
+	assert.Equal(t, "7", r.Header.Get(WebhookIDHeader))
+	assert.Equal(t, model.WebhookEventPublished, r.Header.Get(WebhookEventHeader))
+	assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+}
+
+func TestWebhookClient_Send_ErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	client := &WebhookClient{HTTPClient: server.Client()}
+	status, err := client.Send(context.Background(), &model.WebhookDelivery{URL: server.URL, Payload: "{}"})
+	assert.Equal(t, http.StatusBadGateway, status)
+	assert.ErrorAs(t, err, new(*WebhookStatusError))
+}
+
+func TestWebhookClient_Send_PrivateAddress(t *testing.T) {
+	requested := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = true
+	}))
+	defer server.Close()
+
+	client := &WebhookClient{HTTPClient: NewWebhookHTTPClient(time.Second)}
+	status, err := client.Send(context.Background(), &model.WebhookDelivery{URL: server.URL, Payload: "{}"})
+	assert.Zero(t, status)
+	assert.ErrorIs(t, err, ErrWebhookAddressForbidden)
+	assert.False(t, requested, "webhooks should not reach local services")
+}
+
+func TestIsPublicIP(t *testing.T) {
+	for address, expected := range map[string]bool{
+		"93.184.216.34":   true,
+		"2606:4700::1111": true,
+		"127.0.0.1":       false,
+		"10.1.2.3":        false,
+		"172.16.0.1":      false,
+		"192.168.1.1":     false,
+		"169.254.169.254": false,
+		"100.64.0.1":      false,
+		"0.0.0.0":         false,
+		"::1":             false,
+		"fd00::1":         false,
+		"fe80::1":         false,
+		"::ffff:10.0.0.1": false,
+	} {
+		assert.Equal(t, expected, IsPublicIP(net.ParseIP(address)), address)
+	}
+}
+
+func TestSignWebhookPayload(t *testing.T) {
+	signature := SignWebhookPayload("secret", 1700000000, []byte("{}"))
+	assert.Len(t, signature, 64)
+	assert.Equal(t, signature, SignWebhookPayload("secret", 1700000000, []byte("{}")))
+	assert.NotEqual(t, signature, SignWebhookPayload("secret", 1700000001, []byte("{}")), "timestamp should be signed")
+	assert.NotEqual(t, signature, SignWebhookPayload("other", 1700000000, []byte("{}")))
+}
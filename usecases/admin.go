@@ -0,0 +1,273 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/burenotti/rtu-it-lab-recruit/model"
+	"github.com/burenotti/rtu-it-lab-recruit/repositories"
+	"github.com/sirupsen/logrus"
+	"time"
+)
+
+const defaultPageSize = 20
+
+var (
+	ErrNotAdmin        = errors.New("platform administrator role required")
+	ErrUserBanned      = errors.New("user is banned")
+	ErrUserDeactivated = errors.New("user is deactivated")
+	ErrSelfAction      = errors.New("administrator can't perform this action on themselves")
+)
+
+type AdminUserStorage interface {
+	GetById(ctx context.Context, userId int64) (*model.User, error)
+	Update(ctx context.Context, userId int64, update map[string]interface{}) (*model.User, error)
+	Search(ctx context.Context, query string, limit, offset int) ([]model.User, error)
+	PromoteAdmins(ctx context.Context, emails []string) (int64, error)
+}
+
+type AdminOrganizationStorage interface {
+	GetById(ctx context.Context, orgId int64) (*model.Organization, error)
+	GetMember(ctx context.Context, orgId int64, userId int64) (*model.OrganizationMember, error)
+	TransferOwnership(ctx context.Context, orgId, userId int64) error
+}
+
+type AdminEventStorage interface {
+	Hide(ctx context.Context, eventId int64, reason string) error
+	Unhide(ctx context.Context, eventId int64) error
+}
+
+type ImpersonationTokenIssuer interface {
+	CreateImpersonationToken(ctx context.Context, user, actor *model.User) (*model.ImpersonationToken, error)
+}
+
+type AuditLogStorage interface {
+	Record(ctx context.Context, entry *model.AuditEntryCreate) error
+	List(ctx context.Context, limit, offset int) ([]model.AuditEntry, error)
+}
+
+// AdminUseCase implements platform administration. Every action is recorded to audit log
+// in the same transaction, so there is no action without audit entry.
+type AdminUseCase struct {
+	Transactioner StorageTransactioner
+	Users         AdminUserStorage
+	Organizations AdminOrganizationStorage
+	Events        AdminEventStorage
+	Audit         AuditLogStorage
+	Tokens        ImpersonationTokenIssuer
+	Webhooks      WebhookEmitter
+	Logger        *logrus.Logger
+}
+
+func (u *AdminUseCase) IsAdmin(ctx context.Context, userId int64) (bool, error) {
+	user, err := u.Users.GetById(ctx, userId)
+	if errors.Is(err, repositories.ErrUserNotFound) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return user.IsAdmin && !user.IsBanned(), nil
+}
+
+// PromoteAdmins grants administrator role to users with provided emails. It is used to bootstrap the first admins.
+func (u *AdminUseCase) PromoteAdmins(ctx context.Context, emails []string) error {
+	count, err := u.Users.PromoteAdmins(ctx, emails)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		u.Logger.WithField("count", count).Infof("Promoted %d users to administrators", count)
+	}
+	return nil
+}
+
+func (u *AdminUseCase) SearchUsers(ctx context.Context, search *model.UserSearch) ([]model.User, error) {
+	limit := search.Limit
+	if limit == 0 {
+		limit = defaultPageSize
+	}
+	return u.Users.Search(ctx, search.Query, limit, search.Offset)
+}
+
+func (u *AdminUseCase) ActivateUser(ctx context.Context, actorId, userId int64) (*model.User, error) {
+	return u.updateUser(ctx, actorId, userId, model.AuditUserActivate, nil, repositories.UpdatesMap{
+		"is_active":      true,
+		"deactivated_at": nil,
+	})
+}
+
+func (u *AdminUseCase) DeactivateUser(ctx context.Context, actorId, userId int64) (*model.User, error) {
+	if actorId == userId {
+		return nil, ErrSelfAction
+	}
+	return u.updateUser(ctx, actorId, userId, model.AuditUserDeactivate, nil, repositories.UpdatesMap{
+		"deactivated_at": time.Now().UTC(),
+	})
+}
+
+func (u *AdminUseCase) BanUser(ctx context.Context, actorId, userId int64, reason string) (*model.User, error) {
+	if actorId == userId {
+		return nil, ErrSelfAction
+	}
+	details := map[string]interface{}{"reason": reason}
+	return u.updateUser(ctx, actorId, userId, model.AuditUserBan, details, repositories.UpdatesMap{
+		"banned_at":  time.Now().UTC(),
+		"ban_reason": reason,
+	})
+}
+
+func (u *AdminUseCase) UnbanUser(ctx context.Context, actorId, userId int64) (*model.User, error) {
+	return u.updateUser(ctx, actorId, userId, model.AuditUserUnban, nil, repositories.UpdatesMap{
+		"banned_at":  nil,
+		"ban_reason": nil,
+	})
+}
+
+// Impersonate issues a short-lived token which lets the administrator see the service as the user does.
+// Impersonating other administrators is forbidden, so the token can't be used to gain any extra privileges.
+func (u *AdminUseCase) Impersonate(ctx context.Context, actorId, userId int64) (*model.ImpersonationToken, error) {
+	if actorId == userId {
+		return nil, ErrSelfAction
+	}
+	var token *model.ImpersonationToken
+	err := u.Transactioner.Atomic(ctx, func(ctx context.Context) error {
+		actor, err := u.Users.GetById(ctx, actorId)
+		if err != nil {
+			return err
+		}
+		user, err := u.Users.GetById(ctx, userId)
+		if err != nil {
+			return err
+		}
+		if user.IsAdmin {
+			return fmt.Errorf("%w: administrators can't be impersonated", ErrBusinessLogicViolation)
+		}
+		if token, err = u.Tokens.CreateImpersonationToken(ctx, user, actor); err != nil {
+			return err
+		}
+		return u.audit(ctx, actorId, model.AuditUserImpersonate, model.AuditTargetUser, userId,
+			map[string]interface{}{"expires_at": token.ExpiresAt})
+	})
+	return token, err
+}
+
+// RecordImpersonatedRequest writes a request made with impersonation token to audit log.
+func (u *AdminUseCase) RecordImpersonatedRequest(ctx context.Context, actorId, userId int64, method, path string) error {
+	return u.Audit.Record(ctx, &model.AuditEntryCreate{
+		ActorID:    actorId,
+		Action:     model.AuditImpersonatedRequest,
+		TargetType: model.AuditTargetUser,
+		TargetID:   userId,
+		Details: map[string]interface{}{
+			"method": method,
+			"path":   path,
+		},
+	})
+}
+
+// TransferOwnership forcibly makes the user the only owner of the organization.
+// If the user was not a member, organization's webhooks are notified about the new member.
+func (u *AdminUseCase) TransferOwnership(ctx context.Context, actorId, orgId, userId int64) error {
+	return u.Transactioner.Atomic(ctx, func(ctx context.Context) error {
+		if _, err := u.Organizations.GetById(ctx, orgId); err != nil {
+			return err
+		}
+		if _, err := u.Users.GetById(ctx, userId); err != nil {
+			return err
+		}
+		_, err := u.Organizations.GetMember(ctx, orgId, userId)
+		joined := errors.Is(err, repositories.ErrMemberNotFound)
+		if err != nil && !joined {
+			return err
+		}
+		if err = u.Organizations.TransferOwnership(ctx, orgId, userId); err != nil {
+			return err
+		}
+		if joined {
+			member, err := u.Organizations.GetMember(ctx, orgId, userId)
+			if err != nil {
+				return err
+			}
+			err = u.Webhooks.Emit(ctx, orgId, model.WebhookMemberJoined, model.WebhookMemberData{
+				OrganizationID: orgId,
+				Member:         member,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return u.audit(ctx, actorId, model.AuditOrganizationTransfer, model.AuditTargetOrganization, orgId,
+			map[string]interface{}{"new_owner_id": userId})
+	})
+}
+
+func (u *AdminUseCase) HideEvent(ctx context.Context, actorId, eventId int64, reason string) error {
+	return u.Transactioner.Atomic(ctx, func(ctx context.Context) error {
+		if err := u.Events.Hide(ctx, eventId, reason); err != nil {
+			return err
+		}
+		return u.audit(ctx, actorId, model.AuditEventHide, model.AuditTargetEvent, eventId,
+			map[string]interface{}{"reason": reason})
+	})
+}
+
+func (u *AdminUseCase) UnhideEvent(ctx context.Context, actorId, eventId int64) error {
+	return u.Transactioner.Atomic(ctx, func(ctx context.Context) error {
+		if err := u.Events.Unhide(ctx, eventId); err != nil {
+			return err
+		}
+		return u.audit(ctx, actorId, model.AuditEventUnhide, model.AuditTargetEvent, eventId, nil)
+	})
+}
+
+func (u *AdminUseCase) ListAuditLog(ctx context.Context, page *model.Pagination) ([]model.AuditEntry, error) {
+	limit := page.Limit
+	if limit == 0 {
+		limit = defaultPageSize
+	}
+	return u.Audit.List(ctx, limit, page.Offset)
+}
+
+func (u *AdminUseCase) updateUser(
+	ctx context.Context,
+	actorId, userId int64,
+	action string,
+	details map[string]interface{},
+	updates repositories.UpdatesMap,
+) (*model.User, error) {
+	var user *model.User
+	err := u.Transactioner.Atomic(ctx, func(ctx context.Context) (err error) {
+		user, err = u.Users.Update(ctx, userId, updates)
+		if err != nil {
+			return err
+		}
+		return u.audit(ctx, actorId, action, model.AuditTargetUser, userId, details)
+	})
+	return user, err
+}
+
+func (u *AdminUseCase) audit(
+	ctx context.Context,
+	actorId int64,
+	action, targetType string,
+	targetId int64,
+	details map[string]interface{},
+) error {
+	u.Logger.
+		WithField("actor_id", actorId).
+		WithField("action", action).
+		WithField(targetType+"_id", targetId).
+		Infof("Admin %d performed %s on %s %d", actorId, action, targetType, targetId)
+
+	err := u.Audit.Record(ctx, &model.AuditEntryCreate{
+		ActorID:    actorId,
+		Action:     action,
+		TargetType: targetType,
+		TargetID:   targetId,
+		Details:    details,
+	})
+	if err != nil {
+		return fmt.Errorf("can't record audit entry: %w", err)
+	}
+	return nil
+}
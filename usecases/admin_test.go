@@ -0,0 +1,153 @@
+package usecases
+
+import (
+	"context"
+	"github.com/burenotti/rtu-it-lab-recruit/model"
+	"github.com/burenotti/rtu-it-lab-recruit/repositories"
+	"github.com/burenotti/rtu-it-lab-recruit/usecases/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+	"time"
+)
+
+func TestAdminUseCase_BanUser(t *testing.T) {
+	ctx := context.Background()
+	users := mocks.NewAdminUserStorage(t)
+	audit := mocks.NewAuditLogStorage(t)
+	u := &AdminUseCase{Transactioner: newTestTransactioner(t), Users: users, Audit: audit, Logger: newTestLogger()}
+	reason := "Spam"
+	now := time.Now()
+	banned := &model.User{UserID: 2, BannedAt: &now, BanReason: &reason}
+
+	users.On("Update", mock.Anything, banned.UserID, mock.MatchedBy(func(updates map[string]interface{}) bool {
+		return updates["ban_reason"] == reason && updates["banned_at"] != nil
+	})).Return(banned, nil)
+	audit.On("Record", mock.Anything, &model.AuditEntryCreate{
+		ActorID:    1,
+		Action:     model.AuditUserBan,
+		TargetType: model.AuditTargetUser,
+		TargetID:   banned.UserID,
+		Details:    map[string]interface{}{"reason": reason},
+	}).Return(nil)
+
+	user, err := u.BanUser(ctx, 1, banned.UserID, reason)
+	assert.NoError(t, err)
+	assert.True(t, user.IsBanned())
+}
+
+func TestAdminUseCase_BanUser_Self(t *testing.T) {
+	ctx := context.Background()
+	u := &AdminUseCase{}
+
+	_, err := u.BanUser(ctx, 1, 1, "Spam")
+	assert.ErrorIs(t, err, ErrSelfAction, "admin should not be able to ban themselves")
+}
+
+func TestAdminUseCase_IsAdmin_Banned(t *testing.T) {
+	ctx := context.Background()
+	users := mocks.NewAdminUserStorage(t)
+	u := &AdminUseCase{Users: users}
+	now := time.Now()
+
+	users.On("GetById", mock.Anything, int64(1)).Return(&model.User{UserID: 1, IsAdmin: true, BannedAt: &now}, nil)
+
+	isAdmin, err := u.IsAdmin(ctx, 1)
+	assert.NoError(t, err)
+	assert.False(t, isAdmin, "banned admin should lose administrator rights")
+}
+
+func TestEmailSignInUseCase_RequestCode_Banned(t *testing.T) {
+	ctx := context.Background()
+	users := mocks.NewUserStorage(t)
+	s := EmailSignInUseCase{
+		UserStore:     users,
+		Transactioner: newTestTransactioner(t),
+	}
+	now := time.Now()
+	user := &model.User{UserID: 1, Email: "johndoe@example.com", IsActive: true, BannedAt: &now}
+	users.On("GetByEmail", mock.Anything, user.Email).Return(user, nil)
+
+	err := s.RequestCode(ctx, user.Email)
+	assert.ErrorIs(t, err, ErrUserBanned, "banned user should not receive login code")
+}
+
+func TestAdminUseCase_Impersonate_Admin(t *testing.T) {
+	ctx := context.Background()
+	users := mocks.NewAdminUserStorage(t)
+	u := &AdminUseCase{Transactioner: newTestTransactioner(t), Users: users}
+
+	users.On("GetById", mock.Anything, int64(1)).Return(&model.User{UserID: 1, IsAdmin: true}, nil)
+	users.On("GetById", mock.Anything, int64(2)).Return(&model.User{UserID: 2, IsAdmin: true}, nil)
+
+	_, err := u.Impersonate(ctx, 1, 2)
+	assert.ErrorIs(t, err, ErrBusinessLogicViolation, "administrators should not be impersonated")
+}
+
+func TestAdminUseCase_TransferOwnership_NewMember(t *testing.T) {
+	ctx := context.Background()
+	users := mocks.NewAdminUserStorage(t)
+	audit := mocks.NewAuditLogStorage(t)
+	orgs := mocks.NewAdminOrganizationStorage(t)
+	webhooks := mocks.NewWebhookEmitter(t)
+	u := &AdminUseCase{
+		Transactioner: newTestTransactioner(t),
+		Users:         users,
+		Organizations: orgs,
+		Audit:         audit,
+		Webhooks:      webhooks,
+		Logger:        newTestLogger(),
+	}
+	owner := &model.OrganizationMember{UserID: 2, IsOwner: true}
+
+	orgs.On("GetById", mock.Anything, int64(5)).Return(&model.Organization{OrganizationID: 5}, nil)
+	users.On("GetById", mock.Anything, owner.UserID).Return(&model.User{UserID: owner.UserID}, nil)
+	orgs.On("GetMember", mock.Anything, int64(5), owner.UserID).Return(nil, repositories.ErrMemberNotFound).Once()
+	orgs.On("TransferOwnership", mock.Anything, int64(5), owner.UserID).Return(nil).Once()
+	orgs.On("GetMember", mock.Anything, int64(5), owner.UserID).Return(owner, nil).Once()
+	webhooks.On("Emit", mock.Anything, int64(5), model.WebhookMemberJoined, model.WebhookMemberData{
+		OrganizationID: 5,
+		Member:         owner,
+	}).Return(nil).Once()
+	audit.On("Record", mock.Anything, mock.Anything).Return(nil).Once()
+
+	assert.NoError(t, u.TransferOwnership(ctx, 1, 5, owner.UserID))
+}
+
+func TestAdminUseCase_DeactivateUser(t *testing.T) {
+	ctx := context.Background()
+	users := mocks.NewAdminUserStorage(t)
+	audit := mocks.NewAuditLogStorage(t)
+	u := &AdminUseCase{Transactioner: newTestTransactioner(t), Users: users, Audit: audit, Logger: newTestLogger()}
+	now := time.Now()
+	deactivated := &model.User{UserID: 2, IsActive: true, DeactivatedAt: &now}
+
+	users.On("Update", mock.Anything, deactivated.UserID, mock.MatchedBy(func(updates map[string]interface{}) bool {
+		_, touchesActive := updates["is_active"]
+		return updates["deactivated_at"] != nil && !touchesActive
+	})).Return(deactivated, nil)
+	audit.On("Record", mock.Anything, mock.Anything).Return(nil)
+
+	user, err := u.DeactivateUser(ctx, 1, deactivated.UserID)
+	assert.NoError(t, err)
+	assert.True(t, user.IsDeactivated(), "deactivated account should not look like a never activated one")
+}
+
+func TestEmailSignInUseCase_CanSignIn(t *testing.T) {
+	ctx := context.Background()
+	users := mocks.NewUserStorage(t)
+	s := EmailSignInUseCase{UserStore: users}
+	now := time.Now()
+
+	users.On("GetById", mock.Anything, int64(1)).Return(&model.User{UserID: 1, IsActive: true}, nil)
+	users.On("GetById", mock.Anything, int64(2)).Return(&model.User{UserID: 2, IsActive: true, BannedAt: &now}, nil)
+	users.On("GetById", mock.Anything, int64(3)).Return(&model.User{UserID: 3, IsActive: true, DeactivatedAt: &now}, nil)
+	users.On("GetById", mock.Anything, int64(4)).Return(&model.User{UserID: 4, IsActive: false}, nil)
+	users.On("GetById", mock.Anything, int64(5)).Return(nil, repositories.ErrUserNotFound)
+
+	for userId, expected := range map[int64]bool{1: true, 2: false, 3: false, 4: false, 5: false} {
+		allowed, err := s.CanSignIn(ctx, userId)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, allowed, "user %d", userId)
+	}
+}
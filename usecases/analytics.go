@@ -0,0 +1,293 @@
+package usecases
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"github.com/burenotti/rtu-it-lab-recruit/model"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxAnalyticsPoints limits the length of series, longer periods need longer intervals.
+const maxAnalyticsPoints = 2000
+
+type AnalyticsStorage interface {
+	RefreshActivityStats(ctx context.Context) error
+	ActivitySeries(ctx context.Context, filter *model.AnalyticsFilter) ([]model.AnalyticsPoint, error)
+	TrafficSources(ctx context.Context, filter *model.AnalyticsFilter) ([]model.TrafficSource, error)
+	EventAttendance(ctx context.Context, filter *model.AnalyticsFilter) ([]model.EventAttendance, error)
+}
+
+// AnalyticsUseCase shows organizers registrations and check-ins of their events. Activity is aggregated
+// by the storage in advance and refreshed with RefreshAnalytics, so analytics lag behind a bit.
+// Analytics are available to members with rights to edit events.
+type AnalyticsUseCase struct {
+	Analytics AnalyticsStorage
+	Events    EventGetter
+	Members   EventMemberStorage
+}
+
+// RefreshAnalytics aggregates activity of events made since the last refresh.
+func (u *AnalyticsUseCase) RefreshAnalytics(ctx context.Context) error {
+	return u.Analytics.RefreshActivityStats(ctx)
+}
+
+// GetEventAnalytics returns attendance of the event, its registrations and check-ins over time and by traffic sources.
+func (u *AnalyticsUseCase) GetEventAnalytics(ctx context.Context, userId, eventId int64, query *model.AnalyticsQuery) (*model.EventAnalytics, error) {
+	event, err := u.Events.GetById(ctx, eventId)
+	if err != nil {
+		return nil, err
+	}
+	if err = checkCanEditEvents(ctx, u.Members, event.OrganizationID, userId, "view analytics"); err != nil {
+		return nil, err
+	}
+	filter, err := analyticsFilter(query)
+	if err != nil {
+		return nil, err
+	}
+	filter.EventID = &eventId
+
+	series, err := u.series(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	sources, err := u.Analytics.TrafficSources(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	return &model.EventAnalytics{
+		EventID:         eventId,
+		Interval:        filter.Interval,
+		AttendanceStats: sourcesAttendance(sources),
+		Series:          series,
+		Sources:         sources,
+	}, nil
+}
+
+// GetOrganizationAnalytics returns attendance of events of the organization in total and by events,
+// registrations and check-ins over time and by traffic sources.
+func (u *AnalyticsUseCase) GetOrganizationAnalytics(
+	ctx context.Context, userId, orgId int64, query *model.AnalyticsQuery,
+) (*model.OrganizationAnalytics, error) {
+	if err := checkCanEditEvents(ctx, u.Members, orgId, userId, "view analytics"); err != nil {
+		return nil, err
+	}
+	filter, err := analyticsFilter(query)
+	if err != nil {
+		return nil, err
+	}
+	filter.OrganizationID = &orgId
+
+	series, err := u.series(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	sources, err := u.Analytics.TrafficSources(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	events, err := u.Analytics.EventAttendance(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	for i := range events {
+		events[i].AttendanceStats = attendance(events[i].Registered, events[i].Attended)
+	}
+	return &model.OrganizationAnalytics{
+		OrganizationID:  orgId,
+		Interval:        filter.Interval,
+		AttendanceStats: sourcesAttendance(sources),
+		Series:          series,
+		Sources:         sources,
+		Events:          events,
+	}, nil
+}
+
+// ExportEventAnalytics returns the series of event analytics in CSV.
+func (u *AnalyticsUseCase) ExportEventAnalytics(ctx context.Context, userId, eventId int64, query *model.AnalyticsQuery) ([]byte, error) {
+	analytics, err := u.GetEventAnalytics(ctx, userId, eventId, query)
+	if err != nil {
+		return nil, err
+	}
+	records := [][]string{{"bucket", "registrations", "check_ins"}}
+	for _, p := range analytics.Series {
+		records = append(records, []string{
+			p.Bucket.Format(time.RFC3339),
+			strconv.FormatInt(p.Registrations, 10),
+			strconv.FormatInt(p.CheckIns, 10),
+		})
+	}
+	return writeCSV(records)
+}
+
+// ExportOrganizationAnalytics returns attendance of events of the organization in CSV, an event per row.
+func (u *AnalyticsUseCase) ExportOrganizationAnalytics(ctx context.Context, userId, orgId int64, query *model.AnalyticsQuery) ([]byte, error) {
+	analytics, err := u.GetOrganizationAnalytics(ctx, userId, orgId, query)
+	if err != nil {
+		return nil, err
+	}
+	records := [][]string{{"event_id", "name", "begins_at", "registered", "attended", "no_shows", "no_show_rate"}}
+	for _, e := range analytics.Events {
+		records = append(records, []string{
+			strconv.FormatInt(e.EventID, 10),
+			e.Name,
+			e.BeginsAt.UTC().Format(time.RFC3339),
+			strconv.FormatInt(e.Registered, 10),
+			strconv.FormatInt(e.Attended, 10),
+			strconv.FormatInt(e.NoShows, 10),
+			strconv.FormatFloat(e.NoShowRate, 'f', -1, 64),
+		})
+	}
+	return writeCSV(records)
+}
+
+// series returns activity by intervals of the filter. Intervals without activity are filled with zeros
+// within the period of the filter, or between the first and the last activity if the period is open.
+func (u *AnalyticsUseCase) series(ctx context.Context, filter *model.AnalyticsFilter) ([]model.AnalyticsPoint, error) {
+	points, err := u.Analytics.ActivitySeries(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	var begin, end time.Time
+	if filter.From != nil {
+		begin = bucketStart(*filter.From, filter.Interval)
+	} else if len(points) > 0 {
+		begin = points[0].Bucket
+	} else {
+		return points, nil
+	}
+	if filter.To != nil {
+		end = *filter.To
+	} else if len(points) > 0 {
+		end = nextBucket(points[len(points)-1].Bucket, filter.Interval)
+	} else {
+		return points, nil
+	}
+
+	byBucket := make(map[int64]model.AnalyticsPoint, len(points))
+	for _, p := range points {
+		byBucket[p.Bucket.Unix()] = p
+	}
+	filled := make([]model.AnalyticsPoint, 0, len(points))
+	for bucket := begin; bucket.Before(end); bucket = nextBucket(bucket, filter.Interval) {
+		if len(filled) == maxAnalyticsPoints {
+			return nil, fmt.Errorf("%w: too many intervals, choose longer interval or shorter period", ErrBusinessLogicViolation)
+		}
+		p, ok := byBucket[bucket.Unix()]
+		if !ok {
+			p = model.AnalyticsPoint{Bucket: bucket}
+		}
+		filled = append(filled, p)
+	}
+	return filled, nil
+}
+
+func analyticsFilter(query *model.AnalyticsQuery) (*model.AnalyticsFilter, error) {
+	filter := &model.AnalyticsFilter{Interval: query.Interval}
+	if filter.Interval == "" {
+		filter.Interval = model.IntervalDay
+	}
+	if query.From != "" {
+		from, err := time.Parse("2006-01-02", query.From)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid date %s", ErrBusinessLogicViolation, query.From)
+		}
+		filter.From = &from
+	}
+	if query.To != "" {
+		to, err := time.Parse("2006-01-02", query.To)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid date %s", ErrBusinessLogicViolation, query.To)
+		}
+		// The last day of the period is included.
+		to = to.AddDate(0, 0, 1)
+		filter.To = &to
+	}
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return nil, fmt.Errorf("%w: period must begin before it ends", ErrBusinessLogicViolation)
+	}
+	return filter, nil
+}
+
+// bucketStart returns the beginning of the interval containing the time in UTC. Weeks begin on Monday.
+func bucketStart(t time.Time, interval string) time.Time {
+	t = t.UTC()
+	switch interval {
+	case model.IntervalHour:
+		return t.Truncate(time.Hour)
+	case model.IntervalWeek:
+		day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+		return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+	case model.IntervalMonth:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	}
+}
+
+func nextBucket(bucket time.Time, interval string) time.Time {
+	switch interval {
+	case model.IntervalHour:
+		return bucket.Add(time.Hour)
+	case model.IntervalWeek:
+		return bucket.AddDate(0, 0, 7)
+	case model.IntervalMonth:
+		return bucket.AddDate(0, 1, 0)
+	default:
+		return bucket.AddDate(0, 0, 1)
+	}
+}
+
+func sourcesAttendance(sources []model.TrafficSource) model.AttendanceStats {
+	var registered, attended int64
+	for _, s := range sources {
+		registered += s.Registrations
+		attended += s.CheckIns
+	}
+	return attendance(registered, attended)
+}
+
+// attendance counts no-shows. Within a period some check-ins may be of registrations made before it,
+// so the number of no-shows is never negative.
+func attendance(registered, attended int64) model.AttendanceStats {
+	stats := model.AttendanceStats{Registered: registered, Attended: attended}
+	if registered > attended {
+		stats.NoShows = registered - attended
+	}
+	if registered > 0 {
+		stats.NoShowRate = math.Round(float64(stats.NoShows)/float64(registered)*10000) / 10000
+	}
+	return stats
+}
+
+// trafficSource normalizes the source the user came from, empty source is not saved.
+func trafficSource(source string) *string {
+	source = strings.ToLower(strings.TrimSpace(source))
+	if source == "" {
+		return nil
+	}
+	return &source
+}
+
+// writeCSV writes the records in CSV. Cells which spreadsheets would take for a formula,
+// like an event named "=HYPERLINK(...)", are prefixed with an apostrophe to be shown as text.
+func writeCSV(records [][]string) ([]byte, error) {
+	for _, record := range records {
+		for i, cell := range record {
+			if cell != "" && strings.ContainsRune("=+-@\t\r", rune(cell[0])) {
+				record[i] = "'" + cell
+			}
+		}
+	}
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.WriteAll(records); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,139 @@
+package usecases
+
+import (
+	"context"
+	"github.com/burenotti/rtu-it-lab-recruit/model"
+	"github.com/burenotti/rtu-it-lab-recruit/usecases/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func day(s string) time.Time {
+	t, _ := time.Parse("2006-01-02", s)
+	return t
+}
+
+func TestAnalyticsUseCase_GetEventAnalytics(t *testing.T) {
+	ctx := context.Background()
+	stats := mocks.NewAnalyticsStorage(t)
+	events := mocks.NewEventGetter(t)
+	members := mocks.NewEventMemberStorage(t)
+	u := &AnalyticsUseCase{Analytics: stats, Events: events, Members: members}
+	event := publishedEvent()
+	eventId := event.EventID
+	from, to := day("2023-05-01"), day("2023-05-04")
+	filter := &model.AnalyticsFilter{EventID: &eventId, From: &from, To: &to, Interval: model.IntervalDay}
+
+	events.On("GetById", mock.Anything, event.EventID).Return(event, nil)
+	members.On("GetMember", mock.Anything, event.OrganizationID, int64(3)).
+		Return(&model.OrganizationMember{UserID: 3, Can: model.MemberRights{EditEvents: true}}, nil)
+	stats.On("ActivitySeries", mock.Anything, filter).Return([]model.AnalyticsPoint{
+		{Bucket: day("2023-05-02"), Registrations: 5, CheckIns: 1},
+	}, nil)
+	stats.On("TrafficSources", mock.Anything, filter).Return([]model.TrafficSource{
+		{Source: "telegram", Registrations: 6, CheckIns: 4},
+		{Source: model.SourceDirect, Registrations: 2, CheckIns: 2},
+	}, nil)
+
+	analytics, err := u.GetEventAnalytics(ctx, 3, event.EventID, &model.AnalyticsQuery{From: "2023-05-01", To: "2023-05-03"})
+	require.NoError(t, err)
+	assert.Equal(t, model.AttendanceStats{Registered: 8, Attended: 6, NoShows: 2, NoShowRate: 0.25}, analytics.AttendanceStats)
+	assert.Equal(t, []model.AnalyticsPoint{
+		{Bucket: day("2023-05-01")},
+		{Bucket: day("2023-05-02"), Registrations: 5, CheckIns: 1},
+		{Bucket: day("2023-05-03")},
+	}, analytics.Series, "days without activity are filled with zeros")
+}
+
+func TestAnalyticsUseCase_GetEventAnalytics_InvalidPeriod(t *testing.T) {
+	ctx := context.Background()
+	stats := mocks.NewAnalyticsStorage(t)
+	events := mocks.NewEventGetter(t)
+	members := mocks.NewEventMemberStorage(t)
+	u := &AnalyticsUseCase{Analytics: stats, Events: events, Members: members}
+	event := publishedEvent()
+
+	events.On("GetById", mock.Anything, event.EventID).Return(event, nil)
+	members.On("GetMember", mock.Anything, event.OrganizationID, int64(3)).
+		Return(&model.OrganizationMember{UserID: 3, IsOwner: true}, nil)
+
+	_, err := u.GetEventAnalytics(ctx, 3, event.EventID, &model.AnalyticsQuery{From: "2023-05-03", To: "2023-05-01"})
+	assert.ErrorIs(t, err, ErrBusinessLogicViolation)
+
+	stats.On("ActivitySeries", mock.Anything, mock.Anything).Return([]model.AnalyticsPoint{}, nil)
+	_, err = u.GetEventAnalytics(ctx, 3, event.EventID, &model.AnalyticsQuery{
+		From: "2020-01-01", To: "2023-01-01", Interval: model.IntervalHour,
+	})
+	assert.ErrorIs(t, err, ErrBusinessLogicViolation, "too many intervals")
+}
+
+func TestAnalyticsUseCase_ExportOrganizationAnalytics(t *testing.T) {
+	ctx := context.Background()
+	stats := mocks.NewAnalyticsStorage(t)
+	members := mocks.NewEventMemberStorage(t)
+	u := &AnalyticsUseCase{Analytics: stats, Members: members}
+
+	members.On("GetMember", mock.Anything, int64(2), int64(3)).
+		Return(&model.OrganizationMember{UserID: 3, Can: model.MemberRights{EditEvents: true}}, nil)
+	stats.On("ActivitySeries", mock.Anything, mock.Anything).Return([]model.AnalyticsPoint{}, nil)
+	stats.On("TrafficSources", mock.Anything, mock.Anything).Return([]model.TrafficSource{}, nil)
+	stats.On("EventAttendance", mock.Anything, mock.MatchedBy(func(f *model.AnalyticsFilter) bool {
+		return *f.OrganizationID == 2 && f.EventID == nil
+	})).Return([]model.EventAttendance{{
+		EventID: 1, Name: "Concert, live", BeginsAt: day("2023-05-02"),
+		AttendanceStats: model.AttendanceStats{Registered: 3, Attended: 2},
+	}}, nil)
+
+	data, err := u.ExportOrganizationAnalytics(ctx, 3, 2, &model.AnalyticsQuery{})
+	require.NoError(t, err)
+	assert.Equal(t, "event_id,name,begins_at,registered,attended,no_shows,no_show_rate\n"+
+		"1,\"Concert, live\",2023-05-02T00:00:00Z,3,2,1,0.3333\n", string(data))
+}
+
+func TestAnalyticsUseCase_ExportOrganizationAnalytics_Formula(t *testing.T) {
+	ctx := context.Background()
+	stats := mocks.NewAnalyticsStorage(t)
+	members := mocks.NewEventMemberStorage(t)
+	u := &AnalyticsUseCase{Analytics: stats, Members: members}
+
+	members.On("GetMember", mock.Anything, int64(2), int64(3)).
+		Return(&model.OrganizationMember{UserID: 3, Can: model.MemberRights{EditEvents: true}}, nil)
+	stats.On("ActivitySeries", mock.Anything, mock.Anything).Return([]model.AnalyticsPoint{}, nil)
+	stats.On("TrafficSources", mock.Anything, mock.Anything).Return([]model.TrafficSource{}, nil)
+	stats.On("EventAttendance", mock.Anything, mock.Anything).Return([]model.EventAttendance{
+		{EventID: 1, Name: "=HYPERLINK(\"http://evil.example\")", BeginsAt: day("2023-05-02")},
+		{EventID: 2, Name: "@SUM(A1)", BeginsAt: day("2023-05-03")},
+		{EventID: 3, Name: "Talk - Go", BeginsAt: day("2023-05-04")},
+	}, nil)
+
+	data, err := u.ExportOrganizationAnalytics(ctx, 3, 2, &model.AnalyticsQuery{})
+	require.NoError(t, err)
+	assert.Equal(t, "event_id,name,begins_at,registered,attended,no_shows,no_show_rate\n"+
+		"1,\"'=HYPERLINK(\"\"http://evil.example\"\")\",2023-05-02T00:00:00Z,0,0,0,0\n"+
+		"2,'@SUM(A1),2023-05-03T00:00:00Z,0,0,0,0\n"+
+		"3,Talk - Go,2023-05-04T00:00:00Z,0,0,0,0\n", string(data), "formulas should be exported as text")
+}
+
+func TestAnalyticsUseCase_RequiresRights(t *testing.T) {
+	ctx := context.Background()
+	members := mocks.NewEventMemberStorage(t)
+	u := &AnalyticsUseCase{Members: members}
+
+	members.On("GetMember", mock.Anything, int64(2), int64(3)).
+		Return(&model.OrganizationMember{UserID: 3, Can: model.MemberRights{CheckIn: true}}, nil)
+
+	_, err := u.GetOrganizationAnalytics(ctx, 3, 2, &model.AnalyticsQuery{})
+	assert.ErrorIs(t, err, ErrBusinessLogicViolation)
+}
+
+func TestBucketStart(t *testing.T) {
+	moment := time.Date(2023, 5, 4, 15, 30, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2023, 5, 4, 15, 0, 0, 0, time.UTC), bucketStart(moment, model.IntervalHour))
+	assert.Equal(t, day("2023-05-04"), bucketStart(moment, model.IntervalDay))
+	assert.Equal(t, day("2023-05-01"), bucketStart(moment, model.IntervalWeek), "weeks begin on Monday")
+	assert.Equal(t, day("2023-05-01"), bucketStart(moment, model.IntervalMonth))
+	assert.Equal(t, day("2023-04-24"), bucketStart(day("2023-04-30"), model.IntervalWeek), "Sunday ends the week")
+}
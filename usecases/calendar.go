@@ -0,0 +1,357 @@
+package usecases
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/burenotti/rtu-it-lab-recruit/model"
+	"github.com/burenotti/rtu-it-lab-recruit/pkg/ical"
+	"github.com/burenotti/rtu-it-lab-recruit/pkg/rrule"
+	"github.com/burenotti/rtu-it-lab-recruit/repositories"
+	"time"
+)
+
+const (
+	maxCalendarFeedsPerUser = 20
+	// maxCalendarFeedEvents limits events of a single feed.
+	maxCalendarFeedEvents = 500
+	// calendarFeedHistory is how long past events stay in feeds.
+	calendarFeedHistory = 30 * 24 * time.Hour
+	// calendarFeedAhead is how far ahead feeds contain events.
+	calendarFeedAhead = 2 * 365 * 24 * time.Hour
+)
+
+type CalendarFeedStorage interface {
+	CreateFeed(ctx context.Context, feed *model.CalendarFeed) (*model.CalendarFeed, error)
+	ListFeeds(ctx context.Context, userId int64) ([]model.CalendarFeed, error)
+	GetFeedByToken(ctx context.Context, token string) (*model.CalendarFeed, error)
+	DeleteFeed(ctx context.Context, userId, feedId int64) error
+}
+
+type CalendarEventStorage interface {
+	GetById(ctx context.Context, eventId int64) (*model.Event, error)
+	SelectBy(ctx context.Context, filter repositories.EventFilter) ([]model.Event, error)
+}
+
+type CalendarOccurrenceStorage interface {
+	ListOccurrenceChanges(ctx context.Context, eventIds []int64) ([]model.Occurrence, error)
+	ListRegisteredOccurrences(ctx context.Context, userId int64, since time.Time) ([]model.Occurrence, error)
+}
+
+type CalendarSearchStorage interface {
+	GetSearch(ctx context.Context, userId, searchId int64) (*model.SavedSearch, error)
+}
+
+type CalendarOrganizationStorage interface {
+	GetById(ctx context.Context, orgId int64) (*model.Organization, error)
+}
+
+// CalendarUseCase implements iCalendar export of events and subscribable calendar feeds.
+// Events keep their UIDs across changes, SEQUENCE is incremented on every change.
+type CalendarUseCase struct {
+	Transactioner StorageTransactioner
+	Feeds         CalendarFeedStorage
+	Events        CalendarEventStorage
+	Occurrences   CalendarOccurrenceStorage
+	Searches      CalendarSearchStorage
+	Organizations CalendarOrganizationStorage
+	Venues        EventVenueStorage
+	Tags          EventTagStorage
+	// FeedURL is the address of feeds, the token and .ics extension are appended to it.
+	FeedURL string
+	// Domain is the right-hand side of UIDs, which makes them globally unique.
+	Domain string
+	ProdID string
+}
+
+// CreateCalendarFeed creates the feed of events the user has registered for, events of the organization
+// or events matching the saved search of the user.
+func (u *CalendarUseCase) CreateCalendarFeed(
+	ctx context.Context,
+	userId int64,
+	create *model.CalendarFeedCreate,
+) (*model.CalendarFeed, error) {
+	token, err := newLinkToken()
+	if err != nil {
+		return nil, err
+	}
+	feed := &model.CalendarFeed{UserID: userId, Kind: create.Kind, Token: token}
+	switch create.Kind {
+	case model.CalendarFeedOrganization:
+		feed.OrganizationID = create.OrganizationID
+	case model.CalendarFeedSearch:
+		feed.SearchID = create.SearchID
+	}
+
+	err = u.Transactioner.Atomic(ctx, func(ctx context.Context) error {
+		feeds, err := u.Feeds.ListFeeds(ctx, userId)
+		if err != nil {
+			return err
+		}
+		if len(feeds) >= maxCalendarFeedsPerUser {
+			return fmt.Errorf("%w: user can't have more than %d calendar feeds", ErrBusinessLogicViolation, maxCalendarFeedsPerUser)
+		}
+		if feed.SearchID != nil {
+			if _, err = u.Searches.GetSearch(ctx, userId, *feed.SearchID); err != nil {
+				return err
+			}
+		}
+		feed, err = u.Feeds.CreateFeed(ctx, feed)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	u.setFeedURL(feed)
+	return feed, nil
+}
+
+func (u *CalendarUseCase) ListCalendarFeeds(ctx context.Context, userId int64) ([]model.CalendarFeed, error) {
+	feeds, err := u.Feeds.ListFeeds(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	for i := range feeds {
+		u.setFeedURL(&feeds[i])
+	}
+	return feeds, nil
+}
+
+// DeleteCalendarFeed revokes the feed, its URL stops working.
+func (u *CalendarUseCase) DeleteCalendarFeed(ctx context.Context, userId, feedId int64) error {
+	return u.Feeds.DeleteFeed(ctx, userId, feedId)
+}
+
+// ExportEvent returns iCalendar of published event. Recurring events are exported with their rule,
+// cancelled and changed occurrences.
+func (u *CalendarUseCase) ExportEvent(ctx context.Context, eventId int64) ([]byte, error) {
+	event, err := u.Events.GetById(ctx, eventId)
+	if err != nil {
+		return nil, err
+	}
+	if !event.IsPublished() || event.IsHidden() {
+		return nil, fmt.Errorf("%w: event with provided id does not exist", repositories.ErrEventNotFount)
+	}
+	return u.calendar(ctx, event.Name, []model.Event{*event}, false, nil)
+}
+
+// ExportCalendarFeed returns iCalendar of the feed with the token. Feeds contain events from a month ago
+// up to two years ahead, cancelled events are kept with cancelled status, so calendar apps remove them.
+func (u *CalendarUseCase) ExportCalendarFeed(ctx context.Context, token string) ([]byte, error) {
+	feed, err := u.Feeds.GetFeedByToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	since := now.Add(-calendarFeedHistory)
+	filters := []repositories.EventFilter{
+		repositories.NewEventListedFilter(),
+		repositories.NewEventOccursBetweenFilter(since, now.Add(calendarFeedAhead)),
+	}
+
+	var name string
+	switch feed.Kind {
+	case model.CalendarFeedRegistrations:
+		name = "My registrations"
+		filters = append(filters, repositories.NewEventRegisteredFilter(feed.UserID))
+	case model.CalendarFeedOrganization:
+		org, err := u.Organizations.GetById(ctx, *feed.OrganizationID)
+		if err != nil {
+			return nil, err
+		}
+		name = org.Name
+		filters = append(filters, repositories.NewEventOrganizationFilter(org.OrganizationID))
+	case model.CalendarFeedSearch:
+		search, err := u.Searches.GetSearch(ctx, feed.UserID, *feed.SearchID)
+		if err != nil {
+			return nil, err
+		}
+		name = search.Name
+		filters = append(filters, repositories.NewEventSearchFilter(&search.Filter, now))
+	default:
+		return nil, fmt.Errorf("unknown kind of calendar feed %q", feed.Kind)
+	}
+
+	events, err := u.Events.SelectBy(ctx, repositories.NewLimitFilter(maxCalendarFeedEvents, repositories.NewEventAndFilter(filters...)))
+	if err != nil {
+		return nil, err
+	}
+	if feed.Kind != model.CalendarFeedRegistrations {
+		return u.calendar(ctx, name, events, false, nil)
+	}
+	occurrences, err := u.Occurrences.ListRegisteredOccurrences(ctx, feed.UserID, since)
+	if err != nil {
+		return nil, err
+	}
+	return u.calendar(ctx, name, events, true, occurrences)
+}
+
+// calendar encodes the events. Recurring events are exported as series, unless registered occurrences
+// are given: then recurring events are represented only by them as separate events.
+func (u *CalendarUseCase) calendar(
+	ctx context.Context,
+	name string,
+	events []model.Event,
+	registered bool,
+	occurrences []model.Occurrence,
+) ([]byte, error) {
+	details, err := u.loadDetails(ctx, events)
+	if err != nil {
+		return nil, err
+	}
+	var series []int64
+	for i := range events {
+		if events[i].IsRecurring() && !registered {
+			series = append(series, events[i].EventID)
+		}
+	}
+	changes := map[int64][]model.Occurrence{}
+	if len(series) > 0 {
+		changed, err := u.Occurrences.ListOccurrenceChanges(ctx, series)
+		if err != nil {
+			return nil, err
+		}
+		for _, o := range changed {
+			changes[o.EventID] = append(changes[o.EventID], o)
+		}
+	}
+
+	cal := &ical.Calendar{ProdID: u.ProdID, Name: name}
+	byId := make(map[int64]*model.Event, len(events))
+	for i := range events {
+		event := &events[i]
+		byId[event.EventID] = event
+		if event.IsRecurring() && registered {
+			continue
+		}
+		cal.Events = append(cal.Events, u.seriesEvents(event, details[event.EventID], changes[event.EventID])...)
+	}
+	for i := range occurrences {
+		o := &occurrences[i]
+		if event, ok := byId[o.EventID]; ok {
+			cal.Events = append(cal.Events, u.occurrenceEvent(event, o, details[event.EventID]))
+		}
+	}
+
+	var buf bytes.Buffer
+	if err = cal.Encode(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// seriesEvents returns the event, with its rule and occurrence changes if it is recurring.
+func (u *CalendarUseCase) seriesEvents(event *model.Event, details calendarDetails, changes []model.Occurrence) []ical.Event {
+	loc := eventLocation(event)
+	master := u.icalEvent(event, details)
+	master.UID = fmt.Sprintf("event-%d@%s", event.EventID, u.Domain)
+	if !event.IsRecurring() {
+		return []ical.Event{master}
+	}
+	if rule, err := rrule.Parse(*event.RecurrenceRule); err == nil {
+		master.RRule = rule.WithDateTimeUntil().String()
+	}
+	var overrides []ical.Event
+	for i := range changes {
+		o := &changes[i]
+		if o.IsCancelled() {
+			master.ExDates = append(master.ExDates, o.RecurrenceID.In(loc))
+			continue
+		}
+		override := u.icalEvent(eventOccurrence(event, o), details)
+		override.UID = master.UID
+		override.RecurrenceID = o.RecurrenceID.In(loc)
+		overrides = append(overrides, override)
+	}
+	return append([]ical.Event{master}, overrides...)
+}
+
+// occurrenceEvent returns the occurrence as a separate event. Its UID is kept when the series is split.
+func (u *CalendarUseCase) occurrenceEvent(event *model.Event, o *model.Occurrence, details calendarDetails) ical.Event {
+	e := u.icalEvent(eventOccurrence(event, o), details)
+	e.UID = fmt.Sprintf("occurrence-%d@%s", o.OccurrenceID, u.Domain)
+	if o.IsCancelled() {
+		e.Status = ical.StatusCancelled
+	}
+	return e
+}
+
+func (u *CalendarUseCase) icalEvent(event *model.Event, details calendarDetails) ical.Event {
+	loc := eventLocation(event)
+	e := ical.Event{
+		Sequence:    event.Sequence,
+		Stamp:       event.UpdatedAt,
+		Start:       event.BeginsAt.In(loc),
+		End:         event.EndsAt.In(loc),
+		Summary:     event.Name,
+		Description: event.Description,
+		Categories:  details.tags,
+		Status:      ical.StatusConfirmed,
+	}
+	if event.IsCancelled() {
+		e.Status = ical.StatusCancelled
+	}
+	if event.OnlineURL != nil {
+		e.URL = *event.OnlineURL
+	}
+	if v := details.venue; v != nil {
+		e.Location = v.Name + ", " + v.Address
+		e.Geo = &ical.Geo{Latitude: v.Latitude, Longitude: v.Longitude}
+	}
+	return e
+}
+
+type calendarDetails struct {
+	venue *model.Venue
+	tags  []string
+}
+
+func (u *CalendarUseCase) loadDetails(ctx context.Context, events []model.Event) (map[int64]calendarDetails, error) {
+	details := make(map[int64]calendarDetails, len(events))
+	if len(events) == 0 {
+		return details, nil
+	}
+	eventIds := make([]int64, 0, len(events))
+	var venueIds []int64
+	for i := range events {
+		eventIds = append(eventIds, events[i].EventID)
+		if events[i].VenueID != nil {
+			venueIds = append(venueIds, *events[i].VenueID)
+		}
+	}
+	tags, err := u.Tags.ListTags(ctx, eventIds)
+	if err != nil {
+		return nil, err
+	}
+	venues := map[int64]*model.Venue{}
+	if len(venueIds) > 0 {
+		list, err := u.Venues.ListVenuesByIds(ctx, venueIds)
+		if err != nil {
+			return nil, err
+		}
+		for i := range list {
+			venues[list[i].VenueID] = &list[i]
+		}
+	}
+	for i := range events {
+		d := calendarDetails{tags: tags[events[i].EventID]}
+		if events[i].VenueID != nil {
+			d.venue = venues[*events[i].VenueID]
+		}
+		details[events[i].EventID] = d
+	}
+	return details, nil
+}
+
+func (u *CalendarUseCase) setFeedURL(feed *model.CalendarFeed) {
+	feed.URL = u.FeedURL + "/" + feed.Token + ".ics"
+}
+
+// eventLocation returns the timezone of the event. Events with unknown timezones are exported in UTC.
+func eventLocation(event *model.Event) *time.Location {
+	loc, err := time.LoadLocation(event.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
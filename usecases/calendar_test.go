@@ -0,0 +1,170 @@
+package usecases
+
+import (
+	"context"
+	"github.com/burenotti/rtu-it-lab-recruit/model"
+	"github.com/burenotti/rtu-it-lab-recruit/repositories"
+	"github.com/burenotti/rtu-it-lab-recruit/usecases/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+	"time"
+)
+
+// unfold returns content lines of the calendar.
+func unfold(data []byte) string {
+	return strings.ReplaceAll(string(data), "\r\n ", "")
+}
+
+func TestCalendarUseCase_ExportEvent_Cancelled(t *testing.T) {
+	ctx := context.Background()
+	events := mocks.NewCalendarEventStorage(t)
+	tags := mocks.NewEventTagStorage(t)
+	u := &CalendarUseCase{Events: events, Tags: tags, Domain: "events.example.com"}
+	beginsAt := tomorrowAt(10)
+	now := time.Now()
+	event := &model.Event{
+		EventID: 1, Name: "Lecture", BeginsAt: beginsAt, EndsAt: beginsAt.Add(time.Hour), Timezone: "UTC",
+		PublishedAt: &now, CancelledAt: &now, Sequence: 2, UpdatedAt: now,
+	}
+	events.On("GetById", mock.Anything, int64(1)).Return(event, nil)
+	tags.On("ListTags", mock.Anything, []int64{1}).Return(map[int64][]string{1: {"free"}}, nil)
+
+	data, err := u.ExportEvent(ctx, 1)
+	require.NoError(t, err)
+	out := unfold(data)
+	assert.Contains(t, out, "\r\nUID:event-1@events.example.com\r\n")
+	assert.Contains(t, out, "\r\nSEQUENCE:2\r\n")
+	assert.Contains(t, out, "\r\nSTATUS:CANCELLED\r\n")
+	assert.Contains(t, out, "\r\nCATEGORIES:free\r\n")
+}
+
+func TestCalendarUseCase_ExportEvent_Unpublished(t *testing.T) {
+	ctx := context.Background()
+	events := mocks.NewCalendarEventStorage(t)
+	u := &CalendarUseCase{Events: events}
+	events.On("GetById", mock.Anything, int64(1)).Return(&model.Event{EventID: 1}, nil)
+
+	_, err := u.ExportEvent(ctx, 1)
+	assert.ErrorIs(t, err, repositories.ErrEventNotFount)
+}
+
+func TestCalendarUseCase_ExportEvent_Recurring(t *testing.T) {
+	ctx := context.Background()
+	events := mocks.NewCalendarEventStorage(t)
+	occurrences := mocks.NewCalendarOccurrenceStorage(t)
+	tags := mocks.NewEventTagStorage(t)
+	u := &CalendarUseCase{Events: events, Occurrences: occurrences, Tags: tags, Domain: "events.example.com"}
+	moscow, err := time.LoadLocation("Europe/Moscow")
+	require.NoError(t, err)
+	beginsAt := time.Date(2030, 1, 7, 19, 0, 0, 0, moscow)
+	rule := "FREQ=WEEKLY;UNTIL=20300301"
+	now := time.Now()
+	event := &model.Event{
+		EventID: 1, Name: "Club", BeginsAt: beginsAt.UTC(), EndsAt: beginsAt.Add(time.Hour).UTC(),
+		RecurrenceRule: &rule, Timezone: "Europe/Moscow", PublishedAt: &now, UpdatedAt: now,
+	}
+	special := "Club: special"
+	week := 7 * 24 * time.Hour
+	events.On("GetById", mock.Anything, int64(1)).Return(event, nil)
+	tags.On("ListTags", mock.Anything, []int64{1}).Return(map[int64][]string{}, nil)
+	occurrences.On("ListOccurrenceChanges", mock.Anything, []int64{1}).Return([]model.Occurrence{
+		{OccurrenceID: 11, EventID: 1, RecurrenceID: beginsAt.Add(week).UTC(), CancelledAt: &now},
+		{
+			OccurrenceID: 12, EventID: 1, RecurrenceID: beginsAt.Add(2 * week).UTC(),
+			BeginsAt: beginsAt.Add(2*week + time.Hour).UTC(), EndsAt: beginsAt.Add(2*week + 2*time.Hour).UTC(),
+			Name: &special, Overridden: true,
+		},
+	}, nil)
+
+	data, err := u.ExportEvent(ctx, 1)
+	require.NoError(t, err)
+	out := unfold(data)
+	assert.Contains(t, out, "\r\nDTSTART;TZID=Europe/Moscow:20300107T190000\r\n")
+	assert.Contains(t, out, "\r\nRRULE:FREQ=WEEKLY;UNTIL=20300301T235959Z\r\n")
+	assert.Contains(t, out, "\r\nEXDATE;TZID=Europe/Moscow:20300114T190000\r\n")
+	assert.Contains(t, out, "\r\nRECURRENCE-ID;TZID=Europe/Moscow:20300121T190000\r\nDTSTART;TZID=Europe/Moscow:20300121T200000\r\n")
+	assert.Contains(t, out, "\r\nSUMMARY:Club: special\r\n")
+	assert.Equal(t, 2, strings.Count(out, "UID:event-1@events.example.com"))
+}
+
+func TestCalendarUseCase_ExportCalendarFeed_Registrations(t *testing.T) {
+	ctx := context.Background()
+	feeds := mocks.NewCalendarFeedStorage(t)
+	events := mocks.NewCalendarEventStorage(t)
+	occurrences := mocks.NewCalendarOccurrenceStorage(t)
+	tags := mocks.NewEventTagStorage(t)
+	u := &CalendarUseCase{
+		Feeds:       feeds,
+		Events:      events,
+		Occurrences: occurrences,
+		Tags:        tags,
+		Domain:      "events.example.com",
+	}
+	beginsAt := tomorrowAt(10)
+	rule := "FREQ=DAILY"
+	now := time.Now()
+	registered := []model.Event{
+		{EventID: 1, Name: "Lecture", BeginsAt: beginsAt, EndsAt: beginsAt.Add(time.Hour), Timezone: "UTC", PublishedAt: &now},
+		{
+			EventID: 2, Name: "Club", BeginsAt: beginsAt, EndsAt: beginsAt.Add(time.Hour), Timezone: "UTC",
+			RecurrenceRule: &rule, PublishedAt: &now,
+		},
+	}
+	feeds.On("GetFeedByToken", mock.Anything, "token").
+		Return(&model.CalendarFeed{FeedID: 1, UserID: 3, Kind: model.CalendarFeedRegistrations, Token: "token"}, nil)
+	events.On("SelectBy", mock.Anything, mock.Anything).Return(registered, nil)
+	occurrences.On("ListRegisteredOccurrences", mock.Anything, int64(3), mock.Anything).Return([]model.Occurrence{
+		{OccurrenceID: 21, EventID: 2, RecurrenceID: beginsAt.AddDate(0, 0, 1), BeginsAt: beginsAt.AddDate(0, 0, 1), EndsAt: beginsAt.AddDate(0, 0, 1).Add(time.Hour)},
+	}, nil)
+	tags.On("ListTags", mock.Anything, []int64{1, 2}).Return(map[int64][]string{}, nil)
+
+	data, err := u.ExportCalendarFeed(ctx, "token")
+	require.NoError(t, err)
+	out := unfold(data)
+	assert.Contains(t, out, "\r\nX-WR-CALNAME:My registrations\r\n")
+	assert.Contains(t, out, "\r\nUID:event-1@events.example.com\r\n")
+	assert.Contains(t, out, "\r\nUID:occurrence-21@events.example.com\r\n")
+	assert.NotContains(t, out, "event-2@")
+	assert.NotContains(t, out, "RRULE")
+}
+
+func TestCalendarUseCase_CreateCalendarFeed_Limit(t *testing.T) {
+	ctx := context.Background()
+	feeds := mocks.NewCalendarFeedStorage(t)
+	u := &CalendarUseCase{Transactioner: newTestTransactioner(t), Feeds: feeds}
+	feeds.On("ListFeeds", mock.Anything, int64(3)).
+		Return(make([]model.CalendarFeed, maxCalendarFeedsPerUser), nil)
+
+	_, err := u.CreateCalendarFeed(ctx, 3, &model.CalendarFeedCreate{Kind: model.CalendarFeedRegistrations})
+	assert.ErrorIs(t, err, ErrBusinessLogicViolation)
+}
+
+func TestCalendarUseCase_CreateCalendarFeed(t *testing.T) {
+	ctx := context.Background()
+	feeds := mocks.NewCalendarFeedStorage(t)
+	searches := mocks.NewCalendarSearchStorage(t)
+	u := &CalendarUseCase{
+		Transactioner: newTestTransactioner(t),
+		Feeds:         feeds,
+		Searches:      searches,
+		FeedURL:       "https://events.example.com/feeds",
+	}
+	searchId := int64(5)
+	feeds.On("ListFeeds", mock.Anything, int64(3)).Return([]model.CalendarFeed{}, nil)
+	searches.On("GetSearch", mock.Anything, int64(3), searchId).Return(&model.SavedSearch{SearchID: searchId}, nil)
+	feeds.On("CreateFeed", mock.Anything, mock.Anything).
+		Return(func(_ context.Context, feed *model.CalendarFeed) *model.CalendarFeed {
+			created := *feed
+			created.FeedID = 1
+			return &created
+		}, nil)
+
+	feed, err := u.CreateCalendarFeed(ctx, 3, &model.CalendarFeedCreate{Kind: model.CalendarFeedSearch, SearchID: &searchId})
+	require.NoError(t, err)
+	assert.Equal(t, &searchId, feed.SearchID)
+	assert.Nil(t, feed.OrganizationID)
+	assert.Equal(t, "https://events.example.com/feeds/"+feed.Token+".ics", feed.URL)
+}
@@ -0,0 +1,237 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"github.com/burenotti/rtu-it-lab-recruit/model"
+	"github.com/burenotti/rtu-it-lab-recruit/repositories"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	// maxCategoryDepth limits the taxonomy to categories, subcategories and their subcategories.
+	maxCategoryDepth      = 3
+	defaultTagSuggestions = 10
+)
+
+var slugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+type CategoryStorage interface {
+	CreateCategory(ctx context.Context, create *model.CategoryCreate) (*model.Category, error)
+	GetCategory(ctx context.Context, categoryId int64) (*model.Category, error)
+	ListCategories(ctx context.Context) ([]model.Category, error)
+	CountEvents(ctx context.Context, since time.Time) ([]model.CategoryCount, error)
+	UpdateCategory(ctx context.Context, categoryId int64, updates map[string]interface{}) (*model.Category, error)
+	DeleteCategory(ctx context.Context, categoryId int64) error
+}
+
+type TagSuggestionStorage interface {
+	SuggestTags(ctx context.Context, prefix string, since time.Time, limit int) ([]model.TagSuggestion, error)
+}
+
+// CategoryUseCase implements browsing of the event taxonomy and its management by platform administrators.
+// Changes of the taxonomy are recorded to audit log.
+type CategoryUseCase struct {
+	Transactioner StorageTransactioner
+	Categories    CategoryStorage
+	Tags          TagSuggestionStorage
+	Audit         AuditLogStorage
+}
+
+func (u *CategoryUseCase) ListCategories(ctx context.Context) ([]model.Category, error) {
+	return u.Categories.ListCategories(ctx)
+}
+
+// CountCategoryEvents returns the number of published upcoming events in each category.
+func (u *CategoryUseCase) CountCategoryEvents(ctx context.Context) ([]model.CategoryCount, error) {
+	return u.Categories.CountEvents(ctx, time.Now().UTC())
+}
+
+// SuggestTags returns the most used tags of published upcoming events starting with the prefix.
+func (u *CategoryUseCase) SuggestTags(ctx context.Context, query *model.TagQuery) ([]model.TagSuggestion, error) {
+	prefix := normalizeTag(query.Prefix)
+	if prefix == "" {
+		return []model.TagSuggestion{}, nil
+	}
+	limit := query.Limit
+	if limit == 0 {
+		limit = defaultTagSuggestions
+	}
+	return u.Tags.SuggestTags(ctx, prefix, time.Now().UTC(), limit)
+}
+
+func (u *CategoryUseCase) CreateCategory(ctx context.Context, actorId int64, create *model.CategoryCreate) (*model.Category, error) {
+	if err := checkSlug(create.Slug); err != nil {
+		return nil, err
+	}
+	var category *model.Category
+	err := u.Transactioner.Atomic(ctx, func(ctx context.Context) (err error) {
+		if create.ParentID != nil {
+			if err = u.checkParent(ctx, 0, *create.ParentID); err != nil {
+				return err
+			}
+		}
+		if category, err = u.Categories.CreateCategory(ctx, create); err != nil {
+			return err
+		}
+		return u.audit(ctx, actorId, model.AuditCategoryCreate, category.CategoryID, map[string]interface{}{
+			"slug":      category.Slug,
+			"parent_id": category.ParentID,
+		})
+	})
+	return category, err
+}
+
+// UpdateCategory updates the category. Categories can be moved under other categories,
+// as long as the taxonomy remains a tree not deeper than maxCategoryDepth.
+func (u *CategoryUseCase) UpdateCategory(
+	ctx context.Context,
+	actorId, categoryId int64,
+	update *model.CategoryUpdate,
+) (*model.Category, error) {
+	updates := repositories.UpdatesMap{}
+	if update.Slug != nil {
+		if err := checkSlug(*update.Slug); err != nil {
+			return nil, err
+		}
+		updates["slug"] = *update.Slug
+	}
+	if update.Name != nil {
+		updates["name"] = *update.Name
+	}
+	if update.ParentID != nil {
+		if *update.ParentID == 0 {
+			updates["parent_id"] = nil
+		} else {
+			updates["parent_id"] = *update.ParentID
+		}
+	}
+
+	var category *model.Category
+	err := u.Transactioner.Atomic(ctx, func(ctx context.Context) (err error) {
+		if len(updates) == 0 {
+			category, err = u.Categories.GetCategory(ctx, categoryId)
+			return err
+		}
+		if update.ParentID != nil {
+			if _, err = u.Categories.GetCategory(ctx, categoryId); err != nil {
+				return err
+			}
+			if err = u.checkParent(ctx, categoryId, *update.ParentID); err != nil {
+				return err
+			}
+		}
+		if category, err = u.Categories.UpdateCategory(ctx, categoryId, updates); err != nil {
+			return err
+		}
+		return u.audit(ctx, actorId, model.AuditCategoryUpdate, categoryId, map[string]interface{}{
+			"changes": map[string]interface{}(updates),
+		})
+	})
+	return category, err
+}
+
+// DeleteCategory deletes the category. Categories with subcategories or events can't be deleted.
+func (u *CategoryUseCase) DeleteCategory(ctx context.Context, actorId, categoryId int64) error {
+	return u.Transactioner.Atomic(ctx, func(ctx context.Context) error {
+		category, err := u.Categories.GetCategory(ctx, categoryId)
+		if err != nil {
+			return err
+		}
+		if err = u.Categories.DeleteCategory(ctx, categoryId); err != nil {
+			return err
+		}
+		return u.audit(ctx, actorId, model.AuditCategoryDelete, categoryId, map[string]interface{}{
+			"slug": category.Slug,
+		})
+	})
+}
+
+// checkParent checks the category can be placed under the parent. Zero categoryId is a new category,
+// zero parentId is the root of the taxonomy.
+func (u *CategoryUseCase) checkParent(ctx context.Context, categoryId, parentId int64) error {
+	if parentId == 0 {
+		return nil
+	}
+	if parentId == categoryId {
+		return fmt.Errorf("%w: category can't be its own parent", ErrBusinessLogicViolation)
+	}
+	categories, err := u.Categories.ListCategories(ctx)
+	if err != nil {
+		return err
+	}
+	parents := make(map[int64]*int64, len(categories))
+	for i := range categories {
+		parents[categories[i].CategoryID] = categories[i].ParentID
+	}
+	if _, ok := parents[parentId]; !ok {
+		return fmt.Errorf("%w: parent category with provided id does not exist", repositories.ErrCategoryNotFound)
+	}
+
+	depth := 0
+	for id := &parentId; id != nil; id = parents[*id] {
+		if *id == categoryId {
+			return fmt.Errorf("%w: category can't be moved under its subcategory", ErrBusinessLogicViolation)
+		}
+		depth++
+	}
+	if depth+subtreeHeight(categories, categoryId) > maxCategoryDepth {
+		return fmt.Errorf("%w: categories can't be nested deeper than %d levels", ErrBusinessLogicViolation, maxCategoryDepth)
+	}
+	return nil
+}
+
+func (u *CategoryUseCase) audit(
+	ctx context.Context,
+	actorId int64,
+	action string,
+	categoryId int64,
+	details map[string]interface{},
+) error {
+	return u.Audit.Record(ctx, &model.AuditEntryCreate{
+		ActorID:    actorId,
+		Action:     action,
+		TargetType: model.AuditTargetCategory,
+		TargetID:   categoryId,
+		Details:    details,
+	})
+}
+
+// subtreeHeight returns the number of levels of the category with its subcategories.
+// New categories, with zero id, have one level.
+func subtreeHeight(categories []model.Category, categoryId int64) int {
+	height := 0
+	for i := range categories {
+		if p := categories[i].ParentID; p != nil && *p == categoryId {
+			if h := subtreeHeight(categories, categories[i].CategoryID); h > height {
+				height = h
+			}
+		}
+	}
+	return height + 1
+}
+
+func checkSlug(slug string) error {
+	if !slugPattern.MatchString(slug) {
+		return fmt.Errorf("%w: slug must consist of lowercase latin letters and digits separated by hyphens", ErrBusinessLogicViolation)
+	}
+	return nil
+}
+
+// normalizeTag lowercases the tag and strips surrounding spaces and the leading hash sign.
+func normalizeTag(tag string) string {
+	return strings.ToLower(strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(tag), "#")))
+}
+
+// normalizeTags normalizes tags and removes empty tags and duplicates.
+func normalizeTags(tags []string) []string {
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		if tag = normalizeTag(tag); tag != "" {
+			normalized = append(normalized, tag)
+		}
+	}
+	return uniqueStrings(normalized)
+}
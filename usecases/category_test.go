@@ -0,0 +1,112 @@
+package usecases
+
+import (
+	"context"
+	"github.com/burenotti/rtu-it-lab-recruit/model"
+	"github.com/burenotti/rtu-it-lab-recruit/repositories"
+	"github.com/burenotti/rtu-it-lab-recruit/usecases/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+// testTaxonomy is music(1) → concert(2) → jazz(3) and sport(4).
+func testTaxonomy() []model.Category {
+	music, concert := int64(1), int64(2)
+	return []model.Category{
+		{CategoryID: 1, Slug: "music"},
+		{CategoryID: 2, ParentID: &music, Slug: "concert"},
+		{CategoryID: 3, ParentID: &concert, Slug: "jazz"},
+		{CategoryID: 4, Slug: "sport"},
+	}
+}
+
+func TestCategoryUseCase_CreateCategory(t *testing.T) {
+	ctx := context.Background()
+	categories := mocks.NewCategoryStorage(t)
+	audit := mocks.NewAuditLogStorage(t)
+	u := &CategoryUseCase{Transactioner: newTestTransactioner(t), Categories: categories, Audit: audit}
+	parentId := int64(1)
+	create := &model.CategoryCreate{ParentID: &parentId, Slug: "lecture", Name: "Лекции"}
+
+	categories.On("ListCategories", mock.Anything).Return(testTaxonomy(), nil).Once()
+	categories.On("CreateCategory", mock.Anything, create).
+		Return(&model.Category{CategoryID: 5, ParentID: &parentId, Slug: "lecture"}, nil).Once()
+	audit.On("Record", mock.Anything, mock.MatchedBy(func(e *model.AuditEntryCreate) bool {
+		return e.ActorID == 7 && e.Action == model.AuditCategoryCreate &&
+			e.TargetType == model.AuditTargetCategory && e.TargetID == 5
+	})).Return(nil).Once()
+
+	category, err := u.CreateCategory(ctx, 7, create)
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), category.CategoryID)
+}
+
+func TestCategoryUseCase_CreateCategory_InvalidSlug(t *testing.T) {
+	u := &CategoryUseCase{}
+	for _, slug := range []string{"Music", "live music", "-music", "music--live", "музыка"} {
+		_, err := u.CreateCategory(context.Background(), 7, &model.CategoryCreate{Slug: slug, Name: "Music"})
+		assert.ErrorIs(t, err, ErrBusinessLogicViolation, slug)
+	}
+}
+
+func TestCategoryUseCase_CreateCategory_TooDeep(t *testing.T) {
+	categories := mocks.NewCategoryStorage(t)
+	u := &CategoryUseCase{Transactioner: newTestTransactioner(t), Categories: categories}
+	parentId := int64(3)
+	categories.On("ListCategories", mock.Anything).Return(testTaxonomy(), nil).Once()
+
+	_, err := u.CreateCategory(context.Background(), 7, &model.CategoryCreate{ParentID: &parentId, Slug: "free-jazz", Name: "Free jazz"})
+	assert.ErrorIs(t, err, ErrBusinessLogicViolation)
+}
+
+func TestCategoryUseCase_UpdateCategory_RejectsCycles(t *testing.T) {
+	categories := mocks.NewCategoryStorage(t)
+	u := &CategoryUseCase{Transactioner: newTestTransactioner(t), Categories: categories}
+	categories.On("GetCategory", mock.Anything, int64(1)).Return(&testTaxonomy()[0], nil)
+	categories.On("ListCategories", mock.Anything).Return(testTaxonomy(), nil)
+
+	for _, parentId := range []int64{1, 3} {
+		parentId := parentId
+		_, err := u.UpdateCategory(context.Background(), 7, 1, &model.CategoryUpdate{ParentID: &parentId})
+		assert.ErrorIs(t, err, ErrBusinessLogicViolation)
+	}
+	categories.AssertNotCalled(t, "UpdateCategory", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCategoryUseCase_UpdateCategory_MovesSubtree(t *testing.T) {
+	ctx := context.Background()
+	categories := mocks.NewCategoryStorage(t)
+	audit := mocks.NewAuditLogStorage(t)
+	u := &CategoryUseCase{Transactioner: newTestTransactioner(t), Categories: categories, Audit: audit}
+	sport := int64(4)
+	categories.On("GetCategory", mock.Anything, int64(2)).Return(&testTaxonomy()[1], nil).Once()
+	categories.On("ListCategories", mock.Anything).Return(testTaxonomy(), nil).Once()
+	categories.On("UpdateCategory", mock.Anything, int64(2), map[string]interface{}{"parent_id": sport}).
+		Return(&model.Category{CategoryID: 2, ParentID: &sport, Slug: "concert"}, nil).Once()
+	audit.On("Record", mock.Anything, mock.Anything).Return(nil).Once()
+
+	category, err := u.UpdateCategory(ctx, 7, 2, &model.CategoryUpdate{ParentID: &sport})
+	require.NoError(t, err)
+	assert.Equal(t, &sport, category.ParentID)
+
+	categories.On("GetCategory", mock.Anything, int64(1)).Return(&testTaxonomy()[0], nil).Once()
+	categories.On("ListCategories", mock.Anything).Return(testTaxonomy(), nil).Once()
+	_, err = u.UpdateCategory(ctx, 7, 1, &model.CategoryUpdate{ParentID: &sport})
+	assert.ErrorIs(t, err, ErrBusinessLogicViolation, "moving three levels under a root should exceed the depth")
+}
+
+func TestCategoryUseCase_DeleteCategory_InUse(t *testing.T) {
+	categories := mocks.NewCategoryStorage(t)
+	u := &CategoryUseCase{Transactioner: newTestTransactioner(t), Categories: categories}
+	categories.On("GetCategory", mock.Anything, int64(1)).Return(&testTaxonomy()[0], nil).Once()
+	categories.On("DeleteCategory", mock.Anything, int64(1)).Return(repositories.ErrCategoryInUse).Once()
+
+	err := u.DeleteCategory(context.Background(), 7, 1)
+	assert.ErrorIs(t, err, repositories.ErrCategoryInUse)
+}
+
+func TestNormalizeTags(t *testing.T) {
+	assert.Equal(t, []string{"free", "18+", "kids"}, normalizeTags([]string{" Free", "#18+", "FREE", "", "# ", "Kids"}))
+}
@@ -0,0 +1,337 @@
+package usecases
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/burenotti/rtu-it-lab-recruit/model"
+	"github.com/burenotti/rtu-it-lab-recruit/repositories"
+	"github.com/burenotti/rtu-it-lab-recruit/services"
+	"github.com/sirupsen/logrus"
+	"math/big"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// phoneCodeWindow is the period PhoneCodeLimit and PhoneCodeAttempts are counted within.
+const phoneCodeWindow = 24 * time.Hour
+
+var (
+	ErrChannelNotConfigured = errors.New("channel is not configured")
+	ErrPhoneCodeInvalid     = errors.New("phone confirmation code is invalid")
+)
+
+type UserChannelStorage interface {
+	ListChannels(ctx context.Context, userId int64) ([]model.UserChannel, error)
+	LinkChannel(ctx context.Context, userId int64, channel, address string) (*model.UserChannel, error)
+	ReleaseAddress(ctx context.Context, channel, address string) error
+	UpdateChannel(ctx context.Context, userId int64, channel string, updates map[string]interface{}) (*model.UserChannel, error)
+	UnlinkChannel(ctx context.Context, userId int64, channel string) error
+	CreateTelegramLinkToken(ctx context.Context, userId int64, token string, expiresAt time.Time) error
+	UseTelegramLinkToken(ctx context.Context, token string) (int64, error)
+	CreatePhoneVerification(ctx context.Context, userId int64, phone, code string, expiresAt, windowStart time.Time) (int, error)
+	LockPhoneVerificationRequests(ctx context.Context, userId int64) error
+	CreatePhoneVerificationRequest(ctx context.Context, userId int64) error
+	CountPhoneVerificationRequests(ctx context.Context, userId int64, since time.Time) (int, error)
+	UsePhoneVerificationAttempt(ctx context.Context, userId int64, maxAttempts int) (*model.PhoneVerification, error)
+	DeletePhoneVerification(ctx context.Context, userId int64) error
+}
+
+type ChannelUserStorage interface {
+	GetById(ctx context.Context, userId int64) (*model.User, error)
+}
+
+type ChannelNotifier interface {
+	SendTo(ctx context.Context, user *model.User, channel *model.UserChannel, kind string, data interface{}) error
+}
+
+// ChannelsUseCase manages the channels users receive notifications by.
+type ChannelsUseCase struct {
+	Transactioner StorageTransactioner
+	Channels      UserChannelStorage
+	Users         ChannelUserStorage
+	Notifier      ChannelNotifier
+	Logger        *logrus.Logger
+	// Configured are channels messages could be delivered by.
+	Configured      []string
+	TelegramBotName string
+	TelegramLinkTTL time.Duration
+	PhoneCodeTTL    time.Duration
+	// PhoneCodeAttempts is the number of attempts to enter codes sent to the phone within 24 hours.
+	PhoneCodeAttempts int
+	// PhoneCodeCooldown is the minimal interval between two codes sent to phones of the same user.
+	PhoneCodeCooldown time.Duration
+	// PhoneCodeLimit is the maximal number of codes sent to phones of the same user within 24 hours.
+	PhoneCodeLimit int
+}
+
+type channelLinkedContext struct {
+	User    *model.User
+	Channel string
+}
+
+type phoneCodeContext struct {
+	User *model.User
+	Code string
+}
+
+// ListChannels returns all channels of the user. Email and in-app channels are always present.
+func (u *ChannelsUseCase) ListChannels(ctx context.Context, userId int64) ([]model.UserChannel, error) {
+	user, err := u.Users.GetById(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	channels, err := u.Channels.ListChannels(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	return services.WithDefaultChannels(user, channels), nil
+}
+
+// LinkPhone sends a confirmation code to the phone. The phone is linked by ConfirmPhone,
+// so login codes are never sent to a number the user does not own.
+// Sending is rate limited by PhoneCodeCooldown and PhoneCodeLimit.
+func (u *ChannelsUseCase) LinkPhone(ctx context.Context, userId int64, link *model.PhoneLink) (*model.PhoneVerification, error) {
+	if err := u.checkConfigured(model.ChannelSMS); err != nil {
+		return nil, err
+	}
+	code, err := newPhoneCode()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	verification := &model.PhoneVerification{
+		Phone:     link.Phone,
+		ExpiresAt: now.Add(u.PhoneCodeTTL),
+	}
+	err = u.Transactioner.Atomic(ctx, func(ctx context.Context) error {
+		user, err := u.Users.GetById(ctx, userId)
+		if err != nil {
+			return err
+		}
+		if err = u.Channels.LockPhoneVerificationRequests(ctx, userId); err != nil {
+			return err
+		}
+		recent, err := u.Channels.CountPhoneVerificationRequests(ctx, userId, now.Add(-u.PhoneCodeCooldown))
+		if err != nil {
+			return err
+		}
+		if recent > 0 {
+			return fmt.Errorf("%w: code can be requested once in %s", ErrTooManyRequests, u.PhoneCodeCooldown)
+		}
+		daily, err := u.Channels.CountPhoneVerificationRequests(ctx, userId, now.Add(-phoneCodeWindow))
+		if err != nil {
+			return err
+		}
+		if daily >= u.PhoneCodeLimit {
+			return fmt.Errorf("%w: code can be requested %d times a day", ErrTooManyRequests, u.PhoneCodeLimit)
+		}
+
+		attempts, err := u.Channels.CreatePhoneVerification(ctx, userId, link.Phone, code, verification.ExpiresAt,
+			now.Add(-phoneCodeWindow))
+		if err != nil {
+			return err
+		}
+		if attempts >= u.PhoneCodeAttempts {
+			return fmt.Errorf("%w: code can be entered %d times a day", ErrTooManyRequests, u.PhoneCodeAttempts)
+		}
+		if err = u.Channels.CreatePhoneVerificationRequest(ctx, userId); err != nil {
+			return err
+		}
+		phone := &model.UserChannel{UserID: userId, Channel: model.ChannelSMS, Address: link.Phone}
+		return u.Notifier.SendTo(ctx, user, phone, model.MessagePhoneCode, phoneCodeContext{User: user, Code: code})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return verification, nil
+}
+
+// ConfirmPhone links the phone if the code matches the one sent by LinkPhone.
+func (u *ChannelsUseCase) ConfirmPhone(ctx context.Context, userId int64, confirm *model.PhoneConfirmation) (*model.UserChannel, error) {
+	if err := u.checkConfigured(model.ChannelSMS); err != nil {
+		return nil, err
+	}
+	// The attempt is counted outside the transaction, otherwise a wrong code would roll it back.
+	verification, err := u.Channels.UsePhoneVerificationAttempt(ctx, userId, u.PhoneCodeAttempts)
+	if err != nil {
+		return nil, err
+	}
+	if subtle.ConstantTimeCompare([]byte(verification.Code), []byte(confirm.Code)) != 1 {
+		left := u.PhoneCodeAttempts - verification.Attempts
+		return nil, fmt.Errorf("%w: %d attempts left", ErrPhoneCodeInvalid, left)
+	}
+
+	var channel *model.UserChannel
+	err = u.Transactioner.Atomic(ctx, func(ctx context.Context) error {
+		user, err := u.Users.GetById(ctx, userId)
+		if err != nil {
+			return err
+		}
+		// Deleting the verification guards against the code being used twice by concurrent requests
+		if err = u.Channels.DeletePhoneVerification(ctx, userId); err != nil {
+			return err
+		}
+		channel, err = u.Channels.LinkChannel(ctx, userId, model.ChannelSMS, verification.Phone)
+		if err != nil {
+			return err
+		}
+		u.Logger.WithField("user_id", userId).Infof("Phone is linked to user %d", userId)
+		return u.Notifier.SendTo(ctx, user, channel, model.MessageChannelLinked, channelLinkedContext{
+			User:    user,
+			Channel: model.ChannelSMS,
+		})
+	})
+	return channel, err
+}
+
+// StartTelegramLink issues a deep link to the bot. When the user opens it, the bot receives the token
+// in /start command and links the chat to the user.
+func (u *ChannelsUseCase) StartTelegramLink(ctx context.Context, userId int64) (*model.TelegramLink, error) {
+	if err := u.checkConfigured(model.ChannelTelegram); err != nil {
+		return nil, err
+	}
+	token, err := newLinkToken()
+	if err != nil {
+		return nil, err
+	}
+	expiresAt := time.Now().UTC().Add(u.TelegramLinkTTL)
+	if err = u.Channels.CreateTelegramLinkToken(ctx, userId, token, expiresAt); err != nil {
+		return nil, err
+	}
+	return &model.TelegramLink{
+		URL:       fmt.Sprintf("https://t.me/%s?start=%s", url.PathEscape(u.TelegramBotName), token),
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// HandleTelegramUpdate links the chat to the user if the update is /start command with a valid link token.
+// Other updates are ignored. A chat could be linked to a single user, so it is unlinked from the previous one.
+func (u *ChannelsUseCase) HandleTelegramUpdate(ctx context.Context, update *model.TelegramUpdate) error {
+	if update.Message == nil {
+		return nil
+	}
+	text := strings.TrimSpace(update.Message.Text)
+	if !strings.HasPrefix(text, "/start ") {
+		return nil
+	}
+	token := strings.TrimSpace(strings.TrimPrefix(text, "/start "))
+	chatId := strconv.FormatInt(update.Message.Chat.ID, 10)
+
+	return u.Transactioner.Atomic(ctx, func(ctx context.Context) error {
+		userId, err := u.Channels.UseTelegramLinkToken(ctx, token)
+		if errors.Is(err, repositories.ErrTelegramLinkTokenNotFound) {
+			u.Logger.WithField("update_id", update.UpdateID).Info("Telegram link token is invalid or expired")
+			return nil
+		} else if err != nil {
+			return err
+		}
+		user, err := u.Users.GetById(ctx, userId)
+		if err != nil {
+			return err
+		}
+		if err = u.Channels.ReleaseAddress(ctx, model.ChannelTelegram, chatId); err != nil {
+			return err
+		}
+		channel, err := u.Channels.LinkChannel(ctx, userId, model.ChannelTelegram, chatId)
+		if err != nil {
+			return err
+		}
+		u.Logger.WithField("user_id", userId).Infof("Telegram chat is linked to user %d", userId)
+		return u.Notifier.SendTo(ctx, user, channel, model.MessageChannelLinked, channelLinkedContext{
+			User:    user,
+			Channel: model.ChannelTelegram,
+		})
+	})
+}
+
+// UpdateChannel updates preferences of the channel. Email and in-app preferences are stored on the first update.
+func (u *ChannelsUseCase) UpdateChannel(
+	ctx context.Context,
+	userId int64,
+	channel string,
+	update *model.UserChannelUpdate,
+) (*model.UserChannel, error) {
+	updates := repositories.UpdatesMap{}
+	if update.Enabled != nil {
+		updates["enabled"] = *update.Enabled
+	}
+	if update.Priority != nil {
+		updates["priority"] = *update.Priority
+	}
+
+	var result *model.UserChannel
+	err := u.Transactioner.Atomic(ctx, func(ctx context.Context) (err error) {
+		user, err := u.Users.GetById(ctx, userId)
+		if err != nil {
+			return err
+		}
+		switch channel {
+		case model.ChannelEmail:
+			result, err = u.Channels.LinkChannel(ctx, userId, model.ChannelEmail, user.Email)
+		case model.ChannelInApp:
+			result, err = u.Channels.LinkChannel(ctx, userId, model.ChannelInApp, "")
+		}
+		if err != nil {
+			return err
+		}
+		if len(updates) > 0 {
+			result, err = u.Channels.UpdateChannel(ctx, userId, channel, updates)
+		} else if result == nil {
+			result, err = u.findChannel(ctx, userId, channel)
+		}
+		return err
+	})
+	return result, err
+}
+
+func (u *ChannelsUseCase) findChannel(ctx context.Context, userId int64, channel string) (*model.UserChannel, error) {
+	channels, err := u.Channels.ListChannels(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	for i := range channels {
+		if channels[i].Channel == channel {
+			return &channels[i], nil
+		}
+	}
+	return nil, fmt.Errorf("%w: channel %s is not linked", repositories.ErrChannelNotLinked, channel)
+}
+
+func (u *ChannelsUseCase) UnlinkChannel(ctx context.Context, userId int64, channel string) error {
+	if channel == model.ChannelEmail || channel == model.ChannelInApp {
+		return fmt.Errorf("%w: %s channel can't be unlinked, disable it instead", ErrBusinessLogicViolation, channel)
+	}
+	return u.Channels.UnlinkChannel(ctx, userId, channel)
+}
+
+func (u *ChannelsUseCase) checkConfigured(channel string) error {
+	for _, c := range u.Configured {
+		if c == channel {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s notifications are not available", ErrChannelNotConfigured, channel)
+}
+
+// newPhoneCode returns a random 6-digit code.
+func newPhoneCode() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(1000000))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%06d", n.Int64()), nil
+}
+
+func newLinkToken() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
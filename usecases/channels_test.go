@@ -0,0 +1,278 @@
+package usecases
+
+import (
+	"context"
+	"github.com/burenotti/rtu-it-lab-recruit/model"
+	"github.com/burenotti/rtu-it-lab-recruit/repositories"
+	"github.com/burenotti/rtu-it-lab-recruit/usecases/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTelegramUpdate(text string, chatId int64) *model.TelegramUpdate {
+	return &model.TelegramUpdate{
+		UpdateID: 1,
+		Message:  &model.TelegramMessage{Text: text, Chat: model.TelegramChat{ID: chatId}},
+	}
+}
+
+func TestChannelsUseCase_StartTelegramLink(t *testing.T) {
+	ctx := context.Background()
+	channels := mocks.NewUserChannelStorage(t)
+	u := &ChannelsUseCase{
+		Channels:        channels,
+		Configured:      []string{model.ChannelEmail, model.ChannelTelegram},
+		TelegramBotName: "events_bot",
+	}
+
+	var token string
+	channels.On("CreateTelegramLinkToken", mock.Anything, int64(1), mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { token = args.String(2) }).
+		Return(nil)
+
+	link, err := u.StartTelegramLink(ctx, 1)
+	require.NoError(t, err)
+	assert.NotEmpty(t, token)
+	assert.Equal(t, "https://t.me/events_bot?start="+token, link.URL)
+}
+
+func TestChannelsUseCase_LinkPhone_NotConfigured(t *testing.T) {
+	u := &ChannelsUseCase{}
+
+	_, err := u.LinkPhone(context.Background(), 1, &model.PhoneLink{Phone: "+79991234567"})
+	assert.ErrorIs(t, err, ErrChannelNotConfigured)
+}
+
+func TestChannelsUseCase_LinkPhone(t *testing.T) {
+	ctx := context.Background()
+	channels := mocks.NewUserChannelStorage(t)
+	users := mocks.NewChannelUserStorage(t)
+	notifier := mocks.NewChannelNotifier(t)
+	u := &ChannelsUseCase{
+		Transactioner:     newTestTransactioner(t),
+		Channels:          channels,
+		Users:             users,
+		Notifier:          notifier,
+		Configured:        []string{model.ChannelSMS},
+		PhoneCodeAttempts: 5,
+		PhoneCodeLimit:    5,
+	}
+	user := &model.User{UserID: 1, Email: "johndoe@example.com"}
+	phone := "+79991234567"
+
+	var code string
+	users.On("GetById", mock.Anything, user.UserID).Return(user, nil)
+	channels.On("LockPhoneVerificationRequests", mock.Anything, user.UserID).Return(nil)
+	channels.On("CountPhoneVerificationRequests", mock.Anything, user.UserID, mock.Anything).Return(0, nil).Twice()
+	channels.On("CreatePhoneVerification", mock.Anything, user.UserID, phone, mock.Anything, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { code = args.String(3) }).
+		Return(2, nil)
+	channels.On("CreatePhoneVerificationRequest", mock.Anything, user.UserID).Return(nil)
+	notifier.On("SendTo", mock.Anything, user, mock.MatchedBy(func(c *model.UserChannel) bool {
+		return c.Channel == model.ChannelSMS && c.Address == phone
+	}), model.MessagePhoneCode, mock.Anything).Return(nil)
+
+	verification, err := u.LinkPhone(ctx, user.UserID, &model.PhoneLink{Phone: phone})
+	require.NoError(t, err)
+	assert.Equal(t, phone, verification.Phone)
+	assert.Len(t, code, 6)
+	channels.AssertNotCalled(t, "LinkChannel", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestChannelsUseCase_LinkPhone_Cooldown(t *testing.T) {
+	ctx := context.Background()
+	channels := mocks.NewUserChannelStorage(t)
+	users := mocks.NewChannelUserStorage(t)
+	u := &ChannelsUseCase{
+		Transactioner:     newTestTransactioner(t),
+		Channels:          channels,
+		Users:             users,
+		Configured:        []string{model.ChannelSMS},
+		PhoneCodeCooldown: time.Minute,
+	}
+
+	users.On("GetById", mock.Anything, int64(1)).Return(&model.User{UserID: 1}, nil)
+	channels.On("LockPhoneVerificationRequests", mock.Anything, int64(1)).Return(nil)
+	channels.On("CountPhoneVerificationRequests", mock.Anything, int64(1), mock.MatchedBy(func(since time.Time) bool {
+		return time.Since(since) < time.Hour
+	})).Return(1, nil).Once()
+
+	_, err := u.LinkPhone(ctx, 1, &model.PhoneLink{Phone: "+79991234567"})
+	assert.ErrorIs(t, err, ErrTooManyRequests, "code should not be resent within cooldown")
+}
+
+func TestChannelsUseCase_LinkPhone_DailyLimit(t *testing.T) {
+	ctx := context.Background()
+	channels := mocks.NewUserChannelStorage(t)
+	users := mocks.NewChannelUserStorage(t)
+	u := &ChannelsUseCase{
+		Transactioner:  newTestTransactioner(t),
+		Channels:       channels,
+		Users:          users,
+		Configured:     []string{model.ChannelSMS},
+		PhoneCodeLimit: 5,
+	}
+
+	users.On("GetById", mock.Anything, int64(1)).Return(&model.User{UserID: 1}, nil)
+	channels.On("LockPhoneVerificationRequests", mock.Anything, int64(1)).Return(nil)
+	channels.On("CountPhoneVerificationRequests", mock.Anything, int64(1), mock.Anything).Return(0, nil).Once()
+	channels.On("CountPhoneVerificationRequests", mock.Anything, int64(1), mock.Anything).Return(u.PhoneCodeLimit, nil).Once()
+
+	_, err := u.LinkPhone(ctx, 1, &model.PhoneLink{Phone: "+79991234567"})
+	assert.ErrorIs(t, err, ErrTooManyRequests, "code should not be sent after daily limit exceeded")
+}
+
+func TestChannelsUseCase_LinkPhone_AttemptsExhausted(t *testing.T) {
+	ctx := context.Background()
+	channels := mocks.NewUserChannelStorage(t)
+	users := mocks.NewChannelUserStorage(t)
+	u := &ChannelsUseCase{
+		Transactioner:     newTestTransactioner(t),
+		Channels:          channels,
+		Users:             users,
+		Configured:        []string{model.ChannelSMS},
+		PhoneCodeAttempts: 5,
+		PhoneCodeLimit:    5,
+	}
+
+	users.On("GetById", mock.Anything, int64(1)).Return(&model.User{UserID: 1}, nil)
+	channels.On("LockPhoneVerificationRequests", mock.Anything, int64(1)).Return(nil)
+	channels.On("CountPhoneVerificationRequests", mock.Anything, int64(1), mock.Anything).Return(0, nil).Twice()
+	channels.On("CreatePhoneVerification", mock.Anything, int64(1), "+79991234567", mock.Anything, mock.Anything,
+		mock.MatchedBy(func(windowStart time.Time) bool {
+			return time.Since(windowStart) > 23*time.Hour
+		})).Return(u.PhoneCodeAttempts, nil)
+
+	_, err := u.LinkPhone(ctx, 1, &model.PhoneLink{Phone: "+79991234567"})
+	assert.ErrorIs(t, err, ErrTooManyRequests, "resending code should not give more attempts to guess it")
+}
+
+func TestChannelsUseCase_ConfirmPhone(t *testing.T) {
+	ctx := context.Background()
+	channels := mocks.NewUserChannelStorage(t)
+	users := mocks.NewChannelUserStorage(t)
+	notifier := mocks.NewChannelNotifier(t)
+	u := &ChannelsUseCase{
+		Transactioner:     newTestTransactioner(t),
+		Channels:          channels,
+		Users:             users,
+		Notifier:          notifier,
+		Logger:            newTestLogger(),
+		Configured:        []string{model.ChannelSMS},
+		PhoneCodeAttempts: 5,
+	}
+	user := &model.User{UserID: 1, Email: "johndoe@example.com"}
+	verification := &model.PhoneVerification{Phone: "+79991234567", Code: "123456", Attempts: 1}
+	channel := &model.UserChannel{UserID: 1, Channel: model.ChannelSMS, Address: verification.Phone, Enabled: true}
+
+	channels.On("UsePhoneVerificationAttempt", mock.Anything, user.UserID, u.PhoneCodeAttempts).Return(verification, nil)
+	users.On("GetById", mock.Anything, user.UserID).Return(user, nil)
+	channels.On("DeletePhoneVerification", mock.Anything, user.UserID).Return(nil)
+	channels.On("LinkChannel", mock.Anything, user.UserID, model.ChannelSMS, verification.Phone).Return(channel, nil)
+	notifier.On("SendTo", mock.Anything, user, channel, model.MessageChannelLinked, mock.Anything).Return(nil)
+
+	linked, err := u.ConfirmPhone(ctx, user.UserID, &model.PhoneConfirmation{Code: "123456"})
+	require.NoError(t, err)
+	assert.Equal(t, channel, linked)
+}
+
+func TestChannelsUseCase_ConfirmPhone_InvalidCode(t *testing.T) {
+	ctx := context.Background()
+	channels := mocks.NewUserChannelStorage(t)
+	u := &ChannelsUseCase{Channels: channels, PhoneCodeAttempts: 5, Configured: []string{model.ChannelSMS}}
+	verification := &model.PhoneVerification{Phone: "+79991234567", Code: "123456", Attempts: 1}
+
+	channels.On("UsePhoneVerificationAttempt", mock.Anything, int64(1), u.PhoneCodeAttempts).Return(verification, nil)
+
+	_, err := u.ConfirmPhone(ctx, 1, &model.PhoneConfirmation{Code: "654321"})
+	assert.ErrorIs(t, err, ErrPhoneCodeInvalid, "phone should not be linked without the code sent to it")
+}
+
+func TestChannelsUseCase_HandleTelegramUpdate(t *testing.T) {
+	ctx := context.Background()
+	channels := mocks.NewUserChannelStorage(t)
+	users := mocks.NewChannelUserStorage(t)
+	notifier := mocks.NewChannelNotifier(t)
+	u := &ChannelsUseCase{
+		Transactioner: newTestTransactioner(t),
+		Channels:      channels,
+		Users:         users,
+		Notifier:      notifier,
+		Logger:        newTestLogger(),
+	}
+	user := &model.User{UserID: 1, Email: "johndoe@example.com"}
+	channel := &model.UserChannel{UserID: 1, Channel: model.ChannelTelegram, Address: "42", Enabled: true}
+
+	channels.On("UseTelegramLinkToken", mock.Anything, "token").Return(user.UserID, nil)
+	users.On("GetById", mock.Anything, user.UserID).Return(user, nil)
+	channels.On("ReleaseAddress", mock.Anything, model.ChannelTelegram, "42").Return(nil)
+	channels.On("LinkChannel", mock.Anything, user.UserID, model.ChannelTelegram, "42").Return(channel, nil)
+	notifier.On("SendTo", mock.Anything, user, channel, model.MessageChannelLinked, mock.Anything).Return(nil)
+
+	assert.NoError(t, u.HandleTelegramUpdate(ctx, newTelegramUpdate("/start token", 42)))
+}
+
+func TestChannelsUseCase_HandleTelegramUpdate_Ignored(t *testing.T) {
+	ctx := context.Background()
+	channels := mocks.NewUserChannelStorage(t)
+	u := &ChannelsUseCase{Transactioner: newTestTransactioner(t), Channels: channels, Logger: newTestLogger()}
+
+	assert.NoError(t, u.HandleTelegramUpdate(ctx, &model.TelegramUpdate{UpdateID: 1}))
+	assert.NoError(t, u.HandleTelegramUpdate(ctx, newTelegramUpdate("hello", 42)))
+
+	channels.On("UseTelegramLinkToken", mock.Anything, "expired").
+		Return(int64(0), repositories.ErrTelegramLinkTokenNotFound)
+	assert.NoError(t, u.HandleTelegramUpdate(ctx, newTelegramUpdate("/start expired", 42)),
+		"invalid tokens should not make telegram retry the update")
+}
+
+func TestChannelsUseCase_UpdateChannel_Email(t *testing.T) {
+	ctx := context.Background()
+	channels := mocks.NewUserChannelStorage(t)
+	users := mocks.NewChannelUserStorage(t)
+	u := &ChannelsUseCase{Transactioner: newTestTransactioner(t), Channels: channels, Users: users}
+	user := &model.User{UserID: 1, Email: "johndoe@example.com"}
+	disabled := false
+	expected := &model.UserChannel{UserID: 1, Channel: model.ChannelEmail, Address: user.Email}
+
+	users.On("GetById", mock.Anything, user.UserID).Return(user, nil)
+	channels.On("LinkChannel", mock.Anything, user.UserID, model.ChannelEmail, user.Email).
+		Return(&model.UserChannel{UserID: 1, Channel: model.ChannelEmail, Address: user.Email, Enabled: true}, nil)
+	channels.On("UpdateChannel", mock.Anything, user.UserID, model.ChannelEmail, map[string]interface{}{"enabled": false}).
+		Return(expected, nil)
+
+	channel, err := u.UpdateChannel(ctx, user.UserID, model.ChannelEmail, &model.UserChannelUpdate{Enabled: &disabled})
+	require.NoError(t, err)
+	assert.Equal(t, expected, channel)
+}
+
+func TestChannelsUseCase_UpdateChannel_InApp(t *testing.T) {
+	ctx := context.Background()
+	channels := mocks.NewUserChannelStorage(t)
+	users := mocks.NewChannelUserStorage(t)
+	u := &ChannelsUseCase{Transactioner: newTestTransactioner(t), Channels: channels, Users: users}
+	user := &model.User{UserID: 1, Email: "johndoe@example.com"}
+	expected := &model.UserChannel{UserID: 1, Channel: model.ChannelInApp, Enabled: true}
+
+	users.On("GetById", mock.Anything, user.UserID).Return(user, nil)
+	channels.On("LinkChannel", mock.Anything, user.UserID, model.ChannelInApp, "").Return(expected, nil)
+
+	channel, err := u.UpdateChannel(ctx, user.UserID, model.ChannelInApp, &model.UserChannelUpdate{})
+	require.NoError(t, err)
+	assert.Equal(t, expected, channel, "in-app preferences should be stored without linking")
+}
+
+func TestChannelsUseCase_UnlinkChannel_Email(t *testing.T) {
+	u := &ChannelsUseCase{}
+
+	err := u.UnlinkChannel(context.Background(), 1, model.ChannelEmail)
+	assert.ErrorIs(t, err, ErrBusinessLogicViolation)
+	assert.True(t, strings.Contains(err.Error(), "email"))
+
+	err = u.UnlinkChannel(context.Background(), 1, model.ChannelInApp)
+	assert.ErrorIs(t, err, ErrBusinessLogicViolation)
+}
@@ -0,0 +1,238 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/burenotti/rtu-it-lab-recruit/model"
+	"github.com/burenotti/rtu-it-lab-recruit/pkg/qr"
+	"github.com/burenotti/rtu-it-lab-recruit/repositories"
+	"time"
+)
+
+// ticketQRScale is the size of QR code modules in pixels.
+const ticketQRScale = 8
+
+// checkInOpensBefore is how long before the event begins tickets are checked in.
+// Earlier scans, including backdated offline ones, are rejected.
+const checkInOpensBefore = 24 * time.Hour
+
+type CheckInStorage interface {
+	CheckIn(ctx context.Context, checkIn *model.CheckIn) (*model.CheckIn, bool, error)
+	GetAttendee(ctx context.Context, registrationId int64) (*model.Attendee, error)
+	ListAttendees(ctx context.Context, eventId int64, occurrenceId *int64) ([]model.Attendee, error)
+}
+
+type CheckInOccurrenceStorage interface {
+	GetOccurrence(ctx context.Context, eventId, occurrenceId int64) (*model.Occurrence, error)
+}
+
+// TicketSigner signs codes of tickets. Scanners verify codes offline with the public key.
+type TicketSigner interface {
+	Code(claims model.TicketClaims) (string, error)
+	Verify(code string) (*model.TicketClaims, error)
+	PublicKey() string
+}
+
+// CheckInUseCase implements tickets of registrations and their check-in at the entrance. Tickets are checked in
+// by members with rights to check in or edit events. Scanners may download the attendee list, check in offline
+// and sync check-ins later.
+// Recurring events are checked in for one occurrence at a time.
+type CheckInUseCase struct {
+	CheckIns    CheckInStorage
+	Events      EventGetter
+	Occurrences CheckInOccurrenceStorage
+	Members     EventMemberStorage
+	Signer      TicketSigner
+}
+
+// GetTicket returns the ticket of the user's registration.
+func (u *CheckInUseCase) GetTicket(ctx context.Context, userId, registrationId int64) (*model.Ticket, error) {
+	attendee, err := u.CheckIns.GetAttendee(ctx, registrationId)
+	if err != nil {
+		return nil, err
+	}
+	if attendee.UserID != userId {
+		return nil, fmt.Errorf("%w: registration with provided id does not exist", repositories.ErrRegistrationNotFound)
+	}
+	code, err := u.Signer.Code(model.TicketClaims{RegistrationID: attendee.RegistrationID, EventID: attendee.EventID})
+	if err != nil {
+		return nil, err
+	}
+	return &model.Ticket{
+		RegistrationID: attendee.RegistrationID,
+		EventID:        attendee.EventID,
+		OccurrenceID:   attendee.OccurrenceID,
+		Code:           code,
+		CheckedInAt:    attendee.CheckedInAt,
+	}, nil
+}
+
+// GetTicketQRCode returns the code of the user's ticket rendered as a QR code in PNG.
+func (u *CheckInUseCase) GetTicketQRCode(ctx context.Context, userId, registrationId int64) ([]byte, error) {
+	ticket, err := u.GetTicket(ctx, userId, registrationId)
+	if err != nil {
+		return nil, err
+	}
+	code, err := qr.Encode([]byte(ticket.Code), qr.M)
+	if err != nil {
+		return nil, err
+	}
+	return code.PNG(ticketQRScale)
+}
+
+// CheckIn checks in the ticket scanned online. Invalid tickets are rejected, tickets scanned again are
+// reported as duplicates with the time of the first scan.
+func (u *CheckInUseCase) CheckIn(ctx context.Context, userId, eventId int64, create *model.CheckInCreate) (*model.CheckInResult, error) {
+	event, err := u.getCheckInEvent(ctx, userId, eventId)
+	if err != nil {
+		return nil, err
+	}
+	opensAt, err := u.checkInOpensAt(ctx, event, create.OccurrenceID)
+	if err != nil {
+		return nil, err
+	}
+	return u.checkIn(ctx, event, create.OccurrenceID, opensAt, create.Code, &model.CheckIn{
+		CheckedInBy: &userId,
+		CheckedInAt: time.Now().UTC(),
+	})
+}
+
+// ListAttendees returns attendees of the event with the public key scanners verify tickets with.
+func (u *CheckInUseCase) ListAttendees(ctx context.Context, userId, eventId int64, query *model.AttendeeListQuery) (*model.AttendeeList, error) {
+	if _, err := u.getCheckInEvent(ctx, userId, eventId); err != nil {
+		return nil, err
+	}
+	generatedAt := time.Now().UTC()
+	attendees, err := u.CheckIns.ListAttendees(ctx, eventId, query.OccurrenceID)
+	if err != nil {
+		return nil, err
+	}
+	return &model.AttendeeList{
+		EventID:     eventId,
+		PublicKey:   u.Signer.PublicKey(),
+		GeneratedAt: generatedAt,
+		Attendees:   attendees,
+	}, nil
+}
+
+// SyncCheckIns saves check-ins made by the scanner offline and returns results in the same order.
+// If the ticket was checked in on several scanners, the earliest scan wins and the others are duplicates.
+// Times of scans from the future are replaced with the current time, scans before check-in opens are rejected.
+func (u *CheckInUseCase) SyncCheckIns(ctx context.Context, userId, eventId int64, sync *model.CheckInSync) ([]model.CheckInResult, error) {
+	event, err := u.getCheckInEvent(ctx, userId, eventId)
+	if err != nil {
+		return nil, err
+	}
+	opensAt, err := u.checkInOpensAt(ctx, event, sync.OccurrenceID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	results := make([]model.CheckInResult, 0, len(sync.CheckIns))
+	for _, offline := range sync.CheckIns {
+		checkedInAt := offline.CheckedInAt.UTC()
+		if checkedInAt.After(now) {
+			checkedInAt = now
+		}
+		result, err := u.checkIn(ctx, event, sync.OccurrenceID, opensAt, offline.Code, &model.CheckIn{
+			CheckedInBy: &userId,
+			DeviceID:    &sync.DeviceID,
+			CheckedInAt: checkedInAt,
+		})
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, *result)
+	}
+	return results, nil
+}
+
+func (u *CheckInUseCase) checkIn(
+	ctx context.Context, event *model.Event, occurrenceId *int64, opensAt time.Time, code string, checkIn *model.CheckIn,
+) (*model.CheckInResult, error) {
+	claims, err := u.Signer.Verify(code)
+	if err != nil {
+		return rejectedCheckIn("ticket code is invalid"), nil
+	}
+	if claims.EventID != event.EventID {
+		return rejectedCheckIn("ticket is for another event"), nil
+	}
+	if checkIn.CheckedInAt.Before(opensAt) {
+		return rejectedCheckIn("check-in has not opened yet"), nil
+	}
+	attendee, err := u.CheckIns.GetAttendee(ctx, claims.RegistrationID)
+	if errors.Is(err, repositories.ErrRegistrationNotFound) {
+		return rejectedCheckIn("registration is cancelled"), nil
+	} else if err != nil {
+		return nil, err
+	}
+	if occurrenceId != nil && (attendee.OccurrenceID == nil || *attendee.OccurrenceID != *occurrenceId) {
+		return rejectedCheckIn("ticket is for another occurrence"), nil
+	}
+
+	checkIn.RegistrationID = attendee.RegistrationID
+	checkIn.EventID = attendee.EventID
+	saved, created, err := u.CheckIns.CheckIn(ctx, checkIn)
+	if errors.Is(err, repositories.ErrRegistrationNotFound) {
+		return rejectedCheckIn("registration is cancelled"), nil
+	} else if err != nil {
+		return nil, err
+	}
+	attendee.CheckedInAt = &saved.CheckedInAt
+	result := &model.CheckInResult{Status: model.CheckInAccepted, Attendee: attendee, CheckIn: saved}
+	if !created {
+		result.Status = model.CheckInDuplicate
+	}
+	return result, nil
+}
+
+// checkInOpensAt returns the time check-in of the event opens. Recurring events require the occurrence,
+// otherwise a ticket for one occurrence would admit to all of them.
+func (u *CheckInUseCase) checkInOpensAt(ctx context.Context, event *model.Event, occurrenceId *int64) (time.Time, error) {
+	if !event.IsRecurring() {
+		return event.BeginsAt.Add(-checkInOpensBefore), nil
+	}
+	if occurrenceId == nil {
+		return time.Time{}, fmt.Errorf("%w: occurrence is required to check in for recurring event", ErrBusinessLogicViolation)
+	}
+	occurrence, err := u.Occurrences.GetOccurrence(ctx, event.EventID, *occurrenceId)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if occurrence.IsCancelled() {
+		return time.Time{}, fmt.Errorf("%w: occurrence is cancelled", ErrBusinessLogicViolation)
+	}
+	return occurrence.BeginsAt.Add(-checkInOpensBefore), nil
+}
+
+func (u *CheckInUseCase) getCheckInEvent(ctx context.Context, userId, eventId int64) (*model.Event, error) {
+	event, err := u.Events.GetById(ctx, eventId)
+	if err != nil {
+		return nil, err
+	}
+	if err = checkCanCheckIn(ctx, u.Members, event.OrganizationID, userId); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+func rejectedCheckIn(reason string) *model.CheckInResult {
+	return &model.CheckInResult{Status: model.CheckInRejected, Reason: reason}
+}
+
+// checkCanCheckIn checks the user is a member of the organization with rights to check in or edit events.
+func checkCanCheckIn(ctx context.Context, members EventMemberStorage, orgId, userId int64) error {
+	errLogic := fmt.Errorf("%w: only members with rights to check in can check in attendees", ErrBusinessLogicViolation)
+
+	member, err := members.GetMember(ctx, orgId, userId)
+	if errors.Is(err, repositories.ErrMemberNotFound) {
+		return errLogic
+	} else if err != nil {
+		return err
+	}
+	if !member.IsOwner && !member.Can.EditEvents && !member.Can.CheckIn {
+		return errLogic
+	}
+	return nil
+}
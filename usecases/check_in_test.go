@@ -0,0 +1,235 @@
+package usecases
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"github.com/burenotti/rtu-it-lab-recruit/model"
+	"github.com/burenotti/rtu-it-lab-recruit/repositories"
+	"github.com/burenotti/rtu-it-lab-recruit/services"
+	"github.com/burenotti/rtu-it-lab-recruit/usecases/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+// checkInEvent returns the published event beginning soon, so its check-in is open.
+func checkInEvent() *model.Event {
+	event := publishedEvent()
+	event.BeginsAt = time.Now().Add(time.Hour).UTC()
+	event.EndsAt = event.BeginsAt.Add(2 * time.Hour)
+	return event
+}
+
+func newTestTicketCodes(t *testing.T) *services.TicketCodes {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	return &services.TicketCodes{PrivateKey: key}
+}
+
+func ticketCode(t *testing.T, u *CheckInUseCase, registrationId, eventId int64) string {
+	code, err := u.Signer.Code(model.TicketClaims{RegistrationID: registrationId, EventID: eventId})
+	require.NoError(t, err)
+	return code
+}
+
+func TestCheckInUseCase_CheckIn_Duplicate(t *testing.T) {
+	ctx := context.Background()
+	checkIns := mocks.NewCheckInStorage(t)
+	events := mocks.NewEventGetter(t)
+	members := mocks.NewEventMemberStorage(t)
+	u := &CheckInUseCase{CheckIns: checkIns, Events: events, Members: members, Signer: newTestTicketCodes(t)}
+	event := checkInEvent()
+	firstScan := time.Now().Add(-time.Minute).UTC()
+
+	events.On("GetById", mock.Anything, event.EventID).Return(event, nil)
+	members.On("GetMember", mock.Anything, event.OrganizationID, int64(3)).
+		Return(&model.OrganizationMember{UserID: 3, Can: model.MemberRights{CheckIn: true}}, nil)
+	checkIns.On("GetAttendee", mock.Anything, int64(5)).
+		Return(&model.Attendee{RegistrationID: 5, EventID: event.EventID, UserID: 4, FirstName: "John"}, nil)
+	checkIns.On("CheckIn", mock.Anything, mock.MatchedBy(func(c *model.CheckIn) bool {
+		return c.RegistrationID == 5 && c.EventID == event.EventID && *c.CheckedInBy == 3
+	})).Return(&model.CheckIn{RegistrationID: 5, EventID: event.EventID, CheckedInAt: firstScan}, false, nil)
+
+	result, err := u.CheckIn(ctx, 3, event.EventID, &model.CheckInCreate{Code: ticketCode(t, u, 5, event.EventID)})
+	require.NoError(t, err)
+	assert.Equal(t, model.CheckInDuplicate, result.Status)
+	assert.Equal(t, "John", result.Attendee.FirstName)
+	assert.Equal(t, firstScan, *result.Attendee.CheckedInAt)
+}
+
+func TestCheckInUseCase_CheckIn_Rejected(t *testing.T) {
+	ctx := context.Background()
+	checkIns := mocks.NewCheckInStorage(t)
+	events := mocks.NewEventGetter(t)
+	members := mocks.NewEventMemberStorage(t)
+	u := &CheckInUseCase{CheckIns: checkIns, Events: events, Members: members, Signer: newTestTicketCodes(t)}
+	event := checkInEvent()
+	occurrenceId, otherOccurrenceId := int64(8), int64(9)
+
+	events.On("GetById", mock.Anything, event.EventID).Return(event, nil)
+	members.On("GetMember", mock.Anything, event.OrganizationID, int64(3)).
+		Return(&model.OrganizationMember{UserID: 3, Can: model.MemberRights{EditEvents: true}}, nil)
+	checkIns.On("GetAttendee", mock.Anything, int64(5)).
+		Return(&model.Attendee{RegistrationID: 5, EventID: event.EventID, OccurrenceID: &occurrenceId}, nil)
+	checkIns.On("GetAttendee", mock.Anything, int64(6)).
+		Return(nil, repositories.ErrRegistrationNotFound)
+
+	for name, create := range map[string]*model.CheckInCreate{
+		"forged code":      {Code: "NTox.c2lnbmF0dXJl"},
+		"other event":      {Code: ticketCode(t, u, 5, event.EventID+1)},
+		"cancelled":        {Code: ticketCode(t, u, 6, event.EventID)},
+		"other occurrence": {Code: ticketCode(t, u, 5, event.EventID), OccurrenceID: &otherOccurrenceId},
+	} {
+		result, err := u.CheckIn(ctx, 3, event.EventID, create)
+		require.NoError(t, err, name)
+		assert.Equal(t, model.CheckInRejected, result.Status, name)
+		assert.NotEmpty(t, result.Reason, name)
+	}
+	checkIns.AssertNotCalled(t, "CheckIn", mock.Anything, mock.Anything)
+}
+
+func TestCheckInUseCase_CheckIn_RequiresRights(t *testing.T) {
+	ctx := context.Background()
+	events := mocks.NewEventGetter(t)
+	members := mocks.NewEventMemberStorage(t)
+	u := &CheckInUseCase{Events: events, Members: members, Signer: newTestTicketCodes(t)}
+	event := checkInEvent()
+
+	events.On("GetById", mock.Anything, event.EventID).Return(event, nil)
+	members.On("GetMember", mock.Anything, event.OrganizationID, int64(3)).
+		Return(&model.OrganizationMember{UserID: 3, Can: model.MemberRights{ManageMembers: true}}, nil)
+
+	_, err := u.CheckIn(ctx, 3, event.EventID, &model.CheckInCreate{Code: ticketCode(t, u, 5, event.EventID)})
+	assert.ErrorIs(t, err, ErrBusinessLogicViolation)
+}
+
+func TestCheckInUseCase_SyncCheckIns(t *testing.T) {
+	ctx := context.Background()
+	checkIns := mocks.NewCheckInStorage(t)
+	events := mocks.NewEventGetter(t)
+	members := mocks.NewEventMemberStorage(t)
+	u := &CheckInUseCase{CheckIns: checkIns, Events: events, Members: members, Signer: newTestTicketCodes(t)}
+	event := checkInEvent()
+	scannedAt := time.Now().Add(-time.Hour).UTC()
+
+	events.On("GetById", mock.Anything, event.EventID).Return(event, nil)
+	members.On("GetMember", mock.Anything, event.OrganizationID, int64(3)).
+		Return(&model.OrganizationMember{UserID: 3, IsOwner: true}, nil)
+	for _, id := range []int64{5, 6} {
+		checkIns.On("GetAttendee", mock.Anything, id).
+			Return(&model.Attendee{RegistrationID: id, EventID: event.EventID}, nil)
+	}
+	var saved []model.CheckIn
+	checkIns.On("CheckIn", mock.Anything, mock.Anything).
+		Return(func(_ context.Context, c *model.CheckIn) (*model.CheckIn, bool, error) {
+			saved = append(saved, *c)
+			return c, true, nil
+		})
+
+	results, err := u.SyncCheckIns(ctx, 3, event.EventID, &model.CheckInSync{
+		DeviceID: "scanner-1",
+		CheckIns: []model.OfflineCheckIn{
+			{Code: ticketCode(t, u, 5, event.EventID), CheckedInAt: scannedAt},
+			{Code: "invalid", CheckedInAt: scannedAt},
+			{Code: ticketCode(t, u, 6, event.EventID), CheckedInAt: time.Now().Add(time.Hour)},
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+	assert.Equal(t, model.CheckInAccepted, results[0].Status)
+	assert.Equal(t, model.CheckInRejected, results[1].Status)
+	assert.Equal(t, model.CheckInAccepted, results[2].Status)
+
+	require.Len(t, saved, 2)
+	assert.Equal(t, scannedAt, saved[0].CheckedInAt)
+	assert.Equal(t, "scanner-1", *saved[0].DeviceID)
+	assert.False(t, saved[1].CheckedInAt.After(time.Now()), "scans from the future are moved to now")
+}
+
+func TestCheckInUseCase_SyncCheckIns_BeforeOpening(t *testing.T) {
+	ctx := context.Background()
+	checkIns := mocks.NewCheckInStorage(t)
+	events := mocks.NewEventGetter(t)
+	members := mocks.NewEventMemberStorage(t)
+	u := &CheckInUseCase{CheckIns: checkIns, Events: events, Members: members, Signer: newTestTicketCodes(t)}
+	event := checkInEvent()
+
+	events.On("GetById", mock.Anything, event.EventID).Return(event, nil)
+	members.On("GetMember", mock.Anything, event.OrganizationID, int64(3)).
+		Return(&model.OrganizationMember{UserID: 3, IsOwner: true}, nil)
+
+	results, err := u.SyncCheckIns(ctx, 3, event.EventID, &model.CheckInSync{
+		DeviceID: "scanner-1",
+		CheckIns: []model.OfflineCheckIn{
+			{Code: ticketCode(t, u, 5, event.EventID), CheckedInAt: event.BeginsAt.Add(-checkInOpensBefore - time.Minute)},
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, model.CheckInRejected, results[0].Status, "backdated scans should not win over real ones")
+	checkIns.AssertNotCalled(t, "CheckIn", mock.Anything, mock.Anything)
+}
+
+func TestCheckInUseCase_CheckIn_Recurring(t *testing.T) {
+	ctx := context.Background()
+	checkIns := mocks.NewCheckInStorage(t)
+	events := mocks.NewEventGetter(t)
+	occurrences := mocks.NewCheckInOccurrenceStorage(t)
+	members := mocks.NewEventMemberStorage(t)
+	u := &CheckInUseCase{
+		CheckIns:    checkIns,
+		Events:      events,
+		Occurrences: occurrences,
+		Members:     members,
+		Signer:      newTestTicketCodes(t),
+	}
+	rule := "FREQ=WEEKLY"
+	event := checkInEvent()
+	event.BeginsAt = event.BeginsAt.AddDate(0, 0, -7)
+	event.RecurrenceRule = &rule
+	occurrence := &model.Occurrence{OccurrenceID: 8, EventID: event.EventID, BeginsAt: time.Now().Add(time.Hour)}
+	later := &model.Occurrence{OccurrenceID: 9, EventID: event.EventID, BeginsAt: time.Now().AddDate(0, 0, 7)}
+
+	events.On("GetById", mock.Anything, event.EventID).Return(event, nil)
+	members.On("GetMember", mock.Anything, event.OrganizationID, int64(3)).
+		Return(&model.OrganizationMember{UserID: 3, IsOwner: true}, nil)
+	occurrences.On("GetOccurrence", mock.Anything, event.EventID, occurrence.OccurrenceID).Return(occurrence, nil)
+	occurrences.On("GetOccurrence", mock.Anything, event.EventID, later.OccurrenceID).Return(later, nil)
+	checkIns.On("GetAttendee", mock.Anything, int64(5)).
+		Return(&model.Attendee{RegistrationID: 5, EventID: event.EventID, OccurrenceID: &occurrence.OccurrenceID}, nil)
+	checkIns.On("CheckIn", mock.Anything, mock.Anything).
+		Return(&model.CheckIn{RegistrationID: 5, EventID: event.EventID}, true, nil).Once()
+	code := ticketCode(t, u, 5, event.EventID)
+
+	_, err := u.CheckIn(ctx, 3, event.EventID, &model.CheckInCreate{Code: code})
+	assert.ErrorIs(t, err, ErrBusinessLogicViolation, "occurrence should be required for recurring events")
+
+	result, err := u.CheckIn(ctx, 3, event.EventID, &model.CheckInCreate{Code: code, OccurrenceID: &later.OccurrenceID})
+	require.NoError(t, err)
+	assert.Equal(t, model.CheckInRejected, result.Status, "later occurrences should not be checked in yet")
+
+	result, err = u.CheckIn(ctx, 3, event.EventID, &model.CheckInCreate{Code: code, OccurrenceID: &occurrence.OccurrenceID})
+	require.NoError(t, err)
+	assert.Equal(t, model.CheckInAccepted, result.Status)
+}
+
+func TestCheckInUseCase_GetTicket_OtherUser(t *testing.T) {
+	ctx := context.Background()
+	checkIns := mocks.NewCheckInStorage(t)
+	u := &CheckInUseCase{CheckIns: checkIns, Signer: newTestTicketCodes(t)}
+
+	checkIns.On("GetAttendee", mock.Anything, int64(5)).
+		Return(&model.Attendee{RegistrationID: 5, EventID: 1, UserID: 4}, nil)
+
+	ticket, err := u.GetTicket(ctx, 4, 5)
+	require.NoError(t, err)
+	claims, err := u.Signer.Verify(ticket.Code)
+	require.NoError(t, err)
+	assert.Equal(t, model.TicketClaims{RegistrationID: 5, EventID: 1}, *claims)
+
+	_, err = u.GetTicket(ctx, 3, 5)
+	assert.ErrorIs(t, err, repositories.ErrRegistrationNotFound)
+}
@@ -0,0 +1,233 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/burenotti/rtu-it-lab-recruit/model"
+	"github.com/burenotti/rtu-it-lab-recruit/repositories"
+	"github.com/burenotti/rtu-it-lab-recruit/services"
+	"github.com/sirupsen/logrus"
+	"time"
+)
+
+const (
+	digestPeriod = 7 * 24 * time.Hour
+	// digestDispatchDelay is how late a digest may be sent after its scheduled time.
+	// Time of the last digest is the time it was sent, so the delay is subtracted to get the slot it was sent in.
+	digestDispatchDelay = time.Hour
+)
+
+type DigestSearchStorage interface {
+	ListSearches(ctx context.Context, userId int64) ([]model.SavedSearch, error)
+}
+
+type DigestStorage interface {
+	GetDigestSettings(ctx context.Context, userId int64) (*model.DigestSettings, error)
+	SaveDigestSettings(ctx context.Context, settings *model.DigestSettings) (*model.DigestSettings, error)
+	ClaimDueDigest(ctx context.Context) (*model.DigestSettings, error)
+	MarkDigestSent(ctx context.Context, userId int64, nextSendAt time.Time) error
+}
+
+// DigestUseCase sends weekly digests of upcoming events to users who enabled them.
+type DigestUseCase struct {
+	Transactioner StorageTransactioner
+	Digests       DigestStorage
+	Users         ChannelUserStorage
+	Events        EventStorage
+	Searches      DigestSearchStorage
+	Notifier      ChannelNotifier
+	Logger        *logrus.Logger
+}
+
+type digestContext struct {
+	User *model.User
+	// Followed are upcoming events of followed organizations. Event times are in the timezone of the user.
+	Followed []model.Event
+	// Searches are saved searches of the user with upcoming events matching them.
+	Searches []searchDigest
+}
+
+type searchDigest struct {
+	Search *model.SavedSearch
+	Events []model.Event
+}
+
+func (c *digestContext) isEmpty() bool {
+	return len(c.Followed) == 0 && len(c.Searches) == 0
+}
+
+// DefaultDigestSettings are settings of users who have never changed them. Digest is disabled by default.
+func DefaultDigestSettings(userId int64) *model.DigestSettings {
+	return &model.DigestSettings{
+		UserID:   userId,
+		Enabled:  false,
+		Weekday:  int(time.Monday),
+		Time:     "09:00",
+		Timezone: "UTC",
+	}
+}
+
+func (u *DigestUseCase) GetDigestSettings(ctx context.Context, userId int64) (*model.DigestSettings, error) {
+	settings, err := u.Digests.GetDigestSettings(ctx, userId)
+	if errors.Is(err, repositories.ErrDigestSettingsNotFound) {
+		settings = DefaultDigestSettings(userId)
+		settings.NextSendAt, err = NextDigestAt(settings, time.Now())
+	}
+	return settings, err
+}
+
+func (u *DigestUseCase) UpdateDigestSettings(
+	ctx context.Context,
+	userId int64,
+	update *model.DigestSettingsUpdate,
+) (*model.DigestSettings, error) {
+	var settings *model.DigestSettings
+	err := u.Transactioner.Atomic(ctx, func(ctx context.Context) (err error) {
+		if settings, err = u.GetDigestSettings(ctx, userId); err != nil {
+			return err
+		}
+		if update.Enabled != nil {
+			settings.Enabled = *update.Enabled
+		}
+		if update.Weekday != nil {
+			settings.Weekday = *update.Weekday
+		}
+		if update.Time != nil {
+			settings.Time = *update.Time
+		}
+		if update.Timezone != nil {
+			settings.Timezone = *update.Timezone
+		}
+		if settings.NextSendAt, err = NextDigestAt(settings, nextDigestAfter(settings, time.Now())); err != nil {
+			return err
+		}
+		settings, err = u.Digests.SaveDigestSettings(ctx, settings)
+		return err
+	})
+	return settings, err
+}
+
+// DispatchDigests sends due digests. Settings of each user are locked while the digest is put to the outbox
+// and moved to the next period in the same transaction, so every user gets a single digest per period.
+func (u *DigestUseCase) DispatchDigests(ctx context.Context) error {
+	for ctx.Err() == nil {
+		err := u.Transactioner.Atomic(ctx, func(ctx context.Context) error {
+			settings, err := u.Digests.ClaimDueDigest(ctx)
+			if err != nil {
+				return err
+			}
+			if err = u.sendDigest(ctx, settings); err != nil {
+				return err
+			}
+			// Periods missed while the dispatcher was down are skipped rather than sent one after another.
+			next, err := NextDigestAt(settings, time.Now())
+			if err != nil {
+				return err
+			}
+			return u.Digests.MarkDigestSent(ctx, settings.UserID, next)
+		})
+		if errors.Is(err, repositories.ErrDigestSettingsNotFound) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+	}
+	return ctx.Err()
+}
+
+func (u *DigestUseCase) sendDigest(ctx context.Context, settings *model.DigestSettings) error {
+	user, err := u.Users.GetById(ctx, settings.UserID)
+	if err != nil {
+		return err
+	}
+	loc, err := time.LoadLocation(settings.Timezone)
+	if err != nil {
+		return err
+	}
+
+	digest, err := u.buildDigest(ctx, user, loc, time.Now())
+	if err != nil {
+		return err
+	}
+	if digest.isEmpty() {
+		u.Logger.WithField("user_id", user.UserID).Debugf("Digest of user %d is empty, skipping", user.UserID)
+		return nil
+	}
+	return u.Notifier.SendTo(ctx, user, services.EmailChannel(user), model.MessageWeeklyDigest, digest)
+}
+
+// buildDigest collects events beginning during the period after now. Searches without upcoming events are omitted.
+func (u *DigestUseCase) buildDigest(ctx context.Context, user *model.User, loc *time.Location, now time.Time) (*digestContext, error) {
+	digest := &digestContext{User: user}
+	var err error
+	if digest.Followed, err = u.upcoming(ctx, now, repositories.NewEventFollowedFilter(user.UserID)); err != nil {
+		return nil, err
+	}
+	inLocation(digest.Followed, loc)
+
+	searches, err := u.Searches.ListSearches(ctx, user.UserID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range searches {
+		events, err := u.upcoming(ctx, now, repositories.NewEventSearchFilter(&searches[i].Filter, now))
+		if err != nil {
+			return nil, err
+		}
+		if len(events) > 0 {
+			inLocation(events, loc)
+			digest.Searches = append(digest.Searches, searchDigest{Search: &searches[i], Events: events})
+		}
+	}
+	return digest, nil
+}
+
+func (u *DigestUseCase) upcoming(ctx context.Context, now time.Time, filter repositories.EventFilter) ([]model.Event, error) {
+	return u.Events.SelectBy(ctx, repositories.NewEventAndFilter(
+		repositories.NewEventPublishedFilter(),
+		repositories.NewEventBeginsBetweenFilter(now, now.Add(digestPeriod)),
+		filter,
+	))
+}
+
+func inLocation(events []model.Event, loc *time.Location) {
+	for i := range events {
+		events[i].BeginsAt = events[i].BeginsAt.In(loc)
+		events[i].EndsAt = events[i].EndsAt.In(loc)
+	}
+}
+
+// NextDigestAt returns the first moment after the provided time the digest is sent at according to the settings.
+// nextDigestAfter returns the moment the next digest is scheduled after. Changing the schedule must not
+// send the second digest in the same period, so it is not earlier than a period after the last digest.
+func nextDigestAfter(settings *model.DigestSettings, now time.Time) time.Time {
+	if settings.LastSentAt == nil {
+		return now
+	}
+	periodEnd := settings.LastSentAt.Add(digestPeriod - digestDispatchDelay)
+	if periodEnd.After(now) {
+		return periodEnd
+	}
+	return now
+}
+
+func NextDigestAt(settings *model.DigestSettings, after time.Time) (time.Time, error) {
+	loc, err := time.LoadLocation(settings.Timezone)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: unknown timezone %q", ErrBusinessLogicViolation, settings.Timezone)
+	}
+	at, err := time.Parse("15:04", settings.Time)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: invalid digest time %q", ErrBusinessLogicViolation, settings.Time)
+	}
+
+	local := after.In(loc)
+	days := (settings.Weekday - int(local.Weekday()) + 7) % 7
+	// Dates are built from calendar days rather than adding hours, so the time is kept across DST changes.
+	next := time.Date(local.Year(), local.Month(), local.Day()+days, at.Hour(), at.Minute(), 0, 0, loc)
+	if !next.After(after) {
+		next = time.Date(local.Year(), local.Month(), local.Day()+days+7, at.Hour(), at.Minute(), 0, 0, loc)
+	}
+	return next.UTC(), nil
+}
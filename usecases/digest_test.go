@@ -0,0 +1,140 @@
+package usecases
+
+import (
+	"context"
+	"github.com/burenotti/rtu-it-lab-recruit/model"
+	"github.com/burenotti/rtu-it-lab-recruit/repositories"
+	"github.com/burenotti/rtu-it-lab-recruit/usecases/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestNextDigestAt(t *testing.T) {
+	settings := &model.DigestSettings{Weekday: int(time.Monday), Time: "09:00", Timezone: "Europe/Moscow"}
+	cases := []struct {
+		name     string
+		after    time.Time
+		expected time.Time
+	}{
+		{
+			name:     "later this week",
+			after:    time.Date(2023, 5, 27, 12, 0, 0, 0, time.UTC), // Saturday
+			expected: time.Date(2023, 5, 29, 6, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "later today",
+			after:    time.Date(2023, 5, 29, 5, 0, 0, 0, time.UTC),
+			expected: time.Date(2023, 5, 29, 6, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "exactly at send time",
+			after:    time.Date(2023, 5, 29, 6, 0, 0, 0, time.UTC),
+			expected: time.Date(2023, 6, 5, 6, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "already Monday in Moscow",
+			after:    time.Date(2023, 5, 28, 22, 0, 0, 0, time.UTC), // 01:00 Monday in Moscow
+			expected: time.Date(2023, 5, 29, 6, 0, 0, 0, time.UTC),
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			next, err := NextDigestAt(settings, c.after)
+			require.NoError(t, err)
+			assert.Equal(t, c.expected, next)
+		})
+	}
+
+	_, err := NextDigestAt(&model.DigestSettings{Time: "09:00", Timezone: "Mars/Olympus"}, time.Now())
+	assert.ErrorIs(t, err, ErrBusinessLogicViolation)
+}
+
+func TestDigestUseCase_DispatchDigests(t *testing.T) {
+	ctx := context.Background()
+	digests := mocks.NewDigestStorage(t)
+	users := mocks.NewChannelUserStorage(t)
+	events := mocks.NewEventStorage(t)
+	searches := mocks.NewDigestSearchStorage(t)
+	notifier := mocks.NewChannelNotifier(t)
+	u := &DigestUseCase{
+		Transactioner: newTestTransactioner(t),
+		Digests:       digests,
+		Users:         users,
+		Events:        events,
+		Searches:      searches,
+		Notifier:      notifier,
+		Logger:        newTestLogger(),
+	}
+	due := &model.DigestSettings{UserID: 1, Enabled: true, Weekday: 1, Time: "09:00", Timezone: "Europe/Moscow"}
+	empty := &model.DigestSettings{UserID: 2, Enabled: true, Weekday: 1, Time: "09:00", Timezone: "UTC"}
+	lecture := model.Event{EventID: 3, Name: "Lecture", BeginsAt: time.Now().Add(time.Hour).UTC()}
+
+	users.On("GetById", mock.Anything, mock.Anything).
+		Return(func(_ context.Context, userId int64) *model.User {
+			return &model.User{UserID: userId, Email: "johndoe@example.com"}
+		}, nil)
+	searches.On("ListSearches", mock.Anything, mock.Anything).Return(nil, nil)
+	digests.On("ClaimDueDigest", mock.Anything).Return(due, nil).Once()
+	digests.On("ClaimDueDigest", mock.Anything).Return(empty, nil).Once()
+	digests.On("ClaimDueDigest", mock.Anything).Return(nil, repositories.ErrDigestSettingsNotFound).Once()
+	events.On("SelectBy", mock.Anything, mock.Anything).Return([]model.Event{lecture}, nil).Once()
+	events.On("SelectBy", mock.Anything, mock.Anything).Return(nil, nil).Once()
+	notifier.On("SendTo", mock.Anything, mock.Anything, mock.Anything, model.MessageWeeklyDigest,
+		mock.MatchedBy(func(data *digestContext) bool {
+			return data.User.UserID == due.UserID &&
+				len(data.Followed) == 1 &&
+				data.Followed[0].BeginsAt.Location().String() == "Europe/Moscow"
+		})).Return(nil).Once()
+	digests.On("MarkDigestSent", mock.Anything, due.UserID, mock.MatchedBy(func(next time.Time) bool {
+		return next.After(time.Now())
+	})).Return(nil).Once()
+	digests.On("MarkDigestSent", mock.Anything, empty.UserID, mock.Anything).Return(nil).Once()
+
+	assert.NoError(t, u.DispatchDigests(ctx))
+}
+
+func TestDigestUseCase_UpdateDigestSettings_AfterSent(t *testing.T) {
+	ctx := context.Background()
+	digests := mocks.NewDigestStorage(t)
+	u := &DigestUseCase{Transactioner: newTestTransactioner(t), Digests: digests}
+	weekday := int(time.Now().UTC().Add(time.Hour).Weekday())
+	sendTime := time.Now().UTC().Add(time.Hour).Format("15:04")
+	lastSentAt := time.Now().UTC().Add(-time.Hour)
+	settings := &model.DigestSettings{
+		UserID: 1, Enabled: true, Weekday: int(time.Monday), Time: "09:00", Timezone: "UTC", LastSentAt: &lastSentAt,
+	}
+
+	digests.On("GetDigestSettings", mock.Anything, int64(1)).Return(settings, nil).Once()
+	digests.On("SaveDigestSettings", mock.Anything, mock.Anything).
+		Return(func(_ context.Context, s *model.DigestSettings) *model.DigestSettings {
+			return s
+		}, nil).Once()
+
+	updated, err := u.UpdateDigestSettings(ctx, 1, &model.DigestSettingsUpdate{Weekday: &weekday, Time: &sendTime})
+	require.NoError(t, err)
+	assert.True(t, updated.NextSendAt.After(lastSentAt.Add(6*24*time.Hour)),
+		"moving the schedule should not send another digest in the same week, next is %s", updated.NextSendAt)
+}
+
+func TestDigestUseCase_UpdateDigestSettings(t *testing.T) {
+	ctx := context.Background()
+	digests := mocks.NewDigestStorage(t)
+	u := &DigestUseCase{Transactioner: newTestTransactioner(t), Digests: digests}
+	enabled := true
+	timezone := "Asia/Yekaterinburg"
+
+	digests.On("GetDigestSettings", mock.Anything, int64(1)).
+		Return(nil, repositories.ErrDigestSettingsNotFound).Once()
+	digests.On("SaveDigestSettings", mock.Anything, mock.MatchedBy(func(s *model.DigestSettings) bool {
+		return s.Enabled && s.Timezone == timezone && s.Time == "09:00" && s.NextSendAt.After(time.Now())
+	})).Return(func(_ context.Context, s *model.DigestSettings) *model.DigestSettings {
+		return s
+	}, nil).Once()
+
+	settings, err := u.UpdateDigestSettings(ctx, 1, &model.DigestSettingsUpdate{Enabled: &enabled, Timezone: &timezone})
+	require.NoError(t, err)
+	assert.Equal(t, int(time.Monday), settings.Weekday)
+}
@@ -2,6 +2,7 @@ package usecases
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/burenotti/rtu-it-lab-recruit/model"
 	"github.com/burenotti/rtu-it-lab-recruit/repositories"
@@ -40,15 +41,16 @@ func (s *EmailSignInUseCase) RequestCode(ctx context.Context, userEmail string)
 		if err != nil {
 			return err
 		}
+		if err := checkCanSignIn(user); err != nil {
+			return err
+		}
 
 		code := GenerateActivationCode()
 		if err = s.LoginCodeStore.CreateLoginCode(ctx, user.UserID, code); err != nil {
 			return err
 		}
 
-		text := fmt.Sprintf("Your activation code is: %s", code)
-		err = s.Delivery.SendCode(ctx, user, text)
-		return err
+		return s.Delivery.SendCode(ctx, user, code)
 	})
 }
 
@@ -59,6 +61,9 @@ func (s *EmailSignInUseCase) SignIn(ctx context.Context, creds *model.AuthCreden
 		if err != nil {
 			return err
 		}
+		if err := checkCanSignIn(user); err != nil {
+			return err
+		}
 
 		if err := s.LoginCodeStore.MarkCodeUsed(ctx, user.UserID, creds.Code); err != nil {
 			return err
@@ -70,6 +75,31 @@ func (s *EmailSignInUseCase) SignIn(ctx context.Context, creds *model.AuthCreden
 	return token, err
 }
 
+// CanSignIn reports whether the user may still use issued tokens. Tokens are valid until they expire,
+// so tokens of banned, deactivated and erased users are rejected by this check.
+func (s *EmailSignInUseCase) CanSignIn(ctx context.Context, userId int64) (bool, error) {
+	user, err := s.UserStore.GetById(ctx, userId)
+	if errors.Is(err, repositories.ErrUserNotFound) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return checkCanSignIn(user) == nil, nil
+}
+
+func checkCanSignIn(user *model.User) error {
+	if user.IsBanned() {
+		return ErrUserBanned
+	}
+	if user.IsDeactivated() {
+		return ErrUserDeactivated
+	}
+	if !user.IsActive {
+		return fmt.Errorf("%w: follow the link from activation email or request a new one", ErrUserNotActivated)
+	}
+	return nil
+}
+
 func GenerateActivationCode() string {
 	intCode := rand.Int() % 10000
 	return fmt.Sprintf("%04d", intCode)
@@ -0,0 +1,636 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/burenotti/rtu-it-lab-recruit/model"
+	"github.com/burenotti/rtu-it-lab-recruit/repositories"
+	"github.com/sirupsen/logrus"
+	"time"
+)
+
+const eventTimeLayout = "02.01.2006 15:04 MST"
+
+type ManagedEventStorage interface {
+	Create(ctx context.Context, create *model.EventCreate) (*model.Event, error)
+	GetById(ctx context.Context, eventId int64) (*model.Event, error)
+	SelectBy(ctx context.Context, filter repositories.EventFilter) ([]model.Event, error)
+	UpdateEvent(ctx context.Context, eventId int64, updates map[string]interface{}) (*model.Event, error)
+	DeleteEvent(ctx context.Context, eventId int64) error
+	Publish(ctx context.Context, eventId int64) error
+	Cancel(ctx context.Context, eventId int64, reason string) error
+	Touch(ctx context.Context, eventId int64) error
+}
+
+type EventMemberStorage interface {
+	GetMember(ctx context.Context, orgId int64, userId int64) (*model.OrganizationMember, error)
+}
+
+type EventVenueStorage interface {
+	GetVenue(ctx context.Context, venueId int64) (*model.Venue, error)
+	ListVenuesByIds(ctx context.Context, venueIds []int64) ([]model.Venue, error)
+}
+
+type EventTagStorage interface {
+	SetTags(ctx context.Context, eventId int64, tags []string) error
+	ListTags(ctx context.Context, eventIds []int64) (map[int64][]string, error)
+}
+
+type RegistrationStorage interface {
+	Register(ctx context.Context, eventId, userId int64, occurrenceId *int64, source *string) (*model.Registration, error)
+	Unregister(ctx context.Context, eventId, userId int64, occurrenceId *int64) error
+	ListRegistrants(ctx context.Context, eventId int64) ([]model.User, error)
+	ListOccurrenceRegistrants(ctx context.Context, occurrenceId int64) ([]model.User, error)
+}
+
+type ReminderPlanner interface {
+	ReplanEvent(ctx context.Context, eventId int64) error
+}
+
+type FanoutScheduler interface {
+	ScheduleFanout(ctx context.Context, eventId int64) error
+}
+
+type SearchAlertScheduler interface {
+	ScheduleSearchAlerts(ctx context.Context, eventId int64) error
+}
+
+type EventTicketStorage interface {
+	ListTicketTypes(ctx context.Context, eventId int64) ([]model.TicketType, error)
+}
+
+type WebhookEmitter interface {
+	Emit(ctx context.Context, orgId int64, eventType string, data interface{}) error
+}
+
+// EventUseCase implements management of events and registrations for them.
+type EventUseCase struct {
+	Transactioner StorageTransactioner
+	Events        ManagedEventStorage
+	Members       EventMemberStorage
+	Venues        EventVenueStorage
+	Tags          EventTagStorage
+	Occurrences   OccurrenceStorage
+	Registrations RegistrationStorage
+	Users         ChannelUserStorage
+	Reminders     ReminderPlanner
+	Notifier      UserNotifier
+	Fanouts       FanoutScheduler
+	SearchAlerts  SearchAlertScheduler
+	Webhooks      WebhookEmitter
+	Imports       EventImportStorage
+	Tickets       EventTicketStorage
+	Logger        *logrus.Logger
+	// RecurrenceHorizon is how far ahead occurrences of recurring events are materialized.
+	RecurrenceHorizon time.Duration
+}
+
+type eventChangedContext struct {
+	User    *model.User
+	Event   *model.Event
+	Changes []model.EventChange
+}
+
+type registrationConfirmedContext struct {
+	User  *model.User
+	Event *model.Event
+}
+
+type eventCancelledContext struct {
+	User   *model.User
+	Event  *model.Event
+	Reason string
+}
+
+// UpdateEvent updates the event on behalf of organization member with rights to edit events.
+// If the event time is changed, reminders are planned again and registrants are notified about the change.
+// Changes of published events are sent to organization's webhooks.
+// Tags of the event are returned only if they are updated.
+// Changes of the rule or the time of recurring event apply to all its upcoming occurrences.
+func (u *EventUseCase) UpdateEvent(ctx context.Context, userId, eventId int64, update *model.EventUpdate) (*model.Event, error) {
+	var event *model.Event
+	err := u.Transactioner.Atomic(ctx, func(ctx context.Context) error {
+		current, err := u.Events.GetById(ctx, eventId)
+		if err != nil {
+			return err
+		}
+		if err = u.checkCanEdit(ctx, current.OrganizationID, userId); err != nil {
+			return err
+		}
+		event, err = u.updateEvent(ctx, current, update)
+		return err
+	})
+	return event, err
+}
+
+func (u *EventUseCase) updateEvent(ctx context.Context, current *model.Event, update *model.EventUpdate) (*model.Event, error) {
+	eventId := current.EventID
+	if current.IsCancelled() {
+		return nil, fmt.Errorf("%w: cancelled event can't be changed", ErrBusinessLogicViolation)
+	}
+	if update.VenueID != nil && *update.VenueID != 0 {
+		if err := u.checkVenue(ctx, current.OrganizationID, *update.VenueID); err != nil {
+			return nil, err
+		}
+	}
+
+	updates, err := eventUpdates(current, update)
+	if err != nil {
+		return nil, err
+	}
+	var tags []string
+	if update.Tags != nil {
+		tags = normalizeTags(update.Tags)
+		if err = u.Tags.SetTags(ctx, eventId, tags); err != nil {
+			return nil, err
+		}
+	}
+	if len(updates) == 0 {
+		current.Tags = tags
+		return current, nil
+	}
+	event, err := u.Events.UpdateEvent(ctx, eventId, updates)
+	if err != nil {
+		return nil, err
+	}
+	event.Tags = tags
+	if !event.BeginsAt.Equal(current.BeginsAt) {
+		if err = u.Reminders.ReplanEvent(ctx, eventId); err != nil {
+			return nil, err
+		}
+	}
+	if recurrenceChanged(current, event) {
+		shift := event.BeginsAt.Sub(current.BeginsAt)
+		if err = u.syncOccurrences(ctx, event, shift); err != nil {
+			return nil, err
+		}
+	}
+	changes, err := u.diffEvents(ctx, current, event)
+	if err != nil {
+		return nil, err
+	}
+	if event.IsPublished() {
+		data := model.WebhookEventUpdatedData{Event: event, Changes: changes}
+		if data.Changes == nil {
+			data.Changes = []model.EventChange{}
+		}
+		if err = u.Webhooks.Emit(ctx, event.OrganizationID, model.WebhookEventUpdated, data); err != nil {
+			return nil, err
+		}
+	}
+	if len(changes) == 0 {
+		return event, nil
+	}
+	registrants, err := u.Registrations.ListRegistrants(ctx, eventId)
+	if err != nil {
+		return nil, err
+	}
+	return event, u.notifyRegistrants(ctx, registrants, model.MessageEventChanged, func(user *model.User) interface{} {
+		return eventChangedContext{User: user, Event: localEvent(event), Changes: changes}
+	})
+}
+
+// PublishEvent makes the event visible to everyone, schedules notification of organization followers,
+// matching against saved searches and delivery to organization's webhooks.
+func (u *EventUseCase) PublishEvent(ctx context.Context, userId, eventId int64) (*model.Event, error) {
+	var event *model.Event
+	err := u.Transactioner.Atomic(ctx, func(ctx context.Context) (err error) {
+		if event, err = u.Events.GetById(ctx, eventId); err != nil {
+			return err
+		}
+		if err = u.checkCanEdit(ctx, event.OrganizationID, userId); err != nil {
+			return err
+		}
+		if event.IsPublished() {
+			return fmt.Errorf("%w: event is already published", ErrBusinessLogicViolation)
+		}
+		if event.IsCancelled() {
+			return fmt.Errorf("%w: cancelled event can't be published", ErrBusinessLogicViolation)
+		}
+		if err = u.Events.Publish(ctx, eventId); err != nil {
+			return err
+		}
+		if err = u.Reminders.ReplanEvent(ctx, eventId); err != nil {
+			return err
+		}
+		if event, err = u.Events.GetById(ctx, eventId); err != nil {
+			return err
+		}
+		if err = u.Fanouts.ScheduleFanout(ctx, eventId); err != nil {
+			return err
+		}
+		if err = u.SearchAlerts.ScheduleSearchAlerts(ctx, eventId); err != nil {
+			return err
+		}
+		return u.Webhooks.Emit(ctx, event.OrganizationID, model.WebhookEventPublished, event)
+	})
+	return event, err
+}
+
+// DeleteEvent deletes the event. If someone has registered for the event, it is cancelled instead,
+// so registrants are notified and could see what happened.
+// It returns true if the event was cancelled rather than deleted.
+func (u *EventUseCase) DeleteEvent(ctx context.Context, userId, eventId int64, cancel *model.EventCancel) (bool, error) {
+	cancelled := false
+	err := u.Transactioner.Atomic(ctx, func(ctx context.Context) error {
+		event, err := u.Events.GetById(ctx, eventId)
+		if err != nil {
+			return err
+		}
+		if err = u.checkCanEdit(ctx, event.OrganizationID, userId); err != nil {
+			return err
+		}
+
+		registrants, err := u.Registrations.ListRegistrants(ctx, eventId)
+		if err != nil {
+			return err
+		}
+		if len(registrants) == 0 {
+			return u.Events.DeleteEvent(ctx, eventId)
+		}
+		if event.IsCancelled() {
+			return fmt.Errorf("%w: event with registrants can't be deleted, it is already cancelled", ErrBusinessLogicViolation)
+		}
+
+		cancelled = true
+		return u.cancelEvent(ctx, event, registrants, cancel.Reason)
+	})
+	return cancelled, err
+}
+
+// cancelEvent cancels the event and notifies its registrants.
+func (u *EventUseCase) cancelEvent(ctx context.Context, event *model.Event, registrants []model.User, reason string) error {
+	if err := u.Events.Cancel(ctx, event.EventID, reason); err != nil {
+		return err
+	}
+	// Cancelled events have no reminders, so replanning drops them.
+	if err := u.Reminders.ReplanEvent(ctx, event.EventID); err != nil {
+		return err
+	}
+	return u.notifyRegistrants(ctx, registrants, model.MessageEventCancelled, func(user *model.User) interface{} {
+		return eventCancelledContext{User: user, Event: localEvent(event), Reason: reason}
+	})
+}
+
+// RegisterForEvent registers the user for published event which has not begun yet.
+// If the event has registration window, the user must register within it.
+// Users register for occurrences of recurring events, the window is shifted to the time of the occurrence.
+// Users register for events selling tickets by ordering them.
+// The user is notified about the registration in all enabled channels, the organization is notified with webhooks.
+func (u *EventUseCase) RegisterForEvent(ctx context.Context, userId, eventId int64, target *model.RegistrationTarget) (*model.Registration, error) {
+	var registration *model.Registration
+	err := u.Transactioner.Atomic(ctx, func(ctx context.Context) error {
+		event, err := u.Events.GetById(ctx, eventId)
+		if err != nil {
+			return err
+		}
+		if !event.IsPublished() || event.IsHidden() {
+			return fmt.Errorf("%w: event with provided id does not exist", repositories.ErrEventNotFount)
+		}
+		if event.IsCancelled() {
+			return fmt.Errorf("%w: event is cancelled", ErrBusinessLogicViolation)
+		}
+		occurrence, err := u.occurrenceFor(ctx, event, target.OccurrenceID)
+		if err != nil {
+			return err
+		}
+		var occurrenceIdArg *int64
+		if occurrence != nil {
+			event = eventOccurrence(event, occurrence)
+			occurrenceIdArg = &occurrence.OccurrenceID
+		}
+		if err = checkRegistrationOpen(event, time.Now()); err != nil {
+			return err
+		}
+		ticketTypes, err := u.Tickets.ListTicketTypes(ctx, eventId)
+		if err != nil {
+			return err
+		} else if len(ticketTypes) > 0 {
+			return fmt.Errorf("%w: tickets must be ordered to register for the event", ErrBusinessLogicViolation)
+		}
+		registration, err = u.Registrations.Register(ctx, eventId, userId, occurrenceIdArg, trafficSource(target.Source))
+		if err != nil {
+			return err
+		}
+		user, err := u.Users.GetById(ctx, userId)
+		if err != nil {
+			return err
+		}
+		err = u.Webhooks.Emit(ctx, event.OrganizationID, model.WebhookRegistrationCreated, model.WebhookRegistrationData{
+			Registration: registration,
+			FirstName:    user.FirstName,
+			LastName:     user.LastName,
+		})
+		if err != nil {
+			return err
+		}
+		return u.Notifier.SendToAll(ctx, user, model.MessageRegistrationConfirmed, registrationConfirmedContext{
+			User:  user,
+			Event: event,
+		})
+	})
+	return registration, err
+}
+
+// SearchEvents returns published events beginning within a year, ordered by the beginning.
+// Recurring events are found by their upcoming occurrences.
+// Found events have their venues and tags loaded.
+func (u *EventUseCase) SearchEvents(ctx context.Context, query *model.EventQuery) ([]model.Event, error) {
+	now := time.Now().UTC()
+	filters := []repositories.EventFilter{
+		repositories.NewEventPublishedFilter(),
+		repositories.NewEventOccursBetweenFilter(now, now.AddDate(1, 0, 0)),
+	}
+	if query.MinLatitude != nil && *query.MinLatitude > *query.MaxLatitude {
+		return nil, fmt.Errorf("%w: min_lat must not be greater than max_lat", ErrBusinessLogicViolation)
+	}
+	// The query is matched the same way as saved searches
+	if search := eventQueryFilter(query); search != nil {
+		filters = append(filters, repositories.NewEventSearchFilter(search, now))
+	}
+	limit := query.Limit
+	if limit == 0 {
+		limit = defaultPageSize
+	}
+
+	events, err := u.Events.SelectBy(ctx, repositories.NewLimitFilter(int64(limit), repositories.NewEventAndFilter(filters...)))
+	if err != nil {
+		return nil, err
+	}
+	if events == nil {
+		return []model.Event{}, nil
+	}
+	if err = u.loadVenues(ctx, events); err != nil {
+		return nil, err
+	}
+	return events, u.loadTags(ctx, events)
+}
+
+// CancelRegistration cancels registration of the user for the event, or for its occurrence if occurrenceId is set.
+func (u *EventUseCase) CancelRegistration(ctx context.Context, userId, eventId, occurrenceId int64) error {
+	if occurrenceId == 0 {
+		return u.Registrations.Unregister(ctx, eventId, userId, nil)
+	}
+	return u.Registrations.Unregister(ctx, eventId, userId, &occurrenceId)
+}
+
+func (u *EventUseCase) notifyRegistrants(
+	ctx context.Context,
+	registrants []model.User,
+	kind string,
+	data func(user *model.User) interface{},
+) error {
+	for i := range registrants {
+		user := &registrants[i]
+		if err := u.Notifier.SendToAll(ctx, user, kind, data(user)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (u *EventUseCase) checkCanEdit(ctx context.Context, orgId, userId int64) error {
+	return checkCanEditEvents(ctx, u.Members, orgId, userId, "edit event")
+}
+
+// checkVenue checks the venue belongs to the organization of the event.
+func (u *EventUseCase) checkVenue(ctx context.Context, orgId, venueId int64) error {
+	venue, err := u.Venues.GetVenue(ctx, venueId)
+	if err != nil {
+		return err
+	}
+	if venue.OrganizationID != orgId {
+		return fmt.Errorf("%w: venue with provided id does not exist", repositories.ErrVenueNotFound)
+	}
+	return nil
+}
+
+func (u *EventUseCase) loadVenues(ctx context.Context, events []model.Event) error {
+	var venueIds []int64
+	for i := range events {
+		if events[i].VenueID != nil {
+			venueIds = append(venueIds, *events[i].VenueID)
+		}
+	}
+	if len(venueIds) == 0 {
+		return nil
+	}
+	venues, err := u.Venues.ListVenuesByIds(ctx, venueIds)
+	if err != nil {
+		return err
+	}
+	byId := make(map[int64]*model.Venue, len(venues))
+	for i := range venues {
+		byId[venues[i].VenueID] = &venues[i]
+	}
+	for i := range events {
+		if events[i].VenueID != nil {
+			events[i].Venue = byId[*events[i].VenueID]
+		}
+	}
+	return nil
+}
+
+func (u *EventUseCase) loadTags(ctx context.Context, events []model.Event) error {
+	eventIds := make([]int64, 0, len(events))
+	for i := range events {
+		eventIds = append(eventIds, events[i].EventID)
+	}
+	tags, err := u.Tags.ListTags(ctx, eventIds)
+	if err != nil {
+		return err
+	}
+	for i := range events {
+		events[i].Tags = tags[events[i].EventID]
+	}
+	return nil
+}
+
+// checkCanEditEvents checks the user is a member of the organization with rights to edit events.
+// Action is what the user is going to do, it is shown in the error.
+func checkCanEditEvents(ctx context.Context, members EventMemberStorage, orgId, userId int64, action string) error {
+	errLogic := fmt.Errorf("%w: only members with rights to edit events can %s", ErrBusinessLogicViolation, action)
+
+	member, err := members.GetMember(ctx, orgId, userId)
+	if errors.Is(err, repositories.ErrMemberNotFound) {
+		return errLogic
+	} else if err != nil {
+		return err
+	}
+	if !member.IsOwner && !member.Can.EditEvents {
+		return errLogic
+	}
+	return nil
+}
+
+func checkRegistrationOpen(event *model.Event, now time.Time) error {
+	if !event.BeginsAt.After(now) {
+		return fmt.Errorf("%w: event has already begun", ErrBusinessLogicViolation)
+	}
+	if event.RegistrationBegin != nil && now.Before(*event.RegistrationBegin) {
+		return fmt.Errorf("%w: registration has not started yet", ErrBusinessLogicViolation)
+	}
+	if event.RegistrationEnd != nil && now.After(*event.RegistrationEnd) {
+		return fmt.Errorf("%w: registration is over", ErrBusinessLogicViolation)
+	}
+	return nil
+}
+
+func eventUpdates(current *model.Event, update *model.EventUpdate) (repositories.UpdatesMap, error) {
+	updates := repositories.UpdatesMap{}
+	beginsAt, endsAt := current.BeginsAt, current.EndsAt
+	if update.Name != nil {
+		updates["name"] = *update.Name
+	}
+	if update.Description != nil {
+		updates["description"] = *update.Description
+	}
+	if update.BeginsAt != nil {
+		beginsAt = update.BeginsAt.UTC()
+		updates["begins_at"] = beginsAt
+	}
+	if update.EndsAt != nil {
+		endsAt = update.EndsAt.UTC()
+		updates["ends_at"] = endsAt
+	}
+	if update.VenueID != nil {
+		if *update.VenueID == 0 {
+			updates["venue_id"] = nil
+		} else {
+			updates["venue_id"] = *update.VenueID
+		}
+	}
+	if update.OnlineURL != nil {
+		if *update.OnlineURL == "" {
+			updates["online_url"] = nil
+		} else {
+			updates["online_url"] = *update.OnlineURL
+		}
+	}
+	if update.CategoryID != nil {
+		if *update.CategoryID == 0 {
+			updates["category_id"] = nil
+		} else {
+			updates["category_id"] = *update.CategoryID
+		}
+	}
+	timezone := current.Timezone
+	if update.Timezone != nil {
+		timezone = *update.Timezone
+		updates["timezone"] = timezone
+	}
+	if update.RecurrenceRule != nil {
+		if *update.RecurrenceRule == "" {
+			updates["recurrence_rule"] = nil
+		} else {
+			rule, err := validateRecurrence(*update.RecurrenceRule, timezone)
+			if err != nil {
+				return nil, err
+			}
+			updates["recurrence_rule"] = rule
+		}
+	}
+	if !endsAt.IsZero() && endsAt.Before(beginsAt) {
+		return nil, fmt.Errorf("%w: event can't end before it begins", ErrBusinessLogicViolation)
+	}
+	return updates, nil
+}
+
+// recurrenceChanged reports whether occurrences of the event must be synchronized after the update.
+func recurrenceChanged(old, new *model.Event) bool {
+	if !old.IsRecurring() && !new.IsRecurring() {
+		return false
+	}
+	return !old.IsRecurring() || !new.IsRecurring() || *old.RecurrenceRule != *new.RecurrenceRule ||
+		old.Timezone != new.Timezone || !old.BeginsAt.Equal(new.BeginsAt) || !old.EndsAt.Equal(new.EndsAt)
+}
+
+// DiffEvents returns material changes of the event, which registrants should know about.
+func DiffEvents(old, new *model.Event) []model.EventChange {
+	var changes []model.EventChange
+	if !old.BeginsAt.Equal(new.BeginsAt) {
+		changes = append(changes, model.EventChange{
+			Field: "begins_at",
+			Old:   formatEventTime(old.BeginsAt, eventLocation(old)),
+			New:   formatEventTime(new.BeginsAt, eventLocation(new)),
+		})
+	}
+	if !old.EndsAt.Equal(new.EndsAt) {
+		changes = append(changes, model.EventChange{
+			Field: "ends_at",
+			Old:   formatEventTime(old.EndsAt, eventLocation(old)),
+			New:   formatEventTime(new.EndsAt, eventLocation(new)),
+		})
+	}
+	if !equalPtr(old.VenueID, new.VenueID) {
+		changes = append(changes, model.EventChange{
+			Field: "venue_id",
+			Old:   formatEventVenue(old),
+			New:   formatEventVenue(new),
+		})
+	}
+	if !equalPtr(old.OnlineURL, new.OnlineURL) {
+		changes = append(changes, model.EventChange{
+			Field: "online_url",
+			Old:   formatOptional(old.OnlineURL),
+			New:   formatOptional(new.OnlineURL),
+		})
+	}
+	return changes
+}
+
+// diffEvents is DiffEvents with venues loaded, so registrants see where the event moved rather than ids of venues.
+func (u *EventUseCase) diffEvents(ctx context.Context, old, new *model.Event) ([]model.EventChange, error) {
+	if !equalPtr(old.VenueID, new.VenueID) {
+		events := []model.Event{*old, *new}
+		if err := u.loadVenues(ctx, events); err != nil {
+			return nil, err
+		}
+		old, new = &events[0], &events[1]
+	}
+	return DiffEvents(old, new), nil
+}
+
+func formatEventVenue(e *model.Event) string {
+	if e.VenueID == nil {
+		return "—"
+	}
+	if e.Venue == nil {
+		return fmt.Sprintf("#%d", *e.VenueID)
+	}
+	return e.Venue.Name + ", " + e.Venue.Address
+}
+
+func formatOptional(s *string) string {
+	if s == nil || *s == "" {
+		return "—"
+	}
+	return *s
+}
+
+func equalPtr[T comparable](a, b *T) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// formatEventTime formats the time in the timezone of the event, as registrants see it on the event page.
+func formatEventTime(t time.Time, loc *time.Location) string {
+	if t.IsZero() {
+		return "—"
+	}
+	return t.In(loc).Format(eventTimeLayout)
+}
+
+// localEvent returns a copy of the event with its time in the timezone of the event, so notices show local time.
+func localEvent(e *model.Event) *model.Event {
+	local := *e
+	loc := eventLocation(e)
+	local.BeginsAt = e.BeginsAt.In(loc)
+	if !e.EndsAt.IsZero() {
+		local.EndsAt = e.EndsAt.In(loc)
+	}
+	return &local
+}
@@ -0,0 +1,617 @@
+package usecases
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"github.com/burenotti/rtu-it-lab-recruit/model"
+	"github.com/burenotti/rtu-it-lab-recruit/pkg/ical"
+	"github.com/burenotti/rtu-it-lab-recruit/pkg/rrule"
+	"github.com/burenotti/rtu-it-lab-recruit/repositories"
+	"io"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	maxImportRows = 5000
+	// importStaleAfter is how long running import may not progress before it is claimed again.
+	importStaleAfter = 10 * time.Minute
+	// importProgressStep is how many rows are imported between updates of the progress.
+	importProgressStep = 20
+	importCancelReason = "The event is cancelled by the organizer"
+)
+
+// csvImportColumns are columns of imported CSV files, see model.EventImportQuery.
+var csvImportColumns = []string{
+	"uid", "name", "description", "begins_at", "ends_at", "timezone",
+	"online_url", "recurrence_rule", "tags", "status",
+}
+
+var csvRequiredColumns = []string{"uid", "name", "begins_at", "ends_at"}
+
+type EventImportStorage interface {
+	CreateImport(ctx context.Context, i *model.EventImport, data []byte) (*model.EventImport, error)
+	GetImport(ctx context.Context, importId int64) (*model.EventImport, error)
+	ClaimImport(ctx context.Context, staleBefore time.Time) (*model.EventImport, []byte, error)
+	UpdateImportProgress(ctx context.Context, importId int64, total, processed int) error
+	CompleteImport(ctx context.Context, importId int64, report *model.EventImportReport) error
+	FailImport(ctx context.Context, importId int64, reason string) error
+}
+
+// importedEvent is an event read from imported file along with the result of its import.
+type importedEvent struct {
+	row            model.ImportRow
+	name           string
+	description    string
+	beginsAt       time.Time
+	endsAt         time.Time
+	timezone       string
+	onlineURL      string
+	recurrenceRule string
+	tags           []string
+	cancelled      bool
+	// update is the update of the existing event with the same UID.
+	update *model.EventUpdate
+}
+
+func (e *importedEvent) invalid(format string, args ...interface{}) {
+	e.row.Action = model.ImportRowInvalid
+	e.row.Errors = append(e.row.Errors, fmt.Sprintf(format, args...))
+}
+
+func (e *importedEvent) warn(format string, args ...interface{}) {
+	e.row.Warnings = append(e.row.Warnings, fmt.Sprintf(format, args...))
+}
+
+// CheckImport validates the file and returns what importing it would do with each row, without changing anything.
+func (u *EventUseCase) CheckImport(
+	ctx context.Context,
+	userId, orgId int64,
+	query *model.EventImportQuery,
+	data []byte,
+) (*model.EventImportReport, error) {
+	if err := checkCanEditEvents(ctx, u.Members, orgId, userId, "import events"); err != nil {
+		return nil, err
+	}
+	events, err := readImport(query.Format, data, importLocation(query.Timezone))
+	if err != nil {
+		return nil, err
+	}
+	if err = u.planImport(ctx, orgId, events); err != nil {
+		return nil, err
+	}
+	return importReport(events), nil
+}
+
+// ImportEvents schedules import of the file. Malformed files are rejected right away, rows are validated
+// and imported in background. Events are matched by UIDs: new ones are created unpublished,
+// existing ones are updated, and events cancelled in the file are cancelled.
+func (u *EventUseCase) ImportEvents(
+	ctx context.Context,
+	userId, orgId int64,
+	query *model.EventImportQuery,
+	data []byte,
+) (*model.EventImport, error) {
+	if err := checkCanEditEvents(ctx, u.Members, orgId, userId, "import events"); err != nil {
+		return nil, err
+	}
+	timezone := query.Timezone
+	if timezone == "" {
+		timezone = "UTC"
+	}
+	if _, err := readImport(query.Format, data, importLocation(timezone)); err != nil {
+		return nil, err
+	}
+	return u.Imports.CreateImport(ctx, &model.EventImport{
+		OrganizationID: orgId,
+		UserID:         userId,
+		Format:         query.Format,
+		Timezone:       timezone,
+	}, data)
+}
+
+func (u *EventUseCase) GetEventImport(ctx context.Context, userId, orgId, importId int64) (*model.EventImport, error) {
+	if err := checkCanEditEvents(ctx, u.Members, orgId, userId, "import events"); err != nil {
+		return nil, err
+	}
+	i, err := u.Imports.GetImport(ctx, importId)
+	if err != nil {
+		return nil, err
+	}
+	if i.OrganizationID != orgId {
+		return nil, fmt.Errorf("%w: import with provided id does not exist", repositories.ErrEventImportNotFound)
+	}
+	return i, nil
+}
+
+// ProcessEventImports runs pending imports one by one. Each event is imported in its own transaction,
+// so the progress is visible while the import runs.
+func (u *EventUseCase) ProcessEventImports(ctx context.Context) error {
+	for ctx.Err() == nil {
+		i, data, err := u.Imports.ClaimImport(ctx, time.Now().UTC().Add(-importStaleAfter))
+		if errors.Is(err, repositories.ErrEventImportNotFound) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		report, err := u.runImport(ctx, i, data)
+		if ctx.Err() != nil {
+			// Interrupted import is claimed again when it becomes stale.
+			return ctx.Err()
+		} else if err != nil {
+			if err = u.Imports.FailImport(ctx, i.ImportID, err.Error()); err != nil {
+				return err
+			}
+			continue
+		}
+		if err = u.Imports.CompleteImport(ctx, i.ImportID, report); err != nil {
+			return err
+		}
+	}
+	return ctx.Err()
+}
+
+func (u *EventUseCase) runImport(ctx context.Context, i *model.EventImport, data []byte) (*model.EventImportReport, error) {
+	events, err := readImport(i.Format, data, importLocation(i.Timezone))
+	if err != nil {
+		return nil, err
+	}
+	if err = u.planImport(ctx, i.OrganizationID, events); err != nil {
+		return nil, err
+	}
+	if err = u.Imports.UpdateImportProgress(ctx, i.ImportID, len(events), 0); err != nil {
+		return nil, err
+	}
+	for n := range events {
+		e := &events[n]
+		switch e.row.Action {
+		case model.ImportRowCreate, model.ImportRowUpdate, model.ImportRowCancel:
+			err = u.Transactioner.Atomic(ctx, func(ctx context.Context) error {
+				return u.importEvent(ctx, i, e)
+			})
+			if errors.Is(err, ErrBusinessLogicViolation) || errors.Is(err, repositories.ErrExternalUIDTaken) {
+				e.invalid("%v", err)
+			} else if err != nil {
+				return nil, err
+			}
+		}
+		if (n+1)%importProgressStep == 0 {
+			if err = u.Imports.UpdateImportProgress(ctx, i.ImportID, len(events), n+1); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return importReport(events), nil
+}
+
+// importEvent applies the planned action to the event.
+func (u *EventUseCase) importEvent(ctx context.Context, i *model.EventImport, e *importedEvent) error {
+	switch e.row.Action {
+	case model.ImportRowCreate:
+		create := &model.EventCreate{
+			Name:           e.name,
+			OrganizationID: i.OrganizationID,
+			CreatorID:      i.UserID,
+			Description:    e.description,
+			BeginsAt:       e.beginsAt.UTC(),
+			EndsAt:         e.endsAt.UTC(),
+			Timezone:       e.timezone,
+			ExternalUID:    &e.row.UID,
+		}
+		if e.onlineURL != "" {
+			create.OnlineURL = &e.onlineURL
+		}
+		if e.recurrenceRule != "" {
+			create.RecurrenceRule = &e.recurrenceRule
+		}
+		event, err := u.Events.Create(ctx, create)
+		if err != nil {
+			return err
+		}
+		if len(e.tags) > 0 {
+			if err = u.Tags.SetTags(ctx, event.EventID, e.tags); err != nil {
+				return err
+			}
+		}
+		if event.IsRecurring() {
+			if err = u.syncOccurrences(ctx, event, 0); err != nil {
+				return err
+			}
+		}
+		e.row.EventID = &event.EventID
+		return nil
+	case model.ImportRowUpdate:
+		current, err := u.Events.GetById(ctx, *e.row.EventID)
+		if err != nil {
+			return err
+		}
+		_, err = u.updateEvent(ctx, current, e.update)
+		return err
+	case model.ImportRowCancel:
+		event, err := u.Events.GetById(ctx, *e.row.EventID)
+		if err != nil {
+			return err
+		}
+		registrants, err := u.Registrations.ListRegistrants(ctx, event.EventID)
+		if err != nil {
+			return err
+		}
+		return u.cancelEvent(ctx, event, registrants, importCancelReason)
+	}
+	return nil
+}
+
+// planImport decides what to do with each valid event: events with new UIDs are created,
+// events with UIDs of existing events update or cancel them.
+func (u *EventUseCase) planImport(ctx context.Context, orgId int64, events []importedEvent) error {
+	var uids []string
+	for i := range events {
+		if events[i].row.Action == "" {
+			uids = append(uids, events[i].row.UID)
+		}
+	}
+	existing := map[string]*model.Event{}
+	tags := map[int64][]string{}
+	if len(uids) > 0 {
+		found, err := u.Events.SelectBy(ctx, repositories.NewEventExternalUIDFilter(orgId, uids))
+		if err != nil {
+			return err
+		}
+		ids := make([]int64, 0, len(found))
+		for i := range found {
+			existing[*found[i].ExternalUID] = &found[i]
+			ids = append(ids, found[i].EventID)
+		}
+		if len(ids) > 0 {
+			if tags, err = u.Tags.ListTags(ctx, ids); err != nil {
+				return err
+			}
+		}
+	}
+
+	for i := range events {
+		e := &events[i]
+		if e.row.Action != "" {
+			continue
+		}
+		current, ok := existing[e.row.UID]
+		switch {
+		case !ok && e.cancelled:
+			e.row.Action = model.ImportRowSkip
+			e.warn("cancelled event is not created")
+		case !ok:
+			e.row.Action = model.ImportRowCreate
+		case current.IsCancelled():
+			e.row.EventID = &current.EventID
+			if e.cancelled {
+				e.row.Action = model.ImportRowUnchanged
+			} else {
+				e.invalid("event %d is cancelled, it can't be changed", current.EventID)
+			}
+		case e.cancelled:
+			e.row.EventID = &current.EventID
+			e.row.Action = model.ImportRowCancel
+		default:
+			e.row.EventID = &current.EventID
+			e.update = importUpdate(current, tags[current.EventID], e)
+			e.row.Action = model.ImportRowUpdate
+			if e.update == nil {
+				e.row.Action = model.ImportRowUnchanged
+			}
+		}
+	}
+	return nil
+}
+
+// importUpdate returns changes of the event made by the import, or nil if there are none.
+func importUpdate(current *model.Event, currentTags []string, e *importedEvent) *model.EventUpdate {
+	update := &model.EventUpdate{}
+	changed := false
+	if current.Name != e.name {
+		update.Name, changed = &e.name, true
+	}
+	if current.Description != e.description {
+		update.Description, changed = &e.description, true
+	}
+	if !current.BeginsAt.Equal(e.beginsAt) {
+		update.BeginsAt, changed = &e.beginsAt, true
+	}
+	if !current.EndsAt.Equal(e.endsAt) {
+		update.EndsAt, changed = &e.endsAt, true
+	}
+	if current.Timezone != e.timezone {
+		update.Timezone, changed = &e.timezone, true
+	}
+	if stringOrEmpty(current.OnlineURL) != e.onlineURL {
+		update.OnlineURL, changed = &e.onlineURL, true
+	}
+	if stringOrEmpty(current.RecurrenceRule) != e.recurrenceRule {
+		update.RecurrenceRule, changed = &e.recurrenceRule, true
+	}
+	if !sameStrings(currentTags, e.tags) {
+		update.Tags, changed = e.tags, true
+		if update.Tags == nil {
+			update.Tags = []string{}
+		}
+	}
+	if !changed {
+		return nil
+	}
+	return update
+}
+
+func importReport(events []importedEvent) *model.EventImportReport {
+	report := &model.EventImportReport{Total: len(events), Rows: make([]model.ImportRow, 0, len(events))}
+	for i := range events {
+		row := events[i].row
+		switch row.Action {
+		case model.ImportRowCreate:
+			report.Created++
+		case model.ImportRowUpdate:
+			report.Updated++
+		case model.ImportRowUnchanged:
+			report.Unchanged++
+		case model.ImportRowCancel:
+			report.Cancelled++
+		case model.ImportRowSkip:
+			report.Skipped++
+		case model.ImportRowInvalid:
+			report.Invalid++
+		}
+		report.Rows = append(report.Rows, row)
+	}
+	return report
+}
+
+// readImport reads events of the file and validates them. Invalid events have the invalid action set,
+// events which are not imported have the skip action set, others have no action yet.
+func readImport(format string, data []byte, loc *time.Location) ([]importedEvent, error) {
+	var events []importedEvent
+	var err error
+	switch format {
+	case model.EventImportICS:
+		events, err = readICSImport(data, loc)
+	case model.EventImportCSV:
+		events, err = readCSVImport(data, loc)
+	default:
+		return nil, fmt.Errorf("%w: unknown format %q", ErrBusinessLogicViolation, format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(events) > maxImportRows {
+		return nil, fmt.Errorf("%w: file can't have more than %d events", ErrBusinessLogicViolation, maxImportRows)
+	}
+
+	lines := map[string]int{}
+	for i := range events {
+		e := &events[i]
+		if e.row.Action == model.ImportRowSkip || e.row.UID == "" {
+			continue
+		}
+		if line, ok := lines[e.row.UID]; ok {
+			e.invalid("uid is duplicated, it is used at line %d", line)
+		} else {
+			lines[e.row.UID] = e.row.Line
+		}
+	}
+	return events, nil
+}
+
+func readICSImport(data []byte, loc *time.Location) ([]importedEvent, error) {
+	decoded, err := ical.Decode(bytes.NewReader(data), loc)
+	if errors.Is(err, ical.ErrInvalidCalendar) {
+		return nil, fmt.Errorf("%w: %v", ErrBusinessLogicViolation, err)
+	} else if err != nil {
+		return nil, err
+	}
+	events := make([]importedEvent, 0, len(decoded))
+	for i := range decoded {
+		d := &decoded[i]
+		e := importedEvent{
+			row:            model.ImportRow{Line: d.Line, UID: d.UID},
+			name:           d.Summary,
+			description:    d.Description,
+			beginsAt:       d.Start,
+			endsAt:         d.End,
+			timezone:       d.Start.Location().String(),
+			onlineURL:      d.URL,
+			recurrenceRule: d.RRule,
+			tags:           d.Categories,
+			cancelled:      d.Status == ical.StatusCancelled,
+		}
+		switch {
+		case d.Err != nil:
+			e.invalid("%v", d.Err)
+		case !d.RecurrenceID.IsZero():
+			e.row.Action = model.ImportRowSkip
+			e.warn("changed occurrences of recurring events are not imported")
+		default:
+			validateImportedEvent(&e)
+		}
+		if len(d.ExDates) > 0 {
+			e.warn("excluded dates of recurring events are not imported, cancel the occurrences manually")
+		}
+		if d.Location != "" {
+			e.warn("location is not imported, link a venue to the event manually")
+		}
+		events = append(events, e)
+	}
+	return events, nil
+}
+
+func readCSVImport(data []byte, loc *time.Location) ([]importedEvent, error) {
+	reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\ufeff"))))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%w: file is empty", ErrBusinessLogicViolation)
+	} else if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrBusinessLogicViolation, err)
+	}
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range csvRequiredColumns {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("%w: column %q is missing, required columns are %s",
+				ErrBusinessLogicViolation, name, strings.Join(csvRequiredColumns, ", "))
+		}
+	}
+
+	var events []importedEvent
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrBusinessLogicViolation, err)
+		}
+		line, _ := reader.FieldPos(0)
+		values := map[string]string{}
+		for _, name := range csvImportColumns {
+			if i, ok := columns[name]; ok && i < len(record) {
+				values[name] = strings.TrimSpace(record[i])
+			}
+		}
+		events = append(events, csvImportedEvent(line, values, loc))
+	}
+	return events, nil
+}
+
+func csvImportedEvent(line int, values map[string]string, loc *time.Location) importedEvent {
+	e := importedEvent{
+		row:            model.ImportRow{Line: line, UID: values["uid"]},
+		name:           values["name"],
+		description:    values["description"],
+		timezone:       loc.String(),
+		onlineURL:      values["online_url"],
+		recurrenceRule: values["recurrence_rule"],
+	}
+	if values["timezone"] != "" {
+		e.timezone = values["timezone"]
+		if tz, err := time.LoadLocation(e.timezone); err == nil {
+			loc = tz
+		}
+	}
+	var err error
+	if e.beginsAt, err = parseImportTime(values["begins_at"], loc); err != nil {
+		e.invalid("begins_at: %v", err)
+	}
+	if e.endsAt, err = parseImportTime(values["ends_at"], loc); err != nil {
+		e.invalid("ends_at: %v", err)
+	}
+	if values["tags"] != "" {
+		e.tags = strings.Split(values["tags"], ",")
+	}
+	switch strings.ToLower(values["status"]) {
+	case "", "confirmed":
+	case "cancelled", "canceled":
+		e.cancelled = true
+	default:
+		e.invalid("status must be either confirmed or cancelled")
+	}
+	validateImportedEvent(&e)
+	return e
+}
+
+// parseImportTime parses time in RFC 3339 or local time in loc.
+func parseImportTime(value string, loc *time.Location) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, errors.New("value is required")
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	for _, layout := range []string{"2006-01-02 15:04", "2006-01-02T15:04", "2006-01-02 15:04:05"} {
+		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("time %q must be in RFC 3339 or 2006-01-02 15:04 format", value)
+}
+
+// validateImportedEvent checks the event meets the same constraints as events changed with the API.
+// The rule and tags are normalized.
+func validateImportedEvent(e *importedEvent) {
+	if e.row.UID == "" {
+		e.invalid("uid is required")
+	} else if len(e.row.UID) > 255 {
+		e.invalid("uid can't be longer than 255 characters")
+	}
+	if n := utf8.RuneCountInString(e.name); n < 3 || n > 256 {
+		e.invalid("name must be from 3 to 256 characters long")
+	}
+	if utf8.RuneCountInString(e.description) > 4096 {
+		e.invalid("description can't be longer than 4096 characters")
+	}
+	if !e.beginsAt.IsZero() && e.endsAt.Before(e.beginsAt) {
+		e.invalid("event can't end before it begins")
+	}
+	if _, err := time.LoadLocation(e.timezone); err != nil {
+		e.invalid("unknown timezone %q", e.timezone)
+	}
+	if e.onlineURL != "" {
+		if u, err := url.Parse(e.onlineURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") ||
+			u.Host == "" || len(e.onlineURL) > 2048 {
+			e.invalid("online url must be http or https url up to 2048 characters long")
+		}
+	}
+	if e.recurrenceRule != "" {
+		if rule, err := rrule.Parse(e.recurrenceRule); err != nil {
+			e.invalid("%v", err)
+		} else {
+			e.recurrenceRule = rule.String()
+		}
+	}
+	e.tags = normalizeTags(e.tags)
+	if len(e.tags) > 10 {
+		e.invalid("event can't have more than 10 tags")
+	}
+	for _, tag := range e.tags {
+		if utf8.RuneCountInString(tag) > 32 {
+			e.invalid("tag %q is longer than 32 characters", tag)
+		}
+	}
+}
+
+// importLocation returns the location of the import, unknown timezones are validated before.
+func importLocation(timezone string) *time.Location {
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+func stringOrEmpty(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// sameStrings reports whether the lists have the same strings regardless of the order.
+func sameStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	a, b = append([]string(nil), a...), append([]string(nil), b...)
+	sort.Strings(a)
+	sort.Strings(b)
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,141 @@
+package usecases
+
+import (
+	"context"
+	"github.com/burenotti/rtu-it-lab-recruit/model"
+	"github.com/burenotti/rtu-it-lab-recruit/repositories"
+	"github.com/burenotti/rtu-it-lab-recruit/usecases/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEventUseCase_CheckImport_CSV(t *testing.T) {
+	ctx := context.Background()
+	events := mocks.NewManagedEventStorage(t)
+	members := mocks.NewEventMemberStorage(t)
+	tags := mocks.NewEventTagStorage(t)
+	u := &EventUseCase{Events: events, Members: members, Tags: tags}
+	editor(members, 2, 10)
+	uid := "existing"
+	existing := model.Event{
+		EventID: 5, OrganizationID: 2, Name: "Lecture", Timezone: "Europe/Moscow", ExternalUID: &uid,
+		BeginsAt: time.Date(2030, 5, 10, 16, 0, 0, 0, time.UTC), EndsAt: time.Date(2030, 5, 10, 17, 0, 0, 0, time.UTC),
+	}
+	events.On("SelectBy", mock.Anything, mock.Anything).Return([]model.Event{existing}, nil)
+	tags.On("ListTags", mock.Anything, []int64{5}).Return(map[int64][]string{5: {"free"}}, nil)
+
+	data := strings.Join([]string{
+		"name,uid,begins_at,ends_at,tags,extra",
+		"Lecture,existing,2030-05-10 19:00,2030-05-10 20:00,Free,ignored",
+		`"Concert, live",new,2030-06-01T18:00:00Z,2030-06-01T20:00:00Z,"music,live",`,
+		"Concert,new,2030-06-01T18:00:00Z,2030-06-01T20:00:00Z,,",
+		"No,bad,tomorrow,2030-06-01T20:00:00Z,,",
+	}, "\n")
+	query := &model.EventImportQuery{Format: model.EventImportCSV, Timezone: "Europe/Moscow", DryRun: true}
+	report, err := u.CheckImport(ctx, 10, 2, query, []byte(data))
+	require.NoError(t, err)
+
+	assert.Equal(t, 4, report.Total)
+	assert.Equal(t, 1, report.Unchanged)
+	assert.Equal(t, 1, report.Created)
+	assert.Equal(t, 2, report.Invalid)
+	rows := report.Rows
+	assert.Equal(t, model.ImportRowUnchanged, rows[0].Action)
+	assert.Equal(t, int64(5), *rows[0].EventID)
+	assert.Equal(t, model.ImportRowCreate, rows[1].Action)
+	assert.Equal(t, 3, rows[1].Line)
+	assert.Equal(t, []string{"uid is duplicated, it is used at line 3"}, rows[2].Errors)
+	assert.Len(t, rows[3].Errors, 2)
+}
+
+func TestEventUseCase_ImportEvents_Malformed(t *testing.T) {
+	ctx := context.Background()
+	members := mocks.NewEventMemberStorage(t)
+	u := &EventUseCase{Members: members}
+	editor(members, 2, 10)
+
+	query := &model.EventImportQuery{Format: model.EventImportCSV}
+	_, err := u.ImportEvents(ctx, 10, 2, query, []byte("name,begins_at\nLecture,2030-05-10 19:00\n"))
+	assert.ErrorIs(t, err, ErrBusinessLogicViolation)
+
+	query = &model.EventImportQuery{Format: model.EventImportICS}
+	_, err = u.ImportEvents(ctx, 10, 2, query, []byte("BEGIN:VEVENT\r\nEND:VEVENT\r\n"))
+	assert.ErrorIs(t, err, ErrBusinessLogicViolation)
+}
+
+func TestEventUseCase_ProcessEventImports(t *testing.T) {
+	ctx := context.Background()
+	events := mocks.NewManagedEventStorage(t)
+	tags := mocks.NewEventTagStorage(t)
+	registrations := mocks.NewRegistrationStorage(t)
+	reminders := mocks.NewReminderPlanner(t)
+	imports := mocks.NewEventImportStorage(t)
+	u := &EventUseCase{
+		Transactioner: newTestTransactioner(t),
+		Events:        events,
+		Tags:          tags,
+		Registrations: registrations,
+		Reminders:     reminders,
+		Imports:       imports,
+	}
+	uid := "cancelled@example.com"
+	now := time.Now()
+	existing := model.Event{
+		EventID: 5, OrganizationID: 2, Name: "Lecture", Timezone: "UTC", ExternalUID: &uid, PublishedAt: &now,
+		BeginsAt: time.Date(2030, 5, 10, 16, 0, 0, 0, time.UTC), EndsAt: time.Date(2030, 5, 10, 17, 0, 0, 0, time.UTC),
+	}
+	data := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"BEGIN:VEVENT",
+		"UID:new@example.com",
+		"SUMMARY:Concert",
+		"DTSTART;TZID=Europe/Berlin:20300601T180000",
+		"DTEND;TZID=Europe/Berlin:20300601T200000",
+		"CATEGORIES:Music",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:cancelled@example.com",
+		"SUMMARY:Lecture",
+		"DTSTART:20300510T160000Z",
+		"DTEND:20300510T170000Z",
+		"STATUS:CANCELLED",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\r\n")
+	i := &model.EventImport{ImportID: 1, OrganizationID: 2, UserID: 10, Format: model.EventImportICS, Timezone: "UTC"}
+
+	imports.On("ClaimImport", mock.Anything, mock.Anything).Return(i, []byte(data), nil).Once()
+	imports.On("ClaimImport", mock.Anything, mock.Anything).Return(nil, nil, repositories.ErrEventImportNotFound).Once()
+	events.On("SelectBy", mock.Anything, mock.Anything).Return([]model.Event{existing}, nil)
+	tags.On("ListTags", mock.Anything, []int64{5}).Return(map[int64][]string{}, nil)
+	imports.On("UpdateImportProgress", mock.Anything, int64(1), 2, 0).Return(nil).Once()
+	events.On("Create", mock.Anything, mock.Anything).Return(func(_ context.Context, create *model.EventCreate) *model.Event {
+		return &model.Event{
+			EventID: 6, OrganizationID: create.OrganizationID, Name: create.Name,
+			BeginsAt: create.BeginsAt, EndsAt: create.EndsAt, Timezone: create.Timezone, ExternalUID: create.ExternalUID,
+		}
+	}, nil).Once()
+	tags.On("SetTags", mock.Anything, int64(6), []string{"music"}).Return(nil).Once()
+	events.On("GetById", mock.Anything, int64(5)).Return(&existing, nil)
+	registrations.On("ListRegistrants", mock.Anything, int64(5)).Return([]model.User{}, nil)
+	events.On("Cancel", mock.Anything, int64(5), importCancelReason).Return(nil).Once()
+	reminders.On("ReplanEvent", mock.Anything, int64(5)).Return(nil).Once()
+	imports.On("CompleteImport", mock.Anything, int64(1), mock.Anything).Return(nil).Once()
+
+	require.NoError(t, u.ProcessEventImports(ctx))
+
+	create := events.Calls[1].Arguments.Get(1).(*model.EventCreate)
+	assert.Equal(t, int64(10), create.CreatorID)
+	assert.Equal(t, "Europe/Berlin", create.Timezone)
+	assert.Equal(t, time.Date(2030, 6, 1, 16, 0, 0, 0, time.UTC), create.BeginsAt)
+	assert.Equal(t, "new@example.com", *create.ExternalUID)
+
+	report := imports.Calls[2].Arguments.Get(2).(*model.EventImportReport)
+	assert.Equal(t, 1, report.Created)
+	assert.Equal(t, 1, report.Cancelled)
+	assert.Equal(t, int64(6), *report.Rows[0].EventID)
+}
@@ -0,0 +1,436 @@
+package usecases
+
+import (
+	"context"
+	"github.com/burenotti/rtu-it-lab-recruit/model"
+	"github.com/burenotti/rtu-it-lab-recruit/repositories"
+	"github.com/burenotti/rtu-it-lab-recruit/usecases/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func editor(members *mocks.EventMemberStorage, orgId, userId int64) {
+	members.On("GetMember", mock.Anything, orgId, userId).
+		Return(&model.OrganizationMember{UserID: userId, Can: model.MemberRights{EditEvents: true}}, nil)
+}
+
+func TestEventUseCase_UpdateEvent_ReplansReminders(t *testing.T) {
+	ctx := context.Background()
+	events := mocks.NewManagedEventStorage(t)
+	members := mocks.NewEventMemberStorage(t)
+	registrations := mocks.NewRegistrationStorage(t)
+	reminders := mocks.NewReminderPlanner(t)
+	notifier := mocks.NewUserNotifier(t)
+	u := &EventUseCase{
+		Transactioner: newTestTransactioner(t),
+		Events:        events,
+		Members:       members,
+		Registrations: registrations,
+		Reminders:     reminders,
+		Notifier:      notifier,
+	}
+	beginsAt := time.Now().Add(48 * time.Hour).UTC().Truncate(time.Second)
+	current := &model.Event{EventID: 1, OrganizationID: 2, BeginsAt: beginsAt}
+	newBeginsAt := beginsAt.Add(time.Hour)
+	updated := &model.Event{EventID: 1, OrganizationID: 2, BeginsAt: newBeginsAt}
+	registrant := model.User{UserID: 4}
+
+	events.On("GetById", mock.Anything, current.EventID).Return(current, nil)
+	editor(members, current.OrganizationID, 3)
+	events.On("UpdateEvent", mock.Anything, current.EventID, map[string]interface{}{"begins_at": newBeginsAt}).
+		Return(updated, nil)
+	reminders.On("ReplanEvent", mock.Anything, current.EventID).Return(nil).Once()
+	registrations.On("ListRegistrants", mock.Anything, current.EventID).Return([]model.User{registrant}, nil)
+	notifier.On("SendToAll", mock.Anything, &registrant, model.MessageEventChanged,
+		mock.MatchedBy(func(data eventChangedContext) bool {
+			return len(data.Changes) == 1 && data.Changes[0].Field == "begins_at"
+		})).Return(nil).Once()
+
+	event, err := u.UpdateEvent(ctx, 3, current.EventID, &model.EventUpdate{BeginsAt: &newBeginsAt})
+	require.NoError(t, err)
+	assert.Equal(t, updated, event)
+}
+
+func TestEventUseCase_UpdateEvent_KeepsReminders(t *testing.T) {
+	ctx := context.Background()
+	events := mocks.NewManagedEventStorage(t)
+	members := mocks.NewEventMemberStorage(t)
+	u := &EventUseCase{Transactioner: newTestTransactioner(t), Events: events, Members: members}
+	current := &model.Event{EventID: 1, OrganizationID: 2, Name: "Lecture", BeginsAt: time.Now().Add(time.Hour)}
+	name := "Open lecture"
+	updated := *current
+	updated.Name = name
+
+	events.On("GetById", mock.Anything, current.EventID).Return(current, nil)
+	members.On("GetMember", mock.Anything, current.OrganizationID, int64(3)).
+		Return(&model.OrganizationMember{UserID: 3, IsOwner: true}, nil)
+	events.On("UpdateEvent", mock.Anything, current.EventID, map[string]interface{}{"name": name}).
+		Return(&updated, nil)
+
+	_, err := u.UpdateEvent(ctx, 3, current.EventID, &model.EventUpdate{Name: &name})
+	assert.NoError(t, err, "reminders should not be replanned and registrants notified if time is not changed")
+}
+
+func TestEventUseCase_UpdateEvent_VenueChanged(t *testing.T) {
+	ctx := context.Background()
+	events := mocks.NewManagedEventStorage(t)
+	members := mocks.NewEventMemberStorage(t)
+	venues := mocks.NewEventVenueStorage(t)
+	registrations := mocks.NewRegistrationStorage(t)
+	notifier := mocks.NewUserNotifier(t)
+	u := &EventUseCase{
+		Transactioner: newTestTransactioner(t),
+		Events:        events,
+		Members:       members,
+		Venues:        venues,
+		Registrations: registrations,
+		Notifier:      notifier,
+	}
+	oldVenueId, newVenueId := int64(5), int64(6)
+	current := &model.Event{EventID: 1, OrganizationID: 2, VenueID: &oldVenueId, BeginsAt: time.Now().Add(time.Hour)}
+	updated := *current
+	updated.VenueID = &newVenueId
+	registrant := model.User{UserID: 4}
+
+	events.On("GetById", mock.Anything, current.EventID).Return(current, nil)
+	editor(members, current.OrganizationID, 3)
+	venues.On("GetVenue", mock.Anything, newVenueId).
+		Return(&model.Venue{VenueID: newVenueId, OrganizationID: current.OrganizationID}, nil)
+	events.On("UpdateEvent", mock.Anything, current.EventID, map[string]interface{}{"venue_id": newVenueId}).
+		Return(&updated, nil)
+	venues.On("ListVenuesByIds", mock.Anything, []int64{oldVenueId, newVenueId}).Return([]model.Venue{
+		{VenueID: oldVenueId, Name: "Small hall", Address: "Moscow, Tverskaya 1"},
+		{VenueID: newVenueId, Name: "Concert hall", Address: "Moscow, Vernadskogo 78"},
+	}, nil)
+	registrations.On("ListRegistrants", mock.Anything, current.EventID).Return([]model.User{registrant}, nil)
+	notifier.On("SendToAll", mock.Anything, &registrant, model.MessageEventChanged, eventChangedContext{
+		User:  &registrant,
+		Event: localEvent(&updated),
+		Changes: []model.EventChange{{
+			Field: "venue_id",
+			Old:   "Small hall, Moscow, Tverskaya 1",
+			New:   "Concert hall, Moscow, Vernadskogo 78",
+		}},
+	}).Return(nil).Once()
+
+	event, err := u.UpdateEvent(ctx, 3, current.EventID, &model.EventUpdate{VenueID: &newVenueId})
+	require.NoError(t, err)
+	assert.Nil(t, event.Venue, "venues are loaded only to describe the change")
+}
+
+func TestEventUseCase_UpdateEvent_NoRights(t *testing.T) {
+	ctx := context.Background()
+	events := mocks.NewManagedEventStorage(t)
+	members := mocks.NewEventMemberStorage(t)
+	u := &EventUseCase{Transactioner: newTestTransactioner(t), Events: events, Members: members}
+	current := &model.Event{EventID: 1, OrganizationID: 2}
+
+	events.On("GetById", mock.Anything, current.EventID).Return(current, nil)
+	members.On("GetMember", mock.Anything, current.OrganizationID, int64(3)).
+		Return(&model.OrganizationMember{UserID: 3}, nil)
+
+	_, err := u.UpdateEvent(ctx, 3, current.EventID, &model.EventUpdate{})
+	assert.ErrorIs(t, err, ErrBusinessLogicViolation)
+}
+
+func TestEventUseCase_DeleteEvent_WithRegistrants(t *testing.T) {
+	ctx := context.Background()
+	events := mocks.NewManagedEventStorage(t)
+	members := mocks.NewEventMemberStorage(t)
+	registrations := mocks.NewRegistrationStorage(t)
+	reminders := mocks.NewReminderPlanner(t)
+	notifier := mocks.NewUserNotifier(t)
+	u := &EventUseCase{
+		Transactioner: newTestTransactioner(t),
+		Events:        events,
+		Members:       members,
+		Registrations: registrations,
+		Reminders:     reminders,
+		Notifier:      notifier,
+	}
+	event := &model.Event{EventID: 1, OrganizationID: 2, BeginsAt: time.Now().Add(time.Hour)}
+	registrants := []model.User{{UserID: 4}, {UserID: 5}}
+
+	events.On("GetById", mock.Anything, event.EventID).Return(event, nil)
+	editor(members, event.OrganizationID, 3)
+	registrations.On("ListRegistrants", mock.Anything, event.EventID).Return(registrants, nil)
+	events.On("Cancel", mock.Anything, event.EventID, "Lecturer is ill").Return(nil).Once()
+	reminders.On("ReplanEvent", mock.Anything, event.EventID).Return(nil).Once()
+	notifier.On("SendToAll", mock.Anything, mock.Anything, model.MessageEventCancelled, mock.Anything).Return(nil).Twice()
+
+	cancelled, err := u.DeleteEvent(ctx, 3, event.EventID, &model.EventCancel{Reason: "Lecturer is ill"})
+	require.NoError(t, err)
+	assert.True(t, cancelled, "event with registrants should be cancelled instead of deletion")
+}
+
+func TestEventUseCase_DeleteEvent_WithoutRegistrants(t *testing.T) {
+	ctx := context.Background()
+	events := mocks.NewManagedEventStorage(t)
+	members := mocks.NewEventMemberStorage(t)
+	registrations := mocks.NewRegistrationStorage(t)
+	u := &EventUseCase{
+		Transactioner: newTestTransactioner(t),
+		Events:        events,
+		Members:       members,
+		Registrations: registrations,
+	}
+	event := &model.Event{EventID: 1, OrganizationID: 2}
+
+	events.On("GetById", mock.Anything, event.EventID).Return(event, nil)
+	editor(members, event.OrganizationID, 3)
+	registrations.On("ListRegistrants", mock.Anything, event.EventID).Return(nil, nil)
+	events.On("DeleteEvent", mock.Anything, event.EventID).Return(nil).Once()
+
+	cancelled, err := u.DeleteEvent(ctx, 3, event.EventID, &model.EventCancel{})
+	require.NoError(t, err)
+	assert.False(t, cancelled)
+}
+
+func TestDiffEvents(t *testing.T) {
+	beginsAt := time.Date(2023, 5, 1, 10, 0, 0, 0, time.UTC)
+	venueId, onlineURL := int64(5), "https://meet.example.com/lecture"
+	old := &model.Event{Name: "Lecture", BeginsAt: beginsAt, Timezone: "UTC"}
+	new := &model.Event{
+		Name:      "Open lecture",
+		Timezone:  "Europe/Moscow",
+		BeginsAt:  beginsAt.Add(time.Hour),
+		EndsAt:    beginsAt.Add(2 * time.Hour),
+		VenueID:   &venueId,
+		Venue:     &model.Venue{VenueID: venueId, Name: "Concert hall", Address: "Moscow, Vernadskogo 78"},
+		OnlineURL: &onlineURL,
+	}
+
+	assert.Equal(t, []model.EventChange{
+		{Field: "begins_at", Old: "01.05.2023 10:00 UTC", New: "01.05.2023 14:00 MSK"},
+		{Field: "ends_at", Old: "—", New: "01.05.2023 15:00 MSK"},
+		{Field: "venue_id", Old: "—", New: "Concert hall, Moscow, Vernadskogo 78"},
+		{Field: "online_url", Old: "—", New: onlineURL},
+	}, DiffEvents(old, new), "only time and location changes are material")
+	assert.Empty(t, DiffEvents(old, old))
+}
+
+func TestLocalEvent(t *testing.T) {
+	beginsAt := time.Date(2023, 5, 1, 10, 0, 0, 0, time.UTC)
+	event := &model.Event{Name: "Lecture", BeginsAt: beginsAt, Timezone: "Europe/Moscow"}
+
+	local := localEvent(event)
+	assert.Equal(t, "01.05.2023 13:00 MSK", local.BeginsAt.Format(eventTimeLayout), "notices should show local time")
+	assert.True(t, local.EndsAt.IsZero())
+	assert.Equal(t, time.UTC, event.BeginsAt.Location(), "event itself should not change")
+}
+
+func TestCheckRegistrationOpen(t *testing.T) {
+	now := time.Now()
+	past, future := now.Add(-time.Hour), now.Add(time.Hour)
+
+	assert.NoError(t, checkRegistrationOpen(&model.Event{BeginsAt: future}, now))
+	assert.NoError(t, checkRegistrationOpen(&model.Event{BeginsAt: future, RegistrationBegin: &past, RegistrationEnd: &future}, now))
+	assert.Error(t, checkRegistrationOpen(&model.Event{BeginsAt: past}, now), "event has begun")
+	assert.Error(t, checkRegistrationOpen(&model.Event{BeginsAt: future, RegistrationBegin: &future}, now), "registration not started")
+	assert.Error(t, checkRegistrationOpen(&model.Event{BeginsAt: future, RegistrationEnd: &past}, now), "registration is over")
+}
+
+func TestEventUseCase_PublishEvent(t *testing.T) {
+	ctx := context.Background()
+	events := mocks.NewManagedEventStorage(t)
+	members := mocks.NewEventMemberStorage(t)
+	reminders := mocks.NewReminderPlanner(t)
+	fanouts := mocks.NewFanoutScheduler(t)
+	searchAlerts := mocks.NewSearchAlertScheduler(t)
+	webhooks := mocks.NewWebhookEmitter(t)
+	u := &EventUseCase{
+		Transactioner: newTestTransactioner(t),
+		Events:        events,
+		Members:       members,
+		Reminders:     reminders,
+		Fanouts:       fanouts,
+		SearchAlerts:  searchAlerts,
+		Webhooks:      webhooks,
+	}
+	publishedAt := time.Now().UTC()
+	draft := &model.Event{EventID: 1, OrganizationID: 2}
+	published := &model.Event{EventID: 1, OrganizationID: 2, PublishedAt: &publishedAt}
+
+	events.On("GetById", mock.Anything, draft.EventID).Return(draft, nil).Once()
+	events.On("GetById", mock.Anything, draft.EventID).Return(published, nil).Once()
+	editor(members, draft.OrganizationID, 3)
+	events.On("Publish", mock.Anything, draft.EventID).Return(nil).Once()
+	reminders.On("ReplanEvent", mock.Anything, draft.EventID).Return(nil).Once()
+	fanouts.On("ScheduleFanout", mock.Anything, draft.EventID).Return(nil).Once()
+	searchAlerts.On("ScheduleSearchAlerts", mock.Anything, draft.EventID).Return(nil).Once()
+	webhooks.On("Emit", mock.Anything, draft.OrganizationID, model.WebhookEventPublished, published).Return(nil).Once()
+
+	event, err := u.PublishEvent(ctx, 3, draft.EventID)
+	require.NoError(t, err)
+	assert.Equal(t, published, event)
+
+	events.On("GetById", mock.Anything, draft.EventID).Return(published, nil).Once()
+	_, err = u.PublishEvent(ctx, 3, draft.EventID)
+	assert.ErrorIs(t, err, ErrBusinessLogicViolation, "event should not be published twice")
+}
+
+func TestEventUseCase_RegisterForEvent(t *testing.T) {
+	ctx := context.Background()
+	events := mocks.NewManagedEventStorage(t)
+	registrations := mocks.NewRegistrationStorage(t)
+	users := mocks.NewChannelUserStorage(t)
+	notifier := mocks.NewUserNotifier(t)
+	webhooks := mocks.NewWebhookEmitter(t)
+	tickets := mocks.NewEventTicketStorage(t)
+	u := &EventUseCase{
+		Transactioner: newTestTransactioner(t),
+		Events:        events,
+		Registrations: registrations,
+		Users:         users,
+		Notifier:      notifier,
+		Webhooks:      webhooks,
+		Tickets:       tickets,
+	}
+	publishedAt := time.Now().UTC()
+	event := &model.Event{EventID: 1, OrganizationID: 2, BeginsAt: publishedAt.Add(24 * time.Hour), PublishedAt: &publishedAt}
+	user := &model.User{UserID: 3, FirstName: "John", LastName: "Doe"}
+	expected := &model.Registration{EventID: event.EventID, UserID: user.UserID}
+	source := "telegram"
+
+	events.On("GetById", mock.Anything, event.EventID).Return(event, nil).Once()
+	tickets.On("ListTicketTypes", mock.Anything, event.EventID).Return([]model.TicketType{}, nil).Once()
+	registrations.On("Register", mock.Anything, event.EventID, user.UserID, (*int64)(nil), &source).Return(expected, nil).Once()
+	users.On("GetById", mock.Anything, user.UserID).Return(user, nil).Once()
+	webhooks.On("Emit", mock.Anything, event.OrganizationID, model.WebhookRegistrationCreated, model.WebhookRegistrationData{
+		Registration: expected,
+		FirstName:    "John",
+		LastName:     "Doe",
+	}).Return(nil).Once()
+	notifier.On("SendToAll", mock.Anything, user, model.MessageRegistrationConfirmed, registrationConfirmedContext{
+		User:  user,
+		Event: event,
+	}).Return(nil).Once()
+
+	registration, err := u.RegisterForEvent(ctx, user.UserID, event.EventID, &model.RegistrationTarget{Source: " Telegram"})
+	require.NoError(t, err)
+	assert.Equal(t, expected, registration)
+}
+
+func TestEventUseCase_UpdateEvent_EmitsWebhookForPublished(t *testing.T) {
+	ctx := context.Background()
+	events := mocks.NewManagedEventStorage(t)
+	members := mocks.NewEventMemberStorage(t)
+	webhooks := mocks.NewWebhookEmitter(t)
+	u := &EventUseCase{Transactioner: newTestTransactioner(t), Events: events, Members: members, Webhooks: webhooks}
+	publishedAt := time.Now().UTC()
+	current := &model.Event{EventID: 1, OrganizationID: 2, Name: "Lecture", PublishedAt: &publishedAt}
+	name := "Open lecture"
+	updated := *current
+	updated.Name = name
+
+	events.On("GetById", mock.Anything, current.EventID).Return(current, nil)
+	editor(members, current.OrganizationID, 3)
+	events.On("UpdateEvent", mock.Anything, current.EventID, map[string]interface{}{"name": name}).
+		Return(&updated, nil)
+	webhooks.On("Emit", mock.Anything, current.OrganizationID, model.WebhookEventUpdated, model.WebhookEventUpdatedData{
+		Event:   &updated,
+		Changes: []model.EventChange{},
+	}).Return(nil).Once()
+
+	_, err := u.UpdateEvent(ctx, 3, current.EventID, &model.EventUpdate{Name: &name})
+	assert.NoError(t, err)
+}
+
+func TestEventUseCase_UpdateEvent_Venue(t *testing.T) {
+	ctx := context.Background()
+	events := mocks.NewManagedEventStorage(t)
+	members := mocks.NewEventMemberStorage(t)
+	venues := mocks.NewEventVenueStorage(t)
+	registrations := mocks.NewRegistrationStorage(t)
+	u := &EventUseCase{
+		Transactioner: newTestTransactioner(t),
+		Events:        events,
+		Members:       members,
+		Venues:        venues,
+		Registrations: registrations,
+	}
+	current := &model.Event{EventID: 1, OrganizationID: 2}
+	venueId, foreignVenueId := int64(5), int64(6)
+	updated := *current
+	updated.VenueID = &venueId
+
+	events.On("GetById", mock.Anything, current.EventID).Return(current, nil)
+	editor(members, current.OrganizationID, 3)
+	venues.On("GetVenue", mock.Anything, venueId).Return(&model.Venue{VenueID: venueId, OrganizationID: 2}, nil)
+	venues.On("GetVenue", mock.Anything, foreignVenueId).Return(&model.Venue{VenueID: foreignVenueId, OrganizationID: 4}, nil)
+	events.On("UpdateEvent", mock.Anything, current.EventID, map[string]interface{}{"venue_id": venueId}).
+		Return(&updated, nil).Once()
+	venues.On("ListVenuesByIds", mock.Anything, []int64{venueId}).
+		Return([]model.Venue{{VenueID: venueId, Name: "Hall"}}, nil).Once()
+	registrations.On("ListRegistrants", mock.Anything, current.EventID).Return(nil, nil).Once()
+
+	event, err := u.UpdateEvent(ctx, 3, current.EventID, &model.EventUpdate{VenueID: &venueId})
+	require.NoError(t, err)
+	assert.Equal(t, &venueId, event.VenueID)
+
+	_, err = u.UpdateEvent(ctx, 3, current.EventID, &model.EventUpdate{VenueID: &foreignVenueId})
+	assert.ErrorIs(t, err, repositories.ErrVenueNotFound, "venues of other organizations should not be linked")
+}
+
+func TestEventUseCase_SearchEvents_LoadsVenues(t *testing.T) {
+	ctx := context.Background()
+	events := mocks.NewManagedEventStorage(t)
+	venues := mocks.NewEventVenueStorage(t)
+	tags := mocks.NewEventTagStorage(t)
+	u := &EventUseCase{Events: events, Venues: venues, Tags: tags}
+	venueId := int64(5)
+	lat, lon, radius := 55.75, 37.61, 2000.0
+
+	events.On("SelectBy", mock.Anything, mock.Anything).
+		Return([]model.Event{{EventID: 1, VenueID: &venueId}, {EventID: 2}}, nil).Once()
+	venues.On("ListVenuesByIds", mock.Anything, []int64{venueId}).
+		Return([]model.Venue{{VenueID: venueId, Name: "Hall"}}, nil).Once()
+	tags.On("ListTags", mock.Anything, []int64{1, 2}).
+		Return(map[int64][]string{2: {"free"}}, nil).Once()
+
+	found, err := u.SearchEvents(ctx, &model.EventQuery{Latitude: &lat, Longitude: &lon, Radius: &radius})
+	require.NoError(t, err)
+	require.Len(t, found, 2)
+	assert.Equal(t, "Hall", found[0].Venue.Name)
+	assert.Nil(t, found[1].Venue)
+	assert.Nil(t, found[0].Tags)
+	assert.Equal(t, []string{"free"}, found[1].Tags)
+}
+
+func TestEventUseCase_UpdateEvent_ReplacesTags(t *testing.T) {
+	ctx := context.Background()
+	events := mocks.NewManagedEventStorage(t)
+	members := mocks.NewEventMemberStorage(t)
+	tags := mocks.NewEventTagStorage(t)
+	u := &EventUseCase{Transactioner: newTestTransactioner(t), Events: events, Members: members, Tags: tags}
+	current := &model.Event{EventID: 1, OrganizationID: 2, BeginsAt: time.Now().Add(time.Hour)}
+	events.On("GetById", mock.Anything, int64(1)).Return(current, nil).Once()
+	editor(members, 2, 10)
+	tags.On("SetTags", mock.Anything, int64(1), []string{"free", "18+"}).Return(nil).Once()
+
+	event, err := u.UpdateEvent(ctx, 10, 1, &model.EventUpdate{Tags: []string{"Free", "#18+", "FREE", " "}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"free", "18+"}, event.Tags)
+	events.AssertNotCalled(t, "UpdateEvent", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestEventUseCase_UpdateEvent_RemovesCategory(t *testing.T) {
+	ctx := context.Background()
+	events := mocks.NewManagedEventStorage(t)
+	members := mocks.NewEventMemberStorage(t)
+	u := &EventUseCase{Transactioner: newTestTransactioner(t), Events: events, Members: members}
+	categoryId := int64(3)
+	current := &model.Event{EventID: 1, OrganizationID: 2, CategoryID: &categoryId}
+	events.On("GetById", mock.Anything, int64(1)).Return(current, nil).Once()
+	editor(members, 2, 10)
+	events.On("UpdateEvent", mock.Anything, int64(1), map[string]interface{}{"category_id": nil}).
+		Return(&model.Event{EventID: 1, OrganizationID: 2}, nil).Once()
+
+	zero := int64(0)
+	event, err := u.UpdateEvent(ctx, 10, 1, &model.EventUpdate{CategoryID: &zero})
+	require.NoError(t, err)
+	assert.Nil(t, event.CategoryID)
+}
@@ -0,0 +1,98 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"github.com/burenotti/rtu-it-lab-recruit/model"
+	"github.com/burenotti/rtu-it-lab-recruit/repositories"
+	"github.com/sirupsen/logrus"
+)
+
+type FollowStorage interface {
+	Follow(ctx context.Context, orgId, userId int64) (*model.Follow, error)
+	Unfollow(ctx context.Context, orgId, userId int64) error
+	ListFollowers(ctx context.Context, orgId, afterUserId int64, limit int) ([]model.User, error)
+	ClaimFanout(ctx context.Context) (*model.EventFanout, error)
+	AdvanceFanout(ctx context.Context, fanoutId, lastUserId int64) error
+	CompleteFanout(ctx context.Context, fanoutId int64) error
+}
+
+// FollowUseCase implements following organizations and notification of followers about published events.
+type FollowUseCase struct {
+	Transactioner StorageTransactioner
+	Follows       FollowStorage
+	Events        EventGetter
+	Notifier      UserNotifier
+	Logger        *logrus.Logger
+	// BatchSize is the number of followers notified in a single transaction.
+	BatchSize int
+	// BatchesPerRun limits batches processed by a single run, so a popular organization
+	// doesn't flood the outbox. The rest of followers are notified by the next runs.
+	BatchesPerRun int
+}
+
+type eventPublishedContext struct {
+	User  *model.User
+	Event *model.Event
+}
+
+func (u *FollowUseCase) FollowOrganization(ctx context.Context, userId, orgId int64) (*model.Follow, error) {
+	return u.Follows.Follow(ctx, orgId, userId)
+}
+
+func (u *FollowUseCase) UnfollowOrganization(ctx context.Context, userId, orgId int64) error {
+	return u.Follows.Unfollow(ctx, orgId, userId)
+}
+
+// DispatchFanouts notifies followers of organizations about published events.
+// Each batch is processed in its own transaction with the fanout locked, so replicas don't notify anyone twice.
+func (u *FollowUseCase) DispatchFanouts(ctx context.Context) error {
+	for batch := 0; batch < u.BatchesPerRun && ctx.Err() == nil; batch++ {
+		err := u.Transactioner.Atomic(ctx, func(ctx context.Context) error {
+			fanout, err := u.Follows.ClaimFanout(ctx)
+			if err != nil {
+				return err
+			}
+			return u.notifyBatch(ctx, fanout)
+		})
+		if errors.Is(err, repositories.ErrFanoutNotFound) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+	}
+	return ctx.Err()
+}
+
+func (u *FollowUseCase) notifyBatch(ctx context.Context, fanout *model.EventFanout) error {
+	event, err := u.Events.GetById(ctx, fanout.EventID)
+	if err != nil {
+		return err
+	}
+	if event.IsHidden() || event.IsCancelled() {
+		return u.Follows.CompleteFanout(ctx, fanout.FanoutID)
+	}
+
+	followers, err := u.Follows.ListFollowers(ctx, event.OrganizationID, fanout.LastUserID, u.BatchSize)
+	if err != nil {
+		return err
+	}
+	for i := range followers {
+		user := &followers[i]
+		err = u.Notifier.SendToAll(ctx, user, model.MessageEventPublished, eventPublishedContext{
+			User:  user,
+			Event: event,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	u.Logger.
+		WithField("event_id", event.EventID).
+		Infof("Notified %d followers about event %d", len(followers), event.EventID)
+	if len(followers) < u.BatchSize {
+		return u.Follows.CompleteFanout(ctx, fanout.FanoutID)
+	}
+	return u.Follows.AdvanceFanout(ctx, fanout.FanoutID, followers[len(followers)-1].UserID)
+}
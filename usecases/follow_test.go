@@ -0,0 +1,64 @@
+package usecases
+
+import (
+	"context"
+	"github.com/burenotti/rtu-it-lab-recruit/model"
+	"github.com/burenotti/rtu-it-lab-recruit/repositories"
+	"github.com/burenotti/rtu-it-lab-recruit/usecases/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+	"time"
+)
+
+func TestFollowUseCase_DispatchFanouts(t *testing.T) {
+	ctx := context.Background()
+	follows := mocks.NewFollowStorage(t)
+	events := mocks.NewEventGetter(t)
+	notifier := mocks.NewUserNotifier(t)
+	u := &FollowUseCase{
+		Transactioner: newTestTransactioner(t),
+		Follows:       follows,
+		Events:        events,
+		Notifier:      notifier,
+		Logger:        newTestLogger(),
+		BatchSize:     2,
+		BatchesPerRun: 5,
+	}
+	publishedAt := time.Now()
+	event := &model.Event{EventID: 1, OrganizationID: 2, PublishedAt: &publishedAt}
+	first := []model.User{{UserID: 3}, {UserID: 4}}
+	second := []model.User{{UserID: 5}}
+
+	follows.On("ClaimFanout", mock.Anything).
+		Return(&model.EventFanout{FanoutID: 6, EventID: event.EventID}, nil).Once()
+	follows.On("ClaimFanout", mock.Anything).
+		Return(&model.EventFanout{FanoutID: 6, EventID: event.EventID, LastUserID: 4}, nil).Once()
+	follows.On("ClaimFanout", mock.Anything).Return(nil, repositories.ErrFanoutNotFound).Once()
+	events.On("GetById", mock.Anything, event.EventID).Return(event, nil)
+	follows.On("ListFollowers", mock.Anything, event.OrganizationID, int64(0), u.BatchSize).Return(first, nil)
+	follows.On("ListFollowers", mock.Anything, event.OrganizationID, int64(4), u.BatchSize).Return(second, nil)
+	notifier.On("SendToAll", mock.Anything, mock.Anything, model.MessageEventPublished, mock.Anything).
+		Return(nil).Times(3)
+	follows.On("AdvanceFanout", mock.Anything, int64(6), int64(4)).Return(nil).Once()
+	follows.On("CompleteFanout", mock.Anything, int64(6)).Return(nil).Once()
+
+	assert.NoError(t, u.DispatchFanouts(ctx))
+}
+
+func TestFollowUseCase_DispatchFanouts_CancelledEvent(t *testing.T) {
+	ctx := context.Background()
+	follows := mocks.NewFollowStorage(t)
+	events := mocks.NewEventGetter(t)
+	u := &FollowUseCase{Transactioner: newTestTransactioner(t), Follows: follows, Events: events, BatchesPerRun: 5}
+	cancelledAt := time.Now()
+	event := &model.Event{EventID: 1, OrganizationID: 2, CancelledAt: &cancelledAt}
+
+	follows.On("ClaimFanout", mock.Anything).
+		Return(&model.EventFanout{FanoutID: 6, EventID: event.EventID}, nil).Once()
+	follows.On("ClaimFanout", mock.Anything).Return(nil, repositories.ErrFanoutNotFound).Once()
+	events.On("GetById", mock.Anything, event.EventID).Return(event, nil)
+	follows.On("CompleteFanout", mock.Anything, int64(6)).Return(nil).Once()
+
+	assert.NoError(t, u.DispatchFanouts(ctx))
+}
@@ -40,6 +40,32 @@ func (_m *UserActivator) CreateActivationToken(_a0 context.Context, userId int64
 	return r0, r1
 }
 
+// UseActivationToken provides a mock function with given fields: ctx, token
+func (_m *UserActivator) UseActivationToken(ctx context.Context, token string) (*model.ActivationToken, error) {
+	ret := _m.Called(ctx, token)
+
+	var r0 *model.ActivationToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*model.ActivationToken, error)); ok {
+		return rf(ctx, token)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *model.ActivationToken); ok {
+		r0 = rf(ctx, token)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.ActivationToken)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ValidateActivationToken provides a mock function with given fields: _a0, token
 func (_m *UserActivator) ValidateActivationToken(_a0 context.Context, token string) (*model.ActivationToken, error) {
 	ret := _m.Called(_a0, token)
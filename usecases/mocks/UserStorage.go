@@ -7,6 +7,8 @@ import (
 
 	model "github.com/burenotti/rtu-it-lab-recruit/model"
 	mock "github.com/stretchr/testify/mock"
+
+	time "time"
 )
 
 // UserStorage is an autogenerated mock type for the UserStorage type
@@ -54,6 +56,30 @@ func (_m *UserStorage) Delete(ctx context.Context, userId int64) error {
 	return r0
 }
 
+// DeleteInactiveBefore provides a mock function with given fields: ctx, before
+func (_m *UserStorage) DeleteInactiveBefore(ctx context.Context, before time.Time) (int64, error) {
+	ret := _m.Called(ctx, before)
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) (int64, error)); ok {
+		return rf(ctx, before)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) int64); ok {
+		r0 = rf(ctx, before)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, before)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetByEmail provides a mock function with given fields: ctx, email
 func (_m *UserStorage) GetByEmail(ctx context.Context, email string) (*model.User, error) {
 	ret := _m.Called(ctx, email)
@@ -0,0 +1,83 @@
+package usecases
+
+import (
+	"context"
+	"github.com/burenotti/rtu-it-lab-recruit/model"
+)
+
+// notificationReplayLimit is the maximum number of notifications a stream fetches at once.
+const notificationReplayLimit = 100
+
+type InboxStorage interface {
+	ListNotifications(ctx context.Context, userId int64, unreadOnly bool, limit, offset int) ([]model.Notification, error)
+	ListNotificationsAfter(ctx context.Context, userId, afterId int64, limit int) ([]model.Notification, error)
+	CountUnread(ctx context.Context, userId int64) (int, error)
+	MarkRead(ctx context.Context, userId, notificationId int64) (*model.Notification, error)
+	MarkAllRead(ctx context.Context, userId int64) (int64, error)
+}
+
+type NotificationSubscriber interface {
+	Subscribe(userId int64) (<-chan struct{}, func())
+}
+
+// NotificationUseCase implements the in-app inbox of the user.
+type NotificationUseCase struct {
+	Notifications InboxStorage
+	Subscriber    NotificationSubscriber
+}
+
+// ListNotifications returns the page of notifications starting from the newest and the number of unread ones.
+func (u *NotificationUseCase) ListNotifications(
+	ctx context.Context,
+	userId int64,
+	filter *model.NotificationFilter,
+) (*model.NotificationList, error) {
+	limit := filter.Limit
+	if limit == 0 {
+		limit = defaultPageSize
+	}
+	notifications, err := u.Notifications.ListNotifications(ctx, userId, filter.Unread, limit, filter.Offset)
+	if err != nil {
+		return nil, err
+	}
+	unread, err := u.Notifications.CountUnread(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	return &model.NotificationList{
+		Notifications: notifications,
+		Unread:        unread,
+	}, nil
+}
+
+func (u *NotificationUseCase) MarkNotificationRead(ctx context.Context, userId, notificationId int64) (*model.Notification, error) {
+	return u.Notifications.MarkRead(ctx, userId, notificationId)
+}
+
+func (u *NotificationUseCase) MarkAllNotificationsRead(ctx context.Context, userId int64) (*model.NotificationsRead, error) {
+	count, err := u.Notifications.MarkAllRead(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	return &model.NotificationsRead{Count: count}, nil
+}
+
+// NotificationsAfter returns notifications created after the provided one starting from the oldest.
+func (u *NotificationUseCase) NotificationsAfter(ctx context.Context, userId, afterId int64) ([]model.Notification, error) {
+	return u.Notifications.ListNotificationsAfter(ctx, userId, afterId, notificationReplayLimit)
+}
+
+// LatestNotificationID returns id of the newest notification of the user or zero if there are none.
+// Streams without Last-Event-ID start after it, so old notifications are not sent again.
+func (u *NotificationUseCase) LatestNotificationID(ctx context.Context, userId int64) (int64, error) {
+	notifications, err := u.Notifications.ListNotifications(ctx, userId, false, 1, 0)
+	if err != nil || len(notifications) == 0 {
+		return 0, err
+	}
+	return notifications[0].NotificationID, nil
+}
+
+// SubscribeNotifications returns the channel signalled when new notifications of the user may be available.
+func (u *NotificationUseCase) SubscribeNotifications(userId int64) (<-chan struct{}, func()) {
+	return u.Subscriber.Subscribe(userId)
+}
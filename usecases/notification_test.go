@@ -0,0 +1,42 @@
+package usecases
+
+import (
+	"context"
+	"github.com/burenotti/rtu-it-lab-recruit/model"
+	"github.com/burenotti/rtu-it-lab-recruit/usecases/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestNotificationUseCase_ListNotifications(t *testing.T) {
+	ctx := context.Background()
+	inbox := mocks.NewInboxStorage(t)
+	u := &NotificationUseCase{Notifications: inbox}
+	notifications := []model.Notification{{NotificationID: 2, UserID: 1}, {NotificationID: 1, UserID: 1}}
+
+	inbox.On("ListNotifications", mock.Anything, int64(1), true, defaultPageSize, 0).Return(notifications, nil).Once()
+	inbox.On("CountUnread", mock.Anything, int64(1)).Return(5, nil).Once()
+
+	list, err := u.ListNotifications(ctx, 1, &model.NotificationFilter{Unread: true})
+	require.NoError(t, err)
+	assert.Equal(t, &model.NotificationList{Notifications: notifications, Unread: 5}, list)
+}
+
+func TestNotificationUseCase_LatestNotificationID(t *testing.T) {
+	ctx := context.Background()
+	inbox := mocks.NewInboxStorage(t)
+	u := &NotificationUseCase{Notifications: inbox}
+
+	inbox.On("ListNotifications", mock.Anything, int64(1), false, 1, 0).
+		Return([]model.Notification{{NotificationID: 7}}, nil).Once()
+	id, err := u.LatestNotificationID(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), id)
+
+	inbox.On("ListNotifications", mock.Anything, int64(2), false, 1, 0).Return([]model.Notification{}, nil).Once()
+	id, err = u.LatestNotificationID(ctx, 2)
+	require.NoError(t, err)
+	assert.Zero(t, id, "users without notifications should stream from the beginning")
+}
@@ -43,6 +43,7 @@ func (c *OrganizationUseCase) CreateOrganization(ctx context.Context, userId int
 			Rights: model.MemberRights{
 				EditEvents:    true,
 				ManageMembers: true,
+				CheckIn:       true,
 			},
 		})
 		return err
@@ -0,0 +1,144 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/burenotti/rtu-it-lab-recruit/model"
+	"github.com/sirupsen/logrus"
+	"time"
+)
+
+var (
+	ErrMessageNotDead = errors.New("only dead messages can be replayed")
+)
+
+type OutboxStorage interface {
+	ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]model.OutboxMessage, error)
+	ExtendLease(ctx context.Context, messageId int64, attempts int, lease time.Duration) (bool, error)
+	MarkSent(ctx context.Context, messageId int64) error
+	MarkFailed(ctx context.Context, messageId int64, reason string, nextAttemptAt time.Time) error
+	MarkDead(ctx context.Context, messageId int64, reason string) error
+	Replay(ctx context.Context, messageId int64) error
+	GetById(ctx context.Context, messageId int64) (*model.OutboxMessage, error)
+	List(ctx context.Context, status, channel string, limit, offset int) ([]model.OutboxMessage, error)
+}
+
+type MessageSender interface {
+	Send(ctx context.Context, msg *model.Message) error
+}
+
+// OutboxUseCase dispatches messages written to the outbox.
+// Failed messages are retried with exponential backoff and moved to dead letters after MaxAttempts.
+type OutboxUseCase struct {
+	Transactioner StorageTransactioner
+	Outbox        OutboxStorage
+	Sender        MessageSender
+	Audit         AuditLogStorage
+	Logger        *logrus.Logger
+	// BatchSize is the number of messages claimed at once.
+	BatchSize int
+	// Lease is the time the claimed message is hidden from other dispatchers.
+	// It is renewed before every message of the batch, so it must only be longer than the time needed to send one message.
+	Lease       time.Duration
+	MaxAttempts int
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+}
+
+// DispatchPending sends all due messages. It is safe to run it on several replicas.
+func (u *OutboxUseCase) DispatchPending(ctx context.Context) error {
+	for ctx.Err() == nil {
+		messages, err := u.Outbox.ClaimDue(ctx, u.BatchSize, u.Lease)
+		if err != nil {
+			return err
+		}
+		if len(messages) == 0 {
+			return nil
+		}
+		for i := range messages {
+			if err := u.dispatch(ctx, &messages[i]); err != nil {
+				return err
+			}
+		}
+	}
+	return ctx.Err()
+}
+
+// Backoff returns delay before the next attempt after the given number of failed attempts.
+func (u *OutboxUseCase) Backoff(attempts int) time.Duration {
+	return exponentialBackoff(u.BaseBackoff, u.MaxBackoff, attempts)
+}
+
+func (u *OutboxUseCase) ListMessages(ctx context.Context, filter *model.OutboxFilter) ([]model.OutboxMessage, error) {
+	limit := filter.Limit
+	if limit == 0 {
+		limit = defaultPageSize
+	}
+	return u.Outbox.List(ctx, filter.Status, filter.Channel, limit, filter.Offset)
+}
+
+// ReplayMessage returns dead message to the queue.
+func (u *OutboxUseCase) ReplayMessage(ctx context.Context, actorId, messageId int64) error {
+	return u.Transactioner.Atomic(ctx, func(ctx context.Context) error {
+		msg, err := u.Outbox.GetById(ctx, messageId)
+		if err != nil {
+			return err
+		}
+		if msg.Status != model.OutboxDead {
+			return fmt.Errorf("%w: message status is %s", ErrMessageNotDead, msg.Status)
+		}
+		if err = u.Outbox.Replay(ctx, messageId); err != nil {
+			return err
+		}
+		return u.Audit.Record(ctx, &model.AuditEntryCreate{
+			ActorID:    actorId,
+			Action:     model.AuditOutboxReplay,
+			TargetType: model.AuditTargetEmail,
+			TargetID:   messageId,
+		})
+	})
+}
+
+func (u *OutboxUseCase) dispatch(ctx context.Context, msg *model.OutboxMessage) error {
+	logger := u.Logger.
+		WithField("message_id", msg.MessageID).
+		WithField("kind", msg.Kind).
+		WithField("channel", msg.Channel).
+		WithField("attempt", msg.Attempts)
+
+	// Sending previous messages of the batch could take longer than the lease.
+	extended, err := u.Outbox.ExtendLease(ctx, msg.MessageID, msg.Attempts, u.Lease)
+	if err != nil {
+		return err
+	}
+	if !extended {
+		logger.Warnf("Lease of message %d expired before sending, it is left to another dispatcher", msg.MessageID)
+		return nil
+	}
+
+	sendErr := u.Sender.Send(ctx, &msg.Message)
+	if sendErr == nil {
+		return u.Outbox.MarkSent(ctx, msg.MessageID)
+	}
+
+	if msg.Attempts >= u.MaxAttempts {
+		logger.WithError(sendErr).Errorf("Message %d is dead after %d attempts: %v", msg.MessageID, msg.Attempts, sendErr)
+		return u.Outbox.MarkDead(ctx, msg.MessageID, sendErr.Error())
+	}
+	next := time.Now().UTC().Add(u.Backoff(msg.Attempts))
+	logger.WithError(sendErr).Warnf("Sending message %d failed, retry at %s: %v", msg.MessageID, next, sendErr)
+	return u.Outbox.MarkFailed(ctx, msg.MessageID, sendErr.Error(), next)
+}
+
+// exponentialBackoff doubles the base delay for every failed attempt after the first one, up to the max delay.
+func exponentialBackoff(base, max time.Duration, attempts int) time.Duration {
+	delay := base
+	for i := 1; i < attempts && delay < max; i++ {
+		delay *= 2
+	}
+	if delay > max {
+		delay = max
+	}
+	return delay
+}
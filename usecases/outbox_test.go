@@ -0,0 +1,84 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"github.com/burenotti/rtu-it-lab-recruit/model"
+	"github.com/burenotti/rtu-it-lab-recruit/usecases/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+	"time"
+)
+
+func TestOutboxUseCase_Backoff(t *testing.T) {
+	u := &OutboxUseCase{BaseBackoff: time.Second, MaxBackoff: 10 * time.Second}
+	assert.Equal(t, time.Second, u.Backoff(1))
+	assert.Equal(t, 2*time.Second, u.Backoff(2))
+	assert.Equal(t, 8*time.Second, u.Backoff(4))
+	assert.Equal(t, 10*time.Second, u.Backoff(5), "backoff should be capped by MaxBackoff")
+	assert.Equal(t, 10*time.Second, u.Backoff(100))
+}
+
+func TestOutboxUseCase_DispatchPending(t *testing.T) {
+	ctx := context.Background()
+	outbox := mocks.NewOutboxStorage(t)
+	sender := mocks.NewMessageSender(t)
+	u := &OutboxUseCase{
+		Outbox:      outbox,
+		Sender:      sender,
+		Logger:      newTestLogger(),
+		BatchSize:   10,
+		Lease:       time.Minute,
+		MaxAttempts: 3,
+		BaseBackoff: time.Second,
+		MaxBackoff:  10 * time.Second,
+	}
+	sent := model.OutboxMessage{MessageID: 1, Attempts: 1, Message: model.Message{ToAddress: "sent@example.com"}}
+	failed := model.OutboxMessage{MessageID: 2, Attempts: 1, Message: model.Message{ToAddress: "failed@example.com"}}
+	dead := model.OutboxMessage{MessageID: 3, Attempts: u.MaxAttempts, Message: model.Message{ToAddress: "dead@example.com"}}
+	sendErr := errors.New("smtp is down")
+
+	outbox.On("ClaimDue", mock.Anything, u.BatchSize, u.Lease).
+		Return([]model.OutboxMessage{sent, failed, dead}, nil).Once()
+	outbox.On("ClaimDue", mock.Anything, u.BatchSize, u.Lease).
+		Return(nil, nil).Once()
+	outbox.On("ExtendLease", mock.Anything, mock.Anything, mock.Anything, u.Lease).Return(true, nil)
+	sender.On("Send", mock.Anything, &sent.Message).Return(nil)
+	sender.On("Send", mock.Anything, &failed.Message).Return(sendErr)
+	sender.On("Send", mock.Anything, &dead.Message).Return(sendErr)
+	outbox.On("MarkSent", mock.Anything, sent.MessageID).Return(nil)
+	outbox.On("MarkFailed", mock.Anything, failed.MessageID, sendErr.Error(), mock.MatchedBy(func(next time.Time) bool {
+		return next.After(time.Now())
+	})).Return(nil)
+	outbox.On("MarkDead", mock.Anything, dead.MessageID, sendErr.Error()).Return(nil)
+
+	assert.NoError(t, u.DispatchPending(ctx))
+}
+
+func TestOutboxUseCase_DispatchPending_LeaseExpired(t *testing.T) {
+	ctx := context.Background()
+	outbox := mocks.NewOutboxStorage(t)
+	u := &OutboxUseCase{Outbox: outbox, Logger: newTestLogger(), BatchSize: 10, Lease: time.Minute}
+	reclaimed := model.OutboxMessage{MessageID: 1, Attempts: 1, Message: model.Message{ToAddress: "johndoe@example.com"}}
+
+	outbox.On("ClaimDue", mock.Anything, u.BatchSize, u.Lease).
+		Return([]model.OutboxMessage{reclaimed}, nil).Once()
+	outbox.On("ClaimDue", mock.Anything, u.BatchSize, u.Lease).
+		Return(nil, nil).Once()
+	outbox.On("ExtendLease", mock.Anything, reclaimed.MessageID, reclaimed.Attempts, u.Lease).Return(false, nil)
+
+	assert.NoError(t, u.DispatchPending(ctx), "message claimed by another dispatcher should not be sent twice")
+}
+
+func TestOutboxUseCase_ReplayMessage_NotDead(t *testing.T) {
+	ctx := context.Background()
+	outbox := mocks.NewOutboxStorage(t)
+	u := &OutboxUseCase{Transactioner: newTestTransactioner(t), Outbox: outbox}
+
+	outbox.On("GetById", mock.Anything, int64(1)).
+		Return(&model.OutboxMessage{MessageID: 1, Status: model.OutboxPending}, nil)
+
+	err := u.ReplayMessage(ctx, 1, 1)
+	assert.ErrorIs(t, err, ErrMessageNotDead)
+}
@@ -0,0 +1,280 @@
+package usecases
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/burenotti/rtu-it-lab-recruit/model"
+	"github.com/burenotti/rtu-it-lab-recruit/repositories"
+	"github.com/sirupsen/logrus"
+)
+
+type DataExportStorage interface {
+	Create(ctx context.Context, userId int64) (*model.DataExport, error)
+	GetById(ctx context.Context, exportId int64) (*model.DataExport, error)
+	GetPending(ctx context.Context, userId int64) (*model.DataExport, error)
+	ClaimPending(ctx context.Context) (*model.DataExport, error)
+	Complete(ctx context.Context, exportId int64, archive []byte) error
+	Fail(ctx context.Context, exportId int64, reason string) error
+	GetArchive(ctx context.Context, exportId int64) ([]byte, error)
+}
+
+type PersonalDataStorage interface {
+	GetById(ctx context.Context, userId int64) (*model.User, error)
+	Update(ctx context.Context, userId int64, update map[string]interface{}) (*model.User, error)
+	Anonymize(ctx context.Context, userId int64) error
+}
+
+type MembershipStorage interface {
+	ListUserMemberships(ctx context.Context, userId int64) ([]model.Membership, error)
+}
+
+type LoginHistoryStorage interface {
+	ListLoginHistory(ctx context.Context, userId int64) ([]model.LoginRecord, error)
+}
+
+type RegistrationHistoryStorage interface {
+	ListUserRegistrations(ctx context.Context, userId int64) ([]model.Registration, error)
+}
+
+type FollowHistoryStorage interface {
+	ListUserFollows(ctx context.Context, userId int64) ([]model.Follow, error)
+}
+
+type DigestSettingsStorage interface {
+	GetDigestSettings(ctx context.Context, userId int64) (*model.DigestSettings, error)
+}
+
+type SearchHistoryStorage interface {
+	ListSearches(ctx context.Context, userId int64) ([]model.SavedSearch, error)
+}
+
+type CalendarFeedHistoryStorage interface {
+	ListFeeds(ctx context.Context, userId int64) ([]model.CalendarFeed, error)
+}
+
+type EventImportHistoryStorage interface {
+	ListUserImports(ctx context.Context, userId int64) ([]model.EventImport, error)
+}
+
+type OrderHistoryStorage interface {
+	ListUserOrders(ctx context.Context, userId int64) ([]model.Order, error)
+}
+
+type EventStorage interface {
+	SelectBy(ctx context.Context, filter repositories.EventFilter) ([]model.Event, error)
+}
+
+// PersonalDataUseCase implements export and erasure of user's personal data.
+type PersonalDataUseCase struct {
+	Transactioner StorageTransactioner
+	Users         PersonalDataStorage
+	Exports       DataExportStorage
+	Memberships   MembershipStorage
+	LoginHistory  LoginHistoryStorage
+	Events        EventStorage
+	Registrations RegistrationHistoryStorage
+	Follows       FollowHistoryStorage
+	Digests       DigestSettingsStorage
+	Searches      SearchHistoryStorage
+	Feeds         CalendarFeedHistoryStorage
+	Imports       EventImportHistoryStorage
+	Orders        OrderHistoryStorage
+	Logger        *logrus.Logger
+}
+
+func (u *PersonalDataUseCase) GetProfile(ctx context.Context, userId int64) (*model.User, error) {
+	return u.Users.GetById(ctx, userId)
+}
+
+func (u *PersonalDataUseCase) UpdateProfile(ctx context.Context, userId int64, update *model.MeUpdate) (*model.User, error) {
+	updates := repositories.UpdatesMap{}
+	if update.PreferredLocale != nil {
+		updates["preferred_locale"] = *update.PreferredLocale
+	}
+	if len(updates) == 0 {
+		return u.Users.GetById(ctx, userId)
+	}
+	return u.Users.Update(ctx, userId, updates)
+}
+
+// RequestExport schedules generation of the archive with user's personal data.
+// If there is an export of the user which is not processed yet, it is returned instead.
+func (u *PersonalDataUseCase) RequestExport(ctx context.Context, userId int64) (*model.DataExport, error) {
+	var export *model.DataExport
+	err := u.Transactioner.Atomic(ctx, func(ctx context.Context) (err error) {
+		export, err = u.Exports.GetPending(ctx, userId)
+		if errors.Is(err, repositories.ErrDataExportNotFound) {
+			export, err = u.Exports.Create(ctx, userId)
+		}
+		return err
+	})
+	return export, err
+}
+
+func (u *PersonalDataUseCase) GetExport(ctx context.Context, userId, exportId int64) (*model.DataExport, error) {
+	export, err := u.Exports.GetById(ctx, exportId)
+	if err != nil {
+		return nil, err
+	}
+	if export.UserID != userId {
+		return nil, fmt.Errorf("%w: export with provided id does not exist", repositories.ErrDataExportNotFound)
+	}
+	return export, nil
+}
+
+func (u *PersonalDataUseCase) DownloadExport(ctx context.Context, userId, exportId int64) ([]byte, error) {
+	if _, err := u.GetExport(ctx, userId, exportId); err != nil {
+		return nil, err
+	}
+	return u.Exports.GetArchive(ctx, exportId)
+}
+
+// ProcessPendingExports generates archives for all pending exports.
+// Exports are claimed one by one with row locks, so it is safe to run it on several replicas.
+func (u *PersonalDataUseCase) ProcessPendingExports(ctx context.Context) error {
+	for ctx.Err() == nil {
+		var failed *model.DataExport
+		var buildErr error
+		err := u.Transactioner.Atomic(ctx, func(ctx context.Context) error {
+			export, err := u.Exports.ClaimPending(ctx)
+			if err != nil {
+				return err
+			}
+
+			archive, err := u.buildArchive(ctx, export.UserID)
+			if err != nil {
+				failed, buildErr = export, err
+				return err
+			}
+			return u.Exports.Complete(ctx, export.ExportID, archive)
+		})
+		if failed != nil && ctx.Err() == nil {
+			u.Logger.
+				WithField("export_id", failed.ExportID).
+				WithError(buildErr).
+				Errorf("Export of personal data failed: %v", buildErr)
+			// The failure is recorded after the transaction of the export is rolled back, because a failed query
+			// aborts it. Otherwise the export would stay pending and fail again on every run.
+			if err = u.Exports.Fail(ctx, failed.ExportID, buildErr.Error()); err != nil {
+				return err
+			}
+			continue
+		}
+		if errors.Is(err, repositories.ErrDataExportNotFound) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+	}
+	return ctx.Err()
+}
+
+// EraseUser anonymizes the user instead of deleting, so events created by the user are kept.
+// Owners must transfer or delete their organizations first.
+func (u *PersonalDataUseCase) EraseUser(ctx context.Context, userId int64) error {
+	return u.Transactioner.Atomic(ctx, func(ctx context.Context) error {
+		memberships, err := u.Memberships.ListUserMemberships(ctx, userId)
+		if err != nil {
+			return err
+		}
+		for _, m := range memberships {
+			if m.IsOwner {
+				return fmt.Errorf("%w: user owns organization %d, transfer or delete it first",
+					ErrBusinessLogicViolation, m.OrganizationID)
+			}
+		}
+
+		u.Logger.WithField("user_id", userId).Infof("Erasing personal data of user %d", userId)
+		return u.Users.Anonymize(ctx, userId)
+	})
+}
+
+func (u *PersonalDataUseCase) collect(ctx context.Context, userId int64) (*model.PersonalData, error) {
+	var err error
+	data := &model.PersonalData{}
+	if data.Profile, err = u.Users.GetById(ctx, userId); err != nil {
+		return nil, err
+	}
+	if data.Memberships, err = u.Memberships.ListUserMemberships(ctx, userId); err != nil {
+		return nil, err
+	}
+	if data.LoginHistory, err = u.LoginHistory.ListLoginHistory(ctx, userId); err != nil {
+		return nil, err
+	}
+	if data.CreatedEvents, err = u.Events.SelectBy(ctx, repositories.NewEventCreatorFilter(userId)); err != nil {
+		return nil, err
+	}
+	if data.Registrations, err = u.Registrations.ListUserRegistrations(ctx, userId); err != nil {
+		return nil, err
+	}
+	if data.Follows, err = u.Follows.ListUserFollows(ctx, userId); err != nil {
+		return nil, err
+	}
+	data.DigestSettings, err = u.Digests.GetDigestSettings(ctx, userId)
+	if err != nil && !errors.Is(err, repositories.ErrDigestSettingsNotFound) {
+		return nil, err
+	}
+	if data.SavedSearches, err = u.Searches.ListSearches(ctx, userId); err != nil {
+		return nil, err
+	}
+	if data.CalendarFeeds, err = u.Feeds.ListFeeds(ctx, userId); err != nil {
+		return nil, err
+	}
+	if data.EventImports, err = u.Imports.ListUserImports(ctx, userId); err != nil {
+		return nil, err
+	}
+	if data.Orders, err = u.Orders.ListUserOrders(ctx, userId); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (u *PersonalDataUseCase) buildArchive(ctx context.Context, userId int64) ([]byte, error) {
+	data, err := u.collect(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	return BuildPersonalDataArchive(data)
+}
+
+// BuildPersonalDataArchive packs personal data into zip archive with a json file per section.
+func BuildPersonalDataArchive(data *model.PersonalData) ([]byte, error) {
+	files := []struct {
+		name    string
+		content interface{}
+	}{
+		{"profile.json", data.Profile},
+		{"memberships.json", data.Memberships},
+		{"login_history.json", data.LoginHistory},
+		{"created_events.json", data.CreatedEvents},
+		{"registrations.json", data.Registrations},
+		{"follows.json", data.Follows},
+		{"digest_settings.json", data.DigestSettings},
+		{"saved_searches.json", data.SavedSearches},
+		{"calendar_feeds.json", data.CalendarFeeds},
+		{"event_imports.json", data.EventImports},
+		{"orders.json", data.Orders},
+	}
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	for _, file := range files {
+		w, err := archive.Create(file.name)
+		if err != nil {
+			return nil, err
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if err = encoder.Encode(file.content); err != nil {
+			return nil, err
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
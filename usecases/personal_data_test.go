@@ -0,0 +1,89 @@
+package usecases
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"github.com/burenotti/rtu-it-lab-recruit/model"
+	"github.com/burenotti/rtu-it-lab-recruit/repositories"
+	"github.com/burenotti/rtu-it-lab-recruit/usecases/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestBuildPersonalDataArchive(t *testing.T) {
+	data := &model.PersonalData{
+		Profile: &model.User{UserID: 1, FirstName: "John", LastName: "Doe", Email: "johndoe@example.com"},
+		Memberships: []model.Membership{
+			{OrganizationID: 1, Name: "RTUITLab", IsOwner: true},
+		},
+	}
+	archive, err := BuildPersonalDataArchive(data)
+	require.NoError(t, err, "should build archive without errors")
+
+	reader, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	require.NoError(t, err, "archive should be a valid zip")
+
+	var names []string
+	for _, f := range reader.File {
+		names = append(names, f.Name)
+	}
+	assert.ElementsMatch(t, []string{
+		"profile.json", "memberships.json", "login_history.json", "created_events.json", "registrations.json",
+		"follows.json", "digest_settings.json", "saved_searches.json", "calendar_feeds.json",
+		"event_imports.json", "orders.json",
+	}, names)
+}
+
+func TestPersonalDataUseCase_EraseUser(t *testing.T) {
+	ctx := context.Background()
+	users := mocks.NewPersonalDataStorage(t)
+	memberships := mocks.NewMembershipStorage(t)
+	u := PersonalDataUseCase{
+		Transactioner: newTestTransactioner(t),
+		Users:         users,
+		Memberships:   memberships,
+		Logger:        newTestLogger(),
+	}
+
+	memberships.On("ListUserMemberships", mock.Anything, int64(1)).
+		Return([]model.Membership{{OrganizationID: 1, IsOwner: false}}, nil)
+	users.On("Anonymize", mock.Anything, int64(1)).Return(nil).Once()
+	assert.NoError(t, u.EraseUser(ctx, 1))
+
+	memberships.On("ListUserMemberships", mock.Anything, int64(2)).
+		Return([]model.Membership{{OrganizationID: 1, IsOwner: true}}, nil)
+	err := u.EraseUser(ctx, 2)
+	assert.ErrorIs(t, err, ErrBusinessLogicViolation, "owner should not be erased")
+}
+
+type testTxKey struct{}
+
+func TestPersonalDataUseCase_ProcessPendingExports_Failed(t *testing.T) {
+	ctx := context.Background()
+	tx := mocks.NewStorageTransactioner(t)
+	users := mocks.NewPersonalDataStorage(t)
+	exports := mocks.NewDataExportStorage(t)
+	u := PersonalDataUseCase{
+		Transactioner: tx,
+		Users:         users,
+		Exports:       exports,
+		Logger:        newTestLogger(),
+	}
+	inTx := func(ctx context.Context) bool { return ctx.Value(testTxKey{}) != nil }
+
+	tx.On("Atomic", mock.Anything, mock.Anything).
+		Return(func(ctx context.Context, f repositories.AtomicFunc) error {
+			return f(context.WithValue(ctx, testTxKey{}, true))
+		})
+	exports.On("ClaimPending", mock.Anything).Return(&model.DataExport{ExportID: 5, UserID: 1}, nil).Once()
+	users.On("GetById", mock.Anything, int64(1)).Return(nil, errors.New("connection reset"))
+	exports.On("Fail", mock.MatchedBy(func(ctx context.Context) bool { return !inTx(ctx) }), int64(5), "connection reset").
+		Return(nil).Once()
+	exports.On("ClaimPending", mock.Anything).Return(nil, repositories.ErrDataExportNotFound).Once()
+
+	assert.NoError(t, u.ProcessPendingExports(ctx), "failed export should be recorded outside of its aborted transaction")
+}
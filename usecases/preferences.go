@@ -0,0 +1,96 @@
+package usecases
+
+import (
+	"context"
+	"github.com/burenotti/rtu-it-lab-recruit/model"
+	"github.com/burenotti/rtu-it-lab-recruit/services"
+)
+
+type NotificationPreferenceStorage interface {
+	ListPreferences(ctx context.Context, userId int64) ([]model.NotificationPreference, error)
+	SetPreference(ctx context.Context, userId int64, channel, category string, enabled bool) error
+}
+
+type PreferenceChannelStorage interface {
+	ListChannels(ctx context.Context, userId int64) ([]model.UserChannel, error)
+}
+
+type UnsubscribeTokenVerifier interface {
+	Verify(token string) (*model.UnsubscribeClaims, error)
+}
+
+// PreferencesUseCase manages categories of messages users receive in each channel.
+type PreferencesUseCase struct {
+	Transactioner StorageTransactioner
+	Preferences   NotificationPreferenceStorage
+	Channels      PreferenceChannelStorage
+	Users         ChannelUserStorage
+	Tokens        UnsubscribeTokenVerifier
+}
+
+// ListPreferences returns preferences of every category in every channel of the user.
+func (u *PreferencesUseCase) ListPreferences(ctx context.Context, userId int64) ([]model.NotificationPreference, error) {
+	user, err := u.Users.GetById(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	linked, err := u.Channels.ListChannels(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	stored, err := u.Preferences.ListPreferences(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	disabled := make(map[[2]string]bool)
+	for _, p := range stored {
+		disabled[[2]string{p.Channel, p.Category}] = !p.Enabled
+	}
+
+	var preferences []model.NotificationPreference
+	for _, channel := range services.WithDefaultChannels(user, linked) {
+		for _, category := range model.NotificationCategories {
+			preferences = append(preferences, model.NotificationPreference{
+				Channel:  channel.Channel,
+				Category: category,
+				Enabled:  !disabled[[2]string{channel.Channel, category}],
+			})
+		}
+	}
+	return preferences, nil
+}
+
+func (u *PreferencesUseCase) UpdatePreferences(
+	ctx context.Context,
+	userId int64,
+	update *model.NotificationPreferencesUpdate,
+) ([]model.NotificationPreference, error) {
+	var preferences []model.NotificationPreference
+	err := u.Transactioner.Atomic(ctx, func(ctx context.Context) (err error) {
+		for _, p := range update.Preferences {
+			if err = u.Preferences.SetPreference(ctx, userId, p.Channel, p.Category, *p.Enabled); err != nil {
+				return err
+			}
+		}
+		preferences, err = u.ListPreferences(ctx, userId)
+		return err
+	})
+	return preferences, err
+}
+
+// UnsubscribeByToken opts the user out of the category the unsubscribe link was issued for.
+// Unsubscribing again is not an error, so the link may be followed several times.
+func (u *PreferencesUseCase) UnsubscribeByToken(ctx context.Context, token string) (*model.NotificationPreference, error) {
+	claims, err := u.Tokens.Verify(token)
+	if err != nil {
+		return nil, err
+	}
+	if err = u.Preferences.SetPreference(ctx, claims.UserID, claims.Channel, claims.Category, false); err != nil {
+		return nil, err
+	}
+	return &model.NotificationPreference{
+		Channel:  claims.Channel,
+		Category: claims.Category,
+		Enabled:  false,
+	}, nil
+}
@@ -0,0 +1,60 @@
+package usecases
+
+import (
+	"context"
+	"github.com/burenotti/rtu-it-lab-recruit/model"
+	"github.com/burenotti/rtu-it-lab-recruit/services"
+	"github.com/burenotti/rtu-it-lab-recruit/usecases/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestPreferencesUseCase_ListPreferences(t *testing.T) {
+	ctx := context.Background()
+	preferences := mocks.NewNotificationPreferenceStorage(t)
+	channels := mocks.NewPreferenceChannelStorage(t)
+	users := mocks.NewChannelUserStorage(t)
+	u := &PreferencesUseCase{Preferences: preferences, Channels: channels, Users: users}
+	user := &model.User{UserID: 1, Email: "johndoe@example.com"}
+
+	users.On("GetById", mock.Anything, user.UserID).Return(user, nil)
+	channels.On("ListChannels", mock.Anything, user.UserID).
+		Return([]model.UserChannel{{Channel: model.ChannelTelegram, Address: "42", Enabled: true}}, nil)
+	preferences.On("ListPreferences", mock.Anything, user.UserID).Return([]model.NotificationPreference{
+		{Channel: model.ChannelEmail, Category: model.CategoryDigests, Enabled: false},
+		{Channel: model.ChannelTelegram, Category: model.CategoryFollows, Enabled: true},
+	}, nil)
+
+	result, err := u.ListPreferences(ctx, user.UserID)
+	require.NoError(t, err)
+	require.Len(t, result, 3*len(model.NotificationCategories), "email, in-app and telegram channels are expected")
+	for _, p := range result {
+		disabled := p.Channel == model.ChannelEmail && p.Category == model.CategoryDigests
+		assert.Equal(t, !disabled, p.Enabled, "%s in %s", p.Category, p.Channel)
+	}
+}
+
+func TestPreferencesUseCase_UnsubscribeByToken(t *testing.T) {
+	ctx := context.Background()
+	preferences := mocks.NewNotificationPreferenceStorage(t)
+	links := &services.UnsubscribeLinks{Secret: []byte("secret")}
+	u := &PreferencesUseCase{Preferences: preferences, Tokens: links}
+	token := links.Token(model.UnsubscribeClaims{UserID: 1, Channel: model.ChannelEmail, Category: model.CategoryReminders})
+
+	preferences.On("SetPreference", mock.Anything, int64(1), model.ChannelEmail, model.CategoryReminders, false).
+		Return(nil).Twice()
+
+	for i := 0; i < 2; i++ {
+		preference, err := u.UnsubscribeByToken(ctx, token)
+		require.NoError(t, err, "link should work several times")
+		assert.Equal(t, &model.NotificationPreference{
+			Channel:  model.ChannelEmail,
+			Category: model.CategoryReminders,
+		}, preference)
+	}
+
+	_, err := u.UnsubscribeByToken(ctx, token+"x")
+	assert.ErrorIs(t, err, services.ErrInvalidUnsubscribeToken)
+}
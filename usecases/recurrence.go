@@ -0,0 +1,551 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/burenotti/rtu-it-lab-recruit/model"
+	"github.com/burenotti/rtu-it-lab-recruit/pkg/rrule"
+	"github.com/burenotti/rtu-it-lab-recruit/repositories"
+	"sort"
+	"time"
+)
+
+// maxMaterializedOccurrences limits occurrences of a series materialized within the horizon.
+const maxMaterializedOccurrences = 500
+
+type OccurrenceStorage interface {
+	CreateOccurrences(ctx context.Context, occurrences []model.Occurrence) error
+	GetOccurrence(ctx context.Context, eventId, occurrenceId int64) (*model.Occurrence, error)
+	ListOccurrences(ctx context.Context, eventId int64, from, to time.Time) ([]model.Occurrence, error)
+	ListOccurrencesSince(ctx context.Context, eventId int64, since time.Time) ([]model.Occurrence, error)
+	UpdateOccurrence(ctx context.Context, occurrenceId int64, updates map[string]interface{}) (*model.Occurrence, error)
+	RemoveOccurrences(ctx context.Context, occurrenceIds []int64) ([]int64, error)
+	MoveOccurrences(ctx context.Context, fromEventId, toEventId int64, since time.Time) error
+}
+
+// ListOccurrences returns occurrences of the recurring event within the window of the query.
+// Occurrences of unpublished or hidden events are visible only to members with rights to edit events.
+func (u *EventUseCase) ListOccurrences(
+	ctx context.Context,
+	userId, eventId int64,
+	query *model.OccurrenceQuery,
+) ([]model.Occurrence, error) {
+	event, err := u.Events.GetById(ctx, eventId)
+	if err != nil {
+		return nil, err
+	}
+	if !event.IsPublished() || event.IsHidden() {
+		if err = u.checkCanEdit(ctx, event.OrganizationID, userId); err != nil {
+			return nil, fmt.Errorf("%w: event with provided id does not exist", repositories.ErrEventNotFount)
+		}
+	}
+	from, to := query.Window(time.Now().UTC())
+	if !from.Before(to) {
+		return nil, fmt.Errorf("%w: from must be before to", ErrBusinessLogicViolation)
+	}
+	return u.Occurrences.ListOccurrences(ctx, eventId, from, to)
+}
+
+// UpdateOccurrence changes the occurrence of recurring event within the scope:
+// the occurrence only, the occurrence with all following ones, or the whole series.
+// Changing following occurrences splits the series in two, the occurrence begins the new series.
+// It returns the changed occurrence, which belongs to the new series after the split.
+func (u *EventUseCase) UpdateOccurrence(
+	ctx context.Context,
+	userId, eventId, occurrenceId int64,
+	scope string,
+	update *model.OccurrenceUpdate,
+) (*model.Occurrence, error) {
+	var occurrence *model.Occurrence
+	err := u.Transactioner.Atomic(ctx, func(ctx context.Context) error {
+		event, err := u.Events.GetById(ctx, eventId)
+		if err != nil {
+			return err
+		}
+		if err = u.checkCanEdit(ctx, event.OrganizationID, userId); err != nil {
+			return err
+		}
+		if event.IsCancelled() {
+			return fmt.Errorf("%w: cancelled event can't be changed", ErrBusinessLogicViolation)
+		}
+		current, err := u.Occurrences.GetOccurrence(ctx, eventId, occurrenceId)
+		if err != nil {
+			return err
+		}
+		if current.IsCancelled() {
+			return fmt.Errorf("%w: cancelled occurrence can't be changed", ErrBusinessLogicViolation)
+		}
+
+		switch scope {
+		case model.OccurrenceScopeFollowing:
+			occurrence, err = u.updateFollowingOccurrences(ctx, userId, event, current, update)
+		case model.OccurrenceScopeAll:
+			occurrence, err = u.updateAllOccurrences(ctx, event, current, update)
+		default:
+			occurrence, err = u.updateThisOccurrence(ctx, event, current, update)
+		}
+		return err
+	})
+	return occurrence, err
+}
+
+// CancelOccurrence excludes the occurrence from the series and notifies its registrants.
+func (u *EventUseCase) CancelOccurrence(ctx context.Context, userId, eventId, occurrenceId int64) (*model.Occurrence, error) {
+	var occurrence *model.Occurrence
+	err := u.Transactioner.Atomic(ctx, func(ctx context.Context) error {
+		event, err := u.Events.GetById(ctx, eventId)
+		if err != nil {
+			return err
+		}
+		if err = u.checkCanEdit(ctx, event.OrganizationID, userId); err != nil {
+			return err
+		}
+		current, err := u.Occurrences.GetOccurrence(ctx, eventId, occurrenceId)
+		if err != nil {
+			return err
+		}
+		if current.IsCancelled() {
+			return fmt.Errorf("%w: occurrence is already cancelled", ErrBusinessLogicViolation)
+		}
+		occurrence, err = u.Occurrences.UpdateOccurrence(ctx, occurrenceId, repositories.UpdatesMap{
+			"cancelled_at": time.Now().UTC(),
+		})
+		if err != nil {
+			return err
+		}
+		if err = u.Events.Touch(ctx, eventId); err != nil {
+			return err
+		}
+		return u.notifyOccurrenceCancelled(ctx, eventOccurrence(event, current), occurrenceId)
+	})
+	return occurrence, err
+}
+
+// notifyOccurrenceCancelled notifies registrants of the occurrence, cancelled is the event as of the occurrence.
+func (u *EventUseCase) notifyOccurrenceCancelled(ctx context.Context, cancelled *model.Event, occurrenceId int64) error {
+	registrants, err := u.Registrations.ListOccurrenceRegistrants(ctx, occurrenceId)
+	if err != nil {
+		return err
+	}
+	return u.notifyRegistrants(ctx, registrants, model.MessageEventCancelled, func(user *model.User) interface{} {
+		return eventCancelledContext{User: user, Event: localEvent(cancelled)}
+	})
+}
+
+// ExtendOccurrences materializes occurrences of all recurring events up to the horizon.
+// Each series is synchronized in its own transaction, so a broken one doesn't block the rest.
+func (u *EventUseCase) ExtendOccurrences(ctx context.Context) error {
+	events, err := u.Events.SelectBy(ctx, repositories.NewEventRecurringFilter())
+	if err != nil {
+		return err
+	}
+	for i := range events {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		event := &events[i]
+		if err = u.Transactioner.Atomic(ctx, func(ctx context.Context) error {
+			return u.syncOccurrences(ctx, event, 0)
+		}); err != nil {
+			u.Logger.WithError(err).WithField("event_id", event.EventID).Error("Failed to extend occurrences")
+		}
+	}
+	return nil
+}
+
+func (u *EventUseCase) updateThisOccurrence(
+	ctx context.Context,
+	event *model.Event,
+	current *model.Occurrence,
+	update *model.OccurrenceUpdate,
+) (*model.Occurrence, error) {
+	updates := repositories.UpdatesMap{"overridden": true}
+	beginsAt, endsAt := current.BeginsAt, current.EndsAt
+	if update.Name != nil {
+		updates["name"] = *update.Name
+	}
+	if update.Description != nil {
+		updates["description"] = *update.Description
+	}
+	if update.BeginsAt != nil {
+		beginsAt = update.BeginsAt.UTC()
+		updates["begins_at"] = beginsAt
+	}
+	if update.EndsAt != nil {
+		endsAt = update.EndsAt.UTC()
+		updates["ends_at"] = endsAt
+	}
+	if endsAt.Before(beginsAt) {
+		return nil, fmt.Errorf("%w: occurrence can't end before it begins", ErrBusinessLogicViolation)
+	}
+	occurrence, err := u.Occurrences.UpdateOccurrence(ctx, current.OccurrenceID, updates)
+	if err != nil {
+		return nil, err
+	}
+	if err = u.Events.Touch(ctx, event.EventID); err != nil {
+		return nil, err
+	}
+	if !occurrence.BeginsAt.Equal(current.BeginsAt) {
+		if err = u.Reminders.ReplanEvent(ctx, event.EventID); err != nil {
+			return nil, err
+		}
+	}
+
+	old, changed := eventOccurrence(event, current), eventOccurrence(event, occurrence)
+	changes := DiffEvents(old, changed)
+	if len(changes) == 0 {
+		return occurrence, nil
+	}
+	registrants, err := u.Registrations.ListOccurrenceRegistrants(ctx, occurrence.OccurrenceID)
+	if err != nil {
+		return nil, err
+	}
+	return occurrence, u.notifyRegistrants(ctx, registrants, model.MessageEventChanged, func(user *model.User) interface{} {
+		return eventChangedContext{User: user, Event: localEvent(changed), Changes: changes}
+	})
+}
+
+// updateAllOccurrences applies the change of the occurrence to the series. Time changes shift the series
+// by the difference with the occurrence's time.
+func (u *EventUseCase) updateAllOccurrences(
+	ctx context.Context,
+	event *model.Event,
+	current *model.Occurrence,
+	update *model.OccurrenceUpdate,
+) (*model.Occurrence, error) {
+	beginsAt, endsAt := shiftSeries(event, current, update)
+	_, err := u.updateEvent(ctx, event, &model.EventUpdate{
+		Name:        update.Name,
+		Description: update.Description,
+		BeginsAt:    &beginsAt,
+		EndsAt:      &endsAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return u.Occurrences.GetOccurrence(ctx, event.EventID, current.OccurrenceID)
+}
+
+// updateFollowingOccurrences ends the series before the occurrence and starts the new one with it.
+// Following occurrences and their registrations are moved to the new series.
+func (u *EventUseCase) updateFollowingOccurrences(
+	ctx context.Context,
+	userId int64,
+	event *model.Event,
+	current *model.Occurrence,
+	update *model.OccurrenceUpdate,
+) (*model.Occurrence, error) {
+	if current.RecurrenceID.Equal(event.BeginsAt) {
+		return u.updateAllOccurrences(ctx, event, current, update)
+	}
+	rule, err := rrule.Parse(*event.RecurrenceRule)
+	if err != nil {
+		return nil, err
+	}
+	loc, err := time.LoadLocation(event.Timezone)
+	if err != nil {
+		return nil, err
+	}
+	following := *rule
+	if rule.Count > 0 {
+		before := rule.Between(event.BeginsAt.In(loc), event.BeginsAt, current.RecurrenceID, rule.Count)
+		following.Count = rule.Count - len(before)
+	}
+	rule.SetUntil(current.RecurrenceID.Add(-time.Second))
+
+	seriesBegins, seriesEnds := shiftSeries(event, current, update)
+	shift := seriesBegins.Sub(event.BeginsAt)
+	beginsAt := current.RecurrenceID.Add(shift)
+	endsAt := beginsAt.Add(seriesEnds.Sub(seriesBegins))
+	ruleValue := following.String()
+	create := &model.EventCreate{
+		Name:               event.Name,
+		OrganizationID:     event.OrganizationID,
+		CreatorID:          userId,
+		Description:        event.Description,
+		BeginsAt:           beginsAt,
+		EndsAt:             endsAt,
+		RegistrationNeeded: event.RegistrationNeeded,
+		RegistrationBegin:  shiftTime(event.RegistrationBegin, beginsAt.Sub(event.BeginsAt)),
+		RegistrationEnd:    shiftTime(event.RegistrationEnd, beginsAt.Sub(event.BeginsAt)),
+		VenueID:            event.VenueID,
+		OnlineURL:          event.OnlineURL,
+		CategoryID:         event.CategoryID,
+		RecurrenceRule:     &ruleValue,
+		Timezone:           event.Timezone,
+	}
+	if update.Name != nil {
+		create.Name = *update.Name
+	}
+	if update.Description != nil {
+		create.Description = *update.Description
+	}
+	series, err := u.Events.Create(ctx, create)
+	if err != nil {
+		return nil, err
+	}
+	if event.IsPublished() {
+		if err = u.Events.Publish(ctx, series.EventID); err != nil {
+			return nil, err
+		}
+	}
+	tags, err := u.Tags.ListTags(ctx, []int64{event.EventID})
+	if err != nil {
+		return nil, err
+	}
+	if err = u.Tags.SetTags(ctx, series.EventID, tags[event.EventID]); err != nil {
+		return nil, err
+	}
+
+	if _, err = u.Events.UpdateEvent(ctx, event.EventID, repositories.UpdatesMap{"recurrence_rule": rule.String()}); err != nil {
+		return nil, err
+	}
+	if err = u.Occurrences.MoveOccurrences(ctx, event.EventID, series.EventID, current.RecurrenceID); err != nil {
+		return nil, err
+	}
+	if err = u.syncOccurrences(ctx, series, shift); err != nil {
+		return nil, err
+	}
+	if err = u.Reminders.ReplanEvent(ctx, series.EventID); err != nil {
+		return nil, err
+	}
+	occurrence, err := u.Occurrences.GetOccurrence(ctx, series.EventID, current.OccurrenceID)
+	if err != nil {
+		return nil, err
+	}
+
+	old, changed := eventOccurrence(event, current), eventOccurrence(series, occurrence)
+	changes := DiffEvents(old, changed)
+	if len(changes) == 0 {
+		return occurrence, nil
+	}
+	registrants, err := u.Registrations.ListRegistrants(ctx, series.EventID)
+	if err != nil {
+		return nil, err
+	}
+	return occurrence, u.notifyRegistrants(ctx, registrants, model.MessageEventChanged, func(user *model.User) interface{} {
+		return eventChangedContext{User: user, Event: localEvent(series), Changes: changes}
+	})
+}
+
+// syncOccurrences materializes upcoming occurrences of the event up to the horizon and removes ones
+// no longer matching the rule. Shift is how much the series moved, existing occurrences are moved
+// by it first, so they keep their registrations and individual changes.
+func (u *EventUseCase) syncOccurrences(ctx context.Context, event *model.Event, shift time.Duration) error {
+	now := time.Now().UTC()
+	starts, err := u.occurrenceStarts(event, now)
+	if err != nil {
+		return err
+	}
+	existing, err := u.Occurrences.ListOccurrencesSince(ctx, event.EventID, now)
+	if err != nil {
+		return err
+	}
+
+	wanted := make(map[time.Time]bool, len(starts))
+	for _, start := range starts {
+		wanted[start] = true
+	}
+	claim := func(t time.Time) bool {
+		if !wanted[t] {
+			return false
+		}
+		delete(wanted, t)
+		return true
+	}
+	var moved []model.Occurrence
+	var kept, removed []int64
+	byId := make(map[int64]*model.Occurrence, len(existing))
+	for i, o := range existing {
+		byId[o.OccurrenceID] = &existing[i]
+		key := o.RecurrenceID.UTC().Add(shift)
+		switch {
+		case claim(key):
+			if shift != 0 {
+				moved = append(moved, o)
+			} else {
+				kept = append(kept, o.OccurrenceID)
+			}
+		case shift != 0 && claim(o.RecurrenceID.UTC()):
+			kept = append(kept, o.OccurrenceID)
+		default:
+			removed = append(removed, o.OccurrenceID)
+		}
+	}
+	if len(removed) > 0 {
+		cancelled, err := u.Occurrences.RemoveOccurrences(ctx, removed)
+		if err != nil {
+			return err
+		}
+		for _, occurrenceId := range cancelled {
+			if err = u.notifyOccurrenceCancelled(ctx, eventOccurrence(event, byId[occurrenceId]), occurrenceId); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Occurrences are moved from the far end, so a moved one never takes RECURRENCE-ID of one not moved yet.
+	sort.Slice(moved, func(i, j int) bool {
+		if shift > 0 {
+			return moved[i].RecurrenceID.After(moved[j].RecurrenceID)
+		}
+		return moved[i].RecurrenceID.Before(moved[j].RecurrenceID)
+	})
+	duration := event.EndsAt.Sub(event.BeginsAt)
+	for _, o := range moved {
+		recurrenceId := o.RecurrenceID.UTC().Add(shift)
+		updates := repositories.UpdatesMap{
+			"recurrence_id": recurrenceId,
+			"begins_at":     recurrenceId,
+			"ends_at":       recurrenceId.Add(duration),
+		}
+		if o.Overridden {
+			updates["begins_at"] = o.BeginsAt.Add(shift)
+			updates["ends_at"] = o.EndsAt.Add(shift)
+		}
+		if _, err = u.Occurrences.UpdateOccurrence(ctx, o.OccurrenceID, updates); err != nil {
+			return err
+		}
+	}
+	if err = u.resetOccurrences(ctx, existing, kept, duration); err != nil {
+		return err
+	}
+
+	created := make([]model.Occurrence, 0, len(wanted))
+	for _, start := range starts {
+		if wanted[start] {
+			created = append(created, model.Occurrence{
+				EventID:      event.EventID,
+				RecurrenceID: start,
+				BeginsAt:     start,
+				EndsAt:       start.Add(duration),
+			})
+		}
+	}
+	if len(created) == 0 {
+		return nil
+	}
+	return u.Occurrences.CreateOccurrences(ctx, created)
+}
+
+// resetOccurrences updates time of kept occurrences, which are not overridden, if the duration of the series changed.
+func (u *EventUseCase) resetOccurrences(ctx context.Context, existing []model.Occurrence, kept []int64, duration time.Duration) error {
+	isKept := make(map[int64]bool, len(kept))
+	for _, id := range kept {
+		isKept[id] = true
+	}
+	for _, o := range existing {
+		if !isKept[o.OccurrenceID] || o.Overridden {
+			continue
+		}
+		beginsAt := o.RecurrenceID.UTC()
+		if o.BeginsAt.Equal(beginsAt) && o.EndsAt.Equal(beginsAt.Add(duration)) {
+			continue
+		}
+		_, err := u.Occurrences.UpdateOccurrence(ctx, o.OccurrenceID, repositories.UpdatesMap{
+			"begins_at": beginsAt,
+			"ends_at":   beginsAt.Add(duration),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// occurrenceStarts returns beginnings of occurrences of the event from now up to the horizon.
+// Single and cancelled events have no occurrences.
+func (u *EventUseCase) occurrenceStarts(event *model.Event, now time.Time) ([]time.Time, error) {
+	if !event.IsRecurring() || event.IsCancelled() {
+		return nil, nil
+	}
+	rule, err := rrule.Parse(*event.RecurrenceRule)
+	if err != nil {
+		return nil, err
+	}
+	loc, err := time.LoadLocation(event.Timezone)
+	if err != nil {
+		return nil, err
+	}
+	starts := rule.Between(event.BeginsAt.In(loc), now, now.Add(u.RecurrenceHorizon), maxMaterializedOccurrences)
+	for i := range starts {
+		starts[i] = starts[i].UTC()
+	}
+	return starts, nil
+}
+
+// occurrenceFor returns the occurrence the user registers for. Recurring events require one,
+// the registration window of the series applies to it shifted to its time.
+func (u *EventUseCase) occurrenceFor(ctx context.Context, event *model.Event, occurrenceId int64) (*model.Occurrence, error) {
+	if !event.IsRecurring() {
+		if occurrenceId != 0 {
+			return nil, fmt.Errorf("%w: event is not recurring", ErrBusinessLogicViolation)
+		}
+		return nil, nil
+	}
+	if occurrenceId == 0 {
+		return nil, fmt.Errorf("%w: occurrence is required to register for recurring event", ErrBusinessLogicViolation)
+	}
+	occurrence, err := u.Occurrences.GetOccurrence(ctx, event.EventID, occurrenceId)
+	if err != nil {
+		return nil, err
+	}
+	if occurrence.IsCancelled() {
+		return nil, fmt.Errorf("%w: occurrence is cancelled", ErrBusinessLogicViolation)
+	}
+	return occurrence, nil
+}
+
+// validateRecurrence checks the rule and the timezone of the event and returns the rule in canonical form.
+func validateRecurrence(value string, timezone string) (string, error) {
+	if _, err := time.LoadLocation(timezone); err != nil {
+		return "", fmt.Errorf("%w: unknown timezone %q", ErrBusinessLogicViolation, timezone)
+	}
+	rule, err := rrule.Parse(value)
+	if errors.Is(err, rrule.ErrInvalidRule) {
+		return "", fmt.Errorf("%w: %v", ErrBusinessLogicViolation, err)
+	} else if err != nil {
+		return "", err
+	}
+	return rule.String(), nil
+}
+
+// eventOccurrence returns the event as it is at the occurrence: with its time, its name and description,
+// and the registration window shifted to it.
+func eventOccurrence(event *model.Event, o *model.Occurrence) *model.Event {
+	e := *event
+	shift := o.BeginsAt.Sub(event.BeginsAt)
+	e.BeginsAt, e.EndsAt = o.BeginsAt, o.EndsAt
+	e.RegistrationBegin = shiftTime(event.RegistrationBegin, shift)
+	e.RegistrationEnd = shiftTime(event.RegistrationEnd, shift)
+	if o.Name != nil {
+		e.Name = *o.Name
+	}
+	if o.Description != nil {
+		e.Description = *o.Description
+	}
+	return &e
+}
+
+// shiftSeries returns the new time of the series after the change of its occurrence.
+func shiftSeries(event *model.Event, current *model.Occurrence, update *model.OccurrenceUpdate) (beginsAt, endsAt time.Time) {
+	occurrenceBegins := current.BeginsAt
+	if update.BeginsAt != nil {
+		occurrenceBegins = update.BeginsAt.UTC()
+	}
+	beginsAt = event.BeginsAt.Add(occurrenceBegins.Sub(current.BeginsAt))
+	endsAt = beginsAt.Add(event.EndsAt.Sub(event.BeginsAt))
+	if update.EndsAt != nil {
+		endsAt = beginsAt.Add(update.EndsAt.UTC().Sub(occurrenceBegins))
+	}
+	return beginsAt, endsAt
+}
+
+func shiftTime(t *time.Time, shift time.Duration) *time.Time {
+	if t == nil {
+		return nil
+	}
+	shifted := t.Add(shift)
+	return &shifted
+}
@@ -0,0 +1,375 @@
+package usecases
+
+import (
+	"context"
+	"github.com/burenotti/rtu-it-lab-recruit/model"
+	"github.com/burenotti/rtu-it-lab-recruit/usecases/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func tomorrowAt(hour int) time.Time {
+	t := time.Now().UTC().AddDate(0, 0, 1)
+	return time.Date(t.Year(), t.Month(), t.Day(), hour, 0, 0, 0, time.UTC)
+}
+
+func TestEventUseCase_UpdateEvent_MaterializesOccurrences(t *testing.T) {
+	ctx := context.Background()
+	events := mocks.NewManagedEventStorage(t)
+	members := mocks.NewEventMemberStorage(t)
+	occurrences := mocks.NewOccurrenceStorage(t)
+	u := &EventUseCase{
+		Transactioner:     newTestTransactioner(t),
+		Events:            events,
+		Members:           members,
+		Occurrences:       occurrences,
+		RecurrenceHorizon: 30 * 24 * time.Hour,
+	}
+	beginsAt := tomorrowAt(10)
+	current := &model.Event{EventID: 1, OrganizationID: 2, BeginsAt: beginsAt, EndsAt: beginsAt.Add(2 * time.Hour), Timezone: "UTC"}
+	rule := "FREQ=WEEKLY;COUNT=3"
+	updated := *current
+	updated.RecurrenceRule = &rule
+
+	events.On("GetById", mock.Anything, int64(1)).Return(current, nil).Once()
+	editor(members, 2, 10)
+	events.On("UpdateEvent", mock.Anything, int64(1), map[string]interface{}{"recurrence_rule": rule}).
+		Return(&updated, nil).Once()
+	occurrences.On("ListOccurrencesSince", mock.Anything, int64(1), mock.Anything).Return([]model.Occurrence{}, nil).Once()
+	occurrences.On("CreateOccurrences", mock.Anything, mock.Anything).Return(nil).Once()
+
+	_, err := u.UpdateEvent(ctx, 10, 1, &model.EventUpdate{RecurrenceRule: &rule})
+	require.NoError(t, err)
+
+	created := occurrences.Calls[1].Arguments.Get(1).([]model.Occurrence)
+	require.Len(t, created, 3)
+	for i, o := range created {
+		start := beginsAt.AddDate(0, 0, 7*i)
+		assert.Equal(t, start, o.RecurrenceID)
+		assert.Equal(t, start, o.BeginsAt)
+		assert.Equal(t, start.Add(2*time.Hour), o.EndsAt)
+	}
+}
+
+func TestEventUseCase_UpdateEvent_InvalidRule(t *testing.T) {
+	ctx := context.Background()
+	events := mocks.NewManagedEventStorage(t)
+	members := mocks.NewEventMemberStorage(t)
+	u := &EventUseCase{Transactioner: newTestTransactioner(t), Events: events, Members: members}
+	current := &model.Event{EventID: 1, OrganizationID: 2, BeginsAt: tomorrowAt(10), Timezone: "UTC"}
+	events.On("GetById", mock.Anything, int64(1)).Return(current, nil).Once()
+	editor(members, 2, 10)
+
+	rule := "FREQ=HOURLY"
+	_, err := u.UpdateEvent(ctx, 10, 1, &model.EventUpdate{RecurrenceRule: &rule})
+	assert.ErrorIs(t, err, ErrBusinessLogicViolation)
+}
+
+func TestEventUseCase_UpdateEvent_ShiftsOccurrences(t *testing.T) {
+	ctx := context.Background()
+	events := mocks.NewManagedEventStorage(t)
+	members := mocks.NewEventMemberStorage(t)
+	occurrences := mocks.NewOccurrenceStorage(t)
+	registrations := mocks.NewRegistrationStorage(t)
+	reminders := mocks.NewReminderPlanner(t)
+	u := &EventUseCase{
+		Transactioner:     newTestTransactioner(t),
+		Events:            events,
+		Members:           members,
+		Occurrences:       occurrences,
+		Registrations:     registrations,
+		Reminders:         reminders,
+		RecurrenceHorizon: 30 * 24 * time.Hour,
+	}
+	beginsAt := tomorrowAt(10)
+	rule := "FREQ=DAILY;COUNT=3"
+	current := &model.Event{
+		EventID: 1, OrganizationID: 2, BeginsAt: beginsAt, EndsAt: beginsAt.Add(2 * time.Hour),
+		RecurrenceRule: &rule, Timezone: "UTC",
+	}
+	newBeginsAt := beginsAt.Add(time.Hour)
+	updated := *current
+	updated.BeginsAt = newBeginsAt
+	day := 24 * time.Hour
+	existing := []model.Occurrence{
+		{OccurrenceID: 11, EventID: 1, RecurrenceID: beginsAt, BeginsAt: beginsAt, EndsAt: beginsAt.Add(2 * time.Hour)},
+		{
+			OccurrenceID: 12, EventID: 1, RecurrenceID: beginsAt.Add(day),
+			BeginsAt: beginsAt.Add(day + 5*time.Hour), EndsAt: beginsAt.Add(day + 6*time.Hour), Overridden: true,
+		},
+		{OccurrenceID: 13, EventID: 1, RecurrenceID: beginsAt.Add(2 * day), BeginsAt: beginsAt.Add(2 * day), EndsAt: beginsAt.Add(2*day + 2*time.Hour)},
+	}
+
+	events.On("GetById", mock.Anything, int64(1)).Return(current, nil).Once()
+	editor(members, 2, 10)
+	events.On("UpdateEvent", mock.Anything, int64(1), map[string]interface{}{"begins_at": newBeginsAt}).
+		Return(&updated, nil).Once()
+	reminders.On("ReplanEvent", mock.Anything, int64(1)).Return(nil).Once()
+	occurrences.On("ListOccurrencesSince", mock.Anything, int64(1), mock.Anything).Return(existing, nil).Once()
+	occurrences.On("UpdateOccurrence", mock.Anything, int64(13), map[string]interface{}{
+		"recurrence_id": beginsAt.Add(2*day + time.Hour),
+		"begins_at":     beginsAt.Add(2*day + time.Hour),
+		"ends_at":       beginsAt.Add(2*day + 2*time.Hour),
+	}).Return(&model.Occurrence{}, nil).Once()
+	occurrences.On("UpdateOccurrence", mock.Anything, int64(12), map[string]interface{}{
+		"recurrence_id": beginsAt.Add(day + time.Hour),
+		"begins_at":     beginsAt.Add(day + 6*time.Hour),
+		"ends_at":       beginsAt.Add(day + 7*time.Hour),
+	}).Return(&model.Occurrence{}, nil).Once()
+	occurrences.On("UpdateOccurrence", mock.Anything, int64(11), map[string]interface{}{
+		"recurrence_id": newBeginsAt,
+		"begins_at":     newBeginsAt,
+		"ends_at":       newBeginsAt.Add(time.Hour),
+	}).Return(&model.Occurrence{}, nil).Once()
+	registrations.On("ListRegistrants", mock.Anything, int64(1)).Return([]model.User{}, nil).Once()
+
+	_, err := u.UpdateEvent(ctx, 10, 1, &model.EventUpdate{BeginsAt: &newBeginsAt})
+	require.NoError(t, err)
+
+	var moved []int64
+	for _, call := range occurrences.Calls {
+		if call.Method == "UpdateOccurrence" {
+			moved = append(moved, call.Arguments.Get(1).(int64))
+		}
+	}
+	assert.Equal(t, []int64{13, 12, 11}, moved, "occurrences should be moved from the far end")
+	occurrences.AssertNotCalled(t, "CreateOccurrences", mock.Anything, mock.Anything)
+}
+
+func TestEventUseCase_RegisterForEvent_Occurrence(t *testing.T) {
+	ctx := context.Background()
+	events := mocks.NewManagedEventStorage(t)
+	occurrences := mocks.NewOccurrenceStorage(t)
+	registrations := mocks.NewRegistrationStorage(t)
+	users := mocks.NewChannelUserStorage(t)
+	notifier := mocks.NewUserNotifier(t)
+	webhooks := mocks.NewWebhookEmitter(t)
+	tickets := mocks.NewEventTicketStorage(t)
+	u := &EventUseCase{
+		Transactioner: newTestTransactioner(t),
+		Events:        events,
+		Occurrences:   occurrences,
+		Registrations: registrations,
+		Users:         users,
+		Notifier:      notifier,
+		Webhooks:      webhooks,
+		Tickets:       tickets,
+	}
+	publishedAt := time.Now().UTC()
+	beginsAt := tomorrowAt(10)
+	rule := "FREQ=WEEKLY"
+	event := &model.Event{
+		EventID: 1, OrganizationID: 2, BeginsAt: beginsAt, EndsAt: beginsAt.Add(time.Hour),
+		PublishedAt: &publishedAt, RecurrenceRule: &rule, Timezone: "UTC",
+	}
+	occurrence := &model.Occurrence{
+		OccurrenceID: 5, EventID: 1, RecurrenceID: beginsAt.AddDate(0, 0, 7),
+		BeginsAt: beginsAt.AddDate(0, 0, 7), EndsAt: beginsAt.AddDate(0, 0, 7).Add(time.Hour),
+	}
+	cancelledAt := publishedAt
+	cancelled := &model.Occurrence{OccurrenceID: 6, EventID: 1, CancelledAt: &cancelledAt}
+	user := &model.User{UserID: 3}
+	occurrenceId := occurrence.OccurrenceID
+	expected := &model.Registration{EventID: 1, UserID: 3, OccurrenceID: &occurrenceId}
+
+	events.On("GetById", mock.Anything, int64(1)).Return(event, nil)
+	_, err := u.RegisterForEvent(ctx, user.UserID, 1, &model.RegistrationTarget{})
+	assert.ErrorIs(t, err, ErrBusinessLogicViolation, "occurrence should be required for recurring event")
+
+	occurrences.On("GetOccurrence", mock.Anything, int64(1), int64(6)).Return(cancelled, nil).Once()
+	_, err = u.RegisterForEvent(ctx, user.UserID, 1, &model.RegistrationTarget{OccurrenceID: 6})
+	assert.ErrorIs(t, err, ErrBusinessLogicViolation, "cancelled occurrence should not be open for registration")
+
+	occurrences.On("GetOccurrence", mock.Anything, int64(1), int64(5)).Return(occurrence, nil).Once()
+	tickets.On("ListTicketTypes", mock.Anything, int64(1)).Return([]model.TicketType{}, nil).Once()
+	registrations.On("Register", mock.Anything, int64(1), user.UserID, &occurrenceId, (*string)(nil)).Return(expected, nil).Once()
+	users.On("GetById", mock.Anything, user.UserID).Return(user, nil).Once()
+	webhooks.On("Emit", mock.Anything, int64(2), model.WebhookRegistrationCreated, mock.Anything).Return(nil).Once()
+	notifier.On("SendToAll", mock.Anything, user, model.MessageRegistrationConfirmed, mock.Anything).Return(nil).Once()
+
+	registration, err := u.RegisterForEvent(ctx, user.UserID, 1, &model.RegistrationTarget{OccurrenceID: 5})
+	require.NoError(t, err)
+	assert.Equal(t, expected, registration)
+	confirmed := notifier.Calls[0].Arguments.Get(3).(registrationConfirmedContext)
+	assert.Equal(t, occurrence.BeginsAt, confirmed.Event.BeginsAt, "confirmation should have time of the occurrence")
+}
+
+func TestEventUseCase_UpdateOccurrence_This(t *testing.T) {
+	ctx := context.Background()
+	events := mocks.NewManagedEventStorage(t)
+	members := mocks.NewEventMemberStorage(t)
+	occurrences := mocks.NewOccurrenceStorage(t)
+	registrations := mocks.NewRegistrationStorage(t)
+	reminders := mocks.NewReminderPlanner(t)
+	notifier := mocks.NewUserNotifier(t)
+	u := &EventUseCase{
+		Transactioner: newTestTransactioner(t),
+		Events:        events,
+		Members:       members,
+		Occurrences:   occurrences,
+		Registrations: registrations,
+		Reminders:     reminders,
+		Notifier:      notifier,
+	}
+	beginsAt := tomorrowAt(10)
+	rule := "FREQ=DAILY"
+	event := &model.Event{EventID: 1, OrganizationID: 2, BeginsAt: beginsAt, RecurrenceRule: &rule, Timezone: "UTC"}
+	current := &model.Occurrence{OccurrenceID: 5, EventID: 1, RecurrenceID: beginsAt, BeginsAt: beginsAt, EndsAt: beginsAt.Add(time.Hour)}
+	newBeginsAt := beginsAt.Add(30 * time.Minute)
+	updated := *current
+	updated.BeginsAt, updated.Overridden = newBeginsAt, true
+	registrant := model.User{UserID: 7}
+
+	events.On("GetById", mock.Anything, int64(1)).Return(event, nil).Once()
+	editor(members, 2, 10)
+	occurrences.On("GetOccurrence", mock.Anything, int64(1), int64(5)).Return(current, nil).Once()
+	occurrences.On("UpdateOccurrence", mock.Anything, int64(5), map[string]interface{}{
+		"overridden": true,
+		"begins_at":  newBeginsAt,
+	}).Return(&updated, nil).Once()
+	events.On("Touch", mock.Anything, int64(1)).Return(nil).Once()
+	reminders.On("ReplanEvent", mock.Anything, int64(1)).Return(nil).Once()
+	registrations.On("ListOccurrenceRegistrants", mock.Anything, int64(5)).Return([]model.User{registrant}, nil).Once()
+	notifier.On("SendToAll", mock.Anything, &registrant, model.MessageEventChanged, mock.Anything).Return(nil).Once()
+
+	occurrence, err := u.UpdateOccurrence(ctx, 10, 1, 5, model.OccurrenceScopeThis, &model.OccurrenceUpdate{BeginsAt: &newBeginsAt})
+	require.NoError(t, err)
+	assert.True(t, occurrence.Overridden)
+	registrations.AssertNotCalled(t, "ListRegistrants", mock.Anything, mock.Anything)
+}
+
+func TestEventUseCase_UpdateOccurrence_FollowingSplitsSeries(t *testing.T) {
+	ctx := context.Background()
+	events := mocks.NewManagedEventStorage(t)
+	members := mocks.NewEventMemberStorage(t)
+	tags := mocks.NewEventTagStorage(t)
+	occurrences := mocks.NewOccurrenceStorage(t)
+	registrations := mocks.NewRegistrationStorage(t)
+	reminders := mocks.NewReminderPlanner(t)
+	u := &EventUseCase{
+		Transactioner:     newTestTransactioner(t),
+		Events:            events,
+		Members:           members,
+		Tags:              tags,
+		Occurrences:       occurrences,
+		Registrations:     registrations,
+		Reminders:         reminders,
+		RecurrenceHorizon: 30 * 24 * time.Hour,
+	}
+	beginsAt := tomorrowAt(10)
+	day := 24 * time.Hour
+	rule := "FREQ=DAILY;COUNT=5"
+	event := &model.Event{
+		EventID: 1, OrganizationID: 2, Name: "Club", BeginsAt: beginsAt, EndsAt: beginsAt.Add(time.Hour),
+		RecurrenceRule: &rule, Timezone: "UTC",
+	}
+	pivot := beginsAt.Add(2 * day)
+	current := &model.Occurrence{OccurrenceID: 13, EventID: 1, RecurrenceID: pivot, BeginsAt: pivot, EndsAt: pivot.Add(time.Hour)}
+	newBeginsAt := pivot.Add(time.Hour)
+	followingRule := "FREQ=DAILY;COUNT=3"
+	series := &model.Event{
+		EventID: 9, OrganizationID: 2, Name: "Club", BeginsAt: newBeginsAt, EndsAt: newBeginsAt.Add(time.Hour),
+		RecurrenceRule: &followingRule, Timezone: "UTC",
+	}
+	var moved []model.Occurrence
+	for i := 0; i < 3; i++ {
+		start := pivot.Add(time.Duration(i) * day)
+		moved = append(moved, model.Occurrence{
+			OccurrenceID: int64(13 + i), EventID: 9, RecurrenceID: start, BeginsAt: start, EndsAt: start.Add(time.Hour),
+		})
+	}
+
+	events.On("GetById", mock.Anything, int64(1)).Return(event, nil).Once()
+	editor(members, 2, 10)
+	occurrences.On("GetOccurrence", mock.Anything, int64(1), int64(13)).Return(current, nil).Once()
+	events.On("Create", mock.Anything, mock.MatchedBy(func(create *model.EventCreate) bool {
+		return *create.RecurrenceRule == followingRule && create.BeginsAt.Equal(newBeginsAt) && create.Name == "Club"
+	})).Return(series, nil).Once()
+	tags.On("ListTags", mock.Anything, []int64{1}).Return(map[int64][]string{1: {"free"}}, nil).Once()
+	tags.On("SetTags", mock.Anything, int64(9), []string{"free"}).Return(nil).Once()
+	events.On("UpdateEvent", mock.Anything, int64(1), mock.MatchedBy(func(updates map[string]interface{}) bool {
+		return updates["recurrence_rule"] == "FREQ=DAILY;UNTIL="+pivot.Add(-time.Second).Format("20060102T150405Z")
+	})).Return(event, nil).Once()
+	occurrences.On("MoveOccurrences", mock.Anything, int64(1), int64(9), pivot).Return(nil).Once()
+	occurrences.On("ListOccurrencesSince", mock.Anything, int64(9), mock.Anything).Return(moved, nil).Once()
+	occurrences.On("UpdateOccurrence", mock.Anything, mock.Anything, mock.MatchedBy(func(updates map[string]interface{}) bool {
+		return updates["recurrence_id"].(time.Time).Hour() == 11
+	})).Return(&model.Occurrence{}, nil).Times(3)
+	reminders.On("ReplanEvent", mock.Anything, int64(9)).Return(nil).Once()
+	movedPivot := moved[0]
+	movedPivot.RecurrenceID, movedPivot.BeginsAt, movedPivot.EndsAt = newBeginsAt, newBeginsAt, newBeginsAt.Add(time.Hour)
+	occurrences.On("GetOccurrence", mock.Anything, int64(9), int64(13)).Return(&movedPivot, nil).Once()
+	registrations.On("ListRegistrants", mock.Anything, int64(9)).Return([]model.User{}, nil).Once()
+
+	occurrence, err := u.UpdateOccurrence(ctx, 10, 1, 13, model.OccurrenceScopeFollowing, &model.OccurrenceUpdate{BeginsAt: &newBeginsAt})
+	require.NoError(t, err)
+	assert.Equal(t, int64(9), occurrence.EventID)
+	events.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything)
+}
+
+func TestEventUseCase_ExtendOccurrences_NotifiesCancelled(t *testing.T) {
+	ctx := context.Background()
+	events := mocks.NewManagedEventStorage(t)
+	occurrences := mocks.NewOccurrenceStorage(t)
+	registrations := mocks.NewRegistrationStorage(t)
+	notifier := mocks.NewUserNotifier(t)
+	u := &EventUseCase{
+		Transactioner:     newTestTransactioner(t),
+		Events:            events,
+		Occurrences:       occurrences,
+		Registrations:     registrations,
+		Notifier:          notifier,
+		RecurrenceHorizon: 30 * 24 * time.Hour,
+	}
+	beginsAt := tomorrowAt(10)
+	rule := "FREQ=DAILY;COUNT=1"
+	event := model.Event{EventID: 1, BeginsAt: beginsAt, EndsAt: beginsAt.Add(time.Hour), RecurrenceRule: &rule, Timezone: "UTC"}
+	kept := model.Occurrence{OccurrenceID: 5, EventID: 1, RecurrenceID: beginsAt, BeginsAt: beginsAt, EndsAt: beginsAt.Add(time.Hour)}
+	removedAt := beginsAt.Add(24 * time.Hour)
+	removed := model.Occurrence{OccurrenceID: 6, EventID: 1, RecurrenceID: removedAt, BeginsAt: removedAt, EndsAt: removedAt.Add(time.Hour)}
+	registrant := model.User{UserID: 7}
+
+	events.On("SelectBy", mock.Anything, mock.Anything).Return([]model.Event{event}, nil).Once()
+	occurrences.On("ListOccurrencesSince", mock.Anything, event.EventID, mock.Anything).
+		Return([]model.Occurrence{kept, removed}, nil).Once()
+	occurrences.On("RemoveOccurrences", mock.Anything, []int64{removed.OccurrenceID}).
+		Return([]int64{removed.OccurrenceID}, nil).Once()
+	registrations.On("ListOccurrenceRegistrants", mock.Anything, removed.OccurrenceID).Return([]model.User{registrant}, nil).Once()
+	notifier.On("SendToAll", mock.Anything, &registrant, model.MessageEventCancelled,
+		mock.MatchedBy(func(data eventCancelledContext) bool {
+			return data.Event.BeginsAt.Equal(removedAt)
+		})).Return(nil).Once()
+
+	assert.NoError(t, u.ExtendOccurrences(ctx), "registrants of occurrences dropped from the series should be notified")
+}
+
+func TestEventUseCase_ExtendOccurrences_SkipsBroken(t *testing.T) {
+	ctx := context.Background()
+	events := mocks.NewManagedEventStorage(t)
+	occurrences := mocks.NewOccurrenceStorage(t)
+	u := &EventUseCase{
+		Transactioner:     newTestTransactioner(t),
+		Events:            events,
+		Occurrences:       occurrences,
+		Logger:            newTestLogger(),
+		RecurrenceHorizon: 30 * 24 * time.Hour,
+	}
+	beginsAt := tomorrowAt(10)
+	rule := "FREQ=DAILY;COUNT=1"
+	broken := model.Event{EventID: 1, BeginsAt: beginsAt, EndsAt: beginsAt.Add(time.Hour), RecurrenceRule: &rule, Timezone: "Nowhere/Unknown"}
+	valid := model.Event{EventID: 2, BeginsAt: beginsAt, EndsAt: beginsAt.Add(time.Hour), RecurrenceRule: &rule, Timezone: "UTC"}
+
+	events.On("SelectBy", mock.Anything, mock.Anything).Return([]model.Event{broken, valid}, nil).Once()
+	occurrences.On("ListOccurrencesSince", mock.Anything, valid.EventID, mock.Anything).Return(nil, nil).Once()
+	occurrences.On("CreateOccurrences", mock.Anything, []model.Occurrence{{
+		EventID:      valid.EventID,
+		RecurrenceID: beginsAt,
+		BeginsAt:     beginsAt,
+		EndsAt:       beginsAt.Add(time.Hour),
+	}}).Return(nil).Once()
+
+	assert.NoError(t, u.ExtendOccurrences(ctx), "a broken series should not block the rest")
+}
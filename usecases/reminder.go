@@ -0,0 +1,141 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"github.com/burenotti/rtu-it-lab-recruit/model"
+	"github.com/burenotti/rtu-it-lab-recruit/repositories"
+	"github.com/sirupsen/logrus"
+	"time"
+)
+
+type ReminderStorage interface {
+	PlanReminders(ctx context.Context, offsets []time.Duration) error
+	ReplanEventReminders(ctx context.Context, eventId int64, offsets []time.Duration) error
+	ClaimDueReminder(ctx context.Context) (*model.EventReminder, error)
+	MarkReminderSent(ctx context.Context, reminderId int64) error
+}
+
+type RegistrantStorage interface {
+	ListRegistrants(ctx context.Context, eventId int64) ([]model.User, error)
+	ListOccurrenceRegistrants(ctx context.Context, occurrenceId int64) ([]model.User, error)
+}
+
+type ReminderOccurrenceStorage interface {
+	GetOccurrence(ctx context.Context, eventId, occurrenceId int64) (*model.Occurrence, error)
+}
+
+type EventGetter interface {
+	GetById(ctx context.Context, eventId int64) (*model.Event, error)
+}
+
+type UserNotifier interface {
+	SendToAll(ctx context.Context, user *model.User, kind string, data interface{}) error
+}
+
+// ReminderUseCase reminds registered users about events at configured offsets before the event begins.
+type ReminderUseCase struct {
+	Transactioner StorageTransactioner
+	Reminders     ReminderStorage
+	Registrations RegistrantStorage
+	Events        EventGetter
+	Occurrences   ReminderOccurrenceStorage
+	Notifier      UserNotifier
+	Logger        *logrus.Logger
+	// Offsets are durations before the event begins when reminders are sent, e.g. 24h and 1h.
+	Offsets []time.Duration
+}
+
+type reminderContext struct {
+	User     *model.User
+	Event    *model.Event
+	StartsIn time.Duration
+}
+
+// ReplanEvent plans reminders of the event again. Call it in the same transaction with the change of event time.
+func (u *ReminderUseCase) ReplanEvent(ctx context.Context, eventId int64) error {
+	return u.Reminders.ReplanEventReminders(ctx, eventId, u.Offsets)
+}
+
+// DispatchReminders plans reminders of new events and sends due ones.
+// Each reminder is claimed with row lock and messages are put to the outbox in the same transaction
+// the reminder is marked as sent, so every reminder is sent once even with several replicas.
+func (u *ReminderUseCase) DispatchReminders(ctx context.Context) error {
+	if err := u.Reminders.PlanReminders(ctx, u.Offsets); err != nil {
+		return err
+	}
+	for ctx.Err() == nil {
+		err := u.Transactioner.Atomic(ctx, func(ctx context.Context) error {
+			reminder, err := u.Reminders.ClaimDueReminder(ctx)
+			if err != nil {
+				return err
+			}
+			if err = u.sendReminder(ctx, reminder); err != nil {
+				return err
+			}
+			return u.Reminders.MarkReminderSent(ctx, reminder.ReminderID)
+		})
+		if errors.Is(err, repositories.ErrReminderNotFound) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+	}
+	return ctx.Err()
+}
+
+func (u *ReminderUseCase) sendReminder(ctx context.Context, reminder *model.EventReminder) error {
+	event, err := u.Events.GetById(ctx, reminder.EventID)
+	if err != nil {
+		return err
+	}
+	if event.IsHidden() || event.IsCancelled() {
+		return nil
+	}
+	if reminder.OccurrenceID != nil {
+		occurrence, err := u.Occurrences.GetOccurrence(ctx, event.EventID, *reminder.OccurrenceID)
+		if errors.Is(err, repositories.ErrOccurrenceNotFound) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if occurrence.IsCancelled() {
+			return nil
+		}
+		event = eventOccurrence(event, occurrence)
+	} else if event.IsRecurring() {
+		// The event became recurring after the reminder was planned, its occurrences have their own reminders.
+		return nil
+	}
+	// Reminders could be overdue if the dispatcher was down. There is no point in reminding about started events.
+	startsIn := time.Until(event.BeginsAt)
+	if startsIn <= 0 {
+		return nil
+	}
+
+	var registrants []model.User
+	if reminder.OccurrenceID != nil {
+		registrants, err = u.Registrations.ListOccurrenceRegistrants(ctx, *reminder.OccurrenceID)
+	} else {
+		registrants, err = u.Registrations.ListRegistrants(ctx, event.EventID)
+	}
+	if err != nil {
+		return err
+	}
+	for i := range registrants {
+		user := &registrants[i]
+		err = u.Notifier.SendToAll(ctx, user, model.MessageEventReminder, reminderContext{
+			User:     user,
+			Event:    event,
+			StartsIn: startsIn.Round(time.Minute),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	u.Logger.
+		WithField("event_id", event.EventID).
+		WithField("offset", reminder.Offset.String()).
+		Infof("Sent %s reminder of event %d to %d users", reminder.Offset, event.EventID, len(registrants))
+	return nil
+}
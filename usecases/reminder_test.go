@@ -0,0 +1,137 @@
+package usecases
+
+import (
+	"context"
+	"github.com/burenotti/rtu-it-lab-recruit/model"
+	"github.com/burenotti/rtu-it-lab-recruit/repositories"
+	"github.com/burenotti/rtu-it-lab-recruit/usecases/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+	"time"
+)
+
+func TestReminderUseCase_DispatchReminders(t *testing.T) {
+	ctx := context.Background()
+	reminders := mocks.NewReminderStorage(t)
+	registrations := mocks.NewRegistrantStorage(t)
+	events := mocks.NewEventGetter(t)
+	notifier := mocks.NewUserNotifier(t)
+	u := &ReminderUseCase{
+		Transactioner: newTestTransactioner(t),
+		Reminders:     reminders,
+		Registrations: registrations,
+		Events:        events,
+		Notifier:      notifier,
+		Logger:        newTestLogger(),
+		Offsets:       []time.Duration{24 * time.Hour, time.Hour},
+	}
+	event := &model.Event{EventID: 1, Name: "Lecture", BeginsAt: time.Now().Add(time.Hour)}
+	users := []model.User{{UserID: 1}, {UserID: 2}}
+
+	reminders.On("PlanReminders", mock.Anything, u.Offsets).Return(nil)
+	reminders.On("ClaimDueReminder", mock.Anything).
+		Return(&model.EventReminder{ReminderID: 10, EventID: event.EventID, Offset: time.Hour}, nil).Once()
+	reminders.On("ClaimDueReminder", mock.Anything).Return(nil, repositories.ErrReminderNotFound).Once()
+	events.On("GetById", mock.Anything, event.EventID).Return(event, nil)
+	registrations.On("ListRegistrants", mock.Anything, event.EventID).Return(users, nil)
+	notifier.On("SendToAll", mock.Anything, mock.Anything, model.MessageEventReminder, mock.Anything).Return(nil).Twice()
+	reminders.On("MarkReminderSent", mock.Anything, int64(10)).Return(nil).Once()
+
+	assert.NoError(t, u.DispatchReminders(ctx))
+}
+
+func TestReminderUseCase_DispatchReminders_EventStarted(t *testing.T) {
+	ctx := context.Background()
+	reminders := mocks.NewReminderStorage(t)
+	events := mocks.NewEventGetter(t)
+	u := &ReminderUseCase{
+		Transactioner: newTestTransactioner(t),
+		Reminders:     reminders,
+		Events:        events,
+		Offsets:       []time.Duration{24 * time.Hour, time.Hour},
+	}
+	event := &model.Event{EventID: 1, BeginsAt: time.Now().Add(-time.Minute)}
+
+	reminders.On("PlanReminders", mock.Anything, u.Offsets).Return(nil)
+	reminders.On("ClaimDueReminder", mock.Anything).
+		Return(&model.EventReminder{ReminderID: 10, EventID: event.EventID}, nil).Once()
+	reminders.On("ClaimDueReminder", mock.Anything).Return(nil, repositories.ErrReminderNotFound).Once()
+	events.On("GetById", mock.Anything, event.EventID).Return(event, nil)
+	reminders.On("MarkReminderSent", mock.Anything, int64(10)).Return(nil).Once()
+
+	assert.NoError(t, u.DispatchReminders(ctx), "overdue reminders should be skipped without sending")
+}
+
+func TestReminderUseCase_DispatchReminders_Occurrence(t *testing.T) {
+	ctx := context.Background()
+	reminders := mocks.NewReminderStorage(t)
+	registrations := mocks.NewRegistrantStorage(t)
+	events := mocks.NewEventGetter(t)
+	occurrences := mocks.NewReminderOccurrenceStorage(t)
+	notifier := mocks.NewUserNotifier(t)
+	u := &ReminderUseCase{
+		Transactioner: newTestTransactioner(t),
+		Reminders:     reminders,
+		Registrations: registrations,
+		Events:        events,
+		Occurrences:   occurrences,
+		Notifier:      notifier,
+		Logger:        newTestLogger(),
+		Offsets:       []time.Duration{24 * time.Hour, time.Hour},
+	}
+	rule := "FREQ=WEEKLY"
+	event := &model.Event{EventID: 1, Name: "Club meeting", BeginsAt: time.Now().Add(-7 * 24 * time.Hour), RecurrenceRule: &rule}
+	occurrence := &model.Occurrence{OccurrenceID: 5, EventID: event.EventID, BeginsAt: time.Now().Add(time.Hour)}
+	user := model.User{UserID: 1}
+
+	reminders.On("PlanReminders", mock.Anything, u.Offsets).Return(nil)
+	reminders.On("ClaimDueReminder", mock.Anything).Return(&model.EventReminder{
+		ReminderID:   10,
+		EventID:      event.EventID,
+		OccurrenceID: &occurrence.OccurrenceID,
+		Offset:       time.Hour,
+	}, nil).Once()
+	reminders.On("ClaimDueReminder", mock.Anything).Return(nil, repositories.ErrReminderNotFound).Once()
+	events.On("GetById", mock.Anything, event.EventID).Return(event, nil)
+	occurrences.On("GetOccurrence", mock.Anything, event.EventID, occurrence.OccurrenceID).Return(occurrence, nil)
+	registrations.On("ListOccurrenceRegistrants", mock.Anything, occurrence.OccurrenceID).Return([]model.User{user}, nil)
+	notifier.On("SendToAll", mock.Anything, &user, model.MessageEventReminder, mock.MatchedBy(func(data reminderContext) bool {
+		return data.Event.BeginsAt.Equal(occurrence.BeginsAt) && data.StartsIn == time.Hour
+	})).Return(nil).Once()
+	reminders.On("MarkReminderSent", mock.Anything, int64(10)).Return(nil).Once()
+
+	assert.NoError(t, u.DispatchReminders(ctx), "registrants of the occurrence should be reminded about its time")
+	registrations.AssertNotCalled(t, "ListRegistrants", mock.Anything, mock.Anything)
+}
+
+func TestReminderUseCase_DispatchReminders_CancelledOccurrence(t *testing.T) {
+	ctx := context.Background()
+	reminders := mocks.NewReminderStorage(t)
+	events := mocks.NewEventGetter(t)
+	occurrences := mocks.NewReminderOccurrenceStorage(t)
+	u := &ReminderUseCase{
+		Transactioner: newTestTransactioner(t),
+		Reminders:     reminders,
+		Events:        events,
+		Occurrences:   occurrences,
+		Offsets:       []time.Duration{24 * time.Hour, time.Hour},
+	}
+	rule := "FREQ=WEEKLY"
+	event := &model.Event{EventID: 1, RecurrenceRule: &rule}
+	now := time.Now()
+	occurrence := &model.Occurrence{OccurrenceID: 5, EventID: event.EventID, BeginsAt: now.Add(time.Hour), CancelledAt: &now}
+
+	reminders.On("PlanReminders", mock.Anything, u.Offsets).Return(nil)
+	reminders.On("ClaimDueReminder", mock.Anything).Return(&model.EventReminder{
+		ReminderID:   10,
+		EventID:      event.EventID,
+		OccurrenceID: &occurrence.OccurrenceID,
+	}, nil).Once()
+	reminders.On("ClaimDueReminder", mock.Anything).Return(nil, repositories.ErrReminderNotFound).Once()
+	events.On("GetById", mock.Anything, event.EventID).Return(event, nil)
+	occurrences.On("GetOccurrence", mock.Anything, event.EventID, occurrence.OccurrenceID).Return(occurrence, nil)
+	reminders.On("MarkReminderSent", mock.Anything, int64(10)).Return(nil).Once()
+
+	assert.NoError(t, u.DispatchReminders(ctx), "cancelled occurrences should not be reminded about")
+}
@@ -0,0 +1,351 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/burenotti/rtu-it-lab-recruit/model"
+	"github.com/burenotti/rtu-it-lab-recruit/repositories"
+	"github.com/sirupsen/logrus"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	maxSearchFilterDepth = 4
+	maxSearchFilterNodes = 32
+	maxSearchTextLength  = 256
+	maxSearchesPerUser   = 20
+	maxSearchTagLength   = 32
+	// maxSearchRadius is the radius limit of event search by location, in meters.
+	maxSearchRadius = 100000
+)
+
+var ErrInvalidSearchFilter = errors.New("invalid search filter")
+
+type SavedSearchStorage interface {
+	CreateSearch(ctx context.Context, search *model.SavedSearch) (*model.SavedSearch, error)
+	ListSearches(ctx context.Context, userId int64) ([]model.SavedSearch, error)
+	GetSearch(ctx context.Context, userId, searchId int64) (*model.SavedSearch, error)
+	UpdateSearch(ctx context.Context, userId, searchId int64, updates map[string]interface{}) (*model.SavedSearch, error)
+	DeleteSearch(ctx context.Context, userId, searchId int64) error
+	DisableAlertsByToken(ctx context.Context, token string) (*model.SavedSearch, error)
+}
+
+type SearchAlertStorage interface {
+	ListAlertSearches(ctx context.Context, afterSearchId int64, limit int) ([]model.SavedSearch, error)
+	ClaimSearchAlertJob(ctx context.Context) (*model.SearchAlertJob, error)
+	AdvanceSearchAlertJob(ctx context.Context, jobId, lastSearchId int64) error
+	CompleteSearchAlertJob(ctx context.Context, jobId int64) error
+}
+
+type SearchEventStorage interface {
+	GetById(ctx context.Context, eventId int64) (*model.Event, error)
+	SelectBy(ctx context.Context, filter repositories.EventFilter) ([]model.Event, error)
+}
+
+// SearchUseCase implements saved event searches and alerts about published events matching them.
+type SearchUseCase struct {
+	Transactioner StorageTransactioner
+	Searches      SavedSearchStorage
+	Alerts        SearchAlertStorage
+	Events        SearchEventStorage
+	Users         ChannelUserStorage
+	Notifier      UserNotifier
+	Logger        *logrus.Logger
+	// UnsubscribeURL is the address of unsubscribe endpoint, token is appended to it.
+	UnsubscribeURL string
+	// BatchSize is the number of searches matched in a single transaction.
+	BatchSize int
+	// BatchesPerRun limits batches processed by a single run, the rest of searches are matched by the next runs.
+	BatchesPerRun int
+}
+
+type searchAlertContext struct {
+	User           *model.User
+	Search         *model.SavedSearch
+	Event          *model.Event
+	UnsubscribeURL string
+}
+
+func (u *SearchUseCase) CreateSearch(ctx context.Context, userId int64, create *model.SavedSearchCreate) (*model.SavedSearch, error) {
+	normalizeSearchFilter(&create.Filter)
+	if err := ValidateSearchFilter(&create.Filter); err != nil {
+		return nil, err
+	}
+	token, err := newLinkToken()
+	if err != nil {
+		return nil, err
+	}
+	search := &model.SavedSearch{
+		UserID:           userId,
+		Name:             create.Name,
+		Filter:           create.Filter,
+		AlertsEnabled:    create.AlertsEnabled == nil || *create.AlertsEnabled,
+		UnsubscribeToken: token,
+	}
+
+	err = u.Transactioner.Atomic(ctx, func(ctx context.Context) error {
+		searches, err := u.Searches.ListSearches(ctx, userId)
+		if err != nil {
+			return err
+		}
+		if len(searches) >= maxSearchesPerUser {
+			return fmt.Errorf("%w: no more than %d searches could be saved", ErrBusinessLogicViolation, maxSearchesPerUser)
+		}
+		search, err = u.Searches.CreateSearch(ctx, search)
+		return err
+	})
+	return search, err
+}
+
+func (u *SearchUseCase) ListSearches(ctx context.Context, userId int64) ([]model.SavedSearch, error) {
+	return u.Searches.ListSearches(ctx, userId)
+}
+
+func (u *SearchUseCase) UpdateSearch(
+	ctx context.Context,
+	userId, searchId int64,
+	update *model.SavedSearchUpdate,
+) (*model.SavedSearch, error) {
+	updates := repositories.UpdatesMap{}
+	if update.Name != nil {
+		updates["name"] = *update.Name
+	}
+	if update.AlertsEnabled != nil {
+		updates["alerts_enabled"] = *update.AlertsEnabled
+	}
+	if len(updates) == 0 {
+		return u.Searches.GetSearch(ctx, userId, searchId)
+	}
+	return u.Searches.UpdateSearch(ctx, userId, searchId, updates)
+}
+
+func (u *SearchUseCase) DeleteSearch(ctx context.Context, userId, searchId int64) error {
+	return u.Searches.DeleteSearch(ctx, userId, searchId)
+}
+
+// RunSearch returns upcoming published events matching the saved search.
+func (u *SearchUseCase) RunSearch(ctx context.Context, userId, searchId int64) ([]model.Event, error) {
+	search, err := u.Searches.GetSearch(ctx, userId, searchId)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	return u.Events.SelectBy(ctx, repositories.NewEventAndFilter(
+		repositories.NewEventPublishedFilter(),
+		repositories.NewEventBeginsBetweenFilter(now, now.AddDate(1, 0, 0)),
+		repositories.NewEventSearchFilter(&search.Filter, now),
+	))
+}
+
+// Unsubscribe disables alerts of the search by the token sent in the alert.
+func (u *SearchUseCase) Unsubscribe(ctx context.Context, token string) (*model.SavedSearch, error) {
+	return u.Searches.DisableAlertsByToken(ctx, token)
+}
+
+// DispatchSearchAlerts matches published events against saved searches and alerts owners of matching ones.
+// Each batch is processed in its own transaction with the job locked, so replicas don't alert anyone twice.
+func (u *SearchUseCase) DispatchSearchAlerts(ctx context.Context) error {
+	for batch := 0; batch < u.BatchesPerRun && ctx.Err() == nil; batch++ {
+		err := u.Transactioner.Atomic(ctx, func(ctx context.Context) error {
+			job, err := u.Alerts.ClaimSearchAlertJob(ctx)
+			if err != nil {
+				return err
+			}
+			return u.matchBatch(ctx, job)
+		})
+		if errors.Is(err, repositories.ErrSearchAlertJobNotFound) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+	}
+	return ctx.Err()
+}
+
+func (u *SearchUseCase) matchBatch(ctx context.Context, job *model.SearchAlertJob) error {
+	event, err := u.Events.GetById(ctx, job.EventID)
+	if err != nil {
+		return err
+	}
+	if event.IsHidden() || event.IsCancelled() {
+		return u.Alerts.CompleteSearchAlertJob(ctx, job.JobID)
+	}
+
+	searches, err := u.Alerts.ListAlertSearches(ctx, job.LastSearchID, u.BatchSize)
+	if err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	matched := 0
+	for i := range searches {
+		search := &searches[i]
+		if err := ValidateSearchFilter(&search.Filter); err != nil {
+			// Filters are validated on save, so the search is skipped rather than blocking alerts of others.
+			u.Logger.WithField("search_id", search.SearchID).WithError(err).Warnf("Saved search %d is invalid", search.SearchID)
+			continue
+		}
+		// The event is matched by the same SQL filter the search is run with, so alerts never disagree with results.
+		found, err := u.Events.SelectBy(ctx, repositories.NewEventAndFilter(
+			repositories.NewEventIdFilter(event.EventID),
+			repositories.NewEventSearchFilter(&search.Filter, now),
+		))
+		if err != nil {
+			return err
+		}
+		if len(found) == 0 {
+			continue
+		}
+		if err = u.sendAlert(ctx, search, event); err != nil {
+			return err
+		}
+		matched++
+	}
+
+	u.Logger.
+		WithField("event_id", event.EventID).
+		Infof("Event %d matched %d of %d saved searches", event.EventID, matched, len(searches))
+	if len(searches) < u.BatchSize {
+		return u.Alerts.CompleteSearchAlertJob(ctx, job.JobID)
+	}
+	return u.Alerts.AdvanceSearchAlertJob(ctx, job.JobID, searches[len(searches)-1].SearchID)
+}
+
+func (u *SearchUseCase) sendAlert(ctx context.Context, search *model.SavedSearch, event *model.Event) error {
+	user, err := u.Users.GetById(ctx, search.UserID)
+	if err != nil {
+		return err
+	}
+	return u.Notifier.SendToAll(ctx, user, model.MessageSearchAlert, searchAlertContext{
+		User:           user,
+		Search:         search,
+		Event:          event,
+		UnsubscribeURL: strings.TrimSuffix(u.UnsubscribeURL, "/") + "/" + url.PathEscape(search.UnsubscribeToken),
+	})
+}
+
+// ValidateSearchFilter checks every node of the filter has exactly one condition,
+// and the tree is not too large to be matched against every published event.
+func ValidateSearchFilter(filter *model.SearchFilter) error {
+	nodes := 0
+	return validateSearchFilter(filter, 1, &nodes)
+}
+
+func validateSearchFilter(f *model.SearchFilter, depth int, nodes *int) error {
+	*nodes++
+	if depth > maxSearchFilterDepth {
+		return fmt.Errorf("%w: filter is nested deeper than %d levels", ErrInvalidSearchFilter, maxSearchFilterDepth)
+	}
+	if *nodes > maxSearchFilterNodes {
+		return fmt.Errorf("%w: filter has more than %d conditions", ErrInvalidSearchFilter, maxSearchFilterNodes)
+	}
+
+	set := 0
+	for _, isSet := range []bool{
+		f.And != nil, f.Or != nil, f.Text != nil, f.OrganizationID != nil, f.CategoryID != nil, f.Tag != nil,
+		f.Near != nil, f.InBounds != nil, f.BeginsAfter != nil, f.BeginsBefore != nil, f.BeginsWithinDays != nil,
+	} {
+		if isSet {
+			set++
+		}
+	}
+	if set != 1 {
+		return fmt.Errorf("%w: exactly one condition must be set in each node", ErrInvalidSearchFilter)
+	}
+
+	switch {
+	case f.And != nil || f.Or != nil:
+		children := f.And
+		if f.Or != nil {
+			children = f.Or
+		}
+		if len(children) == 0 {
+			return fmt.Errorf("%w: and/or must contain at least one condition", ErrInvalidSearchFilter)
+		}
+		for i := range children {
+			if err := validateSearchFilter(&children[i], depth+1, nodes); err != nil {
+				return err
+			}
+		}
+	case f.Text != nil:
+		if len(strings.TrimSpace(*f.Text)) == 0 || len(*f.Text) > maxSearchTextLength {
+			return fmt.Errorf("%w: text must be from 1 to %d characters", ErrInvalidSearchFilter, maxSearchTextLength)
+		}
+	case f.Tag != nil:
+		if *f.Tag == "" || len(*f.Tag) > maxSearchTagLength {
+			return fmt.Errorf("%w: tag must be from 1 to %d characters", ErrInvalidSearchFilter, maxSearchTagLength)
+		}
+	case f.Near != nil:
+		if !isValidPoint(f.Near.Latitude, f.Near.Longitude) || f.Near.Radius <= 0 || f.Near.Radius > maxSearchRadius {
+			return fmt.Errorf("%w: near must be a valid point with radius up to %d meters", ErrInvalidSearchFilter, maxSearchRadius)
+		}
+	case f.InBounds != nil:
+		b := f.InBounds
+		if !isValidPoint(b.MinLatitude, b.MinLongitude) || !isValidPoint(b.MaxLatitude, b.MaxLongitude) ||
+			b.MinLatitude > b.MaxLatitude {
+			return fmt.Errorf("%w: in_bounds must be valid points with min_lat not greater than max_lat", ErrInvalidSearchFilter)
+		}
+	case f.BeginsWithinDays != nil:
+		if *f.BeginsWithinDays < 1 || *f.BeginsWithinDays > 366 {
+			return fmt.Errorf("%w: begins_within_days must be from 1 to 366", ErrInvalidSearchFilter)
+		}
+	}
+	return nil
+}
+
+func isValidPoint(latitude, longitude float64) bool {
+	return latitude >= -90 && latitude <= 90 && longitude >= -180 && longitude <= 180
+}
+
+// normalizeSearchFilter normalizes tags of the filter, so they match tags of events.
+func normalizeSearchFilter(f *model.SearchFilter) {
+	if f.Tag != nil {
+		tag := normalizeTag(*f.Tag)
+		f.Tag = &tag
+	}
+	for i := range f.And {
+		normalizeSearchFilter(&f.And[i])
+	}
+	for i := range f.Or {
+		normalizeSearchFilter(&f.Or[i])
+	}
+}
+
+// eventQueryFilter converts the event search query to the search filter. It returns nil if the query has no conditions.
+func eventQueryFilter(query *model.EventQuery) *model.SearchFilter {
+	var conditions []model.SearchFilter
+	if query.Text != "" {
+		conditions = append(conditions, model.SearchFilter{Text: &query.Text})
+	}
+	if query.OrganizationID != 0 {
+		conditions = append(conditions, model.SearchFilter{OrganizationID: &query.OrganizationID})
+	}
+	if query.CategoryID != 0 {
+		conditions = append(conditions, model.SearchFilter{CategoryID: &query.CategoryID})
+	}
+	for _, tag := range normalizeTags(query.Tags) {
+		tag := tag
+		conditions = append(conditions, model.SearchFilter{Tag: &tag})
+	}
+	if query.Latitude != nil {
+		conditions = append(conditions, model.SearchFilter{Near: &model.GeoCircle{
+			Latitude:  *query.Latitude,
+			Longitude: *query.Longitude,
+			Radius:    *query.Radius,
+		}})
+	}
+	if query.MinLatitude != nil {
+		conditions = append(conditions, model.SearchFilter{InBounds: &model.GeoBounds{
+			MinLatitude:  *query.MinLatitude,
+			MinLongitude: *query.MinLongitude,
+			MaxLatitude:  *query.MaxLatitude,
+			MaxLongitude: *query.MaxLongitude,
+		}})
+	}
+	if len(conditions) == 0 {
+		return nil
+	}
+	return &model.SearchFilter{And: conditions}
+}
@@ -0,0 +1,129 @@
+package usecases
+
+import (
+	"context"
+	"github.com/burenotti/rtu-it-lab-recruit/model"
+	"github.com/burenotti/rtu-it-lab-recruit/repositories"
+	"github.com/burenotti/rtu-it-lab-recruit/usecases/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestValidateSearchFilter(t *testing.T) {
+	text := "concert"
+	days := 30
+	orgId := int64(1)
+
+	assert.NoError(t, ValidateSearchFilter(&model.SearchFilter{
+		And: []model.SearchFilter{{Text: &text}, {BeginsWithinDays: &days}},
+	}))
+	assert.ErrorIs(t, ValidateSearchFilter(&model.SearchFilter{}), ErrInvalidSearchFilter,
+		"empty node should be rejected")
+	assert.ErrorIs(t, ValidateSearchFilter(&model.SearchFilter{Text: &text, OrganizationID: &orgId}),
+		ErrInvalidSearchFilter, "node with several conditions should be rejected")
+	assert.ErrorIs(t, ValidateSearchFilter(&model.SearchFilter{Or: []model.SearchFilter{}}),
+		ErrInvalidSearchFilter, "empty or should be rejected")
+
+	deep := model.SearchFilter{Text: &text}
+	for i := 0; i < maxSearchFilterDepth; i++ {
+		deep = model.SearchFilter{And: []model.SearchFilter{deep}}
+	}
+	assert.ErrorIs(t, ValidateSearchFilter(&deep), ErrInvalidSearchFilter, "too deep filter should be rejected")
+}
+
+func TestValidateSearchFilter_Location(t *testing.T) {
+	tag := "free"
+	assert.NoError(t, ValidateSearchFilter(&model.SearchFilter{
+		And: []model.SearchFilter{
+			{Tag: &tag},
+			{Near: &model.GeoCircle{Latitude: 55.75, Longitude: 37.61, Radius: 2000}},
+		},
+	}))
+	assert.ErrorIs(t, ValidateSearchFilter(&model.SearchFilter{
+		Near: &model.GeoCircle{Latitude: 91, Longitude: 37.61, Radius: 2000},
+	}), ErrInvalidSearchFilter, "point out of range should be rejected")
+	assert.ErrorIs(t, ValidateSearchFilter(&model.SearchFilter{
+		InBounds: &model.GeoBounds{MinLatitude: 56, MinLongitude: 37, MaxLatitude: 55, MaxLongitude: 38},
+	}), ErrInvalidSearchFilter, "box with min_lat greater than max_lat should be rejected")
+}
+
+func TestEventQueryFilter(t *testing.T) {
+	lat, lon, radius := 55.75, 37.61, 2000.0
+	filter := eventQueryFilter(&model.EventQuery{
+		Text:       "concert",
+		CategoryID: 2,
+		Tags:       []string{"#Free", "free", "kids"},
+		Latitude:   &lat,
+		Longitude:  &lon,
+		Radius:     &radius,
+	})
+	require.NotNil(t, filter)
+	require.NoError(t, ValidateSearchFilter(filter), "query should convert to a valid search filter")
+	require.Len(t, filter.And, 5)
+	assert.Equal(t, "concert", *filter.And[0].Text)
+	assert.Equal(t, int64(2), *filter.And[1].CategoryID)
+	assert.Equal(t, "free", *filter.And[2].Tag)
+	assert.Equal(t, "kids", *filter.And[3].Tag)
+	assert.Equal(t, &model.GeoCircle{Latitude: lat, Longitude: lon, Radius: radius}, filter.And[4].Near)
+
+	assert.Nil(t, eventQueryFilter(&model.EventQuery{Limit: 10}), "query without conditions should not filter events")
+}
+
+func TestNormalizeSearchFilter(t *testing.T) {
+	tag := " #Kids"
+	filter := &model.SearchFilter{Or: []model.SearchFilter{{Tag: &tag}}}
+	normalizeSearchFilter(filter)
+	assert.Equal(t, "kids", *filter.Or[0].Tag)
+}
+
+func TestSearchUseCase_DispatchSearchAlerts(t *testing.T) {
+	ctx := context.Background()
+	alerts := mocks.NewSearchAlertStorage(t)
+	events := mocks.NewSearchEventStorage(t)
+	users := mocks.NewChannelUserStorage(t)
+	notifier := mocks.NewUserNotifier(t)
+	u := &SearchUseCase{
+		Transactioner:  newTestTransactioner(t),
+		Alerts:         alerts,
+		Events:         events,
+		Users:          users,
+		Notifier:       notifier,
+		Logger:         newTestLogger(),
+		UnsubscribeURL: "https://events.example.com/unsubscribe/search/",
+		BatchSize:      2,
+		BatchesPerRun:  5,
+	}
+	concert, lecture := "concert", "lecture"
+	event := &model.Event{EventID: 1, Name: "Open air concert", BeginsAt: time.Now().Add(time.Hour)}
+	first := []model.SavedSearch{
+		{SearchID: 3, UserID: 10, Filter: model.SearchFilter{Text: &concert}, UnsubscribeToken: "token"},
+		{SearchID: 4, UserID: 11, Filter: model.SearchFilter{Text: &lecture}},
+	}
+	second := []model.SavedSearch{{SearchID: 5, UserID: 12, Filter: model.SearchFilter{}}}
+
+	alerts.On("ClaimSearchAlertJob", mock.Anything).
+		Return(&model.SearchAlertJob{JobID: 6, EventID: event.EventID}, nil).Once()
+	alerts.On("ClaimSearchAlertJob", mock.Anything).
+		Return(&model.SearchAlertJob{JobID: 6, EventID: event.EventID, LastSearchID: 4}, nil).Once()
+	alerts.On("ClaimSearchAlertJob", mock.Anything).Return(nil, repositories.ErrSearchAlertJobNotFound).Once()
+	events.On("GetById", mock.Anything, event.EventID).Return(event, nil)
+	// Searches are matched by SQL in order, the third one is invalid and skipped without a query
+	events.On("SelectBy", mock.Anything, mock.Anything).Return([]model.Event{*event}, nil).Once()
+	events.On("SelectBy", mock.Anything, mock.Anything).Return(nil, nil).Once()
+	alerts.On("ListAlertSearches", mock.Anything, int64(0), u.BatchSize).Return(first, nil)
+	alerts.On("ListAlertSearches", mock.Anything, int64(4), u.BatchSize).Return(second, nil)
+	user := &model.User{UserID: 10}
+	users.On("GetById", mock.Anything, user.UserID).Return(user, nil).Once()
+	notifier.On("SendToAll", mock.Anything, user, model.MessageSearchAlert,
+		mock.MatchedBy(func(data searchAlertContext) bool {
+			return data.Search.SearchID == 3 &&
+				data.UnsubscribeURL == "https://events.example.com/unsubscribe/search/token"
+		})).Return(nil).Once()
+	alerts.On("AdvanceSearchAlertJob", mock.Anything, int64(6), int64(4)).Return(nil).Once()
+	alerts.On("CompleteSearchAlertJob", mock.Anything, int64(6)).Return(nil).Once()
+
+	assert.NoError(t, u.DispatchSearchAlerts(ctx))
+}
@@ -7,10 +7,15 @@ import (
 	"github.com/burenotti/rtu-it-lab-recruit/model"
 	"github.com/burenotti/rtu-it-lab-recruit/repositories"
 	"github.com/sirupsen/logrus"
+	"time"
 )
 
+const activationResendWindow = 24 * time.Hour
+
 var (
-	ErrTokenSendFailed = errors.New("activation token send failed")
+	ErrTokenSendFailed  = errors.New("activation token send failed")
+	ErrUserNotActivated = errors.New("user is not activated")
+	ErrTooManyRequests  = errors.New("too many requests")
 )
 
 type UserStorage interface {
@@ -19,11 +24,19 @@ type UserStorage interface {
 	GetByEmail(ctx context.Context, email string) (*model.User, error)
 	Update(ctx context.Context, userId int64, update map[string]interface{}) (*model.User, error)
 	Delete(ctx context.Context, userId int64) error
+	DeleteInactiveBefore(ctx context.Context, before time.Time) (int64, error)
 }
 
 type UserActivator interface {
 	CreateActivationToken(_ context.Context, userId int64) (*model.ActivationToken, error)
 	ValidateActivationToken(_ context.Context, token string) (*model.ActivationToken, error)
+	UseActivationToken(ctx context.Context, token string) (*model.ActivationToken, error)
+}
+
+type ActivationRequestStorage interface {
+	CreateActivationRequest(ctx context.Context, userId int64) error
+	CountActivationRequests(ctx context.Context, userId int64, since time.Time) (int, error)
+	LockActivationRequests(ctx context.Context, userId int64) error
 }
 
 type TokenDelivery interface {
@@ -33,43 +46,137 @@ type TokenDelivery interface {
 type SignUpUseCase struct {
 	UserRepo       UserStorage
 	ActivationRepo UserActivator
+	RequestRepo    ActivationRequestStorage
 	Delivery       TokenDelivery
+	Transactioner  StorageTransactioner
 	Logger         *logrus.Logger
+
+	// ResendCooldown is the minimal interval between two activation emails sent to the same user.
+	ResendCooldown time.Duration
+	// ResendLimit is the maximal number of activation emails sent to the same user within 24 hours.
+	ResendLimit int
+	// InactiveUserTTL is the period after which never activated accounts are purged.
+	InactiveUserTTL time.Duration
 }
 
 func (u *SignUpUseCase) SignUp(ctx context.Context, create *model.UserCreate) (*model.User, error) {
-	user, err := u.UserRepo.Create(ctx, create)
+	var user *model.User
+	err := u.Transactioner.Atomic(ctx, func(ctx context.Context) (err error) {
+		user, err = u.UserRepo.Create(ctx, create)
+		if err != nil {
+			return err
+		}
+		if err = u.RequestRepo.CreateActivationRequest(ctx, user.UserID); err != nil {
+			return err
+		}
+		return u.sendActivationToken(ctx, user)
+	})
 	if err != nil {
 		return nil, err
 	}
+	return user, nil
+}
 
-	token, err := u.ActivationRepo.CreateActivationToken(ctx, user.UserID)
-	if err != nil {
-		return user, fmt.Errorf("%w: can't create activation token", ErrTokenSendFailed)
-	}
+// ResendActivation sends a new activation token to the user with provided email.
+// Sending is rate limited by ResendCooldown and ResendLimit. The result is the same for unknown emails,
+// active and deactivated accounts and exceeded limits, so emails and states of accounts can't be probed.
+func (u *SignUpUseCase) ResendActivation(ctx context.Context, email string) error {
+	return u.Transactioner.Atomic(ctx, func(ctx context.Context) error {
+		user, err := u.UserRepo.GetByEmail(ctx, email)
+		if errors.Is(err, repositories.ErrUserNotFound) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		logger := u.Logger.WithField("user_id", user.UserID)
+		if user.IsDeactivated() || user.IsActive {
+			logger.Info("Activation email is not resent to activated or deactivated user")
+			return nil
+		}
+		if err = u.RequestRepo.LockActivationRequests(ctx, user.UserID); err != nil {
+			return err
+		}
 
-	u.Logger.
-		WithField("user_id", user.UserID).
-		Infof("Sending activation email to %d", user.UserID)
-	if err = u.Delivery.SendActivationToken(ctx, user, token.Token); err != nil {
-		u.Logger.
-			WithField("user_id", user.UserID).
-			WithError(err).
-			Info("Sending email activation failed: %v", err)
-	}
+		now := time.Now().UTC()
+		recent, err := u.RequestRepo.CountActivationRequests(ctx, user.UserID, now.Add(-u.ResendCooldown))
+		if err != nil {
+			return err
+		}
+		if recent > 0 {
+			logger.Infof("Activation email is not resent within %s", u.ResendCooldown)
+			return nil
+		}
 
-	return user, nil
+		daily, err := u.RequestRepo.CountActivationRequests(ctx, user.UserID, now.Add(-activationResendWindow))
+		if err != nil {
+			return err
+		}
+		if daily >= u.ResendLimit {
+			logger.Infof("Activation email is not resent more than %d times a day", u.ResendLimit)
+			return nil
+		}
+
+		if err = u.RequestRepo.CreateActivationRequest(ctx, user.UserID); err != nil {
+			return err
+		}
+		return u.sendActivationToken(ctx, user)
+	})
 }
 
-func (u *SignUpUseCase) ActivateWithToken(ctx context.Context, token string) error {
+// CheckActivationToken validates token without using it.
+// It is safe to call it on requests made by email link scanners.
+func (u *SignUpUseCase) CheckActivationToken(ctx context.Context, token string) (*model.ActivationToken, error) {
 	t, err := u.ActivationRepo.ValidateActivationToken(ctx, token)
+	if errors.Is(err, repositories.ErrTokenExpired) {
+		return nil, fmt.Errorf("%w, request a new one", err)
+	}
+	return t, err
+}
+
+// ActivateWithToken uses token and activates its owner. Every token could be used only once.
+func (u *SignUpUseCase) ActivateWithToken(ctx context.Context, token string) error {
+	return u.Transactioner.Atomic(ctx, func(ctx context.Context) error {
+		t, err := u.ActivationRepo.UseActivationToken(ctx, token)
+		if errors.Is(err, repositories.ErrTokenExpired) {
+			return fmt.Errorf("%w, request a new one", err)
+		} else if err != nil {
+			return err
+		}
+		u.Logger.WithField("user_id", t.UserId).Infof("Activating user %d", t.UserId)
+
+		_, err = u.UserRepo.Update(ctx, t.UserId, repositories.UpdatesMap{
+			"is_active": true,
+		})
+		return err
+	})
+}
+
+// PurgeInactiveUsers deletes accounts which were not activated within InactiveUserTTL.
+func (u *SignUpUseCase) PurgeInactiveUsers(ctx context.Context) error {
+	before := time.Now().UTC().Add(-u.InactiveUserTTL)
+	count, err := u.UserRepo.DeleteInactiveBefore(ctx, before)
 	if err != nil {
 		return err
 	}
-	u.Logger.WithField("user_id", t.UserId).Info("Activating user %d", t.UserId)
+	if count > 0 {
+		u.Logger.
+			WithField("count", count).
+			Infof("Purged %d inactive users", count)
+	}
+	return nil
+}
 
-	_, err = u.UserRepo.Update(ctx, t.UserId, repositories.UpdatesMap{
-		"is_active": true,
-	})
-	return err
+func (u *SignUpUseCase) sendActivationToken(ctx context.Context, user *model.User) error {
+	token, err := u.ActivationRepo.CreateActivationToken(ctx, user.UserID)
+	if err != nil {
+		return fmt.Errorf("%w: can't create activation token", ErrTokenSendFailed)
+	}
+
+	u.Logger.
+		WithField("user_id", user.UserID).
+		Infof("Sending activation email to %d", user.UserID)
+	if err = u.Delivery.SendActivationToken(ctx, user, token.Token); err != nil {
+		return fmt.Errorf("%w: %v", ErrTokenSendFailed, err)
+	}
+	return nil
 }
@@ -0,0 +1,185 @@
+package usecases
+
+import (
+	"context"
+	"github.com/burenotti/rtu-it-lab-recruit/model"
+	"github.com/burenotti/rtu-it-lab-recruit/repositories"
+	"github.com/burenotti/rtu-it-lab-recruit/usecases/mocks"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"io"
+	"testing"
+	"time"
+)
+
+func newTestTransactioner(t *testing.T) *mocks.StorageTransactioner {
+	tx := mocks.NewStorageTransactioner(t)
+	tx.On("Atomic", mock.Anything, mock.Anything).
+		Return(func(ctx context.Context, f repositories.AtomicFunc) error {
+			return f(ctx)
+		}).Maybe()
+	return tx
+}
+
+func newTestLogger() *logrus.Logger {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	return logger
+}
+
+func TestSignUpUseCase_ResendActivation(t *testing.T) {
+	ctx := context.Background()
+	users := mocks.NewUserStorage(t)
+	activator := mocks.NewUserActivator(t)
+	requests := mocks.NewActivationRequestStorage(t)
+	delivery := mocks.NewTokenDelivery(t)
+	u := &SignUpUseCase{
+		UserRepo:       users,
+		ActivationRepo: activator,
+		RequestRepo:    requests,
+		Delivery:       delivery,
+		Transactioner:  newTestTransactioner(t),
+		Logger:         newTestLogger(),
+		ResendLimit:    3,
+	}
+	user := &model.User{UserID: 1, Email: "johndoe@example.com"}
+
+	users.On("GetByEmail", mock.Anything, user.Email).Return(user, nil)
+	requests.On("LockActivationRequests", mock.Anything, user.UserID).Return(nil)
+	requests.On("CountActivationRequests", mock.Anything, user.UserID, mock.Anything).Return(0, nil).Twice()
+	requests.On("CreateActivationRequest", mock.Anything, user.UserID).Return(nil)
+	activator.On("CreateActivationToken", mock.Anything, user.UserID).
+		Return(&model.ActivationToken{Token: "token", UserId: user.UserID}, nil)
+	delivery.On("SendActivationToken", mock.Anything, user, "token").Return(nil)
+
+	err := u.ResendActivation(ctx, user.Email)
+	assert.NoError(t, err, "should resend activation token")
+}
+
+func TestSignUpUseCase_ResendActivation_AlreadyActive(t *testing.T) {
+	ctx := context.Background()
+	users := mocks.NewUserStorage(t)
+	u := &SignUpUseCase{UserRepo: users, Transactioner: newTestTransactioner(t), Logger: newTestLogger()}
+	user := &model.User{UserID: 1, Email: "johndoe@example.com", IsActive: true}
+
+	users.On("GetByEmail", mock.Anything, user.Email).Return(user, nil)
+
+	err := u.ResendActivation(ctx, user.Email)
+	assert.NoError(t, err, "active account should not be told apart from inactive one")
+}
+
+func TestSignUpUseCase_ResendActivation_UnknownEmail(t *testing.T) {
+	ctx := context.Background()
+	users := mocks.NewUserStorage(t)
+	u := &SignUpUseCase{UserRepo: users, Transactioner: newTestTransactioner(t)}
+
+	users.On("GetByEmail", mock.Anything, "nobody@example.com").Return(nil, repositories.ErrUserNotFound)
+
+	err := u.ResendActivation(ctx, "nobody@example.com")
+	assert.NoError(t, err, "unknown email should not be told apart from registered one")
+}
+
+func TestSignUpUseCase_ResendActivation_Deactivated(t *testing.T) {
+	ctx := context.Background()
+	users := mocks.NewUserStorage(t)
+	u := &SignUpUseCase{UserRepo: users, Transactioner: newTestTransactioner(t), Logger: newTestLogger()}
+	now := time.Now()
+	user := &model.User{UserID: 1, Email: "johndoe@example.com", DeactivatedAt: &now}
+
+	users.On("GetByEmail", mock.Anything, user.Email).Return(user, nil)
+
+	err := u.ResendActivation(ctx, user.Email)
+	assert.NoError(t, err, "deactivated user should not activate the account again nor learn about it")
+}
+
+func TestSignUpUseCase_ResendActivation_Cooldown(t *testing.T) {
+	ctx := context.Background()
+	users := mocks.NewUserStorage(t)
+	requests := mocks.NewActivationRequestStorage(t)
+	u := &SignUpUseCase{
+		UserRepo:       users,
+		RequestRepo:    requests,
+		Transactioner:  newTestTransactioner(t),
+		Logger:         newTestLogger(),
+		ResendCooldown: time.Minute,
+	}
+	user := &model.User{UserID: 1, Email: "johndoe@example.com"}
+
+	users.On("GetByEmail", mock.Anything, user.Email).Return(user, nil)
+	requests.On("LockActivationRequests", mock.Anything, user.UserID).Return(nil)
+	requests.On("CountActivationRequests", mock.Anything, user.UserID, mock.Anything).Return(1, nil).Once()
+
+	err := u.ResendActivation(ctx, user.Email)
+	assert.NoError(t, err, "should not resend token within cooldown silently")
+}
+
+func TestSignUpUseCase_ResendActivation_DailyLimit(t *testing.T) {
+	ctx := context.Background()
+	users := mocks.NewUserStorage(t)
+	requests := mocks.NewActivationRequestStorage(t)
+	u := &SignUpUseCase{
+		UserRepo:      users,
+		RequestRepo:   requests,
+		Transactioner: newTestTransactioner(t),
+		Logger:        newTestLogger(),
+		ResendLimit:   3,
+	}
+	user := &model.User{UserID: 1, Email: "johndoe@example.com"}
+
+	users.On("GetByEmail", mock.Anything, user.Email).Return(user, nil)
+	requests.On("LockActivationRequests", mock.Anything, user.UserID).Return(nil)
+	requests.On("CountActivationRequests", mock.Anything, user.UserID, mock.Anything).Return(0, nil).Once()
+	requests.On("CountActivationRequests", mock.Anything, user.UserID, mock.Anything).Return(u.ResendLimit, nil).Once()
+
+	err := u.ResendActivation(ctx, user.Email)
+	assert.NoError(t, err, "should not resend token after daily limit exceeded silently")
+}
+
+func TestSignUpUseCase_PurgeInactiveUsers(t *testing.T) {
+	ctx := context.Background()
+	users := mocks.NewUserStorage(t)
+	u := &SignUpUseCase{UserRepo: users, Logger: newTestLogger(), InactiveUserTTL: 24 * time.Hour}
+
+	users.On("DeleteInactiveBefore", mock.Anything, mock.MatchedBy(func(before time.Time) bool {
+		return before.Before(time.Now().Add(-u.InactiveUserTTL + time.Minute))
+	})).Return(int64(2), nil)
+
+	assert.NoError(t, u.PurgeInactiveUsers(ctx))
+}
+
+func TestEmailSignInUseCase_RequestCode_NotActivated(t *testing.T) {
+	ctx := context.Background()
+	users := mocks.NewUserStorage(t)
+	s := EmailSignInUseCase{
+		UserStore:     users,
+		Transactioner: newTestTransactioner(t),
+	}
+	user := &model.User{UserID: 1, Email: "johndoe@example.com"}
+	users.On("GetByEmail", mock.Anything, user.Email).Return(user, nil)
+
+	err := s.RequestCode(ctx, user.Email)
+	assert.ErrorIs(t, err, ErrUserNotActivated, "inactive user should not receive login code")
+}
+
+func TestSignUpUseCase_ActivateWithToken(t *testing.T) {
+	ctx := context.Background()
+	users := mocks.NewUserStorage(t)
+	activator := mocks.NewUserActivator(t)
+	u := &SignUpUseCase{
+		UserRepo:       users,
+		ActivationRepo: activator,
+		Transactioner:  newTestTransactioner(t),
+		Logger:         newTestLogger(),
+	}
+	token := &model.ActivationToken{TokenID: "jti", Token: "token", UserId: 1}
+
+	activator.On("UseActivationToken", mock.Anything, token.Token).Return(token, nil).Once()
+	users.On("Update", mock.Anything, token.UserId, map[string]interface{}{"is_active": true}).
+		Return(&model.User{UserID: token.UserId, IsActive: true}, nil).Once()
+	assert.NoError(t, u.ActivateWithToken(ctx, token.Token))
+
+	activator.On("UseActivationToken", mock.Anything, token.Token).Return(nil, repositories.ErrTokenAlreadyUsed).Once()
+	err := u.ActivateWithToken(ctx, token.Token)
+	assert.ErrorIs(t, err, repositories.ErrTokenAlreadyUsed, "token should not be replayed")
+}
@@ -0,0 +1,609 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/burenotti/rtu-it-lab-recruit/model"
+	"github.com/burenotti/rtu-it-lab-recruit/repositories"
+	"github.com/sirupsen/logrus"
+	"strings"
+	"time"
+)
+
+const expiredOrdersBatch = 100
+
+type TicketStorage interface {
+	CreateTicketType(ctx context.Context, eventId int64, create *model.TicketTypeCreate) (*model.TicketType, error)
+	GetTicketType(ctx context.Context, ticketTypeId int64) (*model.TicketType, error)
+	LockTicketType(ctx context.Context, ticketTypeId int64) error
+	ListTicketTypes(ctx context.Context, eventId int64) ([]model.TicketType, error)
+	UpdateTicketType(ctx context.Context, ticketTypeId int64, updates map[string]interface{}) (*model.TicketType, error)
+	DeleteTicketType(ctx context.Context, ticketTypeId int64) error
+	CreatePromoCode(ctx context.Context, eventId int64, create *model.PromoCodeCreate) (*model.PromoCode, error)
+	GetPromoCode(ctx context.Context, promoCodeId int64) (*model.PromoCode, error)
+	LockPromoCode(ctx context.Context, eventId int64, code string) (int64, error)
+	ListPromoCodes(ctx context.Context, eventId int64) ([]model.PromoCode, error)
+	DeletePromoCode(ctx context.Context, eventId, promoCodeId int64) error
+}
+
+type OrderStorage interface {
+	CreateOrder(ctx context.Context, order *model.Order) (*model.Order, error)
+	GetOrder(ctx context.Context, orderId int64) (*model.Order, error)
+	LockOrder(ctx context.Context, orderId int64) (*model.Order, error)
+	ListUserOrders(ctx context.Context, userId int64) ([]model.Order, error)
+	ListExpiredOrders(ctx context.Context, before time.Time, limit int) ([]model.Order, error)
+	SetOrderPayment(ctx context.Context, orderId int64, paymentId, paymentURL string) error
+	UpdateOrderStatus(ctx context.Context, orderId int64, status string) (*model.Order, error)
+}
+
+// PaymentProvider charges users for orders. Users pay on the provider's side by the confirmation URL,
+// then the status of the payment is checked.
+type PaymentProvider interface {
+	CreatePayment(ctx context.Context, request *model.PaymentRequest) (*model.Payment, error)
+	GetPayment(ctx context.Context, paymentId string) (*model.Payment, error)
+	CancelPayment(ctx context.Context, paymentId string) error
+}
+
+// TicketUseCase implements sales of tickets for events. Members who can edit events manage ticket types
+// and promo codes. Ordered tickets are reserved for ReservationTTL, the user is registered for the event
+// once the order is paid.
+type TicketUseCase struct {
+	Transactioner StorageTransactioner
+	Tickets       TicketStorage
+	Orders        OrderStorage
+	Events        EventGetter
+	Members       EventMemberStorage
+	Registrations RegistrationStorage
+	Users         ChannelUserStorage
+	Notifier      UserNotifier
+	Webhooks      WebhookEmitter
+	Logger        *logrus.Logger
+	// Payments is not set until a payment provider is configured, priced tickets are not sold then.
+	Payments PaymentProvider
+	// ReservationTTL is how long ordered tickets are reserved for payment.
+	ReservationTTL time.Duration
+	// ReturnURL is where users are redirected after the payment.
+	ReturnURL string
+}
+
+// CreateTicketType adds the ticket type to the event. Once the event has ticket types,
+// users can register for it only by ordering tickets. Tickets are not sold for recurring events.
+func (u *TicketUseCase) CreateTicketType(ctx context.Context, userId, eventId int64, create *model.TicketTypeCreate) (*model.TicketType, error) {
+	event, err := u.Events.GetById(ctx, eventId)
+	if err != nil {
+		return nil, err
+	}
+	if err = checkCanEditEvents(ctx, u.Members, event.OrganizationID, userId, "manage tickets"); err != nil {
+		return nil, err
+	}
+	if event.IsRecurring() {
+		return nil, fmt.Errorf("%w: tickets are not sold for recurring events", ErrBusinessLogicViolation)
+	}
+	if err = checkSalesWindow(create.SalesBegin, create.SalesEnd); err != nil {
+		return nil, err
+	}
+	create.Currency = strings.ToUpper(create.Currency)
+	return u.Tickets.CreateTicketType(ctx, eventId, create)
+}
+
+// ListTicketTypes returns ticket types of published event. Members who can edit events see ticket types
+// of any event of the organization.
+func (u *TicketUseCase) ListTicketTypes(ctx context.Context, userId, eventId int64) ([]model.TicketType, error) {
+	event, err := u.Events.GetById(ctx, eventId)
+	if err != nil {
+		return nil, err
+	}
+	if !event.IsPublished() || event.IsHidden() {
+		if checkCanEditEvents(ctx, u.Members, event.OrganizationID, userId, "manage tickets") != nil {
+			return nil, fmt.Errorf("%w: event with provided id does not exist", repositories.ErrEventNotFount)
+		}
+	}
+	return u.Tickets.ListTicketTypes(ctx, eventId)
+}
+
+// UpdateTicketType updates the ticket type. Quantity can't be less than the number of sold and reserved tickets.
+func (u *TicketUseCase) UpdateTicketType(ctx context.Context, userId, ticketTypeId int64, update *model.TicketTypeUpdate) (*model.TicketType, error) {
+	var ticketType *model.TicketType
+	err := u.Transactioner.Atomic(ctx, func(ctx context.Context) (err error) {
+		if ticketType, err = u.getEditableTicketType(ctx, userId, ticketTypeId); err != nil {
+			return err
+		}
+		if err = u.Tickets.LockTicketType(ctx, ticketTypeId); err != nil {
+			return err
+		}
+		if ticketType, err = u.Tickets.GetTicketType(ctx, ticketTypeId); err != nil {
+			return err
+		}
+		updates, err := ticketTypeUpdates(ticketType, update)
+		if err != nil || len(updates) == 0 {
+			return err
+		}
+		ticketType, err = u.Tickets.UpdateTicketType(ctx, ticketTypeId, updates)
+		return err
+	})
+	return ticketType, err
+}
+
+// DeleteTicketType deletes the ticket type. Ticket types with orders can't be deleted.
+func (u *TicketUseCase) DeleteTicketType(ctx context.Context, userId, ticketTypeId int64) error {
+	return u.Transactioner.Atomic(ctx, func(ctx context.Context) error {
+		if _, err := u.getEditableTicketType(ctx, userId, ticketTypeId); err != nil {
+			return err
+		}
+		return u.Tickets.DeleteTicketType(ctx, ticketTypeId)
+	})
+}
+
+// CreatePromoCode adds the promo code to the event. Percent codes can't take more than 100 percents off,
+// fixed codes discount only tickets in the currency of the code.
+func (u *TicketUseCase) CreatePromoCode(ctx context.Context, userId, eventId int64, create *model.PromoCodeCreate) (*model.PromoCode, error) {
+	event, err := u.Events.GetById(ctx, eventId)
+	if err != nil {
+		return nil, err
+	}
+	if err = checkCanEditEvents(ctx, u.Members, event.OrganizationID, userId, "manage tickets"); err != nil {
+		return nil, err
+	}
+	create.Code = strings.ToUpper(create.Code)
+	if create.Kind == model.PromoCodePercent {
+		if create.Amount > 100 {
+			return nil, fmt.Errorf("%w: percent promo code can't take more than 100 percents off", ErrBusinessLogicViolation)
+		}
+		create.Currency = nil
+	} else {
+		currency := strings.ToUpper(*create.Currency)
+		create.Currency = &currency
+	}
+	return u.Tickets.CreatePromoCode(ctx, eventId, create)
+}
+
+func (u *TicketUseCase) ListPromoCodes(ctx context.Context, userId, eventId int64) ([]model.PromoCode, error) {
+	event, err := u.Events.GetById(ctx, eventId)
+	if err != nil {
+		return nil, err
+	}
+	if err = checkCanEditEvents(ctx, u.Members, event.OrganizationID, userId, "manage tickets"); err != nil {
+		return nil, err
+	}
+	return u.Tickets.ListPromoCodes(ctx, eventId)
+}
+
+func (u *TicketUseCase) DeletePromoCode(ctx context.Context, userId, eventId, promoCodeId int64) error {
+	event, err := u.Events.GetById(ctx, eventId)
+	if err != nil {
+		return err
+	}
+	if err = checkCanEditEvents(ctx, u.Members, event.OrganizationID, userId, "manage tickets"); err != nil {
+		return err
+	}
+	return u.Tickets.DeletePromoCode(ctx, eventId, promoCodeId)
+}
+
+// CreateOrder reserves tickets of the type for the user and creates the payment for them.
+// Tickets are ordered within the registration window of the event and the sales window of the type.
+// Free orders are paid at once and the user is registered for the event. The order is for one ticket,
+// since the user is registered once, and the user can't have another reserved or paid order for the event.
+func (u *TicketUseCase) CreateOrder(ctx context.Context, userId, eventId int64, create *model.OrderCreate) (*model.Order, error) {
+	if create.Quantity != 1 {
+		return nil, fmt.Errorf("%w: one ticket can be ordered at a time", ErrBusinessLogicViolation)
+	}
+	var order *model.Order
+	var event *model.Event
+	err := u.Transactioner.Atomic(ctx, func(ctx context.Context) (err error) {
+		if event, err = u.Events.GetById(ctx, eventId); err != nil {
+			return err
+		}
+		if !event.IsPublished() || event.IsHidden() {
+			return fmt.Errorf("%w: event with provided id does not exist", repositories.ErrEventNotFount)
+		}
+		if event.IsCancelled() {
+			return fmt.Errorf("%w: event is cancelled", ErrBusinessLogicViolation)
+		}
+		now := time.Now().UTC()
+		if err = checkRegistrationOpen(event, now); err != nil {
+			return err
+		}
+
+		ticketType, err := u.Tickets.GetTicketType(ctx, create.TicketTypeID)
+		if err != nil {
+			return err
+		} else if ticketType.EventID != eventId {
+			return fmt.Errorf("%w: ticket type with provided id does not exist", repositories.ErrTicketTypeNotFound)
+		}
+		if err = checkSalesOpen(ticketType, now); err != nil {
+			return err
+		}
+		if ticketType.Price > 0 && u.Payments == nil {
+			return fmt.Errorf("%w: tickets can't be paid for yet", ErrBusinessLogicViolation)
+		}
+		// Tickets available are read again after the lock, concurrent orders could reserve them.
+		if err = u.Tickets.LockTicketType(ctx, ticketType.TicketTypeID); err != nil {
+			return err
+		}
+		if ticketType, err = u.Tickets.GetTicketType(ctx, ticketType.TicketTypeID); err != nil {
+			return err
+		}
+		if ticketType.Available != nil && *ticketType.Available < create.Quantity {
+			return fmt.Errorf("%w: only %d tickets are left", ErrBusinessLogicViolation, *ticketType.Available)
+		}
+
+		order = &model.Order{
+			EventID:      eventId,
+			UserID:       userId,
+			TicketTypeID: ticketType.TicketTypeID,
+			Quantity:     create.Quantity,
+			Currency:     ticketType.Currency,
+			Subtotal:     ticketType.Price * int64(create.Quantity),
+			Status:       model.OrderReserved,
+			ExpiresAt:    now.Add(u.ReservationTTL),
+			Source:       trafficSource(create.Source),
+		}
+		if create.PromoCode != "" {
+			promoCode, err := u.usePromoCode(ctx, eventId, create.PromoCode, now)
+			if err != nil {
+				return err
+			}
+			order.PromoCodeID = &promoCode.PromoCodeID
+			order.Discount = orderDiscount(promoCode, order)
+		}
+		order.Total = order.Subtotal - order.Discount
+		if order.Total == 0 {
+			order.Status = model.OrderPaid
+			order.PaidAt = &now
+		}
+
+		if order, err = u.Orders.CreateOrder(ctx, order); err != nil {
+			return err
+		}
+		if order.Status == model.OrderPaid {
+			return u.completeOrder(ctx, event, order)
+		}
+		return nil
+	})
+	if err != nil || order.Status == model.OrderPaid {
+		return order, err
+	}
+
+	// The payment is created after tickets are reserved, so the provider is not called while rows are locked.
+	payments, err := u.payments()
+	if err != nil {
+		return nil, err
+	}
+	payment, err := payments.CreatePayment(ctx, &model.PaymentRequest{
+		OrderID:     order.OrderID,
+		Amount:      order.Total,
+		Currency:    order.Currency,
+		Description: fmt.Sprintf("Ticket for %s", event.Name),
+		ReturnURL:   u.ReturnURL,
+	})
+	if err != nil {
+		if _, cerr := u.Orders.UpdateOrderStatus(ctx, order.OrderID, model.OrderCancelled); cerr != nil {
+			return nil, cerr
+		}
+		return nil, fmt.Errorf("failed to create payment: %w", err)
+	}
+	if err = u.Orders.SetOrderPayment(ctx, order.OrderID, payment.PaymentID, payment.ConfirmationURL); err != nil {
+		return nil, err
+	}
+	order.PaymentID = &payment.PaymentID
+	order.PaymentURL = &payment.ConfirmationURL
+	return order, nil
+}
+
+// GetOrder returns the order of the user.
+func (u *TicketUseCase) GetOrder(ctx context.Context, userId, orderId int64) (*model.Order, error) {
+	order, err := u.Orders.GetOrder(ctx, orderId)
+	if err != nil {
+		return nil, err
+	}
+	if order.UserID != userId {
+		return nil, fmt.Errorf("%w: order with provided id does not exist", repositories.ErrOrderNotFound)
+	}
+	return order, nil
+}
+
+func (u *TicketUseCase) ListUserOrders(ctx context.Context, userId int64) ([]model.Order, error) {
+	return u.Orders.ListUserOrders(ctx, userId)
+}
+
+// ConfirmOrder checks the payment of the order. If it has succeeded, the order is paid and the user
+// is registered for the event, if it has been cancelled, the order is cancelled.
+// Orders paid after ExpiresAt are still accepted until they are expired, their tickets are held till then.
+func (u *TicketUseCase) ConfirmOrder(ctx context.Context, userId, orderId int64) (*model.Order, error) {
+	order, err := u.GetOrder(ctx, userId, orderId)
+	if err != nil {
+		return nil, err
+	}
+	if order.Status == model.OrderPaid {
+		return order, nil
+	}
+	if order.Status != model.OrderReserved || order.PaymentID == nil {
+		return nil, fmt.Errorf("%w: order is %s", ErrBusinessLogicViolation, order.Status)
+	}
+	payments, err := u.payments()
+	if err != nil {
+		return nil, err
+	}
+	// The provider is asked before the order is locked, the status of the order is checked again under the lock.
+	payment, err := payments.GetPayment(ctx, *order.PaymentID)
+	if err != nil {
+		return nil, err
+	}
+	err = u.Transactioner.Atomic(ctx, func(ctx context.Context) (err error) {
+		if order, err = u.lockUserOrder(ctx, userId, orderId); err != nil {
+			return err
+		}
+		if order.Status == model.OrderPaid {
+			return nil
+		}
+		if order.Status != model.OrderReserved {
+			return fmt.Errorf("%w: order is %s", ErrBusinessLogicViolation, order.Status)
+		}
+		switch payment.Status {
+		case model.PaymentSucceeded:
+			if order, err = u.Orders.UpdateOrderStatus(ctx, orderId, model.OrderPaid); err != nil {
+				return err
+			}
+			event, err := u.Events.GetById(ctx, order.EventID)
+			if err != nil {
+				return err
+			}
+			return u.completeOrder(ctx, event, order)
+		case model.PaymentCancelled:
+			order, err = u.Orders.UpdateOrderStatus(ctx, orderId, model.OrderCancelled)
+			return err
+		default:
+			return fmt.Errorf("%w: order is not paid yet", ErrBusinessLogicViolation)
+		}
+	})
+	return order, err
+}
+
+// CancelOrder cancels reserved order of the user and its payment, reserved tickets are released.
+func (u *TicketUseCase) CancelOrder(ctx context.Context, userId, orderId int64) (*model.Order, error) {
+	order, err := u.GetOrder(ctx, userId, orderId)
+	if err != nil {
+		return nil, err
+	}
+	if order.Status != model.OrderReserved {
+		return nil, fmt.Errorf("%w: order is %s", ErrBusinessLogicViolation, order.Status)
+	}
+	// The payment is cancelled before the order is locked. It fails if the user has paid meanwhile.
+	if order.PaymentID != nil {
+		payments, err := u.payments()
+		if err != nil {
+			return nil, err
+		}
+		if err = payments.CancelPayment(ctx, *order.PaymentID); err != nil {
+			return nil, err
+		}
+	}
+	err = u.Transactioner.Atomic(ctx, func(ctx context.Context) (err error) {
+		if order, err = u.lockUserOrder(ctx, userId, orderId); err != nil {
+			return err
+		}
+		if order.Status != model.OrderReserved {
+			return fmt.Errorf("%w: order is %s", ErrBusinessLogicViolation, order.Status)
+		}
+		order, err = u.Orders.UpdateOrderStatus(ctx, orderId, model.OrderCancelled)
+		return err
+	})
+	return order, err
+}
+
+// ExpireOrders expires reserved orders which have not been paid in time and cancels their payments.
+// Orders whose payments have succeeded meanwhile are paid instead. Each order is handled in its own transaction,
+// failed ones are logged and retried on the next run.
+func (u *TicketUseCase) ExpireOrders(ctx context.Context) error {
+	orders, err := u.Orders.ListExpiredOrders(ctx, time.Now().UTC(), expiredOrdersBatch)
+	if err != nil {
+		return err
+	}
+	for i := range orders {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err = u.expireOrder(ctx, &orders[i]); err != nil {
+			u.Logger.WithError(err).WithField("order_id", orders[i].OrderID).Error("Failed to expire order")
+		}
+	}
+	return nil
+}
+
+// expireOrder expires the listed order. The provider is called before the order is locked,
+// so a slow provider doesn't hold the lock the buyer needs to confirm the order.
+func (u *TicketUseCase) expireOrder(ctx context.Context, listed *model.Order) error {
+	var payment *model.Payment
+	if listed.PaymentID != nil {
+		payments, err := u.payments()
+		if err != nil {
+			return err
+		}
+		if payment, err = payments.GetPayment(ctx, *listed.PaymentID); err != nil {
+			return err
+		}
+		// Cancelling fails if the user pays meanwhile, the order is paid on the next run then.
+		if payment.Status == model.PaymentPending {
+			if err = payments.CancelPayment(ctx, *listed.PaymentID); err != nil {
+				return err
+			}
+		}
+	}
+	return u.Transactioner.Atomic(ctx, func(ctx context.Context) error {
+		order, err := u.Orders.LockOrder(ctx, listed.OrderID)
+		if err != nil {
+			return err
+		}
+		// The order could be paid or cancelled since it was listed.
+		if order.Status != model.OrderReserved {
+			return nil
+		}
+		if payment != nil && payment.Status == model.PaymentSucceeded {
+			if order, err = u.Orders.UpdateOrderStatus(ctx, order.OrderID, model.OrderPaid); err != nil {
+				return err
+			}
+			event, err := u.Events.GetById(ctx, order.EventID)
+			if err != nil {
+				return err
+			}
+			return u.completeOrder(ctx, event, order)
+		}
+		_, err = u.Orders.UpdateOrderStatus(ctx, order.OrderID, model.OrderExpired)
+		return err
+	})
+}
+
+// completeOrder registers the buyer for the event, unless they are already registered,
+// and notifies the user and the organization about the paid order.
+func (u *TicketUseCase) completeOrder(ctx context.Context, event *model.Event, order *model.Order) error {
+	user, err := u.Users.GetById(ctx, order.UserID)
+	if err != nil {
+		return err
+	}
+	registration, err := u.Registrations.Register(ctx, event.EventID, order.UserID, nil, order.Source)
+	if errors.Is(err, repositories.ErrAlreadyRegistered) {
+		registration = nil
+	} else if err != nil {
+		return err
+	}
+	err = u.Webhooks.Emit(ctx, event.OrganizationID, model.WebhookOrderPaid, model.WebhookOrderData{
+		Order:     order,
+		FirstName: user.FirstName,
+		LastName:  user.LastName,
+	})
+	if err != nil || registration == nil {
+		return err
+	}
+	err = u.Webhooks.Emit(ctx, event.OrganizationID, model.WebhookRegistrationCreated, model.WebhookRegistrationData{
+		Registration: registration,
+		FirstName:    user.FirstName,
+		LastName:     user.LastName,
+	})
+	if err != nil {
+		return err
+	}
+	return u.Notifier.SendToAll(ctx, user, model.MessageRegistrationConfirmed, registrationConfirmedContext{
+		User:  user,
+		Event: event,
+	})
+}
+
+// usePromoCode locks the promo code, so concurrent orders don't exceed its uses, and checks it can be used.
+func (u *TicketUseCase) usePromoCode(ctx context.Context, eventId int64, code string, now time.Time) (*model.PromoCode, error) {
+	promoCodeId, err := u.Tickets.LockPromoCode(ctx, eventId, strings.ToUpper(code))
+	if errors.Is(err, repositories.ErrPromoCodeNotFound) {
+		return nil, fmt.Errorf("%w: promo code is invalid", ErrBusinessLogicViolation)
+	} else if err != nil {
+		return nil, err
+	}
+	promoCode, err := u.Tickets.GetPromoCode(ctx, promoCodeId)
+	if err != nil {
+		return nil, err
+	}
+	if promoCode.ExpiresAt != nil && !now.Before(*promoCode.ExpiresAt) {
+		return nil, fmt.Errorf("%w: promo code has expired", ErrBusinessLogicViolation)
+	}
+	if promoCode.MaxUses != nil && promoCode.Used >= *promoCode.MaxUses {
+		return nil, fmt.Errorf("%w: promo code has been used up", ErrBusinessLogicViolation)
+	}
+	return promoCode, nil
+}
+
+func (u *TicketUseCase) getEditableTicketType(ctx context.Context, userId, ticketTypeId int64) (*model.TicketType, error) {
+	ticketType, err := u.Tickets.GetTicketType(ctx, ticketTypeId)
+	if err != nil {
+		return nil, err
+	}
+	event, err := u.Events.GetById(ctx, ticketType.EventID)
+	if err != nil {
+		return nil, err
+	}
+	if err = checkCanEditEvents(ctx, u.Members, event.OrganizationID, userId, "manage tickets"); err != nil {
+		return nil, err
+	}
+	return ticketType, nil
+}
+
+func (u *TicketUseCase) lockUserOrder(ctx context.Context, userId, orderId int64) (*model.Order, error) {
+	order, err := u.Orders.LockOrder(ctx, orderId)
+	if err != nil {
+		return nil, err
+	}
+	if order.UserID != userId {
+		return nil, fmt.Errorf("%w: order with provided id does not exist", repositories.ErrOrderNotFound)
+	}
+	return order, nil
+}
+
+// payments returns the payment provider, orders with payments can't be handled until it is configured.
+func (u *TicketUseCase) payments() (PaymentProvider, error) {
+	if u.Payments == nil {
+		return nil, fmt.Errorf("%w: payment provider is not configured", ErrBusinessLogicViolation)
+	}
+	return u.Payments, nil
+}
+
+// orderDiscount returns the discount of the promo code, it is never greater than the subtotal.
+// Fixed promo codes in other currencies don't discount the order.
+func orderDiscount(promoCode *model.PromoCode, order *model.Order) int64 {
+	var discount int64
+	switch promoCode.Kind {
+	case model.PromoCodePercent:
+		discount = order.Subtotal * promoCode.Amount / 100
+	case model.PromoCodeFixed:
+		if promoCode.Currency != nil && *promoCode.Currency == order.Currency {
+			discount = promoCode.Amount
+		}
+	}
+	if discount > order.Subtotal {
+		return order.Subtotal
+	}
+	return discount
+}
+
+func checkSalesOpen(ticketType *model.TicketType, now time.Time) error {
+	if ticketType.SalesBegin != nil && now.Before(*ticketType.SalesBegin) {
+		return fmt.Errorf("%w: sales of the tickets have not started yet", ErrBusinessLogicViolation)
+	}
+	if ticketType.SalesEnd != nil && !now.Before(*ticketType.SalesEnd) {
+		return fmt.Errorf("%w: sales of the tickets are over", ErrBusinessLogicViolation)
+	}
+	return nil
+}
+
+func checkSalesWindow(begin, end *time.Time) error {
+	if begin != nil && end != nil && !begin.Before(*end) {
+		return fmt.Errorf("%w: sales must begin before they end", ErrBusinessLogicViolation)
+	}
+	return nil
+}
+
+func ticketTypeUpdates(current *model.TicketType, update *model.TicketTypeUpdate) (repositories.UpdatesMap, error) {
+	updates := repositories.UpdatesMap{}
+	if update.Name != nil {
+		updates["name"] = *update.Name
+	}
+	if update.Price != nil {
+		updates["price"] = *update.Price
+	}
+	if update.Quantity != nil {
+		if *update.Quantity < current.Sold {
+			return nil, fmt.Errorf("%w: %d tickets are already sold or reserved", ErrBusinessLogicViolation, current.Sold)
+		}
+		updates["quantity"] = *update.Quantity
+	}
+	salesBegin, salesEnd := current.SalesBegin, current.SalesEnd
+	if update.SalesBegin != nil {
+		salesBegin = update.SalesBegin
+		updates["sales_begin"] = *update.SalesBegin
+	}
+	if update.SalesEnd != nil {
+		salesEnd = update.SalesEnd
+		updates["sales_end"] = *update.SalesEnd
+	}
+	if err := checkSalesWindow(salesBegin, salesEnd); err != nil {
+		return nil, err
+	}
+	return updates, nil
+}
@@ -0,0 +1,326 @@
+package usecases
+
+import (
+	"context"
+	"github.com/burenotti/rtu-it-lab-recruit/model"
+	"github.com/burenotti/rtu-it-lab-recruit/repositories"
+	"github.com/burenotti/rtu-it-lab-recruit/services"
+	"github.com/burenotti/rtu-it-lab-recruit/usecases/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func publishedEvent() *model.Event {
+	publishedAt := time.Now().UTC()
+	return &model.Event{
+		EventID: 1, OrganizationID: 2, Name: "Concert", PublishedAt: &publishedAt,
+		BeginsAt: publishedAt.Add(48 * time.Hour), EndsAt: publishedAt.Add(50 * time.Hour),
+	}
+}
+
+// createdOrder returns the order passed to CreateOrder with the id set.
+func createdOrder(_ context.Context, order *model.Order) *model.Order {
+	created := *order
+	created.OrderID = 7
+	return &created
+}
+
+func TestTicketUseCase_CreateOrder_PromoCode(t *testing.T) {
+	ctx := context.Background()
+	tickets := mocks.NewTicketStorage(t)
+	orders := mocks.NewOrderStorage(t)
+	events := mocks.NewEventGetter(t)
+	payments := &services.FakePaymentProvider{BaseURL: "https://pay.example.com"}
+	u := &TicketUseCase{
+		Transactioner:  newTestTransactioner(t),
+		Tickets:        tickets,
+		Orders:         orders,
+		Events:         events,
+		Payments:       payments,
+		ReservationTTL: 15 * time.Minute,
+	}
+	event := publishedEvent()
+	quantity, available, maxUses := 100, 5, 10
+	ticketType := &model.TicketType{
+		TicketTypeID: 3, EventID: 1, Price: 100000, Currency: "RUB", Quantity: &quantity, Available: &available,
+	}
+	promoCode := &model.PromoCode{PromoCodeID: 4, EventID: 1, Code: "EARLY", Kind: model.PromoCodePercent, Amount: 20, MaxUses: &maxUses}
+
+	events.On("GetById", mock.Anything, int64(1)).Return(event, nil)
+	tickets.On("GetTicketType", mock.Anything, int64(3)).Return(ticketType, nil)
+	tickets.On("LockTicketType", mock.Anything, int64(3)).Return(nil).Once()
+	tickets.On("LockPromoCode", mock.Anything, int64(1), "EARLY").Return(int64(4), nil).Once()
+	tickets.On("GetPromoCode", mock.Anything, int64(4)).Return(promoCode, nil).Once()
+	orders.On("CreateOrder", mock.Anything, mock.Anything).Return(createdOrder, nil).Once()
+	orders.On("SetOrderPayment", mock.Anything, int64(7), "fake-1", mock.Anything).Return(nil).Once()
+
+	order, err := u.CreateOrder(ctx, 10, 1, &model.OrderCreate{TicketTypeID: 3, Quantity: 1, PromoCode: "early"})
+	require.NoError(t, err)
+	assert.Equal(t, model.OrderReserved, order.Status)
+	assert.Equal(t, int64(100000), order.Subtotal)
+	assert.Equal(t, int64(20000), order.Discount)
+	assert.Equal(t, int64(80000), order.Total)
+	assert.Equal(t, int64(4), *order.PromoCodeID)
+	assert.WithinDuration(t, time.Now().Add(15*time.Minute), order.ExpiresAt, time.Minute)
+	assert.Equal(t, "https://pay.example.com/fake-1?order_id=7", *order.PaymentURL)
+
+	payment, err := payments.GetPayment(ctx, "fake-1")
+	require.NoError(t, err)
+	assert.Equal(t, model.PaymentPending, payment.Status)
+}
+
+func TestTicketUseCase_CreateOrder_Rejected(t *testing.T) {
+	ctx := context.Background()
+	tickets := mocks.NewTicketStorage(t)
+	events := mocks.NewEventGetter(t)
+	u := &TicketUseCase{
+		Transactioner: newTestTransactioner(t),
+		Tickets:       tickets,
+		Events:        events,
+		Payments:      &services.FakePaymentProvider{BaseURL: "https://pay.example.com"},
+	}
+	event := publishedEvent()
+	quantity, available, soldOut, maxUses := 100, 1, 0, 3
+	salesEnd := time.Now().Add(-time.Hour)
+	ticketType := &model.TicketType{TicketTypeID: 3, EventID: 1, Price: 1000, Currency: "RUB", Quantity: &quantity, Available: &available}
+	soldOutType := &model.TicketType{TicketTypeID: 5, EventID: 1, Price: 1000, Currency: "RUB", Quantity: &quantity, Available: &soldOut}
+	closed := &model.TicketType{TicketTypeID: 4, EventID: 1, Price: 1000, Currency: "RUB", SalesEnd: &salesEnd}
+	usedUp := &model.PromoCode{PromoCodeID: 4, Kind: model.PromoCodeFixed, Amount: 100, MaxUses: &maxUses, Used: 3}
+
+	events.On("GetById", mock.Anything, int64(1)).Return(event, nil)
+	tickets.On("GetTicketType", mock.Anything, int64(3)).Return(ticketType, nil)
+	tickets.On("GetTicketType", mock.Anything, int64(4)).Return(closed, nil)
+	tickets.On("GetTicketType", mock.Anything, int64(5)).Return(soldOutType, nil)
+	tickets.On("LockTicketType", mock.Anything, int64(3)).Return(nil)
+	tickets.On("LockTicketType", mock.Anything, int64(5)).Return(nil)
+	tickets.On("LockPromoCode", mock.Anything, int64(1), "USED").Return(int64(4), nil)
+	tickets.On("GetPromoCode", mock.Anything, int64(4)).Return(usedUp, nil)
+
+	_, err := u.CreateOrder(ctx, 10, 1, &model.OrderCreate{TicketTypeID: 3, Quantity: 2})
+	assert.ErrorIs(t, err, ErrBusinessLogicViolation, "order should not be for several tickets")
+
+	_, err = u.CreateOrder(ctx, 10, 1, &model.OrderCreate{TicketTypeID: 5, Quantity: 1})
+	assert.ErrorIs(t, err, ErrBusinessLogicViolation, "order should not exceed available tickets")
+
+	_, err = u.CreateOrder(ctx, 10, 1, &model.OrderCreate{TicketTypeID: 4, Quantity: 1})
+	assert.ErrorIs(t, err, ErrBusinessLogicViolation, "tickets should not be sold after sales end")
+
+	_, err = u.CreateOrder(ctx, 10, 1, &model.OrderCreate{TicketTypeID: 3, Quantity: 1, PromoCode: "used"})
+	assert.ErrorIs(t, err, ErrBusinessLogicViolation, "used up promo code should not be accepted")
+}
+
+func TestTicketUseCase_CreateOrder_NoPaymentProvider(t *testing.T) {
+	ctx := context.Background()
+	tickets := mocks.NewTicketStorage(t)
+	events := mocks.NewEventGetter(t)
+	u := &TicketUseCase{Transactioner: newTestTransactioner(t), Tickets: tickets, Events: events}
+	ticketType := &model.TicketType{TicketTypeID: 3, EventID: 1, Price: 1000, Currency: "RUB"}
+
+	events.On("GetById", mock.Anything, int64(1)).Return(publishedEvent(), nil)
+	tickets.On("GetTicketType", mock.Anything, int64(3)).Return(ticketType, nil)
+
+	_, err := u.CreateOrder(ctx, 10, 1, &model.OrderCreate{TicketTypeID: 3, Quantity: 1})
+	assert.ErrorIs(t, err, ErrBusinessLogicViolation, "priced tickets should not be sold without payment provider")
+}
+
+func TestTicketUseCase_CreateOrder_Free(t *testing.T) {
+	ctx := context.Background()
+	tickets := mocks.NewTicketStorage(t)
+	orders := mocks.NewOrderStorage(t)
+	events := mocks.NewEventGetter(t)
+	registrations := mocks.NewRegistrationStorage(t)
+	users := mocks.NewChannelUserStorage(t)
+	notifier := mocks.NewUserNotifier(t)
+	webhooks := mocks.NewWebhookEmitter(t)
+	u := &TicketUseCase{
+		Transactioner: newTestTransactioner(t),
+		Tickets:       tickets,
+		Orders:        orders,
+		Events:        events,
+		Registrations: registrations,
+		Users:         users,
+		Notifier:      notifier,
+		Webhooks:      webhooks,
+	}
+	event := publishedEvent()
+	user := &model.User{UserID: 10, FirstName: "John", LastName: "Doe"}
+	ticketType := &model.TicketType{TicketTypeID: 3, EventID: 1, Price: 0, Currency: "RUB"}
+	registration := &model.Registration{EventID: 1, UserID: 10}
+
+	events.On("GetById", mock.Anything, int64(1)).Return(event, nil)
+	tickets.On("GetTicketType", mock.Anything, int64(3)).Return(ticketType, nil)
+	tickets.On("LockTicketType", mock.Anything, int64(3)).Return(nil).Once()
+	orders.On("CreateOrder", mock.Anything, mock.Anything).Return(createdOrder, nil).Once()
+	users.On("GetById", mock.Anything, int64(10)).Return(user, nil).Once()
+	registrations.On("Register", mock.Anything, int64(1), int64(10), (*int64)(nil), (*string)(nil)).Return(registration, nil).Once()
+	webhooks.On("Emit", mock.Anything, int64(2), model.WebhookOrderPaid, mock.Anything).Return(nil).Once()
+	webhooks.On("Emit", mock.Anything, int64(2), model.WebhookRegistrationCreated, mock.Anything).Return(nil).Once()
+	notifier.On("SendToAll", mock.Anything, user, model.MessageRegistrationConfirmed, mock.Anything).Return(nil).Once()
+
+	order, err := u.CreateOrder(ctx, 10, 1, &model.OrderCreate{TicketTypeID: 3, Quantity: 1})
+	require.NoError(t, err)
+	assert.Equal(t, model.OrderPaid, order.Status)
+	assert.NotNil(t, order.PaidAt)
+	assert.Nil(t, order.PaymentURL, "free order should not be paid with provider")
+}
+
+func TestTicketUseCase_ConfirmOrder(t *testing.T) {
+	ctx := context.Background()
+	orders := mocks.NewOrderStorage(t)
+	events := mocks.NewEventGetter(t)
+	registrations := mocks.NewRegistrationStorage(t)
+	users := mocks.NewChannelUserStorage(t)
+	webhooks := mocks.NewWebhookEmitter(t)
+	payments := &services.FakePaymentProvider{BaseURL: "https://pay.example.com"}
+	u := &TicketUseCase{
+		Transactioner: newTestTransactioner(t),
+		Orders:        orders,
+		Events:        events,
+		Registrations: registrations,
+		Users:         users,
+		Webhooks:      webhooks,
+		Payments:      payments,
+	}
+	event := publishedEvent()
+	user := &model.User{UserID: 10}
+	payment, err := payments.CreatePayment(ctx, &model.PaymentRequest{OrderID: 7, Amount: 1000, Currency: "RUB"})
+	require.NoError(t, err)
+	reserved := &model.Order{OrderID: 7, EventID: 1, UserID: 10, Status: model.OrderReserved, PaymentID: &payment.PaymentID}
+	paid := *reserved
+	paid.Status = model.OrderPaid
+
+	orders.On("GetOrder", mock.Anything, int64(7)).Return(reserved, nil)
+	orders.On("LockOrder", mock.Anything, int64(7)).Return(reserved, nil)
+	_, err = u.ConfirmOrder(ctx, 11, 7)
+	assert.ErrorIs(t, err, repositories.ErrOrderNotFound, "order of other user should not be confirmed")
+	_, err = u.ConfirmOrder(ctx, 10, 7)
+	assert.ErrorIs(t, err, ErrBusinessLogicViolation, "pending payment should not pay the order")
+
+	require.NoError(t, payments.Succeed(payment.PaymentID))
+	orders.On("UpdateOrderStatus", mock.Anything, int64(7), model.OrderPaid).Return(&paid, nil).Once()
+	events.On("GetById", mock.Anything, int64(1)).Return(event, nil).Once()
+	users.On("GetById", mock.Anything, int64(10)).Return(user, nil).Once()
+	registrations.On("Register", mock.Anything, int64(1), int64(10), (*int64)(nil), (*string)(nil)).
+		Return(nil, repositories.ErrAlreadyRegistered).Once()
+	webhooks.On("Emit", mock.Anything, int64(2), model.WebhookOrderPaid, model.WebhookOrderData{Order: &paid}).Return(nil).Once()
+
+	order, err := u.ConfirmOrder(ctx, 10, 7)
+	require.NoError(t, err)
+	assert.Equal(t, model.OrderPaid, order.Status)
+}
+
+func TestTicketUseCase_ExpireOrders(t *testing.T) {
+	ctx := context.Background()
+	orders := mocks.NewOrderStorage(t)
+	payments := &services.FakePaymentProvider{BaseURL: "https://pay.example.com"}
+	u := &TicketUseCase{Transactioner: newTestTransactioner(t), Orders: orders, Payments: payments}
+	payment, err := payments.CreatePayment(ctx, &model.PaymentRequest{OrderID: 7, Amount: 1000, Currency: "RUB"})
+	require.NoError(t, err)
+	expired := model.Order{OrderID: 7, EventID: 1, UserID: 10, Status: model.OrderReserved, PaymentID: &payment.PaymentID}
+	listed := model.Order{OrderID: 8, EventID: 1, UserID: 10, Status: model.OrderReserved}
+	cancelled := listed
+	cancelled.Status = model.OrderCancelled
+
+	orders.On("ListExpiredOrders", mock.Anything, mock.Anything, expiredOrdersBatch).
+		Return([]model.Order{expired, listed}, nil).Once()
+	orders.On("LockOrder", mock.Anything, int64(7)).Return(&expired, nil).Once()
+	orders.On("LockOrder", mock.Anything, int64(8)).Return(&cancelled, nil).Once()
+	orders.On("UpdateOrderStatus", mock.Anything, int64(7), model.OrderExpired).Return(&expired, nil).Once()
+
+	require.NoError(t, u.ExpireOrders(ctx))
+
+	payment, err = payments.GetPayment(ctx, payment.PaymentID)
+	require.NoError(t, err)
+	assert.Equal(t, model.PaymentCancelled, payment.Status, "payment of expired order should be cancelled")
+}
+
+func TestTicketUseCase_ExpireOrders_PaidMeanwhile(t *testing.T) {
+	ctx := context.Background()
+	orders := mocks.NewOrderStorage(t)
+	events := mocks.NewEventGetter(t)
+	registrations := mocks.NewRegistrationStorage(t)
+	users := mocks.NewChannelUserStorage(t)
+	webhooks := mocks.NewWebhookEmitter(t)
+	payments := &services.FakePaymentProvider{BaseURL: "https://pay.example.com"}
+	u := &TicketUseCase{
+		Transactioner: newTestTransactioner(t),
+		Orders:        orders,
+		Events:        events,
+		Registrations: registrations,
+		Users:         users,
+		Webhooks:      webhooks,
+		Payments:      payments,
+	}
+	event := publishedEvent()
+	payment, err := payments.CreatePayment(ctx, &model.PaymentRequest{OrderID: 7, Amount: 1000, Currency: "RUB"})
+	require.NoError(t, err)
+	require.NoError(t, payments.Succeed(payment.PaymentID))
+	reserved := model.Order{OrderID: 7, EventID: 1, UserID: 10, Status: model.OrderReserved, PaymentID: &payment.PaymentID}
+	paid := reserved
+	paid.Status = model.OrderPaid
+
+	orders.On("ListExpiredOrders", mock.Anything, mock.Anything, expiredOrdersBatch).
+		Return([]model.Order{reserved}, nil).Once()
+	orders.On("LockOrder", mock.Anything, int64(7)).Return(&reserved, nil).Once()
+	orders.On("UpdateOrderStatus", mock.Anything, int64(7), model.OrderPaid).Return(&paid, nil).Once()
+	events.On("GetById", mock.Anything, int64(1)).Return(event, nil).Once()
+	users.On("GetById", mock.Anything, int64(10)).Return(&model.User{UserID: 10}, nil).Once()
+	registrations.On("Register", mock.Anything, int64(1), int64(10), (*int64)(nil), (*string)(nil)).
+		Return(nil, repositories.ErrAlreadyRegistered).Once()
+	webhooks.On("Emit", mock.Anything, int64(2), model.WebhookOrderPaid, model.WebhookOrderData{Order: &paid}).Return(nil).Once()
+
+	require.NoError(t, u.ExpireOrders(ctx))
+	orders.AssertNotCalled(t, "UpdateOrderStatus", mock.Anything, int64(7), model.OrderExpired)
+}
+
+func TestTicketUseCase_CreatePromoCode(t *testing.T) {
+	ctx := context.Background()
+	tickets := mocks.NewTicketStorage(t)
+	events := mocks.NewEventGetter(t)
+	members := mocks.NewEventMemberStorage(t)
+	u := &TicketUseCase{Tickets: tickets, Events: events, Members: members}
+	events.On("GetById", mock.Anything, int64(1)).Return(publishedEvent(), nil)
+	members.On("GetMember", mock.Anything, int64(2), int64(10)).
+		Return(&model.OrganizationMember{UserID: 10, Can: model.MemberRights{EditEvents: true}}, nil)
+
+	_, err := u.CreatePromoCode(ctx, 10, 1, &model.PromoCodeCreate{Code: "half", Kind: model.PromoCodePercent, Amount: 150})
+	assert.ErrorIs(t, err, ErrBusinessLogicViolation, "percent code should not take more than 100 percents off")
+
+	currency := "rub"
+	tickets.On("CreatePromoCode", mock.Anything, int64(1), &model.PromoCodeCreate{
+		Code: "HALF", Kind: model.PromoCodePercent, Amount: 50,
+	}).Return(&model.PromoCode{PromoCodeID: 4}, nil).Once()
+	_, err = u.CreatePromoCode(ctx, 10, 1, &model.PromoCodeCreate{Code: "half", Kind: model.PromoCodePercent, Amount: 50, Currency: &currency})
+	require.NoError(t, err)
+}
+
+func TestOrderDiscount(t *testing.T) {
+	rub, usd := "RUB", "USD"
+	order := &model.Order{Currency: "RUB", Subtotal: 30000}
+	for name, tc := range map[string]struct {
+		promoCode *model.PromoCode
+		expected  int64
+	}{
+		"percent":        {&model.PromoCode{Kind: model.PromoCodePercent, Amount: 15}, 4500},
+		"fixed":          {&model.PromoCode{Kind: model.PromoCodeFixed, Amount: 10000, Currency: &rub}, 10000},
+		"fixed capped":   {&model.PromoCode{Kind: model.PromoCodeFixed, Amount: 50000, Currency: &rub}, 30000},
+		"fixed currency": {&model.PromoCode{Kind: model.PromoCodeFixed, Amount: 100, Currency: &usd}, 0},
+	} {
+		assert.Equal(t, tc.expected, orderDiscount(tc.promoCode, order), name)
+	}
+}
+
+func TestEventUseCase_RegisterForEvent_Tickets(t *testing.T) {
+	ctx := context.Background()
+	events := mocks.NewManagedEventStorage(t)
+	tickets := mocks.NewEventTicketStorage(t)
+	u := &EventUseCase{Transactioner: newTestTransactioner(t), Events: events, Tickets: tickets}
+	events.On("GetById", mock.Anything, int64(1)).Return(publishedEvent(), nil).Once()
+	tickets.On("ListTicketTypes", mock.Anything, int64(1)).Return([]model.TicketType{{TicketTypeID: 3}}, nil).Once()
+
+	_, err := u.RegisterForEvent(ctx, 10, 1, &model.RegistrationTarget{})
+	assert.ErrorIs(t, err, ErrBusinessLogicViolation, "tickets should be ordered to register for the event")
+}
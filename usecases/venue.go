@@ -0,0 +1,93 @@
+package usecases
+
+import (
+	"context"
+	"github.com/burenotti/rtu-it-lab-recruit/model"
+	"github.com/burenotti/rtu-it-lab-recruit/repositories"
+)
+
+type VenueStorage interface {
+	CreateVenue(ctx context.Context, orgId int64, create *model.VenueCreate) (*model.Venue, error)
+	GetVenue(ctx context.Context, venueId int64) (*model.Venue, error)
+	ListVenues(ctx context.Context, orgId int64) ([]model.Venue, error)
+	UpdateVenue(ctx context.Context, venueId int64, updates map[string]interface{}) (*model.Venue, error)
+	DeleteVenue(ctx context.Context, venueId int64) error
+}
+
+// VenueUseCase implements management of organization's venues. Members who can edit events manage venues.
+type VenueUseCase struct {
+	Transactioner StorageTransactioner
+	Venues        VenueStorage
+	Members       EventMemberStorage
+}
+
+func (u *VenueUseCase) CreateVenue(ctx context.Context, userId, orgId int64, create *model.VenueCreate) (*model.Venue, error) {
+	if err := checkCanEditEvents(ctx, u.Members, orgId, userId, "manage venues"); err != nil {
+		return nil, err
+	}
+	return u.Venues.CreateVenue(ctx, orgId, create)
+}
+
+func (u *VenueUseCase) GetVenue(ctx context.Context, venueId int64) (*model.Venue, error) {
+	return u.Venues.GetVenue(ctx, venueId)
+}
+
+func (u *VenueUseCase) ListVenues(ctx context.Context, orgId int64) ([]model.Venue, error) {
+	return u.Venues.ListVenues(ctx, orgId)
+}
+
+func (u *VenueUseCase) UpdateVenue(ctx context.Context, userId, venueId int64, update *model.VenueUpdate) (*model.Venue, error) {
+	var venue *model.Venue
+	err := u.Transactioner.Atomic(ctx, func(ctx context.Context) (err error) {
+		if venue, err = u.Venues.GetVenue(ctx, venueId); err != nil {
+			return err
+		}
+		if err = checkCanEditEvents(ctx, u.Members, venue.OrganizationID, userId, "manage venues"); err != nil {
+			return err
+		}
+		updates := venueUpdates(update)
+		if len(updates) == 0 {
+			return nil
+		}
+		venue, err = u.Venues.UpdateVenue(ctx, venueId, updates)
+		return err
+	})
+	return venue, err
+}
+
+// DeleteVenue deletes the venue. Venues linked to events can't be deleted.
+func (u *VenueUseCase) DeleteVenue(ctx context.Context, userId, venueId int64) error {
+	return u.Transactioner.Atomic(ctx, func(ctx context.Context) error {
+		venue, err := u.Venues.GetVenue(ctx, venueId)
+		if err != nil {
+			return err
+		}
+		if err = checkCanEditEvents(ctx, u.Members, venue.OrganizationID, userId, "manage venues"); err != nil {
+			return err
+		}
+		return u.Venues.DeleteVenue(ctx, venueId)
+	})
+}
+
+func venueUpdates(update *model.VenueUpdate) repositories.UpdatesMap {
+	updates := repositories.UpdatesMap{}
+	if update.Name != nil {
+		updates["name"] = *update.Name
+	}
+	if update.Address != nil {
+		updates["address"] = *update.Address
+	}
+	if update.Latitude != nil {
+		updates["latitude"] = *update.Latitude
+	}
+	if update.Longitude != nil {
+		updates["longitude"] = *update.Longitude
+	}
+	if update.Accessibility != nil {
+		updates["accessibility"] = *update.Accessibility
+	}
+	if update.Capacity != nil {
+		updates["capacity"] = *update.Capacity
+	}
+	return updates
+}
@@ -0,0 +1,45 @@
+package usecases
+
+import (
+	"context"
+	"github.com/burenotti/rtu-it-lab-recruit/model"
+	"github.com/burenotti/rtu-it-lab-recruit/repositories"
+	"github.com/burenotti/rtu-it-lab-recruit/usecases/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+)
+
+func TestVenueUseCase_UpdateVenue(t *testing.T) {
+	ctx := context.Background()
+	venues := mocks.NewVenueStorage(t)
+	members := mocks.NewEventMemberStorage(t)
+	u := &VenueUseCase{Transactioner: newTestTransactioner(t), Venues: venues, Members: members}
+	venue := &model.Venue{VenueID: 1, OrganizationID: 2, Name: "Hall"}
+	name := "Main hall"
+
+	venues.On("GetVenue", mock.Anything, venue.VenueID).Return(venue, nil)
+	members.On("GetMember", mock.Anything, venue.OrganizationID, int64(3)).
+		Return(&model.OrganizationMember{UserID: 3, Can: model.MemberRights{EditEvents: true}}, nil)
+	venues.On("UpdateVenue", mock.Anything, venue.VenueID, map[string]interface{}{"name": name}).
+		Return(&model.Venue{VenueID: 1, OrganizationID: 2, Name: name}, nil).Once()
+
+	updated, err := u.UpdateVenue(ctx, 3, venue.VenueID, &model.VenueUpdate{Name: &name})
+	assert.NoError(t, err)
+	assert.Equal(t, name, updated.Name)
+}
+
+func TestVenueUseCase_DeleteVenue_NotMember(t *testing.T) {
+	ctx := context.Background()
+	venues := mocks.NewVenueStorage(t)
+	members := mocks.NewEventMemberStorage(t)
+	u := &VenueUseCase{Transactioner: newTestTransactioner(t), Venues: venues, Members: members}
+	venue := &model.Venue{VenueID: 1, OrganizationID: 2}
+
+	venues.On("GetVenue", mock.Anything, venue.VenueID).Return(venue, nil)
+	members.On("GetMember", mock.Anything, venue.OrganizationID, int64(3)).
+		Return(nil, repositories.ErrMemberNotFound)
+
+	err := u.DeleteVenue(ctx, 3, venue.VenueID)
+	assert.ErrorIs(t, err, ErrBusinessLogicViolation)
+}
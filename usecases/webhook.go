@@ -0,0 +1,253 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/burenotti/rtu-it-lab-recruit/model"
+	"github.com/burenotti/rtu-it-lab-recruit/repositories"
+	"github.com/sirupsen/logrus"
+	"time"
+)
+
+const maxWebhooksPerOrganization = 10
+
+type WebhookStorage interface {
+	CreateWebhook(ctx context.Context, webhook *model.Webhook) (*model.Webhook, error)
+	ListWebhooks(ctx context.Context, orgId int64) ([]model.Webhook, error)
+	GetWebhook(ctx context.Context, orgId, webhookId int64) (*model.Webhook, error)
+	UpdateWebhook(ctx context.Context, orgId, webhookId int64, updates map[string]interface{}) (*model.Webhook, error)
+	DeleteWebhook(ctx context.Context, orgId, webhookId int64) error
+	ListDeliveries(ctx context.Context, webhookId int64, limit, offset int) ([]model.WebhookDelivery, error)
+}
+
+type WebhookDeliveryStorage interface {
+	ClaimDueDeliveries(ctx context.Context, limit int, lease time.Duration) ([]model.WebhookDelivery, error)
+	ExtendDeliveryLease(ctx context.Context, deliveryId int64, attempts int, lease time.Duration) (bool, error)
+	MarkDelivered(ctx context.Context, deliveryId int64, statusCode int) error
+	MarkDeliveryFailed(ctx context.Context, deliveryId int64, statusCode int, reason string, nextAttemptAt time.Time) error
+	MarkDeliveryDead(ctx context.Context, deliveryId int64, statusCode int, reason string) error
+	ResetFailures(ctx context.Context, webhookId int64) error
+	RecordFailure(ctx context.Context, webhookId int64) (int, error)
+	DisableWebhook(ctx context.Context, webhookId int64) error
+}
+
+type WebhookSender interface {
+	Send(ctx context.Context, delivery *model.WebhookDelivery) (int, error)
+}
+
+// WebhookUseCase implements management of organization's webhooks and delivery of events to them.
+// Failed deliveries are retried with exponential backoff, webhooks failing DisableAfter times in a row are disabled.
+type WebhookUseCase struct {
+	Transactioner StorageTransactioner
+	Webhooks      WebhookStorage
+	Deliveries    WebhookDeliveryStorage
+	Members       EventMemberStorage
+	Sender        WebhookSender
+	Logger        *logrus.Logger
+	// BatchSize is the number of deliveries claimed at once.
+	BatchSize int
+	// Lease is the time the claimed delivery is hidden from other dispatchers.
+	// It is renewed before every delivery of the batch, so it must only be longer than the timeout of webhook requests.
+	Lease        time.Duration
+	MaxAttempts  int
+	BaseBackoff  time.Duration
+	MaxBackoff   time.Duration
+	DisableAfter int
+}
+
+// CreateWebhook subscribes the endpoint to events of the organization. Only owners can manage webhooks.
+// The secret signing payloads is returned only once.
+func (u *WebhookUseCase) CreateWebhook(
+	ctx context.Context,
+	userId, orgId int64,
+	create *model.WebhookCreate,
+) (*model.Webhook, error) {
+	secret, err := newLinkToken()
+	if err != nil {
+		return nil, err
+	}
+	var webhook *model.Webhook
+	err = u.Transactioner.Atomic(ctx, func(ctx context.Context) error {
+		if err := u.checkOwner(ctx, orgId, userId); err != nil {
+			return err
+		}
+		webhooks, err := u.Webhooks.ListWebhooks(ctx, orgId)
+		if err != nil {
+			return err
+		}
+		if len(webhooks) >= maxWebhooksPerOrganization {
+			return fmt.Errorf("%w: organization can't have more than %d webhooks", ErrBusinessLogicViolation, maxWebhooksPerOrganization)
+		}
+		webhook, err = u.Webhooks.CreateWebhook(ctx, &model.Webhook{
+			OrganizationID: orgId,
+			URL:            create.URL,
+			EventTypes:     uniqueStrings(create.EventTypes),
+			Secret:         secret,
+		})
+		return err
+	})
+	return webhook, err
+}
+
+func (u *WebhookUseCase) ListWebhooks(ctx context.Context, userId, orgId int64) ([]model.Webhook, error) {
+	if err := u.checkOwner(ctx, orgId, userId); err != nil {
+		return nil, err
+	}
+	return u.Webhooks.ListWebhooks(ctx, orgId)
+}
+
+// UpdateWebhook updates the webhook. Enabling disabled webhook resumes delivery of its pending events.
+func (u *WebhookUseCase) UpdateWebhook(
+	ctx context.Context,
+	userId, orgId, webhookId int64,
+	update *model.WebhookUpdate,
+) (*model.Webhook, error) {
+	if err := u.checkOwner(ctx, orgId, userId); err != nil {
+		return nil, err
+	}
+	updates := repositories.UpdatesMap{}
+	if update.URL != nil {
+		updates["url"] = *update.URL
+	}
+	if len(update.EventTypes) > 0 {
+		updates["event_types"] = uniqueStrings(update.EventTypes)
+	}
+	if update.Enabled != nil {
+		updates["enabled"] = *update.Enabled
+	}
+	if len(updates) == 0 {
+		return u.Webhooks.GetWebhook(ctx, orgId, webhookId)
+	}
+	return u.Webhooks.UpdateWebhook(ctx, orgId, webhookId, updates)
+}
+
+func (u *WebhookUseCase) DeleteWebhook(ctx context.Context, userId, orgId, webhookId int64) error {
+	if err := u.checkOwner(ctx, orgId, userId); err != nil {
+		return err
+	}
+	return u.Webhooks.DeleteWebhook(ctx, orgId, webhookId)
+}
+
+// ListWebhookDeliveries returns the delivery log of the webhook, newest first.
+func (u *WebhookUseCase) ListWebhookDeliveries(
+	ctx context.Context,
+	userId, orgId, webhookId int64,
+	page *model.Pagination,
+) ([]model.WebhookDelivery, error) {
+	if err := u.checkOwner(ctx, orgId, userId); err != nil {
+		return nil, err
+	}
+	if _, err := u.Webhooks.GetWebhook(ctx, orgId, webhookId); err != nil {
+		return nil, err
+	}
+	limit := page.Limit
+	if limit == 0 {
+		limit = defaultPageSize
+	}
+	return u.Webhooks.ListDeliveries(ctx, webhookId, limit, page.Offset)
+}
+
+// DispatchWebhooks sends all due deliveries. It is safe to run it on several replicas.
+func (u *WebhookUseCase) DispatchWebhooks(ctx context.Context) error {
+	for ctx.Err() == nil {
+		deliveries, err := u.Deliveries.ClaimDueDeliveries(ctx, u.BatchSize, u.Lease)
+		if err != nil {
+			return err
+		}
+		if len(deliveries) == 0 {
+			return nil
+		}
+		for i := range deliveries {
+			if err := u.dispatch(ctx, &deliveries[i]); err != nil {
+				return err
+			}
+		}
+	}
+	return ctx.Err()
+}
+
+// Backoff returns delay before the next attempt after the given number of failed attempts.
+func (u *WebhookUseCase) Backoff(attempts int) time.Duration {
+	return exponentialBackoff(u.BaseBackoff, u.MaxBackoff, attempts)
+}
+
+func (u *WebhookUseCase) dispatch(ctx context.Context, d *model.WebhookDelivery) error {
+	logger := u.Logger.
+		WithField("delivery_id", d.DeliveryID).
+		WithField("webhook_id", d.WebhookID).
+		WithField("event_type", d.EventType).
+		WithField("attempt", d.Attempts)
+
+	// Sending previous deliveries of the batch could take longer than the lease.
+	extended, err := u.Deliveries.ExtendDeliveryLease(ctx, d.DeliveryID, d.Attempts, u.Lease)
+	if err != nil {
+		return err
+	}
+	if !extended {
+		logger.Warnf("Lease of delivery %d expired before sending, it is left to another dispatcher", d.DeliveryID)
+		return nil
+	}
+
+	statusCode, sendErr := u.Sender.Send(ctx, d)
+	return u.Transactioner.Atomic(ctx, func(ctx context.Context) error {
+		if sendErr == nil {
+			if err := u.Deliveries.MarkDelivered(ctx, d.DeliveryID, statusCode); err != nil {
+				return err
+			}
+			return u.Deliveries.ResetFailures(ctx, d.WebhookID)
+		}
+
+		if d.Attempts >= u.MaxAttempts {
+			logger.WithError(sendErr).Errorf("Delivery %d failed after %d attempts: %v", d.DeliveryID, d.Attempts, sendErr)
+			if err := u.Deliveries.MarkDeliveryDead(ctx, d.DeliveryID, statusCode, sendErr.Error()); err != nil {
+				return err
+			}
+		} else {
+			next := time.Now().UTC().Add(u.Backoff(d.Attempts))
+			logger.WithError(sendErr).Warnf("Delivery %d failed, retry at %s: %v", d.DeliveryID, next, sendErr)
+			if err := u.Deliveries.MarkDeliveryFailed(ctx, d.DeliveryID, statusCode, sendErr.Error(), next); err != nil {
+				return err
+			}
+		}
+
+		failures, err := u.Deliveries.RecordFailure(ctx, d.WebhookID)
+		if errors.Is(err, repositories.ErrWebhookNotFound) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if failures < u.DisableAfter {
+			return nil
+		}
+		logger.Warnf("Webhook %d is disabled after %d consecutive failures", d.WebhookID, failures)
+		return u.Deliveries.DisableWebhook(ctx, d.WebhookID)
+	})
+}
+
+func (u *WebhookUseCase) checkOwner(ctx context.Context, orgId, userId int64) error {
+	errLogic := fmt.Errorf("%w: only owners can manage webhooks of organization", ErrBusinessLogicViolation)
+
+	member, err := u.Members.GetMember(ctx, orgId, userId)
+	if errors.Is(err, repositories.ErrMemberNotFound) {
+		return errLogic
+	} else if err != nil {
+		return err
+	}
+	if !member.IsOwner {
+		return errLogic
+	}
+	return nil
+}
+
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]struct{}, len(values))
+	unique := make([]string, 0, len(values))
+	for _, v := range values {
+		if _, ok := seen[v]; !ok {
+			seen[v] = struct{}{}
+			unique = append(unique, v)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,109 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"github.com/burenotti/rtu-it-lab-recruit/model"
+	"github.com/burenotti/rtu-it-lab-recruit/usecases/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+	"time"
+)
+
+func TestWebhookUseCase_CreateWebhook_NotOwner(t *testing.T) {
+	ctx := context.Background()
+	members := mocks.NewEventMemberStorage(t)
+	u := &WebhookUseCase{Transactioner: newTestTransactioner(t), Members: members}
+	members.On("GetMember", mock.Anything, int64(2), int64(3)).
+		Return(&model.OrganizationMember{UserID: 3, Can: model.MemberRights{EditEvents: true}}, nil)
+
+	_, err := u.CreateWebhook(ctx, 3, 2, &model.WebhookCreate{
+		URL:        "https://example.com/hook",
+		EventTypes: []string{model.WebhookEventPublished},
+	})
+	assert.ErrorIs(t, err, ErrBusinessLogicViolation, "only owners should manage webhooks")
+}
+
+func TestWebhookUseCase_CreateWebhook(t *testing.T) {
+	ctx := context.Background()
+	webhooks := mocks.NewWebhookStorage(t)
+	members := mocks.NewEventMemberStorage(t)
+	u := &WebhookUseCase{Transactioner: newTestTransactioner(t), Webhooks: webhooks, Members: members}
+	members.On("GetMember", mock.Anything, int64(2), int64(3)).
+		Return(&model.OrganizationMember{UserID: 3, IsOwner: true}, nil)
+	webhooks.On("ListWebhooks", mock.Anything, int64(2)).Return(nil, nil)
+	webhooks.On("CreateWebhook", mock.Anything, mock.MatchedBy(func(w *model.Webhook) bool {
+		return w.OrganizationID == 2 && len(w.Secret) == 32 &&
+			assert.ObjectsAreEqual([]string{model.WebhookEventPublished, model.WebhookMemberJoined}, w.EventTypes)
+	})).Return(func(_ context.Context, w *model.Webhook) *model.Webhook {
+		return w
+	}, nil)
+
+	webhook, err := u.CreateWebhook(ctx, 3, 2, &model.WebhookCreate{
+		URL:        "https://example.com/hook",
+		EventTypes: []string{model.WebhookEventPublished, model.WebhookMemberJoined, model.WebhookEventPublished},
+	})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, webhook.Secret, "secret should be returned on creation")
+}
+
+func TestWebhookUseCase_DispatchWebhooks(t *testing.T) {
+	ctx := context.Background()
+	deliveries := mocks.NewWebhookDeliveryStorage(t)
+	sender := mocks.NewWebhookSender(t)
+	u := &WebhookUseCase{
+		Transactioner: newTestTransactioner(t),
+		Deliveries:    deliveries,
+		Sender:        sender,
+		Logger:        newTestLogger(),
+		BatchSize:     10,
+		Lease:         time.Minute,
+		MaxAttempts:   3,
+		BaseBackoff:   time.Second,
+		MaxBackoff:    10 * time.Second,
+		DisableAfter:  5,
+	}
+	delivered := model.WebhookDelivery{DeliveryID: 1, WebhookID: 10, Attempts: 1}
+	failed := model.WebhookDelivery{DeliveryID: 2, WebhookID: 11, Attempts: 1}
+	dead := model.WebhookDelivery{DeliveryID: 3, WebhookID: 12, Attempts: u.MaxAttempts}
+	sendErr := errors.New("endpoint responded with status 500")
+
+	deliveries.On("ClaimDueDeliveries", mock.Anything, u.BatchSize, u.Lease).
+		Return([]model.WebhookDelivery{delivered, failed, dead}, nil).Once()
+	deliveries.On("ClaimDueDeliveries", mock.Anything, u.BatchSize, u.Lease).
+		Return(nil, nil).Once()
+	for _, d := range []model.WebhookDelivery{delivered, failed, dead} {
+		deliveries.On("ExtendDeliveryLease", mock.Anything, d.DeliveryID, d.Attempts, u.Lease).Return(true, nil).Once()
+	}
+	sender.On("Send", mock.Anything, &delivered).Return(200, nil)
+	sender.On("Send", mock.Anything, &failed).Return(500, sendErr)
+	sender.On("Send", mock.Anything, &dead).Return(0, sendErr)
+	deliveries.On("MarkDelivered", mock.Anything, delivered.DeliveryID, 200).Return(nil).Once()
+	deliveries.On("ResetFailures", mock.Anything, delivered.WebhookID).Return(nil).Once()
+	deliveries.On("MarkDeliveryFailed", mock.Anything, failed.DeliveryID, 500, sendErr.Error(),
+		mock.MatchedBy(func(next time.Time) bool {
+			return next.After(time.Now())
+		})).Return(nil).Once()
+	deliveries.On("RecordFailure", mock.Anything, failed.WebhookID).Return(1, nil).Once()
+	deliveries.On("MarkDeliveryDead", mock.Anything, dead.DeliveryID, 0, sendErr.Error()).Return(nil).Once()
+	deliveries.On("RecordFailure", mock.Anything, dead.WebhookID).Return(u.DisableAfter, nil).Once()
+	deliveries.On("DisableWebhook", mock.Anything, dead.WebhookID).Return(nil).Once()
+
+	assert.NoError(t, u.DispatchWebhooks(ctx))
+}
+
+func TestWebhookUseCase_DispatchWebhooks_LeaseExpired(t *testing.T) {
+	ctx := context.Background()
+	deliveries := mocks.NewWebhookDeliveryStorage(t)
+	u := &WebhookUseCase{Deliveries: deliveries, Logger: newTestLogger(), BatchSize: 10, Lease: time.Minute}
+	reclaimed := model.WebhookDelivery{DeliveryID: 1, WebhookID: 10, Attempts: 1}
+
+	deliveries.On("ClaimDueDeliveries", mock.Anything, u.BatchSize, u.Lease).
+		Return([]model.WebhookDelivery{reclaimed}, nil).Once()
+	deliveries.On("ClaimDueDeliveries", mock.Anything, u.BatchSize, u.Lease).
+		Return(nil, nil).Once()
+	deliveries.On("ExtendDeliveryLease", mock.Anything, reclaimed.DeliveryID, reclaimed.Attempts, u.Lease).Return(false, nil)
+
+	assert.NoError(t, u.DispatchWebhooks(ctx), "delivery claimed by another dispatcher should not be sent twice")
+}